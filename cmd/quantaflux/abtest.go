@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/abtest"
+	"github.com/songzhibin97/quantaflux/internal/ai"
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/models"
+)
+
+// 未指定模型类型时记录的模型名称
+const defaultModelName = "default"
+
+// buildChallenger 创建 A/B 对比的挑战者分析器，未配置时返回 nil
+func buildChallenger(config *configs.Config) ai.Analyzer {
+	challenger := config.AIConfig.Challenger
+	if !challenger.Enabled() {
+		return nil
+	}
+	apiKey := challenger.APIKey
+	if apiKey == "" {
+		apiKey = config.AIConfig.APIKey
+	}
+	// 挑战者固定使用自己的模型，只沿用采样参数
+	return ai.NewQueue(buildAnalyzer(apiKey, challenger.ModelType, config.AIConfig.Generation().WithoutModels()),
+		config.AIConfig.Queue.MaxConcurrent)
+}
+
+// decision 按与实际下单相同的置信度和价格容差规则，将预测转换为分析器决策
+func (s *QuantSystem) decision(variant, model string, data models.MarketData, prediction *ai.PricePrediction) *abtest.Decision {
+	side := ""
+	if prediction.Confidence >= s.symbolSettings(data.Symbol).MinConfidence {
+		side = s.determineOrderSide(prediction.PredictedPrice, data.Price)
+	}
+	if model == "" {
+		model = defaultModelName
+	}
+	return &abtest.Decision{
+		Variant:        variant,
+		Model:          model,
+		Mode:           s.cfg().RunMode(),
+		Symbol:         data.Symbol,
+		Price:          data.Price,
+		PredictedPrice: prediction.PredictedPrice,
+		Confidence:     prediction.Confidence,
+		TimeFrame:      prediction.TimeFrame,
+		Side:           side,
+		TickTime:       data.Timestamp,
+		CreatedAt:      time.Now(),
+	}
+}
+
+// saveDecision 保存分析器决策，失败只记录日志
+func (s *QuantSystem) saveDecision(ctx context.Context, decision *abtest.Decision) {
+	if err := s.abtests.SaveDecision(ctx, decision); err != nil {
+		log.Error("Error saving analyzer decision", "variant", decision.Variant, "symbol", decision.Symbol, "err", err)
+	}
+}
+
+// runChallenger 记录冠军分析器的决策，并让挑战者分析器对同一行情窗口做出预测，挑战者的决策只记录不下单。
+// 挑战者在后台运行，不占用当前行情的耗时预算；回测时同步运行，保证记录与回放顺序一致
+func (s *QuantSystem) runChallenger(ctx context.Context, data models.MarketData, window []models.MarketData, champion *ai.PricePrediction) {
+	if s.challenger == nil || s.abtests == nil {
+		return
+	}
+
+	config := s.cfg()
+	s.saveDecision(ctx, s.decision(abtest.VariantChampion, championModel(config), data, champion))
+
+	run := func(ctx context.Context) {
+		aiCtx, cancel := s.stageContext(ctx, configs.StageAI)
+		defer cancel()
+
+		prediction, err := s.challenger.PredictPrice(aiCtx, window)
+		if err != nil {
+			log.Warn("challenger analyzer failed", "symbol", data.Symbol, "model", config.AIConfig.Challenger.ModelType, "err", err)
+			return
+		}
+		s.saveDecision(ctx, s.decision(abtest.VariantChallenger, config.AIConfig.Challenger.ModelType, data, prediction))
+	}
+
+	if config.RunMode() == configs.ModeBacktest {
+		run(ctx)
+		return
+	}
+	go run(context.WithoutCancel(ctx))
+}
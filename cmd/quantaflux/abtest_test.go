@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/abtest"
+	"github.com/songzhibin97/quantaflux/internal/ai"
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryDecisionStore struct {
+	decisions []abtest.Decision
+}
+
+func (m *memoryDecisionStore) SaveDecision(ctx context.Context, decision *abtest.Decision) error {
+	decision.ID = int64(len(m.decisions) + 1)
+	m.decisions = append(m.decisions, *decision)
+	return nil
+}
+
+func (m *memoryDecisionStore) ListDecisions(ctx context.Context, mode string, start, end time.Time) ([]abtest.Decision, error) {
+	return m.decisions, nil
+}
+
+type fixedPredictor struct {
+	ai.Analyzer
+	prediction ai.PricePrediction
+}
+
+func (f *fixedPredictor) PredictPrice(ctx context.Context, data []models.MarketData) (*ai.PricePrediction, error) {
+	prediction := f.prediction
+	return &prediction, nil
+}
+
+func TestQuantSystem_RunChallenger(t *testing.T) {
+	system, _ := newTestSystem(t, map[string]float64{"USDT": 1000})
+	config := *system.cfg()
+	config.Mode = configs.ModeBacktest
+	config.AIConfig.MinConfidence = 0.7
+	config.AIConfig.ModelType = "deepseek-chat"
+	config.AIConfig.Challenger.ModelType = "deepseek-reasoner"
+	system.config.Store(&config)
+
+	store := &memoryDecisionStore{}
+	system.abtests = store
+	system.challenger = &fixedPredictor{prediction: ai.PricePrediction{PredictedPrice: 90, Confidence: 0.9, TimeFrame: "1h"}}
+
+	tick := models.MarketData{Symbol: "BTCUSDT", Price: 100, Timestamp: time.Now()}
+	champion := &ai.PricePrediction{PredictedPrice: 110, Confidence: 0.5, TimeFrame: "1h"}
+	system.runChallenger(context.Background(), tick, []models.MarketData{tick}, champion)
+
+	require.Len(t, store.decisions, 2)
+	assert.Equal(t, abtest.VariantChampion, store.decisions[0].Variant)
+	assert.Equal(t, "deepseek-chat", store.decisions[0].Model)
+	// 置信度不足，不产生交易方向
+	assert.Empty(t, store.decisions[0].Side)
+
+	assert.Equal(t, abtest.VariantChallenger, store.decisions[1].Variant)
+	assert.Equal(t, "deepseek-reasoner", store.decisions[1].Model)
+	assert.Equal(t, "sell", store.decisions[1].Side)
+	assert.Equal(t, tick.Timestamp, store.decisions[1].TickTime)
+	assert.Equal(t, configs.ModeBacktest, store.decisions[1].Mode)
+}
@@ -0,0 +1,424 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/api"
+	"github.com/songzhibin97/quantaflux/internal/clock"
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/exchangeinfo"
+	"github.com/songzhibin97/quantaflux/internal/risk"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+	binanceTrading "github.com/songzhibin97/quantaflux/internal/trading/binance"
+	"github.com/songzhibin97/quantaflux/internal/trading/paper"
+)
+
+// account 交易账户：独立的执行器、余额和风险限额
+type account struct {
+	name        string
+	strategy    string
+	symbols     []string // 为空时交易全部交易对
+	executor    trading.TradeExecutor
+	hedger      trading.Hedger // 开合约空单对冲现货持仓，为空时不支持对冲
+	riskManager risk.RiskManager
+	costs       risk.CostModel // 风险评估使用的交易成本模型
+	vaR         risk.VaRModel  // 风险评估使用的 VaR 模型
+	clock       clock.Clock    // 风险统计按天重置使用的时钟，为空时使用系统时钟
+
+	symbolRiskMu  sync.Mutex
+	symbolRisk    map[string]risk.RiskManager // 单独配置了风险限额的交易对
+	positionScale float64                     // 按市场状态缩放最大仓位的比例，0 表示未缩放
+
+	bracketMu      sync.Mutex
+	brackets       *trading.OrderManager // 组合订单，执行器组合中间件后创建
+	bracketVersion int64                 // 已保存的组合订单状态的版本
+}
+
+// trades 判断账户是否交易该交易对
+func (a *account) trades(symbol string) bool {
+	return len(a.symbols) == 0 || slices.Contains(a.symbols, symbol)
+}
+
+// symbolRiskManager 返回账户在该交易对上的风险管理器，并应用当前的交易对风险限额
+func (a *account) symbolRiskManager(ctx context.Context, symbol string, params *risk.RiskParameters) (risk.RiskManager, error) {
+	a.symbolRiskMu.Lock()
+	defer a.symbolRiskMu.Unlock()
+
+	rm, ok := a.symbolRisk[symbol]
+	if !ok {
+		if a.symbolRisk == nil {
+			a.symbolRisk = make(map[string]risk.RiskManager)
+		}
+		basic := risk.NewBasicRiskManager(*params)
+		basic.SetCostModel(a.costs)
+		basic.SetVaR(a.vaR)
+		basic.SetClock(a.clock)
+		if a.positionScale > 0 {
+			basic.SetPositionScale(a.positionScale)
+		}
+		rm = basic
+		a.symbolRisk[symbol] = rm
+	}
+	// 热加载后限额可能变化
+	if err := rm.SetRiskParameters(ctx, params); err != nil {
+		return nil, fmt.Errorf("failed to set risk parameters of %s: %w", symbol, err)
+	}
+	return rm, nil
+}
+
+// setPositionScale 将缩放比例应用到账户和各交易对的风险管理器，之后创建的交易对风险管理器也使用该比例
+func (a *account) setPositionScale(scale float64) {
+	if scaler, ok := a.riskManager.(risk.PositionScaler); ok {
+		scaler.SetPositionScale(scale)
+	}
+
+	a.symbolRiskMu.Lock()
+	defer a.symbolRiskMu.Unlock()
+	a.positionScale = scale
+	for _, rm := range a.symbolRisk {
+		if scaler, ok := rm.(risk.PositionScaler); ok {
+			scaler.SetPositionScale(scale)
+		}
+	}
+}
+
+// accountAlert 带账户信息的风险预警
+type accountAlert struct {
+	account *account
+	alert   risk.RiskAlert
+}
+
+// buildAccounts 根据运行模式为每个账户创建执行器和风险管理器
+func buildAccounts(config *configs.Config, info *exchangeinfo.Service, clk clock.Clock) ([]*account, error) {
+	var accounts []*account
+	for _, ac := range config.AccountConfigs() {
+		var executor trading.TradeExecutor
+		var hedger trading.Hedger
+		switch config.RunMode() {
+		case configs.ModeLive:
+			executor = newBinanceExecutor(config, ac.ExchangeConfig, info)
+			// 只在配置了对冲时使用合约接口，未开通合约权限的账户不受影响
+			if config.Hedges() {
+				hedger = binanceTrading.NewFuturesHedger(ac.ExchangeConfig.APIKey, ac.ExchangeConfig.SecretKey, ac.ExchangeConfig.Debug)
+			}
+		case configs.ModePaper, configs.ModeBacktest:
+			executor = paper.NewPaperExecutor(paperBalances(config, ac))
+		case configs.ModeShadow:
+			// 影子模式按真实账户余额模拟成交，密钥只用于读取余额
+			balances := paperBalances(config, ac)
+			if ac.ExchangeConfig.HasCredentials() {
+				exchange := newBinanceExecutor(config, ac.ExchangeConfig, info)
+				symbols := ac.Symbols
+				if len(symbols) == 0 {
+					symbols = config.Symbols
+				}
+				seeded, err := exchangeBalances(context.Background(), exchange, symbols)
+				if err != nil {
+					log.Warn("failed to load exchange balances for shadow account, using paper balances", "account", ac.Name, "err", err)
+				} else {
+					balances = seeded
+				}
+			}
+			executor = paper.NewPaperExecutor(balances)
+		default:
+			return nil, fmt.Errorf("unknown mode: %s", config.Mode)
+		}
+		if simulated, ok := executor.(*paper.PaperExecutor); ok {
+			// 回测按回放时间运行，不模拟下单延迟；手续费按 cost_config 中的费率扣除
+			sim := config.PaperConfig.Simulation()
+			if config.RunMode() == configs.ModeBacktest {
+				sim.MinLatency, sim.MaxLatency = 0, 0
+			}
+			fees := config.CostConfig.CostModel(configs.ExchangeBinance)
+			sim.MakerFeeRate, sim.TakerFeeRate = fees.MakerFeeRate, fees.TakerFeeRate
+			simulated.SetSimulation(sim)
+			simulated.SetClock(clk)
+			hedger = simulated
+		}
+
+		params := config.RiskParams
+		if ac.RiskParams != nil {
+			params = *ac.RiskParams
+		}
+
+		costs := config.CostConfig.CostModel(configs.ExchangeBinance)
+		riskManager := risk.NewBasicRiskManager(params)
+		riskManager.SetCostModel(costs)
+		riskManager.SetClock(clk)
+
+		accounts = append(accounts, &account{
+			name:        ac.Name,
+			strategy:    ac.Strategy,
+			symbols:     ac.Symbols,
+			executor:    executor,
+			hedger:      hedger,
+			riskManager: riskManager,
+			costs:       costs,
+			clock:       clk,
+		})
+	}
+	return accounts, nil
+}
+
+// newBinanceExecutor 创建 Binance 执行器，应用时间同步配置和共享的交易所元数据
+func newBinanceExecutor(config *configs.Config, ec configs.ExchangeConfig, info *exchangeinfo.Service) *binanceTrading.BinanceExecutor {
+	executor := binanceTrading.NewBinanceExecutor(ec.APIKey, ec.SecretKey, ec.Debug)
+	executor.SetExchangeInfo(info)
+	if window, err := time.ParseDuration(config.TimeSyncConfig.RecvWindow); err == nil {
+		executor.SetRecvWindow(window)
+	}
+	executor.SetBalanceCheck(ec.BalanceCheck())
+	return executor
+}
+
+// paperBalances 返回账户的模拟初始余额，未单独配置时使用 paper_config
+func paperBalances(config *configs.Config, ac configs.AccountConfig) map[string]float64 {
+	if len(ac.InitialBalances) > 0 {
+		return ac.InitialBalances
+	}
+	return config.PaperConfig.InitialBalances
+}
+
+// exchangeBalances 读取交易对涉及的各资产在交易所的余额
+func exchangeBalances(ctx context.Context, executor trading.TradeExecutor, symbols []string) (map[string]float64, error) {
+	balances := make(map[string]float64)
+	for _, symbol := range symbols {
+		base, quote, ok := trading.SplitSymbol(symbol)
+		if !ok {
+			continue
+		}
+		for _, asset := range []string{base, quote} {
+			if _, ok := balances[asset]; ok {
+				continue
+			}
+			amount, err := executor.GetBalance(ctx, asset)
+			if err != nil && !errors.Is(err, trading.ErrBalanceNotFound) {
+				return nil, fmt.Errorf("failed to get %s balance: %w", asset, err)
+			}
+			balances[asset] = amount
+		}
+	}
+	return balances, nil
+}
+
+// primaryAccount 返回第一个账户，用于单账户场景下的默认操作
+func (s *QuantSystem) primaryAccount() *account {
+	return s.accounts[0]
+}
+
+// account 按名称查找账户，名称为空时返回主账户（兼容未标记账户的历史订单）
+func (s *QuantSystem) account(name string) (*account, error) {
+	if name == "" {
+		return s.primaryAccount(), nil
+	}
+	for _, a := range s.accounts {
+		if a.name == name {
+			return a, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", api.ErrAccountNotFound, name)
+}
+
+// accountRiskParams 返回配置中账户单独设置的风险参数，未设置时返回 nil
+func accountRiskParams(config *configs.Config, name string) *risk.RiskParameters {
+	for _, ac := range config.Accounts {
+		if ac.Name == name {
+			return ac.RiskParams
+		}
+	}
+	return nil
+}
+
+// riskParamsFor 返回账户生效的风险参数
+func (s *QuantSystem) riskParamsFor(a *account) *risk.RiskParameters {
+	config := s.cfg()
+	if params := accountRiskParams(config, a.name); params != nil {
+		return params
+	}
+	return &config.RiskParams
+}
+
+// RiskState implements api.System
+func (s *QuantSystem) RiskState(ctx context.Context, name string) (*risk.RiskState, error) {
+	a, err := s.account(name)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := a.riskManager.GetRiskState(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get risk state of account %s: %w", a.name, err)
+	}
+	state.StaleSymbols = s.staleSymbols()
+	return state, nil
+}
+
+// SetRiskParameters implements api.System，name 为空时所有账户改用同一组限额。
+// 修改同时写入当前生效的配置，之后热加载时配置文件中对应的风险参数未变化则保留修改后的值
+func (s *QuantSystem) SetRiskParameters(ctx context.Context, name string, params *risk.RiskParameters) error {
+	targets := s.accounts
+	if name != "" {
+		a, err := s.account(name)
+		if err != nil {
+			return err
+		}
+		targets = []*account{a}
+	}
+
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+
+	// 各账户的校验规则相同，参数不合法时第一个账户即返回错误，不会只修改部分账户
+	for _, a := range targets {
+		if err := a.riskManager.SetRiskParameters(ctx, params); err != nil {
+			return fmt.Errorf("failed to set risk parameters of account %s: %w", a.name, err)
+		}
+	}
+
+	next := *s.cfg()
+	next.Accounts = slices.Clone(next.Accounts)
+	switch {
+	case name == "" || len(next.Accounts) == 0:
+		// 未配置 accounts 时唯一的账户使用全局风险参数
+		next.RiskParams = *params
+		for i := range next.Accounts {
+			next.Accounts[i].RiskParams = nil
+		}
+	default:
+		for i := range next.Accounts {
+			if next.Accounts[i].Name == name {
+				accountParams := *params
+				next.Accounts[i].RiskParams = &accountParams
+			}
+		}
+	}
+	s.config.Store(&next)
+	return nil
+}
+
+// checkTradeRisk 依次按账户限额和交易对限额评估订单，任一不通过即不可接受；
+// 限额按估值资产计价，订单金额先按汇率换算
+func (s *QuantSystem) checkTradeRisk(ctx context.Context, a *account, order *trading.Order) (*risk.RiskAssessment, error) {
+	order, err := s.valueOrder(order)
+	if err != nil {
+		return nil, err
+	}
+
+	assessment, err := a.riskManager.CheckTradeRisk(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+
+	params := s.symbolSettings(order.Symbol).RiskParams
+	if params == nil {
+		return assessment, nil
+	}
+
+	rm, err := a.symbolRiskManager(ctx, order.Symbol, params)
+	if err != nil {
+		return nil, err
+	}
+	symbolAssessment, err := rm.CheckTradeRisk(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+
+	assessment.IsAcceptable = assessment.IsAcceptable && symbolAssessment.IsAcceptable
+	assessment.RiskLevel = max(assessment.RiskLevel, symbolAssessment.RiskLevel)
+	// 交易对限额的结果带交易对前缀，按加上前缀后的内容去重
+	for _, factor := range symbolAssessment.RiskFactors {
+		if factor = order.Symbol + ": " + factor; !slices.Contains(assessment.RiskFactors, factor) {
+			assessment.RiskFactors = append(assessment.RiskFactors, factor)
+		}
+	}
+	for _, recommendation := range symbolAssessment.Recommendations {
+		if recommendation = order.Symbol + ": " + recommendation; !slices.Contains(assessment.Recommendations, recommendation) {
+			assessment.Recommendations = append(assessment.Recommendations, recommendation)
+		}
+	}
+	return assessment, nil
+}
+
+// strategyFor 返回账户在交易对上运行的策略名称，symbol 为空时返回账户策略
+func (s *QuantSystem) strategyFor(a *account, symbol string) string {
+	if symbol != "" {
+		if strategy := s.symbolSettings(symbol).Strategy; strategy != "" {
+			return strategy
+		}
+	}
+	if a.strategy != "" {
+		return a.strategy
+	}
+	return s.strategyName()
+}
+
+// monitorAccounts 合并所有账户的风险预警
+func (s *QuantSystem) monitorAccounts(ctx context.Context) (<-chan accountAlert, error) {
+	out := make(chan accountAlert)
+	for _, a := range s.accounts {
+		if rm, ok := a.riskManager.(*risk.BasicRiskManager); ok {
+			options := s.cfg().PositionMonitorConfig.Options(a.name)
+			options.Positions = s
+			if s.volatility != nil {
+				options.Volatility, options.StopATR = s.volatility, s.cfg().VolatilityConfig.StopATR
+			}
+			rm.SetMonitor(options)
+		}
+		alerts, err := a.riskManager.MonitorPositions(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to monitor account %s: %w", a.name, err)
+		}
+
+		go func(a *account) {
+			for alert := range alerts {
+				select {
+				case out <- accountAlert{account: a, alert: alert}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(a)
+	}
+	s.monitorLiquidity(ctx, out)
+	s.monitorWhales(ctx, out)
+	s.monitorTradingStatus(ctx, out)
+	s.monitorFreshness(ctx, out)
+	s.monitorWallets(ctx, out)
+	s.monitorTakeProfit(ctx, out)
+	return out, nil
+}
+
+// Accounts implements api.System
+func (s *QuantSystem) Accounts(ctx context.Context) ([]api.AccountSummary, error) {
+	result := make([]api.AccountSummary, 0, len(s.accounts))
+	for _, a := range s.accounts {
+		positions, err := s.accountPositions(ctx, a)
+		if err != nil {
+			return nil, err
+		}
+
+		riskState, err := a.riskManager.GetRiskState(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get risk state of account %s: %w", a.name, err)
+		}
+
+		symbols := a.symbols
+		if len(symbols) == 0 {
+			symbols = s.cfg().Symbols
+		}
+
+		result = append(result, api.AccountSummary{
+			Name:      a.name,
+			Strategy:  s.strategyFor(a, ""),
+			Symbols:   symbols,
+			Positions: positions,
+			Risk:      riskState,
+		})
+	}
+	return result, nil
+}
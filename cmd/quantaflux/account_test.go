@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/songzhibin97/quantaflux/internal/api"
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/risk"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuantSystem_CheckTradeRisk_SymbolFactors(t *testing.T) {
+	system, _ := newTestSystem(t, map[string]float64{"USDT": 1000})
+	config := *system.cfg()
+	config.SymbolOverrides = map[string]configs.SymbolConfig{
+		"BTCUSDT": {RiskParams: &risk.RiskParameters{
+			MaxPositionSize: 10,
+			MaxLossPerTrade: 100,
+			MaxDailyLoss:    500,
+			MaxLeverage:     1,
+			MinLiquidity:    1000,
+		}},
+	}
+	system.config.Store(&config)
+
+	order := &trading.Order{Symbol: "BTCUSDT", Side: "buy", Amount: 1, Price: 100, OrderType: "market"}
+	assessment, err := system.checkTradeRisk(context.Background(), system.primaryAccount(), order)
+	require.NoError(t, err)
+
+	// 账户限额和交易对限额给出相同的风险因素时都保留，交易对的带前缀且不重复
+	const slippage = "Market order may result in slippage"
+	assert.Contains(t, assessment.RiskFactors, slippage)
+	assert.Contains(t, assessment.RiskFactors, "BTCUSDT: "+slippage)
+	assert.Contains(t, assessment.Recommendations, "BTCUSDT: Consider using limit order for better price control")
+
+	seen := make(map[string]bool)
+	for _, factor := range assessment.RiskFactors {
+		assert.False(t, seen[factor], "duplicate risk factor %q", factor)
+		seen[factor] = true
+	}
+}
+
+func TestQuantSystem_HandleRiskAlert(t *testing.T) {
+	ctx := context.Background()
+	system, store := newTestSystem(t, map[string]float64{"USDT": 1000, "BTC": 2})
+	a := system.primaryAccount()
+	a.executor.(trading.MarketPriceUpdater).UpdateMarketPrice("BTCUSDT", 100)
+
+	// 风险管理器给出的严重程度为大写
+	alert := risk.RiskAlert{Symbol: "BTCUSDT", AlertType: "unrealized_loss", Severity: risk.SeverityHigh}
+	require.NoError(t, system.handleRiskAlert(ctx, a, alert))
+
+	assert.True(t, system.symbolPaused("BTCUSDT"))
+	btc, err := a.executor.GetBalance(ctx, "BTC")
+	require.NoError(t, err)
+	assert.Zero(t, btc)
+	require.Len(t, store.entries, 1)
+	assert.Equal(t, "risk_emergency_close", store.entries[0].Strategy)
+}
+
+func TestQuantSystem_HandleRiskAlert_Hedge(t *testing.T) {
+	ctx := context.Background()
+	system, store := newTestSystem(t, map[string]float64{"USDT": 1000, "BTC": 2})
+	a := system.primaryAccount()
+	a.hedger = a.executor.(trading.Hedger)
+	a.executor.(trading.MarketPriceUpdater).UpdateMarketPrice("BTCUSDT", 100)
+
+	ratio := 0.5
+	config := *system.cfg()
+	config.SymbolOverrides = map[string]configs.SymbolConfig{"BTCUSDT": {HedgeRatio: &ratio}}
+	system.config.Store(&config)
+
+	// 配置了对冲比例时保留现货，按比例开合约空单，重复预警不重复开仓
+	alert := risk.RiskAlert{Symbol: "BTCUSDT", AlertType: "unrealized_loss", Severity: risk.SeverityHigh}
+	require.NoError(t, system.handleRiskAlert(ctx, a, alert))
+	require.NoError(t, system.handleRiskAlert(ctx, a, alert))
+
+	assert.True(t, system.symbolPaused("BTCUSDT"))
+	btc, err := a.executor.GetBalance(ctx, "BTC")
+	require.NoError(t, err)
+	assert.InDelta(t, 2, btc, 1e-9)
+	hedged, err := a.hedger.HedgeAmount(ctx, "BTCUSDT")
+	require.NoError(t, err)
+	assert.InDelta(t, 1, hedged, 1e-9)
+	assert.Empty(t, store.entries)
+
+	// 清仓时一并平掉对冲，合约盈亏计入计价资产
+	a.executor.(trading.MarketPriceUpdater).UpdateMarketPrice("BTCUSDT", 90)
+	require.NoError(t, system.emergencyClose(ctx, a, "BTCUSDT"))
+	hedged, err = a.hedger.HedgeAmount(ctx, "BTCUSDT")
+	require.NoError(t, err)
+	assert.Zero(t, hedged)
+	usdt, err := a.executor.GetBalance(ctx, "USDT")
+	require.NoError(t, err)
+	assert.InDelta(t, 1000+2*90+10, usdt, 1e-9)
+}
+
+type failingHedger struct {
+	trading.Hedger
+	err error
+}
+
+func (f *failingHedger) OpenHedge(context.Context, string, float64) (*trading.Order, error) {
+	return nil, f.err
+}
+
+func TestQuantSystem_HandleRiskAlert_HedgeFallback(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name   string
+		alert  string
+		hedger func(a *account) trading.Hedger
+	}{
+		{"delisting", alertSymbolDelisting, func(a *account) trading.Hedger { return a.executor.(trading.Hedger) }},
+		{"liquidity withdrawn", risk.AlertLiquidityWithdrawn, func(a *account) trading.Hedger { return a.executor.(trading.Hedger) }},
+		{"lp unlocked", risk.AlertLPUnlocked, func(a *account) trading.Hedger { return a.executor.(trading.Hedger) }},
+		{"hedge failed", "unrealized_loss", func(a *account) trading.Hedger {
+			return &failingHedger{Hedger: a.executor.(trading.Hedger), err: errors.New("futures unavailable")}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			system, store := newTestSystem(t, map[string]float64{"USDT": 1000, "BTC": 2})
+			a := system.primaryAccount()
+			a.hedger = tt.hedger(a)
+			a.executor.(trading.MarketPriceUpdater).UpdateMarketPrice("BTCUSDT", 100)
+
+			ratio := 0.5
+			config := *system.cfg()
+			config.SymbolOverrides = map[string]configs.SymbolConfig{"BTCUSDT": {HedgeRatio: &ratio}}
+			system.config.Store(&config)
+
+			// 配置了对冲比例也清仓现货，不开合约空单
+			alert := risk.RiskAlert{Symbol: "BTCUSDT", AlertType: tt.alert, Severity: risk.SeverityHigh}
+			require.NoError(t, system.handleRiskAlert(ctx, a, alert))
+
+			btc, err := a.executor.GetBalance(ctx, "BTC")
+			require.NoError(t, err)
+			assert.Zero(t, btc)
+			hedged, err := a.executor.(trading.Hedger).HedgeAmount(ctx, "BTCUSDT")
+			require.NoError(t, err)
+			assert.Zero(t, hedged)
+			require.Len(t, store.entries, 1)
+			assert.Equal(t, "risk_emergency_close", store.entries[0].Strategy)
+		})
+	}
+}
+
+func TestQuantSystem_SetRiskParameters(t *testing.T) {
+	ctx := context.Background()
+	system, _ := newTestSystem(t, map[string]float64{"USDT": 1000})
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig := func(refreshInterval string, maxPositionSize float64) {
+		content := fmt.Sprintf(`mode: paper
+symbols: [BTCUSDT]
+refresh_interval: %s
+database:
+  conn_str: postgresql://localhost/quantaflux
+ai_config:
+  api_key: key
+  min_confidence: 0.7
+  scam_threshold: 0.8
+risk_params:
+  max_position_size: %g
+  max_loss_per_trade: 100
+  max_daily_loss: 500
+  max_leverage: 1
+  min_liquidity: 1000
+`, refreshInterval, maxPositionSize)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+	positionLimit := func() float64 {
+		state, err := system.RiskState(ctx, "")
+		require.NoError(t, err)
+		assert.Equal(t, state.Parameters.MaxPositionSize, system.cfg().RiskParams.MaxPositionSize)
+		return state.Parameters.MaxPositionSize
+	}
+
+	params := system.cfg().RiskParams
+	params.MaxPositionSize = 20
+	require.NoError(t, system.SetRiskParameters(ctx, "", &params))
+	assert.Equal(t, 20.0, positionLimit())
+
+	err := system.SetRiskParameters(ctx, "unknown", &params)
+	assert.ErrorIs(t, err, api.ErrAccountNotFound)
+	_, err = system.RiskState(ctx, "unknown")
+	assert.ErrorIs(t, err, api.ErrAccountNotFound)
+
+	invalid := params
+	invalid.MaxPositionSize = -1
+	assert.ErrorIs(t, system.SetRiskParameters(ctx, "main", &invalid), risk.ErrInvalidParameters)
+	assert.Equal(t, 20.0, positionLimit())
+
+	// 配置文件中的风险参数未变化时，热加载保留通过 API 修改的值
+	writeConfig("2m", 10)
+	require.NoError(t, system.reloadConfig(ctx, path))
+	assert.Equal(t, "2m", system.cfg().RefreshInterval)
+	assert.Equal(t, 20.0, positionLimit())
+
+	// 配置文件中的风险参数变化后以文件为准
+	writeConfig("2m", 30)
+	require.NoError(t, system.reloadConfig(ctx, path))
+	assert.Equal(t, 30.0, positionLimit())
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/models"
+)
+
+// errTickSuperseded 同一交易对更新的行情已到达，旧行情的 AI 分析被取消
+var errTickSuperseded = errors.New("tick superseded by newer market data")
+
+// aiTick 正在进行 AI 分析的行情
+type aiTick struct {
+	timestamp time.Time
+	cancel    context.CancelCauseFunc
+}
+
+// aiTicks 按交易对和周期记录正在进行 AI 分析的行情
+type aiTicks struct {
+	mu    sync.Mutex
+	ticks map[string]aiTick
+}
+
+func newAITicks() *aiTicks {
+	return &aiTicks{ticks: make(map[string]aiTick)}
+}
+
+// aiTickKey 同一交易对同一周期的行情互相替代
+func aiTickKey(data models.MarketData) string {
+	return data.Symbol + "/" + data.Timeframe
+}
+
+// aiStageContext 返回受耗时预算约束的 AI 阶段 context 并登记该行情，同一交易对更新的行情到达时可被取消
+func (s *QuantSystem) aiStageContext(ctx context.Context, data models.MarketData) (context.Context, context.CancelFunc) {
+	stageCtx, cancelStage := s.stageContext(ctx, configs.StageAI)
+	aiCtx, cancel := context.WithCancelCause(stageCtx)
+
+	key := aiTickKey(data)
+	s.analyzing.mu.Lock()
+	s.analyzing.ticks[key] = aiTick{timestamp: data.Timestamp, cancel: cancel}
+	s.analyzing.mu.Unlock()
+
+	return aiCtx, func() {
+		s.analyzing.mu.Lock()
+		if tick, ok := s.analyzing.ticks[key]; ok && tick.timestamp.Equal(data.Timestamp) {
+			delete(s.analyzing.ticks, key)
+		}
+		s.analyzing.mu.Unlock()
+		cancel(nil)
+		cancelStage()
+	}
+}
+
+// supersedeTick 新行情到达时取消同一交易对更早行情排队中和执行中的 AI 调用；
+// 未开启 cancel_stale、回测或按 K 线收盘触发时不取消
+func (s *QuantSystem) supersedeTick(data models.MarketData) {
+	config := s.cfg()
+	if !config.AIConfig.Queue.CancelStale || config.RunMode() == configs.ModeBacktest || config.TradingConfig.CandleInterval != "" {
+		return
+	}
+
+	key := aiTickKey(data)
+	s.analyzing.mu.Lock()
+	defer s.analyzing.mu.Unlock()
+
+	tick, ok := s.analyzing.ticks[key]
+	if !ok || !tick.timestamp.Before(data.Timestamp) {
+		return
+	}
+	tick.cancel(errTickSuperseded)
+	delete(s.analyzing.ticks, key)
+}
+
+// tickSuperseded 判断 AI 阶段是否因更新的行情到达被取消，取消时按过期行情计数
+func (s *QuantSystem) tickSuperseded(aiCtx context.Context, symbol string) bool {
+	if !errors.Is(context.Cause(aiCtx), errTickSuperseded) {
+		return false
+	}
+	s.staleTicks.Inc(symbol)
+	return true
+}
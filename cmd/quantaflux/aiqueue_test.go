@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuantSystem_SupersedeTick(t *testing.T) {
+	system, _ := newTestSystem(t, map[string]float64{"USDT": 1000})
+	start := time.Now()
+	tick := models.MarketData{Symbol: "BTCUSDT", Timestamp: start}
+	newer := models.MarketData{Symbol: "BTCUSDT", Timestamp: start.Add(time.Second)}
+
+	// 未开启 cancel_stale 时不取消
+	aiCtx, cancel := system.aiStageContext(context.Background(), tick)
+	system.supersedeTick(newer)
+	assert.NoError(t, aiCtx.Err())
+	cancel()
+	assert.Empty(t, system.analyzing.ticks)
+
+	config := *system.cfg()
+	config.AIConfig.Queue.CancelStale = true
+	system.config.Store(&config)
+
+	aiCtx, cancel = system.aiStageContext(context.Background(), tick)
+	defer cancel()
+
+	// 其他交易对、其他周期和更早的行情不影响
+	system.supersedeTick(models.MarketData{Symbol: "ETHUSDT", Timestamp: newer.Timestamp})
+	system.supersedeTick(models.MarketData{Symbol: "BTCUSDT", Timeframe: "1h", Timestamp: newer.Timestamp})
+	system.supersedeTick(models.MarketData{Symbol: "BTCUSDT", Timestamp: start.Add(-time.Second)})
+	assert.NoError(t, aiCtx.Err())
+	assert.False(t, system.tickSuperseded(aiCtx, tick.Symbol))
+
+	system.supersedeTick(newer)
+	assert.ErrorIs(t, aiCtx.Err(), context.Canceled)
+	assert.True(t, system.tickSuperseded(aiCtx, tick.Symbol))
+	assert.NoError(t, system.skipStaleTick(aiCtx, tick))
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/notify"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 内置告警名称，同时作为 quantaflux_alert_firing 指标的标签值
+const (
+	alertStaleData    = "stale_market_data"
+	alertAIFailures   = "ai_failures"
+	alertOrderRejects = "order_rejects"
+)
+
+// alertState 内置告警依赖的运行统计
+type alertState struct {
+	mu         sync.Mutex
+	started    time.Time
+	updates    map[string]time.Time // 各交易对最近一次收到行情的时间
+	aiFailures int                  // AI 调用连续失败次数
+	orders     []bool               // 最近的下单结果，true 表示被交易所拒绝
+	firing     map[string]string    // 正在告警的名称 -> 描述
+	stale      map[string]bool      // 行情停滞、阻止新订单的交易对
+}
+
+func newAlertState() *alertState {
+	return &alertState{
+		started: time.Now(),
+		updates: make(map[string]time.Time),
+		firing:  make(map[string]string),
+		stale:   make(map[string]bool),
+	}
+}
+
+// recordMarketUpdate 记录交易对收到行情的时间，行情停滞的交易对恢复下单
+func (s *QuantSystem) recordMarketUpdate(symbol string) {
+	s.alertState.mu.Lock()
+	s.alertState.updates[symbol] = time.Now()
+	stale := s.alertState.stale[symbol]
+	delete(s.alertState.stale, symbol)
+	s.alertState.mu.Unlock()
+
+	if stale {
+		log.Info("market data resumed, orders unblocked", "symbol", symbol)
+	}
+}
+
+// recordAIResult 记录一次 AI 调用的结果，成功时清零连续失败次数；停止运行导致的取消不计入
+func (s *QuantSystem) recordAIResult(err error) {
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+
+	s.alertState.mu.Lock()
+	if err != nil {
+		s.alertState.aiFailures++
+	} else {
+		s.alertState.aiFailures = 0
+	}
+	failures := s.alertState.aiFailures
+	s.alertState.mu.Unlock()
+
+	s.aiFailures.Set(float64(failures))
+}
+
+// recordOrderResult 记录账户的一次下单结果，更新所有账户最近 reject_window 笔下单的拒单比例
+func (s *QuantSystem) recordOrderResult(account string, err error) {
+	result := "accepted"
+	switch {
+	case errors.Is(err, trading.ErrOrderRejected):
+		result = "rejected"
+	case err != nil:
+		result = "failed"
+	}
+	s.orderResults.Inc(account, result)
+
+	window := s.cfg().AlertConfig.OrderWindow()
+	s.alertState.mu.Lock()
+	s.alertState.orders = append(s.alertState.orders, result == "rejected")
+	if len(s.alertState.orders) > window {
+		s.alertState.orders = s.alertState.orders[len(s.alertState.orders)-window:]
+	}
+	ratio := rejectRatio(s.alertState.orders)
+	s.alertState.mu.Unlock()
+
+	s.rejectRatio.Set(ratio)
+}
+
+// rejectRatio 返回下单结果中被拒绝的比例
+func rejectRatio(orders []bool) float64 {
+	if len(orders) == 0 {
+		return 0
+	}
+	rejected := 0
+	for _, r := range orders {
+		if r {
+			rejected++
+		}
+	}
+	return float64(rejected) / float64(len(orders))
+}
+
+// runAlerts 按 alert_config.interval 定期检查内置告警；回测按回放时间运行，不检查
+func (s *QuantSystem) runAlerts(ctx context.Context) {
+	if s.cfg().RunMode() == configs.ModeBacktest {
+		return
+	}
+
+	ticker := time.NewTicker(s.cfg().AlertConfig.CheckInterval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.checkAlerts(ctx, now)
+		}
+	}
+}
+
+// checkAlerts 更新行情延迟指标并评估告警阈值，告警开始和恢复时各通知一次，返回正在告警的名称
+func (s *QuantSystem) checkAlerts(ctx context.Context, now time.Time) []string {
+	config := s.cfg()
+	alertConfig := config.AlertConfig
+
+	s.alertState.mu.Lock()
+	firing := make(map[string]string)
+
+	var stale []string
+	maxAge := alertConfig.DataAge()
+	for _, symbol := range config.Symbols {
+		last, ok := s.alertState.updates[symbol]
+		if !ok {
+			last = s.alertState.started
+		}
+		age := now.Sub(last)
+		s.dataAge.Set(age.Seconds(), symbol)
+		if maxAge > 0 && age > maxAge {
+			stale = append(stale, fmt.Sprintf("%s (%s)", symbol, age.Round(time.Second)))
+		}
+	}
+	if len(stale) > 0 {
+		firing[alertStaleData] = fmt.Sprintf("No market data within %s for %s.", maxAge, strings.Join(stale, ", "))
+	}
+
+	if alertConfig.MaxAIFailures > 0 && s.alertState.aiFailures >= alertConfig.MaxAIFailures {
+		firing[alertAIFailures] = fmt.Sprintf("%d consecutive AI analyzer calls failed (threshold %d).", s.alertState.aiFailures, alertConfig.MaxAIFailures)
+	}
+
+	window := alertConfig.OrderWindow()
+	if ratio := rejectRatio(s.alertState.orders); alertConfig.MaxRejectRatio > 0 && len(s.alertState.orders) >= window && ratio > alertConfig.MaxRejectRatio {
+		firing[alertOrderRejects] = fmt.Sprintf("%.0f%% of the last %d orders were rejected by the exchange (threshold %.0f%%).", ratio*100, window, alertConfig.MaxRejectRatio*100)
+	}
+
+	previous := s.alertState.firing
+	s.alertState.firing = firing
+	s.alertState.mu.Unlock()
+
+	for _, name := range []string{alertStaleData, alertAIFailures, alertOrderRejects} {
+		description, active := firing[name]
+		_, wasActive := previous[name]
+		switch {
+		case active && !wasActive:
+			s.alertFiring.Set(1, name)
+			log.Warn("alert firing", "alert", name, "description", description)
+			s.notify(ctx, notify.Message{Title: "Alert firing: " + name, Text: description, Level: notify.LevelWarning})
+		case !active && wasActive:
+			s.alertFiring.Set(0, name)
+			log.Info("alert resolved", "alert", name)
+			s.notify(ctx, notify.Message{Title: "Alert resolved: " + name, Text: name + " is back within its threshold.", Level: notify.LevelInfo})
+		}
+	}
+
+	names := make([]string, 0, len(firing))
+	for name := range firing {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// prometheusRule Prometheus 告警规则
+type prometheusRule struct {
+	Alert       string            `yaml:"alert"`
+	Expr        string            `yaml:"expr"`
+	For         string            `yaml:"for,omitempty"`
+	Labels      map[string]string `yaml:"labels"`
+	Annotations map[string]string `yaml:"annotations"`
+}
+
+type prometheusRuleGroup struct {
+	Name  string           `yaml:"name"`
+	Rules []prometheusRule `yaml:"rules"`
+}
+
+// alertRules 按 alert_config 和 drawdown_config 的阈值生成与内置告警等价的 Prometheus 告警规则，未配置的阈值不生成
+func alertRules(config *configs.Config) []prometheusRule {
+	alertConfig := config.AlertConfig
+	interval := alertConfig.CheckInterval().String()
+	rule := func(name, expr, severity, summary string) prometheusRule {
+		return prometheusRule{
+			Alert:       name,
+			Expr:        expr,
+			For:         interval,
+			Labels:      map[string]string{"severity": severity},
+			Annotations: map[string]string{"summary": summary},
+		}
+	}
+
+	var rules []prometheusRule
+	if maxAge := alertConfig.DataAge(); maxAge > 0 {
+		rules = append(rules, rule("QuantafluxStaleMarketData",
+			fmt.Sprintf("quantaflux_market_data_age_seconds > %g", maxAge.Seconds()), "warning",
+			fmt.Sprintf("No market data for {{ $labels.symbol }} within %s", maxAge)))
+	}
+	if alertConfig.MaxAIFailures > 0 {
+		rules = append(rules, rule("QuantafluxAIFailures",
+			fmt.Sprintf("quantaflux_ai_consecutive_failures >= %d", alertConfig.MaxAIFailures), "warning",
+			"AI analyzer calls keep failing"))
+	}
+	if alertConfig.MaxRejectRatio > 0 {
+		rules = append(rules, rule("QuantafluxOrderRejects",
+			fmt.Sprintf("quantaflux_order_reject_ratio > %g", alertConfig.MaxRejectRatio), "warning",
+			fmt.Sprintf("More than %g of the last %d orders were rejected", alertConfig.MaxRejectRatio, alertConfig.OrderWindow())))
+	}
+	if config.DrawdownConfig.MaxDrawdown > 0 {
+		r := rule("QuantafluxDrawdown",
+			fmt.Sprintf("quantaflux_equity_drawdown_ratio > %g", config.DrawdownConfig.MaxDrawdown), "critical",
+			"Equity drawdown exceeded drawdown_config.max_drawdown")
+		r.For = ""
+		rules = append(rules, r)
+	}
+	return rules
+}
+
+// cmdAlertRules 输出 Prometheus 告警规则文件，供已有 Prometheus/Alertmanager 的部署使用
+func cmdAlertRules(args []string) error {
+	fs, conf := newFlagSet("alert-rules")
+	_ = fs.Parse(args)
+
+	config, err := configs.Load(*conf)
+	if err != nil {
+		return err
+	}
+
+	encoder := yaml.NewEncoder(os.Stdout)
+	encoder.SetIndent(2)
+	defer encoder.Close()
+	return encoder.Encode(map[string][]prometheusRuleGroup{
+		"groups": {{Name: "quantaflux", Rules: alertRules(config)}},
+	})
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuantSystem_CheckAlerts(t *testing.T) {
+	ctx := context.Background()
+	system, _ := newTestSystem(t, map[string]float64{"USDT": 1000})
+	config := *system.cfg()
+	config.AlertConfig = configs.AlertConfig{MaxDataAge: "5m", MaxAIFailures: 3, MaxRejectRatio: 0.5, RejectWindow: 4}
+	system.config.Store(&config)
+
+	now := time.Now()
+	system.recordMarketUpdate("BTCUSDT")
+	assert.Empty(t, system.checkAlerts(ctx, now))
+
+	// AI 连续失败达到阈值，成功一次后清零
+	for i := 0; i < 3; i++ {
+		system.recordAIResult(errors.New("provider unavailable"))
+	}
+	system.recordAIResult(context.Canceled)
+	assert.Equal(t, 3.0, system.aiFailures.Value())
+
+	// 拒单比例只在下单笔数达到窗口后判断
+	rejected := fmt.Errorf("%w: insufficient balance", trading.ErrOrderRejected)
+	system.recordOrderResult("main", rejected)
+	system.recordOrderResult("main", rejected)
+	system.recordOrderResult("main", rejected)
+	assert.Equal(t, []string{alertAIFailures}, system.checkAlerts(ctx, now))
+	system.recordOrderResult("main", nil)
+	assert.InDelta(t, 0.75, system.rejectRatio.Value(), 1e-9)
+	assert.Equal(t, 3.0, system.orderResults.Value("main", "rejected"))
+
+	firing := system.checkAlerts(ctx, now.Add(6*time.Minute))
+	assert.Equal(t, []string{alertAIFailures, alertOrderRejects, alertStaleData}, firing)
+	assert.Equal(t, 1.0, system.alertFiring.Value(alertStaleData))
+	assert.InDelta(t, 360, system.dataAge.Value("BTCUSDT"), 1)
+
+	// 恢复后不再告警
+	system.recordAIResult(nil)
+	system.recordMarketUpdate("BTCUSDT")
+	for i := 0; i < 4; i++ {
+		system.recordOrderResult("main", nil)
+	}
+	assert.Empty(t, system.checkAlerts(ctx, time.Now()))
+	assert.Zero(t, system.alertFiring.Value(alertStaleData))
+	assert.Zero(t, system.alertFiring.Value(alertAIFailures))
+}
+
+func TestAlertRules(t *testing.T) {
+	config := &configs.Config{
+		AlertConfig:    configs.AlertConfig{MaxDataAge: "5m", MaxRejectRatio: 0.5},
+		DrawdownConfig: configs.DrawdownConfig{MaxDrawdown: 0.2},
+	}
+	rules := alertRules(config)
+	require.Len(t, rules, 3)
+	assert.Equal(t, "quantaflux_market_data_age_seconds > 300", rules[0].Expr)
+	assert.Equal(t, "1m0s", rules[0].For)
+	assert.Equal(t, "quantaflux_order_reject_ratio > 0.5", rules[1].Expr)
+	assert.Equal(t, "critical", rules[2].Labels["severity"])
+}
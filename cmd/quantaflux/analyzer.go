@@ -0,0 +1,22 @@
+package main
+
+import (
+	"github.com/songzhibin97/quantaflux/internal/ai"
+	"github.com/songzhibin97/quantaflux/internal/ai/deepseek"
+	"github.com/songzhibin97/quantaflux/internal/configs"
+)
+
+// buildAnalyzer 创建 AI 分析器，各分析方法使用给定的生成参数
+func buildAnalyzer(apiKey, model string, generation ai.GenerationOptions) ai.Analyzer {
+	analyzer := deepseek.NewDeepSeekAnalyzer(apiKey, model)
+	analyzer.SetGenerationOptions(generation)
+	return analyzer
+}
+
+// championModel 冠军分析器预测价格使用的模型，按方法配置的模型优先
+func championModel(config *configs.Config) string {
+	if model := config.AIConfig.Generation().For(ai.MethodPredictPrice).Model; model != "" {
+		return model
+	}
+	return config.AIConfig.ModelType
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/songzhibin97/quantaflux/internal/api"
+	"github.com/songzhibin97/quantaflux/internal/arbitrage"
+	"github.com/songzhibin97/quantaflux/internal/journal"
+	"github.com/songzhibin97/quantaflux/internal/notify"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+
+	collectorData "github.com/songzhibin97/quantaflux/internal/data/collector"
+)
+
+// detectArbitrageJob 返回比较各交易所报价的任务，venues 按名称从 sources 中查找报价来源；
+// arbitrage_config.amount 大于 0 时用各交易所对应账户的执行器下单
+func (a *app) detectArbitrageJob(sources []collectorData.DataSource) (func(ctx context.Context) error, error) {
+	config := a.config.ArbitrageConfig
+	var venues []arbitrage.Venue
+	executors := make(map[string]trading.TradeExecutor)
+	for _, venue := range config.Venues {
+		i := slices.IndexFunc(sources, func(source collectorData.DataSource) bool { return source.Name() == venue.Source })
+		if i < 0 {
+			return nil, fmt.Errorf("arbitrage venue %s is not an enabled market data source", venue.Source)
+		}
+		venues = append(venues, arbitrage.Venue{Name: venue.Source, Source: sources[i], FeeRate: venue.FeeRate})
+		if venue.Account == "" {
+			continue
+		}
+		account, err := a.system.account(venue.Account)
+		if err != nil {
+			return nil, err
+		}
+		executors[venue.Source] = account.executor
+	}
+
+	detector := arbitrage.NewDetector(venues, config.SlippageBps, config.MinProfitBps)
+	var strategy *arbitrage.Strategy
+	if config.Amount > 0 {
+		strategy = arbitrage.NewStrategy(executors, config.Amount)
+	}
+	accounts := make(map[string]string, len(config.Venues))
+	for _, venue := range config.Venues {
+		accounts[venue.Source] = venue.Account
+	}
+	return func(ctx context.Context) error {
+		return a.system.detectArbitrage(ctx, detector, strategy, accounts)
+	}, nil
+}
+
+// detectArbitrage 比较各交易对在各交易所的报价，利润最高的机会推送 arbitrage 事件并发送通知；
+// strategy 不为空时执行套利，两边订单按账户名称记录到交易日志。单个交易对失败不影响其他交易对
+func (s *QuantSystem) detectArbitrage(ctx context.Context, detector *arbitrage.Detector, strategy *arbitrage.Strategy, accounts map[string]string) error {
+	config := s.cfg()
+	symbols := config.ArbitrageConfig.Symbols
+	if len(symbols) == 0 {
+		symbols = config.Symbols
+	}
+
+	var errs []error
+	for _, symbol := range symbols {
+		opportunities, err := detector.Detect(ctx, symbol)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", symbol, err))
+			continue
+		}
+		if len(opportunities) == 0 {
+			continue
+		}
+
+		opp := opportunities[0]
+		log.Info("arbitrage opportunity", "symbol", symbol, "buy", opp.BuyVenue, "sell", opp.SellVenue, "spread_bps", opp.SpreadBps, "net_bps", opp.NetBps)
+		s.publish(api.EventArbitrage, symbol, opp)
+		s.notify(ctx, notify.Message{
+			Title: "Arbitrage opportunity: " + symbol,
+			Text:  fmt.Sprintf("Buy on %s at %.8g, sell on %s at %.8g, %.1fbps net of fees and slippage.", opp.BuyVenue, opp.BuyPrice, opp.SellVenue, opp.SellPrice, opp.NetBps),
+			Level: notify.LevelInfo,
+		})
+		if strategy == nil {
+			continue
+		}
+
+		buy, sell, err := strategy.Execute(ctx, opp)
+		for _, order := range []*trading.Order{buy, sell} {
+			if order == nil || order.OrderID == "" {
+				continue
+			}
+			order.Account = accounts[order.Account]
+			s.recordTrade(ctx, &journal.Entry{Strategy: "arbitrage", Order: *order})
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", symbol, err))
+			continue
+		}
+		log.Info("arbitrage executed", "symbol", symbol, "buy", opp.BuyVenue, "sell", opp.SellVenue, "amount", sell.Amount)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/models"
+	"github.com/songzhibin97/quantaflux/internal/notify"
+	"github.com/songzhibin97/quantaflux/internal/risk"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+	"github.com/songzhibin97/quantaflux/internal/trading/paper"
+
+	collectorData "github.com/songzhibin97/quantaflux/internal/data/collector"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type priceSource struct {
+	name  string
+	price float64
+}
+
+func (p *priceSource) Name() string { return p.name }
+
+func (p *priceSource) CollectTokenInfo(context.Context, string) (*models.TokenInfo, error) {
+	return nil, nil
+}
+
+func (p *priceSource) CollectMarketData(_ context.Context, symbol string) (*models.MarketData, error) {
+	return &models.MarketData{Symbol: symbol, Price: p.price, Timestamp: time.Now()}, nil
+}
+
+func (p *priceSource) CollectSocialMetrics(context.Context, string) (map[string]float64, error) {
+	return nil, nil
+}
+
+type memoryNotifier struct {
+	messages []notify.Message
+}
+
+func (m *memoryNotifier) Notify(_ context.Context, msg notify.Message) error {
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+func TestQuantSystem_DetectArbitrage(t *testing.T) {
+	ctx := context.Background()
+	system, store := newTestSystem(t, map[string]float64{"USDT": 1000})
+	notifier := &memoryNotifier{}
+	system.notifier = notifier
+	primary := system.primaryAccount()
+	other := &account{
+		name:        "other",
+		executor:    paper.NewPaperExecutor(map[string]float64{"BTC": 1}),
+		riskManager: risk.NewBasicRiskManager(system.cfg().RiskParams),
+	}
+	system.accounts = append(system.accounts, other)
+	primary.executor.(trading.MarketPriceUpdater).UpdateMarketPrice("BTCUSDT", 100)
+	other.executor.(trading.MarketPriceUpdater).UpdateMarketPrice("BTCUSDT", 101)
+
+	config := *system.cfg()
+	config.ArbitrageConfig = configs.ArbitrageConfig{
+		Venues: []configs.ArbitrageVenue{
+			{Source: "binance", FeeRate: 0.001},
+			{Source: "coingecko", FeeRate: 0.001},
+		},
+		MinProfitBps: 10,
+	}
+	system.config.Store(&config)
+	a := &app{config: &config, system: system}
+	sources := []collectorData.DataSource{&priceSource{name: "binance", price: 100}, &priceSource{name: "coingecko", price: 101}}
+
+	// 未配置 amount 时只推送和通知
+	job, err := a.detectArbitrageJob(sources)
+	require.NoError(t, err)
+	require.NoError(t, job(ctx))
+	require.Len(t, notifier.messages, 1)
+	assert.Contains(t, notifier.messages[0].Text, "Buy on binance")
+	assert.Empty(t, store.entries)
+
+	// 报价来源必须是已启用的数据源
+	_, err = a.detectArbitrageJob(sources[:1])
+	require.Error(t, err)
+
+	// 配置 amount 后在两个账户下单并记录到交易日志
+	config.ArbitrageConfig.Amount = 0.5
+	config.ArbitrageConfig.Venues[0].Account = "main"
+	config.ArbitrageConfig.Venues[1].Account = "other"
+	job, err = a.detectArbitrageJob(sources)
+	require.NoError(t, err)
+	require.NoError(t, job(ctx))
+	require.Len(t, store.entries, 2)
+	assert.Equal(t, "main", store.entries[0].Order.Account)
+	assert.Equal(t, "buy", store.entries[0].Order.Side)
+	assert.Equal(t, "other", store.entries[1].Order.Account)
+	assert.Equal(t, "sell", store.entries[1].Order.Side)
+	assert.Equal(t, "arbitrage", store.entries[1].Strategy)
+	balance, err := other.executor.GetBalance(ctx, "BTC")
+	require.NoError(t, err)
+	assert.InDelta(t, 0.5, balance, 1e-9)
+
+	// 价差不足时不产生机会
+	sources[1] = &priceSource{name: "coingecko", price: 100.1}
+	job, err = a.detectArbitrageJob(sources)
+	require.NoError(t, err)
+	require.NoError(t, job(ctx))
+	assert.Len(t, notifier.messages, 2)
+	assert.Len(t, store.entries, 2)
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/archive"
+	"github.com/songzhibin97/quantaflux/internal/configs"
+)
+
+// buildArchiveStore 根据 archive_config 创建归档文件的存储位置
+func buildArchiveStore(config configs.ArchiveConfig) (archive.Store, string, error) {
+	scheme, bucket, prefix, err := config.Location()
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid archive destination: %w", err)
+	}
+
+	options := archive.S3Options{
+		Endpoint:  config.Endpoint,
+		Region:    config.Region,
+		Bucket:    bucket,
+		AccessKey: config.AccessKey,
+		SecretKey: config.SecretKey,
+	}
+	switch scheme {
+	case "file":
+		return archive.NewDirStore(bucket), prefix, nil
+	case "gs":
+		if options.Endpoint == "" {
+			options.Endpoint = "https://storage.googleapis.com"
+		}
+		if options.Region == "" {
+			options.Region = "auto"
+		}
+	default:
+		if options.Region == "" {
+			options.Region = "us-east-1"
+		}
+		if options.Endpoint == "" {
+			options.Endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", options.Region)
+		}
+	}
+	return archive.NewS3Store(options), prefix, nil
+}
+
+// archiveData 将超过保留时长的行情、已完成订单和审计日志导出到冷归档，上传成功后从数据库删除
+func (a *app) archiveData(ctx context.Context, retention time.Duration) error {
+	config := a.system.cfg().ArchiveConfig
+	store, prefix, err := buildArchiveStore(config)
+	if err != nil {
+		return err
+	}
+
+	results, err := archive.NewArchiver(a.storage, store, prefix).Run(ctx, config.ArchivedTables(), time.Now().Add(-retention))
+	for _, result := range results {
+		if result.Rows > 0 {
+			log.Info("archived data", "table", result.Table, "rows", result.Rows, "files", len(result.Files), "retention", retention)
+		}
+	}
+	return err
+}
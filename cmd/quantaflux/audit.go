@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+
+	"github.com/songzhibin97/quantaflux/internal/audit"
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+)
+
+// newAuditLog 创建审计日志：写入数据库，配置了文件路径时同时追加到文件
+// 回测不产生真实操作，不记录审计日志
+func newAuditLog(config *configs.Config, store audit.Sink) (*audit.Log, func(), error) {
+	if config.RunMode() == configs.ModeBacktest {
+		return nil, func() {}, nil
+	}
+
+	sinks := []audit.Sink{store}
+	closeFn := func() {}
+	if config.AuditConfig.File != "" {
+		file, err := audit.NewFileSink(config.AuditConfig.File)
+		if err != nil {
+			return nil, nil, err
+		}
+		sinks = append(sinks, file)
+		closeFn = func() {
+			if err := file.Close(); err != nil {
+				log.Error("Error closing audit file", "err", err)
+			}
+		}
+	}
+
+	return audit.NewLog(moduleLog("audit"), sinks...), closeFn, nil
+}
+
+// auditOrder 记录下单结果，失败的下单同样记录
+func (s *QuantSystem) auditOrder(ctx context.Context, order *trading.Order, reason string, err error) {
+	details := map[string]any{
+		"mode":       s.cfg().RunMode(),
+		"account":    order.Account,
+		"side":       order.Side,
+		"amount":     order.Amount,
+		"price":      order.Price,
+		"order_type": order.OrderType,
+		"order_id":   order.OrderID,
+		"status":     order.Status,
+	}
+	if order.QuoteAmount > 0 {
+		details["quote_amount"] = order.QuoteAmount
+	}
+	if err != nil {
+		details["error"] = err.Error()
+	}
+	s.audit.Record(ctx, audit.ActionPlaceOrder, order.Symbol, reason, details)
+}
+
+// auditCancel 记录撤单结果
+func (c *control) auditCancel(ctx context.Context, order trading.Order, reason string, err error) {
+	details := map[string]any{
+		"account":  order.Account,
+		"order_id": order.OrderID,
+	}
+	if err != nil {
+		details["error"] = err.Error()
+	}
+	c.audit.Record(ctx, audit.ActionCancelOrder, order.Symbol, reason, details)
+}
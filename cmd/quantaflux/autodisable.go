@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/audit"
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/journal"
+	"github.com/songzhibin97/quantaflux/internal/notify"
+	"github.com/songzhibin97/quantaflux/internal/risk"
+)
+
+// loadPerformance 回放当前运行模式的历史成交，恢复持仓成本，避免重启后平仓盈亏无法计算
+// 连续亏损和滚动盈亏从启动时重新统计，已人工复核恢复的交易对不会因历史亏损再次停用
+func (s *QuantSystem) loadPerformance(ctx context.Context) error {
+	if s.tradeJournal == nil || s.cfg().RunMode() == configs.ModeBacktest {
+		return nil
+	}
+
+	trades, err := s.tradeJournal.ListTradesInRange(ctx, time.Time{}, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to load trades: %w", err)
+	}
+	for _, entry := range trades {
+		mode := entry.Mode
+		if mode == "" {
+			mode = configs.ModeLive
+		}
+		if mode != s.cfg().RunMode() {
+			continue
+		}
+		s.performance.Record(entry.Order, entry.CreatedAt)
+	}
+	for _, symbol := range s.cfg().Symbols {
+		s.performance.Reset(symbol)
+	}
+	return nil
+}
+
+// trackPerformance 记录成交的平仓盈亏，连续亏损或滚动盈亏过低时自动停用交易对
+func (s *QuantSystem) trackPerformance(ctx context.Context, entry *journal.Entry) {
+	at := entry.MarketData.Timestamp
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	symbol := entry.Order.Symbol
+	if _, closed := s.performance.Record(entry.Order, at); !closed {
+		return
+	}
+
+	config := s.cfg().AutoDisableConfig
+	since := at
+	if window, err := time.ParseDuration(config.RollingWindow); err == nil {
+		since = at.Add(-window)
+	}
+	performance := s.performance.Performance(symbol, since)
+
+	if _, disabled := s.disabledUntil(symbol); disabled {
+		return
+	}
+	reason := disableReason(config, performance)
+	if reason == "" {
+		return
+	}
+	s.disableSymbol(ctx, symbol, at, reason, performance)
+}
+
+// disableReason 判断交易对是否应停用，返回停用原因，无需停用时返回空字符串
+func disableReason(config configs.AutoDisableConfig, performance risk.SymbolPerformance) string {
+	if config.MaxConsecutiveLosses > 0 && performance.ConsecutiveLosses >= config.MaxConsecutiveLosses {
+		return fmt.Sprintf("%d consecutive losing trades", performance.ConsecutiveLosses)
+	}
+	if config.RollingWindow != "" && performance.RollingPnL < config.MinRollingPnL {
+		return fmt.Sprintf("rolling PnL %.2f over %s is below %.2f", performance.RollingPnL, config.RollingWindow, config.MinRollingPnL)
+	}
+	return ""
+}
+
+// disableSymbol 停用交易对，到达复核时间后自动恢复，未配置复核时间时需手动恢复
+func (s *QuantSystem) disableSymbol(ctx context.Context, symbol string, at time.Time, reason string, performance risk.SymbolPerformance) {
+	var until time.Time
+	if review, err := time.ParseDuration(s.cfg().AutoDisableConfig.ReviewPeriod); err == nil {
+		until = at.Add(review)
+	}
+
+	s.mu.Lock()
+	s.pausedSymbols[symbol] = true
+	s.disabled[symbol] = until
+	s.mu.Unlock()
+	s.persistState(ctx)
+
+	log.Warn("symbol auto-disabled", "symbol", symbol, "reason", reason, "review_at", until)
+	s.audit.Record(ctx, audit.ActionDisableSymbol, symbol, reason, map[string]any{
+		"consecutive_losses": performance.ConsecutiveLosses,
+		"rolling_pnl":        performance.RollingPnL,
+		"review_at":          until,
+	})
+
+	text := fmt.Sprintf("Trading of %s stopped: %s.", symbol, reason)
+	if until.IsZero() {
+		text += " Resume it manually after review."
+	} else {
+		text += fmt.Sprintf(" It will be re-enabled at %s.", until.Format(time.RFC3339))
+	}
+	s.notify(ctx, notify.Message{
+		Title: fmt.Sprintf("%s auto-disabled", symbol),
+		Text:  text,
+		Level: notify.LevelWarning,
+	})
+}
+
+// reviewDisabled 复核时间已到时恢复自动停用的交易对，now 使用行情时间以便回测复现
+func (s *QuantSystem) reviewDisabled(ctx context.Context, symbol string, now time.Time) {
+	until, disabled := s.disabledUntil(symbol)
+	if !disabled || until.IsZero() || now.Before(until) {
+		return
+	}
+
+	s.ResumeSymbol(symbol)
+	log.Info("symbol re-enabled after review period", "symbol", symbol)
+	s.audit.Record(ctx, audit.ActionResumeSymbol, symbol, "auto-disable review period elapsed", nil)
+	s.notify(ctx, notify.Message{
+		Title: fmt.Sprintf("%s re-enabled", symbol),
+		Text:  fmt.Sprintf("Trading of %s resumed after the review period.", symbol),
+		Level: notify.LevelInfo,
+	})
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/data/backfill"
+	"github.com/songzhibin97/quantaflux/internal/data/collector/binance"
+)
+
+// cmdBackfill 从 Binance 拉取历史 K 线写入存储，供回测使用；中断后重新执行同一命令即可续传
+func cmdBackfill(args []string) error {
+	fs, conf := newFlagSet("backfill")
+	var symbols stringList
+	fs.Var(&symbols, "symbol", "trading pair, repeatable, defaults to the configured symbols")
+	start := fs.String("start", "", "start time (RFC3339), defaults to 90 days ago")
+	end := fs.String("end", "", "end time (RFC3339), defaults to now")
+	interval := fs.Duration("interval", time.Minute, "kline interval, eg: 1m, 5m, 1h")
+	pageSize := fs.Int("page-size", backfill.DefaultPageSize, "klines per request and per insert batch")
+	_ = fs.Parse(args)
+
+	startTime, endTime, err := parseRange(*start, *end, 90*24*time.Hour)
+	if err != nil {
+		return err
+	}
+
+	quietLogs()
+	a, err := loadApp(*conf)
+	if err != nil {
+		return err
+	}
+	defer a.closeStorage()
+
+	if len(symbols) == 0 {
+		symbols = a.config.Symbols
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	backfiller := backfill.NewBackfiller(binance.NewBinanceDataSource(), a.storage, *pageSize)
+	result := make(map[string]int, len(symbols))
+	for _, symbol := range symbols {
+		began := time.Now()
+		rows, err := backfiller.Run(ctx, symbol, *interval, startTime, endTime, func(p backfill.Progress) {
+			fmt.Fprintf(os.Stderr, "%s %5.1f%% %s rows=%d\n", p.Symbol, p.Percent, p.Current.Format(time.RFC3339), p.Rows)
+		})
+		result[symbol] = rows
+		if err != nil {
+			return fmt.Errorf("backfill %s stopped after %d rows, rerun to resume: %w", symbol, rows, err)
+		}
+		fmt.Fprintf(os.Stderr, "%s done: %d rows in %s\n", symbol, rows, time.Since(began).Round(time.Millisecond))
+	}
+
+	return printJSON(result)
+}
+
+// cmdBackfillMetrics 为 token_info 中缺少近期项目指标的代币执行项目分析并保存，中断后重新执行同一命令即可续传
+func cmdBackfillMetrics(args []string) error {
+	fs, conf := newFlagSet("backfill-metrics")
+	maxAge := fs.Duration("max-age", 7*24*time.Hour, "re-analyze tokens whose latest project metrics are older than this")
+	interval := fs.Duration("interval", 2*time.Second, "minimum time between two AI analyses")
+	_ = fs.Parse(args)
+
+	quietLogs()
+	a, err := loadApp(*conf)
+	if err != nil {
+		return err
+	}
+	defer a.closeStorage()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	backfiller := backfill.NewMetricsBackfiller(a.storage, a.analyzer, *interval)
+	result, err := backfiller.Run(ctx, *maxAge, func(p backfill.MetricsProgress) {
+		if p.Err != nil {
+			fmt.Fprintf(os.Stderr, "%s failed: %v\n", p.Symbol, p.Err)
+			return
+		}
+		fmt.Fprintf(os.Stderr, "%s done, analyzed=%d failed=%d\n", p.Symbol, p.Analyzed, p.Failed)
+	})
+	if err != nil {
+		return fmt.Errorf("metrics backfill stopped after %d tokens, rerun to resume: %w", result.Analyzed, err)
+	}
+
+	return printJSON(result)
+}
+
+// stringList 可重复指定的字符串参数
+type stringList []string
+
+func (l *stringList) String() string {
+	return fmt.Sprint(*l)
+}
+
+func (l *stringList) Set(v string) error {
+	*l = append(*l, v)
+	return nil
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/songzhibin97/quantaflux/internal/data"
+	"github.com/songzhibin97/quantaflux/internal/journal"
+	"github.com/songzhibin97/quantaflux/internal/models"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+)
+
+// bracketNamespace 组合订单状态的命名空间，键为账户名称
+const bracketNamespace = "brackets"
+
+// placeBracket 以组合订单下信号买单，止盈止损价按参考价计算：限价单为委托价，其他为当前行情价。
+// 入场单已下单时即使平仓单创建失败也返回 nil，失败原因写入运行日志，入场单照常记录
+func (s *QuantSystem) placeBracket(ctx context.Context, a *account, order *trading.Order, price float64) error {
+	config := s.cfg().TradingConfig.Bracket
+	if order.OrderType == "limit" && order.Price > 0 {
+		price = order.Price
+	}
+	bracket := &trading.BracketOrder{
+		Entry:      *order,
+		TakeProfit: price * (1 + config.TakeProfit),
+		StopLoss:   price * (1 - config.StopLoss),
+	}
+
+	var err error
+	s.updateBrackets(ctx, a, func() error {
+		err = a.brackets.PlaceBracket(ctx, bracket)
+		return err
+	})
+	if bracket.ID == "" {
+		return err
+	}
+	if err != nil {
+		log.Error("Error placing bracket exit orders", "account", a.name, "symbol", order.Symbol, "bracket", bracket.ID, "status", bracket.Status, "err", err)
+	}
+	*order = bracket.Entry
+	log.Info("bracket order placed", "account", a.name, "symbol", order.Symbol, "bracket", bracket.ID, "status", bracket.Status,
+		"take_profit", bracket.TakeProfit, "stop_loss", bracket.StopLoss, "native", bracket.Native)
+	return nil
+}
+
+// runBrackets 按最新行情触发本地模拟的止损；模拟撮合的账户同时同步入场单和止盈单的成交
+func (s *QuantSystem) runBrackets(ctx context.Context, data models.MarketData, simulated map[string]bool) {
+	for _, a := range s.accounts {
+		if a.brackets == nil {
+			continue
+		}
+		err := s.updateBrackets(ctx, a, func() error {
+			var errs []error
+			if simulated[a.name] {
+				errs = append(errs, a.brackets.Sync(ctx))
+			}
+			errs = append(errs, a.brackets.OnPrice(ctx, data.Symbol, data.Price))
+			return errors.Join(errs...)
+		})
+		if err != nil {
+			log.Error("Error updating bracket orders", "account", a.name, "symbol", data.Symbol, "err", err)
+		}
+	}
+}
+
+// syncBrackets 同步各账户未结束的组合订单，由 sync_orders 任务调用
+func (s *QuantSystem) syncBrackets(ctx context.Context) error {
+	var errs []error
+	for _, a := range s.accounts {
+		if a.brackets == nil {
+			continue
+		}
+		if err := s.updateBrackets(ctx, a, func() error { return a.brackets.Sync(ctx) }); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", a.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// cancelBrackets 撤销账户在交易对上未结束的组合订单，卖出持仓前调用，避免平仓单占用持仓
+func (s *QuantSystem) cancelBrackets(ctx context.Context, a *account, symbol string) {
+	if a.brackets == nil {
+		return
+	}
+	err := s.updateBrackets(ctx, a, func() error {
+		var errs []error
+		for _, b := range a.brackets.Brackets() {
+			if b.Entry.Symbol != symbol || (b.Status != trading.BracketPending && b.Status != trading.BracketActive) {
+				continue
+			}
+			if err := a.brackets.CancelBracket(ctx, b.ID); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	})
+	if err != nil {
+		log.Error("Error canceling bracket orders", "account", a.name, "symbol", symbol, "err", err)
+	}
+}
+
+// updateBrackets 执行组合订单操作：新创建的平仓单记入交易日志并跟踪成交，结束的组合订单被移除，
+// 有变化时保存未结束的组合订单。同一账户的操作依次执行，平仓单不会被重复记录
+func (s *QuantSystem) updateBrackets(ctx context.Context, a *account, fn func() error) error {
+	a.bracketMu.Lock()
+	defer a.bracketMu.Unlock()
+
+	before := a.brackets.Brackets()
+	err := fn()
+	after := a.brackets.Brackets()
+	if reflect.DeepEqual(before, after) {
+		return err
+	}
+
+	previous := make(map[string]trading.BracketOrder, len(before))
+	for _, b := range before {
+		previous[b.ID] = b
+	}
+	for _, b := range after {
+		old := previous[b.ID]
+		for _, exit := range []struct{ order, old trading.Order }{{b.ProfitOrder, old.ProfitOrder}, {b.StopOrder, old.StopOrder}} {
+			if exit.order.OrderID == "" || exit.order.OrderID == exit.old.OrderID {
+				continue
+			}
+			exit.order.Account = a.name
+			s.trackOrder(exit.order)
+			s.recordTrade(ctx, &journal.Entry{Strategy: "bracket", Order: exit.order})
+		}
+		if b.Status != old.Status {
+			log.Info("bracket order updated", "account", a.name, "symbol", b.Entry.Symbol, "bracket", b.ID, "status", b.Status)
+		}
+	}
+	a.brackets.Prune()
+	s.saveBrackets(ctx, a)
+	return err
+}
+
+// restoreBrackets 恢复各账户重启前未结束的组合订单，回测时状态保存在内存中，从空开始
+func (s *QuantSystem) restoreBrackets(ctx context.Context) {
+	if s.dataStorage == nil {
+		return
+	}
+	for _, a := range s.accounts {
+		if a.brackets == nil {
+			continue
+		}
+		saved, err := s.dataStorage.GetStrategyState(ctx, bracketNamespace, a.name)
+		if errors.Is(err, data.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			log.Error("Error loading bracket orders", "account", a.name, "err", err)
+			continue
+		}
+		var brackets []trading.BracketOrder
+		if err := json.Unmarshal(saved.Value, &brackets); err != nil {
+			log.Error("Error decoding bracket orders", "account", a.name, "err", err)
+			continue
+		}
+		a.brackets.Restore(brackets)
+		a.bracketVersion = saved.Version
+		log.Info("bracket orders restored", "account", a.name, "count", len(brackets))
+	}
+}
+
+// saveBrackets 保存账户未结束的组合订单；版本冲突说明另一个实例在管理同一账户，记录错误并以存储中的版本为准继续
+func (s *QuantSystem) saveBrackets(ctx context.Context, a *account) {
+	if s.dataStorage == nil {
+		return
+	}
+	value, err := json.Marshal(a.brackets.Brackets())
+	if err != nil {
+		log.Error("Error encoding bracket orders", "account", a.name, "err", err)
+		return
+	}
+	state := &data.StrategyState{Namespace: bracketNamespace, Key: a.name, Value: value, Version: a.bracketVersion}
+	err = s.dataStorage.SetStrategyState(ctx, state)
+	if errors.Is(err, data.ErrStateConflict) {
+		log.Error("bracket orders changed by another writer", "account", a.name, "err", err)
+		if latest, getErr := s.dataStorage.GetStrategyState(ctx, bracketNamespace, a.name); getErr == nil {
+			a.bracketVersion = latest.Version
+		}
+		return
+	}
+	if err != nil {
+		log.Error("Error saving bracket orders", "account", a.name, "err", err)
+		return
+	}
+	a.bracketVersion = state.Version
+}
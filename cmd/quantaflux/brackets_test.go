@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/ai"
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/data/collector/replay"
+	"github.com/songzhibin97/quantaflux/internal/models"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuantSystem_Brackets(t *testing.T) {
+	ctx := context.Background()
+	system, store := newTestSystem(t, map[string]float64{"USDT": 1000})
+	system.dataStorage = replay.NewGuardedStorage(nil)
+	config := *system.cfg()
+	config.TradingConfig.Bracket = configs.BracketConfig{TakeProfit: 0.1, StopLoss: 0.05}
+	system.config.Store(&config)
+	system.wrapExecutors(nil)
+	a := system.primaryAccount()
+	tick := func(price float64) models.MarketData {
+		data := models.MarketData{Symbol: "BTCUSDT", Price: price, Timestamp: time.Now()}
+		system.updateMarketData(data)
+		updater, _ := trading.As[trading.MarketPriceUpdater](a.executor)
+		updater.UpdateMarketPrice(data.Symbol, data.Price)
+		return data
+	}
+	buy := func(data models.MarketData) *trading.Order {
+		signal := tradeSignal{data: data, side: "buy", prediction: &ai.PricePrediction{PredictedPrice: 103}}
+		order := &trading.Order{Account: a.name, Symbol: "BTCUSDT", Side: "buy", Amount: 1, Price: 103, OrderType: "market"}
+		require.NoError(t, system.submitOrder(governedContext(ctx), a, order, signal))
+		return order
+	}
+
+	// 信号买单成交后按当前行情价挂出止盈单，止盈单记入交易日志
+	order := buy(tick(100))
+	assert.Equal(t, "FILLED", order.Status)
+	brackets := a.brackets.Brackets()
+	require.Len(t, brackets, 1)
+	assert.Equal(t, trading.BracketActive, brackets[0].Status)
+	assert.InDelta(t, 110, brackets[0].TakeProfit, 1e-9)
+	assert.InDelta(t, 95, brackets[0].StopLoss, 1e-9)
+	require.Len(t, store.entries, 1)
+	assert.Equal(t, "bracket", store.entries[0].Strategy)
+	assert.Equal(t, "limit", store.entries[0].Order.OrderType)
+	assert.Equal(t, a.name, store.entries[0].Order.Account)
+
+	// 重启后从策略状态恢复未结束的组合订单
+	a.brackets = trading.NewOrderManager(a.executor)
+	system.restoreBrackets(ctx)
+	require.Len(t, a.brackets.Brackets(), 1)
+
+	// 跌破止损价时撤销止盈单并按市价平仓，结束的组合订单不再保存
+	system.runBrackets(ctx, tick(94), map[string]bool{a.name: true})
+	assert.Empty(t, a.brackets.Brackets())
+	require.Len(t, store.entries, 2)
+	assert.Equal(t, "market", store.entries[1].Order.OrderType)
+	assert.Equal(t, 1.0, store.entries[1].Order.ExecutedAmount())
+	saved, err := system.dataStorage.GetStrategyState(ctx, bracketNamespace, a.name)
+	require.NoError(t, err)
+	assert.JSONEq(t, "[]", string(saved.Value))
+
+	// 平仓前撤销平仓单，释放被占用的持仓
+	buy(tick(100))
+	require.Len(t, a.brackets.Brackets(), 1)
+	closed, err := system.ClosePosition(ctx, "", "BTCUSDT", 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, closed.ExecutedAmount())
+	assert.Empty(t, a.brackets.Brackets())
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/ai"
+	"github.com/songzhibin97/quantaflux/internal/models"
+)
+
+// 预测时间范围无法解析时按 1 小时判断结果
+const defaultCalibrationHorizon = time.Hour
+
+// calibrate 记录模型原始置信度的预测用于统计准确率，并将置信度替换为校准后的值，
+// 原始置信度保存在 RawConfidence 中；之后的置信度过滤、A/B 决策和交易日志使用校准后的值
+func (s *QuantSystem) calibrate(data models.MarketData, prediction *ai.PricePrediction) {
+	if s.calibrator == nil {
+		return
+	}
+
+	horizon, err := time.ParseDuration(prediction.TimeFrame)
+	if err != nil || horizon <= 0 {
+		horizon = defaultCalibrationHorizon
+	}
+	raw := prediction.Confidence
+	s.calibrator.Record(data.Symbol, data.Timestamp, data.Price, prediction.PredictedPrice, raw, horizon)
+
+	prediction.RawConfidence = raw
+	prediction.Confidence = s.calibrator.Calibrate(raw, data.Timestamp)
+	if prediction.Confidence != raw {
+		log.Debug("prediction confidence calibrated", "symbol", data.Symbol, "raw", raw, "calibrated", prediction.Confidence)
+	}
+}
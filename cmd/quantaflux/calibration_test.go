@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/ai"
+	"github.com/songzhibin97/quantaflux/internal/calibration"
+	"github.com/songzhibin97/quantaflux/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuantSystem_Calibrate(t *testing.T) {
+	system, _ := newTestSystem(t, map[string]float64{"USDT": 1000})
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// 未启用校准时保留原始置信度
+	prediction := &ai.PricePrediction{PredictedPrice: 110, Confidence: 0.9, TimeFrame: "1h"}
+	system.calibrate(models.MarketData{Symbol: "BTCUSDT", Price: 100, Timestamp: start}, prediction)
+	assert.Equal(t, ai.PricePrediction{PredictedPrice: 110, Confidence: 0.9, TimeFrame: "1h"}, *prediction)
+
+	system.calibrator = calibration.New(calibration.Options{Window: 24 * time.Hour, MinSamples: 2})
+	for i := 0; i < 2; i++ {
+		tick := models.MarketData{Symbol: "BTCUSDT", Price: 100, Timestamp: start.Add(time.Duration(i) * time.Minute)}
+		system.calibrate(tick, &ai.PricePrediction{PredictedPrice: 110, Confidence: 0.92, TimeFrame: "30m"})
+	}
+	// 预测到期后价格下跌，高置信度的预测全部错误
+	system.calibrator.Observe("BTCUSDT", start.Add(time.Hour), 95)
+
+	prediction = &ai.PricePrediction{PredictedPrice: 110, Confidence: 0.95, TimeFrame: "1h"}
+	system.calibrate(models.MarketData{Symbol: "BTCUSDT", Price: 95, Timestamp: start.Add(time.Hour)}, prediction)
+	assert.InDelta(t, 0.95, prediction.RawConfidence, 1e-9)
+	assert.Zero(t, prediction.Confidence)
+}
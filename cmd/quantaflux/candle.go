@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/ai"
+	"github.com/songzhibin97/quantaflux/internal/data/candle"
+	"github.com/songzhibin97/quantaflux/internal/models"
+)
+
+// candleTrigger 判断行情是否触发策略：未配置 K 线周期时每条行情都触发，
+// 否则只在 K 线收盘时触发。K 线在收到下一周期的第一条行情时收盘，
+// 策略按这条当前行情的价格下单，与模拟撮合使用的最新价格一致
+func (s *QuantSystem) candleTrigger(data models.MarketData) bool {
+	interval, err := time.ParseDuration(s.cfg().TradingConfig.CandleInterval)
+	if err != nil || interval <= 0 {
+		return true
+	}
+
+	s.candleMu.Lock()
+	// 热加载修改周期后重新开始聚合
+	if s.candles == nil || s.candles.Interval() != interval {
+		s.candles = candle.NewAggregator(interval)
+	}
+	aggregator := s.candles
+	s.candleMu.Unlock()
+
+	closed, ok := aggregator.Add(data)
+	if !ok {
+		return false
+	}
+
+	log.Debug("candle closed", "symbol", closed.Symbol, "interval", interval, "open", closed.Open, "close", closed.Close, "ticks", closed.Ticks)
+	return true
+}
+
+// 未配置 K 线周期时，预测历史窗口降采样后的最多行情条数
+const maxPredictHistoryPoints = 48
+
+// predictionWindow 返回价格预测使用的行情序列：当前行情之前 predict_history 时长内的历史行情按 K 线收盘价降采样，
+// 最后一条为当前行情；历史只取当前行情时间之前的数据，回测时不会用到未来行情。
+// 同时返回按降采样前的完整历史计算的统计量，用于提示词，历史不足时返回 false
+func (s *QuantSystem) predictionWindow(ctx context.Context, data models.MarketData) ([]models.MarketData, ai.MarketStats, bool, error) {
+	window, err := time.ParseDuration(s.cfg().AIConfig.PredictHistory)
+	if err != nil || window <= 0 {
+		return []models.MarketData{data}, ai.MarketStats{}, false, nil
+	}
+
+	spanCtx, span := s.tracer.Start(ctx, "storage.price_history")
+	history, err := s.dataStorage.GetHistoricalData(spanCtx, data.Symbol, data.Timestamp.Add(-window), data.Timestamp)
+	span.RecordError(err)
+	span.End()
+	if err != nil {
+		return nil, ai.MarketStats{}, false, fmt.Errorf("failed to load price history of %s: %w", data.Symbol, err)
+	}
+
+	// 当前行情已保存，从历史中去掉后放在最后
+	n := 0
+	for _, d := range history {
+		if d.Timestamp.Before(data.Timestamp) {
+			history[n] = d
+			n++
+		}
+	}
+	history = append(history[:n], data)
+	stats, ok := ai.ComputeMarketStats(history)
+	history = history[:n]
+
+	interval, err := time.ParseDuration(s.cfg().TradingConfig.CandleInterval)
+	if err != nil || interval <= 0 {
+		interval = window / maxPredictHistoryPoints
+	}
+	return append(candle.Resample(history, interval), data), stats, ok, nil
+}
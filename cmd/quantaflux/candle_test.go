@@ -0,0 +1,58 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuantSystem_CandleTrigger(t *testing.T) {
+	system, _ := newTestSystem(t, map[string]float64{"USDT": 1000})
+	config := *system.cfg()
+	config.TradingConfig.CandleInterval = "1m"
+	system.config.Store(&config)
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tick := func(offset time.Duration, price float64) models.MarketData {
+		return models.MarketData{Symbol: "BTCUSDT", Price: price, Timestamp: start.Add(offset)}
+	}
+
+	assert.False(t, system.candleTrigger(tick(0, 100)))
+	assert.False(t, system.candleTrigger(tick(30*time.Second, 101)))
+	// 下一周期的第一条行情使上一根 K 线收盘并触发策略，策略使用这条行情的价格
+	assert.True(t, system.candleTrigger(tick(time.Minute, 105)))
+	assert.False(t, system.candleTrigger(tick(90*time.Second, 106)))
+}
+
+func TestQuantSystem_PipelineOptions(t *testing.T) {
+	system, _ := newTestSystem(t, map[string]float64{"USDT": 1000})
+	config := *system.cfg()
+	config.PipelineConfig.Concurrency = 4
+	system.config.Store(&config)
+
+	opts := system.pipelineOptions()
+	assert.Equal(t, 4, opts.Concurrency)
+	assert.False(t, opts.Blocking)
+	assert.False(t, opts.Coalesce)
+
+	config.PipelineConfig.Coalesce = true
+	system.config.Store(&config)
+	assert.True(t, system.pipelineOptions().Coalesce)
+
+	// 被取代的行情仍参与 K 线聚合，按 K 线触发时也可合并
+	candleConfig := config
+	candleConfig.TradingConfig.CandleInterval = "1m"
+	system.config.Store(&candleConfig)
+	assert.True(t, system.pipelineOptions().Coalesce)
+
+	// 回测时在回放循环中按顺序逐条处理
+	config.Mode = configs.ModeBacktest
+	system.config.Store(&config)
+	opts = system.pipelineOptions()
+	assert.True(t, opts.Inline)
+	assert.False(t, opts.Coalesce)
+}
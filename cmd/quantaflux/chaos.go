@@ -0,0 +1,21 @@
+package main
+
+import (
+	"github.com/songzhibin97/quantaflux/internal/chaos"
+	"github.com/songzhibin97/quantaflux/internal/configs"
+)
+
+// buildChaos 创建故障注入器，未启用或不是模拟交易时返回空
+func buildChaos(config *configs.Config) *chaos.Injector {
+	if !config.ChaosConfig.Enabled || config.RunMode() != configs.ModePaper {
+		return nil
+	}
+	log.Warn("fault injection enabled, components will fail on purpose", "seed", config.ChaosConfig.Seed)
+	return chaos.New(config.ChaosConfig.Options())
+}
+
+// recordFault 记录注入的故障
+func (s *QuantSystem) recordFault(target chaos.Target, err error) {
+	s.chaosFaults.Inc(string(target))
+	log.Debug("fault injected", "target", target, "err", err)
+}
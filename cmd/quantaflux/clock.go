@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/clock"
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/models"
+)
+
+// buildClock 返回系统使用的时钟：回测为从 backtest_config.start 开始、按回放行情时间推进的模拟时钟，
+// 风险统计的按天重置、周期任务和模拟成交的时间戳都按回放时间计算；其余模式为系统时钟
+func buildClock(config *configs.Config) (clock.Clock, error) {
+	if config.RunMode() != configs.ModeBacktest {
+		return clock.Real(), nil
+	}
+	start, err := time.Parse(time.RFC3339, config.BacktestConfig.Start)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backtest start: %w", err)
+	}
+	return clock.NewFake(start), nil
+}
+
+// advanceClock 模拟时钟推进到行情时间，系统时钟不受影响
+func (s *QuantSystem) advanceClock(data models.MarketData) {
+	if fake, ok := s.clock.(*clock.Fake); ok {
+		fake.Set(data.Timestamp)
+	}
+}
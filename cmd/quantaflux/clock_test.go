@@ -0,0 +1,38 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/clock"
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildClock(t *testing.T) {
+	clk, err := buildClock(&configs.Config{Mode: configs.ModePaper})
+	require.NoError(t, err)
+	assert.Equal(t, clock.Real(), clk)
+
+	config := &configs.Config{Mode: configs.ModeBacktest}
+	config.BacktestConfig.Start = "2025-01-01T00:00:00Z"
+	clk, err = buildClock(config)
+	require.NoError(t, err)
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, start, clk.Now())
+
+	// 回测时钟按回放行情时间推进，不倒退
+	system, _ := newTestSystem(t, map[string]float64{"USDT": 1000})
+	system.clock = clk
+	system.advanceClock(models.MarketData{Symbol: "BTCUSDT", Timestamp: start.Add(time.Hour)})
+	assert.Equal(t, start.Add(time.Hour), clk.Now())
+	system.advanceClock(models.MarketData{Symbol: "BTCUSDT", Timestamp: start})
+	assert.Equal(t, start.Add(time.Hour), clk.Now())
+
+	config.BacktestConfig.Start = "yesterday"
+	_, err = buildClock(config)
+	assert.Error(t, err)
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	collectorData "github.com/songzhibin97/quantaflux/internal/data/collector"
+)
+
+// buildConsistencyChecker 根据配置创建数据源报价一致性检查，未配置偏离阈值或处于回测模式时返回 nil
+func buildConsistencyChecker(config *configs.Config) *collectorData.ConsistencyChecker {
+	if config.MarketDataConfig.MaxDeviation <= 0 || config.RunMode() == configs.ModeBacktest {
+		return nil
+	}
+	return collectorData.NewConsistencyChecker(config.MarketDataConfig.MaxDeviation)
+}
+
+// recordDivergence 记录数据源报价偏离共识价格
+func (s *QuantSystem) recordDivergence(d collectorData.Divergence) {
+	s.divergences.Inc(d.Symbol, d.Source)
+}
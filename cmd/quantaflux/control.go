@@ -0,0 +1,335 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/ai"
+	"github.com/songzhibin97/quantaflux/internal/api"
+	"github.com/songzhibin97/quantaflux/internal/audit"
+	"github.com/songzhibin97/quantaflux/internal/fx"
+	"github.com/songzhibin97/quantaflux/internal/models"
+	"github.com/songzhibin97/quantaflux/internal/notify"
+	"github.com/songzhibin97/quantaflux/internal/risk"
+	"github.com/songzhibin97/quantaflux/internal/scheduler"
+	"github.com/songzhibin97/quantaflux/internal/state"
+	"github.com/songzhibin97/quantaflux/internal/stream"
+	"github.com/songzhibin97/quantaflux/internal/tracing"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+)
+
+// 保留的最近预测和预警数量
+const (
+	maxRecentPredictions = 100
+	maxRecentAlerts      = 100
+)
+
+// control 运行时控制状态：暂停开关、最新价格、最近预测与预警
+type control struct {
+	paused    atomic.Bool
+	pauseGen  atomic.Uint64    // 每次暂停或恢复时递增，自动恢复只在期间没有再次暂停或恢复时生效
+	events    *api.Hub         // 仪表盘推送，为空时不推送
+	stream    *stream.Streamer // 事件流，为空时不发布
+	heartbeat atomic.Int64     // 最近一次外部心跳的时间（UnixNano），用于死人开关
+	store     state.Store      // 运行状态持久化，为空时不保存
+	audit     *audit.Log       // 审计日志，为空时不记录
+
+	notifier    notify.Notifier          // 通知渠道，为空时不通知
+	performance *risk.PerformanceTracker // 各交易对平仓表现，用于自动停用
+
+	rates *fx.Converter // 按最新价格在计价资产之间换算，用于统一估值
+
+	mu            sync.RWMutex
+	lastPrices    map[string]float64
+	lastUpdate    time.Time // 最近一次收到行情的时间
+	lastTicks     map[string]time.Time
+	predictions   []api.PredictionRecord
+	alerts        []risk.RiskAlert
+	openOrders    map[string]trading.Order
+	pendingOrders map[string]state.OrderIntent
+	pausedSymbols map[string]bool
+	disabled      map[string]time.Time // 自动停用的交易对 -> 自动恢复时间，零值表示只能手动恢复
+
+	saveMu sync.Mutex
+}
+
+func newControl(store state.Store) *control {
+	return &control{
+		store:         store,
+		lastPrices:    make(map[string]float64),
+		lastTicks:     make(map[string]time.Time),
+		openOrders:    make(map[string]trading.Order),
+		pendingOrders: make(map[string]state.OrderIntent),
+		pausedSymbols: make(map[string]bool),
+		disabled:      make(map[string]time.Time),
+		performance:   risk.NewPerformanceTracker(),
+		rates:         fx.NewConverter(),
+	}
+}
+
+// Pause implements api.System
+func (c *control) Pause() {
+	c.pauseGen.Add(1)
+	c.paused.Store(true)
+	c.persistState(context.Background())
+}
+
+// Resume implements api.System
+func (c *control) Resume() {
+	c.pauseGen.Add(1)
+	c.paused.Store(false)
+	c.persistState(context.Background())
+}
+
+// pauseFor 暂停交易，d 后自动恢复；期间再次暂停或恢复时以最后一次操作为准，d 为 0 时不自动恢复
+func (c *control) pauseFor(d time.Duration, onResume func()) {
+	c.Pause()
+	if d <= 0 {
+		return
+	}
+
+	gen := c.pauseGen.Load()
+	time.AfterFunc(d, func() {
+		if !c.pauseGen.CompareAndSwap(gen, gen+1) {
+			return
+		}
+		c.paused.Store(false)
+		c.persistState(context.Background())
+		onResume()
+	})
+}
+
+// Paused implements api.System
+func (c *control) Paused() bool {
+	return c.paused.Load()
+}
+
+// PauseSymbol implements api.System
+func (c *control) PauseSymbol(symbol string) {
+	c.mu.Lock()
+	c.pausedSymbols[symbol] = true
+	c.mu.Unlock()
+	c.persistState(context.Background())
+}
+
+// ResumeSymbol implements api.System
+// 恢复自动停用的交易对时同时清空其连续亏损和滚动盈亏，重新开始统计
+func (c *control) ResumeSymbol(symbol string) {
+	c.mu.Lock()
+	delete(c.pausedSymbols, symbol)
+	delete(c.disabled, symbol)
+	c.mu.Unlock()
+	c.performance.Reset(symbol)
+	c.persistState(context.Background())
+}
+
+// disabledUntil 返回自动停用的交易对的自动恢复时间
+func (c *control) disabledUntil(symbol string) (time.Time, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	until, ok := c.disabled[symbol]
+	return until, ok
+}
+
+// PausedSymbols implements api.System
+func (c *control) PausedSymbols() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	result := make([]string, 0, len(c.pausedSymbols))
+	for symbol := range c.pausedSymbols {
+		result = append(result, symbol)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// symbolPaused 全局暂停或该交易对暂停时返回 true
+func (c *control) symbolPaused(symbol string) bool {
+	if c.Paused() {
+		return true
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.pausedSymbols[symbol]
+}
+
+// RecentPredictions implements api.System
+func (c *control) RecentPredictions() []api.PredictionRecord {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	result := make([]api.PredictionRecord, len(c.predictions))
+	copy(result, c.predictions)
+	return result
+}
+
+// RecentAlerts implements api.System
+func (c *control) RecentAlerts() []risk.RiskAlert {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	result := make([]risk.RiskAlert, len(c.alerts))
+	copy(result, c.alerts)
+	return result
+}
+
+func (c *control) recordPrediction(prediction ai.PricePrediction, currentPrice float64) {
+	record := api.PredictionRecord{
+		Prediction:   prediction,
+		CurrentPrice: currentPrice,
+		Timestamp:    time.Now(),
+	}
+
+	c.mu.Lock()
+	c.predictions = append(c.predictions, record)
+	if len(c.predictions) > maxRecentPredictions {
+		c.predictions = c.predictions[len(c.predictions)-maxRecentPredictions:]
+	}
+	c.mu.Unlock()
+
+	c.publish(api.EventPrediction, prediction.Symbol, record)
+}
+
+func (c *control) recordAlert(alert risk.RiskAlert) {
+	c.mu.Lock()
+	c.alerts = append(c.alerts, alert)
+	if len(c.alerts) > maxRecentAlerts {
+		c.alerts = c.alerts[len(c.alerts)-maxRecentAlerts:]
+	}
+	c.mu.Unlock()
+
+	c.publish(api.EventRiskAlert, alert.Symbol, alert)
+}
+
+func (c *control) updateMarketData(data models.MarketData) {
+	c.mu.Lock()
+	c.lastPrices[data.Symbol] = data.Price
+	c.lastUpdate = time.Now()
+	c.mu.Unlock()
+	c.rates.Update(data.Symbol, data.Price)
+
+	c.publish(api.EventMarketData, data.Symbol, data)
+}
+
+// publish 将事件推送给仪表盘并发布到事件流，key 为事件流的分区键
+func (c *control) publish(eventType, key string, data interface{}) {
+	c.events.Publish(eventType, data)
+	c.stream.Publish(eventType, key, data)
+}
+
+func (c *control) lastMarketUpdate() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.lastUpdate
+}
+
+func (c *control) lastPrice(symbol string) float64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.lastPrices[symbol]
+}
+
+// Positions implements api.System
+func (s *QuantSystem) Positions(ctx context.Context) ([]api.Position, error) {
+	var positions []api.Position
+	for _, a := range s.accounts {
+		accountPositions, err := s.accountPositions(ctx, a)
+		if err != nil {
+			return nil, err
+		}
+		positions = append(positions, accountPositions...)
+	}
+	if positions == nil {
+		positions = []api.Position{}
+	}
+	return positions, nil
+}
+
+// accountPositions 返回账户交易的各交易对持仓
+func (s *QuantSystem) accountPositions(ctx context.Context, a *account) ([]api.Position, error) {
+	positions := make([]api.Position, 0, len(s.cfg().Symbols))
+	for _, symbol := range s.cfg().Symbols {
+		if !a.trades(symbol) {
+			continue
+		}
+
+		base, quote, ok := trading.SplitSymbol(symbol)
+		if !ok {
+			continue
+		}
+
+		amount, err := a.executor.GetBalance(ctx, base)
+		if err != nil {
+			// 没有持仓的资产会返回 not found，视为空仓
+			continue
+		}
+
+		price := s.lastPrice(symbol)
+		position := api.Position{
+			Account:  a.name,
+			Symbol:   symbol,
+			Asset:    base,
+			Amount:   amount,
+			Price:    price,
+			Value:    amount * price,
+			Currency: quote,
+		}
+		// 市值按估值资产计价，缺少汇率时保留交易对计价资产的市值
+		if value, err := s.value(amount, base); err == nil {
+			position.Value = value
+			position.Currency = s.cfg().ValuationConfig.Asset()
+		}
+		positions = append(positions, position)
+	}
+	return positions, nil
+}
+
+// Flatten implements api.System
+func (s *QuantSystem) Flatten(ctx context.Context) error {
+	var errs []error
+	for _, a := range s.accounts {
+		for _, symbol := range s.cfg().Symbols {
+			if !a.trades(symbol) {
+				continue
+			}
+			if err := s.emergencyClose(ctx, a, symbol); err != nil {
+				errs = append(errs, fmt.Errorf("%s/%s: %w", a.name, symbol, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Jobs implements api.System
+func (s *QuantSystem) Jobs() []scheduler.JobStatus {
+	if s.scheduler == nil {
+		return []scheduler.JobStatus{}
+	}
+	return s.scheduler.Status()
+}
+
+// Traces implements api.System
+func (s *QuantSystem) Traces() []tracing.Trace {
+	if s.traces == nil {
+		return []tracing.Trace{}
+	}
+	return s.traces.Traces()
+}
+
+// positionAmount 返回账户在交易对基础资产上的持仓数量
+func (s *QuantSystem) positionAmount(ctx context.Context, a *account, symbol string) (float64, error) {
+	base, _, ok := trading.SplitSymbol(symbol)
+	if !ok {
+		return 0, fmt.Errorf("unable to determine base asset for symbol: %s", symbol)
+	}
+	return a.executor.GetBalance(ctx, base)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/audit"
+	"github.com/songzhibin97/quantaflux/internal/notify"
+)
+
+// 死人开关检查间隔的上限
+const maxDeadmanCheckInterval = 30 * time.Second
+
+// Heartbeat implements api.System
+func (c *control) Heartbeat() time.Time {
+	now := time.Now()
+	c.heartbeat.Store(now.UnixNano())
+	return now
+}
+
+// lastHeartbeat 返回最近一次心跳时间：API 心跳和心跳文件修改时间中较晚的一个
+func (s *QuantSystem) lastHeartbeat() time.Time {
+	last := time.Unix(0, s.heartbeat.Load())
+	if file := s.cfg().DeadmanConfig.File; file != "" {
+		if info, err := os.Stat(file); err == nil && info.ModTime().After(last) {
+			last = info.ModTime()
+		}
+	}
+	return last
+}
+
+// runDeadman 配置了 deadman_config.timeout 时定期检查心跳，超时后暂停下单，从启动时开始计时
+func (s *QuantSystem) runDeadman(ctx context.Context) {
+	timeout, err := time.ParseDuration(s.cfg().DeadmanConfig.Timeout)
+	if err != nil || timeout <= 0 {
+		return
+	}
+	s.Heartbeat()
+
+	interval := min(timeout/10, maxDeadmanCheckInterval)
+	ticker := time.NewTicker(max(interval, time.Second))
+	defer ticker.Stop()
+
+	tripped := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			tripped = s.checkDeadman(ctx, now, tripped)
+		}
+	}
+}
+
+// checkDeadman 检查心跳是否超时，返回开关是否处于触发状态。触发后只执行一次暂停（和清仓），
+// 收到新的心跳后重新生效，但不会自动恢复下单，需人工确认后恢复
+func (s *QuantSystem) checkDeadman(ctx context.Context, now time.Time, tripped bool) bool {
+	config := s.cfg().DeadmanConfig
+	timeout, err := time.ParseDuration(config.Timeout)
+	if err != nil || timeout <= 0 {
+		return false
+	}
+
+	last := s.lastHeartbeat()
+	silence := now.Sub(last)
+	if silence < timeout {
+		if tripped {
+			log.Info("heartbeat received, dead man's switch re-armed, trading stays paused until resumed", "last_heartbeat", last)
+		}
+		return false
+	}
+	if tripped {
+		return true
+	}
+
+	reason := fmt.Sprintf("no heartbeat for %s", silence.Round(time.Second))
+	log.Warn("dead man's switch tripped, pausing trading", "last_heartbeat", last, "timeout", timeout, "flatten", config.Flatten)
+	s.Pause()
+	s.audit.Record(ctx, audit.ActionPause, "", "dead man's switch: "+reason, map[string]any{"last_heartbeat": last, "timeout": timeout.String()})
+
+	text := fmt.Sprintf("No operator heartbeat since %s (timeout %s). New orders are paused until trading is resumed manually.", last.Format(time.RFC3339), timeout)
+	if config.Flatten {
+		err := s.Flatten(ctx)
+		details := map[string]any{}
+		if err != nil {
+			details["error"] = err.Error()
+			log.Error("Error flattening positions on dead man's switch", "err", err)
+			text += fmt.Sprintf(" Flattening positions failed: %v", err)
+		} else {
+			text += " All positions were flattened."
+		}
+		s.audit.Record(ctx, audit.ActionFlatten, "", "dead man's switch: "+reason, details)
+	}
+
+	s.notify(ctx, notify.Message{
+		Title: "Dead man's switch tripped",
+		Text:  text,
+		Level: notify.LevelCritical,
+	})
+	return true
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/models"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuantSystem_DeadmanSwitch(t *testing.T) {
+	ctx := context.Background()
+	system, _ := newTestSystem(t, map[string]float64{"USDT": 1000})
+	config := *system.cfg()
+	config.DeadmanConfig.Timeout = "10m"
+	config.DeadmanConfig.Flatten = true
+	system.config.Store(&config)
+
+	a := system.primaryAccount()
+	tick := models.MarketData{Symbol: "BTCUSDT", Price: 100, Timestamp: time.Now()}
+	system.updateMarketData(tick)
+	a.executor.(trading.MarketPriceUpdater).UpdateMarketPrice(tick.Symbol, tick.Price)
+	require.NoError(t, a.executor.PlaceOrder(ctx, &trading.Order{Account: a.name, Symbol: "BTCUSDT", Side: "buy", Amount: 1, OrderType: "market"}))
+
+	start := system.Heartbeat()
+	assert.False(t, system.checkDeadman(ctx, start.Add(5*time.Minute), false))
+	assert.False(t, system.Paused())
+
+	// 超时后暂停下单并清仓
+	assert.True(t, system.checkDeadman(ctx, start.Add(11*time.Minute), false))
+	assert.True(t, system.Paused())
+	btc, err := accountBalance(ctx, a, "BTC")
+	require.NoError(t, err)
+	assert.Zero(t, btc)
+
+	// 恢复心跳后重新生效，但仍保持暂停
+	now := system.Heartbeat()
+	assert.False(t, system.checkDeadman(ctx, now.Add(time.Minute), true))
+	assert.True(t, system.Paused())
+}
+
+func TestQuantSystem_DeadmanHeartbeatFile(t *testing.T) {
+	system, _ := newTestSystem(t, map[string]float64{"USDT": 1000})
+	file := filepath.Join(t.TempDir(), "heartbeat")
+	require.NoError(t, os.WriteFile(file, nil, 0o644))
+
+	config := *system.cfg()
+	config.DeadmanConfig = configs.DeadmanConfig{Timeout: "10m", File: file}
+	system.config.Store(&config)
+
+	touched := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(file, touched, touched))
+	assert.Equal(t, touched.Unix(), system.lastHeartbeat().Unix())
+	assert.False(t, system.checkDeadman(context.Background(), touched.Add(time.Minute), false))
+	assert.True(t, system.checkDeadman(context.Background(), touched.Add(11*time.Minute), false))
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/songzhibin97/quantaflux/internal/audit"
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/discovery"
+	"github.com/songzhibin97/quantaflux/internal/notify"
+)
+
+// discoverTokensJob 返回扫描市场并自动加入通过评估的交易对的任务，扫描器记录上次扫描结果
+func (a *app) discoverTokensJob(source discovery.TickerSource) func(ctx context.Context) error {
+	filters := a.config.DiscoveryConfig
+	scanner := discovery.NewScanner(source, discovery.Filters{
+		QuoteAsset:       filters.QuoteAsset,
+		MinQuoteVolume:   filters.MinQuoteVolume,
+		TopGainers:       filters.TopGainers,
+		MinPriceChange:   filters.MinPriceChange,
+		VolumeSpikeRatio: filters.VolumeSpikeRatio,
+		NewListings:      filters.NewListings,
+	})
+
+	return func(ctx context.Context) error {
+		return a.system.discoverTokens(ctx, scanner)
+	}
+}
+
+// discoverTokens 扫描候选交易对，执行项目分析和诈骗检测，通过的交易对加入交易列表
+func (s *QuantSystem) discoverTokens(ctx context.Context, scanner *discovery.Scanner) error {
+	config := s.cfg()
+	candidates, err := scanner.Scan(ctx, config.Symbols)
+	if err != nil {
+		return err
+	}
+	candidates = slices.DeleteFunc(candidates, func(candidate discovery.Candidate) bool {
+		tag, excluded := s.excludedTag(candidate.Symbol, config.DiscoveryConfig.ExcludeTags)
+		if excluded {
+			log.Info("discovery candidate excluded by tag", "symbol", candidate.Symbol, "tag", tag)
+		}
+		return excluded
+	})
+	if limit := config.DiscoveryConfig.MaxCandidates; limit > 0 && len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	evaluator := discovery.NewEvaluator(s.dataCollector, s.aiAnalyzer, config.AIConfig.ScamThreshold)
+	results, evalErr := evaluator.Evaluate(ctx, candidates)
+
+	var passed []discovery.Result
+	for _, result := range results {
+		if !result.Promoted {
+			log.Info("discovery candidate rejected", "symbol", result.Symbol, "reasons", result.Reasons, "reason", result.Reason)
+			continue
+		}
+		passed = append(passed, result)
+	}
+
+	return errors.Join(evalErr, s.promoteSymbols(ctx, passed))
+}
+
+// promoteSymbols 将通过评估的交易对加入交易列表并通知主循环重新订阅，超出 max_symbols 的交易对不加入
+func (s *QuantSystem) promoteSymbols(ctx context.Context, results []discovery.Result) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+
+	current := s.cfg()
+	next := *current
+	next.Symbols = slices.Clone(current.Symbols)
+
+	var promoted []discovery.Result
+	for _, result := range results {
+		if slices.Contains(next.Symbols, result.Symbol) {
+			continue
+		}
+		if limit := current.DiscoveryConfig.MaxSymbols; limit > 0 && len(next.Symbols) >= limit {
+			log.Warn("discovery reached max symbols", "max_symbols", limit, "skipped", result.Symbol)
+			break
+		}
+		next.Symbols = append(next.Symbols, result.Symbol)
+		promoted = append(promoted, result)
+	}
+	if len(promoted) == 0 {
+		return nil
+	}
+
+	s.config.Store(&next)
+	for _, result := range promoted {
+		s.promoted = append(s.promoted, result.Symbol)
+
+		log.Info("symbol promoted by discovery", "audit", true, "symbol", result.Symbol, "reasons", result.Reasons)
+		s.audit.Record(ctx, audit.ActionPromoteSymbol, result.Symbol, "token discovery", map[string]any{
+			"reasons":          result.Reasons,
+			"quote_volume":     result.QuoteVolume,
+			"price_change":     result.PriceChange,
+			"scam_probability": result.Scam.ScamProbability,
+		})
+		s.notify(ctx, notify.Message{
+			Title: fmt.Sprintf("%s added by discovery", result.Symbol),
+			Text:  fmt.Sprintf("%s passed project analysis and scam detection (scam probability %.2f) and is now traded.", result.Symbol, result.Scam.ScamProbability),
+			Level: notify.LevelInfo,
+		})
+	}
+
+	select {
+	case s.reloadCh <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// keepPromotedSymbols 热加载时保留代币发现加入的交易对，配置文件中已有的不重复加入
+func keepPromotedSymbols(loaded *configs.Config, promoted []string) *configs.Config {
+	merged := *loaded
+	merged.Symbols = slices.Clone(loaded.Symbols)
+	for _, symbol := range promoted {
+		if !slices.Contains(merged.Symbols, symbol) {
+			merged.Symbols = append(merged.Symbols, symbol)
+		}
+	}
+	return &merged
+}
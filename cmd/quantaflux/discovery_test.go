@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/songzhibin97/quantaflux/internal/ai"
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/discovery"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuantSystem_PromoteSymbols(t *testing.T) {
+	system, _ := newTestSystem(t, map[string]float64{"USDT": 1000})
+	config := *system.cfg()
+	config.DiscoveryConfig.MaxSymbols = 2
+	system.config.Store(&config)
+
+	passed := func(symbol string) discovery.Result {
+		return discovery.Result{
+			Candidate: discovery.Candidate{Symbol: symbol, Reasons: []string{discovery.ReasonNewListing}},
+			Scam:      &ai.ScamAnalysis{ScamProbability: 0.1},
+			Promoted:  true,
+		}
+	}
+	assert.NoError(t, system.promoteSymbols(context.Background(), []discovery.Result{passed("BTCUSDT"), passed("NEWUSDT"), passed("MOREUSDT")}))
+
+	// 已在交易的不重复加入，超出 max_symbols 的不加入
+	assert.Equal(t, []string{"BTCUSDT", "NEWUSDT"}, system.cfg().Symbols)
+	assert.Equal(t, []string{"NEWUSDT"}, system.promoted)
+	assert.Len(t, system.reloadCh, 1)
+
+	// 热加载配置文件时保留发现的交易对
+	loaded := keepPromotedSymbols(&configs.Config{Symbols: []string{"ETHUSDT"}}, system.promoted)
+	assert.Equal(t, []string{"ETHUSDT", "NEWUSDT"}, loaded.Symbols)
+}
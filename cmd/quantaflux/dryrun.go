@@ -0,0 +1,442 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/ai"
+	"github.com/songzhibin97/quantaflux/internal/analytics"
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/journal"
+	"github.com/songzhibin97/quantaflux/internal/models"
+	"github.com/songzhibin97/quantaflux/internal/money"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+)
+
+// dryRunJournal 在内存中记录试运行的订单，不写入存储
+type dryRunJournal struct {
+	mu      sync.Mutex
+	entries []journal.Entry
+}
+
+func (j *dryRunJournal) RecordTrade(ctx context.Context, entry *journal.Entry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if entry.CreatedAt.IsZero() {
+		// 回放时按行情时间记录，统计范围与回放范围一致
+		entry.CreatedAt = entry.Order.CreatedAt
+		if entry.CreatedAt.IsZero() {
+			entry.CreatedAt = entry.MarketData.Timestamp
+		}
+	}
+	entry.ID = int64(len(j.entries) + 1)
+	j.entries = append(j.entries, *entry)
+	return nil
+}
+
+func (j *dryRunJournal) ListTrades(ctx context.Context, symbol string, limit int) ([]journal.Entry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var result []journal.Entry
+	for i := len(j.entries) - 1; i >= 0 && (limit <= 0 || len(result) < limit); i-- {
+		if symbol == "" || j.entries[i].Order.Symbol == symbol {
+			result = append(result, j.entries[i])
+		}
+	}
+	return result, nil
+}
+
+func (j *dryRunJournal) ListTradesInRange(ctx context.Context, start, end time.Time) ([]journal.Entry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var result []journal.Entry
+	for _, entry := range j.entries {
+		if !entry.CreatedAt.Before(start) && !entry.CreatedAt.After(end) {
+			result = append(result, entry)
+		}
+	}
+	sort.SliceStable(result, func(a, b int) bool { return result[a].CreatedAt.Before(result[b].CreatedAt) })
+	return result, nil
+}
+
+func (j *dryRunJournal) GetTrade(ctx context.Context, account, symbol, orderID string) (*journal.Entry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for i := range j.entries {
+		if j.matches(i, account, symbol, orderID) {
+			entry := j.entries[i]
+			return &entry, nil
+		}
+	}
+	return nil, trading.ErrOrderNotFound
+}
+
+func (j *dryRunJournal) ListOpenTrades(ctx context.Context) ([]journal.Entry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var result []journal.Entry
+	for _, entry := range j.entries {
+		if trading.IsOpenStatus(entry.Order.Status) {
+			result = append(result, entry)
+		}
+	}
+	return result, nil
+}
+
+func (j *dryRunJournal) UpdateOrderStatus(ctx context.Context, account, symbol, orderID, status string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for i := range j.entries {
+		if j.matches(i, account, symbol, orderID) {
+			j.entries[i].Order.Status = status
+		}
+	}
+	return nil
+}
+
+func (j *dryRunJournal) UpdateOrderFill(ctx context.Context, order trading.Order) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for i := range j.entries {
+		if j.matches(i, order.Account, order.Symbol, order.OrderID) {
+			recorded := &j.entries[i].Order
+			recorded.FilledAmount, recorded.FilledPrice = order.FilledAmount, order.FilledPrice
+			recorded.Fee, recorded.FeeAsset, recorded.UpdatedAt = order.Fee, order.FeeAsset, order.UpdatedAt
+		}
+	}
+	return nil
+}
+
+// matches 判断第 i 条记录是否为指定订单，account 或 symbol 为空时匹配任意值，调用方需持有锁
+func (j *dryRunJournal) matches(i int, account, symbol, orderID string) bool {
+	order := j.entries[i].Order
+	return order.OrderID == orderID && (account == "" || order.Account == account) && (symbol == "" || order.Symbol == symbol)
+}
+
+// aiReplayCache 在两次试运行之间共享 AI 分析结果，相同方法和输入只调用一次，
+// 对比结果不受 AI 输出随机性的影响
+type aiReplayCache struct {
+	mu      sync.Mutex
+	results map[string]interface{}
+}
+
+func newAIReplayCache() *aiReplayCache {
+	return &aiReplayCache{results: make(map[string]interface{})}
+}
+
+// call 返回缓存的结果，未缓存时调用 fn 并缓存成功的结果；输入无法序列化时不缓存
+func (c *aiReplayCache) call(method string, input interface{}, fn func() (interface{}, error)) (interface{}, error) {
+	encoded, err := json.Marshal(input)
+	if err != nil {
+		return fn()
+	}
+	key := method + ":" + string(encoded)
+
+	c.mu.Lock()
+	result, ok := c.results[key]
+	c.mu.Unlock()
+	if ok {
+		return result, nil
+	}
+
+	result, err = fn()
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.results[key] = result
+	c.mu.Unlock()
+	return result, nil
+}
+
+// cachedAnalyzer 通过 aiReplayCache 调用分析器
+type cachedAnalyzer struct {
+	analyzer ai.Analyzer
+	cache    *aiReplayCache
+}
+
+func (c *cachedAnalyzer) AnalyzeProject(ctx context.Context, info *models.TokenInfo) (*models.ProjectMetrics, error) {
+	result, err := c.cache.call(ai.MethodAnalyzeProject, info, func() (interface{}, error) {
+		return c.analyzer.AnalyzeProject(ctx, info)
+	})
+	if err != nil {
+		return nil, err
+	}
+	metrics := *result.(*models.ProjectMetrics)
+	return &metrics, nil
+}
+
+func (c *cachedAnalyzer) PredictPrice(ctx context.Context, data []models.MarketData) (*ai.PricePrediction, error) {
+	result, err := c.cache.call(ai.MethodPredictPrice, data, func() (interface{}, error) {
+		return c.analyzer.PredictPrice(ctx, data)
+	})
+	if err != nil {
+		return nil, err
+	}
+	prediction := *result.(*ai.PricePrediction)
+	return &prediction, nil
+}
+
+func (c *cachedAnalyzer) AnalyzeSentiment(ctx context.Context, socialData map[string]string) (float64, error) {
+	result, err := c.cache.call(ai.MethodAnalyzeSentiment, socialData, func() (interface{}, error) {
+		return c.analyzer.AnalyzeSentiment(ctx, socialData)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.(float64), nil
+}
+
+func (c *cachedAnalyzer) DetectScam(ctx context.Context, projectData *models.ProjectMetrics) (*ai.ScamAnalysis, error) {
+	result, err := c.cache.call(ai.MethodDetectScam, projectData, func() (interface{}, error) {
+		return c.analyzer.DetectScam(ctx, projectData)
+	})
+	if err != nil {
+		return nil, err
+	}
+	analysis := *result.(*ai.ScamAnalysis)
+	return &analysis, nil
+}
+
+// dryRunOrder 试运行中的一笔假设订单
+type dryRunOrder struct {
+	Account  string    `json:"account"`
+	Symbol   string    `json:"symbol"`
+	Side     string    `json:"side"`
+	Amount   float64   `json:"amount"`
+	Price    float64   `json:"price"`     // 成交均价，未成交时为委托价格
+	Filled   float64   `json:"filled"`    // 成交数量
+	TickTime time.Time `json:"tick_time"` // 触发下单的行情时间，风控平仓等没有行情的订单为下单时间
+	Strategy string    `json:"strategy"`
+}
+
+// dryRunResult 一份配置的试运行结果
+type dryRunResult struct {
+	Orders []dryRunOrder          `json:"orders"`
+	PnL    []analytics.AccountPnL `json:"pnl"`
+}
+
+// dryRunSummary 试运行结果的汇总
+type dryRunSummary struct {
+	Orders   int                    `json:"orders"`
+	Filled   int                    `json:"filled"` // 有成交的订单数量
+	PnL      float64                `json:"pnl"`
+	Fees     float64                `json:"fees"`
+	Accounts []analytics.AccountPnL `json:"accounts"`
+}
+
+// orderChange 两份配置在同一条行情上都下单，但方向或数量不同
+type orderChange struct {
+	Current  dryRunOrder `json:"current"`
+	Proposed dryRunOrder `json:"proposed"`
+}
+
+// configDiffReport 当前配置和新配置在同一段历史行情上的假设订单和盈亏差异
+type configDiffReport struct {
+	Start        time.Time     `json:"start"`
+	End          time.Time     `json:"end"`
+	Changes      []string      `json:"changes"` // 新配置相对当前配置修改的配置项
+	Current      dryRunSummary `json:"current"`
+	Proposed     dryRunSummary `json:"proposed"`
+	PnLDelta     float64       `json:"pnl_delta"`     // 新配置的盈亏减去当前配置的盈亏
+	OnlyCurrent  []dryRunOrder `json:"only_current"`  // 只有当前配置会下的订单
+	OnlyProposed []dryRunOrder `json:"only_proposed"` // 只有新配置会下的订单
+	Changed      []orderChange `json:"changed"`
+}
+
+// summarize 汇总试运行的订单数量和各账户盈亏
+func (r *dryRunResult) summarize() dryRunSummary {
+	summary := dryRunSummary{Orders: len(r.Orders), Accounts: r.PnL}
+	for _, order := range r.Orders {
+		if order.Filled > 0 {
+			summary.Filled++
+		}
+	}
+	for _, pnl := range r.PnL {
+		summary.PnL = money.Add(summary.PnL, pnl.PnL)
+		summary.Fees = money.Add(summary.Fees, pnl.Fees)
+	}
+	return summary
+}
+
+// dryRunKey 同一账户、交易对在同一条行情上的订单互相对比
+type dryRunKey struct {
+	account  string
+	symbol   string
+	tickTime time.Time
+}
+
+// diffDryRuns 对比两次试运行的订单：按账户、交易对和触发行情配对，同一条行情的多笔订单按下单顺序配对
+func diffDryRuns(current, proposed *dryRunResult) configDiffReport {
+	report := configDiffReport{Current: current.summarize(), Proposed: proposed.summarize()}
+	report.PnLDelta = money.Sub(report.Proposed.PnL, report.Current.PnL)
+
+	key := func(order dryRunOrder) dryRunKey {
+		return dryRunKey{account: order.Account, symbol: order.Symbol, tickTime: order.TickTime.UTC()}
+	}
+	pending := make(map[dryRunKey][]dryRunOrder)
+	for _, order := range proposed.Orders {
+		pending[key(order)] = append(pending[key(order)], order)
+	}
+
+	for _, order := range current.Orders {
+		k := key(order)
+		candidates := pending[k]
+		if len(candidates) == 0 {
+			report.OnlyCurrent = append(report.OnlyCurrent, order)
+			continue
+		}
+		match := candidates[0]
+		pending[k] = candidates[1:]
+		if match.Side != order.Side || money.Sub(match.Amount, order.Amount) != 0 {
+			report.Changed = append(report.Changed, orderChange{Current: order, Proposed: match})
+		}
+	}
+	for _, order := range proposed.Orders {
+		if candidates := pending[key(order)]; len(candidates) > 0 {
+			report.OnlyProposed = append(report.OnlyProposed, candidates[0])
+			pending[key(order)] = candidates[1:]
+		}
+	}
+	return report
+}
+
+// dryRun 以回测模式用 config 回放 [start, end] 内存储的行情，返回假设订单和按最后价格计算的盈亏；
+// 订单只记录在内存中，cache 不为空时 AI 分析结果在多次试运行之间共享
+func dryRun(config *configs.Config, start, end time.Time, cache *aiReplayCache) (*dryRunResult, error) {
+	replay := *config
+	replay.Mode = configs.ModeBacktest
+	replay.BacktestConfig.Start = start.Format(time.RFC3339)
+	replay.BacktestConfig.End = end.Format(time.RFC3339)
+	// 试运行不需要对外提供 API
+	replay.APIConfig.Addr = ""
+	replay.APIConfig.GRPCAddr = ""
+	if err := replay.Validate(); err != nil {
+		return nil, err
+	}
+
+	a, err := bootstrap(&replay)
+	if err != nil {
+		return nil, err
+	}
+	trades := &dryRunJournal{}
+	a.system.tradeJournal = trades
+	if cache != nil {
+		a.system.aiAnalyzer = &cachedAnalyzer{analyzer: a.system.aiAnalyzer, cache: cache}
+	}
+
+	if err := runSystem(a, ""); err != nil {
+		return nil, err
+	}
+
+	entries, err := trades.ListTradesInRange(context.Background(), time.Time{}, end)
+	if err != nil {
+		return nil, err
+	}
+	result := &dryRunResult{Orders: make([]dryRunOrder, 0, len(entries))}
+	prices := make(map[string]float64)
+	for _, entry := range entries {
+		order := entry.Order
+		price := order.ExecutedPrice()
+		if price == 0 {
+			price = order.Price
+		}
+		tickTime := entry.MarketData.Timestamp
+		if tickTime.IsZero() {
+			tickTime = entry.CreatedAt
+		}
+		result.Orders = append(result.Orders, dryRunOrder{
+			Account:  order.Account,
+			Symbol:   order.Symbol,
+			Side:     order.Side,
+			Amount:   order.Amount,
+			Price:    price,
+			Filled:   order.ExecutedAmount(),
+			TickTime: tickTime,
+			Strategy: entry.Strategy,
+		})
+		prices[order.Symbol] = a.system.lastPrice(order.Symbol)
+	}
+
+	service := analytics.NewService(nil, trades, replay.TradingConfig.FeeRate, configs.ModeBacktest)
+	result.PnL, err = service.AccountPnL(context.Background(), time.Time{}, end, prices)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// configDiff 分别用当前配置和新配置回放最近的历史行情并对比假设订单和盈亏；
+// 两份配置的 AI 配置相同时共享 AI 分析结果，差异只来自配置本身
+func configDiff(current, proposed *configs.Config, start, end time.Time) (*configDiffReport, error) {
+	var cache *aiReplayCache
+	if reflect.DeepEqual(current.AIConfig, proposed.AIConfig) {
+		cache = newAIReplayCache()
+	}
+
+	before, err := dryRun(current, start, end, cache)
+	if err != nil {
+		return nil, fmt.Errorf("dry run with current config: %w", err)
+	}
+	after, err := dryRun(proposed, start, end, cache)
+	if err != nil {
+		return nil, fmt.Errorf("dry run with proposed config: %w", err)
+	}
+
+	report := diffDryRuns(before, after)
+	report.Start, report.End = start, end
+	report.Changes = configs.Diff(current, proposed)
+	return &report, nil
+}
+
+// cmdConfigDiff 在应用新配置之前，用当前配置和新配置分别回放最近 -window 的历史行情，输出假设订单和盈亏的差异
+func cmdConfigDiff(args []string) error {
+	fs, conf := newFlagSet("config-diff")
+	proposedPath := fs.String("new", "", "proposed config path")
+	window := fs.Duration("window", 24*time.Hour, "replay the stored market data of this window before -end")
+	endFlag := fs.String("end", "", "replay end time (RFC3339), default now")
+	_ = fs.Parse(args)
+
+	if *proposedPath == "" {
+		return fmt.Errorf("-new is required")
+	}
+	if *window <= 0 {
+		return fmt.Errorf("-window must be positive")
+	}
+	end := time.Now()
+	if *endFlag != "" {
+		parsed, err := time.Parse(time.RFC3339, *endFlag)
+		if err != nil {
+			return fmt.Errorf("invalid -end: %w", err)
+		}
+		end = parsed
+	}
+
+	current, err := configs.Load(*conf)
+	if err != nil {
+		return fmt.Errorf("current config: %w", err)
+	}
+	proposed, err := configs.Load(*proposedPath)
+	if err != nil {
+		return fmt.Errorf("proposed config: %w", err)
+	}
+
+	quietLogs()
+	report, err := configDiff(current, proposed, end.Add(-*window), end)
+	if err != nil {
+		return err
+	}
+	return printJSON(report)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/ai"
+	"github.com/songzhibin97/quantaflux/internal/analytics"
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/journal"
+	"github.com/songzhibin97/quantaflux/internal/models"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffDryRuns(t *testing.T) {
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	order := func(minute int, side string, amount float64) dryRunOrder {
+		return dryRunOrder{Account: "main", Symbol: "BTCUSDT", Side: side, Amount: amount, Filled: amount, TickTime: start.Add(time.Duration(minute) * time.Minute)}
+	}
+
+	current := &dryRunResult{
+		Orders: []dryRunOrder{order(1, "buy", 1), order(2, "sell", 1), order(3, "buy", 2)},
+		PnL:    []analytics.AccountPnL{{Account: "main", PnL: 10, Fees: 1}},
+	}
+	proposed := &dryRunResult{
+		Orders: []dryRunOrder{order(1, "buy", 1), order(3, "buy", 1), order(4, "sell", 1)},
+		PnL:    []analytics.AccountPnL{{Account: "main", PnL: 12.5, Fees: 0.5}},
+	}
+
+	report := diffDryRuns(current, proposed)
+	assert.Equal(t, 3, report.Current.Orders)
+	assert.Equal(t, 3, report.Proposed.Filled)
+	assert.Equal(t, 2.5, report.PnLDelta)
+	assert.Equal(t, []dryRunOrder{order(2, "sell", 1)}, report.OnlyCurrent)
+	assert.Equal(t, []dryRunOrder{order(4, "sell", 1)}, report.OnlyProposed)
+	assert.Equal(t, []orderChange{{Current: order(3, "buy", 2), Proposed: order(3, "buy", 1)}}, report.Changed)
+}
+
+func TestDryRunJournal_PnL(t *testing.T) {
+	ctx := context.Background()
+	trades := &dryRunJournal{}
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	buy := trading.Order{Account: "main", Symbol: "BTCUSDT", Side: "buy", Amount: 1, OrderID: "1", Status: "NEW"}
+	require.NoError(t, trades.RecordTrade(ctx, &journal.Entry{Mode: configs.ModeBacktest, Order: buy, MarketData: models.MarketData{Timestamp: start}}))
+	buy.Status, buy.FilledAmount, buy.FilledPrice = "FILLED", 1, 100
+	require.NoError(t, trades.UpdateOrderFill(ctx, buy))
+	require.NoError(t, trades.UpdateOrderStatus(ctx, "main", "BTCUSDT", "1", "FILLED"))
+
+	open, err := trades.ListOpenTrades(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, open)
+
+	service := analytics.NewService(nil, trades, 0, configs.ModeBacktest)
+	pnl, err := service.AccountPnL(ctx, time.Time{}, start.Add(time.Hour), map[string]float64{"BTCUSDT": 110})
+	require.NoError(t, err)
+	require.Len(t, pnl, 1)
+	assert.Equal(t, 10.0, pnl[0].PnL)
+}
+
+func TestCachedAnalyzer(t *testing.T) {
+	ctx := context.Background()
+	cache := newAIReplayCache()
+	detector := &countingScamDetector{probability: 0.3}
+	first := &cachedAnalyzer{analyzer: detector, cache: cache}
+	second := &cachedAnalyzer{analyzer: &countingScamDetector{probability: 0.9}, cache: cache}
+
+	metrics := &models.ProjectMetrics{TokenInfo: models.TokenInfo{Symbol: "BTCUSDT"}}
+	analysis, err := first.DetectScam(ctx, metrics)
+	require.NoError(t, err)
+	assert.Equal(t, 0.3, analysis.ScamProbability)
+
+	// 另一次试运行的相同输入沿用第一次的结果，结果互不影响
+	analysis.ScamProbability = 1
+	analysis, err = second.DetectScam(ctx, metrics)
+	require.NoError(t, err)
+	assert.Equal(t, 0.3, analysis.ScamProbability)
+	assert.Equal(t, 1, detector.calls)
+
+	// 输入不同时重新调用
+	_, err = first.DetectScam(ctx, &models.ProjectMetrics{TokenInfo: models.TokenInfo{Symbol: "ETHUSDT"}})
+	require.NoError(t, err)
+	assert.Equal(t, 2, detector.calls)
+
+	var _ ai.Analyzer = first
+}
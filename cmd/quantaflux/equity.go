@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/audit"
+	"github.com/songzhibin97/quantaflux/internal/models"
+	"github.com/songzhibin97/quantaflux/internal/notify"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+)
+
+// equitySnapshots 返回所有账户的汇总权益和各账户的权益：计价资产余额计为现金，基础资产按最新价格计为持仓市值，
+// 均换算为估值资产计价；缺少汇率时返回错误，避免少计权益触发回撤熔断
+func (s *QuantSystem) equitySnapshots(ctx context.Context) (*models.EquitySnapshot, []*models.EquitySnapshot, error) {
+	now := time.Now()
+	total := &models.EquitySnapshot{
+		Mode:      s.cfg().RunMode(),
+		Timestamp: now,
+	}
+
+	accounts := make([]*models.EquitySnapshot, 0, len(s.accounts))
+	for _, a := range s.accounts {
+		snapshot := &models.EquitySnapshot{
+			Mode:      total.Mode,
+			Account:   a.name,
+			Timestamp: now,
+		}
+		counted := make(map[string]bool)
+		for _, symbol := range s.cfg().Symbols {
+			if !a.trades(symbol) {
+				continue
+			}
+			base, quote, ok := trading.SplitSymbol(symbol)
+			if !ok {
+				continue
+			}
+
+			if !counted[quote] {
+				counted[quote] = true
+				cash, err := accountBalance(ctx, a, quote)
+				if err != nil {
+					return nil, nil, err
+				}
+				if cash, err = s.value(cash, quote); err != nil {
+					return nil, nil, err
+				}
+				snapshot.Cash += cash
+			}
+
+			if !counted[base] {
+				counted[base] = true
+				amount, err := accountBalance(ctx, a, base)
+				if err != nil {
+					return nil, nil, err
+				}
+				exposure, err := s.value(amount, base)
+				if err != nil {
+					return nil, nil, err
+				}
+				snapshot.Exposure += exposure
+			}
+		}
+		snapshot.Equity = snapshot.Cash + snapshot.Exposure
+		accounts = append(accounts, snapshot)
+
+		total.Cash += snapshot.Cash
+		total.Exposure += snapshot.Exposure
+	}
+
+	total.Equity = total.Cash + total.Exposure
+	return total, accounts, nil
+}
+
+// accountBalance 返回账户的资产余额，没有该资产时视为 0
+func accountBalance(ctx context.Context, a *account, asset string) (float64, error) {
+	amount, err := a.executor.GetBalance(ctx, asset)
+	if errors.Is(err, trading.ErrBalanceNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get %s balance of account %s: %w", asset, a.name, err)
+	}
+	return amount, nil
+}
+
+// snapshotEquity 保存当前权益快照，供绩效报告、权益曲线和回撤熔断使用；未配置存储时不保存
+func (s *QuantSystem) snapshotEquity(ctx context.Context) error {
+	if s.equity == nil {
+		return nil
+	}
+
+	if err := s.refreshRates(ctx); err != nil {
+		log.Warn("Error refreshing conversion rates", "err", err)
+	}
+
+	snapshot, accounts, err := s.equitySnapshots(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.equity.SaveEquitySnapshot(ctx, snapshot); err != nil {
+		return err
+	}
+	// 多账户时各账户的快照单独保存，按账户统计绩效；单账户时与汇总快照相同，不重复保存
+	for _, a := range accounts {
+		s.accountEquity.Set(a.Equity, a.Account)
+		if len(accounts) == 1 {
+			continue
+		}
+		if err := s.equity.SaveEquitySnapshot(ctx, a); err != nil {
+			return err
+		}
+	}
+	return s.checkDrawdown(ctx, snapshot)
+}
+
+// checkDrawdown 计算权益相对统计窗口内峰值的回撤，超过 max_drawdown 时暂停下单，已暂停时不重复触发
+func (s *QuantSystem) checkDrawdown(ctx context.Context, snapshot *models.EquitySnapshot) error {
+	config := s.cfg().DrawdownConfig
+	history, err := s.equity.GetEquitySnapshots(ctx, snapshot.Timestamp.Add(-config.PeakWindow()), snapshot.Timestamp)
+	if err != nil {
+		return fmt.Errorf("failed to load equity snapshots: %w", err)
+	}
+
+	peak := snapshot.Equity
+	for _, h := range history {
+		if h.Mode == snapshot.Mode && h.Account == snapshot.Account && h.Equity > peak {
+			peak = h.Equity
+		}
+	}
+	var drawdown float64
+	if peak > 0 {
+		drawdown = (peak - snapshot.Equity) / peak
+	}
+	s.drawdown.Set(drawdown)
+	s.observeDrawdownRegime(ctx, drawdown, snapshot.Timestamp)
+
+	if config.MaxDrawdown <= 0 || drawdown <= config.MaxDrawdown || s.Paused() {
+		return nil
+	}
+
+	reason := fmt.Sprintf("equity drawdown %.2f%% from peak %.2f exceeds %.2f%%", drawdown*100, peak, config.MaxDrawdown*100)
+	log.Warn("drawdown circuit breaker tripped, pausing trading", "equity", snapshot.Equity, "peak", peak, "drawdown", drawdown, "flatten", config.Flatten)
+	s.Pause()
+	s.audit.Record(ctx, audit.ActionPause, "", "drawdown circuit breaker: "+reason, map[string]any{"equity": snapshot.Equity, "peak": peak})
+
+	text := fmt.Sprintf("Equity %.2f is down %.2f%% from its peak %.2f. New orders are paused until trading is resumed manually.", snapshot.Equity, drawdown*100, peak)
+	if config.Flatten {
+		err := s.Flatten(ctx)
+		details := map[string]any{}
+		if err != nil {
+			details["error"] = err.Error()
+			log.Error("Error flattening positions on drawdown circuit breaker", "err", err)
+			text += fmt.Sprintf(" Flattening positions failed: %v", err)
+		} else {
+			text += " All positions were flattened."
+		}
+		s.audit.Record(ctx, audit.ActionFlatten, "", "drawdown circuit breaker: "+reason, details)
+	}
+
+	s.notify(ctx, notify.Message{
+		Title: "Drawdown circuit breaker tripped",
+		Text:  text,
+		Level: notify.LevelCritical,
+	})
+	return nil
+}
+
+// recordEquity 成交后保存权益快照，失败不影响交易流程
+func (s *QuantSystem) recordEquity(ctx context.Context) {
+	if err := s.snapshotEquity(ctx); err != nil {
+		log.Error("Error saving equity snapshot", "err", err)
+	}
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/analytics"
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/fx"
+	"github.com/songzhibin97/quantaflux/internal/journal"
+	"github.com/songzhibin97/quantaflux/internal/models"
+	"github.com/songzhibin97/quantaflux/internal/risk"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+	"github.com/songzhibin97/quantaflux/internal/trading/paper"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryStorage struct {
+	entries   []journal.Entry
+	snapshots []models.EquitySnapshot
+}
+
+func (m *memoryStorage) RecordTrade(ctx context.Context, entry *journal.Entry) error {
+	entry.ID = int64(len(m.entries) + 1)
+	entry.CreatedAt = time.Now()
+	m.entries = append(m.entries, *entry)
+	return nil
+}
+
+func (m *memoryStorage) ListTrades(ctx context.Context, symbol string, limit int) ([]journal.Entry, error) {
+	return m.entries, nil
+}
+
+func (m *memoryStorage) ListTradesInRange(ctx context.Context, start, end time.Time) ([]journal.Entry, error) {
+	var result []journal.Entry
+	for _, entry := range m.entries {
+		if !entry.CreatedAt.Before(start) && !entry.CreatedAt.After(end) {
+			result = append(result, entry)
+		}
+	}
+	return result, nil
+}
+
+func (m *memoryStorage) GetTrade(ctx context.Context, account, symbol, orderID string) (*journal.Entry, error) {
+	return nil, nil
+}
+
+func (m *memoryStorage) ListOpenTrades(ctx context.Context) ([]journal.Entry, error) {
+	return nil, nil
+}
+
+func (m *memoryStorage) UpdateOrderStatus(ctx context.Context, account, symbol, orderID, status string) error {
+	return nil
+}
+
+func (m *memoryStorage) UpdateOrderFill(ctx context.Context, order trading.Order) error {
+	return nil
+}
+
+func (m *memoryStorage) SaveEquitySnapshot(ctx context.Context, snapshot *models.EquitySnapshot) error {
+	m.snapshots = append(m.snapshots, *snapshot)
+	return nil
+}
+
+func (m *memoryStorage) GetEquitySnapshots(ctx context.Context, start, end time.Time) ([]models.EquitySnapshot, error) {
+	var result []models.EquitySnapshot
+	for _, snapshot := range m.snapshots {
+		if !snapshot.Timestamp.Before(start) && !snapshot.Timestamp.After(end) {
+			result = append(result, snapshot)
+		}
+	}
+	return result, nil
+}
+
+// newTestSystem 创建使用模拟执行器的单账户系统，交易日志和权益快照保存在内存中
+func newTestSystem(t *testing.T, balances map[string]float64) (*QuantSystem, *memoryStorage) {
+	t.Helper()
+
+	config := &configs.Config{
+		Mode:    configs.ModePaper,
+		Symbols: []string{"BTCUSDT"},
+		RiskParams: risk.RiskParameters{
+			MaxPositionSize: 10,
+			MaxLossPerTrade: 100,
+			MaxDailyLoss:    500,
+			MaxLeverage:     1,
+			MinLiquidity:    1000,
+		},
+	}
+	a := &account{
+		name:        "main",
+		executor:    paper.NewPaperExecutor(balances),
+		riskManager: risk.NewBasicRiskManager(config.RiskParams),
+	}
+
+	store := &memoryStorage{}
+	system := NewQuantSystem(config, nil, nil, nil, []*account{a}, store, nil)
+	system.equity = store
+	return system, store
+}
+
+func TestQuantSystem_TradeToPerformanceReport(t *testing.T) {
+	ctx := context.Background()
+	system, store := newTestSystem(t, map[string]float64{"USDT": 1000})
+	a := system.primaryAccount()
+
+	start := time.Now().Add(-time.Minute)
+	tick := models.MarketData{Symbol: "BTCUSDT", Price: 100, Timestamp: time.Now()}
+	system.updateMarketData(tick)
+	a.executor.(trading.MarketPriceUpdater).UpdateMarketPrice(tick.Symbol, tick.Price)
+
+	// 启动时的定时快照
+	require.NoError(t, system.snapshotEquity(ctx))
+
+	order := &trading.Order{Account: a.name, Symbol: "BTCUSDT", Side: "buy", Amount: 2, OrderType: "market"}
+	require.NoError(t, a.executor.PlaceOrder(ctx, order))
+	system.recordTrade(ctx, &journal.Entry{Order: *order, MarketData: tick})
+
+	// 价格上涨后成交，权益随持仓市值变化
+	tick.Price = 110
+	system.updateMarketData(tick)
+	a.executor.(trading.MarketPriceUpdater).UpdateMarketPrice(tick.Symbol, tick.Price)
+	sell := &trading.Order{Account: a.name, Symbol: "BTCUSDT", Side: "sell", Amount: 1, OrderType: "market"}
+	require.NoError(t, a.executor.PlaceOrder(ctx, sell))
+	system.recordTrade(ctx, &journal.Entry{Order: *sell, MarketData: tick})
+
+	require.Len(t, store.snapshots, 3)
+	last := store.snapshots[2]
+	assert.Equal(t, configs.ModePaper, last.Mode)
+	assert.InDelta(t, 910, last.Cash, 1e-9)
+	assert.InDelta(t, 110, last.Exposure, 1e-9)
+	assert.InDelta(t, 1020, last.Equity, 1e-9)
+
+	service := analytics.NewService(store, store, 0.001, system.cfg().RunMode())
+	report, err := service.Report(ctx, start, time.Now())
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, report.TradeCount)
+	assert.InDelta(t, 1000, report.StartEquity, 1e-9)
+	assert.InDelta(t, 1020, report.EndEquity, 1e-9)
+	assert.InDelta(t, 0.02, report.TotalReturn, 1e-9)
+	assert.InDelta(t, 310, report.TradedVolume, 1e-9)
+
+	// 其他运行模式的报告看不到这些快照
+	_, err = analytics.NewService(store, store, 0.001, configs.ModeLive).Report(ctx, start, time.Now())
+	assert.Error(t, err)
+}
+
+func TestQuantSystem_DrawdownCircuitBreaker(t *testing.T) {
+	ctx := context.Background()
+	system, store := newTestSystem(t, map[string]float64{"USDT": 100, "BTC": 1})
+	config := *system.cfg()
+	config.DrawdownConfig.MaxDrawdown = 0.1
+	system.config.Store(&config)
+
+	system.updateMarketData(models.MarketData{Symbol: "BTCUSDT", Price: 100, Timestamp: time.Now()})
+	require.NoError(t, system.snapshotEquity(ctx))
+
+	// 回撤 5%，未超过阈值
+	system.updateMarketData(models.MarketData{Symbol: "BTCUSDT", Price: 90, Timestamp: time.Now()})
+	require.NoError(t, system.snapshotEquity(ctx))
+	assert.False(t, system.Paused())
+	assert.InDelta(t, 0.05, system.drawdown.Value(), 1e-9)
+
+	// 相对峰值 200 回撤 15%，暂停下单
+	system.updateMarketData(models.MarketData{Symbol: "BTCUSDT", Price: 70, Timestamp: time.Now()})
+	require.NoError(t, system.snapshotEquity(ctx))
+	assert.True(t, system.Paused())
+	assert.InDelta(t, 0.15, system.drawdown.Value(), 1e-9)
+	assert.Len(t, store.snapshots, 3)
+}
+
+func TestQuantSystem_SnapshotEquityPerAccount(t *testing.T) {
+	ctx := context.Background()
+	system, store := newTestSystem(t, map[string]float64{"USDT": 1000})
+	second := &account{
+		name:        "grid",
+		executor:    paper.NewPaperExecutor(map[string]float64{"USDT": 500, "BTC": 1}),
+		riskManager: risk.NewBasicRiskManager(system.cfg().RiskParams),
+	}
+	system.accounts = append(system.accounts, second)
+	system.updateMarketData(models.MarketData{Symbol: "BTCUSDT", Price: 100, Timestamp: time.Now()})
+
+	require.NoError(t, system.snapshotEquity(ctx))
+	require.Len(t, store.snapshots, 3)
+	assert.Empty(t, store.snapshots[0].Account)
+	assert.InDelta(t, 1600, store.snapshots[0].Equity, 1e-9)
+	assert.Equal(t, "main", store.snapshots[1].Account)
+	assert.InDelta(t, 1000, store.snapshots[1].Equity, 1e-9)
+	assert.Equal(t, "grid", store.snapshots[2].Account)
+	assert.InDelta(t, 600, store.snapshots[2].Equity, 1e-9)
+	assert.InDelta(t, 600, system.accountEquity.Value("grid"), 1e-9)
+
+	// 账户快照不影响汇总权益的回撤
+	assert.Zero(t, system.drawdown.Value())
+}
+
+func TestQuantSystem_ValuationCurrency(t *testing.T) {
+	ctx := context.Background()
+	system, store := newTestSystem(t, map[string]float64{"USDT": 1000, "BTC": 1, "ETH": 10})
+	config := *system.cfg()
+	config.Symbols = []string{"BTCUSDT", "ETHBTC"}
+	system.config.Store(&config)
+	a := system.primaryAccount()
+
+	// 缺少 ETHBTC 价格时无法估值，不保存快照
+	system.updateMarketData(models.MarketData{Symbol: "BTCUSDT", Price: 100, Timestamp: time.Now()})
+	assert.ErrorIs(t, system.snapshotEquity(ctx), fx.ErrNoRate)
+	assert.Empty(t, store.snapshots)
+
+	// ETH 经 BTC 换算为 USDT：10 * 0.05 * 100
+	system.updateMarketData(models.MarketData{Symbol: "ETHBTC", Price: 0.05, Timestamp: time.Now()})
+	require.NoError(t, system.snapshotEquity(ctx))
+	require.Len(t, store.snapshots, 1)
+	assert.InDelta(t, 1000, store.snapshots[0].Cash, 1e-9)
+	assert.InDelta(t, 150, store.snapshots[0].Exposure, 1e-9)
+
+	positions, err := system.Positions(ctx)
+	require.NoError(t, err)
+	require.Len(t, positions, 2)
+	assert.Equal(t, "USDT", positions[1].Currency)
+	assert.InDelta(t, 50, positions[1].Value, 1e-9)
+
+	// 风控限额按 USDT 计价：0.15 BTC 的订单折合 15 USDT，超过 max_position_size
+	assessment, err := system.checkTradeRisk(ctx, a, &trading.Order{Symbol: "ETHBTC", Side: "buy", Amount: 3, Price: 0.05, OrderType: "limit"})
+	require.NoError(t, err)
+	assert.False(t, assessment.IsAcceptable)
+	assessment, err = system.checkTradeRisk(ctx, a, &trading.Order{Symbol: "ETHBTC", Side: "buy", Amount: 1, Price: 0.05, OrderType: "limit"})
+	require.NoError(t, err)
+	assert.True(t, assessment.IsAcceptable)
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/ai"
+	"github.com/songzhibin97/quantaflux/internal/audit"
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/data"
+	"github.com/songzhibin97/quantaflux/internal/models"
+	"github.com/songzhibin97/quantaflux/internal/risk"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+)
+
+// classifyError 根据领域错误判断错误类别
+func classifyError(err error) string {
+	switch {
+	case errors.Is(err, trading.ErrUnauthorized):
+		return configs.ErrorClassAuth
+	case errors.Is(err, trading.ErrExchangeUnavailable):
+		return configs.ErrorClassExchange
+	case errors.Is(err, trading.ErrOrderRejected),
+		errors.Is(err, trading.ErrOrderNotFound),
+		errors.Is(err, trading.ErrBalanceNotFound),
+		errors.Is(err, trading.ErrTimestampRejected): // 已重新同步时间并重试，只跳过本次操作
+		return configs.ErrorClassOrder
+	case errors.Is(err, ai.ErrProviderUnavailable),
+		errors.Is(err, ai.ErrInvalidResponse),
+		errors.Is(err, ai.ErrInsufficientData):
+		return configs.ErrorClassProvider
+	case errors.Is(err, data.ErrSymbolNotFound),
+		errors.Is(err, data.ErrSourceUnavailable),
+		errors.Is(err, data.ErrNotFound):
+		return configs.ErrorClassData
+	case errors.Is(err, risk.ErrRiskRejected):
+		return configs.ErrorClassRisk
+	default:
+		return configs.ErrorClassUnknown
+	}
+}
+
+// processMarketData 处理行情并按错误类别执行配置的处理策略
+func (s *QuantSystem) processMarketData(ctx context.Context, data models.MarketData) error {
+	// 根 span 覆盖从取出行情到下单的全过程，queue_delay 为采集到开始处理的等待时间
+	s.advanceClock(data)
+	ctx = withTickTime(ctx, data)
+	ctx, span := s.tracer.Start(ctx, "tick", "symbol", data.Symbol, "queue_delay", time.Since(data.Timestamp).String())
+	err := s.handleMarketData(ctx, data)
+	span.RecordError(err)
+	span.End()
+
+	return s.handleError(err, "symbol", data.Symbol)
+}
+
+// handleError 按错误类别执行处理策略，halt 策略的错误会通知主循环退出
+func (s *QuantSystem) handleError(err error, fields ...interface{}) error {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return nil
+	}
+
+	// 回测读取了未来的数据，结果不可信，无论处理策略如何都停止
+	if errors.Is(err, data.ErrLookahead) {
+		log.Error("lookahead bias detected, stopping backtest", append(fields, "err", err)...)
+		select {
+		case s.fatalCh <- err:
+		default:
+		}
+		return nil
+	}
+
+	class := classifyError(err)
+	policy := s.cfg().ErrorPolicyFor(class)
+	fields = append(fields, "class", class, "policy", policy, "err", err)
+
+	switch policy {
+	case configs.ErrorPolicyHalt:
+		log.Error("fatal error, stopping system", fields...)
+		select {
+		case s.fatalCh <- fmt.Errorf("fatal %s error: %w", class, err):
+		default:
+		}
+	case configs.ErrorPolicyPause:
+		duration := s.cfg().ErrorPause()
+		log.Error("error requires attention, pausing trading", append(fields, "duration", duration)...)
+		s.pauseFor(duration, func() {
+			log.Info("error pause expired, trading resumed", "class", class, "duration", duration)
+			s.audit.Record(context.Background(), audit.ActionResume, "", fmt.Sprintf("%s error pause expired after %s", class, duration), map[string]any{"class": class})
+		})
+		s.audit.Record(context.Background(), audit.ActionPause, "", err.Error(), map[string]any{"class": class, "duration": duration.String()})
+	default:
+		if class == configs.ErrorClassRisk {
+			log.Info("trade skipped", fields...)
+		} else {
+			log.Warn("error handling market data, skipped", fields...)
+		}
+	}
+	return nil
+}
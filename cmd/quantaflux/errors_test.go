@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/data"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuantSystem_HandleError_PauseExpires(t *testing.T) {
+	system, _ := newTestSystem(t, map[string]float64{"USDT": 1000})
+	config := *system.cfg()
+	config.ErrorPauseDuration = "20ms"
+	system.config.Store(&config)
+
+	err := fmt.Errorf("failed to place order: %w", trading.ErrExchangeUnavailable)
+	assert.NoError(t, system.handleError(err))
+	assert.True(t, system.Paused())
+	assert.Eventually(t, func() bool { return !system.Paused() }, time.Second, 5*time.Millisecond)
+
+	// 暂停期间手动暂停后不再自动恢复
+	assert.NoError(t, system.handleError(err))
+	system.Pause()
+	time.Sleep(50 * time.Millisecond)
+	assert.True(t, system.Paused())
+
+	// 配置为 0 时需要手动恢复
+	system.Resume()
+	config.ErrorPauseDuration = "0"
+	assert.NoError(t, system.handleError(err))
+	time.Sleep(50 * time.Millisecond)
+	assert.True(t, system.Paused())
+}
+
+func TestQuantSystem_HandleError_Lookahead(t *testing.T) {
+	system, _ := newTestSystem(t, map[string]float64{"USDT": 1000})
+	config := *system.cfg()
+	config.ErrorPolicy = map[string]string{configs.ErrorClassData: configs.ErrorPolicySkip}
+	system.config.Store(&config)
+
+	// 读取未来数据时无论处理策略如何都停止运行
+	err := fmt.Errorf("failed to load price history: %w", data.ErrLookahead)
+	assert.NoError(t, system.handleError(err, "symbol", "BTCUSDT"))
+	select {
+	case fatal := <-system.fatalCh:
+		assert.ErrorIs(t, fatal, data.ErrLookahead)
+	default:
+		t.Fatal("lookahead error did not stop the system")
+	}
+}
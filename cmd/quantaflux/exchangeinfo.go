@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/data/collector/binance"
+	"github.com/songzhibin97/quantaflux/internal/exchangeinfo"
+	"github.com/songzhibin97/quantaflux/internal/notify"
+	"github.com/songzhibin97/quantaflux/internal/risk"
+)
+
+// 交易对状态预警类型
+const (
+	alertTradingHalted   = "Trading Halted"   // 交易对暂停交易或已下架
+	alertSymbolDelisting = "Symbol Delisting" // 交易对即将下架
+)
+
+// 检查下架时间的间隔
+const delistingCheckInterval = time.Minute
+
+// buildExchangeInfo 创建数据源和执行器共享的交易所元数据缓存，回测不访问交易所，返回 nil
+func buildExchangeInfo(config *configs.Config) *exchangeinfo.Service {
+	if config.RunMode() == configs.ModeBacktest {
+		return nil
+	}
+	return exchangeinfo.NewService(binance.NewBinanceDataSource())
+}
+
+// refreshExchangeInfo 刷新交易所元数据并同步到品种池，未启用时不做任何事
+func (s *QuantSystem) refreshExchangeInfo(ctx context.Context) error {
+	if s.exchangeInfo == nil {
+		return nil
+	}
+	if err := s.exchangeInfo.Refresh(ctx); err != nil {
+		return err
+	}
+	return s.syncUniverse(ctx, time.Now())
+}
+
+// exchangeStatusChanged 交易中的交易对暂停交易或下架时发出预警，开始或恢复交易时按配置触发重新分析，
+// 预警由 monitorTradingStatus 分发给账户
+func (s *QuantSystem) exchangeStatusChanged(change exchangeinfo.StatusChange) {
+	if !slices.Contains(s.cfg().Symbols, change.Symbol) {
+		return
+	}
+	if change.Current == exchangeinfo.StatusTrading {
+		log.Info("symbol trading resumed", "symbol", change.Symbol, "previous", change.Previous)
+		if s.cfg().AIConfig.Reanalysis.Listing {
+			s.triggerReanalysis(change.Symbol, eventListing, fmt.Sprintf("status changed from %s to %s", change.Previous, change.Current))
+		}
+		return
+	}
+
+	description := fmt.Sprintf("%s status changed from %s to %s, new orders are blocked", change.Symbol, change.Previous, change.Current)
+	if change.Current == "" {
+		description = fmt.Sprintf("%s is no longer listed on the exchange, new orders are blocked", change.Symbol)
+	}
+	log.Warn("symbol trading halted", "symbol", change.Symbol, "previous", change.Previous, "current", change.Current)
+
+	alert := risk.RiskAlert{
+		Symbol:      change.Symbol,
+		AlertType:   alertTradingHalted,
+		Severity:    risk.SeverityLow,
+		Description: description,
+		Timestamp:   time.Now(),
+	}
+	select {
+	case s.statusAlerts <- alert:
+	default:
+		log.Error("trading status alert dropped, queue full", "symbol", change.Symbol)
+	}
+}
+
+// tradingHalted 交易所元数据显示交易对不可交易或已过公告的下架时间时返回原因，
+// 元数据未加载时只按下架时间判断
+func (s *QuantSystem) tradingHalted(symbol string, now time.Time) (string, bool) {
+	if at, ok := s.cfg().TradingStatusConfig.DelistingAt(symbol); ok && !now.Before(at) {
+		return "delisted", true
+	}
+	if s.exchangeInfo == nil || s.exchangeInfo.UpdatedAt().IsZero() {
+		return "", false
+	}
+	info, ok := s.exchangeInfo.Symbol(symbol)
+	if !ok {
+		return "not listed", true
+	}
+	if !info.Trading() {
+		return info.Status, true
+	}
+	return "", false
+}
+
+// delistingAlerts 返回进入下架前平仓窗口的交易对的 HIGH 级别预警，每个交易对只预警一次
+func (s *QuantSystem) delistingAlerts(now time.Time, alerted map[string]bool) []risk.RiskAlert {
+	config := s.cfg()
+	lead := config.TradingStatusConfig.CloseLead()
+	if lead <= 0 {
+		return nil
+	}
+
+	var alerts []risk.RiskAlert
+	for _, symbol := range config.Symbols {
+		at, ok := config.TradingStatusConfig.DelistingAt(symbol)
+		if !ok || alerted[symbol] || now.Before(at.Add(-lead)) {
+			continue
+		}
+		alerted[symbol] = true
+		alerts = append(alerts, risk.RiskAlert{
+			Symbol:      symbol,
+			AlertType:   alertSymbolDelisting,
+			Severity:    risk.SeverityHigh,
+			Description: fmt.Sprintf("%s will be delisted at %s, closing position", symbol, at.Format(time.RFC3339)),
+			Timestamp:   now,
+		})
+	}
+	return alerts
+}
+
+// monitorTradingStatus 将交易对状态预警和下架平仓预警分发给交易该交易对的每个账户，同时发送通知；回测时不监控
+func (s *QuantSystem) monitorTradingStatus(ctx context.Context, out chan<- accountAlert) {
+	if s.exchangeInfo == nil {
+		return
+	}
+
+	// 已发出下架预警的交易对在监控重启后保留，避免重复预警
+	alerted := make(map[string]bool)
+	s.supervise(ctx, "trading_status_monitor", func(ctx context.Context) {
+		ticker := time.NewTicker(delistingCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			var alerts []risk.RiskAlert
+			select {
+			case <-ctx.Done():
+				return
+			case alert := <-s.statusAlerts:
+				alerts = append(alerts, alert)
+			case now := <-ticker.C:
+				alerts = s.delistingAlerts(now, alerted)
+			}
+
+			for _, alert := range alerts {
+				s.notify(ctx, notify.Message{
+					Title: fmt.Sprintf("%s %s", alert.Symbol, alert.AlertType),
+					Text:  alert.Description,
+					Level: notify.LevelWarning,
+				})
+				for _, a := range s.accounts {
+					if !a.trades(alert.Symbol) {
+						continue
+					}
+					select {
+					case out <- accountAlert{account: a, alert: alert}:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}
+	})
+}
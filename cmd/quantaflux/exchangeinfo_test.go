@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/exchangeinfo"
+	"github.com/songzhibin97/quantaflux/internal/risk"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubExchangeInfo struct {
+	symbols []exchangeinfo.Symbol
+}
+
+func (s *stubExchangeInfo) ExchangeInfo(ctx context.Context) ([]exchangeinfo.Symbol, error) {
+	return s.symbols, nil
+}
+
+func TestQuantSystem_TradingStatus(t *testing.T) {
+	ctx := context.Background()
+	system, _ := newTestSystem(t, map[string]float64{"USDT": 1000})
+	now := time.Now()
+
+	// 元数据未加载时不阻止下单
+	source := &stubExchangeInfo{symbols: []exchangeinfo.Symbol{{Symbol: "BTCUSDT", Status: exchangeinfo.StatusTrading}}}
+	system.exchangeInfo = exchangeinfo.NewService(source)
+	system.exchangeInfo.OnStatusChange = system.exchangeStatusChanged
+	_, halted := system.tradingHalted("BTCUSDT", now)
+	assert.False(t, halted)
+
+	require.NoError(t, system.refreshExchangeInfo(ctx))
+	_, halted = system.tradingHalted("BTCUSDT", now)
+	assert.False(t, halted)
+
+	// 暂停交易后阻止下单并发出预警
+	source.symbols[0].Status = "BREAK"
+	require.NoError(t, system.refreshExchangeInfo(ctx))
+	status, halted := system.tradingHalted("BTCUSDT", now)
+	assert.True(t, halted)
+	assert.Equal(t, "BREAK", status)
+
+	require.Len(t, system.statusAlerts, 1)
+	alert := <-system.statusAlerts
+	assert.Equal(t, alertTradingHalted, alert.AlertType)
+	assert.Equal(t, risk.SeverityLow, alert.Severity)
+
+	// 恢复交易不发出预警
+	source.symbols[0].Status = exchangeinfo.StatusTrading
+	require.NoError(t, system.refreshExchangeInfo(ctx))
+	_, halted = system.tradingHalted("BTCUSDT", now)
+	assert.False(t, halted)
+	assert.Empty(t, system.statusAlerts)
+
+	// 下架前 close_before 内发出一次平仓预警，下架时间之后阻止下单
+	config := *system.cfg()
+	config.TradingStatusConfig.Delistings = map[string]string{"BTCUSDT": now.Add(12 * time.Hour).Format(time.RFC3339)}
+	config.TradingStatusConfig.CloseBefore = "24h"
+	system.config.Store(&config)
+
+	alerted := make(map[string]bool)
+	assert.Empty(t, system.delistingAlerts(now.Add(-13*time.Hour), alerted))
+	alerts := system.delistingAlerts(now, alerted)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, alertSymbolDelisting, alerts[0].AlertType)
+	assert.Equal(t, risk.SeverityHigh, alerts[0].Severity)
+	assert.Empty(t, system.delistingAlerts(now.Add(time.Hour), alerted))
+
+	_, halted = system.tradingHalted("BTCUSDT", now)
+	assert.False(t, halted)
+	status, halted = system.tradingHalted("BTCUSDT", now.Add(13*time.Hour))
+	assert.True(t, halted)
+	assert.Equal(t, "delisted", status)
+}
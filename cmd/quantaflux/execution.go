@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/chaos"
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/risk"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+)
+
+// wrapExecutors 按 execution_config 为各账户的执行器组合中间件并创建组合订单管理器；启用故障注入时交易所故障在最内层
+func (s *QuantSystem) wrapExecutors(injector *chaos.Injector) {
+	for _, a := range s.accounts {
+		a.executor = trading.Chain(a.executor, s.executorMiddlewares(a, injector)...)
+		a.brackets = trading.NewOrderManager(a.executor)
+	}
+}
+
+// executorMiddlewares 返回账户执行器的中间件，从外到内依次为指标、日志、去重、限流、下单限制、风险复核、试运行和故障注入。
+// 指标在最外层，被限流或风险复核拒绝的订单也计入
+func (s *QuantSystem) executorMiddlewares(a *account, injector *chaos.Injector) []trading.Middleware {
+	config := s.cfg().ExecutionConfig
+	middlewares := []trading.Middleware{trading.Metrics(func(op string, err error, elapsed time.Duration) {
+		s.observeExecutor(a.name, op, err, elapsed)
+	})}
+	if config.LogOrders {
+		middlewares = append(middlewares, trading.Logging(moduleLog("executor").With("account", a.name)))
+	}
+	if window := config.Window(); window > 0 {
+		middlewares = append(middlewares, trading.Idempotency(window))
+	}
+	// 回测按回放时间运行，按真实时间限流会拒绝大部分订单
+	if config.RateLimit > 0 && s.cfg().RunMode() != configs.ModeBacktest {
+		middlewares = append(middlewares, trading.RateLimit(config.RateLimit))
+	}
+	// 配对交易等策略直接通过执行器下单，与信号下单一样受暂停、下单金额上限和风险限额约束
+	middlewares = append(middlewares, trading.Guard(func(ctx context.Context, order *trading.Order) (func(), error) {
+		return s.governOrder(ctx, a, order)
+	}))
+	if config.RecheckRisk {
+		middlewares = append(middlewares, trading.RiskCheck(func(ctx context.Context, order *trading.Order) error {
+			return s.recheckRisk(ctx, a, order)
+		}))
+	}
+	if config.DryRun {
+		log.Warn("execution dry run enabled, orders are not sent to the exchange", "account", a.name)
+		middlewares = append(middlewares, trading.DryRun())
+	}
+	if injector != nil {
+		middlewares = append(middlewares, chaos.Middleware(injector))
+	}
+	return middlewares
+}
+
+type governedKey struct{}
+
+// governedContext 标记订单已在信号下单流程中检查过暂停状态、占用了下单额度并通过风险评估，执行器中间件不再重复检查
+func governedContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, governedKey{}, true)
+}
+
+// governOrder 对未经过信号下单流程的买入订单执行同样的限制：交易对暂停、停止交易、行情停滞或资产充提暂停时拒绝，
+// 按 trading_config.spend_limit 占用全局下单额度并评估风险。订单没有下单时调用返回的 release 归还额度
+func (s *QuantSystem) governOrder(ctx context.Context, a *account, order *trading.Order) (func(), error) {
+	if governed, _ := ctx.Value(governedKey{}).(bool); governed {
+		return func() {}, nil
+	}
+
+	reason := ""
+	if s.symbolPaused(order.Symbol) {
+		reason = "trading paused"
+	} else if status, halted := s.tradingHalted(order.Symbol, s.clock.Now()); halted {
+		reason = "symbol not trading: " + status
+	} else if s.symbolStale(order.Symbol) {
+		reason = "market data stale"
+	} else if blocked, ok := s.walletBlocked(a, order.Symbol, order.Side); ok {
+		reason = blocked
+	}
+	if reason != "" {
+		return nil, fmt.Errorf("%w: %s %s: %s", risk.ErrRiskRejected, order.Side, order.Symbol, reason)
+	}
+
+	price := order.Price
+	if price <= 0 {
+		price = s.lastPrice(order.Symbol)
+	}
+	release, err := s.reserveSpend(ctx, order, price)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.recheckRisk(ctx, a, order); err != nil {
+		release()
+		return nil, err
+	}
+	return release, nil
+}
+
+// observeExecutor 记录执行器调用的结果和耗时
+func (s *QuantSystem) observeExecutor(account, op string, err error, elapsed time.Duration) {
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	s.execCalls.Inc(account, op, result)
+	s.execLatency.Set(elapsed.Seconds(), account, op)
+}
+
+// recheckRisk 在订单发送到交易所前按最新的持仓和限额再次评估，不可接受时返回 risk.ErrRiskRejected
+func (s *QuantSystem) recheckRisk(ctx context.Context, a *account, order *trading.Order) error {
+	assessment, err := s.checkTradeRisk(ctx, a, order)
+	if err != nil {
+		return err
+	}
+	if !assessment.IsAcceptable {
+		return fmt.Errorf("%w: %s %s: %s", risk.ErrRiskRejected, order.Side, order.Symbol, strings.Join(assessment.RiskFactors, "; "))
+	}
+	return nil
+}
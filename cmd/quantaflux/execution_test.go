@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/risk"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuantSystem_WrapExecutors(t *testing.T) {
+	ctx := context.Background()
+	system, _ := newTestSystem(t, map[string]float64{"USDT": 1000})
+	config := *system.cfg()
+	config.ExecutionConfig = configs.ExecutionConfig{RateLimit: 1, RecheckRisk: true, DryRun: true, IdempotencyWindow: "1m"}
+	system.config.Store(&config)
+	system.wrapExecutors(nil)
+	a := system.primaryAccount()
+
+	// 试运行不改变模拟账户余额，模拟执行器的可选接口仍可访问
+	order := &trading.Order{Account: a.name, Symbol: "BTCUSDT", Side: "buy", OrderType: "market", Amount: 0.01, Price: 100}
+	require.NoError(t, a.executor.PlaceOrder(ctx, order))
+	assert.Equal(t, trading.StatusDryRun, order.Status)
+	balance, err := a.executor.GetBalance(ctx, "USDT")
+	require.NoError(t, err)
+	assert.Equal(t, 1000.0, balance)
+	_, ok := trading.As[trading.StatefulExecutor](a.executor)
+	assert.True(t, ok)
+
+	// 同一客户端订单号的重试不占用限流额度，新订单被限流
+	retry := &trading.Order{Account: a.name, Symbol: "BTCUSDT", Side: "buy", OrderType: "market", Amount: 0.01, Price: 100, ClientOrderID: order.ClientOrderID}
+	require.NoError(t, a.executor.PlaceOrder(ctx, retry))
+	assert.Equal(t, order.OrderID, retry.OrderID)
+	err = a.executor.PlaceOrder(ctx, &trading.Order{Account: a.name, Symbol: "BTCUSDT", Side: "buy", OrderType: "market", Amount: 0.01, Price: 100})
+	assert.ErrorIs(t, err, trading.ErrRateLimited)
+
+	assert.Equal(t, 2.0, system.execCalls.Value(a.name, trading.OpPlaceOrder, "ok"))
+	assert.Equal(t, 1.0, system.execCalls.Value(a.name, trading.OpPlaceOrder, "error"))
+	assert.Equal(t, 1.0, system.execCalls.Value(a.name, trading.OpGetBalance, "ok"))
+}
+
+func TestQuantSystem_RecheckRisk(t *testing.T) {
+	ctx := context.Background()
+	system, _ := newTestSystem(t, map[string]float64{"USDT": 1000})
+	a := system.primaryAccount()
+
+	// 超过 max_position_size 的买单不可接受
+	err := system.recheckRisk(ctx, a, &trading.Order{Account: a.name, Symbol: "BTCUSDT", Side: "buy", OrderType: "market", Amount: 20, Price: 100})
+	assert.ErrorIs(t, err, risk.ErrRiskRejected)
+	assert.NoError(t, system.recheckRisk(ctx, a, &trading.Order{Account: a.name, Symbol: "BTCUSDT", Side: "buy", OrderType: "market", Amount: 0.01, Price: 100}))
+}
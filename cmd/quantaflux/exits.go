@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/api"
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/journal"
+	"github.com/songzhibin97/quantaflux/internal/risk"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+)
+
+// 分批止盈预警的类型
+const alertTakeProfit = "Take Profit"
+
+// exitLadder 一轮持仓的分批止盈进度
+type exitLadder struct {
+	openedAt time.Time // 持仓开始的时间，变化表示新一轮持仓
+	base     float64   // 第一档止盈时的持仓数量，各档按它的比例卖出
+	done     int       // 已执行的档数
+}
+
+// exitState 各账户交易对的分批止盈进度
+type exitState struct {
+	mu      sync.Mutex
+	ladders map[string]*exitLadder // 账户/交易对 -> 进度
+}
+
+func newExitState() *exitState {
+	return &exitState{ladders: make(map[string]*exitLadder)}
+}
+
+// reached 返回持仓按当前涨幅达到但尚未执行的档位 [from, to)，新一轮持仓从第一档开始
+func (e *exitState) reached(account string, pos risk.Position, levels []configs.TakeProfitLevel) (from, to int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	key := account + "/" + pos.Symbol
+	ladder, ok := e.ladders[key]
+	if !ok || !ladder.openedAt.Equal(pos.OpenedAt) {
+		ladder = &exitLadder{openedAt: pos.OpenedAt}
+		e.ladders[key] = ladder
+	}
+	if pos.AvgCost <= 0 {
+		return ladder.done, ladder.done
+	}
+
+	gain := pos.Price/pos.AvgCost - 1
+	to = ladder.done
+	for to < len(levels) && gain >= levels[to].Gain {
+		to++
+	}
+	return ladder.done, to
+}
+
+// advance 记录执行了 [from, to) 档，返回卖出的数量；持仓已进入新一轮或档位已被执行时返回 0
+func (e *exitState) advance(account string, pos risk.Position, levels []configs.TakeProfitLevel, from, to int, holding float64) float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	ladder, ok := e.ladders[account+"/"+pos.Symbol]
+	if !ok || !ladder.openedAt.Equal(pos.OpenedAt) || ladder.done != from {
+		return 0
+	}
+	if from == 0 {
+		ladder.base = holding
+	}
+	var fraction float64
+	for _, level := range levels[from:to] {
+		fraction += level.Fraction
+	}
+	ladder.done = to
+	return min(ladder.base*fraction, holding)
+}
+
+// rollback 下单失败时撤销 advance，下次检查时重试
+func (e *exitState) rollback(account string, pos risk.Position, from, to int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if ladder, ok := e.ladders[account+"/"+pos.Symbol]; ok && ladder.openedAt.Equal(pos.OpenedAt) && ladder.done == to {
+		ladder.done = from
+	}
+}
+
+// sellPosition 按市价卖出账户在交易对上的 amount 数量持仓，记录审计和交易日志
+func (s *QuantSystem) sellPosition(ctx context.Context, a *account, symbol string, amount float64, strategy, reason string) (*trading.Order, error) {
+	order := &trading.Order{
+		Account:   a.name,
+		Symbol:    symbol,
+		Side:      "sell",
+		Amount:    amount,
+		OrderType: "market",
+	}
+	err := a.executor.PlaceOrder(ctx, order)
+	s.recordOrderResult(a.name, err)
+	s.auditOrder(ctx, order, reason, err)
+	if err != nil {
+		return nil, err
+	}
+	s.recordTrade(ctx, &journal.Entry{Strategy: strategy, Order: *order})
+	return order, nil
+}
+
+// closePosition 按市价卖出账户在交易对上 fraction 比例的持仓，没有持仓时返回 nil
+func (s *QuantSystem) closePosition(ctx context.Context, a *account, symbol string, fraction float64, strategy, reason string) (*trading.Order, error) {
+	s.cancelBrackets(ctx, a, symbol)
+	balance, err := s.positionAmount(ctx, a, symbol)
+	if err != nil {
+		return nil, err
+	}
+	if balance <= 0 {
+		return nil, nil
+	}
+	return s.sellPosition(ctx, a, symbol, balance*min(fraction, 1), strategy, reason)
+}
+
+// ClosePosition implements api.System
+func (s *QuantSystem) ClosePosition(ctx context.Context, account, symbol string, fraction float64) (*trading.Order, error) {
+	if fraction <= 0 || fraction > 1 {
+		return nil, fmt.Errorf("fraction must be greater than 0 and at most 1, got %v", fraction)
+	}
+	a, err := s.account(account)
+	if err != nil {
+		return nil, err
+	}
+	order, err := s.closePosition(ctx, a, symbol, fraction, "manual_close", fmt.Sprintf("close %.0f%% of position via api", fraction*100))
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, fmt.Errorf("%w: %s has no position in %s", api.ErrPositionNotFound, a.name, symbol)
+	}
+	return order, nil
+}
+
+// takeProfit 执行持仓当前涨幅达到的分批止盈档位
+func (s *QuantSystem) takeProfit(ctx context.Context, a *account, symbol string) error {
+	levels := s.symbolSettings(symbol).TakeProfit
+	positions, err := s.OpenPositions(ctx, a.name)
+	if err != nil {
+		return err
+	}
+	for _, pos := range positions {
+		if pos.Symbol != symbol {
+			continue
+		}
+		from, to := s.exits.reached(a.name, pos, levels)
+		if from == to {
+			return nil
+		}
+		holding, err := s.positionAmount(ctx, a, symbol)
+		if err != nil {
+			return err
+		}
+		amount := s.exits.advance(a.name, pos, levels, from, to, holding)
+		if amount <= 0 {
+			return nil
+		}
+		reason := fmt.Sprintf("take profit up to level %d at %+.2f%% over average cost", to, (pos.Price/pos.AvgCost-1)*100)
+		if _, err := s.sellPosition(ctx, a, symbol, amount, "risk_take_profit", reason); err != nil {
+			s.exits.rollback(a.name, pos, from, to)
+			return err
+		}
+		log.Info("take profit executed", "account", a.name, "symbol", symbol, "level", to, "amount", amount)
+	}
+	return nil
+}
+
+// takeProfitAlerts 返回持仓涨幅达到未执行的止盈档位的预警
+func (s *QuantSystem) takeProfitAlerts(ctx context.Context, a *account, now time.Time) ([]risk.RiskAlert, error) {
+	positions, err := s.OpenPositions(ctx, a.name)
+	if err != nil {
+		return nil, err
+	}
+	var alerts []risk.RiskAlert
+	for _, pos := range positions {
+		levels := s.symbolSettings(pos.Symbol).TakeProfit
+		if from, to := s.exits.reached(a.name, pos, levels); from < to {
+			alerts = append(alerts, risk.RiskAlert{
+				Symbol:      pos.Symbol,
+				AlertType:   alertTakeProfit,
+				Severity:    risk.SeverityLow,
+				Description: fmt.Sprintf("Price %.8g of %s is %+.2f%% over average cost %.8g, reaching take profit level %d", pos.Price, pos.Symbol, (pos.Price/pos.AvgCost-1)*100, pos.AvgCost, to),
+				Timestamp:   now,
+			})
+		}
+	}
+	return alerts, nil
+}
+
+// monitorTakeProfit 按持仓监控间隔检查各账户持仓，涨幅达到止盈档位时发出预警，由 handleRiskAlert 卖出
+func (s *QuantSystem) monitorTakeProfit(ctx context.Context, out chan<- accountAlert) {
+	interval, err := time.ParseDuration(s.cfg().PositionMonitorConfig.Interval)
+	if err != nil || interval <= 0 {
+		interval = 15 * time.Second
+	}
+
+	// Ticker 在启动 goroutine 之前创建，回测的模拟时钟推进时不会错过触发
+	// 监控重启时沿用同一个 Ticker，ctx 取消后停止
+	ticker := s.clock.NewTicker(interval)
+	context.AfterFunc(ctx, ticker.Stop)
+	s.supervise(ctx, "take_profit_monitor", func(ctx context.Context) {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C():
+				for _, a := range s.accounts {
+					alerts, err := s.takeProfitAlerts(ctx, a, now)
+					if err != nil {
+						log.Debug("take profit check skipped", "account", a.name, "err", err)
+						continue
+					}
+					for _, alert := range alerts {
+						select {
+						case out <- accountAlert{account: a, alert: alert}:
+						case <-ctx.Done():
+							return
+						}
+					}
+				}
+			}
+		}
+	})
+}
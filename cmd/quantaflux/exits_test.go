@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/journal"
+	"github.com/songzhibin97/quantaflux/internal/models"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuantSystem_TakeProfit(t *testing.T) {
+	ctx := context.Background()
+	system, _ := newTestSystem(t, map[string]float64{"USDT": 10000})
+	a := system.primaryAccount()
+	config := *system.cfg()
+	config.PositionMonitorConfig.TakeProfit = []configs.TakeProfitLevel{{Gain: 0.05, Fraction: 0.3}, {Gain: 0.1, Fraction: 0.3}}
+	system.config.Store(&config)
+
+	setPrice := func(price float64) {
+		tick := models.MarketData{Symbol: "BTCUSDT", Price: price, Timestamp: time.Now()}
+		system.updateMarketData(tick)
+		a.executor.(trading.MarketPriceUpdater).UpdateMarketPrice(tick.Symbol, tick.Price)
+	}
+	setPrice(100)
+	order := &trading.Order{Account: a.name, Symbol: "BTCUSDT", Side: "buy", Amount: 10, OrderType: "market"}
+	require.NoError(t, a.executor.PlaceOrder(ctx, order))
+	system.recordTrade(ctx, &journal.Entry{Order: *order})
+
+	// 涨幅未到第一档
+	setPrice(103)
+	alerts, err := system.takeProfitAlerts(ctx, a, time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, alerts)
+
+	// 第一档卖出 30%
+	setPrice(106)
+	alerts, err = system.takeProfitAlerts(ctx, a, time.Now())
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, alertTakeProfit, alerts[0].AlertType)
+	require.NoError(t, system.handleRiskAlert(ctx, a, alerts[0]))
+	balance, err := system.positionAmount(ctx, a, "BTCUSDT")
+	require.NoError(t, err)
+	assert.InDelta(t, 7, balance, 1e-9)
+
+	// 同一档不重复卖出
+	require.NoError(t, system.takeProfit(ctx, a, "BTCUSDT"))
+	alerts, err = system.takeProfitAlerts(ctx, a, time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, alerts)
+
+	// 第二档按第一档时的持仓卖出 30%
+	setPrice(111)
+	require.NoError(t, system.takeProfit(ctx, a, "BTCUSDT"))
+	balance, err = system.positionAmount(ctx, a, "BTCUSDT")
+	require.NoError(t, err)
+	assert.InDelta(t, 4, balance, 1e-9)
+}
+
+func TestQuantSystem_ClosePosition(t *testing.T) {
+	ctx := context.Background()
+	system, _ := newTestSystem(t, map[string]float64{"USDT": 1000, "BTC": 2})
+	system.primaryAccount().executor.(trading.MarketPriceUpdater).UpdateMarketPrice("BTCUSDT", 100)
+
+	order, err := system.ClosePosition(ctx, "", "BTCUSDT", 0.25)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.5, order.Amount, 1e-9)
+
+	_, err = system.ClosePosition(ctx, "main", "ETHUSDT", 1)
+	assert.Error(t, err)
+	_, err = system.ClosePosition(ctx, "other", "BTCUSDT", 1)
+	assert.Error(t, err)
+}
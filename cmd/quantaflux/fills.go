@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/songzhibin97/quantaflux/internal/journal"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+)
+
+// syncOrder 从交易所查询订单的最新状态和成交，更新交易日志和挂单列表，返回更新后的订单
+// 交易所返回的方向和类型格式与本地不同，只同步状态、成交数量、成交均价、手续费和更新时间
+func (s *QuantSystem) syncOrder(ctx context.Context, a *account, recorded trading.Order) (*trading.Order, error) {
+	order, err := a.executor.GetOrderStatus(ctx, recorded.Symbol, recorded.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	synced := recorded
+	synced.Status = order.Status
+	if order.FilledAmount > 0 {
+		synced.FilledAmount = order.FilledAmount
+	}
+	if order.FilledPrice > 0 {
+		synced.FilledPrice = order.FilledPrice
+	}
+	if order.Fee > 0 {
+		synced.Fee, synced.FeeAsset = order.Fee, order.FeeAsset
+	}
+	if !order.UpdatedAt.IsZero() {
+		synced.UpdatedAt = order.UpdatedAt
+	}
+	if synced.ClientOrderID == "" {
+		synced.ClientOrderID = order.ClientOrderID
+	}
+
+	if s.tradeJournal != nil {
+		if synced.Status != recorded.Status {
+			if err := s.tradeJournal.UpdateOrderStatus(ctx, synced.Account, synced.Symbol, synced.OrderID, synced.Status); err != nil {
+				log.Error("Error updating order status", "order_id", synced.OrderID, "err", err)
+			}
+		}
+		if synced.FilledAmount != recorded.FilledAmount || synced.FilledPrice != recorded.FilledPrice || synced.Fee != recorded.Fee {
+			if err := s.tradeJournal.UpdateOrderFill(ctx, synced); err != nil {
+				log.Error("Error updating order fill", "order_id", synced.OrderID, "err", err)
+			}
+		}
+	}
+
+	s.trackOrder(synced)
+	return &synced, nil
+}
+
+// syncOpenOrders 同步所有挂单的状态和成交，挂单部分成交或成交后新增的成交计入交易对的平仓盈亏统计，并保存权益快照；
+// 同时同步未结束的组合订单
+func (s *QuantSystem) syncOpenOrders(ctx context.Context) error {
+	return errors.Join(s.syncOrders(ctx, func(trading.Order) bool { return true }), s.syncBrackets(ctx))
+}
+
+// syncOrders 同步 match 选中的挂单
+func (s *QuantSystem) syncOrders(ctx context.Context, match func(trading.Order) bool) error {
+	var filled bool
+	var errs []error
+	for _, recorded := range s.openOrderList() {
+		if !match(recorded) {
+			continue
+		}
+		a, err := s.account(recorded.Account)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		order, err := s.syncOrder(ctx, a, recorded)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s/%s: %w", recorded.Symbol, recorded.OrderID, err))
+			continue
+		}
+
+		fill, ok := order.FillSince(recorded)
+		if !ok {
+			continue
+		}
+		log.Info("order filled", "account", a.name, "symbol", order.Symbol, "order_id", order.OrderID, "status", order.Status,
+			"amount", fill.FilledAmount, "price", fill.FilledPrice, "filled_amount", order.FilledAmount)
+		s.trackPerformance(ctx, &journal.Entry{Order: fill})
+		filled = true
+	}
+	if filled {
+		s.recordEquity(ctx)
+	}
+	return errors.Join(errs...)
+}
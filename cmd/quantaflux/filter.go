@@ -0,0 +1,40 @@
+package main
+
+import (
+	"math"
+	"sync"
+
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/models"
+)
+
+// eventFilter 记录各交易对上次分析时的行情，价格变化过小的行情不再分析
+type eventFilter struct {
+	mu   sync.Mutex
+	last map[string]models.MarketData
+}
+
+func newEventFilter() *eventFilter {
+	return &eventFilter{last: make(map[string]models.MarketData)}
+}
+
+// allow 判断行情是否需要分析，需要时记为该交易对最近一次分析的行情。
+// 时间按行情时间计算，回测与实盘一致
+func (f *eventFilter) allow(data models.MarketData, config configs.EventFilterConfig) bool {
+	if config.MinPriceChange <= 0 {
+		return true
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	last, ok := f.last[data.Symbol]
+	if ok && last.Price > 0 && math.Abs(data.Price-last.Price)/last.Price < config.MinPriceChange {
+		quiet := config.QuietPeriod()
+		if quiet <= 0 || data.Timestamp.Sub(last.Timestamp) < quiet {
+			return false
+		}
+	}
+	f.last[data.Symbol] = data
+	return true
+}
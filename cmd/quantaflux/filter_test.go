@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventFilter_Allow(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tick := func(symbol string, minute int, price float64) models.MarketData {
+		return models.MarketData{Symbol: symbol, Price: price, Timestamp: base.Add(time.Duration(minute) * time.Minute)}
+	}
+	config := configs.EventFilterConfig{MinPriceChange: 0.01, MaxQuietPeriod: "30m"}
+	filter := newEventFilter()
+
+	steps := []struct {
+		data  models.MarketData
+		allow bool
+	}{
+		{data: tick("BTCUSDT", 0, 100), allow: true},
+		{data: tick("BTCUSDT", 1, 100.5), allow: false},
+		{data: tick("ETHUSDT", 1, 10), allow: true},
+		// 变化按上次分析的价格计算，累计变动达到阈值时分析
+		{data: tick("BTCUSDT", 2, 101), allow: true},
+		{data: tick("BTCUSDT", 3, 100.2), allow: false},
+		{data: tick("BTCUSDT", 4, 99.9), allow: true},
+		// 超过 max_quiet_period 未分析时不过滤
+		{data: tick("BTCUSDT", 20, 99.95), allow: false},
+		{data: tick("BTCUSDT", 34, 99.95), allow: true},
+		{data: tick("BTCUSDT", 35, 99.95), allow: false},
+	}
+	for i, step := range steps {
+		assert.Equal(t, step.allow, filter.allow(step.data, config), "step %d", i)
+	}
+
+	// 未配置阈值时不过滤
+	assert.True(t, filter.allow(tick("BTCUSDT", 36, 99.95), configs.EventFilterConfig{}))
+}
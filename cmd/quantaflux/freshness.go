@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/notify"
+	"github.com/songzhibin97/quantaflux/internal/risk"
+)
+
+// 交易对行情停滞的预警类型
+const alertStaleMarketData = "Stale Market Data"
+
+// 检查行情是否停滞的间隔
+const freshnessCheckInterval = 10 * time.Second
+
+// staleAlerts 将超过 alert_config.stale_intervals 倍刷新间隔未收到行情的交易对标记为停滞，
+// 返回新进入停滞状态的交易对的预警；停滞的交易对收到行情后由 recordMarketUpdate 恢复
+func (s *QuantSystem) staleAlerts(now time.Time) []risk.RiskAlert {
+	config := s.cfg()
+	intervals := config.AlertConfig.StaleIntervals
+	if intervals <= 0 {
+		return nil
+	}
+
+	maxAges := make(map[string]time.Duration, len(config.Symbols))
+	for _, symbol := range config.Symbols {
+		maxAges[symbol] = time.Duration(intervals) * refreshInterval(config, symbol, s.symbolTags(symbol)...)
+	}
+
+	s.alertState.mu.Lock()
+	defer s.alertState.mu.Unlock()
+
+	var alerts []risk.RiskAlert
+	for _, symbol := range config.Symbols {
+		if s.alertState.stale[symbol] {
+			continue
+		}
+		last, ok := s.alertState.updates[symbol]
+		if !ok {
+			last = s.alertState.started
+		}
+		if age := now.Sub(last); age > maxAges[symbol] {
+			s.alertState.stale[symbol] = true
+			alerts = append(alerts, risk.RiskAlert{
+				Symbol:      symbol,
+				AlertType:   alertStaleMarketData,
+				Severity:    risk.SeverityLow,
+				Description: fmt.Sprintf("no market data for %s within %s, new orders are blocked until data resumes", symbol, age.Round(time.Second)),
+				Timestamp:   now,
+			})
+		}
+	}
+	return alerts
+}
+
+// symbolStale 交易对行情停滞时返回 true，未配置 alert_config.stale_intervals 时不阻止下单
+func (s *QuantSystem) symbolStale(symbol string) bool {
+	if s.cfg().AlertConfig.StaleIntervals <= 0 {
+		return false
+	}
+	s.alertState.mu.Lock()
+	defer s.alertState.mu.Unlock()
+	return s.alertState.stale[symbol]
+}
+
+// staleSymbols 返回行情停滞、阻止新订单的交易对
+func (s *QuantSystem) staleSymbols() []string {
+	if s.cfg().AlertConfig.StaleIntervals <= 0 {
+		return nil
+	}
+	s.alertState.mu.Lock()
+	defer s.alertState.mu.Unlock()
+
+	result := make([]string, 0, len(s.alertState.stale))
+	for symbol := range s.alertState.stale {
+		result = append(result, symbol)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// monitorFreshness 定期检查行情是否停滞，将停滞预警分发给交易该交易对的每个账户，同时发送通知；回测时不监控
+func (s *QuantSystem) monitorFreshness(ctx context.Context, out chan<- accountAlert) {
+	if s.cfg().RunMode() == configs.ModeBacktest {
+		return
+	}
+
+	s.supervise(ctx, "freshness_monitor", func(ctx context.Context) {
+		ticker := time.NewTicker(freshnessCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				for _, alert := range s.staleAlerts(now) {
+					log.Warn("market data stale, orders blocked", "symbol", alert.Symbol, "description", alert.Description)
+					s.notify(ctx, notify.Message{
+						Title: fmt.Sprintf("%s %s", alert.Symbol, alert.AlertType),
+						Text:  alert.Description,
+						Level: notify.LevelWarning,
+					})
+					for _, a := range s.accounts {
+						if !a.trades(alert.Symbol) {
+							continue
+						}
+						select {
+						case out <- accountAlert{account: a, alert: alert}:
+						case <-ctx.Done():
+							return
+						}
+					}
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/risk"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuantSystem_StaleMarketData(t *testing.T) {
+	ctx := context.Background()
+	system, _ := newTestSystem(t, map[string]float64{"USDT": 1000})
+	now := time.Now()
+	system.recordMarketUpdate("BTCUSDT")
+
+	// 未配置 stale_intervals 时不检查
+	assert.Empty(t, system.staleAlerts(now.Add(time.Hour)))
+
+	config := *system.cfg()
+	config.RefreshInterval = "10s"
+	config.AlertConfig.StaleIntervals = 3
+	system.config.Store(&config)
+
+	assert.Empty(t, system.staleAlerts(now.Add(20*time.Second)))
+	assert.False(t, system.symbolStale("BTCUSDT"))
+
+	// 超过 3 个刷新间隔未收到行情，预警一次并阻止下单
+	alerts := system.staleAlerts(now.Add(time.Minute))
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "BTCUSDT", alerts[0].Symbol)
+	assert.Equal(t, alertStaleMarketData, alerts[0].AlertType)
+	assert.Equal(t, risk.SeverityLow, alerts[0].Severity)
+	assert.Empty(t, system.staleAlerts(now.Add(2*time.Minute)))
+	assert.True(t, system.symbolStale("BTCUSDT"))
+
+	state, err := system.RiskState(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BTCUSDT"}, state.StaleSymbols)
+
+	// 收到行情后恢复
+	system.recordMarketUpdate("BTCUSDT")
+	assert.False(t, system.symbolStale("BTCUSDT"))
+	state, err = system.RiskState(ctx, "")
+	require.NoError(t, err)
+	assert.Empty(t, state.StaleSymbols)
+}
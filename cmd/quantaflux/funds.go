@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+)
+
+// downsizeOrder 开启 trading_config.auto_downsize 且下单因余额不足被拒绝时，按可用余额缩小订单，
+// 缩小后不低于最小交易量并通过风险评估时返回 true，由调用方重新下单
+func (s *QuantSystem) downsizeOrder(ctx context.Context, a *account, order *trading.Order, err error) bool {
+	var insufficient *trading.InsufficientFundsError
+	if !s.cfg().TradingConfig.AutoDownsize || !errors.As(err, &insufficient) {
+		return false
+	}
+	ratio := insufficient.Affordable()
+	if ratio <= 0 {
+		return false
+	}
+
+	resized := *order
+	resized.Amount = floorAmount(order.Amount * ratio)
+	resized.QuoteAmount = floorAmount(order.QuoteAmount * ratio)
+	resized.Status, resized.OrderID, resized.RawOrderID, resized.ClientOrderID = "", "", 0, ""
+
+	size := resized.Amount
+	if s.cfg().OrderAmountUnit() == configs.AmountUnitQuote {
+		size = resized.Value()
+	}
+	if size <= 0 || size < s.symbolSettings(order.Symbol).MinOrderAmount {
+		log.Info("order not downsized, affordable amount below minimum", "account", a.name, "symbol", order.Symbol,
+			"asset", insufficient.Asset, "available", insufficient.Available, "required", insufficient.Required)
+		return false
+	}
+	assessment, riskErr := s.checkTradeRisk(ctx, a, &resized)
+	if riskErr != nil || !assessment.IsAcceptable {
+		log.Info("order not downsized, risk assessment failed", "account", a.name, "symbol", order.Symbol, "err", riskErr)
+		return false
+	}
+
+	log.Info("order downsized to affordable amount", "account", a.name, "symbol", order.Symbol, "asset", insufficient.Asset,
+		"available", insufficient.Available, "amount", order.Amount, "downsized", resized.Amount, "quote_amount", resized.QuoteAmount)
+	*order = resized
+	return true
+}
+
+// floorAmount 将数量向下取整到 8 位小数，缩小后的订单不会超过可用余额
+func floorAmount(amount float64) float64 {
+	return math.Floor(amount*1e8) / 1e8
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/songzhibin97/quantaflux/internal/trading"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuantSystem_DownsizeOrder(t *testing.T) {
+	ctx := context.Background()
+	system, _ := newTestSystem(t, map[string]float64{"USDT": 5})
+	a := system.primaryAccount()
+	a.executor.(trading.MarketPriceUpdater).UpdateMarketPrice("BTCUSDT", 1)
+
+	order := &trading.Order{Account: a.name, Symbol: "BTCUSDT", Side: "buy", Amount: 8, Price: 1, OrderType: "market"}
+	err := a.executor.PlaceOrder(ctx, order)
+	require.ErrorIs(t, err, trading.ErrInsufficientFunds)
+
+	// 未开启时不缩小
+	assert.False(t, system.downsizeOrder(ctx, a, order, err))
+
+	config := *system.cfg()
+	config.TradingConfig.AutoDownsize = true
+	system.config.Store(&config)
+	require.True(t, system.downsizeOrder(ctx, a, order, err))
+	assert.Equal(t, 5.0, order.Amount)
+	require.NoError(t, a.executor.PlaceOrder(ctx, order))
+	assert.Equal(t, "FILLED", order.Status)
+
+	// 卖出按基础资产余额缩小
+	config.TradingConfig.MinOrderAmount = 2
+	system.config.Store(&config)
+	order = &trading.Order{Account: a.name, Symbol: "BTCUSDT", Side: "sell", Amount: 8, Price: 1, OrderType: "market"}
+	err = a.executor.PlaceOrder(ctx, order)
+	require.ErrorIs(t, err, trading.ErrInsufficientFunds)
+	assert.True(t, system.downsizeOrder(ctx, a, order, err))
+	assert.InDelta(t, 5, order.Amount, 1e-9)
+
+	// 缩小后低于最小交易量时不下单
+	err = &trading.InsufficientFundsError{Asset: "USDT", Available: 1, Required: 8}
+	assert.False(t, system.downsizeOrder(ctx, a, order, err))
+}
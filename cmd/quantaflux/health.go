@@ -0,0 +1,50 @@
+package main
+
+import (
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/ai"
+	"github.com/songzhibin97/quantaflux/internal/health"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+)
+
+// 行情超过刷新间隔的该倍数未更新即视为采集停滞
+const staleIntervals = 3
+
+// newHealthChecker 注册各依赖的健康检查
+func newHealthChecker(a *app) *health.Checker {
+	checker := health.NewChecker(0)
+
+	refreshInterval, err := time.ParseDuration(a.config.RefreshInterval)
+	if err != nil || refreshInterval <= 0 {
+		refreshInterval = time.Minute
+	}
+	maxAge := max(staleIntervals*refreshInterval, time.Minute)
+	checker.AddLiveness("collector", health.FreshnessCheck(a.system.lastMarketUpdate, maxAge))
+
+	checker.AddReadiness("database", health.PingCheck(a.storage))
+	checker.AddReadiness("connections", a.system.supervisor.Check)
+	for _, acc := range a.accounts {
+		if p, ok := trading.As[health.Pinger](acc.executor); ok {
+			name := "exchange"
+			if len(a.accounts) > 1 {
+				name = "exchange:" + acc.name
+			}
+			checker.AddReadiness(name, health.PingCheck(p))
+		}
+	}
+	// 健康检查不经过调用队列和故障注入，避免排在分析调用之后
+	analyzer := a.analyzer
+	for {
+		wrapper, ok := analyzer.(interface{ Unwrap() ai.Analyzer })
+		if !ok {
+			break
+		}
+		analyzer = wrapper.Unwrap()
+	}
+	if p, ok := analyzer.(health.Pinger); ok {
+		checker.AddReadiness("ai", health.PingCheck(p))
+	}
+
+	return checker
+}
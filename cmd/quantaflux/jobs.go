@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/analytics"
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/data/collector/binance"
+	"github.com/songzhibin97/quantaflux/internal/notify"
+	"github.com/songzhibin97/quantaflux/internal/scheduler"
+)
+
+// registerJobs 根据配置注册周期任务
+func registerJobs(sched *scheduler.Scheduler, a *app) error {
+	for _, job := range a.config.Jobs {
+		interval, err := time.ParseDuration(job.Interval)
+		if err != nil {
+			return fmt.Errorf("invalid interval for job %s: %w", job.Name, err)
+		}
+
+		var fn scheduler.JobFunc
+		switch job.Type {
+		case configs.JobAnalyzeProjects:
+			fn = a.analyzeProjects
+		case configs.JobPerformanceReport:
+			fn = func(ctx context.Context) error {
+				return a.performanceReport(ctx, interval)
+			}
+		case configs.JobRefreshTokenInfo:
+			fn = a.refreshTokenInfo
+		case configs.JobPnLReport:
+			fn = func(ctx context.Context) error {
+				return a.pnlReport(ctx, interval)
+			}
+		case configs.JobSyncOrders:
+			fn = a.system.syncOpenOrders
+		case configs.JobEquitySnapshot:
+			fn = a.system.snapshotEquity
+		case configs.JobRefreshExchangeInfo:
+			fn = a.system.refreshExchangeInfo
+		case configs.JobDiscoverTokens:
+			if a.config.RunMode() == configs.ModeBacktest {
+				log.Warn("token discovery is not available in backtest mode, job skipped", "job", job.Name)
+				continue
+			}
+			fn = a.discoverTokensJob(binance.NewBinanceDataSource())
+		case configs.JobDetectArbitrage:
+			if a.config.RunMode() == configs.ModeBacktest {
+				log.Warn("arbitrage detection is not available in backtest mode, job skipped", "job", job.Name)
+				continue
+			}
+			sources, _, err := buildSources(a.config, nil)
+			if err != nil {
+				return err
+			}
+			if fn, err = a.detectArbitrageJob(sources); err != nil {
+				return fmt.Errorf("invalid arbitrage config for job %s: %w", job.Name, err)
+			}
+		case configs.JobPruneData:
+			retention, err := time.ParseDuration(job.Retention)
+			if err != nil {
+				return fmt.Errorf("invalid retention for job %s: %w", job.Name, err)
+			}
+			fn = func(ctx context.Context) error {
+				return a.pruneData(ctx, retention)
+			}
+		case configs.JobArchiveData:
+			if a.config.RunMode() == configs.ModeBacktest {
+				log.Warn("archiving is not available in backtest mode, job skipped", "job", job.Name)
+				continue
+			}
+			retention, err := time.ParseDuration(job.Retention)
+			if err != nil {
+				return fmt.Errorf("invalid retention for job %s: %w", job.Name, err)
+			}
+			fn = func(ctx context.Context) error {
+				return a.archiveData(ctx, retention)
+			}
+		default:
+			return fmt.Errorf("unknown job type: %s", job.Type)
+		}
+
+		if err := sched.Register(job.Name, interval, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// analyzeProjects 对所有交易对重新执行项目分析并保存结果
+func (a *app) analyzeProjects(ctx context.Context) error {
+	var errs []error
+	for _, symbol := range a.system.cfg().Symbols {
+		tokenInfo, err := a.collector.CollectTokenInfo(ctx, symbol)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", symbol, err))
+			continue
+		}
+
+		if err := a.system.dataStorage.SaveTokenInfo(ctx, tokenInfo); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", symbol, err))
+			continue
+		}
+		a.system.observeTokenInfo(tokenInfo)
+
+		metrics, err := a.analyzer.AnalyzeProject(ctx, tokenInfo)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", symbol, err))
+			continue
+		}
+
+		metrics.UpdatedAt = time.Now()
+		if err := a.storage.SaveProjectMetrics(ctx, metrics); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", symbol, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// performanceReport 计算最近一个周期的绩效统计
+func (a *app) performanceReport(ctx context.Context, period time.Duration) error {
+	reads := a.storage.Analytics()
+	service := analytics.NewService(reads, reads, a.system.cfg().TradingConfig.FeeRate, a.system.cfg().RunMode())
+
+	end := time.Now()
+	report, err := service.Report(ctx, end.Add(-period), end)
+	if err != nil {
+		return err
+	}
+
+	log.Info("performance report",
+		"start", report.Start,
+		"end", report.End,
+		"total_return", report.TotalReturn,
+		"sharpe", report.Sharpe,
+		"sortino", report.Sortino,
+		"max_drawdown", report.MaxDrawdown,
+		"turnover", report.Turnover,
+		"fee_drag", report.FeeDrag,
+		"trade_count", report.TradeCount,
+	)
+	return nil
+}
+
+// pnlReport 生成最近一个周期的盈亏报告，保存后推送
+func (a *app) pnlReport(ctx context.Context, period time.Duration) error {
+	reads := a.storage.Analytics()
+	generator := analytics.NewReportGenerator(reads, reads, a.system.cfg().TradingConfig.FeeRate, a.config.RunMode()).
+		WithTags(a.system.universe)
+
+	end := time.Now()
+	report, err := generator.Generate(ctx, reportPeriod(period), end.Add(-period), end)
+	if err != nil {
+		return err
+	}
+
+	if err := a.storage.SavePnLReport(ctx, report); err != nil {
+		return err
+	}
+
+	return a.notifier.Notify(ctx, notify.Message{
+		Title: fmt.Sprintf("quantaflux %s pnl report", report.Period),
+		Text:  report.Summary(),
+		Level: notify.LevelInfo,
+	})
+}
+
+// reportPeriod 将任务间隔转换为报告周期名称
+func reportPeriod(interval time.Duration) string {
+	switch interval {
+	case 24 * time.Hour:
+		return analytics.PeriodDaily
+	case 7 * 24 * time.Hour:
+		return analytics.PeriodWeekly
+	default:
+		return interval.String()
+	}
+}
+
+// refreshTokenInfo 刷新所有交易对的代币信息
+func (a *app) refreshTokenInfo(ctx context.Context) error {
+	var errs []error
+	for _, symbol := range a.system.cfg().Symbols {
+		tokenInfo, err := a.collector.CollectTokenInfo(ctx, symbol)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", symbol, err))
+			continue
+		}
+
+		a.system.observeTokenInfo(tokenInfo)
+
+		if err := a.system.dataStorage.SaveTokenInfo(ctx, tokenInfo); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", symbol, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// pruneData 清理超过保留时长的行情数据
+func (a *app) pruneData(ctx context.Context, retention time.Duration) error {
+	n, err := a.storage.PruneMarketData(ctx, time.Now().Add(-retention))
+	if err != nil {
+		return err
+	}
+
+	log.Info("pruned market data", "rows", n, "retention", retention)
+	return nil
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// errStageTimeout 阶段超出耗时预算
+var errStageTimeout = errors.New("stage exceeded latency budget")
+
+// stageContext 按阶段的耗时预算设置超时，未配置预算时不限时
+func (s *QuantSystem) stageContext(ctx context.Context, stage string) (context.Context, context.CancelFunc) {
+	budget := s.cfg().StageBudget(stage)
+	if budget <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, budget)
+}
+
+// stageExpired 判断阶段是否超出预算并计数，调用方未响应 ctx 时同样按超时处理
+func (s *QuantSystem) stageExpired(stageCtx context.Context, stage, symbol string) bool {
+	if !errors.Is(stageCtx.Err(), context.DeadlineExceeded) {
+		return false
+	}
+	s.stageTimeouts.Inc(stage, symbol)
+	return true
+}
+
+// stageTimeoutError 返回阶段超时错误，附带配置的预算
+func (s *QuantSystem) stageTimeoutError(stage string) error {
+	return fmt.Errorf("%w: %s budget %s", errStageTimeout, stage, s.cfg().StageBudget(stage))
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/songzhibin97/quantaflux/internal/trading"
+)
+
+// trackOrder 记录未成交订单，用于关闭时撤单
+func (c *control) trackOrder(order trading.Order) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if trading.IsOpenStatus(order.Status) {
+		c.openOrders[order.OrderID] = order
+	} else {
+		delete(c.openOrders, order.OrderID)
+	}
+}
+
+func (c *control) openOrderList() []trading.Order {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	result := make([]trading.Order, 0, len(c.openOrders))
+	for _, order := range c.openOrders {
+		result = append(result, order)
+	}
+	return result
+}
+
+// reconcile 启动时从存储和交易所恢复运行状态、挂单与持仓
+func (s *QuantSystem) reconcile(ctx context.Context) error {
+	if err := s.restoreState(ctx); err != nil {
+		log.Error("Error restoring loop state", "err", err)
+	}
+
+	if s.tradeJournal != nil {
+		entries, err := s.tradeJournal.ListOpenTrades(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			// 同一数据库中其他运行模式的订单不属于本实例
+			if entry.Mode != "" && entry.Mode != s.cfg().RunMode() {
+				continue
+			}
+
+			a, err := s.account(entry.Order.Account)
+			if err != nil {
+				log.Error("Error reconciling order", "order_id", entry.Order.OrderID, "err", err)
+				continue
+			}
+
+			recorded := entry.Order
+			recorded.Account = a.name
+			order, err := s.syncOrder(ctx, a, recorded)
+			if err != nil {
+				log.Error("Error reconciling order", "symbol", recorded.Symbol, "order_id", recorded.OrderID, "err", err)
+				continue
+			}
+			log.Info("reconciled order", "account", a.name, "symbol", order.Symbol, "order_id", order.OrderID, "status", order.Status, "filled_amount", order.FilledAmount)
+		}
+	}
+
+	positions, err := s.Positions(ctx)
+	if err != nil {
+		return err
+	}
+	for _, pos := range positions {
+		log.Info("reconciled position", "account", pos.Account, "symbol", pos.Symbol, "amount", pos.Amount)
+	}
+
+	return nil
+}
+
+// shutdown 按顺序关闭系统：按配置撤销挂单，然后由调用方关闭存储
+func (s *QuantSystem) shutdown(ctx context.Context) error {
+	if !s.cfg().ShutdownConfig.CancelOpenOrders {
+		return nil
+	}
+
+	var errs []error
+	for _, order := range s.openOrderList() {
+		a, err := s.account(order.Account)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		err = a.executor.CancelOrder(ctx, order.Symbol, order.OrderID)
+		s.auditCancel(ctx, order, "shutdown", err)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		order.Status = "CANCELED"
+		s.trackOrder(order)
+		if s.tradeJournal != nil {
+			if err := s.tradeJournal.UpdateOrderStatus(ctx, order.Account, order.Symbol, order.OrderID, order.Status); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		log.Info("canceled open order on shutdown", "account", a.name, "symbol", order.Symbol, "order_id", order.OrderID)
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/onchain"
+	"github.com/songzhibin97/quantaflux/internal/risk"
+)
+
+// buildLiquidityMonitor 根据配置创建链上流动性池监控，未配置检查间隔或处于回测模式时返回 nil
+func buildLiquidityMonitor(config *configs.Config, system *QuantSystem) *risk.LiquidityMonitor {
+	liquidity := config.LiquidityConfig
+	if liquidity.Interval == "" || config.RunMode() == configs.ModeBacktest {
+		return nil
+	}
+
+	networks := make(map[string]onchain.Network, len(liquidity.Networks))
+	for name, network := range liquidity.Networks {
+		networks[name] = onchain.Network{
+			Client:     onchain.NewClient(network.RPCURL),
+			Factory:    network.Factory,
+			QuoteToken: network.QuoteToken,
+			Lockers:    network.Lockers,
+		}
+	}
+	return risk.NewLiquidityMonitor(onchain.NewPairSource(networks), system.liquidityTokens,
+		liquidity.MaxLiquidityDrop, liquidity.MinLockedRatio)
+}
+
+// liquidityTokens 返回当前交易的、配置了代币合约的交易对
+func (s *QuantSystem) liquidityTokens() []risk.LiquidityToken {
+	config := s.cfg()
+	var tokens []risk.LiquidityToken
+	for _, symbol := range config.Symbols {
+		contract, ok := config.LiquidityConfig.Tokens[symbol]
+		if !ok {
+			continue
+		}
+		tokens = append(tokens, risk.LiquidityToken{
+			Symbol:          symbol,
+			Network:         contract.Network,
+			ContractAddress: contract.ContractAddress,
+		})
+	}
+	return tokens
+}
+
+// monitorLiquidity 将流动性预警分发给交易该交易对的每个账户，由主循环按严重程度处理；
+// 流动性撤出时按配置触发重新分析
+func (s *QuantSystem) monitorLiquidity(ctx context.Context, out chan<- accountAlert) {
+	if s.liquidity == nil {
+		return
+	}
+
+	interval, err := time.ParseDuration(s.cfg().LiquidityConfig.Interval)
+	if err != nil {
+		return
+	}
+
+	alerts := s.liquidity.Monitor(ctx, interval)
+	s.supervise(ctx, "liquidity_monitor", func(ctx context.Context) {
+		for alert := range alerts {
+			if alert.AlertType == risk.AlertLiquidityWithdrawn && s.cfg().AIConfig.Reanalysis.LiquidityDrop {
+				s.triggerReanalysis(alert.Symbol, eventLiquidityDrop, alert.Description)
+			}
+			for _, a := range s.accounts {
+				if !a.trades(alert.Symbol) {
+					continue
+				}
+				select {
+				case out <- accountAlert{account: a, alert: alert}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"log/slog"
+
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/logging"
+)
+
+// logs 按模块设置级别的日志工厂，由 setupLogging 根据配置创建；为空时各模块使用 log
+var logs *logging.Loggers
+
+// setupLogging 按 log_config 替换默认日志，返回的函数用于关闭日志文件
+func setupLogging(config *configs.Config) (func(), error) {
+	loggers, err := logging.New(logging.Options{
+		Level:      config.LogConfig.Level,
+		Format:     config.LogConfig.Format,
+		Output:     config.LogConfig.Output,
+		MaxSizeMB:  config.LogConfig.MaxSizeMB,
+		MaxBackups: config.LogConfig.MaxBackups,
+		Modules:    config.LogConfig.Modules,
+		AddSource:  true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logs = loggers
+	log = loggers.Logger()
+	return func() {
+		if err := loggers.Close(); err != nil {
+			log.Error("Error closing log file", "err", err)
+		}
+	}, nil
+}
+
+// moduleLog 返回传给各组件构造函数的模块日志
+func moduleLog(module string) *slog.Logger {
+	if logs == nil {
+		return log
+	}
+	return logs.Module(module)
+}
@@ -2,37 +2,140 @@ package main
 
 import (
 	"context"
-	"encoding/json"
-	"flag"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 
+	"github.com/songzhibin97/quantaflux/internal/data/candle"
 	"github.com/songzhibin97/quantaflux/internal/data/collector/binance"
+	"github.com/songzhibin97/quantaflux/internal/data/collector/replay"
 
 	collectorData "github.com/songzhibin97/quantaflux/internal/data/collector"
 
-	"github.com/songzhibin97/quantaflux/internal/ai/deepseek"
 
 	"github.com/songzhibin97/quantaflux/internal/data/storage"
-	binanceTrading "github.com/songzhibin97/quantaflux/internal/trading/binance"
 
+	"github.com/songzhibin97/quantaflux/internal/abtest"
 	"github.com/songzhibin97/quantaflux/internal/ai"
+	"github.com/songzhibin97/quantaflux/internal/analytics"
+	"github.com/songzhibin97/quantaflux/internal/api"
+	"github.com/songzhibin97/quantaflux/internal/audit"
+	"github.com/songzhibin97/quantaflux/internal/auth"
+	"github.com/songzhibin97/quantaflux/internal/bot"
+	"github.com/songzhibin97/quantaflux/internal/calibration"
+	"github.com/songzhibin97/quantaflux/internal/chaos"
+	"github.com/songzhibin97/quantaflux/internal/clock"
 	"github.com/songzhibin97/quantaflux/internal/configs"
 	"github.com/songzhibin97/quantaflux/internal/data"
+	"github.com/songzhibin97/quantaflux/internal/exchangeinfo"
+	"github.com/songzhibin97/quantaflux/internal/grpcapi"
+	"github.com/songzhibin97/quantaflux/internal/journal"
+	"github.com/songzhibin97/quantaflux/internal/metrics"
 	"github.com/songzhibin97/quantaflux/internal/models"
+	"github.com/songzhibin97/quantaflux/internal/notify"
+	"github.com/songzhibin97/quantaflux/internal/orderbook"
+	"github.com/songzhibin97/quantaflux/internal/params"
+	"github.com/songzhibin97/quantaflux/internal/pipeline"
+	"github.com/songzhibin97/quantaflux/internal/precision"
+	"github.com/songzhibin97/quantaflux/internal/regime"
 	"github.com/songzhibin97/quantaflux/internal/risk"
+	"github.com/songzhibin97/quantaflux/internal/scheduler"
+	"github.com/songzhibin97/quantaflux/internal/sentiment"
+	"github.com/songzhibin97/quantaflux/internal/social"
+	"github.com/songzhibin97/quantaflux/internal/state"
+	"github.com/songzhibin97/quantaflux/internal/supervisor"
+	"github.com/songzhibin97/quantaflux/internal/tracing"
 	"github.com/songzhibin97/quantaflux/internal/trading"
+	"github.com/songzhibin97/quantaflux/internal/universe"
+	"github.com/songzhibin97/quantaflux/internal/volatility"
+	"github.com/songzhibin97/quantaflux/internal/whale"
 )
 
 type QuantSystem struct {
-	config        *configs.Config
-	dataCollector data.DataCollector
-	dataStorage   data.DataStorage
-	aiAnalyzer    ai.Analyzer
-	riskManager   risk.RiskManager
-	tradeExecutor trading.TradeExecutor
+	*control
+
+	config           atomic.Pointer[configs.Config]
+	configMu         sync.Mutex      // 串行化热加载和 API 对配置的修改
+	fileConfig       *configs.Config // 最近一次从配置文件加载的配置，用于判断文件中的风险参数是否变化
+	promoted         []string        // 代币发现加入的交易对，热加载时保留，由 configMu 保护
+	dataCollector    data.DataCollector
+	dataStorage      data.DataStorage
+	aiAnalyzer       ai.Analyzer
+	challenger       ai.Analyzer             // A/B 对比的挑战者分析器，为空时不对比
+	calibrator       *calibration.Calibrator // 置信度校准，未启用时为空
+	volatility       *volatility.Service     // 历史波动率和 ATR，未启用时为空
+	regime           *regime.Scaler          // 按波动率和回撤缩放新订单的风险限额，未启用时为空
+	pairs            []*pairTrader           // 配对交易策略
+	abtests          abtest.Store            // 冠军和挑战者的决策记录
+	accounts         []*account
+	tradeJournal     journal.TradeJournal
+	equity           analytics.EquityStorage // 权益快照存储，为空时不保存
+	snapshots        state.SnapshotStore     // 系统状态快照存储，为空时不支持快照
+	reloadCh         chan struct{}           // 交易对列表变更时通知主循环重新订阅
+	fatalCh          chan error              // 致命错误通知主循环退出
+	liquidity        *risk.LiquidityMonitor  // 链上流动性池监控，为空时不监控
+	whales           *whale.Tracker          // 大额转账追踪，为空时不追踪
+	orderBooks       *orderbook.Tracker      // 盘口深度信号，为空时不拉取
+	trends           *trendTracker           // 趋势过滤周期的最近价格
+	sentiments       sentiment.Store         // 情绪分数存储，为空时只在内存中统计
+	socials          data.SocialStore        // 原始社交指标快照存储，回测时为空
+	sentimentHistory *sentimentTracker       // 统计窗口内的情绪分数
+	scams            *scamCache              // 各交易对最近一次诈骗检测的结论
+	reanalysis       *reanalysisTrigger      // 重大事件触发的重新分析
+	wallets          *walletState            // 交易所维护和资产充提暂停状态
+	spend            *spendState             // 全局下单名义金额的时间窗口统计
+	exits            *exitState              // 各持仓的分批止盈进度
+	supervisor       *supervisor.Supervisor  // 行情推送和后台监控的重连管理
+	analyzing        *aiTicks                // 正在进行 AI 分析的行情，新行情到达时可取消
+	projects         projectMetricsStore     // 项目分析结果存储，为空时不保存
+	eventFilter      *eventFilter            // 各交易对上次分析的行情，用于过滤价格变化过小的行情
+	exchangeInfo     *exchangeinfo.Service   // 交易所元数据缓存，回测时为空
+	universe         *universe.Service       // 品种池的元数据和标签，为空时交易对没有标签
+	params           *params.Registry        // 运行时修改的阈值，回测时为空
+	statusAlerts     chan risk.RiskAlert     // 交易对暂停交易或下架的预警
+	alertState       *alertState             // 内置告警依赖的运行统计
+	tracer           *tracing.Tracer
+	traces           *tracing.Recorder
+	scheduler        *scheduler.Scheduler
+	socialScorer     *social.Scorer // 按 social_score_config 计算社交分数
+	clock            clock.Clock // 回测时为按回放行情时间推进的模拟时钟
+
+	candleMu sync.Mutex
+	candles  *candle.Aggregator // 按 K 线收盘触发策略时的行情聚合
+
+	metrics       *metrics.Registry
+	stageTimeouts *metrics.Counter // 各阶段超出耗时预算的次数
+	staleTicks    *metrics.Counter // 合并排队行情时跳过的过期行情数量
+	filteredTicks *metrics.Counter // 价格变化过小未分析的行情数量
+	streamEvents  *metrics.Counter // 事件流发布的事件数量，按发布结果区分
+	scamChecks    *metrics.Counter // 诈骗检测次数，按实际检测和沿用缓存结论区分
+	divergences   *metrics.Counter // 数据源报价偏离共识价格的次数
+	storageErrors *metrics.Counter // 双写存储中各后端读写失败的次数
+	spendLimited  *metrics.Counter // 超过下单金额上限被拒绝的订单数量，按时间窗口区分
+	chaosFaults   *metrics.Counter // 故障注入产生的失败次数，按组件区分
+	connectionUp  *metrics.Gauge   // 各长连接组件是否处于连接状态
+	reconnects    *metrics.Counter // 各长连接组件断开后重启的次数
+	bookImbalance *metrics.Gauge   // 最近一次盘口深度的买卖盘失衡度
+	bookSpread    *metrics.Gauge   // 最近一次盘口深度的买一卖一价差（基点）
+	execCalls     *metrics.Counter // 执行器调用次数，按账户、操作和结果区分
+	execLatency   *metrics.Gauge   // 各账户最近一次执行器调用的耗时
+	clockOffset   *metrics.Gauge   // 各账户本地时钟相对交易所服务器时间的偏差
+	clockDrift    *metrics.Counter // 时钟偏差超过阈值的次数
+	drawdown      *metrics.Gauge   // 最近一次权益快照相对峰值的回撤
+	riskScale     *metrics.Gauge   // 新订单最大仓位和最大交易量的缩放比例
+	accountEquity *metrics.Gauge   // 最近一次权益快照中各账户的权益
+	dataAge       *metrics.Gauge   // 各交易对距最近一次收到行情的时长
+	aiFailures    *metrics.Gauge   // AI 调用连续失败次数
+	orderResults  *metrics.Counter // 下单结果，按成功、被拒绝和其他失败区分
+	rejectRatio   *metrics.Gauge   // 最近 reject_window 笔下单的拒单比例
+	alertFiring   *metrics.Gauge   // 各内置告警是否正在告警
 }
 
 func NewQuantSystem(
@@ -40,179 +143,653 @@ func NewQuantSystem(
 	collector data.DataCollector,
 	storage data.DataStorage,
 	analyzer ai.Analyzer,
-	riskMgr risk.RiskManager,
-	executor trading.TradeExecutor,
+	accounts []*account,
+	tradeJournal journal.TradeJournal,
+	stateStore state.Store,
 ) *QuantSystem {
-	return &QuantSystem{
-		config:        config,
-		dataCollector: collector,
-		dataStorage:   storage,
-		aiAnalyzer:    analyzer,
-		riskManager:   riskMgr,
-		tradeExecutor: executor,
+	s := &QuantSystem{
+		control:          newControl(stateStore),
+		reloadCh:         make(chan struct{}, 1),
+		trends:           newTrendTracker(),
+		sentimentHistory: newSentimentTracker(),
+		scams:            newScamCache(),
+		reanalysis:       newReanalysisTrigger(),
+		analyzing:        newAITicks(),
+		eventFilter:      newEventFilter(),
+		statusAlerts:     make(chan risk.RiskAlert, 100),
+		alertState:       newAlertState(),
+		wallets:          newWalletState(),
+		spend:            newSpendState(),
+		exits:            newExitState(),
+		fatalCh:          make(chan error, 1),
+		dataCollector:    collector,
+		dataStorage:      storage,
+		aiAnalyzer:       analyzer,
+		accounts:         accounts,
+		tradeJournal:     tradeJournal,
+		clock:            clock.Real(),
+		socialScorer:     social.NewScorer(config.SocialScoreConfig.Options()),
 	}
+	s.config.Store(config)
+	s.fileConfig = config
+	if config.AIConfig.Calibration.Enabled() {
+		s.calibrator = calibration.New(config.AIConfig.Calibration.Options())
+	}
+	if config.VolatilityConfig.Enabled() {
+		s.volatility = volatility.NewService(storage, config.VolatilityConfig.Options())
+		for _, a := range accounts {
+			a.vaR = config.VolatilityConfig.VaR(s.volatility)
+			if rm, ok := a.riskManager.(*risk.BasicRiskManager); ok {
+				rm.SetVaR(a.vaR)
+			}
+		}
+	}
+
+	if config.RegimeConfig.Enabled() {
+		s.regime = regime.NewScaler(config.RegimeConfig.Options())
+	}
+
+	s.metrics = metrics.NewRegistry()
+	s.stageTimeouts = s.metrics.NewCounter("quantaflux_stage_timeouts_total",
+		"Number of times a tick stage exceeded its latency budget.", "stage", "symbol")
+	s.staleTicks = s.metrics.NewCounter("quantaflux_stale_ticks_skipped_total",
+		"Number of queued ticks whose analysis was skipped because a newer tick of the same symbol arrived.", "symbol")
+	s.divergences = s.metrics.NewCounter("quantaflux_price_divergences_total",
+		"Number of quotes whose price deviated from the cross-source consensus beyond max_deviation.", "symbol", "source")
+	s.storageErrors = s.metrics.NewCounter("quantaflux_storage_mirror_errors_total",
+		"Number of failed reads and writes of each backend when database.secondary_conn_str is set.", "backend", "op")
+	s.chaosFaults = s.metrics.NewCounter("quantaflux_chaos_faults_total",
+		"Number of failures injected by chaos_config in paper mode.", "target")
+	s.connectionUp = s.metrics.NewGauge("quantaflux_connection_up",
+		"Whether the supervised component (kline stream, monitors) is connected.", "component")
+	s.reconnects = s.metrics.NewCounter("quantaflux_connection_restarts_total",
+		"Number of times a supervised component disconnected and was restarted with backoff.", "component")
+	s.bookImbalance = s.metrics.NewGauge("quantaflux_order_book_imbalance",
+		"Bid/ask notional imbalance within order_book_config.band at the last order book poll, from -1 (ask-heavy) to 1.", "symbol")
+	s.bookSpread = s.metrics.NewGauge("quantaflux_order_book_spread_bps",
+		"Best bid/ask spread in basis points of the mid price at the last order book poll.", "symbol")
+	s.execCalls = s.metrics.NewCounter("quantaflux_executor_calls_total",
+		"Number of executor calls by account, op (place_order, cancel_order, get_order_status, get_balance) and result (ok, error).", "account", "op", "result")
+	s.execLatency = s.metrics.NewGauge("quantaflux_executor_latency_seconds",
+		"Duration of the last executor call of the account and op.", "account", "op")
+	s.spendLimited = s.metrics.NewCounter("quantaflux_spend_limit_rejections_total",
+		"Number of orders rejected because trading_config.spend_limit was reached within the window.", "window")
+	s.filteredTicks = s.metrics.NewCounter("quantaflux_filtered_ticks_total",
+		"Number of ticks not analyzed because the price moved less than event_filter_config.min_price_change since the last analysis.", "symbol")
+	s.streamEvents = s.metrics.NewCounter("quantaflux_stream_events_total",
+		"Number of events sent to the NATS/Kafka event stream by result (published, dropped, failed).", "result")
+	s.scamChecks = s.metrics.NewCounter("quantaflux_scam_checks_total",
+		"Number of scam verdicts by whether the analyzer was called or a cached verdict was reused.", "symbol", "result")
+	s.drawdown = s.metrics.NewGauge("quantaflux_equity_drawdown_ratio",
+		"Equity drawdown from the peak within drawdown_config.window at the last equity snapshot.")
+	s.riskScale = s.metrics.NewGauge("quantaflux_risk_limit_scale",
+		"Scale applied to max_position_size and max_order_amount of new orders by regime_config, 1 when not scaled.")
+	s.riskScale.Set(1)
+	s.accountEquity = s.metrics.NewGauge("quantaflux_account_equity",
+		"Equity of the account at the last equity snapshot.", "account")
+	s.clockOffset = s.metrics.NewGauge("quantaflux_exchange_clock_offset_seconds",
+		"Local clock minus exchange server time at the last sync.", "account")
+	s.clockDrift = s.metrics.NewCounter("quantaflux_exchange_clock_drift_warnings_total",
+		"Number of time syncs whose clock offset exceeded max_drift.", "account")
+	s.dataAge = s.metrics.NewGauge("quantaflux_market_data_age_seconds",
+		"Seconds since the last market data of the symbol was received, updated every alert_config.interval.", "symbol")
+	s.aiFailures = s.metrics.NewGauge("quantaflux_ai_consecutive_failures",
+		"Number of consecutive failed AI analyzer calls.")
+	s.orderResults = s.metrics.NewCounter("quantaflux_order_results_total",
+		"Number of order placements by account and result (accepted, rejected, failed).", "account", "result")
+	s.rejectRatio = s.metrics.NewGauge("quantaflux_order_reject_ratio",
+		"Ratio of orders rejected by the exchange among the last alert_config.reject_window orders.")
+	s.alertFiring = s.metrics.NewGauge("quantaflux_alert_firing",
+		"Whether the built-in alert is firing (1) or not (0).", "alert")
+	for _, name := range []string{alertStaleData, alertAIFailures, alertOrderRejects} {
+		s.alertFiring.Set(0, name)
+	}
+	s.supervisor = supervisor.New(config.SupervisorConfig.Backoff())
+	s.supervisor.OnStateChange = s.connectionChanged
+	return s
+}
+
+// pipelineOptions 返回行情处理流水线的配置。回测时在回放循环中直接处理每条行情，
+// 不经过各交易对的队列，所有交易对严格按回放顺序处理，结果可复现
+func (s *QuantSystem) pipelineOptions() pipeline.Options {
+	pipelineConfig := s.cfg().PipelineConfig
+	opts := pipeline.Options{
+		Concurrency: pipelineConfig.Concurrency,
+		QueueSize:   pipelineConfig.QueueSize,
+		Coalesce:    pipelineConfig.Coalesce,
+	}
+	if s.cfg().RunMode() == configs.ModeBacktest {
+		opts.Inline = true
+		opts.Coalesce = false
+	}
+	return opts
+}
+
+// cfg 返回当前生效的配置，热加载时会被整体替换
+func (s *QuantSystem) cfg() *configs.Config {
+	return s.config.Load()
 }
 
 // Run 运行量化系统
 func (s *QuantSystem) Run(ctx context.Context) error {
-	// 设置风险参数
-	if err := s.riskManager.SetRiskParameters(ctx, &s.config.RiskParams); err != nil {
-		return err
+	// 设置各账户风险参数
+	for _, a := range s.accounts {
+		if err := a.riskManager.SetRiskParameters(ctx, s.riskParamsFor(a)); err != nil {
+			return fmt.Errorf("failed to set risk parameters of account %s: %w", a.name, err)
+		}
 	}
 	log.Debug("set risk parameters ok!")
 
-	refreshInterval, err := time.ParseDuration(s.config.RefreshInterval)
-	if err != nil {
-		refreshInterval = time.Second * 10
-	}
+	s.loadVolatility(ctx)
 
 	// 订阅市场数据
-	marketDataCh, err := s.dataCollector.SubscribeToMarketData(ctx, s.config.Symbols, refreshInterval)
+	marketDataCh, unsubscribe, err := s.subscribe(ctx)
 	if err != nil {
 		return err
 	}
+	defer func() { unsubscribe() }()
 
 	log.Debug("subscribe to market data ok!")
 
 	// 监控风险预警
-	riskAlertCh, err := s.riskManager.MonitorPositions(ctx)
+	riskAlertCh, err := s.monitorAccounts(ctx)
 	if err != nil {
 		return err
 	}
 
 	log.Debug("monitor positions ok!")
 
+	s.runReanalysis(ctx)
+	s.restorePairs(ctx)
+	s.restoreBrackets(ctx)
+	s.monitorOrderBook(ctx)
+
+	// 每个交易对独立顺序处理，整体并发受限
+	workers := pipeline.New(s.processMarketData, s.pipelineOptions(), moduleLog("pipeline"))
+	defer workers.Close()
+
 	// 主循环
 	for {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
 
-		case marketData := <-marketDataCh:
-			log.Debug("Received market data", "market", marketData)
+		case err := <-s.fatalCh:
+			return err
 
-			if err := s.handleMarketData(ctx, marketData); err != nil {
-				log.Error("Error handling market data", "err", err)
+		case marketData, ok := <-marketDataCh:
+			if !ok {
+				// 回测模式下历史数据回放完毕
+				log.Info("market data stream closed")
+				return nil
 			}
+			log.Debug("Received market data", "market", marketData)
+			s.supersedeTick(marketData)
+			workers.Dispatch(ctx, marketData)
+
+		case <-s.reloadCh:
+			// 交易对列表变更，重新订阅行情
+			unsubscribe()
+			marketDataCh, unsubscribe, err = s.subscribe(ctx)
+			if err != nil {
+				return err
+			}
+			log.Info("resubscribed to market data", "symbols", s.cfg().Symbols)
 
-		case alert := <-riskAlertCh:
-			log.Debug("Received risk alert: %+v\n", alert)
+		case aa := <-riskAlertCh:
+			alert := aa.alert
+			log.Debug("Received risk alert", "account", aa.account.name, "alert", alert)
+			s.recordAlert(alert)
 
-			if err := s.handleRiskAlert(ctx, alert); err != nil {
-				log.Error("Error handling risk alert", "err", err)
+			_ = s.handleError(s.handleRiskAlert(ctx, aa.account, alert), "account", aa.account.name, "symbol", alert.Symbol, "alert", alert.AlertType)
+		}
+	}
+}
+
+// subscribe 按当前配置订阅行情，返回的取消函数用于停止该订阅
+func (s *QuantSystem) subscribe(ctx context.Context) (<-chan models.MarketData, context.CancelFunc, error) {
+	subCtx, cancel := context.WithCancel(ctx)
+	marketDataCh, err := s.dataCollector.SubscribeToMarketData(subCtx, subscriptions(s.cfg(), s.symbolTags))
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+	return marketDataCh, cancel, nil
+}
+
+// subscriptions 返回每个交易对按生效刷新间隔的行情订阅，以及趋势过滤周期的订阅，保持配置中的交易对顺序；
+// tags 返回交易对在品种池中的标签，为空时不应用 tag_overrides。
+// 回测时行情按历史数据回放，刷新间隔只用于区分趋势周期
+func subscriptions(config *configs.Config, tags func(symbol string) []string) []data.Subscription {
+	var subs []data.Subscription
+	for _, symbol := range config.Symbols {
+		var symbolTags []string
+		if tags != nil {
+			symbolTags = tags(symbol)
+		}
+		interval := refreshInterval(config, symbol, symbolTags...)
+		subs = append(subs, data.Subscription{Symbol: symbol, Interval: interval})
+
+		for _, timeframe := range config.ForSymbol(symbol, symbolTags...).TrendTimeframes {
+			d, err := time.ParseDuration(timeframe)
+			if err != nil || d == interval {
+				continue
 			}
+			subs = append(subs, data.Subscription{Symbol: symbol, Interval: d})
 		}
 	}
+	return subs
+}
+
+// refreshInterval 返回交易对生效的刷新间隔，未配置或无效时为 10s
+func refreshInterval(config *configs.Config, symbol string, tags ...string) time.Duration {
+	interval, err := time.ParseDuration(config.ForSymbol(symbol, tags...).RefreshInterval)
+	if err != nil || interval <= 0 {
+		return time.Second * 10
+	}
+	return interval
 }
 
 // handleMarketData 处理市场数据
 func (s *QuantSystem) handleMarketData(ctx context.Context, data models.MarketData) error {
-	// 1. 保存市场数据
-	if err := s.dataStorage.SaveMarketData(ctx, &data); err != nil {
-		return err
+	s.recordMarketUpdate(data.Symbol)
+
+	// 趋势周期的行情只用于趋势过滤，不触发交易
+	if s.isTrendUpdate(data) {
+		s.trends.update(data)
+		return nil
+	}
+
+	// 跳过崩溃前已处理过的行情
+	if !s.markTick(data) {
+		log.Debug("skip already processed market data", "symbol", data.Symbol, "timestamp", data.Timestamp)
+		return nil
+	}
+	s.persistState(ctx)
+
+	s.updateMarketData(data)
+	s.reviewDisabled(ctx, data.Symbol, data.Timestamp)
+	if s.calibrator != nil {
+		s.calibrator.Observe(data.Symbol, data.Timestamp, data.Price)
+	}
+	if s.volatility != nil {
+		if _, ok := s.volatility.Observe(data); ok {
+			s.observeVolatilityRegime(ctx, data.Timestamp)
+		}
+	}
+
+	// 模拟撮合需要最新价格，价格变化后挂单可能成交
+	simulated := make(map[string]bool)
+	for _, a := range s.accounts {
+		if updater, ok := trading.As[trading.MarketPriceUpdater](a.executor); ok {
+			updater.UpdateMarketPrice(data.Symbol, data.Price)
+			simulated[a.name] = true
+		}
+	}
+	if len(simulated) > 0 {
+		err := s.syncOrders(ctx, func(order trading.Order) bool {
+			return order.Symbol == data.Symbol && simulated[order.Account]
+		})
+		if err != nil {
+			log.Error("Error syncing simulated orders", "symbol", data.Symbol, "err", err)
+		}
+	}
+	s.runBrackets(ctx, data, simulated)
+	s.runPairs(ctx, data)
+
+	// 1. 保存市场数据（回测模式下数据本身来自存储，无需重复保存）
+	if s.cfg().RunMode() != configs.ModeBacktest {
+		spanCtx, span := s.tracer.Start(ctx, "storage.save_market_data")
+		err := s.dataStorage.SaveMarketData(spanCtx, &data)
+		span.RecordError(err)
+		span.End()
+		if err != nil {
+			return err
+		}
+	}
+
+	// 按 K 线触发时，只在 K 线收盘时继续分析和交易
+	if !s.candleTrigger(data) {
+		return nil
+	}
+
+	// 流水线队列中已有同一交易对更新的行情时不再分析，以上的行情保存和模拟撮合不受影响
+	if pipeline.Superseded(ctx) {
+		s.staleTicks.Inc(data.Symbol)
+		log.Debug("skip analysis of superseded market data", "symbol", data.Symbol, "timestamp", data.Timestamp)
+		return nil
+	}
+
+	// 价格相对上次分析变化过小时不再分析，以上的行情保存和模拟撮合不受影响
+	if !s.eventFilter.allow(data, s.cfg().EventFilterConfig) {
+		s.filteredTicks.Inc(data.Symbol)
+		log.Debug("skip quiet market data", "symbol", data.Symbol, "price", data.Price)
+		return nil
 	}
 
 	// 2. 收集token信息和社交指标
-	tokenInfo, err := s.dataCollector.CollectTokenInfo(ctx, data.Symbol)
+	spanCtx, span := s.tracer.Start(ctx, "collector.token_info")
+	tokenInfo, err := s.dataCollector.CollectTokenInfo(spanCtx, data.Symbol)
+	span.RecordError(err)
+	span.End()
 	if err != nil {
 		return err
 	}
+	s.observeTokenInfo(tokenInfo)
 
-	socialMetrics, err := s.dataCollector.CollectSocialMetrics(ctx, data.Symbol)
+	spanCtx, span = s.tracer.Start(ctx, "collector.social_metrics")
+	socialMetrics, err := s.dataCollector.CollectSocialMetrics(spanCtx, data.Symbol)
+	span.RecordError(err)
+	span.End()
 	if err != nil {
 		return err
 	}
+	s.observeSocial(data.Symbol, socialMetrics)
+	socialScore := s.socialScorer.Score(data.Symbol, socialMetrics)
+	s.recordSocial(ctx, data.Symbol, socialMetrics, socialScore, data.Timestamp)
 
+	// AI 分析整体受耗时预算约束，超时说明价格可能已过期，跳过该条行情
+	aiCtx, cancelAI := s.aiStageContext(ctx, data)
+	defer cancelAI()
+
+	var scamProbability float64
 	if len(socialMetrics) != 0 {
 		// 3. 构建项目指标用于AI分析
 		projectMetrics := &models.ProjectMetrics{
 			TokenInfo: *tokenInfo,
-			// 计算社交分数（可以根据需要调整计算方法）
-			SocialScore: calculateSocialScore(socialMetrics),
+			SocialScore: socialScore.Total,
 			// 其他指标可以根据需要添加
 			UpdatedAt: time.Now(),
 		}
 
-		// 4. 进行诈骗检测
-		scamAnalysis, err := s.aiAnalyzer.DetectScam(ctx, projectMetrics)
+		// 4. 进行诈骗检测，按复查策略复用近期结论
+		spanCtx, span := s.tracer.Start(aiCtx, "ai.detect_scam")
+		scamAnalysis, err := s.detectScam(spanCtx, data, projectMetrics)
+		span.RecordError(err)
+		span.End()
+		if s.stageExpired(aiCtx, configs.StageAI, data.Symbol) || s.tickSuperseded(aiCtx, data.Symbol) {
+			return s.skipStaleTick(aiCtx, data)
+		}
 		if err != nil {
 			return err
 		}
 
+		scamProbability = scamAnalysis.ScamProbability
+
 		// 如果诈骗可能性高于阈值，停止交易
-		if scamAnalysis.ScamProbability > s.config.AIConfig.ScamThreshold {
-			log.Warn("Warning: High scam probability detected for %s: %.2f", data.Symbol, scamAnalysis.ScamProbability)
+		if scamAnalysis.ScamProbability > s.cfg().AIConfig.ScamThreshold {
+			log.Warn("High scam probability detected", "symbol", data.Symbol, "probability", scamAnalysis.ScamProbability)
 			return nil
 		}
 	}
 
 	// 5. 分析市场情绪
-	sentiment, err := s.aiAnalyzer.AnalyzeSentiment(ctx, convertSocialMetricsToMap(socialMetrics))
+	spanCtx, span = s.tracer.Start(aiCtx, "ai.analyze_sentiment")
+	sentimentData := convertSocialMetricsToMap(socialMetrics)
+	for k, v := range convertSocialMetricsToMap(s.whaleMetrics(data.Symbol)) {
+		sentimentData[k] = v
+	}
+	sentiment, err := s.aiAnalyzer.AnalyzeSentiment(spanCtx, sentimentData)
+	span.RecordError(err)
+	s.recordAIResult(err)
+	span.End()
+	if s.stageExpired(aiCtx, configs.StageAI, data.Symbol) || s.tickSuperseded(aiCtx, data.Symbol) {
+		return s.skipStaleTick(aiCtx, data)
+	}
 	if err != nil {
 		return err
 	}
+	momentum := s.sentimentMomentum(ctx, data, sentiment)
 
 	// 如果市场情绪过于负面，可能需要调整策略
 	if sentiment < -0.5 { // 假设-1到1的范围，-0.5表示相当负面
-		log.Warn("Warning: Negative market sentiment for %s: %.2f\n", data.Symbol, sentiment)
+		log.Warn("Negative market sentiment", "symbol", data.Symbol, "sentiment", sentiment)
 		return nil
 	}
 
-	// 6. AI价格预测
-	prediction, err := s.aiAnalyzer.PredictPrice(ctx, []models.MarketData{data})
+	// 6. AI价格预测，附带近期行情作为趋势参考
+	window, stats, ok, err := s.predictionWindow(ctx, data)
 	if err != nil {
 		return err
 	}
+	if ok {
+		// 提示词使用完整历史的统计量，挑战者使用相同的统计量
+		aiCtx = ai.WithMarketStats(aiCtx, stats)
+		ctx = ai.WithMarketStats(ctx, stats)
+	}
+
+	spanCtx, span = s.tracer.Start(aiCtx, "ai.predict_price", "points", len(window))
+	prediction, err := s.aiAnalyzer.PredictPrice(spanCtx, window)
+	span.RecordError(err)
+	s.recordAIResult(err)
+	span.End()
+	if s.stageExpired(aiCtx, configs.StageAI, data.Symbol) || s.tickSuperseded(aiCtx, data.Symbol) {
+		return s.skipStaleTick(aiCtx, data)
+	}
+	if err != nil {
+		return err
+	}
+
+	s.calibrate(data, prediction)
+	s.recordPrediction(*prediction, data.Price)
+	s.runChallenger(ctx, data, window, prediction)
 
 	// 检查预测置信度
-	if prediction.Confidence < s.config.AIConfig.MinConfidence {
+	if prediction.Confidence < s.symbolSettings(data.Symbol).MinConfidence {
+		return nil
+	}
+
+	// 7. 每个交易该交易对的账户独立下单，单个账户失败不影响其他账户
+	side := s.determineOrderSide(prediction.PredictedPrice, data.Price)
+	if side == "" {
+		// 预测价格在容忍范围内，不交易
+		return nil
+	}
+	if timeframe := s.trendAgainst(data.Symbol, side); timeframe != "" {
+		log.Info("signal filtered by higher timeframe trend", "symbol", data.Symbol, "side", side, "timeframe", timeframe)
+		return nil
+	}
+	if reason := s.sentimentAgainst(side, momentum); reason != "" {
+		log.Info("signal filtered by sentiment momentum", "symbol", data.Symbol, "side", side, "reason", reason,
+			"change", momentum.Change, "divergence", momentum.Divergence)
+		return nil
+	}
+	if reason := s.orderBookAgainst(data.Symbol, side, time.Now()); reason != "" {
+		log.Info("signal filtered by order book", "symbol", data.Symbol, "side", side, "reason", reason)
 		return nil
 	}
 
-	// 7. 生成交易订单
+	signal := tradeSignal{
+		data:            data,
+		side:            side,
+		prediction:      prediction,
+		sentiment:       sentiment,
+		momentum:        momentum,
+		scamProbability: scamProbability,
+	}
+
+	var errs []error
+	for _, a := range s.accounts {
+		if !a.trades(data.Symbol) {
+			continue
+		}
+		if err := s.placeAccountOrder(ctx, a, signal); err != nil {
+			errs = append(errs, fmt.Errorf("account %s: %w", a.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// skipStaleTick AI 分析超出耗时预算或被更新的行情取代时跳过该条行情，不按过期价格交易
+func (s *QuantSystem) skipStaleTick(aiCtx context.Context, data models.MarketData) error {
+	if errors.Is(context.Cause(aiCtx), errTickSuperseded) {
+		log.Info("AI analysis cancelled by newer market data, tick skipped", "symbol", data.Symbol, "timestamp", data.Timestamp)
+		return nil
+	}
+	log.Warn("AI analysis exceeded latency budget, tick skipped", "symbol", data.Symbol, "budget", s.cfg().StageBudget(configs.StageAI), "timestamp", data.Timestamp)
+	return nil
+}
+
+// tradeSignal 一次行情分析得出的交易信号
+type tradeSignal struct {
+	data            models.MarketData
+	side            string
+	prediction      *ai.PricePrediction
+	sentiment       float64
+	momentum        sentiment.Momentum // 统计窗口内的情绪动量
+	scamProbability float64
+}
+
+// placeAccountOrder 按交易信号为账户生成订单，经风险评估后下单
+func (s *QuantSystem) placeAccountOrder(ctx context.Context, a *account, signal tradeSignal) error {
+	data, prediction := signal.data, signal.prediction
+
 	order := &trading.Order{
+		Account:   a.name,
 		Symbol:    data.Symbol,
-		Amount:    s.calculateOrderAmount(prediction.PredictedPrice, data.Price),
 		Price:     prediction.PredictedPrice,
-		OrderType: s.config.TradingConfig.OrderType,
-		Side:      s.determineOrderSide(prediction.PredictedPrice, data.Price),
+		OrderType: s.cfg().TradingConfig.OrderType,
+		Side:      signal.side,
+	}
+	s.sizeOrder(order, data.Price)
+	if order.OrderType == "limit" {
+		order.SubmittedPrice = order.Price
+	}
+
+	// 全局下单金额上限在风险评估之前检查，订单最终没有下单时归还额度
+	releaseSpend, err := s.reserveSpend(ctx, order, data.Price)
+	if err != nil {
+		return err
 	}
+	placed := false
+	defer func() {
+		if !placed {
+			releaseSpend()
+		}
+	}()
 
 	// 8. 风险评估
-	riskAssessment, err := s.riskManager.CheckTradeRisk(ctx, order)
+	riskCtx, cancelRisk := s.stageContext(ctx, configs.StageRisk)
+	spanCtx, span := s.tracer.Start(riskCtx, "risk.check_trade", "account", a.name)
+	riskAssessment, err := s.checkTradeRisk(spanCtx, a, order)
+	span.RecordError(err)
+	span.End()
+	riskExpired := s.stageExpired(riskCtx, configs.StageRisk, data.Symbol)
+	cancelRisk()
+	if riskExpired {
+		return s.stageTimeoutError(configs.StageRisk)
+	}
 	if err != nil {
 		return err
 	}
 
-	// 如果风险可接受，执行交易
-	if riskAssessment.IsAcceptable {
-		log.Debug("Risk assessment for %s: acceptable", data.Symbol)
-		return s.tradeExecutor.PlaceOrder(ctx, order)
+	if !riskAssessment.IsAcceptable {
+		log.Debug("AI预测结果", "account", a.name, "symbol", data.Symbol, "price", prediction.PredictedPrice, "confidence", prediction.Confidence)
+		return fmt.Errorf("%w: %s %s: %s", risk.ErrRiskRejected, order.Side, data.Symbol, strings.Join(riskAssessment.RiskFactors, "; "))
+	}
+
+	// 暂停时继续采集和分析，只抑制下单
+	if s.symbolPaused(data.Symbol) {
+		log.Info("order suppressed, trading paused", "account", a.name, "symbol", data.Symbol, "side", order.Side, "amount", order.Amount)
+		return nil
+	}
+	if status, halted := s.tradingHalted(data.Symbol, data.Timestamp); halted {
+		log.Info("order suppressed, symbol not trading", "account", a.name, "symbol", data.Symbol, "status", status, "side", order.Side, "amount", order.Amount)
+		return nil
 	}
+	if s.symbolStale(data.Symbol) {
+		log.Info("order suppressed, market data stale", "account", a.name, "symbol", data.Symbol, "side", order.Side, "amount", order.Amount)
+		return nil
+	}
+	if reason, blocked := s.walletBlocked(a, data.Symbol, order.Side); blocked {
+		log.Info("order suppressed, wallet unavailable", "account", a.name, "symbol", data.Symbol, "reason", reason, "side", order.Side, "amount", order.Amount)
+		return nil
+	}
+
+	// 9. 风险可接受，执行交易
+	log.Debug("Risk assessment acceptable", "account", a.name, "symbol", data.Symbol)
 
-	log.Debug("AI预测结果: Symbol=%s, 价格=%.2f, 置信度=%.2f", data.Symbol, prediction.PredictedPrice, prediction.Confidence)
+	// 下单前持久化下单意图，崩溃后可发现结果未知的订单
+	intentID := s.addIntent(*order)
+	s.persistState(ctx)
 
+	orderCtx, cancelOrder := s.orderStageContext(ctx, order.OrderType)
+	spanCtx, span = s.tracer.Start(governedContext(orderCtx), "trading.place_order", "account", a.name, "side", order.Side, "amount", order.Amount, "quote_amount", order.QuoteAmount)
+	err = s.submitOrder(spanCtx, a, order, signal)
+	if s.downsizeOrder(spanCtx, a, order, err) {
+		err = s.submitOrder(spanCtx, a, order, signal)
+	}
+	span.RecordError(err)
+	span.End()
+	s.recordOrderResult(a.name, err)
+	orderExpired := s.stageExpired(orderCtx, configs.StageOrder, data.Symbol)
+	cancelOrder()
+	if orderExpired && err != nil {
+		// 超时后订单是否已到达交易所未知，保留下单意图，重启后暂停等待人工核对
+		log.Error("order placement exceeded latency budget, outcome unknown", "account", a.name, "symbol", data.Symbol, "intent_id", intentID, "err", err)
+		placed = true // 订单可能已成交，保留占用的额度
+		return fmt.Errorf("%w: %w", s.stageTimeoutError(configs.StageOrder), err)
+	}
+	s.removeIntent(intentID)
+	if err != nil && s.cfg().RunMode() == configs.ModeShadow {
+		// 影子模式记录每一笔本应下的单，模拟成交失败的订单也记录
+		order.Status = "REJECTED"
+		s.recordTrade(ctx, &journal.Entry{
+			Strategy:        s.strategyFor(a, data.Symbol),
+			Order:           *order,
+			MarketData:      data,
+			Prediction:      prediction,
+			Sentiment:       signal.sentiment,
+			ScamProbability: signal.scamProbability,
+			RiskAssessment:  riskAssessment,
+		})
+	}
+	s.auditOrder(ctx, order, fmt.Sprintf("%s: predicted %.8g vs current %.8g, confidence %.2f, sentiment %.2f (change %+.2f)",
+		s.strategyFor(a, data.Symbol), prediction.PredictedPrice, data.Price, prediction.Confidence, signal.sentiment, signal.momentum.Change), err)
+	if err != nil {
+		s.persistState(ctx)
+		return err
+	}
+	placed = true
+	s.trackOrder(*order)
+	s.persistState(ctx)
+
+	s.recordTrade(ctx, &journal.Entry{
+		Strategy:        s.strategyFor(a, data.Symbol),
+		Order:           *order,
+		MarketData:      data,
+		Prediction:      prediction,
+		Sentiment:       signal.sentiment,
+		ScamProbability: signal.scamProbability,
+		RiskAssessment:  riskAssessment,
+	})
 	return nil
 }
 
-// 辅助函数：计算社交分数
-func calculateSocialScore(metrics map[string]float64) float64 {
-	var score float64
-	// 可以根据不同平台的指标权重计算综合分数
-	weights := map[string]float64{
-		"twitter_followers": 0.3,
-		"telegram_members":  0.3,
-		"github_stars":      0.2,
-		"reddit_members":    0.2,
+// strategyName 返回当前策略名称
+func (s *QuantSystem) strategyName() string {
+	if s.cfg().TradingConfig.Strategy == "" {
+		return "ai_prediction"
 	}
+	return s.cfg().TradingConfig.Strategy
+}
 
-	for platform, value := range metrics {
-		if weight, exists := weights[platform]; exists {
-			score += value * weight
-		}
+// recordTrade 记录交易日志，有成交时保存权益快照，失败不影响交易流程
+func (s *QuantSystem) recordTrade(ctx context.Context, entry *journal.Entry) {
+	entry.Mode = s.cfg().RunMode()
+	defer s.trackPerformance(ctx, entry)
+	if entry.Order.ExecutedAmount() > 0 {
+		defer s.recordEquity(ctx)
+	}
+	if s.tradeJournal == nil {
+		s.publish(api.EventTrade, entry.Order.Symbol, entry)
+		return
 	}
 
-	return score
+	if err := s.tradeJournal.RecordTrade(ctx, entry); err != nil {
+		log.Error("Error recording trade journal", "order_id", entry.Order.OrderID, "err", err)
+	}
+	s.publish(api.EventTrade, entry.Order.Symbol, entry)
 }
 
 // 辅助函数：转换社交指标为字符串映射
@@ -225,160 +802,580 @@ func convertSocialMetricsToMap(metrics map[string]float64) map[string]string {
 }
 
 // handleRiskAlert 处理风险预警
-func (s *QuantSystem) handleRiskAlert(ctx context.Context, alert risk.RiskAlert) error {
+func (s *QuantSystem) handleRiskAlert(ctx context.Context, a *account, alert risk.RiskAlert) error {
+	// 分批止盈按档位卖出部分持仓
+	if alert.AlertType == alertTakeProfit {
+		s.audit.Record(ctx, audit.ActionReducePosition, alert.Symbol, alert.Description, map[string]any{"account": a.name, "alert": alert.AlertType})
+		return s.takeProfit(ctx, a, alert.Symbol)
+	}
+
+	// ATR 止损只平掉该持仓，不暂停交易对
+	if alert.AlertType == risk.AlertStopLoss {
+		s.audit.Record(ctx, audit.ActionEmergencyClose, alert.Symbol, alert.Description, map[string]any{"account": a.name, "alert": alert.AlertType})
+		return s.emergencyClose(ctx, a, alert.Symbol)
+	}
+
 	// 根据风险预警类型和严重程度采取相应措施
-	switch alert.Severity {
-	case "high":
-		// 可以选择清仓或降低仓位
-		return s.emergencyClose(ctx, alert.Symbol)
-	case "medium":
+	switch strings.ToUpper(alert.Severity) {
+	case risk.SeverityHigh:
+		// 熔断：暂停该交易对的新开仓并清仓，配置了对冲比例时改为开合约空单对冲；
+		// 下架和流动性撤出的现货无法靠对冲保住，始终清仓，对冲失败时同样改为清仓
+		s.PauseSymbol(alert.Symbol)
+		log.Warn("circuit breaker tripped, symbol paused", "symbol", alert.Symbol, "description", alert.Description)
+		s.audit.Record(ctx, audit.ActionPauseSymbol, alert.Symbol, alert.Description, map[string]any{"alert": alert.AlertType})
+		if ratio := s.symbolSettings(alert.Symbol).HedgeRatio; ratio > 0 && a.hedger != nil && !closeOnlyAlerts[alert.AlertType] {
+			s.audit.Record(ctx, audit.ActionHedge, alert.Symbol, alert.Description, map[string]any{"account": a.name, "alert": alert.AlertType, "ratio": ratio})
+			err := s.hedgePosition(ctx, a, alert.Symbol, ratio)
+			if err == nil {
+				return nil
+			}
+			log.Error("failed to hedge position, closing instead", "account", a.name, "symbol", alert.Symbol, "err", err)
+		}
+		s.audit.Record(ctx, audit.ActionEmergencyClose, alert.Symbol, alert.Description, map[string]any{"account": a.name, "alert": alert.AlertType})
+		return s.emergencyClose(ctx, a, alert.Symbol)
+	case risk.SeverityMedium:
 		// 可以选择减仓
-		return s.reducePosition(ctx, alert.Symbol)
+		s.audit.Record(ctx, audit.ActionReducePosition, alert.Symbol, alert.Description, map[string]any{"account": a.name, "alert": alert.AlertType})
+		return s.reducePosition(ctx, a, alert.Symbol)
 	default:
 		// 记录警告信息
-		log.Error("risk alert", "symbol", alert.Symbol, "description", alert.Description)
+		log.Error("risk alert", "account", a.name, "symbol", alert.Symbol, "description", alert.Description)
 		return nil
 	}
 }
 
+// closeOnlyAlerts 只能清仓、不能用对冲代替的 HIGH 级别预警
+var closeOnlyAlerts = map[string]bool{
+	alertSymbolDelisting:         true,
+	risk.AlertLiquidityWithdrawn: true,
+	risk.AlertLPUnlocked:         true,
+}
+
 // 计算订单数量
-func (s *QuantSystem) calculateOrderAmount(predictedPrice, currentPrice float64) float64 {
-	// 这里可以实现更复杂的订单数量计算逻辑
-	amount := s.config.TradingConfig.MaxOrderAmount
-	if amount < s.config.TradingConfig.MinOrderAmount {
-		amount = s.config.TradingConfig.MinOrderAmount
+func (s *QuantSystem) calculateOrderAmount(symbol string, predictedPrice, currentPrice float64) float64 {
+	// 配置了每笔风险金额时按 ATR 止损距离计算，不超过最大交易量
+	settings := s.symbolSettings(symbol)
+	amount := settings.MaxOrderAmount * s.positionScale()
+	if sized, ok := s.riskSizedAmount(symbol, currentPrice); ok && sized < amount {
+		amount = sized
+	}
+	if amount < settings.MinOrderAmount {
+		amount = settings.MinOrderAmount
 	}
 	return amount
 }
 
+// sizeOrder 按配置的交易量单位设置订单数量：单位为 quote 时记录计价资产金额，
+// 并按委托价格（市价单按当前价格）估算基础资产数量用于风险检查，实际数量以成交为准
+func (s *QuantSystem) sizeOrder(order *trading.Order, currentPrice float64) {
+	amount := s.calculateOrderAmount(order.Symbol, order.Price, currentPrice)
+	if s.cfg().OrderAmountUnit() != configs.AmountUnitQuote {
+		order.Amount = amount
+		return
+	}
+
+	price := order.Price
+	if order.OrderType == "market" || order.OrderType == "maker" || price <= 0 {
+		price = currentPrice
+	}
+	order.QuoteAmount = amount
+	order.Amount = trading.QuoteToBase(amount, price)
+}
+
 // 确定订单方向
 func (s *QuantSystem) determineOrderSide(predictedPrice, currentPrice float64) string {
-	if predictedPrice > currentPrice*(1+s.config.TradingConfig.PriceTolerance) {
+	if predictedPrice > currentPrice*(1+s.cfg().TradingConfig.PriceTolerance) {
 		return "buy"
 	}
-	if predictedPrice < currentPrice*(1-s.config.TradingConfig.PriceTolerance) {
+	if predictedPrice < currentPrice*(1-s.cfg().TradingConfig.PriceTolerance) {
 		return "sell"
 	}
 	return ""
 }
 
 // emergencyClose 紧急平仓
-func (s *QuantSystem) emergencyClose(ctx context.Context, symbol string) error {
+func (s *QuantSystem) emergencyClose(ctx context.Context, a *account, symbol string) error {
 	// 获取当前持仓
-	balance, err := s.tradeExecutor.GetBalance(ctx, symbol)
+	balance, err := s.positionAmount(ctx, a, symbol)
 	if err != nil {
 		return err
 	}
 
 	if balance > 0 {
 		order := &trading.Order{
+			Account:   a.name,
 			Symbol:    symbol,
 			Side:      "sell",
 			Amount:    balance,
 			OrderType: "market", // 紧急情况使用市价单
 		}
-		return s.tradeExecutor.PlaceOrder(ctx, order)
+		err := a.executor.PlaceOrder(ctx, order)
+		s.recordOrderResult(a.name, err)
+		s.auditOrder(ctx, order, "emergency close", err)
+		if err != nil {
+			return err
+		}
+		s.recordTrade(ctx, &journal.Entry{Strategy: "risk_emergency_close", Order: *order})
+	}
+
+	// 现货清仓后对冲的合约空单不再有意义，一并平掉
+	if a.hedger != nil {
+		order, err := a.hedger.CloseHedge(ctx, symbol)
+		if err != nil {
+			return err
+		}
+		if order != nil {
+			log.Info("hedge closed", "account", a.name, "symbol", symbol, "amount", order.Amount, "price", order.FilledPrice)
+		}
 	}
 	return nil
 }
 
-// reducePosition 降低仓位
-func (s *QuantSystem) reducePosition(ctx context.Context, symbol string) error {
-	balance, err := s.tradeExecutor.GetBalance(ctx, symbol)
+// hedgePosition 按 ratio 用合约空单对冲账户的现货持仓，已有的对冲数量计入，不重复开仓。
+// 合约订单不记入交易日志，不影响现货的持仓和盈亏统计
+func (s *QuantSystem) hedgePosition(ctx context.Context, a *account, symbol string, ratio float64) error {
+	position, err := s.positionAmount(ctx, a, symbol)
+	if err != nil {
+		return err
+	}
+	hedged, err := a.hedger.HedgeAmount(ctx, symbol)
 	if err != nil {
 		return err
 	}
 
-	if balance > 0 {
-		// 减仓一半
-		order := &trading.Order{
-			Symbol:    symbol,
-			Side:      "sell",
-			Amount:    balance * 0.5,
-			OrderType: "market",
-		}
-		return s.tradeExecutor.PlaceOrder(ctx, order)
+	amount := position*ratio - hedged
+	if amount <= 0 {
+		return nil
+	}
+	order, err := a.hedger.OpenHedge(ctx, symbol, amount)
+	if err != nil {
+		return err
 	}
+	log.Warn("position hedged", "account", a.name, "symbol", symbol, "position", position, "ratio", ratio, "amount", order.Amount, "price", order.FilledPrice)
 	return nil
 }
 
-var (
-	flagconf string
-
-	log = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-		AddSource:   true,
-		Level:       slog.LevelDebug,
-		ReplaceAttr: nil,
-	}))
-)
-
-func init() {
-	flag.StringVar(&flagconf, "conf", "../configs", "config path, eg: -conf config.yaml")
+// reducePosition 降低仓位
+func (s *QuantSystem) reducePosition(ctx context.Context, a *account, symbol string) error {
+	// 按 position_monitor_config.reduce_fraction 减仓，默认一半
+	_, err := s.closePosition(ctx, a, symbol, s.cfg().PositionMonitorConfig.ReduceRatio(), "risk_reduce_position", "reduce position")
+	return err
 }
 
-func main() {
-	flag.Parse()
+// buildCollector 根据运行模式创建数据源
+func buildCollector(config *configs.Config, storager data.DataStorage, checker *collectorData.ConsistencyChecker, info *exchangeinfo.Service, clk clock.Clock, sup *supervisor.Supervisor) (data.DataCollector, error) {
+	switch config.RunMode() {
+	case configs.ModeLive, configs.ModePaper, configs.ModeShadow:
+		sources, binanceSource, err := buildSources(config, info)
+		if err != nil {
+			return nil, err
+		}
+		collector := collectorData.NewMultiSourceCollector(sources, moduleLog("collector"))
+		collector.SetClock(clk)
+		if config.MarketDataConfig.Source == configs.MarketDataKlines && binanceSource != nil {
+			klines := binance.NewKlineStream(binanceSource, moduleLog("collector"))
+			klines.SetSupervisor(sup)
+			collector.SetStreamer(klines)
+		}
+		if checker != nil {
+			collector.SetConsistencyChecker(checker)
+		}
+		return collector, nil
 
-	// 加载配置
-	config := &configs.Config{}
-	configFile, err := os.ReadFile(flagconf)
-	if err != nil {
-		log.Error("Error reading config file", "err", err)
-	}
+	case configs.ModeBacktest:
+		start, err := time.Parse(time.RFC3339, config.BacktestConfig.Start)
+		if err != nil {
+			return nil, fmt.Errorf("invalid backtest start: %w", err)
+		}
+		end, err := time.Parse(time.RFC3339, config.BacktestConfig.End)
+		if err != nil {
+			return nil, fmt.Errorf("invalid backtest end: %w", err)
+		}
+		collector := replay.NewReplayCollector(storager, start, end)
+		if social, ok := storager.(data.SocialStore); ok {
+			collector.SetSocialStore(replay.NewGuardedSocialStore(social), config.BacktestConfig.SocialAge())
+		}
+		// 策略只能读取当前回放行情时间及之前的数据
+		return replay.NewGuardedCollector(collector), nil
 
-	if err := json.Unmarshal(configFile, config); err != nil {
-		log.Error("Error parsing config file", "err", err)
-		return
+	default:
+		return nil, fmt.Errorf("unknown mode: %s", config.Mode)
 	}
+}
 
-	log.Debug("Loaded config", "config", config)
+// log 默认日志，运行系统时由 setupLogging 按 log_config 替换
+var log = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+	AddSource: true,
+	Level:     slog.LevelInfo,
+}))
+
+// app 组装完成的系统组件
+type app struct {
+	config    *configs.Config
+	storage   *storage.PostgresStorage
+	secondary *storage.PostgresStorage // 备用数据库，未配置时为空
+	collector data.DataCollector
+	analyzer  ai.Analyzer
+	accounts  []*account
+	notifier  notify.Notifier
+	system    *QuantSystem
+	restore   bool // 启动时从最近的系统快照恢复
+}
 
+// bootstrap 根据配置初始化各个组件
+func bootstrap(config *configs.Config) (*app, error) {
 	if config.Proxy != "" {
 		_ = os.Setenv("HTTP_PROXY", config.Proxy)
 		_ = os.Setenv("HTTPS_PROXY", config.Proxy)
 		log.Debug("set proxy ok", "proxy", config.Proxy)
 	}
 
-	// 初始化各个组件
-	collector := collectorData.NewMultiSourceCollector([]collectorData.DataSource{
-		binance.NewBinanceDataSource(),
-	}, log)
-
-	log.Debug("init collector")
-
 	storager, err := storage.NewPostgresStorage(config.Database.ConnStr)
 	if err != nil {
-		log.Error("Error creating storage", "err", err)
-		return
+		return nil, fmt.Errorf("failed to create storage: %w", err)
+	}
+	storager.SetDedupTolerance(config.Database.Tolerance())
+	if config.Database.ReplicaConnStr != "" {
+		// 副本只分担分析查询，连接失败时改读主库，不影响实时交易
+		if err := storager.SetReadReplica(config.Database.ReplicaConnStr); err != nil {
+			log.Warn("read replica unavailable, analytical queries use the primary database", "err", err)
+		}
 	}
 
 	log.Debug("init storager")
 
-	analyzer := deepseek.NewDeepSeekAnalyzer(config.AIConfig.APIKey, config.AIConfig.ModelType)
+	// 根据运行模式初始化数据源和各账户的执行器
+	clk, err := buildClock(config)
+	if err != nil {
+		_ = storager.Close()
+		return nil, err
+	}
+	checker := buildConsistencyChecker(config)
+	info := buildExchangeInfo(config)
+	// 行情推送和后台监控断开或退出后按退避重启
+	sup := supervisor.New(config.SupervisorConfig.Backoff())
+	// 回测加载整个区间的历史行情，配置了只读副本时从副本读取
+	collector, err := buildCollector(config, storager.Analytics(), checker, info, clk, sup)
+	if err != nil {
+		_ = storager.Close()
+		return nil, fmt.Errorf("failed to initialize %s mode collector: %w", config.RunMode(), err)
+	}
 
-	log.Debug("init analyzer")
+	accounts, err := buildAccounts(config, info, clk)
+	if err != nil {
+		_ = storager.Close()
+		return nil, fmt.Errorf("failed to initialize accounts: %w", err)
+	}
 
-	riskManager := risk.NewBasicRiskManager(config.RiskParams)
+	// 模拟交易启用故障注入时，数据源、存储、AI 和交易所调用按配置变慢或失败
+	injector := buildChaos(config)
+	if injector != nil {
+		collector = chaos.NewCollector(collector, injector)
+	}
 
-	log.Debug("init riskManager")
+	streamer, err := buildStreamer(config)
+	if err != nil {
+		_ = storager.Close()
+		return nil, fmt.Errorf("failed to initialize event stream: %w", err)
+	}
 
-	executor := binanceTrading.NewBinanceExecutor(config.ExchangeConfig.APIKey, config.ExchangeConfig.SecretKey, config.ExchangeConfig.Debug)
+	log.Debug("init collector and accounts", "mode", config.RunMode(), "accounts", len(accounts))
 
-	log.Debug("init executor")
+	provider := buildAnalyzer(config.AIConfig.APIKey, config.AIConfig.ModelType, config.AIConfig.Generation())
+	if injector != nil {
+		provider = chaos.NewAnalyzer(provider, injector)
+	}
+	analyzer := ai.NewQueue(provider, config.AIConfig.Queue.MaxConcurrent)
+
+	log.Debug("init analyzer")
+
+	// 回测产生的模拟成交不写入交易日志和权益快照，也不保存运行状态
+	var tradeJournal journal.TradeJournal = storager
+	var stateStore state.Store = storager
+	var equity analytics.EquityStorage = storager
+	var snapshots state.SnapshotStore = storager
+	var sentiments sentiment.Store = storager
+	var socials data.SocialStore = storager
+	if config.RunMode() == configs.ModeBacktest {
+		tradeJournal = nil
+		stateStore = nil
+		equity = nil
+		snapshots = nil
+		sentiments = nil
+		socials = nil
+	}
+
+	// 回测时禁止读取回放行情时间之后的数据；配置了备用数据库时行情和代币信息同时写入两个库
+	var dataStorage data.DataStorage = storager
+	var secondary *storage.PostgresStorage
+	var mirror *storage.MirrorStorage
+	if config.RunMode() == configs.ModeBacktest {
+		dataStorage = replay.NewGuardedStorage(storager.Analytics())
+	} else if config.Database.SecondaryConnStr != "" {
+		secondary, err = storage.NewPostgresStorage(config.Database.SecondaryConnStr)
+		if err != nil {
+			_ = storager.Close()
+			return nil, fmt.Errorf("failed to create secondary storage: %w", err)
+		}
+		secondary.SetDedupTolerance(config.Database.Tolerance())
+		mirror = storage.NewMirrorStorage(storager, secondary)
+		dataStorage = mirror
+	}
+	if injector != nil {
+		dataStorage = chaos.NewStorage(dataStorage, injector)
+	}
 
 	// 创建量化系统
 	system := NewQuantSystem(
 		config,
 		collector,
-		storager,
+		dataStorage,
 		analyzer,
-		riskManager,
-		executor,
+		accounts,
+		tradeJournal,
+		stateStore,
 	)
+	system.clock = clk
+	// 行情推送和后台监控共用同一个 supervisor，连接状态统一计入指标和健康检查
+	system.supervisor = sup
+	sup.OnStateChange = system.connectionChanged
+	system.equity = equity
+	system.snapshots = snapshots
+	system.sentiments = sentiments
+	system.socials = socials
+	if config.RunMode() != configs.ModeBacktest {
+		system.projects = storager
+		system.params = newParamRegistry(storager)
+	}
+	if mirror != nil {
+		mirror.OnError = system.recordStorageError
+	}
+	if injector != nil {
+		injector.OnFault = system.recordFault
+	}
+	// 日志、限流、风险复核等中间件统一包在各账户执行器外层，交易所适配器只负责下单本身
+	system.wrapExecutors(injector)
+	system.challenger = buildChallenger(config)
+	system.pairs = buildPairs(config, system)
+	system.abtests = storager
+	system.universe = universe.NewService(storager)
+	system.stream = streamer
+	if streamer != nil {
+		streamer.OnDelivery = func(result string, count int) {
+			system.streamEvents.Add(float64(count), result)
+		}
+	}
+	system.liquidity = buildLiquidityMonitor(config, system)
+	system.whales = buildWhaleTracker(config)
+	system.orderBooks = buildOrderBook(config)
+	system.exchangeInfo = info
+	if info != nil {
+		info.OnStatusChange = system.exchangeStatusChanged
+	}
+	if checker != nil {
+		checker.OnDivergence = system.recordDivergence
+	}
+
+	return &app{
+		config:    config,
+		storage:   storager,
+		secondary: secondary,
+		collector: collector,
+		analyzer:  analyzer,
+		accounts:  accounts,
+		notifier:  newNotifier(config),
+		system:    system,
+	}, nil
+}
+
+// closeStorage 关闭主数据库和备用数据库的连接
+func (a *app) closeStorage() error {
+	err := a.storage.Close()
+	if a.secondary != nil {
+		err = errors.Join(err, a.secondary.Close())
+	}
+	return err
+}
+
+// recordStorageError 记录双写存储中某个后端的读写失败，另一个后端不受影响
+func (s *QuantSystem) recordStorageError(backend, op string, err error) {
+	log.Error("storage backend failed", "backend", backend, "op", op, "err", err)
+	s.storageErrors.Inc(backend, op)
+}
+
+// runSystem 运行量化系统直到收到退出信号，然后按顺序关闭
+func runSystem(a *app, confPath string) error {
+	defer func() {
+		if err := a.closeStorage(); err != nil {
+			log.Error("Error closing storage", "err", err)
+		}
+	}()
+
+	system := a.system
+	config := a.config
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// 审计日志
+	auditLog, closeAudit, err := newAuditLog(config, a.storage)
+	if err != nil {
+		return err
+	}
+	defer closeAudit()
+	system.audit = auditLog
+	system.notifier = a.notifier
+
+	// 蓝绿部署时从旧实例保存的快照接管运行状态，需在对账前恢复模拟账户
+	if a.restore {
+		if err := system.restoreSnapshot(ctx); err != nil {
+			return fmt.Errorf("failed to restore snapshot: %w", err)
+		}
+	}
+
+	// 加载品种池，失败时交易对没有标签，也不同步交易所元数据，避免覆盖已保存的标签
+	if err := system.universe.Load(ctx); err != nil {
+		log.Error("Error loading symbol universe", "err", err)
+	}
+
+	// 加载运行时修改过的阈值，失败时使用配置文件中的值，且不允许修改，避免覆盖已保存的值
+	if system.params != nil {
+		if err := system.loadParams(ctx); err != nil {
+			log.Error("Error loading parameters", "err", err)
+		}
+	}
+
+	// 加载交易所元数据，失败时执行器和数据源直接请求交易所，由周期任务重试
+	if err := system.refreshExchangeInfo(ctx); err != nil {
+		log.Error("Error loading exchange info", "err", err)
+	}
+
+	// 启动对账：恢复挂单和持仓状态
+	if err := system.reconcile(ctx); err != nil {
+		log.Error("Error reconciling state", "err", err)
+	}
+
+	// 恢复各交易对的持仓成本，用于自动停用判断
+	if err := system.loadPerformance(ctx); err != nil {
+		log.Error("Error loading trade performance", "err", err)
+	}
+
+	// 调用链追踪
+	if config.TracingConfig.Enabled {
+		system.traces = tracing.NewRecorder(config.TracingConfig.MaxTraces)
+		exporters := []tracing.Exporter{system.traces}
+		if threshold, err := time.ParseDuration(config.TracingConfig.SlowThreshold); err == nil && threshold > 0 {
+			exporters = append(exporters, tracing.NewLogExporter(moduleLog("tracing"), threshold))
+		}
+		system.tracer = tracing.NewTracer(exporters...)
+	}
+
+	// 启动周期任务
+	sched := scheduler.New(moduleLog("scheduler"))
+	sched.SetClock(system.clock)
+	if err := registerJobs(sched, a); err != nil {
+		return err
+	}
+	system.scheduler = sched
+	sched.Start(ctx)
+
+	go system.runTimeSync(ctx)
+	go system.runDeadman(ctx)
+	go system.runAlerts(ctx)
+
+	// 监听配置变更
+	if confPath != "" {
+		go system.watchConfig(ctx, confPath)
+	}
+
+	// 报表和历史行情查询走只读副本，实时交易循环只使用主库
+	reads := a.storage.Analytics()
+	analyticsService := analytics.NewService(reads, reads, config.TradingConfig.FeeRate, config.RunMode()).
+		WithValuation(system.rates, config.ValuationConfig.Asset())
 
-	// 运行系统
-	ctx := context.Background()
-	if err := system.Run(ctx); err != nil {
-		log.Error("System error", "err", err)
+	// 聊天机器人控制
+	controlBot := bot.NewBot(system, analyticsService, auditLog, config.BotConfig.AllowedChats, moduleLog("bot"))
+	if config.BotConfig.Telegram {
+		go bot.NewTelegramPoller(config.NotifyConfig.TelegramBotToken, controlBot, moduleLog("bot")).Run(ctx)
 	}
+
+	// HTTP 和 gRPC 接口共用认证配置，同一密钥的请求合并限流
+	authenticator := auth.New(config.APIConfig.AuthOptions())
+
+	// 启动 HTTP API
+	var serverDone chan struct{}
+	if config.APIConfig.Addr != "" {
+		system.events = api.NewHub(moduleLog("api"))
+		server := api.NewServer(config.APIConfig.Addr, config.APIConfig.ActorTokens(), system, a.storage, analyticsService, reads, system.events, newHealthChecker(a), auditLog, moduleLog("api"))
+		server.SetAuthenticator(authenticator)
+		server.Handle("GET /metrics", system.metrics)
+		if system.exchangeInfo != nil {
+			server.SetPrecision(precision.NewFormatter(system.exchangeInfo))
+		}
+		server.SetUniverse(system.universe)
+		if system.params != nil {
+			server.SetTuner(system)
+		}
+		if config.BotConfig.SlackEnabled() {
+			server.Handle("POST /bot/slack", bot.NewSlackHandler(controlBot, config.BotConfig.SlackSigningSecret))
+		}
+		serverDone = make(chan struct{})
+		go func() {
+			defer close(serverDone)
+			if err := server.Start(ctx); err != nil {
+				log.Error("API server error", "err", err)
+			}
+		}()
+	}
+
+	// 启动只读 gRPC 接口
+	var grpcDone chan struct{}
+	if config.APIConfig.GRPCAddr != "" {
+		grpcServer := grpcapi.NewServer(config.APIConfig.GRPCAddr, system, a.collector, reads, a.storage, moduleLog("grpc"))
+		grpcServer.SetAuthenticator(authenticator)
+		grpcDone = make(chan struct{})
+		go func() {
+			defer close(grpcDone)
+			if err := grpcServer.Start(ctx); err != nil {
+				log.Error("gRPC server error", "err", err)
+			}
+		}()
+	}
+
+	// 事件流在关闭流程结束后停止，关闭时的平仓成交也会发布
+	stopStream := system.runStream()
+
+	// 运行系统，收到信号后 ctx 取消，行情订阅与风险监控随之停止
+	runErr := system.Run(ctx)
+	if errors.Is(runErr, context.Canceled) {
+		runErr = nil
+	}
+	stop()
+
+	log.Info("shutting down")
+
+	shutdownTimeout, err := time.ParseDuration(config.ShutdownConfig.Timeout)
+	if err != nil {
+		shutdownTimeout = 30 * time.Second
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := system.shutdown(shutdownCtx); err != nil {
+		log.Error("Error during shutdown", "err", err)
+	}
+	stopStream(shutdownCtx)
+
+	for _, done := range []chan struct{}{serverDone, grpcDone} {
+		if done == nil {
+			continue
+		}
+		select {
+		case <-done:
+		case <-shutdownCtx.Done():
+		}
+	}
+
+	log.Info("shutdown complete")
+	return runErr
 }
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+)
+
+// submitOrder 提交订单：order_type 为 maker 时挂单优先下单，执行器不能查询盘口时改为市价单
+func (s *QuantSystem) submitOrder(ctx context.Context, a *account, order *trading.Order, signal tradeSignal) error {
+	// 卖出前撤销组合订单的平仓单，释放被占用的持仓
+	if order.Side == "sell" {
+		s.cancelBrackets(ctx, a, order.Symbol)
+	}
+	if order.Side == "buy" && a.brackets != nil && s.cfg().TradingConfig.Bracket.Enabled() {
+		return s.placeBracket(ctx, a, order, signal.data.Price)
+	}
+	if order.OrderType != "maker" {
+		return a.executor.PlaceOrder(ctx, order)
+	}
+
+	quotes, ok := trading.As[trading.QuoteProvider](a.executor)
+	if !ok {
+		log.Warn("executor has no order book quotes, placing market order", "account", a.name, "symbol", order.Symbol)
+		order.OrderType = "market"
+		return a.executor.PlaceOrder(ctx, order)
+	}
+
+	// 撤销未成交的挂单后按最新盘口中间价重新判断方向，暂停、停止交易、行情停滞或资产充提暂停时不再追单
+	valid := func(quote trading.Quote) bool {
+		if s.determineOrderSide(signal.prediction.PredictedPrice, quote.Mid()) != order.Side {
+			return false
+		}
+		if _, halted := s.tradingHalted(order.Symbol, signal.data.Timestamp); halted {
+			return false
+		}
+		if _, blocked := s.walletBlocked(a, order.Symbol, order.Side); blocked {
+			return false
+		}
+		return !s.symbolPaused(order.Symbol) && !s.symbolStale(order.Symbol)
+	}
+	err := trading.PlaceMakerOrder(ctx, a.executor, quotes, order, s.cfg().TradingConfig.Maker.Options(), valid)
+	if err == nil {
+		log.Info("maker order completed", "account", a.name, "symbol", order.Symbol, "side", order.Side,
+			"escalated", order.OrderType == "market", "filled", order.ExecutedAmount(), "price", order.ExecutedPrice())
+	}
+	return err
+}
+
+// orderStageContext 返回下单阶段的 context，挂单优先时预算额外加上挂单等待时间
+func (s *QuantSystem) orderStageContext(ctx context.Context, orderType string) (context.Context, context.CancelFunc) {
+	config := s.cfg()
+	budget := config.StageBudget(configs.StageOrder)
+	if budget <= 0 || orderType != "maker" {
+		return s.stageContext(ctx, configs.StageOrder)
+	}
+	return context.WithTimeout(ctx, budget+config.TradingConfig.Maker.Options().Wait)
+}
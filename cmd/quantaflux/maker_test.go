@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/ai"
+	"github.com/songzhibin97/quantaflux/internal/models"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuantSystem_SubmitMakerOrder(t *testing.T) {
+	ctx := context.Background()
+	system, _ := newTestSystem(t, map[string]float64{"USDT": 1000})
+	a := system.primaryAccount()
+	a.executor.(trading.MarketPriceUpdater).UpdateMarketPrice("BTCUSDT", 100)
+
+	// 模拟盘口没有价差，挂在最新价的限价单立即成交
+	signal := tradeSignal{data: models.MarketData{Symbol: "BTCUSDT", Price: 100, Timestamp: time.Now()}, side: "buy",
+		prediction: &ai.PricePrediction{PredictedPrice: 110}}
+	order := &trading.Order{Account: a.name, Symbol: "BTCUSDT", Side: "buy", Amount: 1, Price: 110, OrderType: "maker"}
+	require.NoError(t, system.submitOrder(ctx, a, order, signal))
+	assert.Equal(t, "limit", order.OrderType)
+	assert.Equal(t, 100.0, order.Price)
+	assert.Equal(t, "FILLED", order.Status)
+
+	// 下单预算加上挂单等待时间
+	config := *system.cfg()
+	config.LatencyBudget.Order = "5s"
+	config.TradingConfig.Maker.Wait = "10s"
+	system.config.Store(&config)
+	orderCtx, cancel := system.orderStageContext(ctx, "maker")
+	defer cancel()
+	deadline, ok := orderCtx.Deadline()
+	require.True(t, ok)
+	assert.InDelta(t, 15*time.Second, time.Until(deadline), float64(time.Second))
+}
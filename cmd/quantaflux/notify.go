@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/notify"
+)
+
+// newNotifier 根据配置组合通知渠道，未配置外部渠道时写入日志
+func newNotifier(config *configs.Config) notify.Notifier {
+	var notifiers []notify.Notifier
+	if config.NotifyConfig.WebhookURL != "" {
+		notifiers = append(notifiers, notify.NewWebhookNotifier(config.NotifyConfig.WebhookURL))
+	}
+	if config.NotifyConfig.TelegramBotToken != "" {
+		notifiers = append(notifiers, notify.NewTelegramNotifier(config.NotifyConfig.TelegramBotToken, config.NotifyConfig.TelegramChatID))
+	}
+	if len(notifiers) == 0 {
+		return notify.NewLogNotifier(moduleLog("notify"))
+	}
+	return notify.NewMulti(notifiers...)
+}
+
+// 单条通知的发送超时时间
+const notifyTimeout = 10 * time.Second
+
+// notify 发送通知，失败只记录日志，不影响交易流程
+func (c *control) notify(ctx context.Context, msg notify.Message) {
+	if c.notifier == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), notifyTimeout)
+	defer cancel()
+	if err := c.notifier.Notify(ctx, msg); err != nil {
+		log.Error("Error sending notification", "title", msg.Title, "err", err)
+	}
+}
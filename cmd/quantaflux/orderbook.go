@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/api"
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/data/collector/binance"
+	"github.com/songzhibin97/quantaflux/internal/orderbook"
+)
+
+// 盘口信号超过拉取间隔的该倍数未更新时不再用于过滤
+const orderBookStaleIntervals = 3
+
+// buildOrderBook 创建盘口深度追踪，未启用或回测时返回 nil
+func buildOrderBook(config *configs.Config) *orderbook.Tracker {
+	bookConfig := config.OrderBookConfig
+	if !bookConfig.Enabled() || config.RunMode() == configs.ModeBacktest {
+		return nil
+	}
+	return orderbook.NewTracker(binance.NewBinanceDataSource(), bookConfig.Limit, bookConfig.Band)
+}
+
+// monitorOrderBook 按 order_book_config.interval 拉取各交易对的盘口深度，信号推送到仪表盘和事件流
+func (s *QuantSystem) monitorOrderBook(ctx context.Context) {
+	if s.orderBooks == nil {
+		return
+	}
+
+	s.supervise(ctx, "order_book_monitor", func(ctx context.Context) {
+		ticker := time.NewTicker(s.cfg().OrderBookConfig.PollInterval())
+		defer ticker.Stop()
+
+		for {
+			s.pollOrderBooks(ctx, time.Now())
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+}
+
+// pollOrderBooks 拉取各交易对的盘口深度并发布信号，单个交易对失败不影响其他交易对
+func (s *QuantSystem) pollOrderBooks(ctx context.Context, now time.Time) {
+	for _, symbol := range s.cfg().Symbols {
+		signal, err := s.orderBooks.Poll(ctx, symbol, now)
+		if err != nil {
+			log.Error("order book poll failed", "symbol", symbol, "err", err)
+			continue
+		}
+		s.bookImbalance.Set(signal.Imbalance, symbol)
+		s.bookSpread.Set(signal.SpreadBps, symbol)
+		s.publish(api.EventOrderBook, symbol, signal)
+	}
+}
+
+// orderBookAgainst 返回盘口不支持开仓的原因。与趋势过滤一致只过滤买入，卖出平仓不受限制；
+// 没有信号或信号已过期时不过滤
+func (s *QuantSystem) orderBookAgainst(symbol, side string, now time.Time) string {
+	if s.orderBooks == nil || side != "buy" {
+		return ""
+	}
+	signal, ok := s.orderBooks.Signal(symbol)
+	config := s.cfg().OrderBookConfig
+	if !ok || now.Sub(signal.Timestamp) > orderBookStaleIntervals*config.PollInterval() {
+		return ""
+	}
+
+	if config.MaxAskImbalance > 0 && signal.Imbalance < -config.MaxAskImbalance {
+		return fmt.Sprintf("ask-heavy order book, imbalance %.2f below -%.2f", signal.Imbalance, config.MaxAskImbalance)
+	}
+	if config.MaxSpreadBps > 0 && signal.SpreadBps > config.MaxSpreadBps {
+		return fmt.Sprintf("spread %.1fbps above %.1fbps", signal.SpreadBps, config.MaxSpreadBps)
+	}
+	return ""
+}
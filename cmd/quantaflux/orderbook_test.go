@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/orderbook"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeDepthSource struct {
+	book orderbook.Book
+}
+
+func (f *fakeDepthSource) Depth(_ context.Context, symbol string, _ int) (*orderbook.Book, error) {
+	book := f.book
+	book.Symbol = symbol
+	return &book, nil
+}
+
+func TestQuantSystem_OrderBookAgainst(t *testing.T) {
+	system, _ := newTestSystem(t, map[string]float64{"USDT": 1000})
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// 未启用时不过滤
+	assert.Empty(t, system.orderBookAgainst("BTCUSDT", "buy", now))
+
+	config := *system.cfg()
+	config.OrderBookConfig = configs.OrderBookConfig{Interval: "10s", MaxAskImbalance: 0.5, MaxSpreadBps: 30}
+	system.config.Store(&config)
+	source := &fakeDepthSource{book: orderbook.Book{
+		Bids: []orderbook.Level{{Price: 99.9, Quantity: 1}},
+		Asks: []orderbook.Level{{Price: 100.1, Quantity: 9}},
+	}}
+	system.orderBooks = orderbook.NewTracker(source, 0, 0)
+
+	// 没有信号时不过滤
+	assert.Empty(t, system.orderBookAgainst("BTCUSDT", "buy", now))
+
+	// 卖盘更厚时不买入，卖出不受限制
+	system.pollOrderBooks(context.Background(), now)
+	assert.InDelta(t, -0.8, system.bookImbalance.Value("BTCUSDT"), 0.01)
+	assert.InDelta(t, 20, system.bookSpread.Value("BTCUSDT"), 1e-6)
+	assert.Contains(t, system.orderBookAgainst("BTCUSDT", "buy", now), "ask-heavy")
+	assert.Empty(t, system.orderBookAgainst("BTCUSDT", "sell", now))
+
+	// 信号过期后不再过滤
+	assert.Empty(t, system.orderBookAgainst("BTCUSDT", "buy", now.Add(time.Minute)))
+
+	// 价差过大时不买入
+	source.book = orderbook.Book{
+		Bids: []orderbook.Level{{Price: 99.7, Quantity: 5}},
+		Asks: []orderbook.Level{{Price: 100.3, Quantity: 5}},
+	}
+	system.pollOrderBooks(context.Background(), now)
+	assert.Contains(t, system.orderBookAgainst("BTCUSDT", "buy", now), "spread")
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/data"
+	"github.com/songzhibin97/quantaflux/internal/journal"
+	"github.com/songzhibin97/quantaflux/internal/models"
+	"github.com/songzhibin97/quantaflux/internal/pairs"
+)
+
+// pairTrader 在账户上运行的配对交易策略
+type pairTrader struct {
+	account  *account
+	strategy *pairs.Strategy
+	version  int64 // 已保存的持仓状态的版本
+}
+
+// stateKey 返回持仓状态的命名空间和键
+func (p *pairTrader) stateKey() (namespace, key string) {
+	y, x := p.strategy.Symbols()
+	return "pairs/" + p.account.name, y + "-" + x
+}
+
+// restorePairs 恢复各配对保存的持仓状态，回测时状态保存在内存中，从空仓开始
+func (s *QuantSystem) restorePairs(ctx context.Context) {
+	if s.dataStorage == nil {
+		return
+	}
+	for _, p := range s.pairs {
+		namespace, key := p.stateKey()
+		saved, err := s.dataStorage.GetStrategyState(ctx, namespace, key)
+		if errors.Is(err, data.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			log.Error("Error loading pair trading state", "account", p.account.name, "pair", key, "err", err)
+			continue
+		}
+		var state pairs.State
+		if err := json.Unmarshal(saved.Value, &state); err != nil {
+			log.Error("Error decoding pair trading state", "account", p.account.name, "pair", key, "err", err)
+			continue
+		}
+		p.strategy.RestoreState(state)
+		p.version = saved.Version
+		log.Info("pair trading state restored", "account", p.account.name, "pair", key, "position", state.Position)
+	}
+}
+
+// savePairState 保存配对的持仓状态；版本冲突说明另一个实例在运行同一配对，记录错误并以存储中的版本为准继续
+func (s *QuantSystem) savePairState(ctx context.Context, p *pairTrader) {
+	if s.dataStorage == nil {
+		return
+	}
+	value, err := json.Marshal(p.strategy.State())
+	if err != nil {
+		log.Error("Error encoding pair trading state", "account", p.account.name, "err", err)
+		return
+	}
+	namespace, key := p.stateKey()
+	state := &data.StrategyState{Namespace: namespace, Key: key, Value: value, Version: p.version}
+	err = s.dataStorage.SetStrategyState(ctx, state)
+	if errors.Is(err, data.ErrStateConflict) {
+		log.Error("pair trading state changed by another writer", "account", p.account.name, "pair", key, "err", err)
+		if latest, getErr := s.dataStorage.GetStrategyState(ctx, namespace, key); getErr == nil {
+			p.version = latest.Version
+		}
+		return
+	}
+	if err != nil {
+		log.Error("Error saving pair trading state", "account", p.account.name, "pair", key, "err", err)
+		return
+	}
+	p.version = state.Version
+}
+
+// buildPairs 为每个配对交易配置创建策略，账户不支持合约对冲时跳过
+func buildPairs(config *configs.Config, system *QuantSystem) []*pairTrader {
+	var traders []*pairTrader
+	for _, pc := range config.Pairs {
+		a, err := system.account(pc.Account)
+		if err != nil {
+			log.Error("skip pair trading", "y", pc.Y, "x", pc.X, "err", err)
+			continue
+		}
+		if a.hedger == nil {
+			log.Warn("skip pair trading, account does not support futures hedging", "account", a.name, "y", pc.Y, "x", pc.X)
+			continue
+		}
+		traders = append(traders, &pairTrader{account: a, strategy: pairs.NewStrategy(pc.Strategy(a.name), a.executor, a.hedger)})
+	}
+	return traders
+}
+
+// runPairs 用最新行情更新配对策略，任一条腿暂停、停止交易、行情停滞或资产充提暂停时跳过。
+// 现货订单经执行器的下单限制占用额度和评估风险；现货订单记入交易日志，合约订单与对冲一样只写入运行日志
+func (s *QuantSystem) runPairs(ctx context.Context, data models.MarketData) {
+	for _, p := range s.pairs {
+		y, x := p.strategy.Symbols()
+		if s.symbolPaused(y) || s.symbolPaused(x) || s.symbolStale(y) || s.symbolStale(x) {
+			continue
+		}
+		if _, halted := s.tradingHalted(y, data.Timestamp); halted {
+			continue
+		}
+		if _, halted := s.tradingHalted(x, data.Timestamp); halted {
+			continue
+		}
+		if _, blocked := s.walletBlocked(p.account, y, ""); blocked {
+			continue
+		}
+		if _, blocked := s.walletBlocked(p.account, x, ""); blocked {
+			continue
+		}
+		signal, err := p.strategy.OnPrice(ctx, data.Symbol, data.Price)
+		if signal == nil || signal.Action == "" {
+			continue
+		}
+
+		log.Info("pair trading signal", "account", p.account.name, "action", signal.Action, "z_score", signal.ZScore,
+			"beta", signal.Test.Beta, "statistic", signal.Test.Statistic, "correlation", signal.Correlation)
+		for i := range signal.Spot {
+			order := &signal.Spot[i]
+			s.recordOrderResult(p.account.name, nil)
+			s.auditOrder(ctx, order, "pair trading "+signal.Action, nil)
+			s.recordTrade(ctx, &journal.Entry{Strategy: "pairs", Order: *order})
+		}
+		for _, order := range signal.Futures {
+			log.Info("pair trading futures order", "account", p.account.name, "symbol", order.Symbol, "side", order.Side, "amount", order.Amount, "price", order.FilledPrice)
+		}
+		s.savePairState(ctx, p)
+		if err != nil {
+			s.recordOrderResult(p.account.name, err)
+			log.Error("pair trading failed", "account", p.account.name, "action", signal.Action, "err", err)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/data/collector/replay"
+	"github.com/songzhibin97/quantaflux/internal/models"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuantSystem_RunPairs(t *testing.T) {
+	ctx := context.Background()
+	system, store := newTestSystem(t, map[string]float64{"USDT": 10000})
+	system.dataStorage = replay.NewGuardedStorage(nil)
+	a := system.primaryAccount()
+	updater := a.executor.(trading.MarketPriceUpdater)
+
+	config := *system.cfg()
+	config.Symbols = []string{"BTCUSDT", "ETHUSDT"}
+	config.Pairs = []configs.PairConfig{{Y: "ETHUSDT", X: "BTCUSDT", Window: 99, EntryZ: 2, ExitZ: 0.5, Notional: 1000}}
+
+	// 账户不支持合约对冲时不运行配对交易
+	assert.Empty(t, buildPairs(&config, system))
+	a.hedger = a.executor.(trading.Hedger)
+	system.pairs = buildPairs(&config, system)
+	require.Len(t, system.pairs, 1)
+
+	tick := pairTicker(ctx, system, updater)
+	// 每次推送两个交易对的价格，49 次后有 97 个样本，冲击时 ETH 更新后刚好达到窗口
+	for range 49 {
+		tick(0)
+	}
+	assert.Empty(t, store.entries)
+
+	// 暂停时不交易
+	system.PauseSymbol("ETHUSDT")
+	tick(-0.03)
+	assert.Empty(t, store.entries)
+	system.ResumeSymbol("ETHUSDT")
+
+	tick(-0.03)
+	require.Len(t, store.entries, 1)
+	assert.Equal(t, "pairs", store.entries[0].Strategy)
+	assert.Equal(t, "ETHUSDT", store.entries[0].Order.Symbol)
+	assert.Equal(t, "buy", store.entries[0].Order.Side)
+	hedged, err := a.hedger.HedgeAmount(ctx, "BTCUSDT")
+	require.NoError(t, err)
+	assert.Positive(t, hedged)
+
+	// 开仓后保存持仓状态，重建策略后恢复
+	assert.Equal(t, int64(1), system.pairs[0].version)
+	system.pairs = buildPairs(&config, system)
+	system.restorePairs(ctx)
+	assert.Equal(t, 1, system.pairs[0].strategy.Position())
+	assert.Equal(t, int64(1), system.pairs[0].version)
+}
+
+// pairTicker 返回按协整关系生成 BTCUSDT 和 ETHUSDT 价格并推送给配对策略的函数，shock 为 ETH 价格的偏离
+func pairTicker(ctx context.Context, system *QuantSystem, updater trading.MarketPriceUpdater) func(shock float64) {
+	r := rand.New(rand.NewSource(2))
+	logX := math.Log(100)
+	var noise float64
+	return func(shock float64) {
+		logX += r.NormFloat64() * 0.01
+		noise = 0.3*noise + r.NormFloat64()*0.002
+		x, y := math.Exp(logX), math.Exp(0.5+1.2*logX+noise+shock)
+		for _, data := range []models.MarketData{{Symbol: "BTCUSDT", Price: x}, {Symbol: "ETHUSDT", Price: y}} {
+			updater.UpdateMarketPrice(data.Symbol, data.Price)
+			system.updateMarketData(data)
+			system.runPairs(ctx, data)
+		}
+	}
+}
+
+func TestQuantSystem_RunPairs_Governed(t *testing.T) {
+	ctx := context.Background()
+	system, store := newTestSystem(t, map[string]float64{"USDT": 10000})
+	system.dataStorage = replay.NewGuardedStorage(nil)
+	a := system.primaryAccount()
+	updater := a.executor.(trading.MarketPriceUpdater)
+	a.hedger = a.executor.(trading.Hedger)
+	system.wrapExecutors(nil)
+
+	config := *system.cfg()
+	config.Symbols = []string{"BTCUSDT", "ETHUSDT"}
+	config.Pairs = []configs.PairConfig{{Y: "ETHUSDT", X: "BTCUSDT", Window: 99, EntryZ: 2, ExitZ: 0.5, Notional: 1000}}
+	config.TradingConfig.SpendLimit = configs.SpendLimitConfig{PerMinute: 500}
+	config.RiskParams.MaxPositionSize = 5000
+	system.config.Store(&config)
+	require.NoError(t, a.riskManager.SetRiskParameters(ctx, &config.RiskParams))
+	system.pairs = buildPairs(&config, system)
+	require.Len(t, system.pairs, 1)
+
+	tick := pairTicker(ctx, system, updater)
+	for range 49 {
+		tick(0)
+	}
+
+	// 现货买入超过下单金额上限时被拒绝，已开的合约空单平掉
+	tick(-0.03)
+	assert.Empty(t, store.entries)
+	assert.Zero(t, system.pairs[0].strategy.Position())
+	hedged, err := a.hedger.HedgeAmount(ctx, "BTCUSDT")
+	require.NoError(t, err)
+	assert.Zero(t, hedged)
+
+	// 另一条腿暂停时同样不交易
+	config.TradingConfig.SpendLimit = configs.SpendLimitConfig{}
+	system.config.Store(&config)
+	system.PauseSymbol("BTCUSDT")
+	tick(-0.03)
+	assert.Empty(t, store.entries)
+	system.ResumeSymbol("BTCUSDT")
+
+	tick(-0.03)
+	require.Len(t, store.entries, 1)
+	assert.Equal(t, "ETHUSDT", store.entries[0].Order.Symbol)
+}
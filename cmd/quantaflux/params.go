@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/params"
+)
+
+// newParamRegistry 创建保存运行时阈值修改的参数注册表
+func newParamRegistry(store params.Store) *params.Registry {
+	tunables := configs.Tunables()
+	names := make([]string, 0, len(tunables))
+	for _, tunable := range tunables {
+		names = append(names, tunable.Name)
+	}
+	return params.NewRegistry(store, names)
+}
+
+// loadParams 从存储加载运行时修改过的阈值，覆盖配置文件中的值
+func (s *QuantSystem) loadParams(ctx context.Context) error {
+	if err := s.params.Load(ctx); err != nil {
+		return err
+	}
+
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+
+	current := s.cfg()
+	next := s.applyParams(current)
+	if err := s.applyRiskParams(ctx, current, next); err != nil {
+		return err
+	}
+	s.config.Store(next)
+	if overrides := s.params.Overrides(); len(overrides) > 0 {
+		log.Info("parameter overrides loaded", "params", overrides)
+	}
+	return nil
+}
+
+// applyParams 返回应用参数注册表中覆盖值后的配置，未启用注册表时原样返回
+func (s *QuantSystem) applyParams(config *configs.Config) *configs.Config {
+	if s.params == nil {
+		return config
+	}
+	return config.ApplyTunables(s.params.Overrides())
+}
+
+// Params implements api.Tuner
+func (s *QuantSystem) Params() []params.Param {
+	config := s.cfg()
+	overrides := s.params.Overrides()
+	result := make([]params.Param, 0, len(configs.Tunables()))
+	for _, tunable := range configs.Tunables() {
+		_, overridden := overrides[tunable.Name]
+		result = append(result, s.param(tunable, config, overridden))
+	}
+	return result
+}
+
+// SetParam 修改阈值：新值先校验并持久化，再作用于运行中的配置和各账户的风险管理器
+func (s *QuantSystem) SetParam(ctx context.Context, name string, value float64, reason string) (*params.Param, error) {
+	tunable, ok := configs.LookupTunable(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", params.ErrUnknownParam, name)
+	}
+
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+
+	current := s.cfg()
+	next := current.ApplyTunables(map[string]float64{name: value})
+	if err := configs.ValidateTunable(next, name); err != nil {
+		return nil, fmt.Errorf("%w: %w", params.ErrInvalidValue, err)
+	}
+	if _, err := s.params.Set(ctx, name, value, tunable.Get(current), reason, s.clock.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.applyRiskParams(ctx, current, next); err != nil {
+		return nil, err
+	}
+	s.config.Store(next)
+
+	param := s.param(tunable, next, true)
+	return &param, nil
+}
+
+// ResetParam 删除阈值的运行时修改，恢复最近一次加载的配置文件中的值
+func (s *QuantSystem) ResetParam(ctx context.Context, name string, reason string) (*params.Param, error) {
+	tunable, ok := configs.LookupTunable(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", params.ErrUnknownParam, name)
+	}
+
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+
+	current := s.cfg()
+	next := current.ApplyTunables(map[string]float64{name: tunable.Get(s.fileConfig)})
+	if _, err := s.params.Reset(ctx, name, tunable.Get(current), reason, s.clock.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.applyRiskParams(ctx, current, next); err != nil {
+		return nil, err
+	}
+	s.config.Store(next)
+
+	param := s.param(tunable, next, false)
+	return &param, nil
+}
+
+// ParamHistory implements api.Tuner
+func (s *QuantSystem) ParamHistory(ctx context.Context, name string, limit int) ([]params.Change, error) {
+	return s.params.History(ctx, name, limit)
+}
+
+func (s *QuantSystem) param(tunable configs.Tunable, config *configs.Config, overridden bool) params.Param {
+	return params.Param{
+		Name:        tunable.Name,
+		Description: tunable.Description,
+		Value:       tunable.Get(config),
+		Default:     tunable.Get(s.fileConfig),
+		Overridden:  overridden,
+	}
+}
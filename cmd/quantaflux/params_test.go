@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/songzhibin97/quantaflux/internal/audit"
+	"github.com/songzhibin97/quantaflux/internal/params"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryParams struct {
+	changes []params.Change
+}
+
+func (m *memoryParams) AppendParamChange(_ context.Context, change *params.Change) error {
+	change.ID = int64(len(m.changes) + 1)
+	m.changes = append(m.changes, *change)
+	return nil
+}
+
+func (m *memoryParams) LatestParamChanges(context.Context) ([]params.Change, error) {
+	latest := make(map[string]params.Change)
+	for _, change := range m.changes {
+		latest[change.Name] = change
+	}
+	var result []params.Change
+	for _, change := range latest {
+		result = append(result, change)
+	}
+	return result, nil
+}
+
+func (m *memoryParams) ListParamChanges(_ context.Context, name string, limit int) ([]params.Change, error) {
+	var result []params.Change
+	for i := len(m.changes) - 1; i >= 0 && len(result) < limit; i-- {
+		if name == "" || m.changes[i].Name == name {
+			result = append(result, m.changes[i])
+		}
+	}
+	return result, nil
+}
+
+func TestQuantSystem_Params(t *testing.T) {
+	ctx := audit.WithActor(context.Background(), "ops")
+	system, _ := newTestSystem(t, map[string]float64{"USDT": 1000})
+	positionLimit := func() float64 {
+		state, err := system.RiskState(ctx, "")
+		require.NoError(t, err)
+		return state.Parameters.MaxPositionSize
+	}
+
+	// 启动时加载上次运行中修改的值
+	limit := 15.0
+	store := &memoryParams{changes: []params.Change{{Name: "risk_parameters.max_position_size", Value: &limit}}}
+	system.params = newParamRegistry(store)
+	require.NoError(t, system.loadParams(ctx))
+	assert.Equal(t, 15.0, system.cfg().RiskParams.MaxPositionSize)
+	assert.Equal(t, 15.0, positionLimit())
+
+	param, err := system.SetParam(ctx, "ai_config.min_confidence", 0.8, "too many losers")
+	require.NoError(t, err)
+	assert.Equal(t, 0.8, param.Value)
+	assert.True(t, param.Overridden)
+	assert.Equal(t, 0.8, system.cfg().AIConfig.MinConfidence)
+
+	_, err = system.SetParam(ctx, "ai_config.min_confidence", 1.5, "")
+	assert.ErrorIs(t, err, params.ErrInvalidValue)
+	_, err = system.SetParam(ctx, "risk_parameters.max_leverage", 0, "")
+	assert.ErrorIs(t, err, params.ErrInvalidValue)
+	_, err = system.SetParam(ctx, "trading_config.unknown", 1, "")
+	assert.ErrorIs(t, err, params.ErrUnknownParam)
+	assert.Equal(t, 0.8, system.cfg().AIConfig.MinConfidence)
+
+	// 热加载时运行中修改的值优先于配置文件
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`mode: paper
+symbols: [BTCUSDT]
+database:
+  conn_str: postgresql://localhost/quantaflux
+ai_config:
+  api_key: key
+  min_confidence: 0.6
+risk_params:
+  max_position_size: 30
+  max_loss_per_trade: 100
+  max_daily_loss: 500
+  max_leverage: 1
+  min_liquidity: 1000
+`), 0o600))
+	require.NoError(t, system.reloadConfig(ctx, path))
+	assert.Equal(t, 0.8, system.cfg().AIConfig.MinConfidence)
+	assert.Equal(t, 15.0, positionLimit())
+
+	// 恢复配置文件中的值
+	param, err = system.ResetParam(ctx, "risk_parameters.max_position_size", "limit restored")
+	require.NoError(t, err)
+	assert.Equal(t, 30.0, param.Value)
+	assert.False(t, param.Overridden)
+	assert.Equal(t, 30.0, positionLimit())
+
+	for _, p := range system.Params() {
+		assert.Equal(t, p.Name == "ai_config.min_confidence", p.Overridden, p.Name)
+	}
+
+	history, err := system.ParamHistory(ctx, "", 10)
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	assert.Equal(t, "limit restored", history[0].Reason)
+	assert.Equal(t, 15.0, history[0].Previous)
+	assert.Equal(t, "ops", history[1].Actor)
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/songzhibin97/quantaflux/internal/risk"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+)
+
+// OpenPositions implements risk.PositionSource，持仓和成本来自成交记录，按最新行情价格估值，
+// 没有行情的交易对实时采集一次，金额换算为估值资产，缺少汇率时保留交易对计价资产的金额
+func (s *QuantSystem) OpenPositions(ctx context.Context, account string) ([]risk.Position, error) {
+	positions := s.performance.Positions(account)
+	for i := range positions {
+		pos := &positions[i]
+		price := s.lastPrice(pos.Symbol)
+		if price <= 0 {
+			if s.dataCollector == nil {
+				return nil, fmt.Errorf("no price for %s", pos.Symbol)
+			}
+			data, err := s.dataCollector.CollectMarketData(ctx, pos.Symbol)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get price of %s: %w", pos.Symbol, err)
+			}
+			price = data.Price
+		}
+
+		if _, quote, ok := trading.SplitSymbol(pos.Symbol); ok {
+			if rate, err := s.value(1, quote); err == nil {
+				price *= rate
+				pos.AvgCost *= rate
+			}
+		}
+		pos.Price = price
+		pos.Value = pos.Amount * price
+		pos.UnrealizedPnL = (price - pos.AvgCost) * pos.Amount
+	}
+	return positions, nil
+}
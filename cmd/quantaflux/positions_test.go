@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/models"
+	"github.com/songzhibin97/quantaflux/internal/risk"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuantSystem_MonitorOpenPositions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	system, _ := newTestSystem(t, map[string]float64{"USDT": 1000})
+	config := *system.cfg()
+	config.Symbols = []string{"BTCUSDT", "ETHBTC"}
+	config.PositionMonitorConfig.Interval = "5ms"
+	system.config.Store(&config)
+
+	opened := time.Now().Add(-time.Hour)
+	system.performance.Record(trading.Order{Account: "main", Symbol: "BTCUSDT", Side: "buy", Amount: 2, Price: 150, Status: "FILLED"}, opened)
+	system.performance.Record(trading.Order{Account: "main", Symbol: "ETHBTC", Side: "buy", Amount: 1, Price: 0.05, Status: "FILLED"}, opened)
+	system.updateMarketData(models.MarketData{Symbol: "BTCUSDT", Price: 100, Timestamp: time.Now()})
+	system.updateMarketData(models.MarketData{Symbol: "ETHBTC", Price: 0.04, Timestamp: time.Now()})
+
+	// ETHBTC 的成本和价格经 BTCUSDT 换算为 USDT
+	positions, err := system.OpenPositions(ctx, "main")
+	require.NoError(t, err)
+	require.Len(t, positions, 2)
+	assert.Equal(t, "BTCUSDT", positions[0].Symbol)
+	assert.InDelta(t, -100, positions[0].UnrealizedPnL, 1e-9)
+	assert.Equal(t, opened, positions[0].OpenedAt)
+	assert.InDelta(t, 4, positions[1].Value, 1e-9)
+	assert.InDelta(t, -1, positions[1].UnrealizedPnL, 1e-9)
+
+	// 浮亏超过 max_loss_per_trade 的持仓触发预警
+	system.updateMarketData(models.MarketData{Symbol: "BTCUSDT", Price: 90, Timestamp: time.Now()})
+	alerts, err := system.monitorAccounts(ctx)
+	require.NoError(t, err)
+	select {
+	case alert := <-alerts:
+		assert.Equal(t, "main", alert.account.name)
+		assert.Equal(t, risk.AlertPositionLoss, alert.alert.AlertType)
+		assert.Equal(t, "BTCUSDT", alert.alert.Symbol)
+	case <-time.After(time.Second):
+		t.Fatal("expected a position loss alert")
+	}
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/models"
+	"github.com/songzhibin97/quantaflux/internal/notify"
+)
+
+// 触发重新分析的事件
+const (
+	eventHolderConcentration = "holder_concentration" // 团队持仓占比大幅上升
+	eventSocialSpike         = "social_spike"         // 社交指标突增
+	eventLiquidityDrop       = "liquidity_drop"       // 流动性池被撤出
+	eventListing             = "listing"              // 交易对开始交易
+)
+
+// 等待执行的重新分析数量上限，队列满时丢弃新的事件
+const reanalysisQueueSize = 32
+
+// projectMetricsStore 保存项目分析结果
+type projectMetricsStore interface {
+	SaveProjectMetrics(ctx context.Context, metrics *models.ProjectMetrics) error
+}
+
+// reanalysisEvent 一次需要立即重新分析的事件
+type reanalysisEvent struct {
+	symbol string
+	reason string
+	detail string
+}
+
+// reanalysisTrigger 记录各交易对上次观察到的持仓集中度和社交指标，以及上次触发重新分析的时间
+type reanalysisTrigger struct {
+	queue chan reanalysisEvent
+
+	mu            sync.Mutex
+	concentration map[string]float64
+	social        map[string]map[string]float64
+	triggeredAt   map[string]time.Time
+}
+
+func newReanalysisTrigger() *reanalysisTrigger {
+	return &reanalysisTrigger{
+		queue:         make(chan reanalysisEvent, reanalysisQueueSize),
+		concentration: make(map[string]float64),
+		social:        make(map[string]map[string]float64),
+		triggeredAt:   make(map[string]time.Time),
+	}
+}
+
+// holderConcentration 返回团队持仓占总量的比例，总量未知时直接使用团队持仓
+func holderConcentration(info models.TokenInfo) float64 {
+	if info.TotalSupply > 0 {
+		return info.TeamAllocation / info.TotalSupply
+	}
+	return info.TeamAllocation
+}
+
+// reanalysisEnabled 回测和未配置触发事件时不重新分析
+func (s *QuantSystem) reanalysisEnabled() bool {
+	config := s.cfg()
+	return config.RunMode() != configs.ModeBacktest && config.AIConfig.Reanalysis.Enabled()
+}
+
+// observeTokenInfo 记录代币的持仓集中度，相对上次观察上升超过 holder_concentration 时触发重新分析
+func (s *QuantSystem) observeTokenInfo(info *models.TokenInfo) {
+	threshold := s.cfg().AIConfig.Reanalysis.HolderConcentration
+	if info == nil || threshold <= 0 || !s.reanalysisEnabled() {
+		return
+	}
+
+	current := holderConcentration(*info)
+	s.reanalysis.mu.Lock()
+	previous, ok := s.reanalysis.concentration[info.Symbol]
+	s.reanalysis.concentration[info.Symbol] = current
+	s.reanalysis.mu.Unlock()
+
+	if ok && previous > 0 && (current-previous)/previous > threshold {
+		s.triggerReanalysis(info.Symbol, eventHolderConcentration,
+			fmt.Sprintf("team allocation share rose from %.4f to %.4f", previous, current))
+	}
+}
+
+// observeSocial 记录交易对的社交指标，任一指标达到上次观察值的 social_spike 倍时触发重新分析
+func (s *QuantSystem) observeSocial(symbol string, metrics map[string]float64) {
+	ratio := s.cfg().AIConfig.Reanalysis.SocialSpike
+	if len(metrics) == 0 || ratio <= 0 || !s.reanalysisEnabled() {
+		return
+	}
+
+	s.reanalysis.mu.Lock()
+	previous := s.reanalysis.social[symbol]
+	latest := make(map[string]float64, len(metrics))
+	for name, value := range metrics {
+		latest[name] = value
+	}
+	s.reanalysis.social[symbol] = latest
+	s.reanalysis.mu.Unlock()
+
+	for name, value := range metrics {
+		if before := previous[name]; before > 0 && value >= before*ratio {
+			s.triggerReanalysis(symbol, eventSocialSpike, fmt.Sprintf("%s rose from %g to %g", name, before, value))
+			return
+		}
+	}
+}
+
+// triggerReanalysis 将交易对加入重新分析队列，同一交易对 cooldown 内只触发一次，队列满时丢弃
+func (s *QuantSystem) triggerReanalysis(symbol, reason, detail string) {
+	config := s.cfg().AIConfig.Reanalysis
+	if !s.reanalysisEnabled() {
+		return
+	}
+
+	now := s.clock.Now()
+	s.reanalysis.mu.Lock()
+	if last, ok := s.reanalysis.triggeredAt[symbol]; ok && now.Sub(last) < config.CooldownPeriod() {
+		s.reanalysis.mu.Unlock()
+		log.Debug("re-analysis suppressed by cooldown", "symbol", symbol, "reason", reason, "last", last)
+		return
+	}
+	s.reanalysis.triggeredAt[symbol] = now
+	s.reanalysis.mu.Unlock()
+
+	select {
+	case s.reanalysis.queue <- reanalysisEvent{symbol: symbol, reason: reason, detail: detail}:
+		log.Info("re-analysis triggered", "symbol", symbol, "reason", reason, "detail", detail)
+	default:
+		log.Error("re-analysis dropped, queue full", "symbol", symbol, "reason", reason)
+	}
+}
+
+// runReanalysis 依次执行队列中的重新分析，失败只记录日志；回测时不启动
+func (s *QuantSystem) runReanalysis(ctx context.Context) {
+	if s.cfg().RunMode() == configs.ModeBacktest {
+		return
+	}
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-s.reanalysis.queue:
+				if err := s.reanalyze(ctx, event); err != nil {
+					log.Error("re-analysis failed", "symbol", event.symbol, "reason", event.reason, "err", err)
+				}
+			}
+		}
+	}()
+}
+
+// reanalyze 重新采集代币信息并执行项目分析和诈骗检测，新结论替换缓存，
+// 诈骗可能性超过阈值时发送通知，之后的行情按新结论停止交易
+func (s *QuantSystem) reanalyze(ctx context.Context, event reanalysisEvent) error {
+	aiCtx, cancel := s.stageContext(ctx, configs.StageAI)
+	defer cancel()
+
+	tokenInfo, err := s.dataCollector.CollectTokenInfo(aiCtx, event.symbol)
+	if err != nil {
+		return fmt.Errorf("failed to collect token info: %w", err)
+	}
+
+	metrics, err := s.aiAnalyzer.AnalyzeProject(aiCtx, tokenInfo)
+	s.recordAIResult(err)
+	if err != nil {
+		return fmt.Errorf("failed to analyze project: %w", err)
+	}
+	metrics.UpdatedAt = s.clock.Now()
+	if s.projects != nil {
+		if err := s.projects.SaveProjectMetrics(ctx, metrics); err != nil {
+			log.Error("Error saving project metrics", "symbol", event.symbol, "err", err)
+		}
+	}
+
+	analysis, err := s.aiAnalyzer.DetectScam(aiCtx, metrics)
+	s.recordAIResult(err)
+	if err != nil {
+		return fmt.Errorf("failed to detect scam: %w", err)
+	}
+	s.scamChecks.Inc(event.symbol, "checked")
+	s.scams.set(event.symbol, scamVerdict{
+		analysis:  *analysis,
+		inputs:    s.scamInputs(event.symbol, *tokenInfo),
+		checkedAt: metrics.UpdatedAt,
+	})
+
+	log.Info("re-analysis completed", "symbol", event.symbol, "reason", event.reason,
+		"risk_score", metrics.RiskScore, "scam_probability", analysis.ScamProbability)
+	if analysis.ScamProbability > s.cfg().AIConfig.ScamThreshold {
+		s.notify(ctx, notify.Message{
+			Title: fmt.Sprintf("%s scam probability %.2f after %s", event.symbol, analysis.ScamProbability, event.reason),
+			Text:  fmt.Sprintf("Re-analysis triggered by %s (%s) found a high scam probability, new trades are skipped.", event.reason, event.detail),
+			Level: notify.LevelWarning,
+		})
+	}
+	return nil
+}
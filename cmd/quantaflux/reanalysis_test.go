@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/data"
+	"github.com/songzhibin97/quantaflux/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type tokenInfoCollector struct {
+	data.DataCollector
+	info models.TokenInfo
+}
+
+func (c *tokenInfoCollector) CollectTokenInfo(ctx context.Context, symbol string) (*models.TokenInfo, error) {
+	info := c.info
+	info.Symbol = symbol
+	return &info, nil
+}
+
+type projectAnalyzer struct {
+	*countingScamDetector
+	analyzed int
+}
+
+func (p *projectAnalyzer) AnalyzeProject(ctx context.Context, info *models.TokenInfo) (*models.ProjectMetrics, error) {
+	p.analyzed++
+	return &models.ProjectMetrics{TokenInfo: *info, RiskScore: 0.8}, nil
+}
+
+type projectMetricsRecorder struct {
+	saved []models.ProjectMetrics
+}
+
+func (r *projectMetricsRecorder) SaveProjectMetrics(ctx context.Context, metrics *models.ProjectMetrics) error {
+	r.saved = append(r.saved, *metrics)
+	return nil
+}
+
+func TestQuantSystem_ReanalysisTriggers(t *testing.T) {
+	system, _ := newTestSystem(t, map[string]float64{"USDT": 1000})
+	config := *system.cfg()
+	config.AIConfig.Reanalysis.HolderConcentration = 0.2
+	config.AIConfig.Reanalysis.SocialSpike = 3
+	config.AIConfig.Reanalysis.Cooldown = "1h"
+	system.config.Store(&config)
+
+	queued := func() []reanalysisEvent {
+		var events []reanalysisEvent
+		for len(system.reanalysis.queue) > 0 {
+			events = append(events, <-system.reanalysis.queue)
+		}
+		return events
+	}
+
+	// 首次观察只记录，团队持仓占比从 10% 升到 11% 未超过 20%
+	system.observeTokenInfo(&models.TokenInfo{Symbol: "BTCUSDT", TotalSupply: 1000, TeamAllocation: 100})
+	system.observeTokenInfo(&models.TokenInfo{Symbol: "BTCUSDT", TotalSupply: 1000, TeamAllocation: 110})
+	assert.Empty(t, queued())
+
+	// 升到 15% 触发，冷却期内再次上升不重复触发
+	system.observeTokenInfo(&models.TokenInfo{Symbol: "BTCUSDT", TotalSupply: 1000, TeamAllocation: 150})
+	system.observeTokenInfo(&models.TokenInfo{Symbol: "BTCUSDT", TotalSupply: 1000, TeamAllocation: 300})
+	events := queued()
+	require.Len(t, events, 1)
+	assert.Equal(t, "BTCUSDT", events[0].symbol)
+	assert.Equal(t, eventHolderConcentration, events[0].reason)
+
+	// 社交指标达到上次的 3 倍
+	system.observeSocial("ETHUSDT", map[string]float64{"twitter_mentions": 100, "telegram_members": 50})
+	system.observeSocial("ETHUSDT", map[string]float64{"twitter_mentions": 250, "telegram_members": 50})
+	assert.Empty(t, queued())
+	system.observeSocial("ETHUSDT", map[string]float64{"twitter_mentions": 800, "telegram_members": 50})
+	events = queued()
+	require.Len(t, events, 1)
+	assert.Equal(t, eventSocialSpike, events[0].reason)
+
+	// 回测不触发
+	config.Mode = configs.ModeBacktest
+	system.config.Store(&config)
+	system.triggerReanalysis("SOLUSDT", eventListing, "")
+	assert.Empty(t, queued())
+}
+
+func TestQuantSystem_Reanalyze(t *testing.T) {
+	ctx := context.Background()
+	system, _ := newTestSystem(t, map[string]float64{"USDT": 1000})
+	config := *system.cfg()
+	config.AIConfig.ScamThreshold = 0.7
+	config.AIConfig.ScamCheck.Interval = "6h"
+	system.config.Store(&config)
+
+	analyzer := &projectAnalyzer{countingScamDetector: &countingScamDetector{probability: 0.9}}
+	recorder := &projectMetricsRecorder{}
+	system.aiAnalyzer = analyzer
+	system.dataCollector = &tokenInfoCollector{info: models.TokenInfo{CirculatingSupply: 1000, TeamAllocation: 0.5}}
+	system.projects = recorder
+
+	require.NoError(t, system.reanalyze(ctx, reanalysisEvent{symbol: "BTCUSDT", reason: eventLiquidityDrop}))
+	assert.Equal(t, 1, analyzer.analyzed)
+	assert.Equal(t, 1, analyzer.calls)
+	require.Len(t, recorder.saved, 1)
+	assert.Equal(t, 0.8, recorder.saved[0].RiskScore)
+
+	// 之后的行情沿用事件触发的检测结论
+	metrics := &models.ProjectMetrics{TokenInfo: models.TokenInfo{CirculatingSupply: 1000, TeamAllocation: 0.5}}
+	analysis, err := system.detectScam(ctx, models.MarketData{Symbol: "BTCUSDT", Timestamp: time.Now()}, metrics)
+	require.NoError(t, err)
+	assert.Equal(t, 0.9, analysis.ScamProbability)
+	assert.Equal(t, 1, analyzer.calls)
+}
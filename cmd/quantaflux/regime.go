@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/audit"
+	"github.com/songzhibin97/quantaflux/internal/regime"
+)
+
+// positionScale 返回新订单最大仓位和最大交易量的缩放比例，未启用 regime_config 时为 1
+func (s *QuantSystem) positionScale() float64 {
+	if s.regime == nil {
+		return 1
+	}
+	return s.regime.Scale()
+}
+
+// observeVolatilityRegime K 线收盘后按各交易对的平均年化波动率调整缩放比例
+func (s *QuantSystem) observeVolatilityRegime(ctx context.Context, now time.Time) {
+	if s.regime == nil {
+		return
+	}
+	var total float64
+	var count int
+	for _, symbol := range s.cfg().Symbols {
+		if estimate, ok := s.volatility.Estimate(symbol); ok {
+			total += estimate.Annualized
+			count++
+		}
+	}
+	if count == 0 {
+		return
+	}
+	if adjustment, ok := s.regime.ObserveVolatility(total/float64(count), now); ok {
+		s.scaleRiskLimits(ctx, adjustment)
+	}
+}
+
+// observeDrawdownRegime 权益快照后按回撤调整缩放比例
+func (s *QuantSystem) observeDrawdownRegime(ctx context.Context, drawdown float64, now time.Time) {
+	if s.regime == nil {
+		return
+	}
+	if adjustment, ok := s.regime.ObserveDrawdown(drawdown, now); ok {
+		s.scaleRiskLimits(ctx, adjustment)
+	}
+}
+
+// scaleRiskLimits 将缩放比例应用到各账户及其交易对的风险管理器，并作为参数修改记录到审计日志。
+// 配置中的限额不变，热加载和 API 修改的是缩放前的值
+func (s *QuantSystem) scaleRiskLimits(ctx context.Context, adjustment regime.Adjustment) {
+	for _, a := range s.accounts {
+		a.setPositionScale(adjustment.Scale)
+	}
+	s.riskScale.Set(adjustment.Scale)
+
+	config := s.cfg()
+	details := map[string]any{
+		"previous":          adjustment.Previous,
+		"scale":             adjustment.Scale,
+		"volatility":        adjustment.Volatility,
+		"drawdown":          adjustment.Drawdown,
+		"max_position_size": config.RiskParams.MaxPositionSize * adjustment.Scale,
+		"max_order_amount":  config.TradingConfig.MaxOrderAmount * adjustment.Scale,
+	}
+	log.Info("risk limits scaled", "audit", true, "reason", adjustment.Reason, "previous", adjustment.Previous, "scale", adjustment.Scale)
+	s.audit.Record(ctx, audit.ActionScaleRiskLimits, "", adjustment.Reason, details)
+}
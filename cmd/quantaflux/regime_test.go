@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/audit"
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/regime"
+	"github.com/songzhibin97/quantaflux/internal/risk"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryAuditSink struct {
+	entries []audit.Entry
+}
+
+func (m *memoryAuditSink) AppendAudit(_ context.Context, entry *audit.Entry) error {
+	m.entries = append(m.entries, *entry)
+	return nil
+}
+
+func TestQuantSystem_RegimeScaling(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	system, _ := newTestSystem(t, map[string]float64{"USDT": 1000})
+	sink := &memoryAuditSink{}
+	system.audit = audit.NewLog(moduleLog("audit"), sink)
+	config := *system.cfg()
+	config.TradingConfig.MaxOrderAmount = 4
+	system.config.Store(&config)
+	a := system.primaryAccount()
+
+	// 未启用时不缩放
+	system.observeDrawdownRegime(ctx, 0.5, now)
+	assert.Equal(t, 4.0, system.calculateOrderAmount("BTCUSDT", 110, 100))
+
+	system.regime = regime.NewScaler(regime.Options{DrawdownTarget: 0.1, RecoveryStep: 0.5, RecoveryInterval: time.Hour})
+	system.observeDrawdownRegime(ctx, 0.2, now)
+	assert.Equal(t, 2.0, system.calculateOrderAmount("BTCUSDT", 110, 100))
+	assert.Equal(t, 0.5, system.riskScale.Value())
+
+	// 新订单按缩小后的最大仓位检查，配置中的限额不变
+	assessment, err := a.riskManager.CheckTradeRisk(ctx, &trading.Order{Symbol: "BTCUSDT", Side: "buy", Amount: 0.08, Price: 100, OrderType: "limit"})
+	require.NoError(t, err)
+	assert.False(t, assessment.IsAcceptable)
+	state, err := system.RiskState(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, 0.5, state.PositionScale)
+	assert.Equal(t, 10.0, system.cfg().RiskParams.MaxPositionSize)
+
+	require.Len(t, sink.entries, 1)
+	assert.Equal(t, audit.ActionScaleRiskLimits, sink.entries[0].Action)
+	assert.Equal(t, audit.ActorAuto, sink.entries[0].Actor)
+	assert.Contains(t, sink.entries[0].Reason, "drawdown")
+	assert.Equal(t, 2.0, sink.entries[0].Details["max_order_amount"])
+
+	// 回撤恢复后按间隔逐步恢复
+	system.observeDrawdownRegime(ctx, 0, now.Add(time.Minute))
+	assert.Equal(t, 2.0, system.calculateOrderAmount("BTCUSDT", 110, 100))
+	system.observeDrawdownRegime(ctx, 0, now.Add(2*time.Hour))
+	assert.Equal(t, 4.0, system.calculateOrderAmount("BTCUSDT", 110, 100))
+	assert.Len(t, sink.entries, 2)
+}
+
+func TestQuantSystem_RegimeScaling_SymbolLimits(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	system, _ := newTestSystem(t, map[string]float64{"USDT": 1000})
+	system.regime = regime.NewScaler(regime.Options{DrawdownTarget: 0.1})
+	a := system.primaryAccount()
+	limits := func(maxPosition float64) *risk.RiskParameters {
+		return &risk.RiskParameters{MaxPositionSize: maxPosition, MaxLossPerTrade: 100, MaxDailyLoss: 500, MaxLeverage: 1, MinLiquidity: 1000}
+	}
+	require.NoError(t, a.riskManager.SetRiskParameters(ctx, limits(1000)))
+	config := *system.cfg()
+	config.Symbols = []string{"BTCUSDT", "ETHUSDT"}
+	config.SymbolOverrides = map[string]configs.SymbolConfig{"BTCUSDT": {RiskParams: limits(10)}, "ETHUSDT": {RiskParams: limits(10)}}
+	system.config.Store(&config)
+	acceptable := func(symbol string) bool {
+		assessment, err := system.checkTradeRisk(ctx, a, &trading.Order{Symbol: symbol, Side: "buy", Amount: 0.08, Price: 100, OrderType: "limit"})
+		require.NoError(t, err)
+		return assessment.IsAcceptable
+	}
+
+	// 缩放前交易对限额内的订单可以通过，同时创建了 BTCUSDT 的交易对风险管理器
+	assert.True(t, acceptable("BTCUSDT"))
+
+	// 已创建和之后创建的交易对风险管理器都按缩小后的限额检查
+	system.observeDrawdownRegime(ctx, 0.2, now)
+	assert.False(t, acceptable("BTCUSDT"))
+	assert.False(t, acceptable("ETHUSDT"))
+
+	system.observeDrawdownRegime(ctx, 0, now.Add(48*time.Hour))
+	for i := 1; i <= 5; i++ {
+		system.observeDrawdownRegime(ctx, 0, now.Add(time.Duration(48+i)*time.Hour))
+	}
+	assert.Equal(t, 1.0, system.positionScale())
+	assert.True(t, acceptable("BTCUSDT"))
+	assert.True(t, acceptable("ETHUSDT"))
+}
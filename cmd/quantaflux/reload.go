@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/audit"
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/risk"
+)
+
+// 配置文件变化轮询间隔
+const configPollInterval = 5 * time.Second
+
+// watchConfig 监听 SIGHUP 信号和配置文件修改时间，触发热加载
+func (s *QuantSystem) watchConfig(ctx context.Context, path string) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	ticker := time.NewTicker(configPollInterval)
+	defer ticker.Stop()
+
+	lastMod := modTime(path)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-hup:
+			log.Info("received SIGHUP, reloading config")
+			lastMod = modTime(path)
+			if err := s.reloadConfig(ctx, path); err != nil {
+				log.Error("Error reloading config", "err", err)
+			}
+
+		case <-ticker.C:
+			mod := modTime(path)
+			if mod.Equal(lastMod) {
+				continue
+			}
+			lastMod = mod
+
+			log.Info("config file changed, reloading config")
+			if err := s.reloadConfig(ctx, path); err != nil {
+				log.Error("Error reloading config", "err", err)
+			}
+		}
+	}
+}
+
+// reloadConfig 加载、校验并原子替换配置，需重启才能生效的配置项保持不变
+func (s *QuantSystem) reloadConfig(ctx context.Context, path string) error {
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+
+	loaded, err := configs.Load(path)
+	if err != nil {
+		return err
+	}
+
+	current := s.cfg()
+	file := s.fileConfig
+	s.fileConfig = loaded
+	loaded = keepRiskParams(loaded, file, current)
+	loaded = keepPromotedSymbols(loaded, s.promoted)
+	// 通过参数注册表修改的阈值优先于配置文件
+	loaded = s.applyParams(loaded)
+
+	next := mergeHotReloadable(current, loaded)
+	if ignored := configs.Diff(next, loaded); len(ignored) > 0 {
+		log.Warn("config changes require restart and were ignored", "changes", ignored)
+	}
+
+	changes := configs.Diff(current, next)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	if err := s.applyRiskParams(ctx, current, next); err != nil {
+		return err
+	}
+
+	s.config.Store(next)
+	log.Info("config reloaded", "audit", true, "changes", changes)
+	s.audit.Record(ctx, audit.ActionReloadConfig, path, "config file changed", map[string]any{"changes": changes})
+
+	if !slices.Equal(subscriptions(current, s.symbolTags), subscriptions(next, s.symbolTags)) {
+		select {
+		case s.reloadCh <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// applyRiskParams 全局风险参数变化时更新各账户的风险管理器，只作用于未单独配置风险限额的账户
+func (s *QuantSystem) applyRiskParams(ctx context.Context, current, next *configs.Config) error {
+	if next.RiskParams == current.RiskParams {
+		return nil
+	}
+	for _, a := range s.accounts {
+		if accountRiskParams(next, a.name) != nil {
+			continue
+		}
+		if err := a.riskManager.SetRiskParameters(ctx, &next.RiskParams); err != nil {
+			return fmt.Errorf("failed to set risk parameters of account %s: %w", a.name, err)
+		}
+	}
+	return nil
+}
+
+// keepRiskParams 配置文件中的风险参数与上次加载时相同时保留当前生效的值（可能已通过 API 修改）
+func keepRiskParams(loaded, file, current *configs.Config) *configs.Config {
+	merged := *loaded
+	if loaded.RiskParams == file.RiskParams {
+		merged.RiskParams = current.RiskParams
+	}
+
+	merged.Accounts = slices.Clone(loaded.Accounts)
+	for i, ac := range merged.Accounts {
+		if equalRiskParams(ac.RiskParams, accountRiskParams(file, ac.Name)) {
+			merged.Accounts[i].RiskParams = accountRiskParams(current, ac.Name)
+		}
+	}
+	return &merged
+}
+
+func equalRiskParams(a, b *risk.RiskParameters) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// mergeHotReloadable 在当前配置基础上应用可热加载的配置项
+func mergeHotReloadable(current, loaded *configs.Config) *configs.Config {
+	merged := *current
+	merged.Symbols = loaded.Symbols
+	merged.RefreshInterval = loaded.RefreshInterval
+	merged.RiskParams = loaded.RiskParams
+	merged.AIConfig.MinConfidence = loaded.AIConfig.MinConfidence
+	merged.AIConfig.ScamThreshold = loaded.AIConfig.ScamThreshold
+	merged.AIConfig.PredictTimeFrame = loaded.AIConfig.PredictTimeFrame
+	merged.AIConfig.PredictHistory = loaded.AIConfig.PredictHistory
+	merged.TradingConfig = loaded.TradingConfig
+	merged.SymbolOverrides = loaded.SymbolOverrides
+	merged.AutoDisableConfig = loaded.AutoDisableConfig
+	merged.LatencyBudget = loaded.LatencyBudget
+	merged.ShutdownConfig = loaded.ShutdownConfig
+	merged.ErrorPolicy = loaded.ErrorPolicy
+	return &merged
+}
+
+func modTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
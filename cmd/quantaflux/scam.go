@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/ai"
+	"github.com/songzhibin97/quantaflux/internal/models"
+)
+
+// scamInputs 诈骗检测时代币的持仓和流动性，相对上次检测变化较大时需要重新检测
+type scamInputs struct {
+	circulatingSupply float64
+	teamAllocation    float64
+	liquidity         float64 // 流动性池规模，未监控时为 0
+}
+
+// scamVerdict 交易对最近一次诈骗检测的结论
+type scamVerdict struct {
+	analysis  ai.ScamAnalysis
+	inputs    scamInputs
+	checkedAt time.Time // 检测时的行情时间
+}
+
+// scamCache 按交易对缓存诈骗检测结论
+type scamCache struct {
+	mu       sync.Mutex
+	verdicts map[string]scamVerdict
+}
+
+func newScamCache() *scamCache {
+	return &scamCache{verdicts: make(map[string]scamVerdict)}
+}
+
+func (c *scamCache) get(symbol string) (scamVerdict, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	verdict, ok := c.verdicts[symbol]
+	return verdict, ok
+}
+
+func (c *scamCache) set(symbol string, verdict scamVerdict) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.verdicts[symbol] = verdict
+}
+
+// scamInputs 返回代币当前的持仓和流动性
+func (s *QuantSystem) scamInputs(symbol string, info models.TokenInfo) scamInputs {
+	inputs := scamInputs{
+		circulatingSupply: info.CirculatingSupply,
+		teamAllocation:    info.TeamAllocation,
+	}
+	if s.liquidity != nil {
+		if pool, ok := s.liquidity.Latest(symbol); ok {
+			inputs.liquidity = pool.Liquidity
+		}
+	}
+	return inputs
+}
+
+// scamRecheckReason 返回需要重新检测的原因，可以沿用缓存结论时返回空字符串
+func (s *QuantSystem) scamRecheckReason(cached scamVerdict, inputs scamInputs, now time.Time) string {
+	policy := s.cfg().AIConfig.ScamCheck
+	switch {
+	case now.Sub(cached.checkedAt) >= policy.CheckInterval():
+		return "interval"
+	case policy.MaxHolderChange > 0 && (relativeChange(cached.inputs.circulatingSupply, inputs.circulatingSupply) > policy.MaxHolderChange ||
+		relativeChange(cached.inputs.teamAllocation, inputs.teamAllocation) > policy.MaxHolderChange):
+		return "holder_change"
+	case policy.MaxLiquidityChange > 0 && relativeChange(cached.inputs.liquidity, inputs.liquidity) > policy.MaxLiquidityChange:
+		return "liquidity_change"
+	default:
+		return ""
+	}
+}
+
+// relativeChange 返回相对变化比例，原值为 0 时出现数据视为完全变化
+func relativeChange(previous, current float64) float64 {
+	if previous == 0 {
+		if current == 0 {
+			return 0
+		}
+		return math.Inf(1)
+	}
+	return math.Abs(current-previous) / math.Abs(previous)
+}
+
+// detectScam 按复查策略进行诈骗检测：间隔内且持仓和流动性没有大幅变化时沿用缓存结论；
+// 重新检测失败时，有效期内的缓存结论继续生效
+func (s *QuantSystem) detectScam(ctx context.Context, data models.MarketData, metrics *models.ProjectMetrics) (*ai.ScamAnalysis, error) {
+	inputs := s.scamInputs(data.Symbol, metrics.TokenInfo)
+	cached, ok := s.scams.get(data.Symbol)
+	if ok {
+		reason := s.scamRecheckReason(cached, inputs, data.Timestamp)
+		if reason == "" {
+			s.scamChecks.Inc(data.Symbol, "cached")
+			return &cached.analysis, nil
+		}
+		log.Debug("re-running scam detection", "symbol", data.Symbol, "reason", reason)
+	}
+
+	analysis, err := s.aiAnalyzer.DetectScam(ctx, metrics)
+	s.recordAIResult(err)
+	if err != nil {
+		expiry := s.cfg().AIConfig.ScamCheck.VerdictExpiry()
+		if ok && data.Timestamp.Sub(cached.checkedAt) < expiry && ctx.Err() == nil {
+			log.Warn("scam detection failed, using cached verdict", "symbol", data.Symbol, "checked_at", cached.checkedAt, "err", err)
+			s.scamChecks.Inc(data.Symbol, "cached")
+			return &cached.analysis, nil
+		}
+		return nil, err
+	}
+
+	s.scamChecks.Inc(data.Symbol, "checked")
+	s.scams.set(data.Symbol, scamVerdict{analysis: *analysis, inputs: inputs, checkedAt: data.Timestamp})
+	return analysis, nil
+}
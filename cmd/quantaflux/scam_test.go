@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/ai"
+	"github.com/songzhibin97/quantaflux/internal/models"
+	"github.com/songzhibin97/quantaflux/internal/risk"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingScamDetector struct {
+	ai.Analyzer
+	calls       int
+	probability float64
+	err         error
+}
+
+func (c *countingScamDetector) DetectScam(ctx context.Context, projectData *models.ProjectMetrics) (*ai.ScamAnalysis, error) {
+	c.calls++
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &ai.ScamAnalysis{ScamProbability: c.probability}, nil
+}
+
+type fixedPool struct {
+	liquidity float64
+}
+
+func (f *fixedPool) PoolState(ctx context.Context, token risk.LiquidityToken) (*risk.PoolState, error) {
+	return &risk.PoolState{Pool: "0xpair", Liquidity: f.liquidity}, nil
+}
+
+func TestQuantSystem_DetectScamPolicy(t *testing.T) {
+	ctx := context.Background()
+	system, _ := newTestSystem(t, map[string]float64{"USDT": 1000})
+	config := *system.cfg()
+	config.AIConfig.ScamCheck.Interval = "6h"
+	config.AIConfig.ScamCheck.Expiry = "24h"
+	config.AIConfig.ScamCheck.MaxHolderChange = 0.1
+	config.AIConfig.ScamCheck.MaxLiquidityChange = 0.2
+	system.config.Store(&config)
+
+	detector := &countingScamDetector{probability: 0.1}
+	system.aiAnalyzer = detector
+	pool := &fixedPool{liquidity: 100}
+	token := risk.LiquidityToken{Symbol: "BTCUSDT"}
+	system.liquidity = risk.NewLiquidityMonitor(pool, nil, 0, 0)
+	_, err := system.liquidity.Check(ctx, token)
+	require.NoError(t, err)
+
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	metrics := &models.ProjectMetrics{TokenInfo: models.TokenInfo{CirculatingSupply: 1000, TeamAllocation: 0.2}}
+	detect := func(at time.Duration) *ai.ScamAnalysis {
+		analysis, err := system.detectScam(ctx, models.MarketData{Symbol: "BTCUSDT", Timestamp: start.Add(at)}, metrics)
+		require.NoError(t, err)
+		return analysis
+	}
+
+	detect(0)
+	detect(time.Hour)
+	assert.Equal(t, 1, detector.calls)
+
+	// 流通量小幅变化沿用结论，超过 10% 立即重新检测
+	metrics.TokenInfo.CirculatingSupply = 1050
+	detect(2 * time.Hour)
+	assert.Equal(t, 1, detector.calls)
+	metrics.TokenInfo.CirculatingSupply = 1200
+	detect(2 * time.Hour)
+	assert.Equal(t, 2, detector.calls)
+
+	// 流动性被撤出 30%
+	pool.liquidity = 70
+	_, err = system.liquidity.Check(ctx, token)
+	require.NoError(t, err)
+	detector.probability = 0.9
+	assert.InDelta(t, 0.9, detect(3*time.Hour).ScamProbability, 1e-9)
+	assert.Equal(t, 3, detector.calls)
+
+	// 到达间隔后重新检测，失败时在有效期内沿用上次结论
+	detector.err = errors.New("ai unavailable")
+	assert.InDelta(t, 0.9, detect(10*time.Hour).ScamProbability, 1e-9)
+	assert.Equal(t, 4, detector.calls)
+
+	_, err = system.detectScam(ctx, models.MarketData{Symbol: "BTCUSDT", Timestamp: start.Add(30 * time.Hour)}, metrics)
+	assert.Error(t, err)
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/models"
+	"github.com/songzhibin97/quantaflux/internal/sentiment"
+)
+
+// sentimentTracker 缓存各交易对统计窗口内的情绪分数
+type sentimentTracker struct {
+	mu     sync.Mutex
+	scores map[string][]sentiment.Score // 交易对 -> 按时间排序的情绪分数
+}
+
+func newSentimentTracker() *sentimentTracker {
+	return &sentimentTracker{scores: make(map[string][]sentiment.Score)}
+}
+
+// loaded 交易对是否已有缓存，没有时需先从存储加载历史分数
+func (t *sentimentTracker) loaded(symbol string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	_, ok := t.scores[symbol]
+	return ok
+}
+
+// add 记录情绪分数并丢弃窗口外的分数，返回窗口内分数的副本
+func (t *sentimentTracker) add(score sentiment.Score, window time.Duration) []sentiment.Score {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	cutoff := score.Timestamp.Add(-window)
+	scores := slices.DeleteFunc(append(t.scores[score.Symbol], score), func(s sentiment.Score) bool {
+		return s.Timestamp.Before(cutoff)
+	})
+	t.scores[score.Symbol] = scores
+	return slices.Clone(scores)
+}
+
+// sentimentMomentum 保存本次情绪分数，返回统计窗口内的情绪动量。
+// 重启后首次分析交易对时从存储加载窗口内的历史分数
+func (s *QuantSystem) sentimentMomentum(ctx context.Context, data models.MarketData, score float64) sentiment.Momentum {
+	window := s.cfg().SentimentConfig.MomentumWindow()
+	current := sentiment.Score{Symbol: data.Symbol, Score: score, Price: data.Price, Timestamp: data.Timestamp}
+
+	if s.sentiments != nil {
+		if !s.sentimentHistory.loaded(data.Symbol) {
+			history, err := s.sentiments.ListSentiment(ctx, data.Symbol, data.Timestamp.Add(-window), data.Timestamp)
+			if err != nil {
+				log.Error("Error loading sentiment history", "symbol", data.Symbol, "err", err)
+			}
+			for _, h := range history {
+				s.sentimentHistory.add(h, window)
+			}
+		}
+		if err := s.sentiments.SaveSentiment(ctx, &current); err != nil {
+			log.Error("Error saving sentiment score", "symbol", data.Symbol, "err", err)
+		}
+	}
+
+	return sentiment.Compute(s.sentimentHistory.add(current, window))
+}
+
+// sentimentAgainst 返回情绪动量不支持开仓的原因。与趋势过滤一致只过滤买入，卖出平仓不受限制
+func (s *QuantSystem) sentimentAgainst(side string, momentum sentiment.Momentum) string {
+	if side != "buy" {
+		return ""
+	}
+	config := s.cfg().SentimentConfig
+	if config.MaxDecline > 0 && -momentum.Change > config.MaxDecline {
+		return "declining"
+	}
+	if config.BlockDivergence && momentum.Divergence < 0 {
+		return "bearish_divergence"
+	}
+	return ""
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/models"
+	"github.com/songzhibin97/quantaflux/internal/sentiment"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type memorySentimentStore struct {
+	scores []sentiment.Score
+}
+
+func (m *memorySentimentStore) SaveSentiment(ctx context.Context, score *sentiment.Score) error {
+	score.ID = int64(len(m.scores) + 1)
+	m.scores = append(m.scores, *score)
+	return nil
+}
+
+func (m *memorySentimentStore) ListSentiment(ctx context.Context, symbol string, start, end time.Time) ([]sentiment.Score, error) {
+	var result []sentiment.Score
+	for _, score := range m.scores {
+		if score.Symbol == symbol && !score.Timestamp.Before(start) && !score.Timestamp.After(end) {
+			result = append(result, score)
+		}
+	}
+	return result, nil
+}
+
+func TestQuantSystem_SentimentMomentum(t *testing.T) {
+	ctx := context.Background()
+	system, _ := newTestSystem(t, map[string]float64{"USDT": 1000})
+	config := *system.cfg()
+	config.SentimentConfig.Window = "2h"
+	config.SentimentConfig.MaxDecline = 0.3
+	system.config.Store(&config)
+
+	// 重启前保存的分数，窗口外的不参与统计
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	store := &memorySentimentStore{scores: []sentiment.Score{
+		{Symbol: "BTCUSDT", Score: -0.9, Price: 90, Timestamp: start.Add(-3 * time.Hour)},
+		{Symbol: "BTCUSDT", Score: 0.6, Price: 100, Timestamp: start},
+	}}
+	system.sentiments = store
+
+	tick := models.MarketData{Symbol: "BTCUSDT", Price: 105, Timestamp: start.Add(time.Hour)}
+	momentum := system.sentimentMomentum(ctx, tick, 0.4)
+	assert.Equal(t, 2, momentum.Samples)
+	assert.InDelta(t, -0.2, momentum.Change, 1e-9)
+	assert.Len(t, store.scores, 3)
+	assert.Empty(t, system.sentimentAgainst("buy", momentum))
+
+	// 两小时前的分数移出窗口
+	tick = models.MarketData{Symbol: "BTCUSDT", Price: 110, Timestamp: start.Add(150 * time.Minute)}
+	momentum = system.sentimentMomentum(ctx, tick, 0)
+	assert.Equal(t, 2, momentum.Samples)
+	assert.InDelta(t, -0.4, momentum.Change, 1e-9)
+	assert.Equal(t, "declining", system.sentimentAgainst("buy", momentum))
+	assert.Empty(t, system.sentimentAgainst("sell", momentum))
+
+	config.SentimentConfig.MaxDecline = 0
+	config.SentimentConfig.BlockDivergence = true
+	system.config.Store(&config)
+	assert.Equal(t, "bearish_divergence", system.sentimentAgainst("buy", momentum))
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/api"
+	"github.com/songzhibin97/quantaflux/internal/audit"
+	"github.com/songzhibin97/quantaflux/internal/risk"
+	"github.com/songzhibin97/quantaflux/internal/state"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+)
+
+// 恢复时余额差异超过该值视为不一致
+const balanceTolerance = 1e-8
+
+// Snapshot implements api.System，保存持仓、挂单、风险状态、当日统计和策略状态
+func (s *QuantSystem) Snapshot(ctx context.Context, label string) (*state.Snapshot, error) {
+	if s.snapshots == nil {
+		return nil, api.ErrSnapshotUnavailable
+	}
+
+	snapshot := &state.Snapshot{
+		Label: label,
+		Mode:  s.cfg().RunMode(),
+		Loop:  *s.snapshotState(),
+		Strategy: state.StrategyState{
+			Trends: s.trends.export(),
+		},
+		CreatedAt: time.Now(),
+	}
+
+	s.configMu.Lock()
+	snapshot.Strategy.PromotedSymbols = slices.Clone(s.promoted)
+	s.configMu.Unlock()
+
+	openOrders := s.openOrderList()
+	for _, a := range s.accounts {
+		accountSnapshot, err := s.accountSnapshot(ctx, a, openOrders)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Accounts = append(snapshot.Accounts, *accountSnapshot)
+	}
+
+	if err := s.snapshots.SaveSnapshot(ctx, snapshot); err != nil {
+		return nil, err
+	}
+	log.Info("system snapshot saved", "id", snapshot.ID, "label", label, "accounts", len(snapshot.Accounts))
+	return snapshot, nil
+}
+
+// accountSnapshot 返回账户在各交易对涉及资产的余额、挂单和风险状态
+func (s *QuantSystem) accountSnapshot(ctx context.Context, a *account, openOrders []trading.Order) (*state.AccountSnapshot, error) {
+	result := &state.AccountSnapshot{
+		Name:     a.name,
+		Balances: make(map[string]float64),
+	}
+
+	for _, symbol := range s.cfg().Symbols {
+		if !a.trades(symbol) {
+			continue
+		}
+		base, quote, ok := trading.SplitSymbol(symbol)
+		if !ok {
+			continue
+		}
+		for _, asset := range []string{base, quote} {
+			if _, ok := result.Balances[asset]; ok {
+				continue
+			}
+			amount, err := accountBalance(ctx, a, asset)
+			if err != nil {
+				return nil, err
+			}
+			result.Balances[asset] = amount
+		}
+	}
+
+	for _, order := range openOrders {
+		if order.Account == a.name {
+			result.OpenOrders = append(result.OpenOrders, order)
+		}
+	}
+
+	riskState, err := a.riskManager.GetRiskState(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get risk state of account %s: %w", a.name, err)
+	}
+	result.Risk = *riskState
+
+	if executor, ok := trading.As[trading.StatefulExecutor](a.executor); ok {
+		simulated := executor.ExportState()
+		result.Simulated = &simulated
+	}
+	return result, nil
+}
+
+// restoreSnapshot 启动时从当前运行模式最近的快照恢复。模拟账户恢复余额和挂单，
+// 实盘账户以交易所为准，只提示余额差异；未确认的下单意图不恢复，由对账处理
+func (s *QuantSystem) restoreSnapshot(ctx context.Context) error {
+	if s.snapshots == nil {
+		return api.ErrSnapshotUnavailable
+	}
+
+	snapshot, err := s.snapshots.LatestSnapshot(ctx, s.cfg().RunMode())
+	if err != nil {
+		return err
+	}
+	if snapshot == nil {
+		log.Warn("no snapshot to restore", "mode", s.cfg().RunMode())
+		return nil
+	}
+
+	for _, accountSnapshot := range snapshot.Accounts {
+		a, err := s.account(accountSnapshot.Name)
+		if err != nil || a.name != accountSnapshot.Name {
+			log.Warn("snapshot account not configured, skipped", "account", accountSnapshot.Name)
+			continue
+		}
+		if err := s.restoreAccount(ctx, a, accountSnapshot); err != nil {
+			return err
+		}
+	}
+
+	s.applyLoopState(&snapshot.Loop)
+	s.trends.restore(snapshot.Strategy.Trends)
+	s.restorePromoted(snapshot.Strategy.PromotedSymbols)
+
+	log.Info("restored system snapshot", "id", snapshot.ID, "label", snapshot.Label, "created_at", snapshot.CreatedAt)
+	s.audit.Record(ctx, audit.ActionRestoreSnapshot, "", "startup restore", map[string]any{"id": snapshot.ID, "label": snapshot.Label})
+	s.persistState(ctx)
+	return nil
+}
+
+// restoreAccount 恢复账户的模拟状态、风险参数和当日统计
+func (s *QuantSystem) restoreAccount(ctx context.Context, a *account, snapshot state.AccountSnapshot) error {
+	if executor, ok := trading.As[trading.StatefulExecutor](a.executor); ok && snapshot.Simulated != nil {
+		executor.RestoreState(*snapshot.Simulated)
+		for _, order := range snapshot.Simulated.OpenOrders {
+			order.Account = a.name
+			s.trackOrder(order)
+		}
+	} else {
+		for asset, expected := range snapshot.Balances {
+			actual, err := accountBalance(ctx, a, asset)
+			if err != nil {
+				return err
+			}
+			if math.Abs(actual-expected) > balanceTolerance {
+				log.Warn("balance differs from snapshot", "account", a.name, "asset", asset, "snapshot", expected, "actual", actual)
+			}
+		}
+	}
+
+	current, err := a.riskManager.GetRiskState(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get risk state of account %s: %w", a.name, err)
+	}
+	if current.Parameters != snapshot.Risk.Parameters {
+		params := snapshot.Risk.Parameters
+		if err := s.SetRiskParameters(ctx, a.name, &params); err != nil {
+			return err
+		}
+	}
+	if restorer, ok := a.riskManager.(risk.StatsRestorer); ok {
+		restorer.RestoreDailyStats(&snapshot.Risk)
+	}
+	return nil
+}
+
+// restorePromoted 恢复代币发现加入的交易对，已在交易列表中的不重复加入
+func (s *QuantSystem) restorePromoted(symbols []string) {
+	if len(symbols) == 0 {
+		return
+	}
+
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+
+	for _, symbol := range symbols {
+		if !slices.Contains(s.promoted, symbol) {
+			s.promoted = append(s.promoted, symbol)
+		}
+	}
+	s.config.Store(keepPromotedSymbols(s.cfg(), s.promoted))
+}
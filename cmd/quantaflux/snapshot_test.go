@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/api"
+	"github.com/songzhibin97/quantaflux/internal/models"
+	"github.com/songzhibin97/quantaflux/internal/risk"
+	"github.com/songzhibin97/quantaflux/internal/state"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memorySnapshotStore struct {
+	snapshots []state.Snapshot
+}
+
+func (m *memorySnapshotStore) SaveSnapshot(ctx context.Context, snapshot *state.Snapshot) error {
+	snapshot.ID = int64(len(m.snapshots) + 1)
+	m.snapshots = append(m.snapshots, *snapshot)
+	return nil
+}
+
+func (m *memorySnapshotStore) LatestSnapshot(ctx context.Context, mode string) (*state.Snapshot, error) {
+	for i := len(m.snapshots) - 1; i >= 0; i-- {
+		if m.snapshots[i].Mode == mode {
+			snapshot := m.snapshots[i]
+			return &snapshot, nil
+		}
+	}
+	return nil, nil
+}
+
+func TestQuantSystem_SnapshotRestore(t *testing.T) {
+	ctx := context.Background()
+	store := &memorySnapshotStore{}
+
+	old, _ := newTestSystem(t, map[string]float64{"USDT": 1000})
+	old.snapshots = store
+	a := old.primaryAccount()
+
+	tick := models.MarketData{Symbol: "BTCUSDT", Price: 100, Timestamp: time.Now()}
+	old.updateMarketData(tick)
+	a.executor.(trading.MarketPriceUpdater).UpdateMarketPrice(tick.Symbol, tick.Price)
+	require.True(t, old.markTick(tick))
+
+	buy := &trading.Order{Account: a.name, Symbol: "BTCUSDT", Side: "buy", Amount: 2, OrderType: "market"}
+	require.NoError(t, a.executor.PlaceOrder(ctx, buy))
+	a.riskManager.(risk.StatsRestorer).RestoreDailyStats(&risk.RiskState{DailyLoss: 50, DailyTradeCount: 3, StatsReset: time.Now()})
+	old.PauseSymbol("BTCUSDT")
+	old.trends.update(models.MarketData{Symbol: "BTCUSDT", Timeframe: "4h", Price: 95})
+	old.trends.update(models.MarketData{Symbol: "BTCUSDT", Timeframe: "4h", Price: 90})
+	old.promoted = []string{"PEPEUSDT"}
+
+	saved, err := old.Snapshot(ctx, "v2")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), saved.ID)
+	require.Len(t, saved.Accounts, 1)
+	assert.InDelta(t, 800, saved.Accounts[0].Balances["USDT"], 1e-9)
+	assert.InDelta(t, 2, saved.Accounts[0].Balances["BTC"], 1e-9)
+	require.NotNil(t, saved.Accounts[0].Simulated)
+
+	// 新实例从快照接管
+	next, _ := newTestSystem(t, map[string]float64{"USDT": 1000})
+	next.snapshots = store
+	require.NoError(t, next.restoreSnapshot(ctx))
+
+	btc, err := next.primaryAccount().executor.GetBalance(ctx, "BTC")
+	require.NoError(t, err)
+	assert.InDelta(t, 2, btc, 1e-9)
+	usdt, err := next.primaryAccount().executor.GetBalance(ctx, "USDT")
+	require.NoError(t, err)
+	assert.InDelta(t, 800, usdt, 1e-9)
+
+	assert.Equal(t, []string{"BTCUSDT"}, next.PausedSymbols())
+	assert.False(t, next.markTick(tick))
+	assert.Equal(t, trendDown, next.trends.direction("BTCUSDT", "4h"))
+	assert.Contains(t, next.cfg().Symbols, "PEPEUSDT")
+
+	riskState, err := next.primaryAccount().riskManager.GetRiskState(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, riskState.DailyTradeCount)
+	assert.InDelta(t, 50, riskState.DailyLoss, 1e-9)
+}
+
+func TestQuantSystem_SnapshotUnavailable(t *testing.T) {
+	system, _ := newTestSystem(t, map[string]float64{"USDT": 1000})
+	_, err := system.Snapshot(context.Background(), "")
+	assert.ErrorIs(t, err, api.ErrSnapshotUnavailable)
+}
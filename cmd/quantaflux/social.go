@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/data"
+	"github.com/songzhibin97/quantaflux/internal/models"
+	"github.com/songzhibin97/quantaflux/internal/social"
+)
+
+// withTickTime 将行情时间标记到 context，回测的数据源据此只返回当时已发生的数据
+func withTickTime(ctx context.Context, tick models.MarketData) context.Context {
+	return data.WithAsOf(ctx, tick.Timestamp)
+}
+
+// recordSocial 保存本次采集到的原始社交指标和社交分数，供之后的回测按时间点查询；回测和未配置存储时不保存，失败只记录日志
+func (s *QuantSystem) recordSocial(ctx context.Context, symbol string, metrics map[string]float64, score social.Score, at time.Time) {
+	if s.socials == nil || len(metrics) == 0 {
+		return
+	}
+	snapshot := &models.SocialSnapshot{Symbol: symbol, Metrics: metrics, Score: score.Total, Components: score.Components, Timestamp: at}
+	if err := s.socials.SaveSocialSnapshot(ctx, snapshot); err != nil {
+		log.Error("Error saving social snapshot", "symbol", symbol, "err", err)
+	}
+}
+
+// cmdImportSocial 从 JSON Lines 文件导入历史社交指标快照，每行一个 {"symbol", "metrics", "timestamp"} 对象，供回测使用
+func cmdImportSocial(args []string) error {
+	fs, conf := newFlagSet("import-social")
+	file := fs.String("file", "", "JSON Lines file of social snapshots")
+	_ = fs.Parse(args)
+
+	if *file == "" {
+		return fmt.Errorf("-file is required")
+	}
+	f, err := os.Open(*file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	quietLogs()
+	a, err := loadApp(*conf)
+	if err != nil {
+		return err
+	}
+	defer a.closeStorage()
+
+	ctx := context.Background()
+	imported := make(map[string]int)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var snapshot models.SocialSnapshot
+		if err := json.Unmarshal(scanner.Bytes(), &snapshot); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		if snapshot.Symbol == "" || snapshot.Timestamp.IsZero() {
+			return fmt.Errorf("line %d: symbol and timestamp are required", line)
+		}
+		if err := a.storage.SaveSocialSnapshot(ctx, &snapshot); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		imported[snapshot.Symbol]++
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	return printJSON(imported)
+}
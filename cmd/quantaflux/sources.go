@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/data/collector/binance"
+	"github.com/songzhibin97/quantaflux/internal/data/collector/coingecko"
+	"github.com/songzhibin97/quantaflux/internal/data/collector/twitter"
+	"github.com/songzhibin97/quantaflux/internal/exchangeinfo"
+
+	collectorData "github.com/songzhibin97/quantaflux/internal/data/collector"
+)
+
+// buildSources 按 market_data_config.sources 创建数据源，按优先级排列并限制请求频率；
+// 启用了 Binance 时同时返回它，用于订阅 K 线推送
+func buildSources(config *configs.Config, info *exchangeinfo.Service) ([]collectorData.DataSource, *binance.BinanceDataSource, error) {
+	var sources []collectorData.DataSource
+	var binanceSource *binance.BinanceDataSource
+	for _, sc := range config.MarketDataConfig.DataSourceConfigs() {
+		var source collectorData.DataSource
+		switch sc.Name {
+		case configs.DataSourceBinance:
+			binanceSource = binance.NewBinanceDataSource()
+			binanceSource.SetExchangeInfo(info)
+			source = binanceSource
+		case configs.DataSourceCoinGecko:
+			source = coingecko.NewCoinGeckoDataSource(sc.BaseURL, sc.APIKey, sc.IDs)
+		case configs.DataSourceTwitter:
+			source = twitter.NewTwitterDataSource(sc.BaseURL, sc.APIKey, sc.IDs)
+		default:
+			return nil, nil, fmt.Errorf("unknown data source: %s", sc.Name)
+		}
+		sources = append(sources, collectorData.RateLimited(source, sc.RateLimit))
+	}
+	return sources, binanceSource, nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/notify"
+	"github.com/songzhibin97/quantaflux/internal/risk"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+)
+
+// spendState 全局下单名义金额的统计，以及各时间窗口上次通知的时间
+type spendState struct {
+	governor *risk.SpendGovernor
+
+	mu       sync.Mutex
+	notified map[time.Duration]time.Time
+}
+
+func newSpendState() *spendState {
+	return &spendState{governor: risk.NewSpendGovernor(), notified: make(map[time.Duration]time.Time)}
+}
+
+// shouldNotify 每个时间窗口内只通知一次
+func (s *spendState) shouldNotify(window time.Duration, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if last, ok := s.notified[window]; ok && now.Sub(last) < window {
+		return false
+	}
+	s.notified[window] = now
+	return true
+}
+
+// orderNotional 返回订单按估值资产计的名义金额，按计价资产下单时使用下单金额，否则按数量和价格估算
+func (s *QuantSystem) orderNotional(order *trading.Order, currentPrice float64) (float64, error) {
+	notional := order.QuoteAmount
+	if notional <= 0 {
+		price := order.Price
+		if order.OrderType != "limit" || price <= 0 {
+			price = currentPrice
+		}
+		notional = order.Amount * price
+	}
+
+	_, quote, ok := trading.SplitSymbol(order.Symbol)
+	if !ok || quote == s.cfg().ValuationConfig.Asset() {
+		return notional, nil
+	}
+	return s.value(notional, quote)
+}
+
+// reserveSpend 在风险评估前按 trading_config.spend_limit 占用全局下单额度，超过任一时间窗口的上限时拒绝订单。
+// 订单最终没有下单时调用返回的 release 归还额度
+func (s *QuantSystem) reserveSpend(ctx context.Context, order *trading.Order, currentPrice float64) (func(), error) {
+	limits := s.cfg().TradingConfig.SpendLimit.Limits()
+	if len(limits) == 0 {
+		return func() {}, nil
+	}
+
+	notional, err := s.orderNotional(order, currentPrice)
+	if err != nil {
+		// 无法估算金额时不放行，避免绕过限额
+		return func() {}, fmt.Errorf("%w: %s %s: %w", risk.ErrRiskRejected, order.Side, order.Symbol, err)
+	}
+
+	now := s.clock.Now()
+	release, err := s.spend.governor.Reserve(now, notional, limits)
+	if err == nil {
+		return release, nil
+	}
+
+	var window time.Duration
+	var limitErr *risk.SpendLimitError
+	if errors.As(err, &limitErr) {
+		window = limitErr.Limit.Window
+	}
+	s.spendLimited.Inc(window.String())
+	log.Warn("order rejected by spend limit", "account", order.Account, "symbol", order.Symbol, "side", order.Side, "notional", notional, "err", err)
+	if s.spend.shouldNotify(window, now) {
+		s.notify(ctx, notify.Message{
+			Title: "Spend limit reached",
+			Text:  fmt.Sprintf("%s %s %s of %.2f rejected: %v. New orders in all symbols stay blocked until the window rolls over.", order.Account, order.Side, order.Symbol, notional, err),
+			Level: notify.LevelWarning,
+		})
+	}
+	return func() {}, fmt.Errorf("%w: %s %s: %w", risk.ErrRiskRejected, order.Side, order.Symbol, err)
+}
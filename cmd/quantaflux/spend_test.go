@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/risk"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuantSystem_ReserveSpend(t *testing.T) {
+	ctx := context.Background()
+	system, _ := newTestSystem(t, map[string]float64{"USDT": 1000})
+	order := &trading.Order{Account: "main", Symbol: "BTCUSDT", Side: "buy", OrderType: "market", QuoteAmount: 100}
+
+	// 未配置上限时不限制
+	_, err := system.reserveSpend(ctx, order, 50000)
+	require.NoError(t, err)
+
+	config := *system.cfg()
+	config.TradingConfig.SpendLimit = configs.SpendLimitConfig{PerMinute: 150}
+	system.config.Store(&config)
+
+	release, err := system.reserveSpend(ctx, order, 50000)
+	require.NoError(t, err)
+
+	// 按数量下单时按当前价格估算金额：0.002 * 50000 = 100
+	base := &trading.Order{Account: "main", Symbol: "BTCUSDT", Side: "buy", OrderType: "market", Amount: 0.002}
+	_, err = system.reserveSpend(ctx, base, 50000)
+	assert.ErrorIs(t, err, risk.ErrRiskRejected)
+	assert.ErrorIs(t, err, risk.ErrSpendLimit)
+	assert.Equal(t, configs.ErrorClassRisk, classifyError(err))
+
+	// 没有下单的订单归还额度后可以继续下单
+	release()
+	_, err = system.reserveSpend(ctx, base, 50000)
+	assert.NoError(t, err)
+}
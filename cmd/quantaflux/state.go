@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/audit"
+	"github.com/songzhibin97/quantaflux/internal/models"
+	"github.com/songzhibin97/quantaflux/internal/state"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+)
+
+// 状态保存超时时间，用于没有上层 ctx 的调用（如 API 触发的暂停）
+const stateSaveTimeout = 5 * time.Second
+
+var intentSeq atomic.Uint64
+
+// markTick 记录已处理的行情，已处理过的（时间不晚于上次）返回 false
+// 在处理前标记，崩溃后同一行情不会被重复处理
+func (c *control) markTick(data models.MarketData) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if last, ok := c.lastTicks[data.Symbol]; ok && !data.Timestamp.After(last) {
+		return false
+	}
+	c.lastTicks[data.Symbol] = data.Timestamp
+	return true
+}
+
+// addIntent 在下单前记录下单意图
+func (c *control) addIntent(order trading.Order) string {
+	intent := state.OrderIntent{
+		ID:        fmt.Sprintf("%s-%d-%d", order.Symbol, time.Now().UnixNano(), intentSeq.Add(1)),
+		Order:     order,
+		CreatedAt: time.Now(),
+	}
+
+	c.mu.Lock()
+	c.pendingOrders[intent.ID] = intent
+	c.mu.Unlock()
+	return intent.ID
+}
+
+// removeIntent 下单结果确定后移除下单意图
+func (c *control) removeIntent(id string) {
+	c.mu.Lock()
+	delete(c.pendingOrders, id)
+	c.mu.Unlock()
+}
+
+func (c *control) snapshotState() *state.LoopState {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	loopState := &state.LoopState{
+		LastTicks:     make(map[string]time.Time, len(c.lastTicks)),
+		PendingOrders: make([]state.OrderIntent, 0, len(c.pendingOrders)),
+		Paused:        c.Paused(),
+		PausedSymbols: make([]string, 0, len(c.pausedSymbols)),
+		DisabledUntil: make(map[string]time.Time, len(c.disabled)),
+		UpdatedAt:     time.Now(),
+	}
+	for symbol, until := range c.disabled {
+		loopState.DisabledUntil[symbol] = until
+	}
+	for symbol := range c.pausedSymbols {
+		loopState.PausedSymbols = append(loopState.PausedSymbols, symbol)
+	}
+	for symbol, t := range c.lastTicks {
+		loopState.LastTicks[symbol] = t
+	}
+	for _, intent := range c.pendingOrders {
+		loopState.PendingOrders = append(loopState.PendingOrders, intent)
+	}
+	return loopState
+}
+
+// persistState 保存当前运行状态，失败只记录日志，不中断交易流程
+func (c *control) persistState(ctx context.Context) {
+	if c.store == nil {
+		return
+	}
+
+	// 串行保存，避免并发写入时旧快照覆盖新快照
+	c.saveMu.Lock()
+	defer c.saveMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), stateSaveTimeout)
+	defer cancel()
+
+	if err := c.store.SaveLoopState(ctx, c.snapshotState()); err != nil {
+		log.Error("Error saving loop state", "err", err)
+	}
+}
+
+// applyLoopState 合并已处理行情、暂停和停用状态，不包括未确认的下单意图
+func (c *control) applyLoopState(loopState *state.LoopState) {
+	c.mu.Lock()
+	for symbol, t := range loopState.LastTicks {
+		if last, ok := c.lastTicks[symbol]; !ok || t.After(last) {
+			c.lastTicks[symbol] = t
+		}
+	}
+	for _, symbol := range loopState.PausedSymbols {
+		c.pausedSymbols[symbol] = true
+	}
+	for symbol, until := range loopState.DisabledUntil {
+		c.disabled[symbol] = until
+	}
+	c.mu.Unlock()
+
+	if loopState.Paused {
+		c.paused.Store(true)
+		log.Warn("trading was paused before restart, staying paused")
+	}
+	if len(loopState.PausedSymbols) > 0 {
+		log.Warn("symbols were paused before restart, staying paused", "symbols", loopState.PausedSymbols)
+	}
+}
+
+// restoreState 从上次保存的状态恢复
+// 存在未确认的下单意图说明上次可能在下单过程中崩溃，此时暂停交易等待人工核对，避免重复下单
+func (c *control) restoreState(ctx context.Context) error {
+	if c.store == nil {
+		return nil
+	}
+
+	loopState, err := c.store.LoadLoopState(ctx)
+	if err != nil {
+		return err
+	}
+	if loopState == nil {
+		return nil
+	}
+
+	c.applyLoopState(loopState)
+
+	if len(loopState.PendingOrders) > 0 {
+		for _, intent := range loopState.PendingOrders {
+			log.Warn("unconfirmed order intent found after restart",
+				"intent_id", intent.ID,
+				"symbol", intent.Order.Symbol,
+				"side", intent.Order.Side,
+				"amount", intent.Order.Amount,
+				"created_at", intent.CreatedAt,
+			)
+		}
+		c.paused.Store(true)
+		log.Warn("trading paused until unconfirmed orders are verified", "count", len(loopState.PendingOrders))
+		c.audit.Record(ctx, audit.ActionPause, "", "unconfirmed order intents found after restart", map[string]any{"pending_orders": loopState.PendingOrders})
+	}
+
+	log.Info("restored loop state", "symbols", len(loopState.LastTicks), "paused", c.Paused(), "saved_at", loopState.UpdatedAt)
+
+	// 未确认的意图已提示并暂停交易，清除后保存
+	c.persistState(ctx)
+	return nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/stream"
+)
+
+// buildStreamer 创建事件流，未启用或回测时返回 nil
+func buildStreamer(config *configs.Config) (*stream.Streamer, error) {
+	sc := config.StreamConfig
+	if !sc.Enabled() || config.RunMode() == configs.ModeBacktest {
+		return nil, nil
+	}
+
+	var publisher stream.Publisher
+	switch sc.Driver {
+	case configs.StreamNATS:
+		nats, err := stream.NewNATSPublisher(sc.URL, "quantaflux")
+		if err != nil {
+			return nil, err
+		}
+		publisher = nats
+	case configs.StreamKafka:
+		publisher = stream.NewKafkaRESTPublisher(sc.URL)
+	default:
+		return nil, fmt.Errorf("unknown stream driver %q", sc.Driver)
+	}
+	return stream.NewStreamer(publisher, sc.Options(), moduleLog("stream")), nil
+}
+
+// runStream 在后台发布事件，返回的函数停止发布并等待队列中剩余的事件发送完毕或 ctx 取消
+func (s *QuantSystem) runStream() func(ctx context.Context) {
+	if s.stream == nil {
+		return func(context.Context) {}
+	}
+
+	streamCtx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.stream.Run(streamCtx)
+	}()
+	return func(ctx context.Context) {
+		cancel()
+		select {
+		case <-done:
+		case <-ctx.Done():
+		}
+	}
+}
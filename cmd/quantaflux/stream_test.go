@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/api"
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/journal"
+	"github.com/songzhibin97/quantaflux/internal/models"
+	"github.com/songzhibin97/quantaflux/internal/risk"
+	"github.com/songzhibin97/quantaflux/internal/stream"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingPublisher struct {
+	messages []stream.Message
+}
+
+func (r *recordingPublisher) Publish(_ context.Context, messages []stream.Message) error {
+	r.messages = append(r.messages, messages...)
+	return nil
+}
+
+func (r *recordingPublisher) Close() error {
+	return nil
+}
+
+func TestQuantSystem_Stream(t *testing.T) {
+	// 回测时不发布
+	config := &configs.Config{Mode: configs.ModeBacktest, StreamConfig: configs.StreamConfig{Driver: configs.StreamKafka, URL: "http://localhost:8082"}}
+	streamer, err := buildStreamer(config)
+	require.NoError(t, err)
+	assert.Nil(t, streamer)
+	config.Mode = configs.ModePaper
+	streamer, err = buildStreamer(config)
+	require.NoError(t, err)
+	assert.NotNil(t, streamer)
+
+	system, _ := newTestSystem(t, map[string]float64{"USDT": 1000})
+	publisher := &recordingPublisher{}
+	system.stream = stream.NewStreamer(publisher, stream.Options{Events: []string{api.EventTrade, api.EventRiskAlert}}, moduleLog("stream"))
+	system.stream.OnDelivery = func(result string, count int) { system.streamEvents.Add(float64(count), result) }
+	stop := system.runStream()
+
+	system.updateMarketData(models.MarketData{Symbol: "BTCUSDT", Price: 100})
+	system.recordAlert(risk.RiskAlert{Symbol: "ETHUSDT", AlertType: risk.AlertStopLoss, Timestamp: time.Now()})
+	system.recordTrade(context.Background(), &journal.Entry{Order: trading.Order{Symbol: "BTCUSDT", OrderID: "1"}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stop(ctx)
+
+	require.Len(t, publisher.messages, 2)
+	assert.Equal(t, "quantaflux.risk_alert", publisher.messages[0].Topic)
+	assert.Equal(t, "ETHUSDT", publisher.messages[0].Key)
+	assert.Equal(t, "quantaflux.trade", publisher.messages[1].Topic)
+	assert.Equal(t, "BTCUSDT", publisher.messages[1].Key)
+	assert.Equal(t, 2.0, system.streamEvents.Value(stream.ResultPublished))
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+
+	"github.com/songzhibin97/quantaflux/internal/supervisor"
+)
+
+// supervise 在 supervisor 下运行后台监控，监控在 ctx 取消前退出或 panic 时按退避重启
+func (s *QuantSystem) supervise(ctx context.Context, name string, loop func(ctx context.Context)) {
+	s.supervisor.Go(ctx, name, func(ctx context.Context, connected func()) error {
+		connected()
+		loop(ctx)
+		return nil
+	})
+}
+
+// connectionChanged 记录长连接组件的状态变化
+func (s *QuantSystem) connectionChanged(status supervisor.Status) {
+	up := 0.0
+	if status.State == supervisor.StateConnected {
+		up = 1
+	}
+	s.connectionUp.Set(up, status.Name)
+
+	switch status.State {
+	case supervisor.StateConnected:
+		log.Info("component connected", "component", status.Name, "restarts", status.Restarts)
+	case supervisor.StateBackoff:
+		s.reconnects.Inc(status.Name)
+		log.Warn("component disconnected, restarting with backoff", "component", status.Name,
+			"restarts", status.Restarts, "error", status.LastError)
+	}
+}
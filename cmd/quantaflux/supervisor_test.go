@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/supervisor"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuantSystem_Supervise(t *testing.T) {
+	system, _ := newTestSystem(t, map[string]float64{"USDT": 1000})
+	system.supervisor = supervisor.New(supervisor.Backoff{Initial: time.Millisecond})
+	system.supervisor.OnStateChange = system.connectionChanged
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// 第一次运行 panic，重启后持续运行
+	runs := 0
+	system.supervise(ctx, "test_monitor", func(ctx context.Context) {
+		runs++
+		if runs == 1 {
+			panic("boom")
+		}
+		<-ctx.Done()
+	})
+
+	require.Eventually(t, func() bool {
+		return system.connectionUp.Value("test_monitor") == 1
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, 1.0, system.reconnects.Value("test_monitor"))
+	assert.NoError(t, system.supervisor.Check(ctx))
+
+	cancel()
+	require.Eventually(t, func() bool {
+		return system.connectionUp.Value("test_monitor") == 0
+	}, time.Second, time.Millisecond)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/trading"
+)
+
+// runTimeSync 启动时同步一次各账户的交易所服务器时间，配置了 interval 时定期重新同步
+// 执行器在首次签名请求和时间戳被拒绝后也会自动同步，这里负责定期校准和偏差告警
+func (s *QuantSystem) runTimeSync(ctx context.Context) {
+	s.syncExchangeTime(ctx)
+
+	interval, err := time.ParseDuration(s.cfg().TimeSyncConfig.Interval)
+	if err != nil || interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.syncExchangeTime(ctx)
+		}
+	}
+}
+
+// syncExchangeTime 同步各账户的服务器时间，记录时钟偏差，偏差超过 max_drift 时告警
+func (s *QuantSystem) syncExchangeTime(ctx context.Context) {
+	maxDrift, _ := time.ParseDuration(s.cfg().TimeSyncConfig.MaxDrift)
+	for _, a := range s.accounts {
+		syncer, ok := trading.As[trading.TimeSyncer](a.executor)
+		if !ok {
+			continue
+		}
+
+		offset, err := syncer.SyncTime(ctx)
+		if err != nil {
+			log.Error("Error syncing exchange time", "account", a.name, "err", err)
+			continue
+		}
+
+		s.clockOffset.Set(offset.Seconds(), a.name)
+		if maxDrift > 0 && offset.Abs() > maxDrift {
+			s.clockDrift.Inc(a.name)
+			log.Warn("local clock drifts from exchange server time", "account", a.name, "offset", offset, "max_drift", maxDrift)
+			continue
+		}
+		log.Debug("synced exchange time", "account", a.name, "offset", offset)
+	}
+}
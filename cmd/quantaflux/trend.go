@@ -0,0 +1,112 @@
+package main
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/data"
+	"github.com/songzhibin97/quantaflux/internal/models"
+)
+
+// 每个趋势周期保留的价格数量，最新价格高于最早价格时趋势向上
+const trendSamples = 3
+
+// 趋势方向
+const (
+	trendUp   = 1
+	trendFlat = 0
+	trendDown = -1
+)
+
+// trendTracker 记录交易对在各趋势周期的最近价格
+type trendTracker struct {
+	mu     sync.Mutex
+	prices map[string][]float64 // 交易对@周期 -> 最近价格
+}
+
+func newTrendTracker() *trendTracker {
+	return &trendTracker{prices: make(map[string][]float64)}
+}
+
+// update 记录趋势周期的行情
+func (t *trendTracker) update(marketData models.MarketData) {
+	key := marketData.Symbol + "@" + marketData.Timeframe
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	prices := append(t.prices[key], marketData.Price)
+	if len(prices) > trendSamples {
+		prices = prices[len(prices)-trendSamples:]
+	}
+	t.prices[key] = prices
+}
+
+// direction 返回交易对在该周期的趋势方向，价格不足两个时视为无趋势
+func (t *trendTracker) direction(symbol, timeframe string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	prices := t.prices[symbol+"@"+timeframe]
+	if len(prices) < 2 {
+		return trendFlat
+	}
+	switch first, last := prices[0], prices[len(prices)-1]; {
+	case last > first:
+		return trendUp
+	case last < first:
+		return trendDown
+	default:
+		return trendFlat
+	}
+}
+
+// export 返回各趋势周期最近价格的副本，用于状态快照
+func (t *trendTracker) export() map[string][]float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	result := make(map[string][]float64, len(t.prices))
+	for key, prices := range t.prices {
+		result[key] = slices.Clone(prices)
+	}
+	return result
+}
+
+// restore 从快照恢复最近价格，已记录的周期以当前价格为准
+func (t *trendTracker) restore(prices map[string][]float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for key, p := range prices {
+		if _, ok := t.prices[key]; !ok {
+			t.prices[key] = slices.Clone(p)
+		}
+	}
+}
+
+// isTrendUpdate 判断行情是否来自趋势周期的订阅，而不是交易对的执行周期
+func (s *QuantSystem) isTrendUpdate(marketData models.MarketData) bool {
+	if marketData.Timeframe == "" {
+		return false
+	}
+	return marketData.Timeframe != data.FormatInterval(refreshInterval(s.cfg(), marketData.Symbol, s.symbolTags(marketData.Symbol)...))
+}
+
+// trendAgainst 返回与交易方向相反的趋势周期。现货只过滤买入：任一周期趋势向下时不开仓，卖出平仓不受限制
+func (s *QuantSystem) trendAgainst(symbol, side string) string {
+	if side != "buy" {
+		return ""
+	}
+	for _, timeframe := range s.symbolSettings(symbol).TrendTimeframes {
+		d, err := time.ParseDuration(timeframe)
+		if err != nil {
+			continue
+		}
+		if tf := data.FormatInterval(d); s.trends.direction(symbol, tf) == trendDown {
+			return tf
+		}
+	}
+	return ""
+}
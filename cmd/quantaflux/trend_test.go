@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/data"
+	"github.com/songzhibin97/quantaflux/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuantSystem_TrendFilter(t *testing.T) {
+	system, _ := newTestSystem(t, map[string]float64{"USDT": 1000})
+	config := *system.cfg()
+	config.RefreshInterval = "1m"
+	config.TradingConfig.TrendTimeframes = []string{"1h", "1m"}
+	system.config.Store(&config)
+
+	// 与刷新间隔相同的趋势周期不重复订阅
+	assert.Equal(t, []data.Subscription{
+		{Symbol: "BTCUSDT", Interval: time.Minute},
+		{Symbol: "BTCUSDT", Interval: time.Hour},
+	}, subscriptions(&config, nil))
+
+	assert.False(t, system.isTrendUpdate(models.MarketData{Symbol: "BTCUSDT", Timeframe: "1m"}))
+	assert.True(t, system.isTrendUpdate(models.MarketData{Symbol: "BTCUSDT", Timeframe: "1h"}))
+
+	// 价格不足两个时不过滤
+	system.trends.update(models.MarketData{Symbol: "BTCUSDT", Timeframe: "1h", Price: 100})
+	assert.Empty(t, system.trendAgainst("BTCUSDT", "buy"))
+
+	for _, price := range []float64{98, 95, 97} {
+		system.trends.update(models.MarketData{Symbol: "BTCUSDT", Timeframe: "1h", Price: price})
+	}
+	// 最近三个价格 98 -> 97，趋势向下时只过滤买入
+	assert.Equal(t, "1h", system.trendAgainst("BTCUSDT", "buy"))
+	assert.Empty(t, system.trendAgainst("BTCUSDT", "sell"))
+
+	system.trends.update(models.MarketData{Symbol: "BTCUSDT", Timeframe: "1h", Price: 99})
+	assert.Empty(t, system.trendAgainst("BTCUSDT", "buy"))
+}
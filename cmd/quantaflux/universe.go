@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/configs"
+)
+
+// syncUniverse 按交易所元数据同步品种池，新上架的交易对记录日志
+func (s *QuantSystem) syncUniverse(ctx context.Context, now time.Time) error {
+	if s.universe == nil || s.exchangeInfo == nil {
+		return nil
+	}
+	added, err := s.universe.Sync(ctx, s.exchangeInfo.Symbols(), now)
+	if err != nil {
+		return fmt.Errorf("failed to sync symbol universe: %w", err)
+	}
+	if len(added) > 0 {
+		log.Info("symbol universe updated", "added", len(added), "symbols", added)
+	}
+	return nil
+}
+
+// symbolTags 返回交易对在品种池中的标签
+func (s *QuantSystem) symbolTags(symbol string) []string {
+	if s.universe == nil {
+		return nil
+	}
+	return s.universe.Tags(symbol)
+}
+
+// symbolSettings 返回交易对按品种池标签和单独配置生效的配置
+func (s *QuantSystem) symbolSettings(symbol string) configs.SymbolSettings {
+	return s.cfg().ForSymbol(symbol, s.symbolTags(symbol)...)
+}
+
+// excludedTag 返回交易对带有的 discovery_config.exclude_tags 中的标签
+func (s *QuantSystem) excludedTag(symbol string, exclude []string) (string, bool) {
+	if s.universe == nil {
+		return "", false
+	}
+	info, ok := s.universe.Get(symbol)
+	if !ok {
+		return "", false
+	}
+	for _, tag := range exclude {
+		if info.HasTag(tag) {
+			return tag, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/exchangeinfo"
+	"github.com/songzhibin97/quantaflux/internal/universe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryUniverse struct {
+	symbols []universe.Symbol
+}
+
+func (m *memoryUniverse) SaveSymbols(_ context.Context, symbols []universe.Symbol) error {
+	m.symbols = append(m.symbols, symbols...)
+	return nil
+}
+
+func (m *memoryUniverse) ListSymbols(context.Context) ([]universe.Symbol, error) {
+	return m.symbols, nil
+}
+
+func TestQuantSystem_SymbolTags(t *testing.T) {
+	ctx := context.Background()
+	system, _ := newTestSystem(t, map[string]float64{"USDT": 10000})
+	assert.Empty(t, system.symbolTags("PEPEUSDT"))
+
+	system.universe = universe.NewService(&memoryUniverse{})
+	require.NoError(t, system.universe.Load(ctx))
+	system.exchangeInfo = exchangeinfo.NewService(&stubExchangeInfo{symbols: []exchangeinfo.Symbol{
+		{Symbol: "BTCUSDT", QuoteAsset: "USDT", Status: exchangeinfo.StatusTrading},
+		{Symbol: "PEPEUSDT", QuoteAsset: "USDT", Status: exchangeinfo.StatusTrading},
+	}})
+	require.NoError(t, system.refreshExchangeInfo(ctx))
+	_, ok := system.universe.Get("PEPEUSDT")
+	require.True(t, ok)
+
+	_, err := system.universe.Update(ctx, "PEPEUSDT", universe.Update{Tags: []string{"meme"}}, time.Now())
+	require.NoError(t, err)
+
+	config := *system.cfg()
+	minConfidence := 0.95
+	config.TagOverrides = map[string]configs.SymbolConfig{"meme": {MinConfidence: &minConfidence}}
+	system.config.Store(&config)
+	assert.Equal(t, 0.95, system.symbolSettings("PEPEUSDT").MinConfidence)
+	assert.Equal(t, config.AIConfig.MinConfidence, system.symbolSettings("BTCUSDT").MinConfidence)
+
+	tag, excluded := system.excludedTag("PEPEUSDT", []string{"L1", "MEME"})
+	assert.True(t, excluded)
+	assert.Equal(t, "MEME", tag)
+	_, excluded = system.excludedTag("BTCUSDT", []string{"meme"})
+	assert.False(t, excluded)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/data/collector/binance"
+	"github.com/songzhibin97/quantaflux/internal/data/storage"
+	"github.com/songzhibin97/quantaflux/internal/health"
+)
+
+// validationReport validate-config 的输出，配置有问题时不做连通性测试
+type validationReport struct {
+	Config string                   `json:"config"`
+	Mode   string                   `json:"mode,omitempty"`
+	Valid  bool                     `json:"valid"`
+	Issues []*configs.FieldError    `json:"issues,omitempty"`
+	Checks map[string]health.Result `json:"checks,omitempty"`
+}
+
+// cmdValidateConfig 校验配置文件，并以只读方式测试数据库、交易所、行情和 AI 服务的连通性，
+// 不建表、不下单，有问题时以非零状态退出
+func cmdValidateConfig(args []string) error {
+	fs, conf := newFlagSet("validate-config")
+	offline := fs.Bool("offline", false, "only validate the config, skip connectivity checks")
+	timeout := fs.Duration("timeout", 10*time.Second, "timeout of each connectivity check")
+	_ = fs.Parse(args)
+
+	quietLogs()
+	config, err := configs.Parse(*conf)
+	if err != nil {
+		return err
+	}
+
+	report := validationReport{Config: *conf, Mode: config.RunMode(), Valid: true}
+	if err := config.Validate(); err != nil {
+		report.Valid = false
+		report.Issues = configs.FieldErrors(err)
+	} else if !*offline {
+		readiness := connectivityChecker(config, *timeout).Readiness(context.Background())
+		report.Checks = readiness.Checks
+		report.Valid = readiness.Status == health.StatusOK
+	}
+
+	if err := printJSON(report); err != nil {
+		return err
+	}
+	if !report.Valid {
+		return errors.New("config validation failed")
+	}
+	return nil
+}
+
+// connectivityChecker 注册配置中各依赖服务的连通性检查，回测只检查数据库
+func connectivityChecker(config *configs.Config, timeout time.Duration) *health.Checker {
+	checker := health.NewChecker(timeout)
+	checker.AddReadiness("database", func(ctx context.Context) error {
+		return storage.PingDatabase(ctx, config.Database.ConnStr)
+	})
+	if config.RunMode() == configs.ModeBacktest {
+		return checker
+	}
+
+	if len(config.Symbols) > 0 {
+		symbol := config.Symbols[0]
+		checker.AddReadiness("market_data", func(ctx context.Context) error {
+			_, err := binance.NewBinanceDataSource().CollectMarketData(ctx, symbol)
+			return err
+		})
+	}
+
+	// 模拟交易不访问交易所账户，不测试密钥
+	if config.RunMode() != configs.ModePaper {
+		accounts := config.AccountConfigs()
+		for _, ac := range accounts {
+			if !ac.ExchangeConfig.HasCredentials() {
+				continue
+			}
+			name := "exchange"
+			if len(accounts) > 1 {
+				name = "exchange:" + ac.Name
+			}
+			checker.AddReadiness(name, health.PingCheck(newBinanceExecutor(config, ac.ExchangeConfig, nil)))
+		}
+	}
+
+	analyzer := buildAnalyzer(config.AIConfig.APIKey, config.AIConfig.ModelType, config.AIConfig.Generation())
+	if p, ok := analyzer.(health.Pinger); ok {
+		checker.AddReadiness("ai", health.PingCheck(p))
+	}
+	if p, ok := buildChallenger(config).(health.Pinger); ok {
+		checker.AddReadiness("ai:challenger", health.PingCheck(p))
+	}
+
+	return checker
+}
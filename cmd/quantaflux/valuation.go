@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/songzhibin97/quantaflux/internal/trading"
+)
+
+// value 将资产数量按最新价格换算为 valuation_config.currency 计价的金额
+func (s *QuantSystem) value(amount float64, asset string) (float64, error) {
+	return s.rates.Convert(amount, asset, s.cfg().ValuationConfig.Asset())
+}
+
+// valueOrder 返回按估值资产计价的订单副本，计价资产与估值资产相同时返回原订单
+func (s *QuantSystem) valueOrder(order *trading.Order) (*trading.Order, error) {
+	_, quote, ok := trading.SplitSymbol(order.Symbol)
+	if !ok || quote == s.cfg().ValuationConfig.Asset() {
+		return order, nil
+	}
+
+	rate, err := s.value(1, quote)
+	if err != nil {
+		return nil, fmt.Errorf("failed to value order of %s: %w", order.Symbol, err)
+	}
+	valued := *order
+	valued.Price *= rate
+	valued.SubmittedPrice *= rate
+	valued.QuoteAmount *= rate
+	return &valued, nil
+}
+
+// refreshRates 采集 valuation_config.rate_symbols 中不参与交易的交易对的最新价格，交易中的交易对由行情更新
+func (s *QuantSystem) refreshRates(ctx context.Context) error {
+	if s.dataCollector == nil {
+		return nil
+	}
+
+	config := s.cfg()
+	var errs []error
+	for _, symbol := range config.ValuationConfig.RateSymbols {
+		if slices.Contains(config.Symbols, symbol) {
+			continue
+		}
+		data, err := s.dataCollector.CollectMarketData(ctx, symbol)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", symbol, err))
+			continue
+		}
+		s.rates.Update(symbol, data.Price)
+	}
+	return errors.Join(errs...)
+}
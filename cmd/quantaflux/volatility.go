@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/configs"
+)
+
+// loadVolatility 用历史行情预热波动率，回测时历史行情在回放中逐条加入，不预热以免用到未来数据
+func (s *QuantSystem) loadVolatility(ctx context.Context) {
+	if s.volatility == nil || s.cfg().RunMode() == configs.ModeBacktest {
+		return
+	}
+	if err := s.volatility.Load(ctx, s.cfg().Symbols, time.Now()); err != nil {
+		log.Warn("failed to load volatility history", "err", err)
+	}
+}
+
+// riskSizedAmount 按每笔风险金额和 ATR 止损距离计算下单数量，单位与 amount_unit 一致；
+// 未配置 risk_per_trade 或交易对的 ATR 尚未就绪时返回 false
+func (s *QuantSystem) riskSizedAmount(symbol string, currentPrice float64) (float64, bool) {
+	vc := s.cfg().VolatilityConfig
+	if s.volatility == nil || vc.RiskPerTrade <= 0 || vc.StopATR <= 0 {
+		return 0, false
+	}
+	estimate, ok := s.volatility.Estimate(symbol)
+	if !ok || estimate.ATR <= 0 {
+		return 0, false
+	}
+
+	amount := vc.RiskPerTrade / (vc.StopATR * estimate.ATR)
+	if s.cfg().OrderAmountUnit() == configs.AmountUnitQuote {
+		amount *= currentPrice
+	}
+	return amount, true
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/models"
+	"github.com/songzhibin97/quantaflux/internal/risk"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+	"github.com/songzhibin97/quantaflux/internal/volatility"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuantSystem_VolatilitySizingAndStop(t *testing.T) {
+	ctx := context.Background()
+	system, store := newTestSystem(t, map[string]float64{"USDT": 10000})
+	a := system.primaryAccount()
+
+	config := *system.cfg()
+	config.TradingConfig.MaxOrderAmount = 5
+	config.TradingConfig.MinOrderAmount = 0.1
+	config.VolatilityConfig = configs.VolatilityConfig{Interval: "1m", Window: 5, StopATR: 2, RiskPerTrade: 20}
+	system.config.Store(&config)
+	system.volatility = volatility.NewService(nil, config.VolatilityConfig.Options())
+
+	// ATR 尚未就绪时按最大交易量下单
+	assert.Equal(t, 5.0, system.calculateOrderAmount("BTCUSDT", 0, 100))
+
+	// 每分钟价格交替涨跌 2，ATR 为 2
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range 8 {
+		price := 100.0
+		if i%2 == 1 {
+			price = 102
+		}
+		system.volatility.Observe(models.MarketData{Symbol: "BTCUSDT", Price: price, Timestamp: base.Add(time.Duration(i) * time.Minute)})
+	}
+	estimate, ok := system.volatility.Estimate("BTCUSDT")
+	require.True(t, ok)
+	assert.InDelta(t, 2, estimate.ATR, 1e-9)
+
+	// 止损距离 2*ATR=4，亏损 20 对应 5 个，不超过最大交易量；ATR 变大后数量减少
+	assert.InDelta(t, 5, system.calculateOrderAmount("BTCUSDT", 0, 100), 1e-9)
+	config.VolatilityConfig.StopATR = 4
+	assert.InDelta(t, 2.5, system.calculateOrderAmount("BTCUSDT", 0, 100), 1e-9)
+	config.TradingConfig.AmountUnit = configs.AmountUnitQuote
+	config.TradingConfig.MaxOrderAmount = 1000
+	assert.InDelta(t, 250, system.calculateOrderAmount("BTCUSDT", 0, 100), 1e-9)
+
+	// ATR 止损平掉持仓，不暂停交易对
+	a.executor.(trading.MarketPriceUpdater).UpdateMarketPrice("BTCUSDT", 100)
+	order := &trading.Order{Account: a.name, Symbol: "BTCUSDT", Side: "buy", Amount: 1, OrderType: "market"}
+	require.NoError(t, a.executor.PlaceOrder(ctx, order))
+	alert := risk.RiskAlert{Symbol: "BTCUSDT", AlertType: risk.AlertStopLoss, Severity: risk.SeverityMedium}
+	require.NoError(t, system.handleRiskAlert(ctx, a, alert))
+	assert.False(t, system.symbolPaused("BTCUSDT"))
+	require.NotEmpty(t, store.entries)
+	last := store.entries[len(store.entries)-1]
+	assert.Equal(t, "sell", last.Order.Side)
+	assert.InDelta(t, 1, last.Order.Amount, 1e-9)
+}
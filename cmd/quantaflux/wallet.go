@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/notify"
+	"github.com/songzhibin97/quantaflux/internal/risk"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+)
+
+// 交易所维护和资产充提暂停的预警类型
+const (
+	alertExchangeMaintenance = "Exchange Maintenance"
+	alertWalletSuspended     = "Wallet Suspended"
+)
+
+// walletState 按账户记录交易所是否处于系统维护，以及充值或提现暂停的资产
+type walletState struct {
+	mu          sync.Mutex
+	maintenance map[string]bool
+	suspended   map[string]map[string]trading.AssetStatus
+}
+
+func newWalletState() *walletState {
+	return &walletState{
+		maintenance: make(map[string]bool),
+		suspended:   make(map[string]map[string]trading.AssetStatus),
+	}
+}
+
+// symbolAssets 返回交易对的基础资产和计价资产，优先使用交易所元数据
+func (s *QuantSystem) symbolAssets(symbol string) (base, quote string, ok bool) {
+	if s.exchangeInfo != nil {
+		if info, found := s.exchangeInfo.Symbol(symbol); found && info.BaseAsset != "" && info.QuoteAsset != "" {
+			return info.BaseAsset, info.QuoteAsset, true
+		}
+	}
+	return trading.SplitSymbol(symbol)
+}
+
+// assetBlocked 资产提现暂停时返回 true，配置了 block_deposits 时充值暂停同样返回 true
+func (s *QuantSystem) assetBlocked(a *account, asset string) bool {
+	status, ok := s.wallets.suspended[a.name][asset]
+	if !ok {
+		return false
+	}
+	return !status.WithdrawEnabled || (!status.DepositEnabled && s.cfg().WalletStatusConfig.BlockDeposits)
+}
+
+// walletBlocked 交易所维护时阻止账户的全部订单；买入得到基础资产、卖出得到计价资产，
+// 得到的资产充提暂停时阻止下单，side 为空时任一资产暂停即阻止。返回阻止的原因
+func (s *QuantSystem) walletBlocked(a *account, symbol, side string) (string, bool) {
+	s.wallets.mu.Lock()
+	defer s.wallets.mu.Unlock()
+
+	if s.wallets.maintenance[a.name] {
+		return "exchange maintenance", true
+	}
+	base, quote, ok := s.symbolAssets(symbol)
+	if !ok {
+		return "", false
+	}
+	var assets []string
+	switch side {
+	case "buy":
+		assets = []string{base}
+	case "sell":
+		assets = []string{quote}
+	default:
+		assets = []string{base, quote}
+	}
+	for _, asset := range assets {
+		if s.assetBlocked(a, asset) {
+			return asset + " wallet suspended", true
+		}
+	}
+	return "", false
+}
+
+// accountSymbols 返回账户交易的交易对
+func (s *QuantSystem) accountSymbols(a *account) []string {
+	var symbols []string
+	for _, symbol := range s.cfg().Symbols {
+		if a.trades(symbol) {
+			symbols = append(symbols, symbol)
+		}
+	}
+	return symbols
+}
+
+// checkWallet 查询账户所在交易所的系统状态和资产充提状态，返回新进入维护或新暂停的预警，
+// 只关注账户交易的交易对涉及的资产；恢复时只记录日志
+func (s *QuantSystem) checkWallet(ctx context.Context, a *account, provider trading.WalletStatusProvider, now time.Time) ([]risk.RiskAlert, error) {
+	system, err := provider.SystemStatus(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get system status: %w", err)
+	}
+	statuses, err := provider.AssetStatuses(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get asset statuses: %w", err)
+	}
+
+	symbols := s.accountSymbols(a)
+	watched := make(map[string][]string)
+	for _, symbol := range symbols {
+		if base, quote, ok := s.symbolAssets(symbol); ok {
+			watched[base] = append(watched[base], symbol)
+			watched[quote] = append(watched[quote], symbol)
+		}
+	}
+	suspended := make(map[string]trading.AssetStatus)
+	for _, status := range statuses {
+		if _, ok := watched[status.Asset]; ok && (!status.DepositEnabled || !status.WithdrawEnabled) {
+			suspended[status.Asset] = status
+		}
+	}
+
+	s.wallets.mu.Lock()
+	defer s.wallets.mu.Unlock()
+
+	var alerts []risk.RiskAlert
+	if system.Maintenance && !s.wallets.maintenance[a.name] {
+		for _, symbol := range symbols {
+			alerts = append(alerts, risk.RiskAlert{
+				Symbol:      symbol,
+				AlertType:   alertExchangeMaintenance,
+				Severity:    risk.SeverityLow,
+				Description: fmt.Sprintf("exchange of account %s is under maintenance (%s), new orders are blocked", a.name, system.Message),
+				Timestamp:   now,
+			})
+		}
+	} else if !system.Maintenance && s.wallets.maintenance[a.name] {
+		log.Info("exchange maintenance ended, orders resumed", "account", a.name)
+	}
+	s.wallets.maintenance[a.name] = system.Maintenance
+
+	previous := s.wallets.suspended[a.name]
+	assets := make([]string, 0, len(suspended))
+	for asset := range suspended {
+		assets = append(assets, asset)
+	}
+	sort.Strings(assets)
+	for _, asset := range assets {
+		status := suspended[asset]
+		if before, ok := previous[asset]; ok && before == status {
+			continue
+		}
+		for _, symbol := range watched[asset] {
+			alerts = append(alerts, risk.RiskAlert{
+				Symbol:      symbol,
+				AlertType:   alertWalletSuspended,
+				Severity:    risk.SeverityLow,
+				Description: fmt.Sprintf("%s deposit enabled %t, withdrawal enabled %t on account %s, orders acquiring %s are blocked", asset, status.DepositEnabled, status.WithdrawEnabled, a.name, asset),
+				Timestamp:   now,
+			})
+		}
+	}
+	for asset := range previous {
+		if _, ok := suspended[asset]; !ok {
+			log.Info("wallet resumed, orders resumed", "account", a.name, "asset", asset)
+		}
+	}
+	s.wallets.suspended[a.name] = suspended
+	return alerts, nil
+}
+
+// monitorWallets 定期检查支持查询充提状态的账户，将预警分发给对应账户，同时发送通知；
+// 回测或未配置 wallet_status_config.interval 时不监控
+func (s *QuantSystem) monitorWallets(ctx context.Context, out chan<- accountAlert) {
+	config := s.cfg()
+	interval := config.WalletStatusConfig.CheckInterval()
+	if config.RunMode() == configs.ModeBacktest || interval <= 0 {
+		return
+	}
+
+	providers := make(map[*account]trading.WalletStatusProvider)
+	for _, a := range s.accounts {
+		if provider, ok := trading.As[trading.WalletStatusProvider](a.executor); ok {
+			providers[a] = provider
+		}
+	}
+	if len(providers) == 0 {
+		return
+	}
+
+	s.supervise(ctx, "wallet_monitor", func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		now := time.Now()
+		for {
+			for a, provider := range providers {
+				alerts, err := s.checkWallet(ctx, a, provider, now)
+				if err != nil {
+					log.Error("wallet status check failed", "account", a.name, "err", err)
+					continue
+				}
+				for _, alert := range alerts {
+					log.Warn("wallet status changed, orders blocked", "account", a.name, "symbol", alert.Symbol, "type", alert.AlertType)
+					s.notify(ctx, notify.Message{
+						Title: fmt.Sprintf("%s %s", alert.Symbol, alert.AlertType),
+						Text:  alert.Description,
+						Level: notify.LevelWarning,
+					})
+					select {
+					case out <- accountAlert{account: a, alert: alert}:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case now = <-ticker.C:
+			}
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/trading"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type walletStatusStub struct {
+	system trading.SystemStatus
+	assets []trading.AssetStatus
+}
+
+func (w *walletStatusStub) SystemStatus(ctx context.Context) (trading.SystemStatus, error) {
+	return w.system, nil
+}
+
+func (w *walletStatusStub) AssetStatuses(ctx context.Context) ([]trading.AssetStatus, error) {
+	return w.assets, nil
+}
+
+func TestQuantSystem_CheckWallet(t *testing.T) {
+	ctx := context.Background()
+	system, _ := newTestSystem(t, map[string]float64{"USDT": 1000})
+	a := system.primaryAccount()
+	now := time.Now()
+	stub := &walletStatusStub{assets: []trading.AssetStatus{
+		{Asset: "BTC", DepositEnabled: true, WithdrawEnabled: false},
+		{Asset: "ETH", DepositEnabled: false, WithdrawEnabled: false}, // 账户不交易的资产不预警
+		{Asset: "USDT", DepositEnabled: true, WithdrawEnabled: true},
+	}}
+
+	// BTC 提现暂停：阻止买入 BTC，卖出得到 USDT 不受影响
+	alerts, err := system.checkWallet(ctx, a, stub, now)
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "BTCUSDT", alerts[0].Symbol)
+	assert.Equal(t, alertWalletSuspended, alerts[0].AlertType)
+	_, blocked := system.walletBlocked(a, "BTCUSDT", "buy")
+	assert.True(t, blocked)
+	_, blocked = system.walletBlocked(a, "BTCUSDT", "sell")
+	assert.False(t, blocked)
+
+	// 状态不变时不重复预警
+	alerts, err = system.checkWallet(ctx, a, stub, now)
+	require.NoError(t, err)
+	assert.Empty(t, alerts)
+
+	// 只暂停充值时默认不阻止，配置 block_deposits 后阻止
+	stub.assets[0] = trading.AssetStatus{Asset: "BTC", DepositEnabled: false, WithdrawEnabled: true}
+	alerts, err = system.checkWallet(ctx, a, stub, now)
+	require.NoError(t, err)
+	assert.Len(t, alerts, 1)
+	_, blocked = system.walletBlocked(a, "BTCUSDT", "buy")
+	assert.False(t, blocked)
+	config := *system.cfg()
+	config.WalletStatusConfig.BlockDeposits = true
+	system.config.Store(&config)
+	_, blocked = system.walletBlocked(a, "BTCUSDT", "buy")
+	assert.True(t, blocked)
+
+	// 系统维护阻止全部订单，结束后恢复
+	stub.system.Maintenance = true
+	stub.assets[0].DepositEnabled = true
+	alerts, err = system.checkWallet(ctx, a, stub, now)
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, alertExchangeMaintenance, alerts[0].AlertType)
+	reason, blocked := system.walletBlocked(a, "BTCUSDT", "sell")
+	assert.True(t, blocked)
+	assert.Equal(t, "exchange maintenance", reason)
+
+	stub.system.Maintenance = false
+	alerts, err = system.checkWallet(ctx, a, stub, now)
+	require.NoError(t, err)
+	assert.Empty(t, alerts)
+	_, blocked = system.walletBlocked(a, "BTCUSDT", "")
+	assert.False(t, blocked)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/configs"
+	"github.com/songzhibin97/quantaflux/internal/risk"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+	"github.com/songzhibin97/quantaflux/internal/whale"
+)
+
+// 大额转账预警类型
+const alertWhaleInflow = "Whale Exchange Inflow"
+
+// buildWhaleTracker 根据配置创建大额转账追踪，未配置 API 密钥或处于回测模式时返回 nil
+func buildWhaleTracker(config *configs.Config) *whale.Tracker {
+	whaleConfig := config.WhaleConfig
+	if !whaleConfig.Enabled() || config.RunMode() == configs.ModeBacktest {
+		return nil
+	}
+	return whale.NewTracker(whale.NewWhaleAlertSource(whaleConfig.APIKey), whaleConfig.StatsWindow(), whaleConfig.MinValueUSD)
+}
+
+// whaleMetrics 返回交易对基础资产的大额转账指标，未启用追踪时返回 nil
+func (s *QuantSystem) whaleMetrics(symbol string) map[string]float64 {
+	if s.whales == nil {
+		return nil
+	}
+	base, _, ok := trading.SplitSymbol(symbol)
+	if !ok {
+		return nil
+	}
+	return s.whales.Metrics(base)
+}
+
+// monitorWhales 定期拉取大额转账，单笔流入交易所达到 alert_inflow_usd 时向交易该资产的账户发出减仓预警
+func (s *QuantSystem) monitorWhales(ctx context.Context, out chan<- accountAlert) {
+	if s.whales == nil {
+		return
+	}
+
+	s.supervise(ctx, "whale_monitor", func(ctx context.Context) {
+		ticker := time.NewTicker(s.cfg().WhaleConfig.PollInterval())
+		defer ticker.Stop()
+
+		for {
+			transfers, err := s.whales.Poll(ctx, time.Now())
+			if err != nil {
+				log.Error("Error polling whale transfers", "err", err)
+			}
+			for _, alert := range s.whaleAlerts(transfers) {
+				for _, a := range s.accounts {
+					if !a.trades(alert.Symbol) {
+						continue
+					}
+					select {
+					case out <- accountAlert{account: a, alert: alert}:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+}
+
+// whaleAlerts 将大额流入交易所的转账转换为当前交易的交易对的 MEDIUM 级别预警
+func (s *QuantSystem) whaleAlerts(transfers []whale.Transfer) []risk.RiskAlert {
+	threshold := s.cfg().WhaleConfig.AlertInflowUSD
+	if threshold <= 0 {
+		return nil
+	}
+
+	var alerts []risk.RiskAlert
+	for _, transfer := range transfers {
+		if transfer.Flow() != whale.FlowInflow || transfer.AmountUSD < threshold {
+			continue
+		}
+		for _, symbol := range s.cfg().Symbols {
+			base, _, ok := trading.SplitSymbol(symbol)
+			if !ok || base != transfer.Asset {
+				continue
+			}
+			alerts = append(alerts, risk.RiskAlert{
+				Symbol:    symbol,
+				AlertType: alertWhaleInflow,
+				Severity:  risk.SeverityMedium,
+				Description: fmt.Sprintf("%.0f USD of %s moved to %s (%s)",
+					transfer.AmountUSD, transfer.Asset, transfer.ToExchange, transfer.Hash),
+				Timestamp: transfer.Timestamp,
+			})
+		}
+	}
+	return alerts
+}
@@ -0,0 +1,44 @@
+package abtest
+
+import (
+	"context"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/models"
+)
+
+// 参与对比的分析器
+const (
+	VariantChampion   = "champion"   // 实际执行交易的分析器
+	VariantChallenger = "challenger" // 只记录假设决策的分析器
+)
+
+// Decision 分析器对同一条行情的预测和由此产生的交易方向
+type Decision struct {
+	ID             int64     `json:"id"`
+	Variant        string    `json:"variant"`
+	Model          string    `json:"model"`
+	Mode           string    `json:"mode"` // 运行模式
+	Symbol         string    `json:"symbol"`
+	Price          float64   `json:"price"`           // 决策时的行情价格
+	PredictedPrice float64   `json:"predicted_price"` // 预测价格
+	Confidence     float64   `json:"confidence"`
+	TimeFrame      string    `json:"time_frame"` // 预测时间范围
+	Side           string    `json:"side"`       // buy/sell，置信度不足或预测价格在容忍范围内时为空
+	TickTime       time.Time `json:"tick_time"`  // 行情时间，用于配对两个分析器的决策
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// Store 保存和查询分析器决策
+type Store interface {
+	// SaveDecision persists a decision and sets its ID
+	SaveDecision(ctx context.Context, decision *Decision) error
+
+	// ListDecisions returns decisions of the run mode whose tick time is in [start, end], oldest first
+	ListDecisions(ctx context.Context, mode string, start, end time.Time) ([]Decision, error)
+}
+
+// PriceHistory 查询预测到期后的实际价格
+type PriceHistory interface {
+	GetHistoricalData(ctx context.Context, symbol string, start, end time.Time) ([]models.MarketData, error)
+}
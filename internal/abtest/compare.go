@@ -0,0 +1,157 @@
+package abtest
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// 未指定预测时间范围时的评估时长
+const defaultHorizon = time.Hour
+
+// VariantStats 单个分析器的预测准确度和假设收益
+type VariantStats struct {
+	Model           string  `json:"model"`
+	Decisions       int     `json:"decisions"`          // 决策数量
+	Signals         int     `json:"signals"`            // 产生交易方向的决策数量
+	Evaluated       int     `json:"evaluated"`          // 已到预测时间、可评估的决策数量
+	DirectionHits   int     `json:"direction_hits"`     // 涨跌方向预测正确的数量
+	HitRate         float64 `json:"hit_rate"`           // 方向命中率
+	MeanAbsPctError float64 `json:"mean_abs_pct_error"` // 预测价格的平均绝对百分比误差
+	SignalReturn    float64 `json:"signal_return"`      // 按交易方向持有到预测时间的收益率之和，不计交易成本
+	WinRate         float64 `json:"win_rate"`           // 收益为正的交易信号占比
+}
+
+// Report 冠军和挑战者分析器的对比结果
+type Report struct {
+	Start      time.Time    `json:"start"`
+	End        time.Time    `json:"end"`
+	Champion   VariantStats `json:"champion"`
+	Challenger VariantStats `json:"challenger"`
+	Paired     int          `json:"paired"`    // 两个分析器都做出决策的行情数量
+	Agreement  float64      `json:"agreement"` // 配对行情中交易方向相同的比例
+}
+
+type accumulator struct {
+	stats   VariantStats
+	errSum  float64
+	winning int
+	signals int // 已评估的交易信号数量
+}
+
+// Compare 将决策与预测时间到期后的实际价格比较，未到期的决策只计入数量
+func Compare(ctx context.Context, decisions []Decision, history PriceHistory, start, end time.Time) (*Report, error) {
+	variants := map[string]*accumulator{
+		VariantChampion:   {},
+		VariantChallenger: {},
+	}
+
+	type tick struct {
+		symbol string
+		at     time.Time
+	}
+	sides := make(map[tick]map[string]string)
+
+	for _, d := range decisions {
+		acc, ok := variants[d.Variant]
+		if !ok {
+			continue
+		}
+		acc.stats.Model = d.Model
+		acc.stats.Decisions++
+		if d.Side != "" {
+			acc.stats.Signals++
+		}
+
+		key := tick{symbol: d.Symbol, at: d.TickTime}
+		if sides[key] == nil {
+			sides[key] = make(map[string]string)
+		}
+		sides[key][d.Variant] = d.Side
+
+		actual, ok, err := outcome(ctx, history, d, end)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		acc.stats.Evaluated++
+		if (d.PredictedPrice-d.Price)*(actual-d.Price) > 0 {
+			acc.stats.DirectionHits++
+		}
+		acc.errSum += math.Abs(d.PredictedPrice-actual) / actual
+
+		if d.Side == "" {
+			continue
+		}
+		ret := (actual - d.Price) / d.Price
+		if d.Side == "sell" {
+			ret = -ret
+		}
+		acc.signals++
+		acc.stats.SignalReturn += ret
+		if ret > 0 {
+			acc.winning++
+		}
+	}
+
+	report := &Report{Start: start, End: end}
+	for _, variantSides := range sides {
+		champion, ok1 := variantSides[VariantChampion]
+		challenger, ok2 := variantSides[VariantChallenger]
+		if !ok1 || !ok2 {
+			continue
+		}
+		report.Paired++
+		if champion == challenger {
+			report.Agreement++
+		}
+	}
+	if report.Paired > 0 {
+		report.Agreement /= float64(report.Paired)
+	}
+
+	report.Champion = variants[VariantChampion].result()
+	report.Challenger = variants[VariantChallenger].result()
+	return report, nil
+}
+
+func (a *accumulator) result() VariantStats {
+	stats := a.stats
+	if stats.Evaluated > 0 {
+		stats.HitRate = float64(stats.DirectionHits) / float64(stats.Evaluated)
+		stats.MeanAbsPctError = a.errSum / float64(stats.Evaluated)
+	}
+	if a.signals > 0 {
+		stats.WinRate = float64(a.winning) / float64(a.signals)
+	}
+	return stats
+}
+
+// outcome 返回预测时间到期后的第一条实际价格，未到期或没有行情时 ok 为 false
+func outcome(ctx context.Context, history PriceHistory, d Decision, end time.Time) (float64, bool, error) {
+	if d.Price <= 0 {
+		return 0, false, nil
+	}
+
+	horizon, err := time.ParseDuration(d.TimeFrame)
+	if err != nil || horizon <= 0 {
+		horizon = defaultHorizon
+	}
+	target := d.TickTime.Add(horizon)
+	if target.After(end) {
+		return 0, false, nil
+	}
+
+	data, err := history.GetHistoricalData(ctx, d.Symbol, target, target.Add(horizon))
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to load outcome price of %s: %w", d.Symbol, err)
+	}
+	if len(data) == 0 || data[0].Price <= 0 {
+		return 0, false, nil
+	}
+	return data[0].Price, true, nil
+}
@@ -0,0 +1,69 @@
+package abtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeHistory struct {
+	prices []models.MarketData
+}
+
+func (f *fakeHistory) GetHistoricalData(ctx context.Context, symbol string, start, end time.Time) ([]models.MarketData, error) {
+	var result []models.MarketData
+	for _, d := range f.prices {
+		if d.Symbol == symbol && !d.Timestamp.Before(start) && !d.Timestamp.After(end) {
+			result = append(result, d)
+		}
+	}
+	return result, nil
+}
+
+func TestCompare(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	history := &fakeHistory{prices: []models.MarketData{
+		{Symbol: "BTCUSDT", Price: 110, Timestamp: t0.Add(time.Hour)},
+		{Symbol: "BTCUSDT", Price: 95, Timestamp: t0.Add(2 * time.Hour)},
+	}}
+
+	decision := func(variant string, at time.Time, price, predicted float64, side string) Decision {
+		return Decision{Variant: variant, Model: variant + "-model", Symbol: "BTCUSDT", Price: price, PredictedPrice: predicted, TimeFrame: "1h", Side: side, TickTime: at}
+	}
+	decisions := []Decision{
+		// 第一条行情：冠军看涨买入，挑战者看跌卖出，实际上涨到 110
+		decision(VariantChampion, t0, 100, 108, "buy"),
+		decision(VariantChallenger, t0, 100, 97, "sell"),
+		// 第二条行情：两者都看跌，实际下跌到 95
+		decision(VariantChampion, t0.Add(time.Hour), 110, 100, "sell"),
+		decision(VariantChallenger, t0.Add(time.Hour), 110, 96, "sell"),
+		// 未到期的决策只计数
+		decision(VariantChallenger, t0.Add(2*time.Hour), 95, 99, "buy"),
+	}
+
+	report, err := Compare(context.Background(), decisions, history, t0, t0.Add(2*time.Hour))
+	require.NoError(t, err)
+
+	assert.Equal(t, "champion-model", report.Champion.Model)
+	assert.Equal(t, 2, report.Champion.Decisions)
+	assert.Equal(t, 2, report.Champion.Evaluated)
+	assert.Equal(t, 2, report.Champion.DirectionHits)
+	assert.InDelta(t, 1, report.Champion.HitRate, 1e-9)
+	assert.InDelta(t, 0.1+15.0/110, report.Champion.SignalReturn, 1e-9)
+	assert.InDelta(t, 1, report.Champion.WinRate, 1e-9)
+
+	assert.Equal(t, 3, report.Challenger.Decisions)
+	assert.Equal(t, 3, report.Challenger.Signals)
+	assert.Equal(t, 2, report.Challenger.Evaluated)
+	assert.Equal(t, 1, report.Challenger.DirectionHits)
+	assert.InDelta(t, -0.1+15.0/110, report.Challenger.SignalReturn, 1e-9)
+	assert.InDelta(t, 0.5, report.Challenger.WinRate, 1e-9)
+
+	assert.Equal(t, 2, report.Paired)
+	assert.InDelta(t, 0.5, report.Agreement, 1e-9)
+}
@@ -1,6 +1,7 @@
 package deepseek
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -16,14 +17,22 @@ import (
 const (
 	defaultAPIEndpoint = "https://api.deepseek.com/v1"
 	defaultModel       = "deepseek-chat"
+	// ReasonerModel 推理模型，先输出推理过程再回答，耗时更长，不支持 temperature 和 top_p
+	ReasonerModel = "deepseek-reasoner"
 )
 
 // DeepSeekAnalyzer implements the Analyzer interface using DeepSeek
 type DeepSeekAnalyzer struct {
-	apiKey   string
-	endpoint string
-	model    string
-	client   *http.Client
+	apiKey     string
+	endpoint   string
+	model      string
+	client     *http.Client
+	generation ai.GenerationOptions
+}
+
+// SetGenerationOptions sets the sampling parameters of each analysis method
+func (a *DeepSeekAnalyzer) SetGenerationOptions(options ai.GenerationOptions) {
+	a.generation = options
 }
 
 // NewDeepSeekAnalyzer creates a new DeepSeek analyzer instance
@@ -43,7 +52,10 @@ func NewDeepSeekAnalyzer(apiKey string, model string) *DeepSeekAnalyzer {
 type chatRequest struct {
 	Model       string        `json:"model"`
 	Messages    []chatMessage `json:"messages"`
-	Temperature float64       `json:"temperature"`
+	Temperature *float64      `json:"temperature,omitempty"`
+	TopP        *float64      `json:"top_p,omitempty"`
+	MaxTokens   int           `json:"max_tokens,omitempty"`
+	Stream      bool          `json:"stream,omitempty"`
 }
 
 type chatMessage struct {
@@ -54,7 +66,8 @@ type chatMessage struct {
 type chatResponse struct {
 	Choices []struct {
 		Message struct {
-			Content string `json:"content"`
+			Content          string `json:"content"`
+			ReasoningContent string `json:"reasoning_content"`
 		} `json:"message"`
 	} `json:"choices"`
 	Error *struct {
@@ -62,6 +75,51 @@ type chatResponse struct {
 	} `json:"error,omitempty"`
 }
 
+// chatChunk 流式响应的一个增量
+type chatChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content          string `json:"content"`
+			ReasoningContent string `json:"reasoning_content"`
+		} `json:"delta"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// completion 模型的回答和推理模型的推理过程
+type completion struct {
+	content   string
+	reasoning string
+}
+
+// isReasoner 是否为推理模型
+func isReasoner(model string) bool {
+	return strings.Contains(model, "reasoner")
+}
+
+// Ping checks that the DeepSeek API is reachable with the configured key
+func (a *DeepSeekAnalyzer) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/models", a.endpoint), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", a.apiKey))
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ai.ErrProviderUnavailable, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: status=%d", ai.ErrProviderUnavailable, resp.StatusCode)
+	}
+	return nil
+}
+
 // AnalyzeProject implements the Analyzer interface
 func (a *DeepSeekAnalyzer) AnalyzeProject(ctx context.Context, info *models.TokenInfo) (*models.ProjectMetrics, error) {
 	prompt := fmt.Sprintf(`分析以下加密货币项目并提供详细评估:
@@ -99,7 +157,7 @@ func (a *DeepSeekAnalyzer) AnalyzeProject(ctx context.Context, info *models.Toke
 		info.Name, info.Symbol, info.ContractAddress, info.Network,
 		info.LaunchType, info.InitialPrice, info.TotalSupply, info.CirculatingSupply)
 
-	resp, err := a.createChatCompletion(ctx, prompt)
+	resp, err := a.createChatCompletion(ctx, ai.MethodAnalyzeProject, prompt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to analyze project: %w", err)
 	}
@@ -119,8 +177,8 @@ func (a *DeepSeekAnalyzer) AnalyzeProject(ctx context.Context, info *models.Toke
 		} `json:"analysis"`
 	}
 
-	if err := json.Unmarshal([]byte(resp), &analysis); err != nil {
-		return nil, fmt.Errorf("failed to parse analysis results: %w", err)
+	if err := json.Unmarshal([]byte(resp.content), &analysis); err != nil {
+		return nil, fmt.Errorf("%w: failed to parse analysis results: %w", ai.ErrInvalidResponse, err)
 	}
 
 	return &models.ProjectMetrics{
@@ -136,18 +194,11 @@ func (a *DeepSeekAnalyzer) AnalyzeProject(ctx context.Context, info *models.Toke
 // PredictPrice implements the Analyzer interface
 func (a *DeepSeekAnalyzer) PredictPrice(ctx context.Context, data []models.MarketData) (*ai.PricePrediction, error) {
 	if len(data) == 0 {
-		return nil, fmt.Errorf("no market data provided")
+		return nil, fmt.Errorf("%w: no market data provided", ai.ErrInsufficientData)
 	}
 
-	marketDataDesc := strings.Builder{}
-	marketDataDesc.WriteString("市场数据分析：\n")
-	for _, d := range data {
-		marketDataDesc.WriteString(fmt.Sprintf("时间: %s\n价格: %.8f\n24h成交量: %.2f\n市值: %.2f\n\n",
-			d.Timestamp.Format("2006-01-02 15:04:05"),
-			d.Price,
-			d.Volume24h,
-			d.MarketCap))
-	}
+	// 历史行情以统计量描述，只附带最近的行情，减少提示词长度
+	marketDataDesc := ai.DescribeMarket(ctx, data)
 
 	prompt := fmt.Sprintf(`基于以下市场数据，对%s进行价格预测分析：
 
@@ -166,9 +217,9 @@ func (a *DeepSeekAnalyzer) PredictPrice(ctx context.Context, data []models.Marke
     "factors": ["因素1", "因素2", ...],
     "reasoning": "详细分析理由",
     "potential_risks": ["风险1", "风险2", ...]
-}`, data[0].Symbol, marketDataDesc.String())
+}`, data[0].Symbol, marketDataDesc)
 
-	resp, err := a.createChatCompletion(ctx, prompt)
+	resp, err := a.createChatCompletion(ctx, ai.MethodPredictPrice, prompt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to predict price: %w", err)
 	}
@@ -181,8 +232,8 @@ func (a *DeepSeekAnalyzer) PredictPrice(ctx context.Context, data []models.Marke
 		PotentialRisks []string `json:"potential_risks"`
 	}
 
-	if err := json.Unmarshal([]byte(resp), &prediction); err != nil {
-		return nil, fmt.Errorf("failed to parse prediction results: %w", err)
+	if err := json.Unmarshal([]byte(resp.content), &prediction); err != nil {
+		return nil, fmt.Errorf("%w: failed to parse prediction results: %w", ai.ErrInvalidResponse, err)
 	}
 
 	return &ai.PricePrediction{
@@ -191,6 +242,7 @@ func (a *DeepSeekAnalyzer) PredictPrice(ctx context.Context, data []models.Marke
 		Confidence:     prediction.Confidence,
 		TimeFrame:      "24h",
 		Factors:        prediction.Factors,
+		Reasoning:      resp.reasoning,
 	}, nil
 }
 
@@ -236,7 +288,7 @@ func (a *DeepSeekAnalyzer) DetectScam(ctx context.Context, projectData *models.P
 		projectData.MarketSentiment,
 		projectData.RiskScore)
 
-	resp, err := a.createChatCompletion(ctx, prompt)
+	resp, err := a.createChatCompletion(ctx, ai.MethodDetectScam, prompt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to detect scam: %w", err)
 	}
@@ -249,14 +301,15 @@ func (a *DeepSeekAnalyzer) DetectScam(ctx context.Context, projectData *models.P
 		Recommendations []string `json:"recommendations"`
 	}
 
-	if err := json.Unmarshal([]byte(resp), &result); err != nil {
-		return nil, fmt.Errorf("failed to parse scam analysis results: %w", err)
+	if err := json.Unmarshal([]byte(resp.content), &result); err != nil {
+		return nil, fmt.Errorf("%w: failed to parse scam analysis results: %w", ai.ErrInvalidResponse, err)
 	}
 
 	return &ai.ScamAnalysis{
 		ScamProbability: result.ScamProbability,
 		RiskFactors:     result.RiskFactors,
 		Confidence:      result.Confidence,
+		Reasoning:       resp.reasoning,
 	}, nil
 }
 
@@ -284,7 +337,7 @@ func (a *DeepSeekAnalyzer) AnalyzeSentiment(ctx context.Context, socialData map[
     "trends": ["趋势1", "趋势2", ...]
 }`, socialText.String())
 
-	resp, err := a.createChatCompletion(ctx, prompt)
+	resp, err := a.createChatCompletion(ctx, ai.MethodAnalyzeSentiment, prompt)
 	if err != nil {
 		return 0, fmt.Errorf("failed to analyze sentiment: %w", err)
 	}
@@ -296,17 +349,23 @@ func (a *DeepSeekAnalyzer) AnalyzeSentiment(ctx context.Context, socialData map[
 		Trends         []string `json:"trends"`
 	}
 
-	if err := json.Unmarshal([]byte(resp), &result); err != nil {
-		return 0, fmt.Errorf("failed to parse sentiment results: %w", err)
+	if err := json.Unmarshal([]byte(resp.content), &result); err != nil {
+		return 0, fmt.Errorf("%w: failed to parse sentiment results: %w", ai.ErrInvalidResponse, err)
 	}
 
 	return result.SentimentScore, nil
 }
 
-// createChatCompletion sends a request to the DeepSeek API
-func (a *DeepSeekAnalyzer) createChatCompletion(ctx context.Context, prompt string) (string, error) {
+// createChatCompletion sends a request to the DeepSeek API with the generation parameters of the method
+func (a *DeepSeekAnalyzer) createChatCompletion(ctx context.Context, method, prompt string) (*completion, error) {
+	params := a.generation.For(method)
+	model := a.model
+	if params.Model != "" {
+		model = params.Model
+	}
+
 	reqBody := chatRequest{
-		Model: a.model,
+		Model: model,
 		Messages: []chatMessage{
 			{
 				Role:    "system",
@@ -317,19 +376,25 @@ func (a *DeepSeekAnalyzer) createChatCompletion(ctx context.Context, prompt stri
 				Content: prompt,
 			},
 		},
-		Temperature: 0.3,
+		MaxTokens: params.MaxTokens,
+		Stream:    params.Stream,
+	}
+	// 推理模型忽略采样参数
+	if !isReasoner(model) {
+		reqBody.Temperature = params.Temperature
+		reqBody.TopP = params.TopP
 	}
 
 	reqBytes, err := json.Marshal(reqBody)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, "POST",
 		fmt.Sprintf("%s/chat/completions", a.endpoint),
 		bytes.NewBuffer(reqBytes))
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -337,35 +402,106 @@ func (a *DeepSeekAnalyzer) createChatCompletion(ctx context.Context, prompt stri
 
 	resp, err := a.client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to send request: %w", err)
+		return nil, fmt.Errorf("%w: failed to send request: %w", ai.ErrProviderUnavailable, err)
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("%w: status=%d, body=%s", ai.ErrProviderUnavailable, resp.StatusCode, string(body))
+	}
+
+	var result *completion
+	if params.Stream {
+		result, err = readStream(resp.Body)
+	} else {
+		result, err = readResponse(resp.Body)
+	}
 	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
+		return nil, err
 	}
+	result.content = extractJSON(result.content)
+	return result, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("api error: status=%d, body=%s", resp.StatusCode, string(body))
+// readResponse 读取完整响应
+func readResponse(r io.Reader) (*completion, error) {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read response: %w", ai.ErrProviderUnavailable, err)
 	}
 
 	if !json.Valid(body) {
-		return "", fmt.Errorf("API 返回无效的 JSON 响应")
+		return nil, fmt.Errorf("%w: API 返回无效的 JSON 响应", ai.ErrInvalidResponse)
 	}
 
 	var chatResp chatResponse
 	if err := json.Unmarshal(body, &chatResp); err != nil {
-		return "", fmt.Errorf("failed to parse response: %w", err)
+		return nil, fmt.Errorf("%w: failed to parse response: %w", ai.ErrInvalidResponse, err)
 	}
 
 	if chatResp.Error != nil {
-		return "", fmt.Errorf("api error: %s", chatResp.Error.Message)
+		return nil, fmt.Errorf("%w: %s", ai.ErrProviderUnavailable, chatResp.Error.Message)
 	}
 
 	if len(chatResp.Choices) == 0 {
-		return "", fmt.Errorf("no response from api")
+		return nil, fmt.Errorf("%w: no response from api", ai.ErrInvalidResponse)
 	}
 
-	return chatResp.Choices[0].Message.Content, nil
+	message := chatResp.Choices[0].Message
+	return &completion{content: message.Content, reasoning: message.ReasoningContent}, nil
+}
+
+// readStream 读取 server-sent events 格式的流式响应，拼接回答和推理过程的增量
+func readStream(r io.Reader) (*completion, error) {
+	var content, reasoning strings.Builder
+	var received bool
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		data, ok := strings.CutPrefix(line, "data:")
+		if !ok {
+			// 空行和服务端保活注释
+			continue
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			break
+		}
+
+		var chunk chatChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return nil, fmt.Errorf("%w: failed to parse stream chunk: %w", ai.ErrInvalidResponse, err)
+		}
+		if chunk.Error != nil {
+			return nil, fmt.Errorf("%w: %s", ai.ErrProviderUnavailable, chunk.Error.Message)
+		}
+		for _, choice := range chunk.Choices {
+			received = true
+			content.WriteString(choice.Delta.Content)
+			reasoning.WriteString(choice.Delta.ReasoningContent)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: failed to read stream: %w", ai.ErrProviderUnavailable, err)
+	}
+
+	if !received {
+		return nil, fmt.Errorf("%w: no response from api", ai.ErrInvalidResponse)
+	}
+	return &completion{content: content.String(), reasoning: reasoning.String()}, nil
+}
+
+// extractJSON 去掉回答外层的 Markdown 代码块，推理模型不支持 JSON 输出模式，常用代码块包裹结果
+func extractJSON(content string) string {
+	content = strings.TrimSpace(content)
+	if !strings.HasPrefix(content, "```") {
+		return content
+	}
+	content = strings.TrimPrefix(content, "```")
+	content = strings.TrimPrefix(content, "json")
+	content = strings.TrimSuffix(strings.TrimSpace(content), "```")
+	return strings.TrimSpace(content)
 }
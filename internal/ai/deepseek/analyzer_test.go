@@ -2,17 +2,31 @@ package deepseek
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 	"time"
 
+	"github.com/songzhibin97/quantaflux/internal/ai"
 	"github.com/songzhibin97/quantaflux/internal/models"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var apiKey = os.Getenv("DEEPSEEK_API_KEY")
 
+// skipWithoutAPIKey 测试调用真实的 AI 服务，未设置 API 密钥时跳过
+func skipWithoutAPIKey(t *testing.T) {
+	t.Helper()
+	if apiKey == "" {
+		t.Skip("DEEPSEEK_API_KEY not set")
+	}
+}
+
 func TestOpenAIAnalyzer_AnalyzeProject(t *testing.T) {
+	skipWithoutAPIKey(t)
 	analyzer := NewDeepSeekAnalyzer(apiKey, "")
 
 	info := &models.TokenInfo{
@@ -29,13 +43,14 @@ func TestOpenAIAnalyzer_AnalyzeProject(t *testing.T) {
 	ctx := context.Background()
 	metrics, err := analyzer.AnalyzeProject(ctx, info)
 
-	assert.NoError(t, err)
-	assert.NotNil(t, metrics)
+	require.NoError(t, err)
+	require.NotNil(t, metrics)
 	assert.GreaterOrEqual(t, metrics.SocialScore, 0.0)
 	assert.LessOrEqual(t, metrics.SocialScore, 100.0)
 }
 
 func TestOpenAIAnalyzer_PredictPrice(t *testing.T) {
+	skipWithoutAPIKey(t)
 	analyzer := NewDeepSeekAnalyzer(apiKey, "")
 
 	data := []models.MarketData{
@@ -65,6 +80,7 @@ func TestOpenAIAnalyzer_PredictPrice(t *testing.T) {
 }
 
 func TestOpenAIAnalyzer_DetectScam(t *testing.T) {
+	skipWithoutAPIKey(t)
 	analyzer := NewDeepSeekAnalyzer(apiKey, "")
 
 	projectData := &models.ProjectMetrics{
@@ -88,3 +104,84 @@ func TestOpenAIAnalyzer_DetectScam(t *testing.T) {
 	assert.LessOrEqual(t, analysis.ScamProbability, 1.0)
 	assert.NotEmpty(t, analysis.RiskFactors)
 }
+
+func TestDeepSeekAnalyzer_GenerationOptions(t *testing.T) {
+	var requests []chatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req chatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests = append(requests, req)
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"{\"sentiment_score\":0.5,\"scam_probability\":0.1}"}}]}`))
+	}))
+	defer server.Close()
+
+	analyzer := NewDeepSeekAnalyzer("key", "")
+	analyzer.endpoint = server.URL
+	topP, scamTemperature := 0.9, 0.0
+	analyzer.SetGenerationOptions(ai.GenerationOptions{
+		Default: ai.GenerationParams{TopP: &topP, MaxTokens: 512},
+		Methods: map[string]ai.GenerationParams{
+			ai.MethodDetectScam: {Temperature: &scamTemperature, MaxTokens: 2048},
+		},
+	})
+
+	ctx := context.Background()
+	_, err := analyzer.AnalyzeSentiment(ctx, map[string]string{"twitter": "bullish"})
+	require.NoError(t, err)
+	_, err = analyzer.DetectScam(ctx, &models.ProjectMetrics{})
+	require.NoError(t, err)
+
+	require.Len(t, requests, 2)
+	require.NotNil(t, requests[0].Temperature)
+	assert.Equal(t, ai.DefaultTemperature, *requests[0].Temperature)
+	assert.Equal(t, &topP, requests[0].TopP)
+	assert.Equal(t, 512, requests[0].MaxTokens)
+
+	require.NotNil(t, requests[1].Temperature)
+	assert.Zero(t, *requests[1].Temperature)
+	assert.Equal(t, &topP, requests[1].TopP)
+	assert.Equal(t, 2048, requests[1].MaxTokens)
+}
+
+func TestDeepSeekAnalyzer_StreamReasoner(t *testing.T) {
+	var requests []chatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req chatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests = append(requests, req)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(": keep-alive\n\n"))
+		for _, chunk := range []string{
+			`{"choices":[{"delta":{"reasoning_content":"流动性集中，"}}]}`,
+			`{"choices":[{"delta":{"reasoning_content":"团队匿名"}}]}`,
+			`{"choices":[{"delta":{"content":"` + "```" + `json\n{\"scam_probability\":"}}]}`,
+			`{"choices":[{"delta":{"content":"0.7,\"risk_factors\":[\"匿名团队\"],\"confidence\":0.8}\n` + "```" + `"}}]}`,
+		} {
+			_, _ = w.Write([]byte("data: " + chunk + "\n\n"))
+		}
+		_, _ = w.Write([]byte("data: [DONE]\n\n"))
+	}))
+	defer server.Close()
+
+	analyzer := NewDeepSeekAnalyzer("key", "")
+	analyzer.endpoint = server.URL
+	analyzer.SetGenerationOptions(ai.GenerationOptions{
+		Methods: map[string]ai.GenerationParams{
+			ai.MethodDetectScam: {Model: ReasonerModel, Stream: true},
+		},
+	})
+
+	analysis, err := analyzer.DetectScam(context.Background(), &models.ProjectMetrics{})
+	require.NoError(t, err)
+	assert.InDelta(t, 0.7, analysis.ScamProbability, 1e-9)
+	assert.Equal(t, []string{"匿名团队"}, analysis.RiskFactors)
+	assert.Equal(t, "流动性集中，团队匿名", analysis.Reasoning)
+
+	// 推理模型不发送采样参数
+	require.Len(t, requests, 1)
+	assert.Equal(t, ReasonerModel, requests[0].Model)
+	assert.True(t, requests[0].Stream)
+	assert.Nil(t, requests[0].Temperature)
+	assert.Nil(t, requests[0].TopP)
+}
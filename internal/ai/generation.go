@@ -0,0 +1,71 @@
+package ai
+
+// 分析方法，用于按方法设置生成参数
+const (
+	MethodAnalyzeProject   = "analyze_project"
+	MethodPredictPrice     = "predict_price"
+	MethodDetectScam       = "detect_scam"
+	MethodAnalyzeSentiment = "analyze_sentiment"
+)
+
+// Methods 所有分析方法
+var Methods = []string{MethodAnalyzeProject, MethodPredictPrice, MethodDetectScam, MethodAnalyzeSentiment}
+
+// DefaultTemperature 未配置时的采样温度，较低的温度输出更稳定
+const DefaultTemperature = 0.3
+
+// GenerationParams 模型生成参数，未设置的项使用默认值
+type GenerationParams struct {
+	Model       string   `json:"model" yaml:"model"`             // 使用的模型，为空时使用分析器的模型，如诈骗检测使用推理模型
+	Temperature *float64 `json:"temperature" yaml:"temperature"` // 采样温度，越低输出越确定
+	TopP        *float64 `json:"top_p" yaml:"top_p"`             // 核采样概率，为空时由服务端决定
+	MaxTokens   int      `json:"max_tokens" yaml:"max_tokens"`   // 最大输出长度，0 表示由服务端决定
+	Stream      bool     `json:"stream" yaml:"stream"`           // 流式接收回答，推理模型耗时较长时避免连接长时间空闲
+}
+
+// GenerationOptions 默认生成参数和按分析方法的覆盖
+type GenerationOptions struct {
+	Default GenerationParams
+	Methods map[string]GenerationParams // 分析方法 -> 覆盖项
+}
+
+// For 返回分析方法的生成参数：方法覆盖项优先，其次是默认参数，温度未配置时为 DefaultTemperature
+func (o GenerationOptions) For(method string) GenerationParams {
+	params := o.Default
+	if override, ok := o.Methods[method]; ok {
+		if override.Model != "" {
+			params.Model = override.Model
+		}
+		if override.Temperature != nil {
+			params.Temperature = override.Temperature
+		}
+		if override.TopP != nil {
+			params.TopP = override.TopP
+		}
+		if override.MaxTokens > 0 {
+			params.MaxTokens = override.MaxTokens
+		}
+		if override.Stream {
+			params.Stream = true
+		}
+	}
+	if params.Temperature == nil {
+		temperature := DefaultTemperature
+		params.Temperature = &temperature
+	}
+	return params
+}
+
+// WithoutModels 返回去掉模型覆盖项的副本，用于需要固定模型的分析器，如 A/B 对比的挑战者
+func (o GenerationOptions) WithoutModels() GenerationOptions {
+	result := GenerationOptions{Default: o.Default}
+	result.Default.Model = ""
+	if o.Methods != nil {
+		result.Methods = make(map[string]GenerationParams, len(o.Methods))
+		for method, params := range o.Methods {
+			params.Model = ""
+			result.Methods[method] = params
+		}
+	}
+	return result
+}
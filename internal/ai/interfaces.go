@@ -2,10 +2,20 @@ package ai
 
 import (
 	"context"
+	"errors"
 
 	"github.com/songzhibin97/quantaflux/internal/models"
 )
 
+var (
+	// ErrProviderUnavailable AI 服务无法访问或返回错误状态
+	ErrProviderUnavailable = errors.New("ai provider unavailable")
+	// ErrInvalidResponse AI 服务返回的内容无法解析
+	ErrInvalidResponse = errors.New("invalid ai response")
+	// ErrInsufficientData 输入数据不足以完成分析
+	ErrInsufficientData = errors.New("insufficient data")
+)
+
 // Analyzer defines methods for AI analysis
 type Analyzer interface {
 	// AnalyzeProject performs comprehensive project analysis
@@ -26,8 +36,10 @@ type PricePrediction struct {
 	Symbol         string   `json:"symbol"`
 	PredictedPrice float64  `json:"predicted_price"`
 	Confidence     float64  `json:"confidence"`
+	RawConfidence  float64  `json:"raw_confidence,omitempty"` // 校准前模型给出的置信度，未校准时为 0
 	TimeFrame      string   `json:"time_frame"`
 	Factors        []string `json:"factors"`
+	Reasoning      string   `json:"reasoning,omitempty"` // 推理模型输出的推理过程
 }
 
 // ScamAnalysis 欺诈分析结果
@@ -35,4 +47,5 @@ type ScamAnalysis struct {
 	ScamProbability float64  `json:"scam_probability"`
 	RiskFactors     []string `json:"risk_factors"`
 	Confidence      float64  `json:"confidence"`
+	Reasoning       string   `json:"reasoning,omitempty"` // 推理模型输出的推理过程
 }
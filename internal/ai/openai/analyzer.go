@@ -12,8 +12,9 @@ import (
 
 // OpenAIAnalyzer implements the Analyzer interface using OpenAI
 type OpenAIAnalyzer struct {
-	client *openai.Client
-	model  string
+	client     *openai.Client
+	model      string
+	generation ai.GenerationOptions
 }
 
 // NewOpenAIAnalyzer creates a new OpenAI analyzer instance
@@ -28,6 +29,19 @@ func NewOpenAIAnalyzer(apiKey string, model string) *OpenAIAnalyzer {
 	}
 }
 
+// SetGenerationOptions sets the sampling parameters of each analysis method
+func (a *OpenAIAnalyzer) SetGenerationOptions(options ai.GenerationOptions) {
+	a.generation = options
+}
+
+// Ping checks that the OpenAI API is reachable with the configured key
+func (a *OpenAIAnalyzer) Ping(ctx context.Context) error {
+	if _, err := a.client.ListModels(ctx); err != nil {
+		return fmt.Errorf("%w: %w", ai.ErrProviderUnavailable, err)
+	}
+	return nil
+}
+
 // AnalyzeProject implements the Analyzer interface
 func (a *OpenAIAnalyzer) AnalyzeProject(ctx context.Context, info *models.TokenInfo) (*models.ProjectMetrics, error) {
 	prompt := fmt.Sprintf(`分析以下加密货币项目并提供详细评估:
@@ -58,7 +72,7 @@ func (a *OpenAIAnalyzer) AnalyzeProject(ctx context.Context, info *models.TokenI
 		info.Name, info.Symbol, info.ContractAddress, info.Network,
 		info.LaunchType, info.InitialPrice, info.TotalSupply, info.CirculatingSupply)
 
-	resp, err := a.createChatCompletion(ctx, prompt)
+	resp, err := a.createChatCompletion(ctx, ai.MethodAnalyzeProject, prompt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to analyze project: %w", err)
 	}
@@ -72,7 +86,7 @@ func (a *OpenAIAnalyzer) AnalyzeProject(ctx context.Context, info *models.TokenI
 	}
 
 	if err := json.Unmarshal([]byte(resp), &scores); err != nil {
-		return nil, fmt.Errorf("failed to parse analysis results: %w", err)
+		return nil, fmt.Errorf("%w: failed to parse analysis results: %w", ai.ErrInvalidResponse, err)
 	}
 
 	return &models.ProjectMetrics{
@@ -88,24 +102,17 @@ func (a *OpenAIAnalyzer) AnalyzeProject(ctx context.Context, info *models.TokenI
 // PredictPrice implements the Analyzer interface
 func (a *OpenAIAnalyzer) PredictPrice(ctx context.Context, data []models.MarketData) (*ai.PricePrediction, error) {
 	if len(data) == 0 {
-		return nil, fmt.Errorf("no market data provided")
+		return nil, fmt.Errorf("%w: no market data provided", ai.ErrInsufficientData)
 	}
 
-	// 构建市场数据的时间序列描述
-	marketDataDesc := "市场数据分析:\n"
-	for _, d := range data {
-		marketDataDesc += fmt.Sprintf("时间: %s, 价格: %.8f, 24h成交量: %.2f, 市值: %.2f\n",
-			d.Timestamp.Format("2006-01-02 15:04:05"),
-			d.Price,
-			d.Volume24h,
-			d.MarketCap)
-	}
+	// 历史行情以统计量描述，只附带最近的行情，减少提示词长度
+	marketDataDesc := ai.DescribeMarket(ctx, data)
 
 	prompt := fmt.Sprintf(`基于以下市场数据预测%s的价格走势:
 %s
 
 请分析价格趋势并预测未来24小时的价格变动。
-考虑因素包括：价格趋势、成交量变化、市值变化等。
+考虑因素包括：价格趋势、波动率、成交量变化、支撑位和阻力位、市值变化等。
 
 输出格式为JSON:
 {
@@ -114,7 +121,7 @@ func (a *OpenAIAnalyzer) PredictPrice(ctx context.Context, data []models.MarketD
     "factors": ["因素1", "因素2", ...]
 }`, data[0].Symbol, marketDataDesc)
 
-	resp, err := a.createChatCompletion(ctx, prompt)
+	resp, err := a.createChatCompletion(ctx, ai.MethodPredictPrice, prompt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to predict price: %w", err)
 	}
@@ -126,7 +133,7 @@ func (a *OpenAIAnalyzer) PredictPrice(ctx context.Context, data []models.MarketD
 	}
 
 	if err := json.Unmarshal([]byte(resp), &prediction); err != nil {
-		return nil, fmt.Errorf("failed to parse prediction results: %w", err)
+		return nil, fmt.Errorf("%w: failed to parse prediction results: %w", ai.ErrInvalidResponse, err)
 	}
 
 	return &ai.PricePrediction{
@@ -158,7 +165,7 @@ func (a *OpenAIAnalyzer) AnalyzeSentiment(ctx context.Context, socialData map[st
     "sentiment_score": float
 }`, socialDataText)
 
-	resp, err := a.createChatCompletion(ctx, prompt)
+	resp, err := a.createChatCompletion(ctx, ai.MethodAnalyzeSentiment, prompt)
 	if err != nil {
 		return 0, fmt.Errorf("failed to analyze sentiment: %w", err)
 	}
@@ -168,7 +175,7 @@ func (a *OpenAIAnalyzer) AnalyzeSentiment(ctx context.Context, socialData map[st
 	}
 
 	if err := json.Unmarshal([]byte(resp), &sentiment); err != nil {
-		return 0, fmt.Errorf("failed to parse sentiment results: %w", err)
+		return 0, fmt.Errorf("%w: failed to parse sentiment results: %w", ai.ErrInvalidResponse, err)
 	}
 
 	return sentiment.Score, nil
@@ -203,44 +210,52 @@ func (a *OpenAIAnalyzer) DetectScam(ctx context.Context, projectData *models.Pro
 		projectData.MarketSentiment,
 		projectData.RiskScore)
 
-	resp, err := a.createChatCompletion(ctx, prompt)
+	resp, err := a.createChatCompletion(ctx, ai.MethodDetectScam, prompt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to detect scam: %w", err)
 	}
 
 	var scamAnalysis ai.ScamAnalysis
 	if err := json.Unmarshal([]byte(resp), &scamAnalysis); err != nil {
-		return nil, fmt.Errorf("failed to parse scam analysis results: %w", err)
+		return nil, fmt.Errorf("%w: failed to parse scam analysis results: %w", ai.ErrInvalidResponse, err)
 	}
 
 	return &scamAnalysis, nil
 }
 
-// createChatCompletion is a helper function to make OpenAI API calls
-func (a *OpenAIAnalyzer) createChatCompletion(ctx context.Context, prompt string) (string, error) {
-	resp, err := a.client.CreateChatCompletion(
-		ctx,
-		openai.ChatCompletionRequest{
-			Model: a.model,
-			Messages: []openai.ChatCompletionMessage{
-				{
-					Role:    openai.ChatMessageRoleSystem,
-					Content: "你是一个专业的加密货币分析师，擅长项目分析、价格预测和风险评估。请始终以JSON格式返回分析结果。",
-				},
-				{
-					Role:    openai.ChatMessageRoleUser,
-					Content: prompt,
-				},
+// createChatCompletion is a helper function to make OpenAI API calls with the generation parameters of the method
+func (a *OpenAIAnalyzer) createChatCompletion(ctx context.Context, method, prompt string) (string, error) {
+	params := a.generation.For(method)
+	model := a.model
+	if params.Model != "" {
+		model = params.Model
+	}
+	request := openai.ChatCompletionRequest{
+		Model: model,
+		Messages: []openai.ChatCompletionMessage{
+			{
+				Role:    openai.ChatMessageRoleSystem,
+				Content: "你是一个专业的加密货币分析师，擅长项目分析、价格预测和风险评估。请始终以JSON格式返回分析结果。",
+			},
+			{
+				Role:    openai.ChatMessageRoleUser,
+				Content: prompt,
 			},
-			Temperature: 0.3, // 使用较低的temperature以获得更稳定的输出
 		},
-	)
+		Temperature: float32(*params.Temperature),
+		MaxTokens:   params.MaxTokens,
+	}
+	if params.TopP != nil {
+		request.TopP = float32(*params.TopP)
+	}
+
+	resp, err := a.client.CreateChatCompletion(ctx, request)
 	if err != nil {
-		return "", fmt.Errorf("openai api error: %w", err)
+		return "", fmt.Errorf("%w: %w", ai.ErrProviderUnavailable, err)
 	}
 
 	if len(resp.Choices) == 0 {
-		return "", fmt.Errorf("no response from openai")
+		return "", fmt.Errorf("%w: no response from openai", ai.ErrInvalidResponse)
 	}
 
 	return resp.Choices[0].Message.Content, nil
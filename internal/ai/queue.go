@@ -0,0 +1,165 @@
+package ai
+
+import (
+	"context"
+	"sync"
+
+	"github.com/songzhibin97/quantaflux/internal/models"
+)
+
+// 调用优先级，数值越小越先执行
+const (
+	PriorityCritical   = iota // 诈骗检测，结论决定是否停止交易
+	PriorityNormal            // 价格预测和情绪分析
+	PriorityBackground        // 周期性的项目分析
+)
+
+// MethodPriority 返回分析方法的优先级
+func MethodPriority(method string) int {
+	switch method {
+	case MethodDetectScam:
+		return PriorityCritical
+	case MethodAnalyzeProject:
+		return PriorityBackground
+	default:
+		return PriorityNormal
+	}
+}
+
+// waiter 排队等待执行的调用
+type waiter struct {
+	priority int
+	seq      uint64
+	ready    chan struct{} // 轮到执行时关闭
+	granted  bool
+}
+
+// Queue 按优先级排队调用分析器，同时执行的调用不超过 limit 个，同优先级先到先执行；
+// 排队中的调用在 ctx 取消后直接出队并返回 ctx 的错误。可并发使用
+type Queue struct {
+	analyzer Analyzer
+	limit    int
+
+	mu      sync.Mutex
+	running int
+	seq     uint64
+	waiting []*waiter
+}
+
+// NewQueue 创建调用队列，limit 小于等于 0 时直接返回 analyzer，不排队
+func NewQueue(analyzer Analyzer, limit int) Analyzer {
+	if limit <= 0 {
+		return analyzer
+	}
+	return &Queue{analyzer: analyzer, limit: limit}
+}
+
+// Unwrap 返回被排队调用的分析器
+func (q *Queue) Unwrap() Analyzer {
+	return q.analyzer
+}
+
+// Waiting 返回正在排队的调用数量
+func (q *Queue) Waiting() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.waiting)
+}
+
+// acquire 等待执行名额，成功后调用方需调用 release
+func (q *Queue) acquire(ctx context.Context, priority int) error {
+	q.mu.Lock()
+	if q.running < q.limit && len(q.waiting) == 0 {
+		q.running++
+		q.mu.Unlock()
+		return nil
+	}
+	q.seq++
+	w := &waiter{priority: priority, seq: q.seq, ready: make(chan struct{})}
+	q.waiting = append(q.waiting, w)
+	q.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		if w.granted {
+			// 取消的同时轮到执行，把名额交给下一个调用
+			q.handOff()
+			return ctx.Err()
+		}
+		for i, queued := range q.waiting {
+			if queued == w {
+				q.waiting = append(q.waiting[:i], q.waiting[i+1:]...)
+				break
+			}
+		}
+		return ctx.Err()
+	}
+}
+
+// release 归还执行名额
+func (q *Queue) release() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.handOff()
+}
+
+// handOff 把一个执行名额交给优先级最高、最早排队的调用，没有排队的调用时归还名额，调用方需持有锁
+func (q *Queue) handOff() {
+	if len(q.waiting) == 0 {
+		q.running--
+		return
+	}
+	next := 0
+	for i, w := range q.waiting {
+		if w.priority < q.waiting[next].priority ||
+			(w.priority == q.waiting[next].priority && w.seq < q.waiting[next].seq) {
+			next = i
+		}
+	}
+	w := q.waiting[next]
+	q.waiting = append(q.waiting[:next], q.waiting[next+1:]...)
+	w.granted = true
+	close(w.ready)
+}
+
+// AnalyzeProject implements the Analyzer interface
+func (q *Queue) AnalyzeProject(ctx context.Context, info *models.TokenInfo) (*models.ProjectMetrics, error) {
+	if err := q.acquire(ctx, MethodPriority(MethodAnalyzeProject)); err != nil {
+		return nil, err
+	}
+	defer q.release()
+	return q.analyzer.AnalyzeProject(ctx, info)
+}
+
+// PredictPrice implements the Analyzer interface
+func (q *Queue) PredictPrice(ctx context.Context, data []models.MarketData) (*PricePrediction, error) {
+	if err := q.acquire(ctx, MethodPriority(MethodPredictPrice)); err != nil {
+		return nil, err
+	}
+	defer q.release()
+	return q.analyzer.PredictPrice(ctx, data)
+}
+
+// AnalyzeSentiment implements the Analyzer interface
+func (q *Queue) AnalyzeSentiment(ctx context.Context, socialData map[string]string) (float64, error) {
+	if err := q.acquire(ctx, MethodPriority(MethodAnalyzeSentiment)); err != nil {
+		return 0, err
+	}
+	defer q.release()
+	return q.analyzer.AnalyzeSentiment(ctx, socialData)
+}
+
+// DetectScam implements the Analyzer interface
+func (q *Queue) DetectScam(ctx context.Context, projectData *models.ProjectMetrics) (*ScamAnalysis, error) {
+	if err := q.acquire(ctx, MethodPriority(MethodDetectScam)); err != nil {
+		return nil, err
+	}
+	defer q.release()
+	return q.analyzer.DetectScam(ctx, projectData)
+}
@@ -0,0 +1,101 @@
+package ai
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gatedAnalyzer 记录调用顺序，每次调用阻塞到 gate 放行
+type gatedAnalyzer struct {
+	gate chan struct{}
+
+	mu    sync.Mutex
+	calls []string
+}
+
+func (g *gatedAnalyzer) call(method string) {
+	g.mu.Lock()
+	g.calls = append(g.calls, method)
+	g.mu.Unlock()
+	<-g.gate
+}
+
+func (g *gatedAnalyzer) AnalyzeProject(ctx context.Context, info *models.TokenInfo) (*models.ProjectMetrics, error) {
+	g.call(MethodAnalyzeProject)
+	return &models.ProjectMetrics{}, nil
+}
+
+func (g *gatedAnalyzer) PredictPrice(ctx context.Context, data []models.MarketData) (*PricePrediction, error) {
+	g.call(MethodPredictPrice)
+	return &PricePrediction{}, nil
+}
+
+func (g *gatedAnalyzer) AnalyzeSentiment(ctx context.Context, socialData map[string]string) (float64, error) {
+	g.call(MethodAnalyzeSentiment)
+	return 0, nil
+}
+
+func (g *gatedAnalyzer) DetectScam(ctx context.Context, projectData *models.ProjectMetrics) (*ScamAnalysis, error) {
+	g.call(MethodDetectScam)
+	return &ScamAnalysis{}, nil
+}
+
+func TestQueue_Priority(t *testing.T) {
+	ctx := context.Background()
+	analyzer := &gatedAnalyzer{gate: make(chan struct{})}
+	queue := NewQueue(analyzer, 1).(*Queue)
+	assert.Same(t, Analyzer(analyzer), NewQueue(analyzer, 0))
+
+	var wg sync.WaitGroup
+	run := func(fn func()) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fn()
+		}()
+	}
+	waitFor := func(n int) {
+		require.Eventually(t, func() bool { return queue.Waiting() == n }, time.Second, time.Millisecond)
+	}
+
+	// 第一个调用占用名额，之后的调用按到达顺序排队
+	run(func() { _, _ = queue.PredictPrice(ctx, nil) })
+	require.Eventually(t, func() bool {
+		analyzer.mu.Lock()
+		defer analyzer.mu.Unlock()
+		return len(analyzer.calls) == 1
+	}, time.Second, time.Millisecond)
+	run(func() { _, _ = queue.AnalyzeProject(ctx, &models.TokenInfo{}) })
+	waitFor(1)
+	run(func() { _, _ = queue.AnalyzeSentiment(ctx, nil) })
+	waitFor(2)
+	run(func() { _, _ = queue.DetectScam(ctx, &models.ProjectMetrics{}) })
+	waitFor(3)
+
+	// 排队中的调用取消后出队
+	cancelled, cancel := context.WithCancel(ctx)
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := queue.PredictPrice(cancelled, nil)
+		errCh <- err
+	}()
+	waitFor(4)
+	cancel()
+	assert.ErrorIs(t, <-errCh, context.Canceled)
+	assert.Equal(t, 3, queue.Waiting())
+
+	for i := 0; i < 4; i++ {
+		analyzer.gate <- struct{}{}
+	}
+	wg.Wait()
+
+	assert.Equal(t, []string{MethodPredictPrice, MethodDetectScam, MethodAnalyzeSentiment, MethodAnalyzeProject}, analyzer.calls)
+	assert.Zero(t, queue.running)
+}
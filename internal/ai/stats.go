@@ -0,0 +1,175 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/models"
+)
+
+// PromptRecentPoints 价格预测提示词中附带的最近行情条数，更早的行情只以统计量描述
+const PromptRecentPoints = 12
+
+// 一年的时长，用于年化波动率，加密货币全年交易
+const year = 365 * 24 * time.Hour
+
+// swingSpan 判断波段高低点时两侧比较的行情条数
+const swingSpan = 2
+
+// MarketStats 一段历史行情的统计量，代替逐条行情写入提示词
+type MarketStats struct {
+	Symbol      string
+	From        time.Time
+	To          time.Time
+	Samples     int
+	Last        float64
+	High        float64
+	Low         float64
+	Change      float64   // 区间涨跌幅
+	Volatility  float64   // 对数收益率的年化已实现波动率
+	VolumeTrend float64   // 后半段平均 24h 成交量相对前半段的变化比例
+	Support     []float64 // 当前价格下方最近的波段低点，由近及远
+	Resistance  []float64 // 当前价格上方最近的波段高点，由近及远
+}
+
+// ComputeMarketStats 统计时间有序的行情，少于 2 条时返回 false
+func ComputeMarketStats(data []models.MarketData) (MarketStats, bool) {
+	if len(data) < 2 {
+		return MarketStats{}, false
+	}
+
+	first, last := data[0], data[len(data)-1]
+	stats := MarketStats{
+		Symbol:  last.Symbol,
+		From:    first.Timestamp,
+		To:      last.Timestamp,
+		Samples: len(data),
+		Last:    last.Price,
+		High:    last.Price,
+		Low:     last.Price,
+	}
+	if first.Price > 0 {
+		stats.Change = last.Price/first.Price - 1
+	}
+
+	var returns []float64
+	for i, d := range data {
+		stats.High = max(stats.High, d.Price)
+		stats.Low = min(stats.Low, d.Price)
+		if i > 0 && d.Price > 0 && data[i-1].Price > 0 {
+			returns = append(returns, math.Log(d.Price/data[i-1].Price))
+		}
+	}
+	if len(returns) > 1 && last.Timestamp.After(first.Timestamp) {
+		var mean, variance float64
+		for _, r := range returns {
+			mean += r
+		}
+		mean /= float64(len(returns))
+		for _, r := range returns {
+			variance += (r - mean) * (r - mean)
+		}
+		step := last.Timestamp.Sub(first.Timestamp) / time.Duration(len(data)-1)
+		stats.Volatility = math.Sqrt(variance/float64(len(returns)-1)) * math.Sqrt(float64(year)/float64(step))
+	}
+
+	half := len(data) / 2
+	if earlier, later := averageVolume(data[:half]), averageVolume(data[half:]); earlier > 0 {
+		stats.VolumeTrend = later/earlier - 1
+	}
+
+	stats.Support, stats.Resistance = swingLevels(data, last.Price)
+	return stats, true
+}
+
+func averageVolume(data []models.MarketData) float64 {
+	if len(data) == 0 {
+		return 0
+	}
+	var total float64
+	for _, d := range data {
+		total += d.Volume24h
+	}
+	return total / float64(len(data))
+}
+
+// swingLevels 返回价格两侧最近的各两个波段低点和高点：价格不高于（不低于）前后 swingSpan 条行情的点
+func swingLevels(data []models.MarketData, price float64) (support, resistance []float64) {
+	for i := swingSpan; i < len(data)-swingSpan; i++ {
+		low, high := true, true
+		for j := i - swingSpan; j <= i+swingSpan; j++ {
+			low = low && data[i].Price <= data[j].Price
+			high = high && data[i].Price >= data[j].Price
+		}
+		switch {
+		case low && data[i].Price < price && !slices.Contains(support, data[i].Price):
+			support = append(support, data[i].Price)
+		case high && data[i].Price > price && !slices.Contains(resistance, data[i].Price):
+			resistance = append(resistance, data[i].Price)
+		}
+	}
+	slices.Sort(support)
+	slices.Reverse(support)
+	slices.Sort(resistance)
+	return support[:min(len(support), 2)], resistance[:min(len(resistance), 2)]
+}
+
+// Prompt 返回统计量的提示词描述
+func (s MarketStats) Prompt() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "区间: %s 至 %s，共 %d 条行情\n", s.From.Format("2006-01-02 15:04"), s.To.Format("2006-01-02 15:04"), s.Samples)
+	fmt.Fprintf(&b, "最新价: %.8g，区间最高: %.8g，区间最低: %.8g，区间涨跌幅: %+.2f%%\n", s.Last, s.High, s.Low, s.Change*100)
+	fmt.Fprintf(&b, "年化已实现波动率: %.2f%%，成交量趋势(后半段相对前半段): %+.2f%%\n", s.Volatility*100, s.VolumeTrend*100)
+	fmt.Fprintf(&b, "支撑位: %s，阻力位: %s\n", formatLevels(s.Support, s.Low), formatLevels(s.Resistance, s.High))
+	return b.String()
+}
+
+// formatLevels 格式化价位，没有波段点时使用区间极值
+func formatLevels(levels []float64, fallback float64) string {
+	if len(levels) == 0 {
+		return fmt.Sprintf("%.8g(区间极值)", fallback)
+	}
+	parts := make([]string, len(levels))
+	for i, level := range levels {
+		parts[i] = fmt.Sprintf("%.8g", level)
+	}
+	return strings.Join(parts, ", ")
+}
+
+type statsKey struct{}
+
+// WithMarketStats 附带按存储中完整历史行情计算的统计量，价格预测优先使用，而不是按传入的降采样行情计算
+func WithMarketStats(ctx context.Context, stats MarketStats) context.Context {
+	return context.WithValue(ctx, statsKey{}, stats)
+}
+
+// MarketStatsFrom 返回 WithMarketStats 附带的统计量，未附带时返回 false
+func MarketStatsFrom(ctx context.Context) (MarketStats, bool) {
+	stats, ok := ctx.Value(statsKey{}).(MarketStats)
+	return stats, ok
+}
+
+// DescribeMarket 返回价格预测提示词中的行情描述：历史行情的统计量加最近 PromptRecentPoints 条行情
+func DescribeMarket(ctx context.Context, data []models.MarketData) string {
+	var b strings.Builder
+	stats, ok := MarketStatsFrom(ctx)
+	if !ok {
+		stats, ok = ComputeMarketStats(data)
+	}
+	if ok {
+		b.WriteString("历史行情统计:\n")
+		b.WriteString(stats.Prompt())
+		b.WriteString("\n")
+	}
+
+	b.WriteString("最近行情:\n")
+	for _, d := range data[max(0, len(data)-PromptRecentPoints):] {
+		fmt.Fprintf(&b, "时间: %s, 价格: %.8f, 24h成交量: %.2f, 市值: %.2f\n",
+			d.Timestamp.Format("2006-01-02 15:04:05"), d.Price, d.Volume24h, d.MarketCap)
+	}
+	return b.String()
+}
@@ -0,0 +1,61 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeMarketStats(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	prices := []float64{100, 98, 95, 97, 101, 104, 102, 99, 100, 103, 105, 103}
+	var data []models.MarketData
+	for i, price := range prices {
+		data = append(data, models.MarketData{
+			Symbol:    "BTCUSDT",
+			Price:     price,
+			Volume24h: float64(1000 + 100*i),
+			Timestamp: start.Add(time.Duration(i) * time.Hour),
+		})
+	}
+
+	stats, ok := ComputeMarketStats(data)
+	require.True(t, ok)
+	assert.Equal(t, 12, stats.Samples)
+	assert.Equal(t, 105.0, stats.High)
+	assert.Equal(t, 95.0, stats.Low)
+	assert.InDelta(t, 0.03, stats.Change, 1e-9)
+	assert.Greater(t, stats.Volatility, 0.0)
+	assert.Greater(t, stats.VolumeTrend, 0.0)
+	// 103 下方的波段低点 99、95，上方的波段高点 104（105 在末尾两条之内，不算波段高点）
+	assert.Equal(t, []float64{99, 95}, stats.Support)
+	assert.Equal(t, []float64{104}, stats.Resistance)
+
+	_, ok = ComputeMarketStats(data[:1])
+	assert.False(t, ok)
+}
+
+func TestDescribeMarket(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var data []models.MarketData
+	for i := 0; i < 50; i++ {
+		data = append(data, models.MarketData{Symbol: "BTCUSDT", Price: float64(100 + i), Timestamp: start.Add(time.Duration(i) * time.Minute)})
+	}
+
+	// 只附带最近的行情
+	desc := DescribeMarket(context.Background(), data)
+	assert.Equal(t, PromptRecentPoints, strings.Count(desc, "时间: "))
+	assert.Contains(t, desc, "区间最高: 149")
+
+	// 优先使用附带的完整历史统计量
+	ctx := WithMarketStats(context.Background(), MarketStats{Samples: 500, High: 200, Low: 50})
+	desc = DescribeMarket(ctx, data)
+	assert.Contains(t, desc, "共 500 条行情")
+	assert.Contains(t, desc, "区间最高: 200")
+}
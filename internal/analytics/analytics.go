@@ -0,0 +1,226 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/journal"
+	"github.com/songzhibin97/quantaflux/internal/models"
+)
+
+// 年化使用的交易日数量，加密市场全年无休
+const periodsPerYear = 365
+
+// Service 基于交易日志和权益快照计算绩效统计
+type Service struct {
+	equity  EquityStorage
+	journal journal.TradeJournal
+	feeRate float64
+	mode    string // 只统计该运行模式的快照和交易，为空时统计全部
+	account string // 只统计该账户的快照和交易，为空时统计所有账户的汇总
+
+	rates    Converter // 账户盈亏换算为 currency 计价，为空时各计价资产的金额直接相加
+	currency string
+}
+
+func NewService(equity EquityStorage, tradeJournal journal.TradeJournal, feeRate float64, mode string) *Service {
+	return &Service{
+		equity:  equity,
+		journal: tradeJournal,
+		feeRate: feeRate,
+		mode:    mode,
+	}
+}
+
+// ForAccount 返回只统计指定账户的绩效统计服务，account 为空时统计所有账户的汇总
+func (s *Service) ForAccount(account string) *Service {
+	scoped := *s
+	scoped.account = account
+	return &scoped
+}
+
+// WithValuation 返回将账户盈亏按当前汇率换算为 currency 计价的副本
+func (s *Service) WithValuation(rates Converter, currency string) *Service {
+	scoped := *s
+	scoped.rates = rates
+	scoped.currency = currency
+	return &scoped
+}
+
+// Report 计算指定时间范围内的绩效统计
+func (s *Service) Report(ctx context.Context, start, end time.Time) (*PerformanceReport, error) {
+	snapshots, err := s.EquityCurve(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(snapshots) == 0 {
+		return nil, fmt.Errorf("no equity snapshots between %s and %s", start.Format(time.RFC3339), end.Format(time.RFC3339))
+	}
+
+	trades, err := s.trades(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &PerformanceReport{
+		Start:       start,
+		End:         end,
+		StartEquity: snapshots[0].Equity,
+		EndEquity:   snapshots[len(snapshots)-1].Equity,
+		TradeCount:  len(trades),
+	}
+
+	if report.StartEquity > 0 {
+		report.TotalReturn = report.EndEquity/report.StartEquity - 1
+	}
+
+	report.DailyReturns = dailyReturns(snapshots)
+	report.Sharpe = sharpeRatio(report.DailyReturns)
+	report.Sortino = sortinoRatio(report.DailyReturns)
+	report.MaxDrawdown = maxDrawdown(snapshots)
+
+	var equitySum, exposureSum float64
+	for _, snapshot := range snapshots {
+		equitySum += snapshot.Equity
+		if snapshot.Equity > 0 {
+			exposureSum += snapshot.Exposure / snapshot.Equity
+		}
+	}
+	avgEquity := equitySum / float64(len(snapshots))
+	report.AvgExposure = exposureSum / float64(len(snapshots))
+
+	for _, trade := range trades {
+		report.TradedVolume += trade.Order.ExecutedAmount() * trade.Order.ExecutedPrice()
+	}
+	report.EstimatedFees = report.TradedVolume * s.feeRate
+
+	if avgEquity > 0 {
+		report.Turnover = report.TradedVolume / avgEquity
+		report.FeeDrag = report.EstimatedFees / avgEquity
+	}
+
+	return report, nil
+}
+
+// EquityCurve 返回指定时间范围内属于统计运行模式和账户的权益快照，按时间升序
+func (s *Service) EquityCurve(ctx context.Context, start, end time.Time) ([]models.EquitySnapshot, error) {
+	snapshots, err := s.equity.GetEquitySnapshots(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load equity snapshots: %w", err)
+	}
+
+	result := make([]models.EquitySnapshot, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		if matchesMode(s.mode, snapshot.Mode) && snapshot.Account == s.account {
+			result = append(result, snapshot)
+		}
+	}
+	return result, nil
+}
+
+// trades 返回指定时间范围内属于统计运行模式和账户的交易
+func (s *Service) trades(ctx context.Context, start, end time.Time) ([]journal.Entry, error) {
+	all, err := s.journal.ListTradesInRange(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load trades: %w", err)
+	}
+
+	var result []journal.Entry
+	for _, trade := range all {
+		if matchesMode(s.mode, trade.Mode) && (s.account == "" || trade.Order.Account == s.account) {
+			result = append(result, trade)
+		}
+	}
+	return result, nil
+}
+
+// dailyReturns 取每日最后一个快照计算日收益率
+func dailyReturns(snapshots []models.EquitySnapshot) []float64 {
+	var closes []float64
+	var lastDay string
+	for _, snapshot := range snapshots {
+		day := snapshot.Timestamp.UTC().Format(time.DateOnly)
+		if day == lastDay {
+			closes[len(closes)-1] = snapshot.Equity
+			continue
+		}
+		closes = append(closes, snapshot.Equity)
+		lastDay = day
+	}
+
+	returns := make([]float64, 0, len(closes))
+	for i := 1; i < len(closes); i++ {
+		if closes[i-1] <= 0 {
+			continue
+		}
+		returns = append(returns, closes[i]/closes[i-1]-1)
+	}
+	return returns
+}
+
+// sharpeRatio 年化夏普比率（无风险利率按0计算）
+func sharpeRatio(returns []float64) float64 {
+	if len(returns) < 2 {
+		return 0
+	}
+
+	mean, std := meanStd(returns)
+	if std == 0 {
+		return 0
+	}
+	return mean / std * math.Sqrt(periodsPerYear)
+}
+
+// sortinoRatio 年化索提诺比率，仅以下行波动作为分母
+func sortinoRatio(returns []float64) float64 {
+	if len(returns) < 2 {
+		return 0
+	}
+
+	mean, _ := meanStd(returns)
+
+	var downside float64
+	for _, r := range returns {
+		if r < 0 {
+			downside += r * r
+		}
+	}
+	downsideDev := math.Sqrt(downside / float64(len(returns)))
+	if downsideDev == 0 {
+		return 0
+	}
+	return mean / downsideDev * math.Sqrt(periodsPerYear)
+}
+
+// maxDrawdown 最大回撤，返回正数比例
+func maxDrawdown(snapshots []models.EquitySnapshot) float64 {
+	var peak, drawdown float64
+	for _, snapshot := range snapshots {
+		if snapshot.Equity > peak {
+			peak = snapshot.Equity
+		}
+		if peak > 0 {
+			drawdown = math.Max(drawdown, (peak-snapshot.Equity)/peak)
+		}
+	}
+	return drawdown
+}
+
+func meanStd(values []float64) (float64, float64) {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+
+	var variance float64
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	variance /= float64(len(values) - 1)
+
+	return mean, math.Sqrt(variance)
+}
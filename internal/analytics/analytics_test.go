@@ -0,0 +1,369 @@
+package analytics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/fx"
+	"github.com/songzhibin97/quantaflux/internal/journal"
+	"github.com/songzhibin97/quantaflux/internal/models"
+	"github.com/songzhibin97/quantaflux/internal/risk"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeEquityStorage struct {
+	snapshots []models.EquitySnapshot
+}
+
+func (f *fakeEquityStorage) SaveEquitySnapshot(ctx context.Context, snapshot *models.EquitySnapshot) error {
+	f.snapshots = append(f.snapshots, *snapshot)
+	return nil
+}
+
+func (f *fakeEquityStorage) GetEquitySnapshots(ctx context.Context, start, end time.Time) ([]models.EquitySnapshot, error) {
+	return f.snapshots, nil
+}
+
+type fakeJournal struct {
+	entries []journal.Entry
+}
+
+func (f *fakeJournal) RecordTrade(ctx context.Context, entry *journal.Entry) error {
+	f.entries = append(f.entries, *entry)
+	return nil
+}
+
+func (f *fakeJournal) ListTrades(ctx context.Context, symbol string, limit int) ([]journal.Entry, error) {
+	return f.entries, nil
+}
+
+func (f *fakeJournal) ListTradesInRange(ctx context.Context, start, end time.Time) ([]journal.Entry, error) {
+	return f.entries, nil
+}
+
+func (f *fakeJournal) GetTrade(ctx context.Context, account, symbol, orderID string) (*journal.Entry, error) {
+	return nil, nil
+}
+
+func (f *fakeJournal) ListOpenTrades(ctx context.Context) ([]journal.Entry, error) {
+	return nil, nil
+}
+
+func (f *fakeJournal) UpdateOrderStatus(ctx context.Context, account, symbol, orderID, status string) error {
+	return nil
+}
+
+func (f *fakeJournal) UpdateOrderFill(ctx context.Context, order trading.Order) error {
+	return nil
+}
+
+func snapshotsFromEquity(start time.Time, equity ...float64) []models.EquitySnapshot {
+	result := make([]models.EquitySnapshot, len(equity))
+	for i, e := range equity {
+		result[i] = models.EquitySnapshot{
+			Equity:    e,
+			Exposure:  e / 2,
+			Timestamp: start.Add(time.Duration(i) * 24 * time.Hour),
+		}
+	}
+	return result
+}
+
+func TestDailyReturns(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	snapshots := []models.EquitySnapshot{
+		{Equity: 100, Timestamp: start},
+		{Equity: 105, Timestamp: start.Add(12 * time.Hour)},
+		{Equity: 110, Timestamp: start.Add(24 * time.Hour)},
+		{Equity: 99, Timestamp: start.Add(48 * time.Hour)},
+	}
+
+	returns := dailyReturns(snapshots)
+	require.Len(t, returns, 2)
+	assert.InDelta(t, 110.0/105-1, returns[0], 1e-9)
+	assert.InDelta(t, 99.0/110-1, returns[1], 1e-9)
+}
+
+func TestMaxDrawdown(t *testing.T) {
+	snapshots := snapshotsFromEquity(time.Now(), 100, 120, 90, 130, 117)
+	assert.InDelta(t, 0.25, maxDrawdown(snapshots), 1e-9)
+}
+
+func TestRatios(t *testing.T) {
+	assert.Zero(t, sharpeRatio([]float64{0.01}))
+	assert.Zero(t, sharpeRatio([]float64{0.01, 0.01}))
+	assert.Zero(t, sortinoRatio([]float64{0.01, 0.02}))
+
+	returns := []float64{0.02, -0.01, 0.03, -0.02}
+	assert.Greater(t, sharpeRatio(returns), 0.0)
+	assert.Greater(t, sortinoRatio(returns), sharpeRatio(returns))
+}
+
+func TestService_Report(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	snapshots := snapshotsFromEquity(start, 1000, 1010, 1005, 1020)
+	// 其他运行模式的快照和交易不参与统计
+	snapshots = append(snapshots, models.EquitySnapshot{Equity: 5000, Mode: "shadow", Timestamp: start.Add(time.Hour)})
+	equity := &fakeEquityStorage{snapshots: snapshots}
+	trades := &fakeJournal{entries: []journal.Entry{
+		{Order: trading.Order{Symbol: "BTCUSDT", Amount: 0.01, Price: 50000, Status: "FILLED"}},
+		// 成交额按实际成交数量计算
+		{Order: trading.Order{Symbol: "BTCUSDT", Amount: 0.02, Price: 51000, FilledAmount: 0.01, Status: "PARTIALLY_FILLED"}},
+		{Order: trading.Order{Symbol: "BTCUSDT", Amount: 0.01, Price: 52000, Status: "NEW"}},
+		{Mode: "shadow", Order: trading.Order{Symbol: "BTCUSDT", Amount: 1, Price: 50000, Status: "FILLED"}},
+	}}
+
+	service := NewService(equity, trades, 0.001, "live")
+	report, err := service.Report(context.Background(), start, start.Add(72*time.Hour))
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, report.TradeCount)
+	assert.InDelta(t, 0.02, report.TotalReturn, 1e-9)
+	assert.InDelta(t, 1010, report.TradedVolume, 1e-9)
+	assert.InDelta(t, 1.01, report.EstimatedFees, 1e-9)
+	assert.InDelta(t, 0.5, report.AvgExposure, 1e-9)
+	assert.InDelta(t, 1010/1008.75, report.Turnover, 1e-9)
+	assert.Len(t, report.DailyReturns, 3)
+
+	_, err = NewService(&fakeEquityStorage{}, trades, 0, "").Report(context.Background(), start, start)
+	assert.Error(t, err)
+}
+
+func TestService_ForAccount(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	equity := &fakeEquityStorage{snapshots: []models.EquitySnapshot{
+		{Equity: 3000, Timestamp: start},
+		{Equity: 1000, Account: "trend", Timestamp: start},
+		{Equity: 2000, Account: "grid", Timestamp: start},
+		{Equity: 3300, Timestamp: start.Add(24 * time.Hour)},
+		{Equity: 1100, Account: "trend", Timestamp: start.Add(24 * time.Hour)},
+		{Equity: 2200, Account: "grid", Timestamp: start.Add(24 * time.Hour)},
+	}}
+	trades := &fakeJournal{entries: []journal.Entry{
+		{Order: trading.Order{Account: "trend", Symbol: "BTCUSDT", Amount: 0.01, Price: 50000, Status: "FILLED"}},
+		{Order: trading.Order{Account: "grid", Symbol: "ETHUSDT", Amount: 1, Price: 3000, Status: "FILLED"}},
+	}}
+	service := NewService(equity, trades, 0, "")
+
+	// 未指定账户时只统计汇总快照和全部交易
+	curve, err := service.EquityCurve(context.Background(), start, start.Add(24*time.Hour))
+	require.NoError(t, err)
+	assert.Len(t, curve, 2)
+	report, err := service.Report(context.Background(), start, start.Add(24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.TradeCount)
+
+	report, err = service.ForAccount("trend").Report(context.Background(), start, start.Add(24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.TradeCount)
+	assert.InDelta(t, 1000, report.StartEquity, 1e-9)
+	assert.InDelta(t, 500, report.TradedVolume, 1e-9)
+
+	_, err = service.ForAccount("arbitrage").Report(context.Background(), start, start.Add(24*time.Hour))
+	assert.Error(t, err)
+}
+
+func TestAccountPnL(t *testing.T) {
+	filled := func(account, side string, amount, price float64) journal.Entry {
+		return journal.Entry{Order: trading.Order{
+			Account: account, Symbol: "BTCUSDT", Side: side, Amount: amount, Price: price, Status: "FILLED",
+		}}
+	}
+	trades := []journal.Entry{
+		filled("trend", "buy", 2, 100),
+		filled("trend", "sell", 1, 120),
+		filled("scalp", "buy", 1, 110),
+		{Order: trading.Order{Account: "scalp", Symbol: "BTCUSDT", Side: "buy", Amount: 5, Price: 100, Status: "CANCELED"}},
+	}
+
+	result := accountPnL(trades, map[string]float64{"BTCUSDT": 130}, 0.001)
+	require.Len(t, result, 2)
+
+	scalp, trend := result[0], result[1]
+	assert.Equal(t, "scalp", scalp.Account)
+	assert.Equal(t, 1, scalp.TradeCount)
+	assert.InDelta(t, 130-110-0.11, scalp.PnL, 1e-9)
+
+	assert.Equal(t, "trend", trend.Account)
+	assert.Equal(t, 2, trend.TradeCount)
+	assert.InDelta(t, 1.0, trend.Positions["BTCUSDT"], 1e-9)
+	assert.InDelta(t, 0.32, trend.Fees, 1e-9)
+	assert.InDelta(t, 120-200-0.32+130, trend.PnL, 1e-9)
+
+	// 只统计当前运行模式的交易
+	shadow := filled("trend", "buy", 10, 100)
+	shadow.Mode = "shadow"
+	service := NewService(&fakeEquityStorage{}, &fakeJournal{entries: append(trades, shadow)}, 0.001, "live")
+	result, err := service.AccountPnL(context.Background(), time.Time{}, time.Now(), map[string]float64{"BTCUSDT": 130})
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, 2, result[1].TradeCount)
+}
+
+func TestAccountPnL_Valuation(t *testing.T) {
+	trades := []journal.Entry{
+		{Order: trading.Order{Account: "main", Symbol: "BTCUSDT", Side: "buy", Amount: 1, Price: 100, Status: "FILLED"}},
+		{Order: trading.Order{Account: "main", Symbol: "ETHBTC", Side: "buy", Amount: 10, Price: 0.05, Status: "FILLED"}},
+		{Order: trading.Order{Account: "main", Symbol: "ETHBTC", Side: "sell", Amount: 4, Price: 0.06, Status: "FILLED"}},
+	}
+	rates := fx.NewConverter()
+	rates.Update("BTCUSDT", 200)
+
+	// 不同计价资产的成交额按当前汇率换算为 USDT 后再汇总
+	service := NewService(&fakeEquityStorage{}, &fakeJournal{entries: trades}, 0, "").WithValuation(rates, "USDT")
+	result, err := service.AccountPnL(context.Background(), time.Time{}, time.Now(), map[string]float64{"BTCUSDT": 200, "ETHBTC": 0.07, "BNBUSDT": 300})
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+
+	pnl := result[0]
+	assert.Equal(t, "USDT", pnl.Currency)
+	assert.InDelta(t, 100+10*0.05*200, pnl.BuyValue, 1e-9)
+	assert.InDelta(t, 4*0.06*200, pnl.SellValue, 1e-9)
+	assert.InDelta(t, 200+6*0.07*200, pnl.PositionValue, 1e-9)
+
+	// 缺少汇率时报错，不直接相加
+	service = NewService(&fakeEquityStorage{}, &fakeJournal{entries: trades}, 0, "").WithValuation(fx.NewConverter(), "USDT")
+	_, err = service.AccountPnL(context.Background(), time.Time{}, time.Now(), nil)
+	assert.ErrorIs(t, err, fx.ErrNoRate)
+}
+
+func TestAccountPnL_PartialFill(t *testing.T) {
+	trades := []journal.Entry{
+		// 限价买单成交一半后撤单
+		{Order: trading.Order{Account: "trend", Symbol: "BTCUSDT", Side: "buy", Amount: 2, Price: 100, FilledAmount: 1, FilledPrice: 99, Status: "CANCELED"}},
+		// 挂单中的卖单已部分成交
+		{Order: trading.Order{Account: "trend", Symbol: "BTCUSDT", Side: "sell", Amount: 1, Price: 120, FilledAmount: 0.25, FilledPrice: 120, Status: "PARTIALLY_FILLED"}},
+		{Order: trading.Order{Account: "trend", Symbol: "BTCUSDT", Side: "sell", Amount: 1, Price: 130, Status: "NEW"}},
+	}
+
+	result := accountPnL(trades, map[string]float64{"BTCUSDT": 110}, 0)
+	require.Len(t, result, 1)
+
+	pnl := result[0]
+	assert.Equal(t, 2, pnl.TradeCount)
+	assert.InDelta(t, 0.75, pnl.Positions["BTCUSDT"], 1e-9)
+	assert.InDelta(t, 99, pnl.BuyValue, 1e-9)
+	assert.InDelta(t, 30, pnl.SellValue, 1e-9)
+	assert.InDelta(t, 30-99+0.75*110, pnl.PnL, 1e-9)
+}
+
+func TestExecutionStats(t *testing.T) {
+	// price 为订单价格（市价单为预测价格），submitted 为提交给交易所的委托价格
+	filled := func(side, orderType string, decision, price, submitted, fill float64) journal.Entry {
+		return journal.Entry{
+			MarketData: models.MarketData{Symbol: "BTCUSDT", Price: decision},
+			Order: trading.Order{
+				Symbol: "BTCUSDT", Side: side, OrderType: orderType, Amount: 2,
+				Price: price, SubmittedPrice: submitted, FilledPrice: fill, Status: "FILLED",
+			},
+		}
+	}
+	trades := []journal.Entry{
+		filled("buy", "market", 100, 105, 0, 100.5),   // 买贵 50bps
+		filled("sell", "market", 100, 95, 0, 99.8),    // 卖便宜 20bps
+		filled("buy", "limit", 100, 101, 101, 100.99), // 限价单按委托价附近成交
+		{Order: trading.Order{Symbol: "BTCUSDT", Side: "sell", OrderType: "market", Amount: 1, Price: 90, Status: "FILLED"}},
+		{MarketData: models.MarketData{Price: 100}, Order: trading.Order{Symbol: "BTCUSDT", Side: "buy", OrderType: "market", Amount: 1, FilledPrice: 150, Status: "CANCELED"}},
+	}
+
+	result := executionStats(trades)
+	require.Len(t, result, 2)
+
+	limit, market := result[0], result[1]
+	assert.Equal(t, "limit", limit.OrderType)
+	assert.Equal(t, 1, limit.Orders)
+	assert.InDelta(t, 99, limit.AvgSlippageBps, 1e-6)
+	assert.InDelta(t, -0.01/101*10000, limit.AvgSubmitDiffBps, 1e-6)
+
+	assert.Equal(t, "market", market.OrderType)
+	assert.Equal(t, 2, market.Orders)
+	assert.InDelta(t, 35, market.AvgSlippageBps, 1e-6)
+	assert.InDelta(t, 50, market.MaxSlippageBps, 1e-6)
+	assert.InDelta(t, 0, market.AvgSubmitDiffBps, 1e-9)
+	assert.InDelta(t, 2*0.5+2*0.2, market.SlippageCost, 1e-9)
+
+	// 只统计当前运行模式的订单
+	shadow := filled("buy", "market", 100, 105, 0, 110)
+	shadow.Mode = "shadow"
+	service := NewService(&fakeEquityStorage{}, &fakeJournal{entries: append(trades, shadow)}, 0, "live")
+	result, err := service.ExecutionQuality(context.Background(), time.Time{}, time.Now())
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, 2, result[1].Orders)
+}
+
+type seriesHistory struct {
+	data []models.MarketData
+}
+
+func (f *seriesHistory) GetHistoricalData(ctx context.Context, symbol string, start, end time.Time) ([]models.MarketData, error) {
+	var result []models.MarketData
+	for _, d := range f.data {
+		if d.Symbol == symbol && !d.Timestamp.Before(start) && !d.Timestamp.After(end) {
+			result = append(result, d)
+		}
+	}
+	return result, nil
+}
+
+func TestService_CostAnalysis(t *testing.T) {
+	now := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	filled := func(symbol, orderType string, submitted, fill float64) journal.Entry {
+		return journal.Entry{
+			MarketData: models.MarketData{Symbol: symbol, Price: 100, Timestamp: now},
+			Order: trading.Order{
+				Symbol: symbol, Side: "buy", OrderType: orderType, Amount: 1,
+				Price: 101, SubmittedPrice: submitted, FilledPrice: fill, Status: "FILLED",
+			},
+			CreatedAt: now,
+		}
+	}
+	trades := []journal.Entry{
+		filled("BTCUSDT", "market", 0, 100.1),   // 之后行情跌破决策价格，挂单也会成交
+		filled("ETHUSDT", "market", 0, 100.1),   // 之后行情没有回到决策价格
+		filled("BTCUSDT", "limit", 101, 100.05), // 下单时已达到市价，吃单成交
+		filled("BTCUSDT", "limit", 101, 101),    // 按委托价成交，挂单成交
+		{Order: trading.Order{Symbol: "BTCUSDT", Side: "buy", OrderType: "limit", Amount: 1, Price: 99, Status: "CANCELED"}},
+	}
+	history := &seriesHistory{data: []models.MarketData{
+		{Symbol: "BTCUSDT", Price: 100, Timestamp: now},
+		{Symbol: "BTCUSDT", Price: 99.9, Timestamp: now.Add(5 * time.Minute)},
+		{Symbol: "ETHUSDT", Price: 100.2, Timestamp: now.Add(5 * time.Minute)},
+		{Symbol: "ETHUSDT", Price: 99, Timestamp: now.Add(time.Hour)},
+	}}
+
+	service := NewService(&fakeEquityStorage{}, &fakeJournal{entries: trades}, 0, "")
+	analysis, err := service.CostAnalysis(context.Background(), time.Time{}, now.Add(time.Hour), CostOptions{
+		Costs:          risk.CostModel{MakerFeeRate: 0.0002, TakerFeeRate: 0.001},
+		History:        history,
+		Window:         15 * time.Minute,
+		PriceTolerance: 0.001,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, analysis.Breakdown, 3)
+	maker := analysis.Breakdown[0]
+	assert.Equal(t, "limit", maker.OrderType)
+	assert.Equal(t, LiquidityMaker, maker.Liquidity)
+	assert.InDelta(t, 101*0.0002, maker.Fees, 1e-9)
+	assert.InDelta(t, 1, maker.SlippageCost, 1e-9)
+	assert.InDelta(t, (101*0.0002+1)/101*10000, maker.AvgCostBps, 1e-9)
+	assert.Equal(t, LiquidityTaker, analysis.Breakdown[1].Liquidity)
+	assert.Equal(t, "market", analysis.Breakdown[2].OrderType)
+	assert.Equal(t, 2, analysis.Breakdown[2].Orders)
+
+	assert.Equal(t, 3, analysis.TakerOrders)
+	assert.Equal(t, 2, analysis.MakerEligible)
+	assert.Equal(t, 1, analysis.UnfilledLimits)
+	assert.InDelta(t, 100.1*0.0008+0.1+100.05*0.0008+0.05, analysis.PotentialSavings, 1e-9)
+	assert.InDelta(t, 4, analysis.MakerRoundTripBps, 1e-9)
+	assert.InDelta(t, 20+0.25/300.25*10000, analysis.TakerRoundTripBps, 1e-9)
+
+	// 多数吃单成交可以改为挂单，价格容差高于挂单的开平仓成本
+	require.Len(t, analysis.Recommendations, 1)
+	assert.Contains(t, analysis.Recommendations[0], "2 of 3 taker fills")
+}
@@ -0,0 +1,196 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/journal"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+)
+
+// 吃单成交中改为挂单也会成交的比例达到该值时，建议改用限价单
+const makerEligibleRatio = 0.5
+
+// CostAnalysis 将指定时间范围内已成交订单的手续费和滑点归因到订单类型和成交方式，
+// 根据吃单成交后的历史行情判断按决策价格挂单能否成交，给出订单类型和价格容差的调整建议
+func (s *Service) CostAnalysis(ctx context.Context, start, end time.Time, options CostOptions) (*CostAnalysis, error) {
+	trades, err := s.trades(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	analysis := &CostAnalysis{
+		Start:             start,
+		End:               end,
+		Window:            options.Window.String(),
+		PriceToleranceBps: options.PriceTolerance * 10000,
+		MakerRoundTripBps: 2 * options.Costs.MakerFeeRate * 10000,
+	}
+
+	type liquidityKey struct {
+		orderType string
+		liquidity string
+	}
+	byKey := make(map[liquidityKey]*LiquidityStats)
+	var takerVolume, takerSlippage float64
+
+	for _, trade := range trades {
+		order := trade.Order
+		amount := order.ExecutedAmount()
+		if amount <= 0 {
+			if order.OrderType == "limit" && order.Status != "" && !trading.IsOpenStatus(order.Status) {
+				analysis.UnfilledLimits++
+			}
+			continue
+		}
+		direction := sideDirection(order.Side)
+		fill := order.ExecutedPrice()
+		if direction == 0 || fill <= 0 {
+			continue
+		}
+
+		liquidity := liquidityOf(order)
+		feeRate := options.Costs.MakerFeeRate
+		if liquidity == LiquidityTaker {
+			feeRate = options.Costs.TakerFeeRate
+		}
+		volume := amount * fill
+		// 没有决策价格的订单（如风控紧急平仓）不计滑点
+		decision := trade.MarketData.Price
+		var slippage float64
+		if decision > 0 {
+			slippage = direction * (fill - decision) * amount
+		}
+
+		key := liquidityKey{orderType: order.OrderType, liquidity: liquidity}
+		stats, ok := byKey[key]
+		if !ok {
+			stats = &LiquidityStats{OrderType: order.OrderType, Liquidity: liquidity}
+			byKey[key] = stats
+		}
+		stats.Orders++
+		stats.Volume += volume
+		stats.Fees += volume * feeRate
+		stats.SlippageCost += slippage
+
+		if liquidity != LiquidityTaker {
+			continue
+		}
+		analysis.TakerOrders++
+		takerVolume += volume
+		takerSlippage += slippage
+		if decision <= 0 || options.History == nil {
+			continue
+		}
+		eligible, err := wouldFill(ctx, options.History, options.Window, trade, direction)
+		if err != nil {
+			return nil, err
+		}
+		if eligible {
+			analysis.MakerEligible++
+			analysis.PotentialSavings += volume*(options.Costs.TakerFeeRate-options.Costs.MakerFeeRate) + math.Max(slippage, 0)
+		}
+	}
+
+	// 没有吃单成交时按成本模型的预估滑点计算
+	slippageBps := options.Costs.SlippageBps
+	if takerVolume > 0 {
+		slippageBps = takerSlippage / takerVolume * 10000
+	}
+	analysis.TakerRoundTripBps = 2*options.Costs.TakerFeeRate*10000 + slippageBps
+
+	analysis.Breakdown = make([]LiquidityStats, 0, len(byKey))
+	for _, stats := range byKey {
+		if stats.Volume > 0 {
+			stats.AvgCostBps = (stats.Fees + stats.SlippageCost) / stats.Volume * 10000
+		}
+		analysis.Breakdown = append(analysis.Breakdown, *stats)
+	}
+	sort.Slice(analysis.Breakdown, func(i, j int) bool {
+		if analysis.Breakdown[i].OrderType != analysis.Breakdown[j].OrderType {
+			return analysis.Breakdown[i].OrderType < analysis.Breakdown[j].OrderType
+		}
+		return analysis.Breakdown[i].Liquidity < analysis.Breakdown[j].Liquidity
+	})
+
+	analysis.recommend()
+	return analysis, nil
+}
+
+// recommend 根据成本归因给出订单类型和 trading_config.price_tolerance 的调整建议：
+// 预测涨跌幅至少要覆盖一次开平仓的成本，吃单成交多数可以改为挂单时按挂单成本计算
+func (a *CostAnalysis) recommend() {
+	if a.TakerOrders == 0 {
+		return
+	}
+
+	target := a.TakerRoundTripBps
+	eligible := float64(a.MakerEligible)/float64(a.TakerOrders) >= makerEligibleRatio
+	if eligible {
+		target = a.MakerRoundTripBps
+		a.Recommendations = append(a.Recommendations, fmt.Sprintf(
+			"%d of %d taker fills (%.0f%%) would also have filled as maker orders at the decision price within %s; posting limit orders at the decision price could have saved %.2f",
+			a.MakerEligible, a.TakerOrders, float64(a.MakerEligible)/float64(a.TakerOrders)*100, a.Window, a.PotentialSavings))
+	}
+
+	switch {
+	case a.PriceToleranceBps < target:
+		a.Recommendations = append(a.Recommendations, fmt.Sprintf(
+			"trading_config.price_tolerance (%.1f bps) is below the round-trip execution cost (%.1f bps); raise it to at least %.4f so predicted moves cover costs",
+			a.PriceToleranceBps, target, target/10000))
+	case eligible && a.PriceToleranceBps > a.TakerRoundTripBps:
+		a.Recommendations = append(a.Recommendations, fmt.Sprintf(
+			"with maker execution the round-trip cost drops to %.1f bps; trading_config.price_tolerance could be lowered to %.4f to act on smaller predicted moves",
+			target, target/10000))
+	}
+}
+
+// liquidityOf 判断订单的成交方式：按委托价成交的限价单视为挂单成交，
+// 市价单和以优于委托价的价格成交（下单时已达到市价）的限价单视为吃单成交
+func liquidityOf(order trading.Order) string {
+	if order.OrderType != "limit" {
+		return LiquidityTaker
+	}
+	limit := order.SubmittedPrice
+	if limit <= 0 {
+		limit = order.Price
+	}
+	if math.Abs(order.ExecutedPrice()-limit) <= limit*1e-9 {
+		return LiquidityMaker
+	}
+	return LiquidityTaker
+}
+
+// wouldFill 判断吃单成交后 window 内行情是否穿过决策价格，即按决策价格挂单也会成交
+func wouldFill(ctx context.Context, history PriceHistory, window time.Duration, trade journal.Entry, direction float64) (bool, error) {
+	from := trade.MarketData.Timestamp
+	if from.IsZero() {
+		from = trade.CreatedAt
+	}
+
+	data, err := history.GetHistoricalData(ctx, trade.Order.Symbol, from, from.Add(window))
+	if err != nil {
+		return false, fmt.Errorf("failed to load prices of %s: %w", trade.Order.Symbol, err)
+	}
+	decision := trade.MarketData.Price
+	for _, d := range data {
+		if d.Timestamp.After(from) && direction*(decision-d.Price) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// sideDirection 买入返回 1，卖出返回 -1，用于将价格差换算为对交易不利的成本
+func sideDirection(side string) float64 {
+	switch side {
+	case "buy":
+		return 1
+	case "sell":
+		return -1
+	}
+	return 0
+}
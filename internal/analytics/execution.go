@@ -0,0 +1,86 @@
+package analytics
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/journal"
+)
+
+// ExecutionQuality 统计指定时间范围内当前运行模式已成交订单的滑点，按交易对和订单类型分组
+func (s *Service) ExecutionQuality(ctx context.Context, start, end time.Time) ([]ExecutionStats, error) {
+	trades, err := s.trades(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return executionStats(trades), nil
+}
+
+type executionKey struct {
+	symbol    string
+	orderType string
+}
+
+func executionStats(trades []journal.Entry) []ExecutionStats {
+	byKey := make(map[executionKey]*ExecutionStats)
+	submitted := make(map[executionKey]int)
+
+	for _, trade := range trades {
+		order := trade.Order
+		// 没有决策价格的订单（如风控紧急平仓）不统计
+		decision := trade.MarketData.Price
+		fill := order.ExecutedPrice()
+		amount := order.ExecutedAmount()
+		if amount <= 0 || decision <= 0 || fill <= 0 {
+			continue
+		}
+
+		var direction float64
+		switch order.Side {
+		case "buy":
+			direction = 1
+		case "sell":
+			direction = -1
+		default:
+			continue
+		}
+
+		key := executionKey{symbol: order.Symbol, orderType: order.OrderType}
+		stats, ok := byKey[key]
+		if !ok {
+			stats = &ExecutionStats{Symbol: order.Symbol, OrderType: order.OrderType}
+			byKey[key] = stats
+		}
+
+		slippage := direction * (fill - decision) / decision * 10000
+		if stats.Orders == 0 || slippage > stats.MaxSlippageBps {
+			stats.MaxSlippageBps = slippage
+		}
+		stats.Orders++
+		stats.AvgSlippageBps += slippage
+		stats.SlippageCost += direction * (fill - decision) * amount
+
+		if order.SubmittedPrice > 0 {
+			stats.AvgSubmitDiffBps += direction * (fill - order.SubmittedPrice) / order.SubmittedPrice * 10000
+			submitted[key]++
+		}
+	}
+
+	result := make([]ExecutionStats, 0, len(byKey))
+	for key, stats := range byKey {
+		stats.AvgSlippageBps /= float64(stats.Orders)
+		if n := submitted[key]; n > 0 {
+			stats.AvgSubmitDiffBps /= float64(n)
+		}
+		result = append(result, *stats)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Symbol != result[j].Symbol {
+			return result[i].Symbol < result[j].Symbol
+		}
+		return result[i].OrderType < result[j].OrderType
+	})
+	return result
+}
@@ -0,0 +1,167 @@
+package analytics
+
+import (
+	"context"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/models"
+	"github.com/songzhibin97/quantaflux/internal/risk"
+)
+
+// EquityStorage provides access to stored equity snapshots
+type EquityStorage interface {
+	// SaveEquitySnapshot stores an equity snapshot
+	SaveEquitySnapshot(ctx context.Context, snapshot *models.EquitySnapshot) error
+
+	// GetEquitySnapshots retrieves equity snapshots in a time range
+	GetEquitySnapshots(ctx context.Context, start, end time.Time) ([]models.EquitySnapshot, error)
+}
+
+// PerformanceReport 绩效统计结果
+type PerformanceReport struct {
+	Start         time.Time `json:"start"`
+	End           time.Time `json:"end"`
+	StartEquity   float64   `json:"start_equity"`
+	EndEquity     float64   `json:"end_equity"`
+	TotalReturn   float64   `json:"total_return"`
+	DailyReturns  []float64 `json:"daily_returns"`
+	Sharpe        float64   `json:"sharpe"`
+	Sortino       float64   `json:"sortino"`
+	MaxDrawdown   float64   `json:"max_drawdown"`
+	AvgExposure   float64   `json:"avg_exposure"` // 平均持仓占权益比例
+	Turnover      float64   `json:"turnover"`     // 成交额 / 平均权益
+	FeeDrag       float64   `json:"fee_drag"`     // 手续费 / 平均权益
+	TradeCount    int       `json:"trade_count"`
+	TradedVolume  float64   `json:"traded_volume"`
+	EstimatedFees float64   `json:"estimated_fees"`
+}
+
+// AccountPnL 账户盈亏，按已成交订单和最新价格计算
+type AccountPnL struct {
+	Account       string             `json:"account"`
+	TradeCount    int                `json:"trade_count"`
+	BuyValue      float64            `json:"buy_value"`
+	SellValue     float64            `json:"sell_value"`
+	Fees          float64            `json:"fees"`
+	Positions     map[string]float64 `json:"positions"`          // 交易对 -> 净持仓数量
+	PositionValue float64            `json:"position_value"`     // 净持仓按最新价格计算的市值
+	PnL           float64            `json:"pnl"`                // 卖出额 - 买入额 - 手续费 + 持仓市值
+	Currency      string             `json:"currency,omitempty"` // 金额的计价资产，未设置估值资产时为空
+}
+
+// Converter 在资产之间换算金额，由 fx.Converter 实现
+type Converter interface {
+	Convert(amount float64, from, to string) (float64, error)
+}
+
+// ExecutionStats 按交易对和订单类型统计的成交质量，滑点以基点表示，正数表示对交易不利
+type ExecutionStats struct {
+	Symbol           string  `json:"symbol"`
+	OrderType        string  `json:"order_type"`
+	Orders           int     `json:"orders"`              // 有决策价格和成交价格的已成交订单数
+	AvgSlippageBps   float64 `json:"avg_slippage_bps"`    // 成交价相对决策时行情价格的平均滑点
+	MaxSlippageBps   float64 `json:"max_slippage_bps"`    // 最不利的单笔滑点
+	AvgSubmitDiffBps float64 `json:"avg_submit_diff_bps"` // 成交价相对委托价格的平均偏差，只统计有委托价格的限价单
+	SlippageCost     float64 `json:"slippage_cost"`       // 滑点造成的成本（计价资产）
+}
+
+// PriceHistory provides historical market prices
+type PriceHistory interface {
+	// GetHistoricalData retrieves market data of a symbol in a time range, oldest first
+	GetHistoricalData(ctx context.Context, symbol string, start, end time.Time) ([]models.MarketData, error)
+}
+
+// ReportStorage stores generated PnL reports for later retrieval
+type ReportStorage interface {
+	// SavePnLReport stores a generated report
+	SavePnLReport(ctx context.Context, report *PnLReport) error
+
+	// ListPnLReports retrieves the most recent reports, optionally filtered by period, newest first
+	ListPnLReports(ctx context.Context, period string, limit int) ([]PnLReport, error)
+}
+
+// 报告周期
+const (
+	PeriodDaily  = "daily"
+	PeriodWeekly = "weekly"
+)
+
+// PnLReport 周期盈亏报告
+type PnLReport struct {
+	ID            int64              `json:"id"`
+	Mode          string             `json:"mode"`   // 统计的运行模式，影子模式报告为模拟成交
+	Period        string             `json:"period"` // daily/weekly
+	Start         time.Time          `json:"start"`
+	End           time.Time          `json:"end"`
+	RealizedPnL   float64            `json:"realized_pnl"`   // 周期内卖出按平均成本计算的已实现盈亏
+	UnrealizedPnL float64            `json:"unrealized_pnl"` // 期末持仓按最新价格计算的浮动盈亏
+	Fees          float64            `json:"fees"`           // 周期内成交的估算手续费
+	NetPnL        float64            `json:"net_pnl"`        // 已实现 + 浮动 - 手续费
+	TradeCount    int                `json:"trade_count"`
+	TagPnL        map[string]float64 `json:"tag_pnl,omitempty"` // 品种池标签 -> 周期内的已实现盈亏
+	BestTrades    []TradePnL         `json:"best_trades"`
+	WorstTrades   []TradePnL         `json:"worst_trades"`
+	AIAccuracy    PredictionStats    `json:"ai_accuracy"`
+	CreatedAt     time.Time          `json:"created_at"`
+}
+
+// TradePnL 单笔平仓交易的已实现盈亏
+type TradePnL struct {
+	OrderID string    `json:"order_id"`
+	Account string    `json:"account"`
+	Symbol  string    `json:"symbol"`
+	Amount  float64   `json:"amount"`
+	Price   float64   `json:"price"`
+	PnL     float64   `json:"pnl"`
+	Time    time.Time `json:"time"`
+}
+
+// PredictionStats AI 价格预测准确度
+type PredictionStats struct {
+	Predictions     int     `json:"predictions"`        // 周期内的预测数量
+	Evaluated       int     `json:"evaluated"`          // 已到预测时间、可评估的数量
+	DirectionHits   int     `json:"direction_hits"`     // 涨跌方向预测正确的数量
+	HitRate         float64 `json:"hit_rate"`           // 方向命中率
+	MeanAbsPctError float64 `json:"mean_abs_pct_error"` // 预测价格的平均绝对百分比误差
+}
+
+// 成交方式
+const (
+	LiquidityMaker = "maker" // 挂单成交
+	LiquidityTaker = "taker" // 吃单成交
+)
+
+// CostOptions 交易成本归因的参数
+type CostOptions struct {
+	Costs          risk.CostModel // 按成交方式计算手续费的费率
+	History        PriceHistory   // 判断吃单成交改为挂单能否成交的历史行情
+	Window         time.Duration  // 吃单成交后等待行情回到决策价格的时长
+	PriceTolerance float64        // 当前的 trading_config.price_tolerance，用于给出阈值建议
+}
+
+// CostAnalysis 按订单类型和成交方式归因的交易成本，成本以基点表示时相对成交额
+type CostAnalysis struct {
+	Start             time.Time        `json:"start"`
+	End               time.Time        `json:"end"`
+	Window            string           `json:"window"`
+	Breakdown         []LiquidityStats `json:"breakdown"`
+	TakerOrders       int              `json:"taker_orders"`         // 吃单成交的订单数
+	MakerEligible     int              `json:"maker_eligible"`       // 吃单成交中，window 内行情穿过决策价格、按决策价格挂单也会成交的订单数
+	PotentialSavings  float64          `json:"potential_savings"`    // 这些订单改为挂单可节省的手续费和滑点
+	UnfilledLimits    int              `json:"unfilled_limits"`      // 没有任何成交就结束的限价单数
+	TakerRoundTripBps float64          `json:"taker_round_trip_bps"` // 按吃单成交开平仓一次的平均成本
+	MakerRoundTripBps float64          `json:"maker_round_trip_bps"` // 按挂单成交开平仓一次的成本
+	PriceToleranceBps float64          `json:"price_tolerance_bps"`
+	Recommendations   []string         `json:"recommendations,omitempty"`
+}
+
+// LiquidityStats 按订单类型和成交方式统计的成交额和成本
+type LiquidityStats struct {
+	OrderType    string  `json:"order_type"`
+	Liquidity    string  `json:"liquidity"` // maker 或 taker
+	Orders       int     `json:"orders"`
+	Volume       float64 `json:"volume"`        // 成交额（计价资产）
+	Fees         float64 `json:"fees"`          // 按成交方式的费率估算的手续费
+	SlippageCost float64 `json:"slippage_cost"` // 成交价相对决策时行情价格的成本
+	AvgCostBps   float64 `json:"avg_cost_bps"`  // (手续费 + 滑点) / 成交额
+}
@@ -0,0 +1,141 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/journal"
+	"github.com/songzhibin97/quantaflux/internal/money"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+
+	"github.com/shopspring/decimal"
+)
+
+// AccountPnL 按账户统计指定时间范围内当前运行模式已成交订单（包括部分成交）的盈亏，prices 为各交易对最新价格
+func (s *Service) AccountPnL(ctx context.Context, start, end time.Time, prices map[string]float64) ([]AccountPnL, error) {
+	trades, err := s.trades(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if s.rates == nil {
+		return accountPnL(trades, prices, s.feeRate), nil
+	}
+
+	trades, prices, err = s.valueTrades(trades, prices)
+	if err != nil {
+		return nil, err
+	}
+	result := accountPnL(trades, prices, s.feeRate)
+	for i := range result {
+		result[i].Currency = s.currency
+	}
+	return result, nil
+}
+
+// valueTrades 将成交价格和最新价格按当前汇率从各交易对的计价资产换算为估值资产，
+// 缺少汇率时返回错误，避免不同计价资产的金额直接相加
+func (s *Service) valueTrades(trades []journal.Entry, prices map[string]float64) ([]journal.Entry, map[string]float64, error) {
+	rates := make(map[string]float64)
+	rate := func(symbol string) (float64, error) {
+		if r, ok := rates[symbol]; ok {
+			return r, nil
+		}
+		_, quote, ok := trading.SplitSymbol(symbol)
+		if !ok {
+			return 0, fmt.Errorf("unknown quote asset of %s", symbol)
+		}
+		r, err := s.rates.Convert(1, quote, s.currency)
+		if err != nil {
+			return 0, fmt.Errorf("failed to value %s in %s: %w", symbol, s.currency, err)
+		}
+		rates[symbol] = r
+		return r, nil
+	}
+
+	valued := make([]journal.Entry, 0, len(trades))
+	for _, trade := range trades {
+		if trade.Order.ExecutedAmount() > 0 {
+			r, err := rate(trade.Order.Symbol)
+			if err != nil {
+				return nil, nil, err
+			}
+			trade.Order.Price *= r
+			trade.Order.FilledPrice *= r
+			trade.Order.SubmittedPrice *= r
+		}
+		valued = append(valued, trade)
+	}
+
+	valuedPrices := make(map[string]float64, len(prices))
+	for symbol, price := range prices {
+		// 没有成交的交易对不计入持仓市值，不需要汇率
+		if r, ok := rates[symbol]; ok {
+			valuedPrices[symbol] = price * r
+		}
+	}
+	return valued, valuedPrices, nil
+}
+
+// accountPnL 按十进制累加各账户的成交金额、手续费和持仓，结果再转换为 float64
+func accountPnL(trades []journal.Entry, prices map[string]float64, feeRate float64) []AccountPnL {
+	type totals struct {
+		buy, sell, fees decimal.Decimal
+		positions       map[string]decimal.Decimal
+	}
+	rate := money.FromFloat(feeRate)
+	byAccount := make(map[string]*AccountPnL)
+	sums := make(map[string]*totals)
+	for _, trade := range trades {
+		order := trade.Order
+		amount := order.ExecutedAmount()
+		if amount <= 0 {
+			continue
+		}
+
+		pnl, ok := byAccount[order.Account]
+		if !ok {
+			pnl = &AccountPnL{Account: order.Account, Positions: make(map[string]float64)}
+			byAccount[order.Account] = pnl
+			sums[order.Account] = &totals{positions: make(map[string]decimal.Decimal)}
+		}
+		sum := sums[order.Account]
+
+		filled := money.FromFloat(amount)
+		value := filled.Mul(money.FromFloat(order.ExecutedPrice()))
+		switch order.Side {
+		case "buy":
+			sum.buy = sum.buy.Add(value)
+			sum.positions[order.Symbol] = sum.positions[order.Symbol].Add(filled)
+		case "sell":
+			sum.sell = sum.sell.Add(value)
+			sum.positions[order.Symbol] = sum.positions[order.Symbol].Sub(filled)
+		default:
+			continue
+		}
+		sum.fees = sum.fees.Add(value.Mul(rate))
+		pnl.TradeCount++
+	}
+
+	result := make([]AccountPnL, 0, len(byAccount))
+	for account, pnl := range byAccount {
+		sum := sums[account]
+		positionValue := decimal.Zero
+		for symbol, amount := range sum.positions {
+			pnl.Positions[symbol] = money.Float(amount)
+			positionValue = positionValue.Add(amount.Mul(money.FromFloat(prices[symbol])))
+		}
+		pnl.BuyValue = money.Float(sum.buy)
+		pnl.SellValue = money.Float(sum.sell)
+		pnl.Fees = money.Float(sum.fees)
+		pnl.PositionValue = money.Float(positionValue)
+		pnl.PnL = money.Float(sum.sell.Sub(sum.buy).Sub(sum.fees).Add(positionValue))
+		result = append(result, *pnl)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Account < result[j].Account
+	})
+	return result
+}
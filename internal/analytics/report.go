@@ -0,0 +1,288 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/journal"
+)
+
+const (
+	// 报告中列出的最佳/最差交易数量
+	reportTopTrades = 3
+	// 预测未指定时间范围时的评估时长
+	defaultPredictionHorizon = time.Hour
+	// 查找期末价格时向前回溯的时长
+	markPriceLookback = 24 * time.Hour
+	// 实盘运行模式，与 configs.ModeLive 一致
+	liveMode = "live"
+)
+
+// ReportGenerator 基于交易日志和历史行情生成周期盈亏报告
+type ReportGenerator struct {
+	journal journal.TradeJournal
+	history PriceHistory
+	feeRate float64
+	mode    string    // 只统计该运行模式的交易，为空时统计全部
+	tags    TagSource // 交易对标签，为空时不按标签汇总
+}
+
+// TagSource 交易对的分类标签，*universe.Service 实现了该接口
+type TagSource interface {
+	Tags(symbol string) []string
+}
+
+func NewReportGenerator(tradeJournal journal.TradeJournal, history PriceHistory, feeRate float64, mode string) *ReportGenerator {
+	return &ReportGenerator{
+		journal: tradeJournal,
+		history: history,
+		feeRate: feeRate,
+		mode:    mode,
+	}
+}
+
+// WithTags 返回按交易对标签汇总已实现盈亏的副本
+func (g *ReportGenerator) WithTags(tags TagSource) *ReportGenerator {
+	scoped := *g
+	scoped.tags = tags
+	return &scoped
+}
+
+// Generate 生成 [start, end] 的盈亏报告；成本基于截至 end 的全部成交计算
+func (g *ReportGenerator) Generate(ctx context.Context, period string, start, end time.Time) (*PnLReport, error) {
+	all, err := g.journal.ListTradesInRange(ctx, time.Time{}, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load trades: %w", err)
+	}
+
+	var trades []journal.Entry
+	for _, trade := range all {
+		if g.includes(trade) {
+			trades = append(trades, trade)
+		}
+	}
+
+	report := &PnLReport{
+		Mode:      g.mode,
+		Period:    period,
+		Start:     start,
+		End:       end,
+		CreatedAt: time.Now(),
+	}
+
+	positions, closed := g.replayTrades(trades, start, report)
+
+	for key, pos := range positions {
+		if pos.amount <= 0 {
+			continue
+		}
+		price, err := g.markPrice(ctx, key.symbol, end)
+		if err != nil {
+			return nil, err
+		}
+		if price > 0 {
+			report.UnrealizedPnL += pos.amount * (price - pos.avgCost)
+		}
+	}
+	report.NetPnL = report.RealizedPnL + report.UnrealizedPnL - report.Fees
+
+	sort.Slice(closed, func(i, j int) bool { return closed[i].PnL > closed[j].PnL })
+	for i := 0; i < len(closed) && i < reportTopTrades; i++ {
+		if closed[i].PnL > 0 {
+			report.BestTrades = append(report.BestTrades, closed[i])
+		}
+	}
+	for i := len(closed) - 1; i >= 0 && len(closed)-1-i < reportTopTrades; i-- {
+		if closed[i].PnL < 0 {
+			report.WorstTrades = append(report.WorstTrades, closed[i])
+		}
+	}
+
+	stats, err := g.predictionStats(ctx, trades, start, end)
+	if err != nil {
+		return nil, err
+	}
+	report.AIAccuracy = stats
+
+	return report, nil
+}
+
+// includes 判断交易是否属于统计的运行模式
+func (g *ReportGenerator) includes(trade journal.Entry) bool {
+	return matchesMode(g.mode, trade.Mode)
+}
+
+// matchesMode 判断记录的运行模式是否符合过滤条件，filter 为空时全部符合，未标记模式的历史记录视为实盘
+func matchesMode(filter, mode string) bool {
+	if filter == "" {
+		return true
+	}
+	if mode == "" {
+		mode = liveMode
+	}
+	return mode == filter
+}
+
+type positionKey struct {
+	account string
+	symbol  string
+}
+
+type costBasis struct {
+	amount  float64
+	avgCost float64
+}
+
+// replayTrades 按平均成本法回放成交，统计周期内的已实现盈亏和手续费，返回期末持仓和周期内的平仓交易
+func (g *ReportGenerator) replayTrades(trades []journal.Entry, start time.Time, report *PnLReport) (map[positionKey]*costBasis, []TradePnL) {
+	positions := make(map[positionKey]*costBasis)
+	var closed []TradePnL
+
+	for _, trade := range trades {
+		order := trade.Order
+		amount := order.ExecutedAmount()
+		if amount <= 0 {
+			continue
+		}
+		price := order.ExecutedPrice()
+
+		key := positionKey{account: order.Account, symbol: order.Symbol}
+		pos, ok := positions[key]
+		if !ok {
+			pos = &costBasis{}
+			positions[key] = pos
+		}
+
+		inPeriod := !trade.CreatedAt.Before(start)
+		if inPeriod {
+			report.TradeCount++
+			report.Fees += amount * price * g.feeRate
+		}
+
+		switch order.Side {
+		case "buy":
+			total := pos.amount + amount
+			if total > 0 {
+				pos.avgCost = (pos.amount*pos.avgCost + amount*price) / total
+			}
+			pos.amount = total
+		case "sell":
+			// 超出已知持仓的部分没有成本，不计入盈亏
+			matched := math.Min(amount, pos.amount)
+			pnl := matched * (price - pos.avgCost)
+			pos.amount -= matched
+			if inPeriod && matched > 0 {
+				report.RealizedPnL += pnl
+				g.addTagPnL(report, order.Symbol, pnl)
+				closed = append(closed, TradePnL{
+					OrderID: order.OrderID,
+					Account: order.Account,
+					Symbol:  order.Symbol,
+					Amount:  matched,
+					Price:   price,
+					PnL:     pnl,
+					Time:    trade.CreatedAt,
+				})
+			}
+		}
+	}
+	return positions, closed
+}
+
+// addTagPnL 将已实现盈亏计入交易对的每个标签，带多个标签的交易对在各标签中重复计入
+func (g *ReportGenerator) addTagPnL(report *PnLReport, symbol string, pnl float64) {
+	if g.tags == nil {
+		return
+	}
+	for _, tag := range g.tags.Tags(symbol) {
+		if report.TagPnL == nil {
+			report.TagPnL = make(map[string]float64)
+		}
+		report.TagPnL[tag] += pnl
+	}
+}
+
+// markPrice 返回 end 之前最近的行情价格，没有行情时返回 0
+func (g *ReportGenerator) markPrice(ctx context.Context, symbol string, end time.Time) (float64, error) {
+	data, err := g.history.GetHistoricalData(ctx, symbol, end.Add(-markPriceLookback), end)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load mark price of %s: %w", symbol, err)
+	}
+	if len(data) == 0 {
+		return 0, nil
+	}
+	return data[len(data)-1].Price, nil
+}
+
+// predictionStats 将周期内的预测与预测时间范围到期后的实际价格比较
+func (g *ReportGenerator) predictionStats(ctx context.Context, trades []journal.Entry, start, end time.Time) (PredictionStats, error) {
+	var stats PredictionStats
+	var errSum float64
+
+	for _, trade := range trades {
+		prediction := trade.Prediction
+		if prediction == nil || trade.CreatedAt.Before(start) || trade.MarketData.Price <= 0 {
+			continue
+		}
+		stats.Predictions++
+
+		horizon, err := time.ParseDuration(prediction.TimeFrame)
+		if err != nil || horizon <= 0 {
+			horizon = defaultPredictionHorizon
+		}
+		target := trade.CreatedAt.Add(horizon)
+		if target.After(end) {
+			continue
+		}
+
+		data, err := g.history.GetHistoricalData(ctx, trade.Order.Symbol, target, target.Add(horizon))
+		if err != nil {
+			return stats, fmt.Errorf("failed to load outcome price of %s: %w", trade.Order.Symbol, err)
+		}
+		if len(data) == 0 || data[0].Price <= 0 {
+			continue
+		}
+
+		base, actual := trade.MarketData.Price, data[0].Price
+		stats.Evaluated++
+		if (prediction.PredictedPrice-base)*(actual-base) > 0 {
+			stats.DirectionHits++
+		}
+		errSum += math.Abs(prediction.PredictedPrice-actual) / actual
+	}
+
+	if stats.Evaluated > 0 {
+		stats.HitRate = float64(stats.DirectionHits) / float64(stats.Evaluated)
+		stats.MeanAbsPctError = errSum / float64(stats.Evaluated)
+	}
+	return stats, nil
+}
+
+// Summary 返回适合推送的报告文本
+func (r *PnLReport) Summary() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s ~ %s\n", r.Start.Format(time.DateTime), r.End.Format(time.DateTime))
+	fmt.Fprintf(&b, "Net PnL: %.2f (realized %.2f, unrealized %.2f, fees %.2f)\n", r.NetPnL, r.RealizedPnL, r.UnrealizedPnL, r.Fees)
+	fmt.Fprintf(&b, "Trades: %d\n", r.TradeCount)
+	tags := make([]string, 0, len(r.TagPnL))
+	for tag := range r.TagPnL {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	for _, tag := range tags {
+		fmt.Fprintf(&b, "Tag %s: realized %.2f\n", tag, r.TagPnL[tag])
+	}
+	for _, t := range r.BestTrades {
+		fmt.Fprintf(&b, "Best: %s %s %.8g @ %.8g, pnl %.2f\n", t.Account, t.Symbol, t.Amount, t.Price, t.PnL)
+	}
+	for _, t := range r.WorstTrades {
+		fmt.Fprintf(&b, "Worst: %s %s %.8g @ %.8g, pnl %.2f\n", t.Account, t.Symbol, t.Amount, t.Price, t.PnL)
+	}
+	fmt.Fprintf(&b, "AI accuracy: %d/%d direction hits (%.0f%%), mean abs error %.2f%%",
+		r.AIAccuracy.DirectionHits, r.AIAccuracy.Evaluated, r.AIAccuracy.HitRate*100, r.AIAccuracy.MeanAbsPctError*100)
+	return b.String()
+}
@@ -0,0 +1,89 @@
+package analytics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/ai"
+	"github.com/songzhibin97/quantaflux/internal/journal"
+	"github.com/songzhibin97/quantaflux/internal/models"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePriceHistory struct {
+	price float64
+}
+
+func (f *fakePriceHistory) GetHistoricalData(ctx context.Context, symbol string, start, end time.Time) ([]models.MarketData, error) {
+	return []models.MarketData{{Symbol: symbol, Price: f.price, Timestamp: start}}, nil
+}
+
+func TestReportGenerator_Generate(t *testing.T) {
+	start := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+
+	entry := func(offset time.Duration, side string, amount, price float64, status string, prediction *ai.PricePrediction, current float64) journal.Entry {
+		return journal.Entry{
+			Order: trading.Order{
+				OrderID: side + offset.String(), Account: "default", Symbol: "BTCUSDT",
+				Side: side, Amount: amount, Price: price, Status: status,
+			},
+			Prediction: prediction,
+			MarketData: models.MarketData{Symbol: "BTCUSDT", Price: current},
+			CreatedAt:  start.Add(offset),
+		}
+	}
+	trades := []journal.Entry{
+		entry(-48*time.Hour, "buy", 2, 100, "FILLED", nil, 0),
+		entry(time.Hour, "sell", 1, 120, "FILLED", &ai.PricePrediction{PredictedPrice: 125, TimeFrame: "1h"}, 118),
+		entry(2*time.Hour, "buy", 1, 130, "FILLED", nil, 0),
+		entry(3*time.Hour, "sell", 1, 110, "FILLED", &ai.PricePrediction{PredictedPrice: 100, TimeFrame: "1h"}, 112),
+		entry(23*time.Hour+30*time.Minute, "buy", 5, 100, "CANCELED", &ai.PricePrediction{PredictedPrice: 130, TimeFrame: "1h"}, 120),
+	}
+
+	// 其他运行模式的交易不计入
+	shadow := entry(4*time.Hour, "buy", 1, 120, "FILLED", nil, 0)
+	shadow.Mode = "shadow"
+	trades = append(trades, shadow)
+
+	generator := NewReportGenerator(&fakeJournal{entries: trades}, &fakePriceHistory{price: 125}, 0.001, "live")
+	report, err := generator.Generate(context.Background(), PeriodDaily, start, end)
+	require.NoError(t, err)
+
+	assert.Equal(t, PeriodDaily, report.Period)
+	assert.Equal(t, 3, report.TradeCount)
+	assert.InDelta(t, 15, report.RealizedPnL, 1e-9)
+	assert.InDelta(t, 10, report.UnrealizedPnL, 1e-9)
+	assert.InDelta(t, 0.36, report.Fees, 1e-9)
+	assert.InDelta(t, 24.64, report.NetPnL, 1e-9)
+
+	require.Len(t, report.BestTrades, 1)
+	assert.InDelta(t, 20, report.BestTrades[0].PnL, 1e-9)
+	require.Len(t, report.WorstTrades, 1)
+	assert.InDelta(t, -5, report.WorstTrades[0].PnL, 1e-9)
+
+	assert.Equal(t, 3, report.AIAccuracy.Predictions)
+	assert.Equal(t, 2, report.AIAccuracy.Evaluated)
+	assert.Equal(t, 1, report.AIAccuracy.DirectionHits)
+	assert.InDelta(t, 0.5, report.AIAccuracy.HitRate, 1e-9)
+	assert.InDelta(t, 0.1, report.AIAccuracy.MeanAbsPctError, 1e-9)
+
+	assert.Contains(t, report.Summary(), "Net PnL: 24.64")
+	assert.Nil(t, report.TagPnL)
+
+	// 按交易对标签汇总已实现盈亏
+	report, err = generator.WithTags(stubTags{"BTCUSDT": {"L1", "major"}}).Generate(context.Background(), PeriodDaily, start, end)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"L1": 15, "major": 15}, report.TagPnL)
+	assert.Contains(t, report.Summary(), "Tag L1: realized 15.00")
+}
+
+type stubTags map[string][]string
+
+func (s stubTags) Tags(symbol string) []string {
+	return s[symbol]
+}
@@ -0,0 +1,20 @@
+package api
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"net/http"
+)
+
+//go:embed static
+var staticFiles embed.FS
+
+// dashboardHandler 提供内嵌的 Web 仪表盘静态资源
+func dashboardHandler() http.Handler {
+	sub, err := fs.Sub(staticFiles, "static")
+	if err != nil {
+		panic(fmt.Sprintf("invalid embedded dashboard: %v", err))
+	}
+	return http.FileServer(http.FS(sub))
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Event WebSocket 推送事件
+type Event struct {
+	Type      string      `json:"type"`
+	Data      interface{} `json:"data"`
+	Timestamp time.Time   `json:"timestamp"`
+}
+
+// 事件类型
+const (
+	EventMarketData = "market_data"
+	EventPrediction = "prediction"
+	EventRiskAlert  = "risk_alert"
+	EventTrade      = "trade"
+	EventOrderBook  = "order_book"
+	EventArbitrage  = "arbitrage"
+)
+
+const (
+	clientBufferSize = 64
+	writeTimeout     = 10 * time.Second
+)
+
+// Hub 管理 WebSocket 客户端并广播事件
+type Hub struct {
+	upgrader websocket.Upgrader
+	logger   Logger
+
+	mu      sync.RWMutex
+	clients map[*wsClient]struct{}
+}
+
+type wsClient struct {
+	conn *websocket.Conn
+	send chan Event
+}
+
+func NewHub(logger Logger) *Hub {
+	return &Hub{
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+		},
+		logger:  logger,
+		clients: make(map[*wsClient]struct{}),
+	}
+}
+
+// Publish 向所有客户端广播事件，慢客户端的事件会被丢弃
+func (h *Hub) Publish(eventType string, data interface{}) {
+	if h == nil {
+		return
+	}
+
+	event := Event{
+		Type:      eventType,
+		Data:      data,
+		Timestamp: time.Now(),
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for client := range h.clients {
+		select {
+		case client.send <- event:
+		default:
+			h.logger.Error("websocket client too slow, dropping event", "type", eventType)
+		}
+	}
+}
+
+// ServeHTTP upgrades the request to a WebSocket connection
+func (h *Hub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		h.logger.Error("failed to upgrade websocket", "error", err)
+		return
+	}
+
+	client := &wsClient{
+		conn: conn,
+		send: make(chan Event, clientBufferSize),
+	}
+
+	h.mu.Lock()
+	h.clients[client] = struct{}{}
+	h.mu.Unlock()
+
+	go h.writeLoop(client)
+	h.readLoop(client)
+}
+
+// readLoop 读取客户端消息以感知连接关闭
+func (h *Hub) readLoop(client *wsClient) {
+	defer h.remove(client)
+
+	for {
+		if _, _, err := client.conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+func (h *Hub) writeLoop(client *wsClient) {
+	defer client.conn.Close()
+
+	for event := range client.send {
+		_ = client.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if err := client.conn.WriteJSON(event); err != nil {
+			return
+		}
+	}
+}
+
+func (h *Hub) remove(client *wsClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.clients[client]; ok {
+		delete(h.clients, client)
+		close(client.send)
+	}
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/ai"
+	"github.com/songzhibin97/quantaflux/internal/params"
+	"github.com/songzhibin97/quantaflux/internal/risk"
+	"github.com/songzhibin97/quantaflux/internal/scheduler"
+	"github.com/songzhibin97/quantaflux/internal/state"
+	"github.com/songzhibin97/quantaflux/internal/tracing"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+)
+
+// ErrAccountNotFound 指定的交易账户不存在
+var ErrAccountNotFound = errors.New("account not found")
+
+// ErrPositionNotFound 账户在交易对上没有持仓
+var ErrPositionNotFound = errors.New("position not found")
+
+// ErrSnapshotUnavailable 未配置快照存储（如回测模式）
+var ErrSnapshotUnavailable = errors.New("snapshot store is not configured")
+
+// System 运行中量化系统对外暴露的控制接口
+type System interface {
+	// Positions returns current holdings of all traded symbols
+	Positions(ctx context.Context) ([]Position, error)
+
+	// RecentPredictions returns the most recent AI price predictions
+	RecentPredictions() []PredictionRecord
+
+	// RecentAlerts returns the most recent risk alerts
+	RecentAlerts() []risk.RiskAlert
+
+	// Pause stops placing new orders
+	Pause()
+
+	// Resume resumes placing new orders
+	Resume()
+
+	// Paused reports whether trading is paused
+	Paused() bool
+
+	// PauseSymbol stops placing new orders for a single symbol
+	PauseSymbol(symbol string)
+
+	// ResumeSymbol resumes placing new orders for a single symbol
+	ResumeSymbol(symbol string)
+
+	// PausedSymbols returns the symbols that are individually paused
+	PausedSymbols() []string
+
+	// Flatten closes all open positions with market orders
+	Flatten(ctx context.Context) error
+
+	// ClosePosition sells fraction of the position of the symbol with a market order, the primary account when account is empty
+	ClosePosition(ctx context.Context, account, symbol string, fraction float64) (*trading.Order, error)
+
+	// RiskState returns the risk state of an account, the primary account when account is empty
+	RiskState(ctx context.Context, account string) (*risk.RiskState, error)
+
+	// SetRiskParameters updates the risk limits of an account, all accounts when account is empty
+	SetRiskParameters(ctx context.Context, account string, params *risk.RiskParameters) error
+
+	// Jobs returns the status of scheduled jobs
+	Jobs() []scheduler.JobStatus
+
+	// Traces returns recently recorded tick traces
+	Traces() []tracing.Trace
+
+	// Accounts returns positions and risk state of every trading account
+	Accounts(ctx context.Context) ([]AccountSummary, error)
+
+	// Heartbeat records an operator heartbeat for the dead man's switch and returns its time
+	Heartbeat() time.Time
+
+	// Snapshot saves the full system state for a later restore
+	Snapshot(ctx context.Context, label string) (*state.Snapshot, error)
+}
+
+// Tuner 运行时调整阈值的参数注册表
+type Tuner interface {
+	// Params returns the current state of every tunable parameter
+	Params() []params.Param
+
+	// SetParam overrides the parameter with value, the actor is taken from ctx
+	SetParam(ctx context.Context, name string, value float64, reason string) (*params.Param, error)
+
+	// ResetParam drops the override and restores the value from the config file
+	ResetParam(ctx context.Context, name string, reason string) (*params.Param, error)
+
+	// ParamHistory returns up to limit changes of the parameter, all parameters when name is empty, newest first
+	ParamHistory(ctx context.Context, name string, limit int) ([]params.Change, error)
+}
+
+// Logger 日志接口
+type Logger interface {
+	Error(msg string, fields ...interface{})
+	Info(msg string, fields ...interface{})
+}
+
+// TradingStatus 下单开关状态
+type TradingStatus struct {
+	Paused        bool     `json:"paused"`
+	PausedSymbols []string `json:"paused_symbols"`
+}
+
+// Position 当前持仓
+type Position struct {
+	Account  string  `json:"account"`
+	Symbol   string  `json:"symbol"`
+	Asset    string  `json:"asset"`
+	Amount   float64 `json:"amount"`
+	Price    float64 `json:"price"`
+	Value    float64 `json:"value"`
+	Currency string  `json:"currency"` // 市值的计价资产，通常为 valuation_config.currency
+}
+
+// AccountSummary 交易账户概况
+type AccountSummary struct {
+	Name      string          `json:"name"`
+	Strategy  string          `json:"strategy"`
+	Symbols   []string        `json:"symbols"`
+	Positions []Position      `json:"positions"`
+	Risk      *risk.RiskState `json:"risk"`
+}
+
+// PredictionRecord AI预测记录
+type PredictionRecord struct {
+	Prediction   ai.PricePrediction `json:"prediction"`
+	CurrentPrice float64            `json:"current_price"`
+	Timestamp    time.Time          `json:"timestamp"`
+}
@@ -0,0 +1,830 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/analytics"
+	"github.com/songzhibin97/quantaflux/internal/audit"
+	"github.com/songzhibin97/quantaflux/internal/auth"
+	"github.com/songzhibin97/quantaflux/internal/data"
+	"github.com/songzhibin97/quantaflux/internal/health"
+	"github.com/songzhibin97/quantaflux/internal/journal"
+	"github.com/songzhibin97/quantaflux/internal/params"
+	"github.com/songzhibin97/quantaflux/internal/precision"
+	"github.com/songzhibin97/quantaflux/internal/risk"
+	"github.com/songzhibin97/quantaflux/internal/universe"
+)
+
+const (
+	defaultOrderLimit  = 50
+	defaultReportLimit = 30
+)
+
+// ReasonHeader 审计记录中操作原因的请求头
+const ReasonHeader = "X-Audit-Reason"
+
+// Server 提供系统控制与查询的 HTTP REST API
+type Server struct {
+	addr      string
+	auth      *auth.Authenticator // 认证、角色检查和限流
+	system    System
+	journal   journal.TradeJournal
+	analytics *analytics.Service
+	reports   analytics.ReportStorage
+	hub       *Hub
+	health    *health.Checker
+	audit     *audit.Log
+	precision *precision.Formatter // 仪表盘展示使用的交易对精度
+	universe  *universe.Service    // 品种池，为空时品种池接口不可用
+	tuner     Tuner                // 参数注册表，为空时参数接口不可用
+	logger    Logger
+	mux       *http.ServeMux
+}
+
+func NewServer(
+	addr string,
+	tokens map[string]string,
+	system System,
+	tradeJournal journal.TradeJournal,
+	analyticsService *analytics.Service,
+	reports analytics.ReportStorage,
+	hub *Hub,
+	checker *health.Checker,
+	auditLog *audit.Log,
+	logger Logger,
+) *Server {
+	s := &Server{
+		addr:      addr,
+		auth:      auth.New(auth.Options{Keys: ControlKeys(tokens)}),
+		system:    system,
+		journal:   tradeJournal,
+		analytics: analyticsService,
+		reports:   reports,
+		hub:       hub,
+		health:    checker,
+		audit:     auditLog,
+		precision: precision.NewFormatter(nil),
+		logger:    logger,
+		mux:       http.NewServeMux(),
+	}
+	s.routes()
+	return s
+}
+
+// SetPrecision 设置交易所的交易对精度，未设置时按默认精度展示
+func (s *Server) SetPrecision(formatter *precision.Formatter) {
+	s.precision = formatter
+}
+
+// SetUniverse 设置品种池，用于查询和修改交易对的标签
+func (s *Server) SetUniverse(service *universe.Service) {
+	s.universe = service
+}
+
+// SetTuner 设置参数注册表，用于在运行时查询和调整阈值
+func (s *Server) SetTuner(tuner Tuner) {
+	s.tuner = tuner
+}
+
+// SetAuthenticator 替换认证配置，未设置时 tokens 中的令牌均为控制角色，查询类接口不需认证
+func (s *Server) SetAuthenticator(authenticator *auth.Authenticator) {
+	s.auth = authenticator
+}
+
+// ControlKeys 将发起方名称 -> 访问令牌转换为控制角色的 API 密钥
+func ControlKeys(tokens map[string]string) []auth.Key {
+	keys := make([]auth.Key, 0, len(tokens))
+	for name, token := range tokens {
+		keys = append(keys, auth.Key{Name: name, Secret: token, Role: auth.RoleControl})
+	}
+	return keys
+}
+
+// Handle registers an additional handler on the server
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// ServeHTTP implements http.Handler，查询类接口和 WebSocket 按只读角色检查认证和限流，
+// 健康检查、指标和仪表盘静态页面不检查
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := audit.WithActor(r.Context(), audit.ActorAPI)
+	if r.Method == http.MethodGet && (strings.HasPrefix(r.URL.Path, "/api/") || r.URL.Path == "/ws") {
+		principal, err := s.auth.Check(requestToken(r), auth.RoleRead, clientAddr(r))
+		if err != nil {
+			s.writeAuthError(w, err)
+			return
+		}
+		if principal.Name != "" {
+			ctx = audit.WithActor(ctx, principal.Name)
+		}
+	}
+	s.mux.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// authorize 修改类接口需携带控制角色的 Authorization: Bearer <token>，审计记录的发起方为密钥名称或 JWT 的 sub；
+// 未配置任何凭证时拒绝所有修改
+func (s *Server) authorize(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal, err := s.auth.Check(requestToken(r), auth.RoleControl, clientAddr(r))
+		if err != nil {
+			s.writeAuthError(w, err)
+			return
+		}
+		next(w, r.WithContext(audit.WithActor(r.Context(), principal.Name)))
+	}
+}
+
+// requestToken 读取 Bearer 令牌，查询类请求也可用 access_token 查询参数（浏览器的 WebSocket 无法设置请求头）
+func requestToken(r *http.Request) string {
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return token
+	}
+	if r.Method == http.MethodGet {
+		return r.URL.Query().Get("access_token")
+	}
+	return ""
+}
+
+// clientAddr 返回客户端 IP，用于未认证请求的限流
+func clientAddr(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func (s *Server) writeAuthError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, auth.ErrRateLimited):
+		w.Header().Set("Retry-After", "1")
+		s.writeError(w, http.StatusTooManyRequests, err)
+	case errors.Is(err, auth.ErrPermissionDenied), errors.Is(err, auth.ErrNotConfigured):
+		s.writeError(w, http.StatusForbidden, err)
+	default:
+		s.writeError(w, http.StatusUnauthorized, err)
+	}
+}
+
+// Start 启动 HTTP 服务，ctx 取消时优雅关闭
+func (s *Server) Start(ctx context.Context) error {
+	srv := &http.Server{
+		Addr:              s.addr,
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		s.logger.Info("api server listening", "addr", s.addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return srv.Shutdown(shutdownCtx)
+}
+
+func (s *Server) routes() {
+	s.mux.HandleFunc("GET /api/v1/positions", s.handlePositions)
+	s.mux.HandleFunc("POST /api/v1/positions/{symbol}/close", s.authorize(s.handleClosePosition))
+	s.mux.HandleFunc("GET /api/v1/accounts", s.handleAccounts)
+	s.mux.HandleFunc("GET /api/v1/orders", s.handleOrders)
+	s.mux.HandleFunc("GET /api/v1/orders/{id}", s.handleOrder)
+	s.mux.HandleFunc("GET /api/v1/predictions", s.handlePredictions)
+	s.mux.HandleFunc("GET /api/v1/risk", s.handleRiskState)
+	s.mux.HandleFunc("PUT /api/v1/risk/parameters", s.authorize(s.handleSetRiskParameters))
+	s.mux.HandleFunc("GET /api/v1/trading/status", s.handleTradingStatus)
+	s.mux.HandleFunc("POST /api/v1/trading/pause", s.authorize(s.handlePause))
+	s.mux.HandleFunc("POST /api/v1/trading/resume", s.authorize(s.handleResume))
+	s.mux.HandleFunc("POST /api/v1/trading/flatten", s.authorize(s.handleFlatten))
+	s.mux.HandleFunc("POST /api/v1/trading/symbols/{symbol}/pause", s.authorize(s.handlePauseSymbol))
+	s.mux.HandleFunc("POST /api/v1/trading/symbols/{symbol}/resume", s.authorize(s.handleResumeSymbol))
+	s.mux.HandleFunc("POST /api/v1/heartbeat", s.authorize(s.handleHeartbeat))
+	s.mux.HandleFunc("POST /api/v1/snapshots", s.authorize(s.handleSnapshot))
+	s.mux.HandleFunc("GET /api/v1/analytics/performance", s.handlePerformance)
+	s.mux.HandleFunc("GET /api/v1/analytics/accounts", s.handleAccountPnL)
+	s.mux.HandleFunc("GET /api/v1/analytics/execution", s.handleExecutionQuality)
+	s.mux.HandleFunc("GET /api/v1/reports", s.handleReports)
+	s.mux.HandleFunc("GET /api/v1/equity", s.handleEquity)
+	s.mux.HandleFunc("GET /api/v1/alerts", s.handleAlerts)
+	s.mux.HandleFunc("GET /api/v1/jobs", s.handleJobs)
+	s.mux.HandleFunc("GET /api/v1/traces", s.handleTraces)
+	s.mux.HandleFunc("GET /api/v1/precision", s.handlePrecision)
+	s.mux.HandleFunc("GET /api/v1/universe", s.handleUniverse)
+	s.mux.HandleFunc("GET /api/v1/universe/{symbol}", s.handleUniverseSymbol)
+	s.mux.HandleFunc("PUT /api/v1/universe/{symbol}", s.authorize(s.handleUpdateUniverseSymbol))
+	s.mux.HandleFunc("GET /api/v1/params", s.handleParams)
+	s.mux.HandleFunc("GET /api/v1/params/history", s.handleParamHistory)
+	s.mux.HandleFunc("PUT /api/v1/params/{name}", s.authorize(s.handleSetParam))
+	s.mux.HandleFunc("DELETE /api/v1/params/{name}", s.authorize(s.handleResetParam))
+
+	// 健康检查，供 Kubernetes 探针和告警使用
+	if s.health != nil {
+		s.mux.HandleFunc("GET /healthz", s.handleHealthz)
+		s.mux.HandleFunc("GET /readyz", s.handleReadyz)
+	}
+
+	// Web 仪表盘
+	if s.hub != nil {
+		s.mux.Handle("GET /ws", s.hub)
+	}
+	s.mux.Handle("GET /", dashboardHandler())
+}
+
+func (s *Server) handlePositions(w http.ResponseWriter, r *http.Request) {
+	positions, err := s.system.Positions(r.Context())
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.writeJSON(w, http.StatusOK, positions)
+}
+
+func (s *Server) handleAccounts(w http.ResponseWriter, r *http.Request) {
+	accounts, err := s.system.Accounts(r.Context())
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.writeJSON(w, http.StatusOK, accounts)
+}
+
+func (s *Server) handleOrders(w http.ResponseWriter, r *http.Request) {
+	limit := defaultOrderLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			s.writeError(w, http.StatusBadRequest, fmt.Errorf("invalid limit: %s", v))
+			return
+		}
+		limit = n
+	}
+
+	entries, err := s.journal.ListTrades(r.Context(), r.URL.Query().Get("symbol"), limit)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.writeJSON(w, http.StatusOK, entries)
+}
+
+func (s *Server) handleOrder(w http.ResponseWriter, r *http.Request) {
+	// 订单号只在交易对内唯一，可通过 account 和 symbol 查询参数区分
+	query := r.URL.Query()
+	entry, err := s.journal.GetTrade(r.Context(), query.Get("account"), query.Get("symbol"), r.PathValue("id"))
+	if errors.Is(err, data.ErrNotFound) {
+		s.writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.writeJSON(w, http.StatusOK, entry)
+}
+
+func (s *Server) handlePredictions(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, http.StatusOK, s.system.RecentPredictions())
+}
+
+func (s *Server) handleRiskState(w http.ResponseWriter, r *http.Request) {
+	state, err := s.system.RiskState(r.Context(), r.URL.Query().Get("account"))
+	if errors.Is(err, ErrAccountNotFound) {
+		s.writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	state.TradingPaused = s.system.Paused()
+	state.PausedSymbols = s.system.PausedSymbols()
+	s.writeJSON(w, http.StatusOK, state)
+}
+
+func (s *Server) handleSetRiskParameters(w http.ResponseWriter, r *http.Request) {
+	var params risk.RiskParameters
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		s.writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	// account 为空时修改所有账户的风险限额
+	account := r.URL.Query().Get("account")
+	if err := s.system.SetRiskParameters(r.Context(), account, &params); err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, risk.ErrInvalidParameters):
+			status = http.StatusBadRequest
+		case errors.Is(err, ErrAccountNotFound):
+			status = http.StatusNotFound
+		}
+		s.writeError(w, status, err)
+		return
+	}
+
+	s.logger.Info("risk parameters updated via api", "account", account, "params", params)
+	s.audit.Record(r.Context(), audit.ActionSetRiskParameters, account, auditReason(r), map[string]any{"params": params})
+	s.handleRiskState(w, r)
+}
+
+func (s *Server) handleTradingStatus(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, http.StatusOK, TradingStatus{
+		Paused:        s.system.Paused(),
+		PausedSymbols: s.system.PausedSymbols(),
+	})
+}
+
+func (s *Server) handlePause(w http.ResponseWriter, r *http.Request) {
+	s.system.Pause()
+	s.logger.Info("trading paused via api")
+	s.audit.Record(r.Context(), audit.ActionPause, "", auditReason(r), nil)
+	s.handleTradingStatus(w, r)
+}
+
+func (s *Server) handleResume(w http.ResponseWriter, r *http.Request) {
+	s.system.Resume()
+	s.logger.Info("trading resumed via api")
+	s.audit.Record(r.Context(), audit.ActionResume, "", auditReason(r), nil)
+	s.handleTradingStatus(w, r)
+}
+
+func (s *Server) handlePauseSymbol(w http.ResponseWriter, r *http.Request) {
+	symbol := r.PathValue("symbol")
+	s.system.PauseSymbol(symbol)
+	s.logger.Info("symbol paused via api", "symbol", symbol)
+	s.audit.Record(r.Context(), audit.ActionPauseSymbol, symbol, auditReason(r), nil)
+	s.handleTradingStatus(w, r)
+}
+
+func (s *Server) handleResumeSymbol(w http.ResponseWriter, r *http.Request) {
+	symbol := r.PathValue("symbol")
+	s.system.ResumeSymbol(symbol)
+	s.logger.Info("symbol resumed via api", "symbol", symbol)
+	s.audit.Record(r.Context(), audit.ActionResumeSymbol, symbol, auditReason(r), nil)
+	s.handleTradingStatus(w, r)
+}
+
+func (s *Server) handleFlatten(w http.ResponseWriter, r *http.Request) {
+	// 先暂停下单，避免清仓过程中或清仓后策略重新开仓，需手动恢复
+	s.system.Pause()
+	s.audit.Record(r.Context(), audit.ActionPause, "", "flatten", nil)
+
+	s.logger.Info("emergency flatten triggered via api")
+	err := s.system.Flatten(r.Context())
+	details := map[string]any{}
+	if err != nil {
+		details["error"] = err.Error()
+	}
+	s.audit.Record(r.Context(), audit.ActionFlatten, "", auditReason(r), details)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.writeJSON(w, http.StatusOK, map[string]string{"status": "flattened"})
+}
+
+// handleClosePosition 按市价卖出交易对持仓的 fraction 比例（默认全部），account 为空时为主账户
+func (s *Server) handleClosePosition(w http.ResponseWriter, r *http.Request) {
+	symbol := r.PathValue("symbol")
+	account := r.URL.Query().Get("account")
+	fraction := 1.0
+	if value := r.URL.Query().Get("fraction"); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed <= 0 || parsed > 1 {
+			s.writeError(w, http.StatusBadRequest, fmt.Errorf("invalid fraction %q, must be greater than 0 and at most 1", value))
+			return
+		}
+		fraction = parsed
+	}
+
+	order, err := s.system.ClosePosition(r.Context(), account, symbol, fraction)
+	details := map[string]any{"account": account, "fraction": fraction}
+	if err != nil {
+		details["error"] = err.Error()
+	}
+	s.audit.Record(r.Context(), audit.ActionReducePosition, symbol, auditReason(r), details)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, ErrAccountNotFound) || errors.Is(err, ErrPositionNotFound) {
+			status = http.StatusNotFound
+		}
+		s.writeError(w, status, err)
+		return
+	}
+	s.logger.Info("position closed via api", "account", account, "symbol", symbol, "fraction", fraction, "amount", order.Amount)
+	s.writeJSON(w, http.StatusOK, order)
+}
+
+// handleHeartbeat 记录操作人心跳，心跳较频繁，不写入审计日志
+func (s *Server) handleHeartbeat(w http.ResponseWriter, r *http.Request) {
+	at := s.system.Heartbeat()
+	s.writeJSON(w, http.StatusOK, map[string]time.Time{"last_heartbeat": at})
+}
+
+// handleSnapshot 保存系统完整状态快照，label 用于标识快照，如部署版本
+func (s *Server) handleSnapshot(w http.ResponseWriter, r *http.Request) {
+	label := r.URL.Query().Get("label")
+	snapshot, err := s.system.Snapshot(r.Context(), label)
+	if errors.Is(err, ErrSnapshotUnavailable) {
+		s.writeError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	s.logger.Info("system snapshot saved via api", "id", snapshot.ID, "label", label)
+	s.audit.Record(r.Context(), audit.ActionSnapshot, "", auditReason(r), map[string]any{"id": snapshot.ID, "label": label})
+	s.writeJSON(w, http.StatusOK, snapshot)
+}
+
+func (s *Server) handleJobs(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, http.StatusOK, s.system.Jobs())
+}
+
+func (s *Server) handleTraces(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, http.StatusOK, s.system.Traces())
+}
+
+// handlePrecision 返回 symbols 参数（逗号分隔）中各交易对的价格和数量精度
+func (s *Server) handlePrecision(w http.ResponseWriter, r *http.Request) {
+	rules := make(map[string]precision.Rules)
+	for _, symbol := range strings.Split(r.URL.Query().Get("symbols"), ",") {
+		if symbol = strings.ToUpper(strings.TrimSpace(symbol)); symbol != "" {
+			rules[symbol] = s.precision.Rules(symbol)
+		}
+	}
+	s.writeJSON(w, http.StatusOK, rules)
+}
+
+// handleUniverse 返回品种池中的交易对，可按 tag、quote 和 status 筛选
+func (s *Server) handleUniverse(w http.ResponseWriter, r *http.Request) {
+	if s.universe == nil {
+		s.writeError(w, http.StatusNotImplemented, fmt.Errorf("symbol universe not available"))
+		return
+	}
+	query := r.URL.Query()
+	symbols := s.universe.List(universe.Filter{
+		Tag:        query.Get("tag"),
+		QuoteAsset: strings.ToUpper(query.Get("quote")),
+		Status:     strings.ToUpper(query.Get("status")),
+	})
+	if symbols == nil {
+		symbols = []universe.Symbol{}
+	}
+	s.writeJSON(w, http.StatusOK, symbols)
+}
+
+func (s *Server) handleUniverseSymbol(w http.ResponseWriter, r *http.Request) {
+	if s.universe == nil {
+		s.writeError(w, http.StatusNotImplemented, fmt.Errorf("symbol universe not available"))
+		return
+	}
+	symbol := strings.ToUpper(r.PathValue("symbol"))
+	info, ok := s.universe.Get(symbol)
+	if !ok {
+		s.writeError(w, http.StatusNotFound, fmt.Errorf("symbol %s not in universe", symbol))
+		return
+	}
+	s.writeJSON(w, http.StatusOK, info)
+}
+
+// handleUpdateUniverseSymbol 修改交易对的标签或上线日期，交易对不在品种池中时作为内部交易对加入
+func (s *Server) handleUpdateUniverseSymbol(w http.ResponseWriter, r *http.Request) {
+	if s.universe == nil {
+		s.writeError(w, http.StatusNotImplemented, fmt.Errorf("symbol universe not available"))
+		return
+	}
+	var update universe.Update
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		s.writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	symbol := strings.ToUpper(r.PathValue("symbol"))
+	info, err := s.universe.Update(r.Context(), symbol, update, time.Now())
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, universe.ErrInvalidSymbol):
+			status = http.StatusBadRequest
+		case errors.Is(err, universe.ErrNotLoaded):
+			status = http.StatusServiceUnavailable
+		}
+		s.writeError(w, status, err)
+		return
+	}
+
+	s.logger.Info("symbol universe updated via api", "symbol", symbol, "tags", info.Tags)
+	s.audit.Record(r.Context(), audit.ActionUpdateSymbol, symbol, auditReason(r), map[string]any{"tags": info.Tags, "listed_at": info.ListedAt})
+	s.writeJSON(w, http.StatusOK, info)
+}
+
+// handleParams 返回所有可在运行时调整的阈值及其当前值
+func (s *Server) handleParams(w http.ResponseWriter, r *http.Request) {
+	if s.tuner == nil {
+		s.writeError(w, http.StatusNotImplemented, fmt.Errorf("parameter registry not available"))
+		return
+	}
+	s.writeJSON(w, http.StatusOK, s.tuner.Params())
+}
+
+// handleParamHistory 返回参数的修改记录，未指定 name 时返回所有参数的
+func (s *Server) handleParamHistory(w http.ResponseWriter, r *http.Request) {
+	if s.tuner == nil {
+		s.writeError(w, http.StatusNotImplemented, fmt.Errorf("parameter registry not available"))
+		return
+	}
+
+	limit := defaultOrderLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			s.writeError(w, http.StatusBadRequest, fmt.Errorf("invalid limit: %s", v))
+			return
+		}
+		limit = n
+	}
+
+	changes, err := s.tuner.ParamHistory(r.Context(), r.URL.Query().Get("name"), limit)
+	if err != nil {
+		s.writeParamError(w, err)
+		return
+	}
+	if changes == nil {
+		changes = []params.Change{}
+	}
+	s.writeJSON(w, http.StatusOK, changes)
+}
+
+// handleSetParam 修改参数的值，立即生效并持久化
+func (s *Server) handleSetParam(w http.ResponseWriter, r *http.Request) {
+	if s.tuner == nil {
+		s.writeError(w, http.StatusNotImplemented, fmt.Errorf("parameter registry not available"))
+		return
+	}
+	var req struct {
+		Value *float64 `json:"value"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+	if req.Value == nil {
+		s.writeError(w, http.StatusBadRequest, fmt.Errorf("value is required"))
+		return
+	}
+
+	name := r.PathValue("name")
+	reason := auditReason(r)
+	param, err := s.tuner.SetParam(r.Context(), name, *req.Value, reason)
+	if err != nil {
+		s.writeParamError(w, err)
+		return
+	}
+
+	s.logger.Info("parameter updated via api", "name", name, "value", param.Value)
+	s.audit.Record(r.Context(), audit.ActionSetParam, name, reason, map[string]any{"value": param.Value})
+	s.writeJSON(w, http.StatusOK, param)
+}
+
+// handleResetParam 删除参数的运行时修改，恢复配置文件中的值
+func (s *Server) handleResetParam(w http.ResponseWriter, r *http.Request) {
+	if s.tuner == nil {
+		s.writeError(w, http.StatusNotImplemented, fmt.Errorf("parameter registry not available"))
+		return
+	}
+
+	name := r.PathValue("name")
+	reason := auditReason(r)
+	param, err := s.tuner.ResetParam(r.Context(), name, reason)
+	if err != nil {
+		s.writeParamError(w, err)
+		return
+	}
+
+	s.logger.Info("parameter reset via api", "name", name, "value", param.Value)
+	s.audit.Record(r.Context(), audit.ActionResetParam, name, reason, map[string]any{"value": param.Value})
+	s.writeJSON(w, http.StatusOK, param)
+}
+
+func (s *Server) writeParamError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, params.ErrUnknownParam):
+		status = http.StatusNotFound
+	case errors.Is(err, params.ErrInvalidValue):
+		status = http.StatusBadRequest
+	case errors.Is(err, params.ErrNotLoaded):
+		status = http.StatusServiceUnavailable
+	}
+	s.writeError(w, status, err)
+}
+
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	s.writeHealth(w, s.health.Liveness(r.Context()))
+}
+
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	s.writeHealth(w, s.health.Readiness(r.Context()))
+}
+
+func (s *Server) writeHealth(w http.ResponseWriter, report *health.Report) {
+	status := http.StatusOK
+	if !report.Healthy() {
+		status = http.StatusServiceUnavailable
+	}
+	s.writeJSON(w, status, report)
+}
+
+func (s *Server) handlePerformance(w http.ResponseWriter, r *http.Request) {
+	if s.analytics == nil {
+		s.writeError(w, http.StatusNotImplemented, fmt.Errorf("analytics not available"))
+		return
+	}
+
+	start, end, err := parseTimeRange(r, 30*24*time.Hour)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	report, err := s.analyticsFor(r).Report(r.Context(), start, end)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.writeJSON(w, http.StatusOK, report)
+}
+
+func (s *Server) handleAccountPnL(w http.ResponseWriter, r *http.Request) {
+	if s.analytics == nil {
+		s.writeError(w, http.StatusNotImplemented, fmt.Errorf("analytics not available"))
+		return
+	}
+
+	start, end, err := parseTimeRange(r, 30*24*time.Hour)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// 持仓按最新价格估值
+	positions, err := s.system.Positions(r.Context())
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	prices := make(map[string]float64, len(positions))
+	for _, pos := range positions {
+		prices[pos.Symbol] = pos.Price
+	}
+
+	pnl, err := s.analytics.AccountPnL(r.Context(), start, end, prices)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.writeJSON(w, http.StatusOK, pnl)
+}
+
+func (s *Server) handleExecutionQuality(w http.ResponseWriter, r *http.Request) {
+	if s.analytics == nil {
+		s.writeError(w, http.StatusNotImplemented, fmt.Errorf("analytics not available"))
+		return
+	}
+
+	start, end, err := parseTimeRange(r, 30*24*time.Hour)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	stats, err := s.analyticsFor(r).ExecutionQuality(r.Context(), start, end)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.writeJSON(w, http.StatusOK, stats)
+}
+
+// handleEquity 返回当前运行模式的权益曲线，供仪表盘绘图，account 参数指定账户
+func (s *Server) handleEquity(w http.ResponseWriter, r *http.Request) {
+	if s.analytics == nil {
+		s.writeError(w, http.StatusNotImplemented, fmt.Errorf("analytics not available"))
+		return
+	}
+
+	start, end, err := parseTimeRange(r, 7*24*time.Hour)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	snapshots, err := s.analyticsFor(r).EquityCurve(r.Context(), start, end)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.writeJSON(w, http.StatusOK, snapshots)
+}
+
+func (s *Server) handleAlerts(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, http.StatusOK, s.system.RecentAlerts())
+}
+
+func (s *Server) handleReports(w http.ResponseWriter, r *http.Request) {
+	if s.reports == nil {
+		s.writeError(w, http.StatusNotImplemented, fmt.Errorf("reports not available"))
+		return
+	}
+
+	limit := defaultReportLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			s.writeError(w, http.StatusBadRequest, fmt.Errorf("invalid limit: %s", v))
+			return
+		}
+		limit = n
+	}
+
+	reports, err := s.reports.ListPnLReports(r.Context(), r.URL.Query().Get("period"), limit)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if reports == nil {
+		reports = []analytics.PnLReport{}
+	}
+	s.writeJSON(w, http.StatusOK, reports)
+}
+
+// auditReason 返回请求携带的操作原因，优先取请求头，其次取 reason 查询参数
+func auditReason(r *http.Request) string {
+	if reason := r.Header.Get(ReasonHeader); reason != "" {
+		return reason
+	}
+	return r.URL.Query().Get("reason")
+}
+
+// analyticsFor 按 account 查询参数返回只统计该账户的绩效统计服务，未指定时统计所有账户的汇总
+func (s *Server) analyticsFor(r *http.Request) *analytics.Service {
+	return s.analytics.ForAccount(r.URL.Query().Get("account"))
+}
+
+// parseTimeRange 解析 start/end 查询参数，缺省为截至当前的 span 时长
+func parseTimeRange(r *http.Request, span time.Duration) (time.Time, time.Time, error) {
+	end := time.Now()
+	if v := r.URL.Query().Get("end"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid end: %w", err)
+		}
+		end = t
+	}
+
+	start := end.Add(-span)
+	if v := r.URL.Query().Get("start"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid start: %w", err)
+		}
+		start = t
+	}
+
+	return start, end, nil
+}
+
+func (s *Server) writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		s.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+func (s *Server) writeError(w http.ResponseWriter, status int, err error) {
+	s.writeJSON(w, status, map[string]string{"error": err.Error()})
+}
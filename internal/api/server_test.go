@@ -0,0 +1,672 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/analytics"
+	"github.com/songzhibin97/quantaflux/internal/audit"
+	"github.com/songzhibin97/quantaflux/internal/auth"
+	"github.com/songzhibin97/quantaflux/internal/data"
+	"github.com/songzhibin97/quantaflux/internal/exchangeinfo"
+	"github.com/songzhibin97/quantaflux/internal/health"
+	"github.com/songzhibin97/quantaflux/internal/journal"
+	"github.com/songzhibin97/quantaflux/internal/models"
+	"github.com/songzhibin97/quantaflux/internal/params"
+	"github.com/songzhibin97/quantaflux/internal/precision"
+	"github.com/songzhibin97/quantaflux/internal/risk"
+	"github.com/songzhibin97/quantaflux/internal/scheduler"
+	"github.com/songzhibin97/quantaflux/internal/state"
+	"github.com/songzhibin97/quantaflux/internal/tracing"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+	"github.com/songzhibin97/quantaflux/internal/universe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSystem struct {
+	paused        bool
+	pausedSymbols []string
+	flattened     bool
+	pausedOnFlat  bool
+	riskManager   risk.RiskManager
+	snapshots     []string
+	heartbeats    int
+	closed        []float64 // ClosePosition 收到的卖出比例
+}
+
+func (f *fakeSystem) Positions(ctx context.Context) ([]Position, error) {
+	return []Position{{Symbol: "BTCUSDT", Asset: "BTC", Amount: 1, Price: 100, Value: 100}}, nil
+}
+
+func (f *fakeSystem) RecentPredictions() []PredictionRecord { return nil }
+func (f *fakeSystem) RecentAlerts() []risk.RiskAlert        { return nil }
+func (f *fakeSystem) Pause()                                { f.paused = true }
+func (f *fakeSystem) Resume()                               { f.paused = false }
+func (f *fakeSystem) Paused() bool                          { return f.paused }
+
+func (f *fakeSystem) PauseSymbol(symbol string) {
+	f.pausedSymbols = append(f.pausedSymbols, symbol)
+}
+
+func (f *fakeSystem) ResumeSymbol(symbol string) {
+	f.pausedSymbols = slices.DeleteFunc(f.pausedSymbols, func(s string) bool { return s == symbol })
+}
+
+func (f *fakeSystem) PausedSymbols() []string {
+	return f.pausedSymbols
+}
+
+func (f *fakeSystem) Jobs() []scheduler.JobStatus {
+	return []scheduler.JobStatus{{Name: "prune", Interval: "24h0m0s"}}
+}
+
+func (f *fakeSystem) Traces() []tracing.Trace {
+	return []tracing.Trace{{TraceID: "abc", Name: "tick"}}
+}
+
+func (f *fakeSystem) Accounts(ctx context.Context) ([]AccountSummary, error) {
+	positions, _ := f.Positions(ctx)
+	return []AccountSummary{{Name: "default", Strategy: "ai_prediction", Symbols: []string{"BTCUSDT"}, Positions: positions}}, nil
+}
+
+func (f *fakeSystem) Flatten(ctx context.Context) error {
+	f.flattened = true
+	f.pausedOnFlat = f.paused
+	return nil
+}
+
+func (f *fakeSystem) ClosePosition(ctx context.Context, account, symbol string, fraction float64) (*trading.Order, error) {
+	if symbol != "BTCUSDT" {
+		return nil, fmt.Errorf("%w: no position in %s", ErrPositionNotFound, symbol)
+	}
+	f.closed = append(f.closed, fraction)
+	return &trading.Order{Symbol: symbol, Side: "sell", Amount: fraction, OrderType: "market"}, nil
+}
+
+func (f *fakeSystem) Heartbeat() time.Time {
+	f.heartbeats++
+	return time.Now()
+}
+
+func (f *fakeSystem) Snapshot(ctx context.Context, label string) (*state.Snapshot, error) {
+	f.snapshots = append(f.snapshots, label)
+	return &state.Snapshot{ID: int64(len(f.snapshots)), Label: label, Loop: state.LoopState{Paused: f.paused}}, nil
+}
+
+func (f *fakeSystem) RiskState(ctx context.Context, account string) (*risk.RiskState, error) {
+	if account != "" && account != "default" {
+		return nil, fmt.Errorf("%w: %s", ErrAccountNotFound, account)
+	}
+	return f.riskManager.GetRiskState(ctx)
+}
+
+func (f *fakeSystem) SetRiskParameters(ctx context.Context, account string, params *risk.RiskParameters) error {
+	if account != "" && account != "default" {
+		return fmt.Errorf("%w: %s", ErrAccountNotFound, account)
+	}
+	return f.riskManager.SetRiskParameters(ctx, params)
+}
+
+type fakeJournal struct {
+	entries []journal.Entry
+}
+
+func (f *fakeJournal) RecordTrade(ctx context.Context, entry *journal.Entry) error {
+	f.entries = append(f.entries, *entry)
+	return nil
+}
+
+func (f *fakeJournal) ListTrades(ctx context.Context, symbol string, limit int) ([]journal.Entry, error) {
+	return f.entries, nil
+}
+
+func (f *fakeJournal) ListTradesInRange(ctx context.Context, start, end time.Time) ([]journal.Entry, error) {
+	return f.entries, nil
+}
+
+func (f *fakeJournal) GetTrade(ctx context.Context, account, symbol, orderID string) (*journal.Entry, error) {
+	for _, e := range f.entries {
+		if e.Order.OrderID == orderID && (symbol == "" || e.Order.Symbol == symbol) {
+			return &e, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no journal entry found for order: %s", data.ErrNotFound, orderID)
+}
+
+func (f *fakeJournal) ListOpenTrades(ctx context.Context) ([]journal.Entry, error) {
+	return nil, nil
+}
+
+func (f *fakeJournal) UpdateOrderStatus(ctx context.Context, account, symbol, orderID, status string) error {
+	return nil
+}
+
+func (f *fakeJournal) UpdateOrderFill(ctx context.Context, order trading.Order) error {
+	return nil
+}
+
+type fakeEquityStorage struct {
+	snapshots []models.EquitySnapshot
+}
+
+func (f *fakeEquityStorage) SaveEquitySnapshot(ctx context.Context, snapshot *models.EquitySnapshot) error {
+	f.snapshots = append(f.snapshots, *snapshot)
+	return nil
+}
+
+func (f *fakeEquityStorage) GetEquitySnapshots(ctx context.Context, start, end time.Time) ([]models.EquitySnapshot, error) {
+	return f.snapshots, nil
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Error(msg string, fields ...interface{}) {}
+func (nopLogger) Info(msg string, fields ...interface{})  {}
+
+type memoryAuditSink struct {
+	entries []audit.Entry
+}
+
+func (m *memoryAuditSink) AppendAudit(ctx context.Context, entry *audit.Entry) error {
+	m.entries = append(m.entries, *entry)
+	return nil
+}
+
+func newTestServer() (*Server, *fakeSystem) {
+	server, system, _ := newAuditedTestServer()
+	return server, system
+}
+
+// 测试用访问令牌
+var testTokens = map[string]string{audit.ActorCLI: "cli-token", "ops": "ops-token"}
+
+func newAuditedTestServer() (*Server, *fakeSystem, *memoryAuditSink) {
+	system := &fakeSystem{riskManager: risk.NewBasicRiskManager(risk.RiskParameters{
+		MaxPositionSize: 1000,
+		MaxLossPerTrade: 100,
+		MaxDailyLoss:    500,
+		MaxLeverage:     1,
+		MinLiquidity:    1000,
+	})}
+	tradeJournal := &fakeJournal{entries: []journal.Entry{
+		{Strategy: "ai_prediction", Order: trading.Order{Symbol: "BTCUSDT", OrderID: "42"}},
+	}}
+	checker := health.NewChecker(time.Second)
+	checker.AddLiveness("collector", func(ctx context.Context) error { return nil })
+	checker.AddReadiness("database", func(ctx context.Context) error { return errors.New("connection refused") })
+	sink := &memoryAuditSink{}
+	auditLog := audit.NewLog(nopLogger{}, sink)
+	return NewServer(":0", testTokens, system, tradeJournal, nil, nil, NewHub(nopLogger{}), checker, auditLog, nopLogger{}), system, sink
+}
+
+func doRequest(t *testing.T, handler http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	return doAuthorizedRequest(t, handler, method, path, body, testTokens["ops"])
+}
+
+func doAuthorizedRequest(t *testing.T, handler http.Handler, method, path, body, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServer_Positions(t *testing.T) {
+	server, _ := newTestServer()
+
+	rec := doRequest(t, server, http.MethodGet, "/api/v1/positions", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var positions []Position
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &positions))
+	assert.Len(t, positions, 1)
+}
+
+func TestServer_ClosePosition(t *testing.T) {
+	server, system := newTestServer()
+
+	rec := doRequest(t, server, http.MethodPost, "/api/v1/positions/BTCUSDT/close?fraction=0.3", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var order trading.Order
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &order))
+	assert.Equal(t, "sell", order.Side)
+
+	// 未指定比例时全部卖出
+	rec = doRequest(t, server, http.MethodPost, "/api/v1/positions/BTCUSDT/close", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, []float64{0.3, 1}, system.closed)
+
+	rec = doRequest(t, server, http.MethodPost, "/api/v1/positions/BTCUSDT/close?fraction=1.5", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = doRequest(t, server, http.MethodPost, "/api/v1/positions/ETHUSDT/close", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestServer_Precision(t *testing.T) {
+	server, _ := newTestServer()
+	info := exchangeinfo.NewService(stubExchangeInfo{{Symbol: "BTCUSDT", TickSize: 0.01, StepSize: 0.00001}})
+	require.NoError(t, info.Refresh(context.Background()))
+	server.SetPrecision(precision.NewFormatter(info))
+
+	rec := doRequest(t, server, http.MethodGet, "/api/v1/precision?symbols=btcusdt,DOGEUSDT,", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var rules map[string]precision.Rules
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &rules))
+	assert.Equal(t, map[string]precision.Rules{
+		"BTCUSDT":  {TickSize: 0.01, StepSize: 0.00001, PriceDecimals: 2, QuantityDecimals: 5},
+		"DOGEUSDT": {PriceDecimals: precision.DefaultDecimals, QuantityDecimals: precision.DefaultDecimals},
+	}, rules)
+}
+
+func TestServer_Universe(t *testing.T) {
+	server, _, sink := newAuditedTestServer()
+	rec := doRequest(t, server, http.MethodGet, "/api/v1/universe", "")
+	assert.Equal(t, http.StatusNotImplemented, rec.Code)
+
+	service := universe.NewService(&memoryUniverse{})
+	require.NoError(t, service.Load(context.Background()))
+	_, err := service.Sync(context.Background(), []exchangeinfo.Symbol{
+		{Symbol: "BTCUSDT", BaseAsset: "BTC", QuoteAsset: "USDT", Status: exchangeinfo.StatusTrading},
+		{Symbol: "PEPEUSDT", BaseAsset: "PEPE", QuoteAsset: "USDT", Status: exchangeinfo.StatusTrading},
+	}, time.Now())
+	require.NoError(t, err)
+	server.SetUniverse(service)
+
+	rec = doAuthorizedRequest(t, server, http.MethodPut, "/api/v1/universe/pepeusdt", `{"tags":["meme"]}`, "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	rec = doRequest(t, server, http.MethodPut, "/api/v1/universe/pepeusdt", `{"tags":["meme"]}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, sink.entries, 1)
+	assert.Equal(t, audit.ActionUpdateSymbol, sink.entries[0].Action)
+	assert.Equal(t, "PEPEUSDT", sink.entries[0].Target)
+
+	rec = doRequest(t, server, http.MethodGet, "/api/v1/universe?tag=MEME", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var symbols []universe.Symbol
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &symbols))
+	require.Len(t, symbols, 1)
+	assert.Equal(t, "PEPEUSDT", symbols[0].Symbol)
+	assert.Equal(t, []string{"meme"}, symbols[0].Tags)
+
+	rec = doRequest(t, server, http.MethodGet, "/api/v1/universe/BTCUSDT", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = doRequest(t, server, http.MethodGet, "/api/v1/universe/DOGEUSDT", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = doRequest(t, server, http.MethodPut, "/api/v1/universe/BTCUSDT", `{"tags":`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+type memoryUniverse struct {
+	symbols []universe.Symbol
+}
+
+func (m *memoryUniverse) SaveSymbols(ctx context.Context, symbols []universe.Symbol) error {
+	m.symbols = append(m.symbols, symbols...)
+	return nil
+}
+
+func (m *memoryUniverse) ListSymbols(ctx context.Context) ([]universe.Symbol, error) {
+	return m.symbols, nil
+}
+
+type stubExchangeInfo []exchangeinfo.Symbol
+
+func (s stubExchangeInfo) ExchangeInfo(ctx context.Context) ([]exchangeinfo.Symbol, error) {
+	return s, nil
+}
+
+// fakeTuner 只有 ai_config.min_confidence 一个参数，取值范围为 [0, 1]
+type fakeTuner struct {
+	param   params.Param
+	changes []params.Change
+}
+
+func (f *fakeTuner) Params() []params.Param {
+	return []params.Param{f.param}
+}
+
+func (f *fakeTuner) SetParam(ctx context.Context, name string, value float64, reason string) (*params.Param, error) {
+	if name != f.param.Name {
+		return nil, params.ErrUnknownParam
+	}
+	if value < 0 || value > 1 {
+		return nil, fmt.Errorf("%w: out of range", params.ErrInvalidValue)
+	}
+	f.changes = append([]params.Change{{Name: name, Value: &value, Previous: f.param.Value, Actor: audit.ActorFromContext(ctx), Reason: reason}}, f.changes...)
+	f.param.Value, f.param.Overridden = value, true
+	return &f.param, nil
+}
+
+func (f *fakeTuner) ResetParam(ctx context.Context, name string, reason string) (*params.Param, error) {
+	if name != f.param.Name {
+		return nil, params.ErrUnknownParam
+	}
+	f.changes = append([]params.Change{{Name: name, Previous: f.param.Value, Actor: audit.ActorFromContext(ctx), Reason: reason}}, f.changes...)
+	f.param.Value, f.param.Overridden = f.param.Default, false
+	return &f.param, nil
+}
+
+func (f *fakeTuner) ParamHistory(ctx context.Context, name string, limit int) ([]params.Change, error) {
+	return f.changes[:min(limit, len(f.changes))], nil
+}
+
+func TestServer_Params(t *testing.T) {
+	server, _, sink := newAuditedTestServer()
+	rec := doRequest(t, server, http.MethodGet, "/api/v1/params", "")
+	assert.Equal(t, http.StatusNotImplemented, rec.Code)
+
+	tuner := &fakeTuner{param: params.Param{Name: "ai_config.min_confidence", Value: 0.7, Default: 0.7}}
+	server.SetTuner(tuner)
+
+	rec = doAuthorizedRequest(t, server, http.MethodPut, "/api/v1/params/ai_config.min_confidence", `{"value":0.8}`, "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	rec = doRequest(t, server, http.MethodPut, "/api/v1/params/ai_config.min_confidence?reason=too+many+losers", `{"value":0.8}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, sink.entries, 1)
+	assert.Equal(t, audit.ActionSetParam, sink.entries[0].Action)
+	assert.Equal(t, "ai_config.min_confidence", sink.entries[0].Target)
+
+	rec = doRequest(t, server, http.MethodGet, "/api/v1/params", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var current []params.Param
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &current))
+	require.Len(t, current, 1)
+	assert.Equal(t, 0.8, current[0].Value)
+	assert.True(t, current[0].Overridden)
+
+	rec = doRequest(t, server, http.MethodPut, "/api/v1/params/ai_config.min_confidence", `{"value":1.5}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = doRequest(t, server, http.MethodPut, "/api/v1/params/ai_config.min_confidence", `{}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = doRequest(t, server, http.MethodPut, "/api/v1/params/trading_config.unknown", `{"value":1}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = doRequest(t, server, http.MethodDelete, "/api/v1/params/ai_config.min_confidence", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 0.7, tuner.param.Value)
+	require.Len(t, sink.entries, 2)
+	assert.Equal(t, audit.ActionResetParam, sink.entries[1].Action)
+
+	rec = doRequest(t, server, http.MethodGet, "/api/v1/params/history?limit=1", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var history []params.Change
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &history))
+	require.Len(t, history, 1)
+	assert.Nil(t, history[0].Value)
+	assert.Equal(t, "ops", history[0].Actor)
+	rec = doRequest(t, server, http.MethodGet, "/api/v1/params/history?limit=x", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestServer_Accounts(t *testing.T) {
+	server, _ := newTestServer()
+
+	rec := doRequest(t, server, http.MethodGet, "/api/v1/accounts", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var accounts []AccountSummary
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &accounts))
+	require.Len(t, accounts, 1)
+	assert.Equal(t, "default", accounts[0].Name)
+	assert.Len(t, accounts[0].Positions, 1)
+}
+
+func TestServer_Orders(t *testing.T) {
+	server, _ := newTestServer()
+
+	rec := doRequest(t, server, http.MethodGet, "/api/v1/orders?limit=10", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = doRequest(t, server, http.MethodGet, "/api/v1/orders?limit=abc", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = doRequest(t, server, http.MethodGet, "/api/v1/orders/42", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = doRequest(t, server, http.MethodGet, "/api/v1/orders/42?symbol=BTCUSDT", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = doRequest(t, server, http.MethodGet, "/api/v1/orders/42?symbol=ETHUSDT", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = doRequest(t, server, http.MethodGet, "/api/v1/orders/7", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestServer_Health(t *testing.T) {
+	server, _ := newTestServer()
+
+	rec := doRequest(t, server, http.MethodGet, "/healthz", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = doRequest(t, server, http.MethodGet, "/readyz", "")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	var report health.Report
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	assert.Equal(t, health.StatusFail, report.Status)
+	assert.Equal(t, health.StatusOK, report.Checks["collector"].Status)
+	assert.Equal(t, "connection refused", report.Checks["database"].Error)
+}
+
+func TestServer_TradingControls(t *testing.T) {
+	server, system := newTestServer()
+
+	rec := doRequest(t, server, http.MethodPost, "/api/v1/trading/pause", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, system.paused)
+
+	rec = doRequest(t, server, http.MethodPost, "/api/v1/trading/resume", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.False(t, system.paused)
+
+	rec = doRequest(t, server, http.MethodPost, "/api/v1/trading/symbols/ETHUSDT/pause", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var status TradingStatus
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+	assert.False(t, status.Paused)
+	assert.Equal(t, []string{"ETHUSDT"}, status.PausedSymbols)
+
+	rec = doRequest(t, server, http.MethodGet, "/api/v1/risk", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var riskState risk.RiskState
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &riskState))
+	assert.Equal(t, []string{"ETHUSDT"}, riskState.PausedSymbols)
+
+	rec = doRequest(t, server, http.MethodPost, "/api/v1/trading/symbols/ETHUSDT/resume", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, system.pausedSymbols)
+
+	rec = doRequest(t, server, http.MethodPost, "/api/v1/trading/flatten", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, system.flattened)
+	// 清仓前先暂停下单
+	assert.True(t, system.pausedOnFlat)
+	assert.True(t, system.paused)
+
+	rec = doRequest(t, server, http.MethodPost, "/api/v1/heartbeat", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 1, system.heartbeats)
+	rec = doAuthorizedRequest(t, server, http.MethodPost, "/api/v1/heartbeat", "", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, 1, system.heartbeats)
+
+	rec = doRequest(t, server, http.MethodPost, "/api/v1/snapshots?label=v1.2.0", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var snapshot state.Snapshot
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &snapshot))
+	assert.Equal(t, "v1.2.0", snapshot.Label)
+	assert.True(t, snapshot.Loop.Paused)
+	assert.Equal(t, []string{"v1.2.0"}, system.snapshots)
+
+	rec = doRequest(t, server, http.MethodGet, "/api/v1/trading/pause", "")
+	assert.NotEqual(t, http.StatusOK, rec.Code)
+}
+
+func TestServer_Audit(t *testing.T) {
+	server, _, sink := newAuditedTestServer()
+
+	doRequest(t, server, http.MethodPost, "/api/v1/trading/pause?reason=maintenance", "")
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/trading/symbols/ETHUSDT/resume", nil)
+	req.Header.Set("Authorization", "Bearer "+testTokens[audit.ActorCLI])
+	req.Header.Set(ReasonHeader, "checked manually")
+	server.ServeHTTP(httptest.NewRecorder(), req)
+
+	// 只读请求不记录
+	doRequest(t, server, http.MethodGet, "/api/v1/trading/status", "")
+
+	require.Len(t, sink.entries, 2)
+	assert.Equal(t, "ops", sink.entries[0].Actor)
+	assert.Equal(t, audit.ActionPause, sink.entries[0].Action)
+	assert.Equal(t, "maintenance", sink.entries[0].Reason)
+
+	assert.Equal(t, audit.ActorCLI, sink.entries[1].Actor)
+	assert.Equal(t, audit.ActionResumeSymbol, sink.entries[1].Action)
+	assert.Equal(t, "ETHUSDT", sink.entries[1].Target)
+	assert.Equal(t, "checked manually", sink.entries[1].Reason)
+}
+
+func TestServer_Dashboard(t *testing.T) {
+	server, _ := newTestServer()
+
+	rec := doRequest(t, server, http.MethodGet, "/", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "QuantaFlux")
+
+	rec = doRequest(t, server, http.MethodGet, "/api/v1/jobs", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "prune")
+
+	rec = doRequest(t, server, http.MethodGet, "/api/v1/traces", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"trace_id":"abc"`)
+
+	rec = doRequest(t, server, http.MethodGet, "/api/v1/equity", "")
+	assert.Equal(t, http.StatusNotImplemented, rec.Code)
+}
+
+func TestServer_RiskParameters(t *testing.T) {
+	server, _ := newTestServer()
+
+	body := `{"max_position_size": 2000, "max_loss_per_trade": 200, "max_daily_loss": 800, "max_leverage": 2, "min_liquidity": 500}`
+	rec := doRequest(t, server, http.MethodPut, "/api/v1/risk/parameters", body)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var state risk.RiskState
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &state))
+	assert.Equal(t, 2000.0, state.Parameters.MaxPositionSize)
+
+	rec = doRequest(t, server, http.MethodPut, "/api/v1/risk/parameters", `{"max_position_size": -1}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = doRequest(t, server, http.MethodPut, "/api/v1/risk/parameters?account=default", body)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = doRequest(t, server, http.MethodPut, "/api/v1/risk/parameters?account=unknown", body)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = doRequest(t, server, http.MethodGet, "/api/v1/risk?account=unknown", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = doRequest(t, server, http.MethodGet, "/api/v1/analytics/performance", "")
+	assert.Equal(t, http.StatusNotImplemented, rec.Code)
+
+	rec = doRequest(t, server, http.MethodGet, "/api/v1/analytics/execution", "")
+	assert.Equal(t, http.StatusNotImplemented, rec.Code)
+}
+
+func TestServer_Equity(t *testing.T) {
+	now := time.Now()
+	equity := &fakeEquityStorage{snapshots: []models.EquitySnapshot{
+		{Equity: 1000, Mode: "paper", Timestamp: now.Add(-time.Hour)},
+		{Equity: 5000, Mode: "shadow", Timestamp: now.Add(-time.Hour)},
+		{Equity: 1010, Mode: "paper", Timestamp: now},
+	}}
+	service := analytics.NewService(equity, &fakeJournal{}, 0, "paper")
+	server := NewServer(":0", nil, &fakeSystem{}, &fakeJournal{}, service, nil, NewHub(nopLogger{}), nil, nil, nopLogger{})
+
+	rec := doRequest(t, server, http.MethodGet, "/api/v1/equity", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var snapshots []models.EquitySnapshot
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &snapshots))
+	require.Len(t, snapshots, 2)
+	assert.Equal(t, 1010.0, snapshots[1].Equity)
+}
+
+func TestServer_Authorization(t *testing.T) {
+	server, system := newTestServer()
+
+	// 未携带或携带错误的令牌
+	rec := doAuthorizedRequest(t, server, http.MethodPost, "/api/v1/trading/pause", "", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	rec = doAuthorizedRequest(t, server, http.MethodPost, "/api/v1/trading/pause", "", "wrong-token")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.False(t, system.paused)
+
+	// 伪造的发起方请求头不再生效
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/trading/flatten", nil)
+	req.Header.Set("X-Quantaflux-Actor", audit.ActorCLI)
+	rec = httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.False(t, system.flattened)
+
+	// 只读接口不需要令牌
+	rec = doAuthorizedRequest(t, server, http.MethodGet, "/api/v1/trading/status", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// 未配置令牌时拒绝所有修改
+	unconfigured := NewServer(":0", nil, system, &fakeJournal{}, nil, nil, nil, nil, nil, nopLogger{})
+	rec = doAuthorizedRequest(t, unconfigured, http.MethodPost, "/api/v1/trading/pause", "", "cli-token")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.False(t, system.paused)
+}
+
+func TestServer_Roles(t *testing.T) {
+	server, system := newTestServer()
+	server.SetAuthenticator(auth.New(auth.Options{
+		RequireAuth: true,
+		Keys: append(ControlKeys(testTokens),
+			auth.Key{Name: "grafana", Secret: "read-token", Role: auth.RoleRead, RateLimit: 2}),
+	}))
+
+	// 要求认证时只读接口也需要令牌，健康检查不需要
+	rec := doAuthorizedRequest(t, server, http.MethodGet, "/api/v1/trading/status", "", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	rec = doAuthorizedRequest(t, server, http.MethodGet, "/healthz", "", "")
+	assert.NotEqual(t, http.StatusUnauthorized, rec.Code)
+
+	// 只读角色不能修改
+	rec = doAuthorizedRequest(t, server, http.MethodGet, "/api/v1/trading/status", "", "read-token")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = doAuthorizedRequest(t, server, http.MethodPost, "/api/v1/trading/flatten", "", "read-token")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.False(t, system.flattened)
+
+	// 查询参数中的令牌，供浏览器的 WebSocket 使用，超过限流返回 429
+	rec = doAuthorizedRequest(t, server, http.MethodGet, "/api/v1/trading/status?access_token=read-token", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = doAuthorizedRequest(t, server, http.MethodGet, "/api/v1/trading/status?access_token=read-token", "", "")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+
+	// 控制角色不受只读密钥的限流影响
+	rec = doAuthorizedRequest(t, server, http.MethodGet, "/api/v1/trading/status", "", testTokens["ops"])
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
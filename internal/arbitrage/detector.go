@@ -0,0 +1,117 @@
+package arbitrage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+const bps = 10000
+
+// Detector 比较同一交易对在各交易所的价格，价差覆盖手续费和滑点时产生套利信号
+type Detector struct {
+	venues       []Venue
+	slippageBps  float64
+	minProfitBps float64
+}
+
+// NewDetector slippageBps 为单边预估滑点，minProfitBps 为扣除成本后要求的最小利润
+func NewDetector(venues []Venue, slippageBps, minProfitBps float64) *Detector {
+	return &Detector{
+		venues:       venues,
+		slippageBps:  slippageBps,
+		minProfitBps: minProfitBps,
+	}
+}
+
+// Quotes 并发获取各交易所的报价，获取失败的交易所会被跳过，少于两个报价时返回错误
+func (d *Detector) Quotes(ctx context.Context, symbol string) ([]Quote, error) {
+	quotes := make([]*Quote, len(d.venues))
+	errs := make([]error, len(d.venues))
+
+	var wg sync.WaitGroup
+	for i, venue := range d.venues {
+		wg.Add(1)
+		go func(i int, venue Venue) {
+			defer wg.Done()
+			data, err := venue.Source.CollectMarketData(ctx, symbol)
+			if err != nil {
+				errs[i] = fmt.Errorf("%s: %w", venue.Name, err)
+				return
+			}
+			if data == nil || data.Price <= 0 {
+				errs[i] = fmt.Errorf("%s: invalid price for %s", venue.Name, symbol)
+				return
+			}
+			quotes[i] = &Quote{Venue: venue.Name, Price: data.Price, Timestamp: data.Timestamp}
+		}(i, venue)
+	}
+	wg.Wait()
+
+	result := make([]Quote, 0, len(quotes))
+	for _, q := range quotes {
+		if q != nil {
+			result = append(result, *q)
+		}
+	}
+	if len(result) < 2 {
+		return result, fmt.Errorf("failed to collect quotes from at least two venues: %w", errors.Join(errs...))
+	}
+	return result, nil
+}
+
+// Detect 获取报价并返回当前的套利机会
+func (d *Detector) Detect(ctx context.Context, symbol string) ([]Opportunity, error) {
+	quotes, err := d.Quotes(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	return d.Compare(symbol, quotes), nil
+}
+
+// Compare 两两比较报价，返回扣除成本后利润达到要求的机会，按利润从高到低排序
+func (d *Detector) Compare(symbol string, quotes []Quote) []Opportunity {
+	var result []Opportunity
+	for _, buy := range quotes {
+		for _, sell := range quotes {
+			if buy.Venue == sell.Venue || sell.Price <= buy.Price {
+				continue
+			}
+
+			spread := (sell.Price - buy.Price) / buy.Price * bps
+			cost := (d.feeRate(buy.Venue)+d.feeRate(sell.Venue))*bps + 2*d.slippageBps
+			if spread-cost <= d.minProfitBps {
+				continue
+			}
+
+			result = append(result, Opportunity{
+				Symbol:    symbol,
+				BuyVenue:  buy.Venue,
+				SellVenue: sell.Venue,
+				BuyPrice:  buy.Price,
+				SellPrice: sell.Price,
+				SpreadBps: spread,
+				CostBps:   cost,
+				NetBps:    spread - cost,
+				Timestamp: time.Now(),
+			})
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].NetBps > result[j].NetBps
+	})
+	return result
+}
+
+func (d *Detector) feeRate(venue string) float64 {
+	for _, v := range d.venues {
+		if v.Name == venue {
+			return v.FeeRate
+		}
+	}
+	return 0
+}
@@ -0,0 +1,86 @@
+package arbitrage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSource struct {
+	price float64
+	err   error
+}
+
+func (f *fakeSource) CollectMarketData(ctx context.Context, symbol string) (*models.MarketData, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &models.MarketData{Symbol: symbol, Price: f.price, Timestamp: time.Now()}, nil
+}
+
+func TestDetector_Detect(t *testing.T) {
+	venues := []Venue{
+		{Name: "binance", Source: &fakeSource{price: 100}, FeeRate: 0.001},
+		{Name: "okx", Source: &fakeSource{price: 100.5}, FeeRate: 0.001},
+		{Name: "bybit", Source: &fakeSource{price: 100.25}, FeeRate: 0.001},
+	}
+	// 成本：双边手续费 20bps + 双边滑点 2bps
+	detector := NewDetector(venues, 1, 5)
+
+	opportunities, err := detector.Detect(context.Background(), "BTCUSDT")
+	require.NoError(t, err)
+
+	// 只有 binance -> okx 的价差（50bps）覆盖成本和最小利润，bybit 相关的价差都不足
+	require.Len(t, opportunities, 1)
+	opp := opportunities[0]
+	assert.Equal(t, "binance", opp.BuyVenue)
+	assert.Equal(t, "okx", opp.SellVenue)
+	assert.InDelta(t, 50, opp.SpreadBps, 1e-9)
+	assert.InDelta(t, 22, opp.CostBps, 1e-9)
+	assert.InDelta(t, 28, opp.NetBps, 1e-9)
+}
+
+func TestDetector_Compare_SortsByProfit(t *testing.T) {
+	detector := NewDetector([]Venue{{Name: "a"}, {Name: "b"}, {Name: "c"}}, 0, 0)
+	opportunities := detector.Compare("BTCUSDT", []Quote{
+		{Venue: "a", Price: 100},
+		{Venue: "b", Price: 101},
+		{Venue: "c", Price: 102},
+	})
+
+	require.Len(t, opportunities, 3)
+	assert.Equal(t, "a", opportunities[0].BuyVenue)
+	assert.Equal(t, "c", opportunities[0].SellVenue)
+	for i := 1; i < len(opportunities); i++ {
+		assert.GreaterOrEqual(t, opportunities[i-1].NetBps, opportunities[i].NetBps)
+	}
+}
+
+func TestDetector_Quotes(t *testing.T) {
+	ctx := context.Background()
+
+	// 单个交易所失败时跳过
+	detector := NewDetector([]Venue{
+		{Name: "binance", Source: &fakeSource{price: 100}},
+		{Name: "okx", Source: &fakeSource{err: errors.New("timeout")}},
+		{Name: "bybit", Source: &fakeSource{price: 101}},
+	}, 0, 0)
+	quotes, err := detector.Quotes(ctx, "BTCUSDT")
+	require.NoError(t, err)
+	assert.Len(t, quotes, 2)
+
+	// 少于两个有效报价时无法比价
+	detector = NewDetector([]Venue{
+		{Name: "binance", Source: &fakeSource{price: 100}},
+		{Name: "okx", Source: &fakeSource{price: 0}},
+	}, 0, 0)
+	_, err = detector.Quotes(ctx, "BTCUSDT")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "okx: invalid price")
+}
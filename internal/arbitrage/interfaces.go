@@ -0,0 +1,41 @@
+package arbitrage
+
+import (
+	"context"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/models"
+)
+
+// PriceSource 提供单个交易所行情的数据源
+type PriceSource interface {
+	// CollectMarketData returns the latest market data of the symbol on this exchange.
+	CollectMarketData(ctx context.Context, symbol string) (*models.MarketData, error)
+}
+
+// Venue 参与比价的交易所
+type Venue struct {
+	Name    string
+	Source  PriceSource
+	FeeRate float64 // 单边手续费率
+}
+
+// Quote 某个交易所的报价
+type Quote struct {
+	Venue     string    `json:"venue"`
+	Price     float64   `json:"price"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Opportunity 套利机会：在 BuyVenue 买入，同时在 SellVenue 卖出
+type Opportunity struct {
+	Symbol    string    `json:"symbol"`
+	BuyVenue  string    `json:"buy_venue"`
+	SellVenue string    `json:"sell_venue"`
+	BuyPrice  float64   `json:"buy_price"`
+	SellPrice float64   `json:"sell_price"`
+	SpreadBps float64   `json:"spread_bps"` // 价差，相对买入价
+	CostBps   float64   `json:"cost_bps"`   // 双边手续费加滑点
+	NetBps    float64   `json:"net_bps"`    // 扣除成本后的预期利润
+	Timestamp time.Time `json:"timestamp"`
+}
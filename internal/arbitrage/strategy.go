@@ -0,0 +1,91 @@
+package arbitrage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/songzhibin97/quantaflux/internal/trading"
+)
+
+// Strategy 套利执行：在买入交易所市价买入，同时在卖出交易所卖出等量的基础资产。
+// 现货无法卖空，卖出交易所需预先持有基础资产
+type Strategy struct {
+	executors map[string]trading.TradeExecutor // 交易所名称 -> 执行器
+	amount    float64                          // 每次套利的基础资产数量
+}
+
+func NewStrategy(executors map[string]trading.TradeExecutor, amount float64) *Strategy {
+	return &Strategy{
+		executors: executors,
+		amount:    amount,
+	}
+}
+
+// Execute 执行一次套利，返回两边的订单。先买入，按实际成交数量卖出；
+// 卖出失败时在买入交易所卖回，避免留下单边持仓
+func (s *Strategy) Execute(ctx context.Context, opp Opportunity) (buy, sell *trading.Order, err error) {
+	buyExecutor, ok := s.executors[opp.BuyVenue]
+	if !ok {
+		return nil, nil, fmt.Errorf("no executor for venue %s", opp.BuyVenue)
+	}
+	sellExecutor, ok := s.executors[opp.SellVenue]
+	if !ok {
+		return nil, nil, fmt.Errorf("no executor for venue %s", opp.SellVenue)
+	}
+
+	base, _, ok := trading.SplitSymbol(opp.Symbol)
+	if !ok {
+		return nil, nil, fmt.Errorf("unable to determine base asset for symbol: %s", opp.Symbol)
+	}
+	available, err := sellExecutor.GetBalance(ctx, base)
+	if err != nil && !errors.Is(err, trading.ErrBalanceNotFound) {
+		return nil, nil, fmt.Errorf("failed to get %s balance on %s: %w", base, opp.SellVenue, err)
+	}
+	if available < s.amount {
+		return nil, nil, fmt.Errorf("%w: %s holds %f %s, need %f", trading.ErrOrderRejected, opp.SellVenue, available, base, s.amount)
+	}
+
+	buy = &trading.Order{
+		Account:   opp.BuyVenue,
+		Symbol:    opp.Symbol,
+		Side:      "buy",
+		Amount:    s.amount,
+		Price:     opp.BuyPrice,
+		OrderType: "market",
+	}
+	if err := buyExecutor.PlaceOrder(ctx, buy); err != nil {
+		return nil, nil, fmt.Errorf("failed to buy on %s: %w", opp.BuyVenue, err)
+	}
+
+	filled := buy.ExecutedAmount()
+	if filled <= 0 {
+		return buy, nil, fmt.Errorf("buy order on %s was not filled, status: %s", opp.BuyVenue, buy.Status)
+	}
+
+	sell = &trading.Order{
+		Account:   opp.SellVenue,
+		Symbol:    opp.Symbol,
+		Side:      "sell",
+		Amount:    filled,
+		Price:     opp.SellPrice,
+		OrderType: "market",
+	}
+	if err := sellExecutor.PlaceOrder(ctx, sell); err != nil {
+		err = fmt.Errorf("failed to sell on %s: %w", opp.SellVenue, err)
+		unwind := &trading.Order{
+			Account:   opp.BuyVenue,
+			Symbol:    opp.Symbol,
+			Side:      "sell",
+			Amount:    filled,
+			Price:     opp.BuyPrice,
+			OrderType: "market",
+		}
+		if unwindErr := buyExecutor.PlaceOrder(ctx, unwind); unwindErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to unwind buy on %s: %w", opp.BuyVenue, unwindErr))
+		}
+		return buy, unwind, err
+	}
+
+	return buy, sell, nil
+}
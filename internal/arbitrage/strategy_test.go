@@ -0,0 +1,76 @@
+package arbitrage
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/songzhibin97/quantaflux/internal/trading"
+	"github.com/songzhibin97/quantaflux/internal/trading/paper"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rejectingExecutor 拒绝所有订单的执行器，用于模拟卖出失败
+type rejectingExecutor struct {
+	*paper.PaperExecutor
+}
+
+func (r rejectingExecutor) PlaceOrder(ctx context.Context, order *trading.Order) error {
+	return trading.ErrOrderRejected
+}
+
+func newVenue(price float64, balances map[string]float64) *paper.PaperExecutor {
+	executor := paper.NewPaperExecutor(balances)
+	executor.UpdateMarketPrice("BTCUSDT", price)
+	return executor
+}
+
+func TestStrategy_Execute(t *testing.T) {
+	ctx := context.Background()
+	cheap := newVenue(100, map[string]float64{"USDT": 1000})
+	dear := newVenue(101, map[string]float64{"BTC": 2})
+	strategy := NewStrategy(map[string]trading.TradeExecutor{"cheap": cheap, "dear": dear}, 1)
+
+	opp := Opportunity{Symbol: "BTCUSDT", BuyVenue: "cheap", SellVenue: "dear", BuyPrice: 100, SellPrice: 101}
+	buy, sell, err := strategy.Execute(ctx, opp)
+	require.NoError(t, err)
+	assert.Equal(t, "buy", buy.Side)
+	assert.Equal(t, "sell", sell.Side)
+	assert.Equal(t, 1.0, sell.Amount)
+
+	usdt, err := cheap.GetBalance(ctx, "USDT")
+	require.NoError(t, err)
+	assert.InDelta(t, 900, usdt, 1e-9)
+	btc, err := dear.GetBalance(ctx, "BTC")
+	require.NoError(t, err)
+	assert.InDelta(t, 1, btc, 1e-9)
+
+	// 卖出交易所持有的基础资产不足时不下单
+	strategy = NewStrategy(map[string]trading.TradeExecutor{"cheap": cheap, "dear": dear}, 5)
+	_, _, err = strategy.Execute(ctx, opp)
+	assert.ErrorIs(t, err, trading.ErrOrderRejected)
+
+	_, _, err = strategy.Execute(ctx, Opportunity{Symbol: "BTCUSDT", BuyVenue: "cheap", SellVenue: "unknown"})
+	assert.Error(t, err)
+}
+
+func TestStrategy_Execute_UnwindsOnSellFailure(t *testing.T) {
+	ctx := context.Background()
+	cheap := newVenue(100, map[string]float64{"USDT": 1000})
+	dear := rejectingExecutor{newVenue(101, map[string]float64{"BTC": 2})}
+	strategy := NewStrategy(map[string]trading.TradeExecutor{"cheap": cheap, "dear": dear}, 1)
+
+	opp := Opportunity{Symbol: "BTCUSDT", BuyVenue: "cheap", SellVenue: "dear", BuyPrice: 100, SellPrice: 101}
+	_, unwind, err := strategy.Execute(ctx, opp)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, trading.ErrOrderRejected))
+	require.NotNil(t, unwind)
+	assert.Equal(t, "cheap", unwind.Account)
+
+	// 买入后已在买入交易所卖回，不留单边持仓
+	btc, err := cheap.GetBalance(ctx, "BTC")
+	require.NoError(t, err)
+	assert.Zero(t, btc)
+}
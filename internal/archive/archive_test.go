@@ -0,0 +1,155 @@
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type row struct {
+	id int64
+	at time.Time
+}
+
+// memorySource 内存中的一张表
+type memorySource struct {
+	rows []row
+}
+
+func (m *memorySource) OldestArchivable(ctx context.Context, table string, before time.Time) (time.Time, bool, error) {
+	var oldest time.Time
+	for _, r := range m.rows {
+		if r.at.Before(before) && (oldest.IsZero() || r.at.Before(oldest)) {
+			oldest = r.at
+		}
+	}
+	return oldest, !oldest.IsZero(), nil
+}
+
+func (m *memorySource) ExportRows(ctx context.Context, table string, start, end time.Time, w io.Writer) (int, int64, error) {
+	var count int
+	var maxID int64
+	for _, r := range m.rows {
+		if !r.at.Before(start) && r.at.Before(end) {
+			fmt.Fprintf(w, "{\"id\":%d}\n", r.id)
+			count++
+			maxID = max(maxID, r.id)
+		}
+	}
+	return count, maxID, nil
+}
+
+func (m *memorySource) DeleteRows(ctx context.Context, table string, start, end time.Time, maxID int64) (int64, error) {
+	var kept []row
+	for _, r := range m.rows {
+		if r.at.Before(start) || !r.at.Before(end) || r.id > maxID {
+			kept = append(kept, r)
+		}
+	}
+	n := int64(len(m.rows) - len(kept))
+	m.rows = kept
+	return n, nil
+}
+
+func readArchive(t *testing.T, name string) string {
+	t.Helper()
+	f, err := os.Open(name)
+	require.NoError(t, err)
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	content, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	return string(content)
+}
+
+func TestArchiver_Run(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	source := &memorySource{rows: []row{
+		{id: 1, at: day.Add(time.Hour)},
+		{id: 2, at: day.Add(23 * time.Hour)},
+		{id: 3, at: day.Add(25 * time.Hour)},
+		{id: 4, at: day.Add(50 * time.Hour)}, // 晚于 before，保留
+	}}
+	root := t.TempDir()
+	archiver := NewArchiver(source, NewDirStore(root), "quantaflux")
+
+	results, err := archiver.Run(ctx, []string{"market_data"}, day.Add(48*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, 3, results[0].Rows)
+	assert.Equal(t, []string{
+		"quantaflux/market_data/2024/03/01/part-2.jsonl.gz",
+		"quantaflux/market_data/2024/03/02/part-3.jsonl.gz",
+	}, results[0].Files)
+	assert.Equal(t, []row{{id: 4, at: day.Add(50 * time.Hour)}}, source.rows)
+	assert.Equal(t, "{\"id\":1}\n{\"id\":2}\n", readArchive(t, filepath.Join(root, "quantaflux/market_data/2024/03/01/part-2.jsonl.gz")))
+
+	// 归档后补写的同一天的行上传为新文件，不覆盖已有归档
+	source.rows = append(source.rows, row{id: 5, at: day.Add(2 * time.Hour)})
+	results, err = archiver.Run(ctx, []string{"market_data"}, day.Add(48*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"quantaflux/market_data/2024/03/01/part-5.jsonl.gz"}, results[0].Files)
+	assert.Equal(t, "{\"id\":1}\n{\"id\":2}\n", readArchive(t, filepath.Join(root, "quantaflux/market_data/2024/03/01/part-2.jsonl.gz")))
+}
+
+func TestArchiver_UploadFailureKeepsRows(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "AccessDenied", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	source := &memorySource{rows: []row{{id: 1, at: day}}}
+	archiver := NewArchiver(source, NewS3Store(S3Options{Endpoint: server.URL, Region: "auto", Bucket: "archive"}), "")
+
+	results, err := archiver.Run(context.Background(), []string{"audit_log"}, day.Add(48*time.Hour))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "AccessDenied")
+	assert.Zero(t, results[0].Rows)
+	assert.Len(t, source.rows, 1)
+}
+
+func TestS3Store_Put(t *testing.T) {
+	var got *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	store := NewS3Store(S3Options{Endpoint: server.URL + "/", Region: "us-east-1", Bucket: "archive", AccessKey: "AKID", SecretKey: "secret"})
+	store.now = func() time.Time { return time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC) }
+	require.NoError(t, store.Put(context.Background(), "market_data/2024/03/01/part-2.jsonl.gz", []byte("data")))
+
+	require.NotNil(t, got)
+	assert.Equal(t, http.MethodPut, got.Method)
+	assert.Equal(t, "/archive/market_data/2024/03/01/part-2.jsonl.gz", got.URL.Path)
+	assert.Equal(t, []byte("data"), body)
+	assert.Equal(t, "20240301T120000Z", got.Header.Get("X-Amz-Date"))
+	assert.Equal(t, sha256Hex([]byte("data")), got.Header.Get("X-Amz-Content-Sha256"))
+	auth := got.Header.Get("Authorization")
+	assert.True(t, strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/20240301/us-east-1/s3/aws4_request, "), auth)
+	assert.Contains(t, auth, "SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date, Signature=")
+
+	// 相同请求的签名确定
+	req, err := http.NewRequest(http.MethodPut, server.URL+"/archive/market_data/2024/03/01/part-2.jsonl.gz", bytes.NewReader([]byte("data")))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/gzip")
+	store.sign(req, []byte("data"))
+	assert.Equal(t, auth, req.Header.Get("Authorization"))
+}
@@ -0,0 +1,33 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// DirStore 将归档文件写入本地目录，用于挂载的网络存储或测试
+type DirStore struct {
+	root string
+}
+
+func NewDirStore(root string) *DirStore {
+	return &DirStore{root: root}
+}
+
+// Put implements Store interface，先写入临时文件再改名，中断时不会留下不完整的归档
+func (d *DirStore) Put(ctx context.Context, key string, body []byte) error {
+	name := filepath.Join(d.root, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return fmt.Errorf("failed to create archive directory: %w", err)
+	}
+	tmp := name + ".tmp"
+	if err := os.WriteFile(tmp, body, 0o644); err != nil {
+		return fmt.Errorf("failed to write archive file: %w", err)
+	}
+	if err := os.Rename(tmp, name); err != nil {
+		return fmt.Errorf("failed to write archive file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package archive
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// Source 可归档的数据库表
+type Source interface {
+	// OldestArchivable returns the oldest time of rows before the given time that can be archived, false if none
+	OldestArchivable(ctx context.Context, table string, before time.Time) (time.Time, bool, error)
+
+	// ExportRows writes archivable rows in [start, end) as JSON lines, returning the row count and the largest id
+	ExportRows(ctx context.Context, table string, start, end time.Time, w io.Writer) (int, int64, error)
+
+	// DeleteRows deletes archivable rows in [start, end) whose id is not greater than maxID
+	DeleteRows(ctx context.Context, table string, start, end time.Time, maxID int64) (int64, error)
+}
+
+// Store 归档文件的存储位置
+type Store interface {
+	// Put uploads an object, overwriting any existing object with the same key
+	Put(ctx context.Context, key string, body []byte) error
+}
+
+// Result 一张表的归档结果
+type Result struct {
+	Table string   `json:"table"`
+	Rows  int      `json:"rows"`  // 导出并删除的行数
+	Files []string `json:"files"` // 上传的文件
+}
@@ -0,0 +1,116 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// S3Options S3 兼容对象存储的访问参数
+type S3Options struct {
+	Endpoint  string // 如 https://s3.us-east-1.amazonaws.com、https://storage.googleapis.com
+	Region    string // 签名使用的区域，GCS 使用 auto
+	Bucket    string
+	AccessKey string
+	SecretKey string
+}
+
+// S3Store 使用 AWS Signature Version 4 签名的 PUT 请求上传归档文件，按路径风格访问存储桶
+type S3Store struct {
+	options S3Options
+	client  *http.Client
+	now     func() time.Time
+}
+
+func NewS3Store(options S3Options) *S3Store {
+	return &S3Store{
+		options: options,
+		client:  &http.Client{Timeout: 5 * time.Minute},
+		now:     time.Now,
+	}
+}
+
+// Put implements Store interface
+func (s *S3Store) Put(ctx context.Context, key string, body []byte) error {
+	endpoint, err := url.Parse(strings.TrimSuffix(s.options.Endpoint, "/"))
+	if err != nil {
+		return fmt.Errorf("invalid endpoint: %w", err)
+	}
+	endpoint.Path += "/" + s.options.Bucket + "/" + key
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = int64(len(body))
+	req.Header.Set("Content-Type", "application/gzip")
+	s.sign(req, body)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("upload returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// sign 按 AWS Signature Version 4 为请求添加 Authorization 头
+func (s *S3Store) sign(req *http.Request, body []byte) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("Host", req.URL.Host)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "content-type;host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := "content-type:" + req.Header.Get("Content-Type") + "\n" +
+		"host:" + req.URL.Host + "\n" +
+		"x-amz-content-sha256:" + payloadHash + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := day + "/" + s.options.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+s.options.SecretKey), day)
+	key = hmacSHA256(key, s.options.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.options.AccessKey, scope, signedHeaders, signature))
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
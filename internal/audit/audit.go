@@ -0,0 +1,57 @@
+package audit
+
+import (
+	"context"
+	"time"
+)
+
+type actorKey struct{}
+
+// WithActor 标记 ctx 中后续操作的发起方
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext 返回 ctx 中的操作发起方，未标记时为系统自动执行
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return ActorAuto
+}
+
+// Log 审计日志，将每条记录追加到所有写入目标；为 nil 时不记录
+type Log struct {
+	sinks  []Sink
+	logger Logger
+}
+
+func NewLog(logger Logger, sinks ...Sink) *Log {
+	return &Log{sinks: sinks, logger: logger}
+}
+
+// Record 记录一次操作，发起方取自 ctx；写入失败只记录日志，不影响操作本身
+func (l *Log) Record(ctx context.Context, action, target, reason string, details map[string]any) {
+	if l == nil {
+		return
+	}
+
+	entry := &Entry{
+		Actor:     ActorFromContext(ctx),
+		Action:    action,
+		Target:    target,
+		Reason:    reason,
+		Details:   details,
+		CreatedAt: time.Now(),
+	}
+
+	// 操作可能发生在请求或关闭阶段，ctx 取消后仍需写入
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	for _, sink := range l.sinks {
+		if err := sink.AppendAudit(ctx, entry); err != nil {
+			l.logger.Error("failed to append audit entry", "action", action, "target", target, "err", err)
+		}
+	}
+}
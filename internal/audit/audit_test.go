@@ -0,0 +1,98 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memorySink struct {
+	entries []Entry
+	err     error
+}
+
+func (m *memorySink) AppendAudit(ctx context.Context, entry *Entry) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.entries = append(m.entries, *entry)
+	return nil
+}
+
+type nopLogger struct{ errors int }
+
+func (l *nopLogger) Error(msg string, fields ...interface{}) { l.errors++ }
+
+func TestActorFromContext(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{name: "default", ctx: context.Background(), want: ActorAuto},
+		{name: "api", ctx: WithActor(context.Background(), ActorAPI), want: ActorAPI},
+		{name: "cli", ctx: WithActor(context.Background(), ActorCLI), want: ActorCLI},
+		{name: "empty", ctx: WithActor(context.Background(), ""), want: ActorAuto},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ActorFromContext(tt.ctx))
+		})
+	}
+}
+
+func TestLog_Record(t *testing.T) {
+	failing := &memorySink{err: errors.New("disk full")}
+	sink := &memorySink{}
+	logger := &nopLogger{}
+	log := NewLog(logger, failing, sink)
+
+	ctx, cancel := context.WithCancel(WithActor(context.Background(), ActorAPI))
+	cancel()
+	log.Record(ctx, ActionPauseSymbol, "BTCUSDT", "manual check", map[string]any{"account": "default"})
+
+	require.Len(t, sink.entries, 1)
+	entry := sink.entries[0]
+	assert.Equal(t, ActorAPI, entry.Actor)
+	assert.Equal(t, ActionPauseSymbol, entry.Action)
+	assert.Equal(t, "BTCUSDT", entry.Target)
+	assert.Equal(t, "manual check", entry.Reason)
+	assert.False(t, entry.CreatedAt.IsZero())
+	assert.Equal(t, 1, logger.errors)
+
+	// nil 审计日志不记录
+	var empty *Log
+	empty.Record(context.Background(), ActionPause, "", "", nil)
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	for i := 0; i < 2; i++ {
+		sink, err := NewFileSink(path)
+		require.NoError(t, err)
+		require.NoError(t, sink.AppendAudit(context.Background(), &Entry{Actor: ActorCLI, Action: ActionResume}))
+		require.NoError(t, sink.Close())
+	}
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var lines int
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry Entry
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		assert.Equal(t, ActionResume, entry.Action)
+		lines++
+	}
+	assert.Equal(t, 2, lines)
+}
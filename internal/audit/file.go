@@ -0,0 +1,44 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// FileSink 以 JSON Lines 格式追加写入审计文件
+type FileSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit file: %w", err)
+	}
+	return &FileSink{file: file}, nil
+}
+
+// AppendAudit implements Sink
+func (f *FileSink) AppendAudit(ctx context.Context, entry *Entry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := f.file.Write(line); err != nil {
+		return fmt.Errorf("failed to write audit file: %w", err)
+	}
+	return f.file.Sync()
+}
+
+func (f *FileSink) Close() error {
+	return f.file.Close()
+}
@@ -0,0 +1,69 @@
+package audit
+
+import (
+	"context"
+	"time"
+)
+
+// 操作发起方
+const (
+	ActorAuto = "auto" // 系统自动执行
+	ActorAPI  = "api"  // 通过 HTTP API
+	ActorCLI  = "cli"  // 通过命令行
+	ActorBot  = "bot"  // 通过聊天机器人
+)
+
+// 审计的操作类型
+const (
+	ActionPlaceOrder        = "place_order"
+	ActionCancelOrder       = "cancel_order"
+	ActionSetRiskParameters = "set_risk_parameters"
+	ActionReloadConfig      = "reload_config"
+	ActionPause             = "pause"
+	ActionResume            = "resume"
+	ActionPauseSymbol       = "pause_symbol"
+	ActionResumeSymbol      = "resume_symbol"
+	ActionDisableSymbol     = "disable_symbol"
+	ActionFlatten           = "flatten"
+	ActionEmergencyClose    = "emergency_close"
+	ActionReducePosition    = "reduce_position"
+	ActionHedge             = "hedge"
+	ActionPromoteSymbol     = "promote_symbol"
+	ActionSnapshot          = "snapshot"
+	ActionRestoreSnapshot   = "restore_snapshot"
+	ActionBotCommand        = "bot_command"
+	ActionUpdateSymbol      = "update_symbol"
+	ActionSetParam          = "set_param"
+	ActionResetParam        = "reset_param"
+	ActionScaleRiskLimits   = "scale_risk_limits"
+)
+
+// Sink 审计记录的追加写入目标，已写入的记录不可修改
+type Sink interface {
+	// AppendAudit appends an entry to the audit log
+	AppendAudit(ctx context.Context, entry *Entry) error
+}
+
+// Storage 可查询的审计日志存储
+type Storage interface {
+	Sink
+
+	// ListAuditEntries returns the most recent entries, newest first
+	ListAuditEntries(ctx context.Context, limit int) ([]Entry, error)
+}
+
+// Entry 审计记录
+type Entry struct {
+	ID        int64          `json:"id"`
+	Actor     string         `json:"actor"`             // auto/api/cli/bot
+	Action    string         `json:"action"`            // 操作类型
+	Target    string         `json:"target"`            // 操作对象，如交易对或账户
+	Reason    string         `json:"reason"`            // 操作原因
+	Details   map[string]any `json:"details,omitempty"` // 操作参数和结果
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// Logger 日志接口
+type Logger interface {
+	Error(msg string, fields ...interface{})
+}
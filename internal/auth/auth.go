@@ -0,0 +1,184 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+// 角色
+const (
+	RoleRead    = "read"    // 只读查询
+	RoleControl = "control" // 查询和交易控制（暂停、清仓、修改风险参数等）
+)
+
+// maxBuckets 限流状态的数量上限，超过时清理已回满的令牌桶，避免按客户端地址限流时无限增长
+const maxBuckets = 10000
+
+var (
+	// ErrUnauthenticated 未携带凭证或凭证无效
+	ErrUnauthenticated = errors.New("missing or invalid credentials")
+	// ErrPermissionDenied 凭证的角色不允许该操作
+	ErrPermissionDenied = errors.New("permission denied for this role")
+	// ErrNotConfigured 未配置任何凭证，控制类操作一律拒绝
+	ErrNotConfigured = errors.New("no api credentials are configured, control endpoints are disabled")
+	// ErrRateLimited 超过请求频率限制
+	ErrRateLimited = errors.New("rate limit exceeded")
+)
+
+// Key 一个 API 密钥
+type Key struct {
+	Name      string // 发起方名称，记录为审计发起方
+	Secret    string
+	Role      string
+	RateLimit int // 每分钟请求数上限，0 时使用默认值
+}
+
+// Principal 认证后的调用方，未认证的只读请求 Name 为空
+type Principal struct {
+	Name string
+	Role string
+}
+
+// Can 判断调用方是否具有 role 的权限，control 包含 read
+func (p Principal) Can(role string) bool {
+	return p.Role == RoleControl || p.Role == role
+}
+
+// Options 认证配置
+type Options struct {
+	Keys        []Key
+	JWTSecret   string // HS256 签名密钥，为空时不接受 JWT
+	RateLimit   int    // 每个调用方每分钟的默认请求数上限，0 为不限流；未认证请求按客户端地址计数
+	RequireAuth bool   // 只读请求也需认证
+}
+
+// Authenticator 校验 API 密钥或 JWT，检查角色并按调用方限流
+type Authenticator struct {
+	opts Options
+	now  func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+// New creates a new Authenticator instance
+func New(opts Options) *Authenticator {
+	return &Authenticator{
+		opts:    opts,
+		now:     time.Now,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// Configured 是否配置了任何凭证
+func (a *Authenticator) Configured() bool {
+	return len(a.opts.Keys) > 0 || a.opts.JWTSecret != ""
+}
+
+// Check 校验 token 是否具有 role 的权限并计入限流，client 为客户端地址；
+// 未要求认证时，不带 token 或 token 无效的只读请求按匿名调用方放行，按客户端地址限流
+func (a *Authenticator) Check(token, role, client string) (Principal, error) {
+	if role == RoleControl && !a.Configured() {
+		return Principal{}, ErrNotConfigured
+	}
+
+	principal, limit, err := a.authenticate(token)
+	if err != nil {
+		if role != RoleRead || a.opts.RequireAuth {
+			return Principal{}, err
+		}
+		if !a.allow("client:"+client, a.opts.RateLimit) {
+			return Principal{}, ErrRateLimited
+		}
+		return Principal{}, nil
+	}
+	if !principal.Can(role) {
+		return Principal{}, ErrPermissionDenied
+	}
+	if !a.allow("principal:"+principal.Name, limit) {
+		return Principal{}, ErrRateLimited
+	}
+	return principal, nil
+}
+
+// authenticate 依次匹配 API 密钥和 JWT，返回调用方及其限流值
+func (a *Authenticator) authenticate(token string) (Principal, int, error) {
+	if token == "" {
+		return Principal{}, 0, ErrUnauthenticated
+	}
+
+	var matched *Key
+	for i, key := range a.opts.Keys {
+		// 比较所有密钥，耗时与匹配位置无关
+		if subtle.ConstantTimeCompare([]byte(token), []byte(key.Secret)) == 1 {
+			matched = &a.opts.Keys[i]
+		}
+	}
+	if matched != nil {
+		limit := matched.RateLimit
+		if limit == 0 {
+			limit = a.opts.RateLimit
+		}
+		return Principal{Name: matched.Name, Role: matched.Role}, limit, nil
+	}
+
+	if a.opts.JWTSecret != "" {
+		claims, err := verifyJWT(token, []byte(a.opts.JWTSecret), a.now())
+		if err == nil {
+			return Principal{Name: claims.Subject, Role: claims.Role}, a.opts.RateLimit, nil
+		}
+	}
+	return Principal{}, 0, ErrUnauthenticated
+}
+
+// bucket 令牌桶，容量为每分钟请求数，按时间匀速补充
+type bucket struct {
+	capacity float64
+	tokens   float64
+	last     time.Time
+}
+
+// refill 返回 now 时桶内的令牌数
+func (b *bucket) refill(now time.Time) float64 {
+	rate := b.capacity / time.Minute.Seconds()
+	return math.Min(b.capacity, b.tokens+now.Sub(b.last).Seconds()*rate)
+}
+
+// allow 从 id 的令牌桶取一个令牌，limit 为 0 时不限流
+func (a *Authenticator) allow(id string, limit int) bool {
+	if limit <= 0 {
+		return true
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	b, ok := a.buckets[id]
+	if !ok || b.capacity != float64(limit) {
+		if len(a.buckets) >= maxBuckets {
+			a.prune(now)
+		}
+		b = &bucket{capacity: float64(limit), tokens: float64(limit), last: now}
+		a.buckets[id] = b
+	}
+	b.tokens = b.refill(now)
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// prune 删除已回满的令牌桶，删除后重新创建的桶状态不变
+func (a *Authenticator) prune(now time.Time) {
+	for id, b := range a.buckets {
+		if b.refill(now) >= b.capacity {
+			delete(a.buckets, id)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAuthenticator(opts Options) (*Authenticator, *time.Time) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := New(opts)
+	a.now = func() time.Time { return now }
+	return a, &now
+}
+
+func TestAuthenticator_Roles(t *testing.T) {
+	a, _ := newTestAuthenticator(Options{Keys: []Key{
+		{Name: "dashboard", Secret: "read-key", Role: RoleRead},
+		{Name: "ops", Secret: "control-key", Role: RoleControl},
+	}})
+
+	p, err := a.Check("read-key", RoleRead, "10.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, Principal{Name: "dashboard", Role: RoleRead}, p)
+
+	_, err = a.Check("read-key", RoleControl, "10.0.0.1")
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+
+	p, err = a.Check("control-key", RoleControl, "10.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, "ops", p.Name)
+	_, err = a.Check("control-key", RoleRead, "10.0.0.1")
+	assert.NoError(t, err)
+
+	_, err = a.Check("wrong", RoleControl, "10.0.0.1")
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+	_, err = a.Check("", RoleControl, "10.0.0.1")
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+
+	// 未要求认证时匿名只读请求放行
+	p, err = a.Check("", RoleRead, "10.0.0.1")
+	require.NoError(t, err)
+	assert.Empty(t, p.Name)
+	p, err = a.Check("wrong", RoleRead, "10.0.0.1")
+	require.NoError(t, err)
+	assert.Empty(t, p.Name)
+}
+
+func TestAuthenticator_RequireAuth(t *testing.T) {
+	a, _ := newTestAuthenticator(Options{RequireAuth: true, Keys: []Key{{Name: "ops", Secret: "k", Role: RoleControl}}})
+	_, err := a.Check("", RoleRead, "10.0.0.1")
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+	_, err = a.Check("wrong", RoleRead, "10.0.0.1")
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+	_, err = a.Check("k", RoleRead, "10.0.0.1")
+	assert.NoError(t, err)
+}
+
+func TestAuthenticator_NotConfigured(t *testing.T) {
+	a, _ := newTestAuthenticator(Options{})
+	assert.False(t, a.Configured())
+	_, err := a.Check("anything", RoleControl, "10.0.0.1")
+	assert.ErrorIs(t, err, ErrNotConfigured)
+	_, err = a.Check("", RoleRead, "10.0.0.1")
+	assert.NoError(t, err)
+}
+
+func TestAuthenticator_RateLimit(t *testing.T) {
+	a, now := newTestAuthenticator(Options{RateLimit: 2, Keys: []Key{
+		{Name: "bot", Secret: "bot-key", Role: RoleRead, RateLimit: 60},
+		{Name: "ops", Secret: "ops-key", Role: RoleControl},
+	}})
+
+	// 使用默认限流值
+	for range 2 {
+		_, err := a.Check("ops-key", RoleControl, "10.0.0.1")
+		require.NoError(t, err)
+	}
+	_, err := a.Check("ops-key", RoleRead, "10.0.0.1")
+	assert.ErrorIs(t, err, ErrRateLimited)
+
+	// 按密钥计数，其他密钥不受影响
+	for range 60 {
+		_, err := a.Check("bot-key", RoleRead, "10.0.0.1")
+		require.NoError(t, err)
+	}
+	_, err = a.Check("bot-key", RoleRead, "10.0.0.1")
+	assert.ErrorIs(t, err, ErrRateLimited)
+
+	// 每秒补充一个令牌
+	*now = now.Add(time.Second)
+	_, err = a.Check("bot-key", RoleRead, "10.0.0.1")
+	assert.NoError(t, err)
+	_, err = a.Check("bot-key", RoleRead, "10.0.0.1")
+	assert.ErrorIs(t, err, ErrRateLimited)
+
+	// 匿名请求按客户端地址计数
+	for range 2 {
+		_, err := a.Check("", RoleRead, "10.0.0.2")
+		require.NoError(t, err)
+	}
+	_, err = a.Check("", RoleRead, "10.0.0.2")
+	assert.ErrorIs(t, err, ErrRateLimited)
+	_, err = a.Check("", RoleRead, "10.0.0.3")
+	assert.NoError(t, err)
+}
+
+func TestAuthenticator_JWT(t *testing.T) {
+	secret := "0123456789abcdef0123456789abcdef"
+	a, now := newTestAuthenticator(Options{JWTSecret: secret})
+
+	token, err := SignJWT(Claims{Subject: "grafana", Role: RoleRead, ExpiresAt: now.Add(time.Hour).Unix()}, []byte(secret))
+	require.NoError(t, err)
+	p, err := a.Check(token, RoleRead, "10.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, Principal{Name: "grafana", Role: RoleRead}, p)
+	_, err = a.Check(token, RoleControl, "10.0.0.1")
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+
+	// 签名密钥不同
+	forged, err := SignJWT(Claims{Subject: "grafana", Role: RoleControl, ExpiresAt: now.Add(time.Hour).Unix()}, []byte("other"))
+	require.NoError(t, err)
+	_, err = a.Check(forged, RoleControl, "10.0.0.1")
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+
+	// 过期后按无效凭证处理
+	*now = now.Add(2 * time.Hour)
+	_, _, err = a.authenticate(token)
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+}
+
+func TestVerifyJWT(t *testing.T) {
+	secret := []byte("secret")
+	now := time.Unix(1700000000, 0)
+
+	for name, claims := range map[string]Claims{
+		"missing sub":  {Role: RoleRead, ExpiresAt: now.Unix() + 60},
+		"unknown role": {Subject: "a", Role: "admin", ExpiresAt: now.Unix() + 60},
+		"missing exp":  {Subject: "a", Role: RoleRead},
+		"not before":   {Subject: "a", Role: RoleRead, ExpiresAt: now.Unix() + 60, NotBefore: now.Unix() + 30},
+	} {
+		token, err := SignJWT(claims, secret)
+		require.NoError(t, err)
+		_, err = verifyJWT(token, secret, now)
+		assert.Error(t, err, name)
+	}
+
+	// alg none 不被接受
+	_, err := verifyJWT("eyJhbGciOiJub25lIn0.eyJzdWIiOiJhIiwicm9sZSI6ImNvbnRyb2wiLCJleHAiOjk5OTk5OTk5OTl9.", secret, now)
+	assert.Error(t, err)
+	_, err = verifyJWT("not-a-jwt", secret, now)
+	assert.Error(t, err)
+}
@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Claims 接受的 JWT 声明，sub 为发起方名称，role 为角色
+type Claims struct {
+	Subject   string `json:"sub"`
+	Role      string `json:"role"`
+	ExpiresAt int64  `json:"exp"`
+	NotBefore int64  `json:"nbf,omitempty"`
+}
+
+// SignJWT 用 HS256 签发 JWT，供运维工具和测试生成令牌
+func SignJWT(claims Claims, secret []byte) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "HS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sign(signingInput, secret)), nil
+}
+
+// verifyJWT 校验 HS256 签名和有效期，必须带 exp，role 必须为已知角色
+func verifyJWT(token string, secret []byte, now time.Time) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed jwt")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("invalid jwt header: %w", err)
+	}
+	// 只接受 HS256，拒绝 none 等算法
+	if header.Alg != "HS256" {
+		return nil, fmt.Errorf("unsupported jwt algorithm %q", header.Alg)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid jwt signature: %w", err)
+	}
+	if !hmac.Equal(signature, sign(parts[0]+"."+parts[1], secret)) {
+		return nil, errors.New("jwt signature mismatch")
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("invalid jwt claims: %w", err)
+	}
+	switch {
+	case claims.Subject == "":
+		return nil, errors.New("jwt sub is required")
+	case claims.Role != RoleRead && claims.Role != RoleControl:
+		return nil, fmt.Errorf("unknown jwt role %q", claims.Role)
+	case claims.ExpiresAt == 0:
+		return nil, errors.New("jwt exp is required")
+	case now.Unix() >= claims.ExpiresAt:
+		return nil, errors.New("jwt has expired")
+	case claims.NotBefore != 0 && now.Unix() < claims.NotBefore:
+		return nil, errors.New("jwt is not valid yet")
+	}
+	return &claims, nil
+}
+
+func sign(signingInput string, secret []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signingInput))
+	return mac.Sum(nil)
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	raw, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
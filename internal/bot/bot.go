@@ -0,0 +1,225 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/analytics"
+	"github.com/songzhibin97/quantaflux/internal/api"
+	"github.com/songzhibin97/quantaflux/internal/audit"
+)
+
+// 默认的盈亏统计区间
+const defaultPnLPeriod = 24 * time.Hour
+
+// PnLSource 按账户统计盈亏，由 analytics.Service 实现
+type PnLSource interface {
+	// AccountPnL returns the PnL of every account between start and end, positions valued at prices
+	AccountPnL(ctx context.Context, start, end time.Time, prices map[string]float64) ([]analytics.AccountPnL, error)
+}
+
+// Logger 日志接口
+type Logger interface {
+	Error(msg string, fields ...interface{})
+	Info(msg string, fields ...interface{})
+}
+
+// Bot 解析聊天消息中的控制命令并在运行中的系统上执行，只接受白名单中的会话，
+// 每条命令（包括被拒绝的命令）都写入审计日志
+type Bot struct {
+	system  api.System
+	pnl     PnLSource
+	audit   *audit.Log
+	allowed map[string]bool
+	logger  Logger
+}
+
+// NewBot creates a new Bot instance, pnl may be nil when analytics is not available
+func NewBot(system api.System, pnl PnLSource, auditLog *audit.Log, allowedChats []string, logger Logger) *Bot {
+	allowed := make(map[string]bool, len(allowedChats))
+	for _, chat := range allowedChats {
+		allowed[chat] = true
+	}
+	return &Bot{system: system, pnl: pnl, audit: auditLog, allowed: allowed, logger: logger}
+}
+
+// Handle 执行一条命令并返回回复内容，channel 为消息来源（telegram/slack），chat 为会话 ID
+func (b *Bot) Handle(ctx context.Context, channel, chat, text string) string {
+	fields := strings.Fields(text)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+		return ""
+	}
+	// Telegram 群组中的命令带有机器人名称，如 /pause@quantaflux_bot
+	command, _, _ := strings.Cut(strings.ToLower(fields[0]), "@")
+	args := fields[1:]
+
+	ctx = audit.WithActor(ctx, audit.ActorBot)
+	details := map[string]any{"channel": channel, "chat": chat, "command": command, "args": args}
+	if !b.allowed[chat] {
+		details["error"] = "unauthorized"
+		b.audit.Record(ctx, audit.ActionBotCommand, command, "unauthorized chat", details)
+		b.logger.Info("rejected bot command from unauthorized chat", "channel", channel, "chat", chat, "command", command)
+		return "Unauthorized: this chat is not allowed to control the system."
+	}
+
+	reply, err := b.execute(ctx, command, args)
+	if err != nil {
+		details["error"] = err.Error()
+		reply = "Error: " + err.Error()
+	}
+	b.audit.Record(ctx, audit.ActionBotCommand, command, channel+" command", details)
+	return reply
+}
+
+// execute 执行已通过鉴权的命令
+func (b *Bot) execute(ctx context.Context, command string, args []string) (string, error) {
+	switch command {
+	case "/help", "/start":
+		return help, nil
+	case "/positions":
+		return b.positions(ctx)
+	case "/pnl":
+		return b.accountPnL(ctx, args)
+	case "/risk":
+		return b.risk(ctx, args)
+	case "/pause":
+		return b.pause(ctx, args), nil
+	case "/resume":
+		return b.resume(ctx, args), nil
+	case "/flatten":
+		return b.flatten(ctx)
+	}
+	return "", fmt.Errorf("unknown command %s, send /help for the list of commands", command)
+}
+
+const help = `Commands:
+/positions - current holdings
+/pnl [period] - account PnL over the period, e.g. /pnl 168h (default 24h)
+/risk [account] - risk state of an account (default primary account)
+/pause [SYMBOL] - pause all trading, or a single symbol
+/resume [SYMBOL] - resume all trading, or a single symbol
+/flatten - pause trading and close all positions`
+
+func (b *Bot) positions(ctx context.Context) (string, error) {
+	positions, err := b.system.Positions(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for _, pos := range positions {
+		if pos.Amount == 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, "%s %s: %g @ %g = %.2f %s\n", pos.Account, pos.Symbol, pos.Amount, pos.Price, pos.Value, pos.Currency)
+	}
+	if sb.Len() == 0 {
+		return "No open positions.", nil
+	}
+	return strings.TrimSuffix(sb.String(), "\n"), nil
+}
+
+func (b *Bot) accountPnL(ctx context.Context, args []string) (string, error) {
+	if b.pnl == nil {
+		return "", fmt.Errorf("analytics is not available")
+	}
+	period := defaultPnLPeriod
+	if len(args) > 0 {
+		d, err := time.ParseDuration(args[0])
+		if err != nil || d <= 0 {
+			return "", fmt.Errorf("invalid period %q, use values like 24h or 168h", args[0])
+		}
+		period = d
+	}
+
+	// 持仓按最新价格估值
+	positions, err := b.system.Positions(ctx)
+	if err != nil {
+		return "", err
+	}
+	prices := make(map[string]float64, len(positions))
+	for _, pos := range positions {
+		prices[pos.Symbol] = pos.Price
+	}
+
+	end := time.Now()
+	result, err := b.pnl.AccountPnL(ctx, end.Add(-period), end, prices)
+	if err != nil {
+		return "", err
+	}
+	if len(result) == 0 {
+		return fmt.Sprintf("No trades in the last %s.", period), nil
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Account < result[j].Account })
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "PnL over the last %s:", period)
+	for _, pnl := range result {
+		fmt.Fprintf(&sb, "\n%s: %.2f %s (%d trades, fees %.2f)", pnl.Account, pnl.PnL, pnl.Currency, pnl.TradeCount, pnl.Fees)
+	}
+	return sb.String(), nil
+}
+
+func (b *Bot) risk(ctx context.Context, args []string) (string, error) {
+	account := ""
+	if len(args) > 0 {
+		account = args[0]
+	}
+	state, err := b.system.RiskState(ctx, account)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Daily loss: %.2f / %.2f\n", state.DailyLoss, state.Parameters.MaxDailyLoss)
+	fmt.Fprintf(&sb, "Trades today: %d, volume %.2f\n", state.DailyTradeCount, state.DailyVolume)
+	fmt.Fprintf(&sb, "Trading paused: %v", b.system.Paused())
+	if paused := b.system.PausedSymbols(); len(paused) > 0 {
+		fmt.Fprintf(&sb, "\nPaused symbols: %s", strings.Join(paused, ", "))
+	}
+	return sb.String(), nil
+}
+
+func (b *Bot) pause(ctx context.Context, args []string) string {
+	if len(args) == 0 {
+		b.system.Pause()
+		b.audit.Record(ctx, audit.ActionPause, "", "bot command", nil)
+		return "Trading paused."
+	}
+	symbol := strings.ToUpper(args[0])
+	b.system.PauseSymbol(symbol)
+	b.audit.Record(ctx, audit.ActionPauseSymbol, symbol, "bot command", nil)
+	return fmt.Sprintf("Trading paused for %s.", symbol)
+}
+
+func (b *Bot) resume(ctx context.Context, args []string) string {
+	if len(args) == 0 {
+		b.system.Resume()
+		b.audit.Record(ctx, audit.ActionResume, "", "bot command", nil)
+		return "Trading resumed."
+	}
+	symbol := strings.ToUpper(args[0])
+	b.system.ResumeSymbol(symbol)
+	b.audit.Record(ctx, audit.ActionResumeSymbol, symbol, "bot command", nil)
+	return fmt.Sprintf("Trading resumed for %s.", symbol)
+}
+
+// flatten 与 API 一致，先暂停下单再平仓，避免平仓后又开新仓
+func (b *Bot) flatten(ctx context.Context) (string, error) {
+	b.system.Pause()
+	b.audit.Record(ctx, audit.ActionPause, "", "flatten", nil)
+
+	err := b.system.Flatten(ctx)
+	details := map[string]any{}
+	if err != nil {
+		details["error"] = err.Error()
+	}
+	b.audit.Record(ctx, audit.ActionFlatten, "", "bot command", details)
+	if err != nil {
+		return "", fmt.Errorf("trading paused, but flatten failed: %w", err)
+	}
+	return "Trading paused and all positions closed.", nil
+}
@@ -0,0 +1,254 @@
+package bot
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/analytics"
+	"github.com/songzhibin97/quantaflux/internal/api"
+	"github.com/songzhibin97/quantaflux/internal/audit"
+	"github.com/songzhibin97/quantaflux/internal/risk"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSystem 只实现机器人用到的方法
+type fakeSystem struct {
+	api.System
+	paused        bool
+	pausedSymbols []string
+	flattenErr    error
+	flattened     bool
+}
+
+func (f *fakeSystem) Positions(ctx context.Context) ([]api.Position, error) {
+	return []api.Position{
+		{Account: "main", Symbol: "BTCUSDT", Asset: "BTC", Amount: 0.5, Price: 100, Value: 50, Currency: "USDT"},
+		{Account: "main", Symbol: "ETHUSDT", Asset: "ETH", Price: 10, Currency: "USDT"},
+	}, nil
+}
+
+func (f *fakeSystem) Pause()                     { f.paused = true }
+func (f *fakeSystem) Resume()                    { f.paused = false }
+func (f *fakeSystem) Paused() bool               { return f.paused }
+func (f *fakeSystem) PauseSymbol(symbol string)  { f.pausedSymbols = append(f.pausedSymbols, symbol) }
+func (f *fakeSystem) ResumeSymbol(symbol string) { f.pausedSymbols = nil }
+func (f *fakeSystem) PausedSymbols() []string    { return f.pausedSymbols }
+
+func (f *fakeSystem) Flatten(ctx context.Context) error {
+	f.flattened = true
+	return f.flattenErr
+}
+
+func (f *fakeSystem) RiskState(ctx context.Context, account string) (*risk.RiskState, error) {
+	if account != "" && account != "main" {
+		return nil, api.ErrAccountNotFound
+	}
+	return &risk.RiskState{Parameters: risk.RiskParameters{MaxDailyLoss: 500}, DailyLoss: 120, DailyTradeCount: 3, DailyVolume: 900}, nil
+}
+
+type fakePnL struct {
+	prices map[string]float64
+	start  time.Time
+	end    time.Time
+}
+
+func (f *fakePnL) AccountPnL(ctx context.Context, start, end time.Time, prices map[string]float64) ([]analytics.AccountPnL, error) {
+	f.prices, f.start, f.end = prices, start, end
+	return []analytics.AccountPnL{{Account: "main", TradeCount: 4, Fees: 1.5, PnL: 42, Currency: "USDT"}}, nil
+}
+
+type memorySink struct {
+	entries []audit.Entry
+}
+
+func (m *memorySink) AppendAudit(ctx context.Context, entry *audit.Entry) error {
+	m.entries = append(m.entries, *entry)
+	return nil
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Error(msg string, fields ...interface{}) {}
+func (nopLogger) Info(msg string, fields ...interface{})  {}
+
+func newTestBot() (*Bot, *fakeSystem, *fakePnL, *memorySink) {
+	system := &fakeSystem{}
+	pnl := &fakePnL{}
+	sink := &memorySink{}
+	return NewBot(system, pnl, audit.NewLog(nopLogger{}, sink), []string{"100"}, nopLogger{}), system, pnl, sink
+}
+
+func TestBot_Handle(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("unauthorized chat", func(t *testing.T) {
+		bot, system, _, sink := newTestBot()
+		reply := bot.Handle(ctx, "telegram", "200", "/pause")
+		assert.Contains(t, reply, "Unauthorized")
+		assert.False(t, system.paused)
+		require.Len(t, sink.entries, 1)
+		assert.Equal(t, audit.ActorBot, sink.entries[0].Actor)
+		assert.Equal(t, audit.ActionBotCommand, sink.entries[0].Action)
+		assert.Equal(t, "unauthorized", sink.entries[0].Details["error"])
+	})
+
+	t.Run("plain message ignored", func(t *testing.T) {
+		bot, _, _, sink := newTestBot()
+		assert.Empty(t, bot.Handle(ctx, "telegram", "100", "hello"))
+		assert.Empty(t, sink.entries)
+	})
+
+	t.Run("pause symbol", func(t *testing.T) {
+		bot, system, _, sink := newTestBot()
+		reply := bot.Handle(ctx, "telegram", "100", "/pause@quantaflux_bot btcusdt")
+		assert.Equal(t, "Trading paused for BTCUSDT.", reply)
+		assert.Equal(t, []string{"BTCUSDT"}, system.pausedSymbols)
+		require.Len(t, sink.entries, 2)
+		assert.Equal(t, audit.ActionPauseSymbol, sink.entries[0].Action)
+		assert.Equal(t, "BTCUSDT", sink.entries[0].Target)
+		assert.Equal(t, "/pause", sink.entries[1].Target)
+		assert.Equal(t, "100", sink.entries[1].Details["chat"])
+	})
+
+	t.Run("flatten", func(t *testing.T) {
+		bot, system, _, sink := newTestBot()
+		system.flattenErr = errors.New("insufficient balance")
+		reply := bot.Handle(ctx, "telegram", "100", "/flatten")
+		assert.True(t, system.paused)
+		assert.True(t, system.flattened)
+		assert.Contains(t, reply, "flatten failed: insufficient balance")
+		assert.Equal(t, audit.ActionFlatten, sink.entries[1].Action)
+		assert.Contains(t, sink.entries[2].Details["error"], "insufficient balance")
+	})
+
+	t.Run("positions", func(t *testing.T) {
+		bot, _, _, _ := newTestBot()
+		assert.Equal(t, "main BTCUSDT: 0.5 @ 100 = 50.00 USDT", bot.Handle(ctx, "telegram", "100", "/positions"))
+	})
+
+	t.Run("pnl", func(t *testing.T) {
+		bot, _, pnl, _ := newTestBot()
+		reply := bot.Handle(ctx, "telegram", "100", "/pnl 168h")
+		assert.Equal(t, "PnL over the last 168h0m0s:\nmain: 42.00 USDT (4 trades, fees 1.50)", reply)
+		assert.Equal(t, 168*time.Hour, pnl.end.Sub(pnl.start))
+		assert.Equal(t, map[string]float64{"BTCUSDT": 100, "ETHUSDT": 10}, pnl.prices)
+
+		assert.Contains(t, bot.Handle(ctx, "telegram", "100", "/pnl week"), "invalid period")
+	})
+
+	t.Run("risk", func(t *testing.T) {
+		bot, system, _, _ := newTestBot()
+		system.pausedSymbols = []string{"ETHUSDT"}
+		reply := bot.Handle(ctx, "telegram", "100", "/risk")
+		assert.Equal(t, "Daily loss: 120.00 / 500.00\nTrades today: 3, volume 900.00\nTrading paused: false\nPaused symbols: ETHUSDT", reply)
+		assert.Contains(t, bot.Handle(ctx, "telegram", "100", "/risk other"), api.ErrAccountNotFound.Error())
+	})
+
+	t.Run("unknown command", func(t *testing.T) {
+		bot, _, _, _ := newTestBot()
+		assert.Contains(t, bot.Handle(ctx, "telegram", "100", "/buy BTCUSDT"), "unknown command /buy")
+	})
+}
+
+func TestTelegramPoller(t *testing.T) {
+	bot, system, _, _ := newTestBot()
+
+	var sent []map[string]string
+	var offsets []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/bottoken/getUpdates":
+			offsets = append(offsets, r.URL.Query().Get("offset"))
+			_, _ = w.Write([]byte(`{"ok":true,"result":[
+				{"update_id":7,"message":{"text":"/pause","chat":{"id":100}}},
+				{"update_id":8,"message":{"text":"/resume","chat":{"id":200}}},
+				{"update_id":9}
+			]}`))
+		case "/bottoken/sendMessage":
+			var body map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			sent = append(sent, body)
+		}
+	}))
+	defer server.Close()
+
+	poller := NewTelegramPoller("token", bot, nopLogger{})
+	poller.endpoint = server.URL
+	require.NoError(t, poller.poll(context.Background()))
+	require.NoError(t, poller.poll(context.Background()))
+
+	assert.Equal(t, []string{"0", "10"}, offsets)
+	assert.True(t, system.paused)
+	require.Len(t, sent, 4)
+	assert.Equal(t, map[string]string{"chat_id": "100", "text": "Trading paused."}, sent[0])
+	assert.Equal(t, "200", sent[1]["chat_id"])
+	assert.Contains(t, sent[1]["text"], "Unauthorized")
+
+	// 请求失败时错误中不包含 token
+	server.Close()
+	poller = NewTelegramPoller("123:secret", bot, nopLogger{})
+	poller.endpoint = server.URL
+	err := poller.poll(context.Background())
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "secret")
+	err = poller.send(context.Background(), "100", "ok")
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "secret")
+}
+
+func TestSlackHandler(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	sign := func(timestamp int64, body string) string {
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write([]byte("v0:" + strconv.FormatInt(timestamp, 10) + ":" + body))
+		return "v0=" + hex.EncodeToString(mac.Sum(nil))
+	}
+	body := url.Values{"command": {"/pause"}, "text": {"ETHUSDT"}, "channel_id": {"100"}}.Encode()
+
+	tests := []struct {
+		name       string
+		timestamp  int64
+		signature  string
+		wantStatus int
+	}{
+		{name: "valid", timestamp: now.Unix(), signature: sign(now.Unix(), body), wantStatus: http.StatusOK},
+		{name: "bad signature", timestamp: now.Unix(), signature: sign(now.Unix(), body+"x"), wantStatus: http.StatusUnauthorized},
+		{name: "replayed", timestamp: now.Add(-10 * time.Minute).Unix(), signature: sign(now.Add(-10*time.Minute).Unix(), body), wantStatus: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot, system, _, _ := newTestBot()
+			handler := NewSlackHandler(bot, "secret")
+			handler.now = func() time.Time { return now }
+
+			req := httptest.NewRequest(http.MethodPost, "/bot/slack", strings.NewReader(body))
+			req.Header.Set("X-Slack-Request-Timestamp", strconv.FormatInt(tt.timestamp, 10))
+			req.Header.Set("X-Slack-Signature", tt.signature)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus != http.StatusOK {
+				assert.Empty(t, system.pausedSymbols)
+				return
+			}
+			var reply map[string]string
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&reply))
+			assert.Equal(t, "Trading paused for ETHUSDT.", reply["text"])
+			assert.Equal(t, []string{"ETHUSDT"}, system.pausedSymbols)
+		})
+	}
+}
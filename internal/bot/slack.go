@@ -0,0 +1,71 @@
+package bot
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// 签名时间戳与当前时间的最大偏差，超过时视为重放请求
+const slackMaxClockSkew = 5 * time.Minute
+
+// SlackHandler 接收 Slack slash command（如 /pause BTCUSDT），校验请求签名后交给 Bot 处理，
+// 以 channel_id 作为会话 ID 鉴权；Slack 要求 3 秒内响应，回复只对发送者可见
+type SlackHandler struct {
+	bot           *Bot
+	signingSecret string
+	now           func() time.Time
+}
+
+// NewSlackHandler creates a new SlackHandler instance
+func NewSlackHandler(bot *Bot, signingSecret string) *SlackHandler {
+	return &SlackHandler{bot: bot, signingSecret: signingSecret, now: time.Now}
+}
+
+// ServeHTTP implements http.Handler
+func (h *SlackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, "failed to read request", http.StatusBadRequest)
+		return
+	}
+	if !h.verify(r.Header, body) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+
+	text := form.Get("command")
+	if args := form.Get("text"); args != "" {
+		text += " " + args
+	}
+	reply := h.bot.Handle(r.Context(), "slack", form.Get("channel_id"), text)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]string{"response_type": "ephemeral", "text": reply})
+}
+
+// verify 按 Slack 签名规则校验请求：v0=HMAC-SHA256(signing_secret, "v0:timestamp:body")
+func (h *SlackHandler) verify(header http.Header, body []byte) bool {
+	timestamp, err := strconv.ParseInt(header.Get("X-Slack-Request-Timestamp"), 10, 64)
+	if err != nil || math.Abs(h.now().Sub(time.Unix(timestamp, 0)).Seconds()) > slackMaxClockSkew.Seconds() {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(h.signingSecret))
+	mac.Write([]byte("v0:" + strconv.FormatInt(timestamp, 10) + ":"))
+	mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(header.Get("X-Slack-Signature")))
+}
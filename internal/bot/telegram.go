@@ -0,0 +1,115 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/utils/request"
+)
+
+const (
+	telegramEndpoint = "https://api.telegram.org"
+	// getUpdates 长轮询的等待时间（秒）
+	telegramPollTimeout = 30
+	// 拉取失败后的重试间隔
+	telegramRetryDelay = 5 * time.Second
+)
+
+// TelegramPoller 通过 getUpdates 长轮询接收 Telegram 消息，命令交给 Bot 处理后回复到原会话；
+// 使用 getUpdates 时 Bot 不能设置 webhook
+type TelegramPoller struct {
+	endpoint string
+	token    string
+	bot      *Bot
+	logger   Logger
+	offset   int64 // 下一条待处理更新的 ID
+}
+
+// NewTelegramPoller creates a new TelegramPoller instance
+func NewTelegramPoller(token string, bot *Bot, logger Logger) *TelegramPoller {
+	return &TelegramPoller{endpoint: telegramEndpoint, token: token, bot: bot, logger: logger}
+}
+
+type telegramUpdates struct {
+	OK          bool             `json:"ok"`
+	Description string           `json:"description"`
+	Result      []telegramUpdate `json:"result"`
+}
+
+type telegramUpdate struct {
+	UpdateID int64 `json:"update_id"`
+	Message  *struct {
+		Text string `json:"text"`
+		Chat struct {
+			ID int64 `json:"id"`
+		} `json:"chat"`
+	} `json:"message"`
+}
+
+// Run 持续接收并处理命令，直到 ctx 取消
+func (p *TelegramPoller) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		if err := p.poll(ctx); err != nil && ctx.Err() == nil {
+			p.logger.Error("failed to poll telegram updates", "err", err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(telegramRetryDelay):
+			}
+		}
+	}
+}
+
+// poll 拉取一批更新并逐条处理，处理后推进 offset，Telegram 据此确认已收到的更新
+func (p *TelegramPoller) poll(ctx context.Context) error {
+	resp, err := request.Request.R().
+		SetContext(ctx).
+		SetQueryParams(map[string]string{
+			"offset":          strconv.FormatInt(p.offset, 10),
+			"timeout":         strconv.Itoa(telegramPollTimeout),
+			"allowed_updates": `["message"]`,
+		}).
+		Get(fmt.Sprintf("%s/bot%s/getUpdates", p.endpoint, p.token))
+	if err != nil {
+		return request.RedactURL(err)
+	}
+	var updates telegramUpdates
+	if err := json.Unmarshal(resp.Body(), &updates); err != nil {
+		return fmt.Errorf("failed to decode updates: status=%d, err=%w", resp.StatusCode(), err)
+	}
+	if resp.IsError() || !updates.OK {
+		return fmt.Errorf("getUpdates failed: status=%d, description=%s", resp.StatusCode(), updates.Description)
+	}
+
+	for _, update := range updates.Result {
+		p.offset = update.UpdateID + 1
+		if update.Message == nil {
+			continue
+		}
+		chat := strconv.FormatInt(update.Message.Chat.ID, 10)
+		reply := p.bot.Handle(ctx, "telegram", chat, update.Message.Text)
+		if reply == "" {
+			continue
+		}
+		if err := p.send(ctx, chat, reply); err != nil {
+			p.logger.Error("failed to send telegram reply", "chat", chat, "err", err)
+		}
+	}
+	return nil
+}
+
+func (p *TelegramPoller) send(ctx context.Context, chat, text string) error {
+	resp, err := request.Request.R().
+		SetContext(ctx).
+		SetBody(map[string]string{"chat_id": chat, "text": text}).
+		Post(fmt.Sprintf("%s/bot%s/sendMessage", p.endpoint, p.token))
+	if err != nil {
+		return request.RedactURL(err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("sendMessage failed: status=%d, body=%s", resp.StatusCode(), resp.String())
+	}
+	return nil
+}
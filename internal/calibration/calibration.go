@@ -0,0 +1,173 @@
+package calibration
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Options 校准参数
+type Options struct {
+	Window     time.Duration // 只统计该时长内到期的预测
+	Buckets    int           // 置信度区间数量，默认 10，即 [0,0.1)、[0.1,0.2) ...
+	MinSamples int           // 区间内到期预测少于该数量时不校准，默认 20
+}
+
+// Bucket 一个置信度区间的预测结果统计
+type Bucket struct {
+	Lower       float64 `json:"lower"`
+	Upper       float64 `json:"upper"`
+	Predictions int     `json:"predictions"`
+	Hits        int     `json:"hits"`     // 涨跌方向预测正确的数量
+	HitRate     float64 `json:"hit_rate"` // 经验准确率，即校准后的置信度
+}
+
+// pending 尚未到期的预测
+type pending struct {
+	due        time.Time
+	price      float64 // 预测时的价格
+	direction  float64 // 预测涨为 1，跌为 -1
+	confidence float64
+}
+
+// outcome 到期预测的结果
+type outcome struct {
+	at     time.Time
+	bucket int
+	hit    bool
+}
+
+// Calibrator 统计模型给出的置信度与实际方向准确率的对应关系，将原始置信度替换为同一区间内的经验准确率。
+// 预测在时间范围到期后按到期时刻之后的第一条行情判断方向是否正确，时间取行情时间，回测与实盘一致
+type Calibrator struct {
+	mu       sync.Mutex
+	options  Options
+	pending  map[string][]pending // 交易对 -> 按到期时间排序的预测
+	outcomes []outcome            // 按到期时间排序
+}
+
+// New creates a new Calibrator instance
+func New(options Options) *Calibrator {
+	if options.Buckets <= 0 {
+		options.Buckets = 10
+	}
+	if options.MinSamples <= 0 {
+		options.MinSamples = 20
+	}
+	return &Calibrator{options: options, pending: make(map[string][]pending)}
+}
+
+// Record 记录一次预测，预测价格等于当前价格时没有方向，不参与统计
+func (c *Calibrator) Record(symbol string, at time.Time, price, predicted, confidence float64, horizon time.Duration) {
+	if price <= 0 || predicted == price {
+		return
+	}
+	p := pending{due: at.Add(horizon), price: price, direction: 1, confidence: confidence}
+	if predicted < price {
+		p.direction = -1
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	queue := c.pending[symbol]
+	i := len(queue)
+	for i > 0 && queue[i-1].due.After(p.due) {
+		i--
+	}
+	c.pending[symbol] = append(queue[:i], append([]pending{p}, queue[i:]...)...)
+}
+
+// Observe 用最新行情判断已到期的预测
+func (c *Calibrator) Observe(symbol string, at time.Time, price float64) {
+	if price <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	queue := c.pending[symbol]
+	resolved := 0
+	for _, p := range queue {
+		if p.due.After(at) {
+			break
+		}
+		c.outcomes = append(c.outcomes, outcome{
+			at:     at,
+			bucket: c.bucket(p.confidence),
+			hit:    p.direction*(price-p.price) > 0,
+		})
+		resolved++
+	}
+	c.pending[symbol] = queue[resolved:]
+	c.prune(at)
+}
+
+// Calibrate 返回置信度所在区间的经验准确率，样本不足时返回原始置信度
+func (c *Calibrator) Calibrate(confidence float64, now time.Time) float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.prune(now)
+	bucket := c.bucket(confidence)
+	var predictions, hits int
+	for _, o := range c.outcomes {
+		if o.bucket != bucket {
+			continue
+		}
+		predictions++
+		if o.hit {
+			hits++
+		}
+	}
+	if predictions < c.options.MinSamples {
+		return confidence
+	}
+	return float64(hits) / float64(predictions)
+}
+
+// Buckets 返回各置信度区间在统计窗口内的预测结果
+func (c *Calibrator) Buckets(now time.Time) []Bucket {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.prune(now)
+	width := 1 / float64(c.options.Buckets)
+	buckets := make([]Bucket, c.options.Buckets)
+	for i := range buckets {
+		buckets[i].Lower = float64(i) * width
+		buckets[i].Upper = float64(i+1) * width
+	}
+	for _, o := range c.outcomes {
+		buckets[o.bucket].Predictions++
+		if o.hit {
+			buckets[o.bucket].Hits++
+		}
+	}
+	for i := range buckets {
+		if buckets[i].Predictions > 0 {
+			buckets[i].HitRate = float64(buckets[i].Hits) / float64(buckets[i].Predictions)
+		}
+	}
+	return buckets
+}
+
+// bucket 返回置信度所在的区间，置信度 1 归入最后一个区间
+func (c *Calibrator) bucket(confidence float64) int {
+	i := int(math.Floor(confidence * float64(c.options.Buckets)))
+	return min(max(i, 0), c.options.Buckets-1)
+}
+
+// prune 移除统计窗口之外的结果，调用方需持有锁
+func (c *Calibrator) prune(now time.Time) {
+	if c.options.Window <= 0 {
+		return
+	}
+	cutoff := now.Add(-c.options.Window)
+	i := 0
+	for i < len(c.outcomes) && c.outcomes[i].at.Before(cutoff) {
+		i++
+	}
+	c.outcomes = c.outcomes[i:]
+}
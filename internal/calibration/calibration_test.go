@@ -0,0 +1,58 @@
+package calibration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalibrator(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := New(Options{Window: 24 * time.Hour, MinSamples: 4})
+
+	// 置信度 0.9 的预测只有一半方向正确
+	for i := 0; i < 4; i++ {
+		at := start.Add(time.Duration(i) * time.Minute)
+		c.Record("BTCUSDT", at, 100, 110, 0.9, time.Hour)
+	}
+	c.Record("BTCUSDT", start, 100, 100, 0.95, time.Hour) // 没有方向，不统计
+
+	// 未到期的预测不统计
+	c.Observe("BTCUSDT", start.Add(30*time.Minute), 120)
+	assert.InDelta(t, 0.9, c.Calibrate(0.9, start.Add(30*time.Minute)), 1e-9)
+
+	c.Observe("BTCUSDT", start.Add(time.Hour+time.Minute), 105)
+	c.Observe("ETHUSDT", start.Add(time.Hour+2*time.Minute), 90)
+	c.Observe("BTCUSDT", start.Add(time.Hour+3*time.Minute), 95)
+	now := start.Add(2 * time.Hour)
+	assert.InDelta(t, 0.5, c.Calibrate(0.9, now), 1e-9)
+	assert.InDelta(t, 0.5, c.Calibrate(0.95, now), 1e-9)
+	// 其他区间样本不足，保留原始置信度
+	assert.InDelta(t, 0.7, c.Calibrate(0.7, now), 1e-9)
+
+	buckets := c.Buckets(now)
+	require.Len(t, buckets, 10)
+	assert.Equal(t, Bucket{Lower: 0.9, Upper: 1, Predictions: 4, Hits: 2, HitRate: 0.5}, roundBucket(buckets[9]))
+
+	// 超出统计窗口的结果不再参与校准
+	assert.InDelta(t, 0.9, c.Calibrate(0.9, start.Add(26*time.Hour)), 1e-9)
+	assert.Zero(t, c.Buckets(start.Add(26 * time.Hour))[9].Predictions)
+}
+
+func TestCalibrator_ShortPrediction(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := New(Options{Window: time.Hour, Buckets: 2, MinSamples: 1})
+
+	c.Record("BTCUSDT", start, 100, 90, 0.6, 5*time.Minute)
+	c.Observe("BTCUSDT", start.Add(5*time.Minute), 80)
+	assert.InDelta(t, 1, c.Calibrate(0.6, start.Add(5*time.Minute)), 1e-9)
+	assert.InDelta(t, 0.2, c.Calibrate(0.2, start.Add(5*time.Minute)), 1e-9)
+}
+
+func roundBucket(b Bucket) Bucket {
+	round := func(v float64) float64 { return float64(int(v*1e6+0.5)) / 1e6 }
+	b.Lower, b.Upper, b.HitRate = round(b.Lower), round(b.Upper), round(b.HitRate)
+	return b
+}
@@ -0,0 +1,126 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/ai"
+	"github.com/songzhibin97/quantaflux/internal/data"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+)
+
+// ErrInjected 故障注入产生的错误，同时包装对应组件的领域错误，按相同的错误类别处理
+var ErrInjected = errors.New("injected fault")
+
+// Target 注入故障的组件
+type Target string
+
+const (
+	TargetCollector Target = "collector" // 行情和代币信息采集
+	TargetStorage   Target = "storage"   // 行情存储
+	TargetAI        Target = "ai"        // AI 分析
+	TargetExchange  Target = "exchange"  // 交易所下单和查询
+)
+
+// Fault 一个组件的故障参数，零值表示不注入
+type Fault struct {
+	Rate    float64       // 每次调用失败的概率
+	Latency time.Duration // 每次调用额外增加的延迟
+	Outage  time.Duration // 失败后该组件持续不可用的时长，为 0 时只有本次调用失败
+}
+
+// Options 故障注入参数
+type Options struct {
+	Faults map[Target]Fault
+	Seed   int64 // 随机数种子，为 0 时每次运行不同
+}
+
+// Injector 按概率让组件调用变慢或失败，用于在模拟交易中验证系统的故障处理。可并发使用
+type Injector struct {
+	// OnFault 注入失败时调用，可为空
+	OnFault func(target Target, err error)
+
+	faults map[Target]Fault
+	now    func() time.Time
+
+	mu     sync.Mutex
+	rng    *rand.Rand
+	outage map[Target]time.Time // 各组件不可用的截止时间
+}
+
+func New(opts Options) *Injector {
+	seed := opts.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &Injector{
+		faults: opts.Faults,
+		now:    time.Now,
+		rng:    rand.New(rand.NewSource(seed)),
+		outage: make(map[Target]time.Time),
+	}
+}
+
+// Inject 在组件调用前执行：先等待配置的延迟，再按概率返回该组件的故障错误，
+// 处于故障持续时间内时直接返回错误
+func (i *Injector) Inject(ctx context.Context, target Target) error {
+	fault, ok := i.faults[target]
+	if !ok {
+		return nil
+	}
+
+	if fault.Latency > 0 {
+		timer := time.NewTimer(fault.Latency)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	if !i.fail(target, fault) {
+		return nil
+	}
+	err := fmt.Errorf("%w: %w", ErrInjected, targetError(target))
+	if i.OnFault != nil {
+		i.OnFault(target, err)
+	}
+	return err
+}
+
+// fail 判断本次调用是否失败，失败时开始故障持续时间
+func (i *Injector) fail(target Target, fault Fault) bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	now := i.now()
+	if now.Before(i.outage[target]) {
+		return true
+	}
+	if fault.Rate <= 0 || i.rng.Float64() >= fault.Rate {
+		return false
+	}
+	if fault.Outage > 0 {
+		i.outage[target] = now.Add(fault.Outage)
+	}
+	return true
+}
+
+// targetError 返回组件故障对应的领域错误
+func targetError(target Target) error {
+	switch target {
+	case TargetCollector:
+		return fmt.Errorf("%w: collector outage", data.ErrSourceUnavailable)
+	case TargetAI:
+		return fmt.Errorf("%w: request timed out", ai.ErrProviderUnavailable)
+	case TargetExchange:
+		return fmt.Errorf("%w: 503 Service Unavailable", trading.ErrExchangeUnavailable)
+	default:
+		return fmt.Errorf("%s unavailable", target)
+	}
+}
@@ -0,0 +1,77 @@
+package chaos
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/ai"
+	"github.com/songzhibin97/quantaflux/internal/data"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+	"github.com/songzhibin97/quantaflux/internal/trading/paper"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInjector(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	injector := New(Options{Seed: 1, Faults: map[Target]Fault{
+		TargetCollector: {Rate: 1, Outage: time.Minute},
+		TargetAI:        {Latency: time.Hour},
+	}})
+	injector.now = func() time.Time { return now }
+	var injected []Target
+	injector.OnFault = func(target Target, err error) { injected = append(injected, target) }
+
+	// 失败后在 outage 内持续不可用
+	err := injector.Inject(ctx, TargetCollector)
+	assert.ErrorIs(t, err, ErrInjected)
+	assert.ErrorIs(t, err, data.ErrSourceUnavailable)
+	injector.faults[TargetCollector] = Fault{Outage: time.Minute}
+	now = now.Add(30 * time.Second)
+	assert.Error(t, injector.Inject(ctx, TargetCollector))
+	now = now.Add(time.Minute)
+	assert.NoError(t, injector.Inject(ctx, TargetCollector))
+	assert.Equal(t, []Target{TargetCollector, TargetCollector}, injected)
+
+	// 延迟超过调用的超时时间时返回超时
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, injector.Inject(timeout, TargetAI), context.DeadlineExceeded)
+
+	// 未配置的组件不受影响
+	assert.NoError(t, injector.Inject(ctx, TargetExchange))
+}
+
+func TestExecutor(t *testing.T) {
+	ctx := context.Background()
+	simulated := paper.NewPaperExecutor(map[string]float64{"USDT": 1000})
+	injector := New(Options{Faults: map[Target]Fault{TargetExchange: {Rate: 1}}})
+	executor := trading.Chain(simulated, Middleware(injector))
+
+	err := executor.PlaceOrder(ctx, &trading.Order{Symbol: "BTCUSDT", Side: "buy", OrderType: "market", Amount: 0.01})
+	assert.ErrorIs(t, err, trading.ErrExchangeUnavailable)
+	_, err = executor.GetBalance(ctx, "USDT")
+	assert.ErrorIs(t, err, trading.ErrExchangeUnavailable)
+
+	// 模拟执行器的其余功能通过 As 访问
+	_, ok := trading.As[trading.StatefulExecutor](executor)
+	assert.True(t, ok)
+	_, ok = trading.As[trading.MarketPriceUpdater](executor)
+	assert.True(t, ok)
+
+	injector.faults[TargetExchange] = Fault{}
+	balance, err := executor.GetBalance(ctx, "USDT")
+	require.NoError(t, err)
+	assert.Equal(t, 1000.0, balance)
+}
+
+func TestAnalyzer(t *testing.T) {
+	injector := New(Options{Faults: map[Target]Fault{TargetAI: {Rate: 1}}})
+	analyzer := NewAnalyzer(nil, injector)
+
+	_, err := analyzer.PredictPrice(context.Background(), nil)
+	assert.ErrorIs(t, err, ai.ErrProviderUnavailable)
+}
@@ -0,0 +1,194 @@
+package chaos
+
+import (
+	"context"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/ai"
+	"github.com/songzhibin97/quantaflux/internal/data"
+	"github.com/songzhibin97/quantaflux/internal/models"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+)
+
+// Collector 注入采集故障的数据源，故障期间实时行情被丢弃，模拟数据源中断
+type Collector struct {
+	data.DataCollector
+	injector *Injector
+}
+
+func NewCollector(collector data.DataCollector, injector *Injector) *Collector {
+	return &Collector{DataCollector: collector, injector: injector}
+}
+
+func (c *Collector) CollectTokenInfo(ctx context.Context, symbol string) (*models.TokenInfo, error) {
+	if err := c.injector.Inject(ctx, TargetCollector); err != nil {
+		return nil, err
+	}
+	return c.DataCollector.CollectTokenInfo(ctx, symbol)
+}
+
+func (c *Collector) CollectMarketData(ctx context.Context, symbol string) (*models.MarketData, error) {
+	if err := c.injector.Inject(ctx, TargetCollector); err != nil {
+		return nil, err
+	}
+	return c.DataCollector.CollectMarketData(ctx, symbol)
+}
+
+func (c *Collector) CollectSocialMetrics(ctx context.Context, symbol string) (map[string]float64, error) {
+	if err := c.injector.Inject(ctx, TargetCollector); err != nil {
+		return nil, err
+	}
+	return c.DataCollector.CollectSocialMetrics(ctx, symbol)
+}
+
+func (c *Collector) SubscribeToMarketData(ctx context.Context, subscriptions []data.Subscription) (<-chan models.MarketData, error) {
+	if err := c.injector.Inject(ctx, TargetCollector); err != nil {
+		return nil, err
+	}
+	updates, err := c.DataCollector.SubscribeToMarketData(ctx, subscriptions)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan models.MarketData, cap(updates))
+	go func() {
+		defer close(out)
+		for update := range updates {
+			if err := c.injector.Inject(ctx, TargetCollector); err != nil {
+				continue
+			}
+			select {
+			case out <- update:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// Storage 注入延迟和读写失败的行情存储，策略状态不受影响
+type Storage struct {
+	data.DataStorage
+	injector *Injector
+}
+
+func NewStorage(storage data.DataStorage, injector *Injector) *Storage {
+	return &Storage{DataStorage: storage, injector: injector}
+}
+
+func (s *Storage) SaveTokenInfo(ctx context.Context, info *models.TokenInfo) error {
+	if err := s.injector.Inject(ctx, TargetStorage); err != nil {
+		return err
+	}
+	return s.DataStorage.SaveTokenInfo(ctx, info)
+}
+
+func (s *Storage) SaveMarketData(ctx context.Context, md *models.MarketData) error {
+	if err := s.injector.Inject(ctx, TargetStorage); err != nil {
+		return err
+	}
+	return s.DataStorage.SaveMarketData(ctx, md)
+}
+
+func (s *Storage) GetHistoricalData(ctx context.Context, symbol string, start, end time.Time) ([]models.MarketData, error) {
+	if err := s.injector.Inject(ctx, TargetStorage); err != nil {
+		return nil, err
+	}
+	return s.DataStorage.GetHistoricalData(ctx, symbol, start, end)
+}
+
+func (s *Storage) GetProjectMetrics(ctx context.Context, symbol string) (*models.ProjectMetrics, error) {
+	if err := s.injector.Inject(ctx, TargetStorage); err != nil {
+		return nil, err
+	}
+	return s.DataStorage.GetProjectMetrics(ctx, symbol)
+}
+
+// Analyzer 注入延迟和超时的 AI 分析器
+type Analyzer struct {
+	analyzer ai.Analyzer
+	injector *Injector
+}
+
+func NewAnalyzer(analyzer ai.Analyzer, injector *Injector) *Analyzer {
+	return &Analyzer{analyzer: analyzer, injector: injector}
+}
+
+// Unwrap 返回被注入故障的分析器
+func (a *Analyzer) Unwrap() ai.Analyzer {
+	return a.analyzer
+}
+
+func (a *Analyzer) AnalyzeProject(ctx context.Context, info *models.TokenInfo) (*models.ProjectMetrics, error) {
+	if err := a.injector.Inject(ctx, TargetAI); err != nil {
+		return nil, err
+	}
+	return a.analyzer.AnalyzeProject(ctx, info)
+}
+
+func (a *Analyzer) PredictPrice(ctx context.Context, data []models.MarketData) (*ai.PricePrediction, error) {
+	if err := a.injector.Inject(ctx, TargetAI); err != nil {
+		return nil, err
+	}
+	return a.analyzer.PredictPrice(ctx, data)
+}
+
+func (a *Analyzer) AnalyzeSentiment(ctx context.Context, socialData map[string]string) (float64, error) {
+	if err := a.injector.Inject(ctx, TargetAI); err != nil {
+		return 0, err
+	}
+	return a.analyzer.AnalyzeSentiment(ctx, socialData)
+}
+
+func (a *Analyzer) DetectScam(ctx context.Context, projectData *models.ProjectMetrics) (*ai.ScamAnalysis, error) {
+	if err := a.injector.Inject(ctx, TargetAI); err != nil {
+		return nil, err
+	}
+	return a.analyzer.DetectScam(ctx, projectData)
+}
+
+// Executor 注入交易所 5xx 错误的执行器中间件，模拟成交、持仓快照等可选接口通过 trading.As 访问被包装的执行器
+type Executor struct {
+	trading.Wrapped
+	injector *Injector
+}
+
+func NewExecutor(executor trading.TradeExecutor, injector *Injector) *Executor {
+	return &Executor{Wrapped: trading.Wrapped{Next: executor}, injector: injector}
+}
+
+// Middleware 返回注入交易所故障的执行器中间件
+func Middleware(injector *Injector) trading.Middleware {
+	return func(next trading.TradeExecutor) trading.TradeExecutor {
+		return NewExecutor(next, injector)
+	}
+}
+
+func (e *Executor) PlaceOrder(ctx context.Context, order *trading.Order) error {
+	if err := e.injector.Inject(ctx, TargetExchange); err != nil {
+		return err
+	}
+	return e.Next.PlaceOrder(ctx, order)
+}
+
+func (e *Executor) CancelOrder(ctx context.Context, symbol string, orderID string) error {
+	if err := e.injector.Inject(ctx, TargetExchange); err != nil {
+		return err
+	}
+	return e.Next.CancelOrder(ctx, symbol, orderID)
+}
+
+func (e *Executor) GetOrderStatus(ctx context.Context, symbol, orderID string) (*trading.Order, error) {
+	if err := e.injector.Inject(ctx, TargetExchange); err != nil {
+		return nil, err
+	}
+	return e.Next.GetOrderStatus(ctx, symbol, orderID)
+}
+
+func (e *Executor) GetBalance(ctx context.Context, symbol string) (float64, error) {
+	if err := e.injector.Inject(ctx, TargetExchange); err != nil {
+		return 0, err
+	}
+	return e.Next.GetBalance(ctx, symbol)
+}
@@ -0,0 +1,60 @@
+// Package clock 抽象时间来源：运行时使用系统时钟，测试和回测使用可手动推进的 Fake，
+// 按天重置、周期任务和轮询等依赖时间的逻辑在模拟时间下可确定地复现。
+package clock
+
+import "time"
+
+// Clock 时间来源
+type Clock interface {
+	// Now 返回当前时间
+	Now() time.Time
+	// NewTicker 返回按 d 周期触发的 Ticker，d 必须大于 0
+	NewTicker(d time.Duration) Ticker
+	// After 返回在 d 之后收到当前时间的 channel
+	After(d time.Duration) <-chan time.Time
+}
+
+// Ticker 周期触发器，和 time.Ticker 一样来不及接收的触发会被丢弃
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// Real 返回系统时钟
+func Real() Clock {
+	return realClock{}
+}
+
+// OrReal 返回 c，c 为空时返回系统时钟
+func OrReal(c Clock) Clock {
+	if c == nil {
+		return Real()
+	}
+	return c
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+type realTicker struct {
+	t *time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time {
+	return t.t.C
+}
+
+func (t realTicker) Stop() {
+	t.t.Stop()
+}
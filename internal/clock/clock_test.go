@@ -0,0 +1,87 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// received 返回 channel 中已有的时间，没有时返回 false
+func received(ch <-chan time.Time) (time.Time, bool) {
+	select {
+	case t := <-ch:
+		return t, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+func TestFake_Now(t *testing.T) {
+	f := NewFake(start)
+	assert.Equal(t, start, f.Now())
+
+	f.Advance(time.Minute)
+	assert.Equal(t, start.Add(time.Minute), f.Now())
+
+	// 时钟不倒退
+	f.Set(start)
+	assert.Equal(t, start.Add(time.Minute), f.Now())
+	f.Set(start.Add(time.Hour))
+	assert.Equal(t, start.Add(time.Hour), f.Now())
+}
+
+func TestFake_Ticker(t *testing.T) {
+	f := NewFake(start)
+	ticker := f.NewTicker(time.Hour)
+	assert.Equal(t, 1, f.Waiters())
+
+	f.Advance(59 * time.Minute)
+	_, ok := received(ticker.C())
+	assert.False(t, ok)
+
+	f.Advance(time.Minute)
+	at, ok := received(ticker.C())
+	assert.True(t, ok)
+	assert.Equal(t, start.Add(time.Hour), at)
+
+	// 一次跨过多个周期时只保留第一次未接收的触发
+	f.Advance(3 * time.Hour)
+	at, ok = received(ticker.C())
+	assert.True(t, ok)
+	assert.Equal(t, start.Add(2*time.Hour), at)
+	_, ok = received(ticker.C())
+	assert.False(t, ok)
+
+	ticker.Stop()
+	f.Advance(2 * time.Hour)
+	_, ok = received(ticker.C())
+	assert.False(t, ok)
+	assert.Zero(t, f.Waiters())
+}
+
+func TestFake_After(t *testing.T) {
+	f := NewFake(start)
+	ch := f.After(time.Second)
+
+	_, ok := received(ch)
+	assert.False(t, ok)
+
+	f.Set(start.Add(2 * time.Second))
+	at, ok := received(ch)
+	assert.True(t, ok)
+	assert.Equal(t, start.Add(time.Second), at)
+	assert.Zero(t, f.Waiters())
+
+	_, ok = received(f.After(0))
+	assert.True(t, ok)
+}
+
+func TestOrReal(t *testing.T) {
+	assert.Equal(t, Real(), OrReal(nil))
+
+	f := NewFake(start)
+	assert.Same(t, f, OrReal(f))
+}
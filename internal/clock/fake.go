@@ -0,0 +1,131 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Fake 手动推进的时钟，Advance 或 Set 推进时间后触发到期的 Ticker 和 After；可并发使用
+type Fake struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*waiter
+}
+
+// waiter 一个等待中的 Ticker 或 After，period 为 0 时只触发一次
+type waiter struct {
+	next    time.Time
+	period  time.Duration
+	ch      chan time.Time
+	stopped bool
+}
+
+// NewFake 创建从 start 开始的时钟
+func NewFake(start time.Time) *Fake {
+	return &Fake{now: start}
+}
+
+// Now implements Clock
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// NewTicker implements Clock，第一次在 d 之后触发
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+	return &fakeTicker{f: f, w: f.add(d, d)}
+}
+
+// After implements Clock
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	w := f.add(d, 0)
+	if d <= 0 {
+		f.Advance(0)
+	}
+	return w.ch
+}
+
+func (f *Fake) add(d, period time.Duration) *waiter {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w := &waiter{next: f.now.Add(d), period: period, ch: make(chan time.Time, 1)}
+	f.waiters = append(f.waiters, w)
+	return w
+}
+
+// Advance 把时间推进 d
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	f.set(f.now.Add(d))
+	f.mu.Unlock()
+}
+
+// Set 把时间推进到 t，t 早于当前时间时不变，时钟不会倒退
+func (f *Fake) Set(t time.Time) {
+	f.mu.Lock()
+	if t.After(f.now) {
+		f.set(t)
+	}
+	f.mu.Unlock()
+}
+
+// set 推进时间并触发到期的等待者，调用方需持有锁；
+// 一次推进跨过多个周期时 Ticker 只保留一次未接收的触发，与 time.Ticker 一致
+func (f *Fake) set(t time.Time) {
+	f.now = t
+	active := f.waiters[:0]
+	for _, w := range f.waiters {
+		if w.stopped {
+			continue
+		}
+		for !w.next.After(t) {
+			select {
+			case w.ch <- w.next:
+			default:
+			}
+			if w.period == 0 {
+				w.stopped = true
+				break
+			}
+			w.next = w.next.Add(w.period)
+		}
+		if !w.stopped {
+			active = append(active, w)
+		}
+	}
+	f.waiters = active
+}
+
+// Waiters 返回等待中的 Ticker 和 After 数量，测试中用于确认后台任务已开始等待
+func (f *Fake) Waiters() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	n := 0
+	for _, w := range f.waiters {
+		if !w.stopped {
+			n++
+		}
+	}
+	return n
+}
+
+type fakeTicker struct {
+	f *Fake
+	w *waiter
+}
+
+func (t *fakeTicker) C() <-chan time.Time {
+	return t.w.ch
+}
+
+func (t *fakeTicker) Stop() {
+	t.f.mu.Lock()
+	defer t.f.mu.Unlock()
+	t.w.stopped = true
+}
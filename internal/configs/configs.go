@@ -1,11 +1,114 @@
 package configs
 
 import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/ai"
+	"github.com/songzhibin97/quantaflux/internal/auth"
+	"github.com/songzhibin97/quantaflux/internal/calibration"
+	"github.com/songzhibin97/quantaflux/internal/chaos"
+	"github.com/songzhibin97/quantaflux/internal/pairs"
+	"github.com/songzhibin97/quantaflux/internal/regime"
 	"github.com/songzhibin97/quantaflux/internal/risk"
+	"github.com/songzhibin97/quantaflux/internal/social"
+	"github.com/songzhibin97/quantaflux/internal/stream"
+	"github.com/songzhibin97/quantaflux/internal/supervisor"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+	"github.com/songzhibin97/quantaflux/internal/trading/paper"
+	"github.com/songzhibin97/quantaflux/internal/volatility"
+)
+
+// 运行模式
+const (
+	ModeLive     = "live"     // 实盘交易
+	ModePaper    = "paper"    // 模拟交易，使用实时行情和本地撮合
+	ModeBacktest = "backtest" // 回测，回放历史行情并本地撮合
+	ModeShadow   = "shadow"   // 影子模式，完整运行决策流程并按实时行情模拟成交，不向交易所下单
 )
 
+// 周期任务类型
+const (
+	JobAnalyzeProjects     = "analyze_projects"      // 重新执行项目分析
+	JobPerformanceReport   = "performance_report"    // 生成绩效报告
+	JobRefreshTokenInfo    = "refresh_token_info"    // 刷新代币信息
+	JobPruneData           = "prune_data"            // 清理过期行情数据
+	JobPnLReport           = "pnl_report"            // 生成并推送盈亏报告
+	JobSyncOrders          = "sync_orders"           // 同步挂单的状态和成交
+	JobEquitySnapshot      = "equity_snapshot"       // 保存账户权益快照
+	JobDiscoverTokens      = "discover_tokens"       // 扫描市场发现新交易对
+	JobRefreshExchangeInfo = "refresh_exchange_info" // 刷新交易所元数据（交易对状态和过滤条件）
+	JobArchiveData         = "archive_data"          // 导出历史数据到冷归档并从数据库删除
+	JobDetectArbitrage     = "detect_arbitrage"      // 比较各交易所报价，发现跨交易所套利机会
+)
+
+// ArchiveTables 支持冷归档的表
+var ArchiveTables = []string{"market_data", "trade_journal", "audit_log"}
+
+// 行情来源
+const (
+	MarketDataTicker = "ticker" // 按刷新间隔轮询 24 小时行情
+	MarketDataKlines = "klines" // 订阅 K 线 WebSocket，每根 K 线收盘时生成行情
+)
+
+// 数据源
+const (
+	DataSourceBinance   = "binance"   // 行情和交易对信息
+	DataSourceCoinGecko = "coingecko" // 行情、代币信息和社区指标
+	DataSourceTwitter   = "twitter"   // 项目账号的关注人数，只提供社交指标
+)
+
+// DataSources 支持的数据源
+var DataSources = []string{DataSourceBinance, DataSourceCoinGecko, DataSourceTwitter}
+
+// 行情处理阶段，用于耗时预算
+const (
+	StageAI    = "ai"    // 诈骗检测、情绪分析和价格预测
+	StageRisk  = "risk"  // 风险检查
+	StageOrder = "order" // 下单
+)
+
+// 下单数量单位
+const (
+	AmountUnitBase  = "base"  // 基础资产数量，如 BTC
+	AmountUnitQuote = "quote" // 计价资产金额，如 USDT
+)
+
+// 错误类别
+const (
+	ErrorClassData     = "data"     // 行情或代币数据缺失、数据源不可用
+	ErrorClassProvider = "provider" // AI 服务不可用或返回无效结果
+	ErrorClassExchange = "exchange" // 交易所不可用
+	ErrorClassOrder    = "order"    // 订单被拒绝或不存在、余额不足、时间戳被拒绝
+	ErrorClassAuth     = "auth"     // 交易所拒绝 API 密钥
+	ErrorClassRisk     = "risk"     // 交易未通过风险检查
+	ErrorClassUnknown  = "unknown"  // 其他未分类错误
+)
+
+// 错误处理策略
+const (
+	ErrorPolicySkip  = "skip"  // 记录日志后继续处理下一条行情
+	ErrorPolicyPause = "pause" // 暂停交易，继续采集行情
+	ErrorPolicyHalt  = "halt"  // 视为致命错误，停止系统
+)
+
+// DefaultErrorPolicy 未配置时各错误类别的处理策略
+var DefaultErrorPolicy = map[string]string{
+	ErrorClassData:     ErrorPolicySkip,
+	ErrorClassProvider: ErrorPolicySkip,
+	ErrorClassExchange: ErrorPolicyPause,
+	ErrorClassOrder:    ErrorPolicySkip,
+	ErrorClassAuth:     ErrorPolicyHalt,
+	ErrorClassRisk:     ErrorPolicySkip,
+	ErrorClassUnknown:  ErrorPolicySkip,
+}
+
 type Config struct {
 	// 基础配置
+	Mode            string   `json:"mode" yaml:"mode"`                         // 运行模式(live/paper/backtest)
 	Symbols         []string ` json:"symbols" yaml:"symbols"`                  // 交易对列表
 	RefreshInterval string   `json:"refresh_interval" yaml:"refresh_interval"` // 数据刷新间隔
 
@@ -23,31 +126,1290 @@ type Config struct {
 	// 交易所配置
 	ExchangeConfig ExchangeConfig `json:"exchange_config" yaml:"exchange_config"`
 
+	// 行情来源
+	MarketDataConfig MarketDataConfig `json:"market_data_config" yaml:"market_data_config"`
+
+	// 社交分数
+	SocialScoreConfig SocialScoreConfig `json:"social_score_config" yaml:"social_score_config"`
+
+	// 风险评估使用的交易成本模型
+	CostConfig CostConfig `json:"cost_config" yaml:"cost_config"`
+
+	// 交易对单独配置，交易对 -> 覆盖项，未设置的项继承全局配置
+	SymbolOverrides map[string]SymbolConfig `json:"symbol_overrides" yaml:"symbol_overrides"`
+
+	// 按品种池标签的交易对配置，标签 -> 覆盖项，优先级低于 symbol_overrides
+	TagOverrides map[string]SymbolConfig `json:"tag_overrides" yaml:"tag_overrides"`
+
+	// 交易对自动停用配置
+	AutoDisableConfig AutoDisableConfig `json:"auto_disable_config" yaml:"auto_disable_config"`
+
+	// 死人开关配置
+	DeadmanConfig DeadmanConfig `json:"deadman_config" yaml:"deadman_config"`
+
+	// 权益回撤熔断配置
+	DrawdownConfig DrawdownConfig `json:"drawdown_config" yaml:"drawdown_config"`
+
+	// 持仓监控配置
+	PositionMonitorConfig PositionMonitorConfig `json:"position_monitor_config" yaml:"position_monitor_config"`
+
+	// 历史波动率和 ATR 配置
+	VolatilityConfig VolatilityConfig `json:"volatility_config" yaml:"volatility_config"`
+
+	// 内置告警阈值配置
+	AlertConfig AlertConfig `json:"alert_config" yaml:"alert_config"`
+
+	// 多计价资产的统一估值配置
+	ValuationConfig ValuationConfig `json:"valuation_config" yaml:"valuation_config"`
+
+	// 交易账户，为空时使用 exchange_config 和 risk_parameters 作为唯一账户
+	Accounts []AccountConfig `json:"accounts" yaml:"accounts"`
+
+	// 配对交易，每项在账户上运行一个现货/合约配对策略
+	Pairs []PairConfig `json:"pairs" yaml:"pairs"`
+
+	// 模拟交易配置
+	PaperConfig PaperConfig `json:"paper_config" yaml:"paper_config"`
+
+	// 故障注入配置，只在模拟交易中使用
+	ChaosConfig ChaosConfig `json:"chaos_config" yaml:"chaos_config"`
+
+	// 行情推送和后台监控等长连接组件的重连退避
+	SupervisorConfig SupervisorConfig `json:"supervisor_config" yaml:"supervisor_config"`
+
+	// 回测配置
+	BacktestConfig BacktestConfig `json:"backtest_config" yaml:"backtest_config"`
+
+	// HTTP API 配置
+	APIConfig APIConfig `json:"api_config" yaml:"api_config"`
+
+	// 行情处理流水线配置
+	PipelineConfig PipelineConfig `json:"pipeline_config" yaml:"pipeline_config"`
+
+	// 行情事件过滤配置
+	EventFilterConfig EventFilterConfig `json:"event_filter_config" yaml:"event_filter_config"`
+
+	// 事件流配置，将事件发布到 NATS 或 Kafka
+	StreamConfig StreamConfig `json:"stream_config" yaml:"stream_config"`
+
+	// 单条行情各阶段的耗时预算
+	LatencyBudget LatencyBudget `json:"latency_budget" yaml:"latency_budget"`
+
+	// 交易所时间同步配置
+	TimeSyncConfig TimeSyncConfig `json:"time_sync_config" yaml:"time_sync_config"`
+
+	// 代币发现配置，由 discover_tokens 任务使用
+	DiscoveryConfig DiscoveryConfig `json:"discovery_config" yaml:"discovery_config"`
+
+	// 跨交易所套利配置，由 detect_arbitrage 任务使用
+	ArbitrageConfig ArbitrageConfig `json:"arbitrage_config" yaml:"arbitrage_config"`
+
+	// 链上流动性池监控配置
+	LiquidityConfig LiquidityConfig `json:"liquidity_config" yaml:"liquidity_config"`
+
+	// 大额转账追踪配置
+	WhaleConfig WhaleConfig `json:"whale_config" yaml:"whale_config"`
+
+	// 盘口深度信号配置
+	OrderBookConfig OrderBookConfig `json:"order_book_config" yaml:"order_book_config"`
+
+	// 执行器中间件配置
+	ExecutionConfig ExecutionConfig `json:"execution_config" yaml:"execution_config"`
+
+	// 按市场状态缩放风险限额配置
+	RegimeConfig RegimeConfig `json:"regime_config" yaml:"regime_config"`
+
+	// 情绪动量配置
+	SentimentConfig SentimentConfig `json:"sentiment_config" yaml:"sentiment_config"`
+
+	// 交易对状态配置
+	TradingStatusConfig TradingStatusConfig `json:"trading_status_config" yaml:"trading_status_config"`
+
+	// 交易所系统和资产钱包状态监控
+	WalletStatusConfig WalletStatusConfig `json:"wallet_status_config" yaml:"wallet_status_config"`
+
+	// 周期任务配置
+	Jobs []JobConfig `json:"jobs" yaml:"jobs"`
+
+	// 调用链追踪配置
+	TracingConfig TracingConfig `json:"tracing_config" yaml:"tracing_config"`
+
+	// 日志配置
+	LogConfig LogConfig `json:"log_config" yaml:"log_config"`
+
+	// 错误处理策略，错误类别 -> skip/pause/halt
+	ErrorPolicy map[string]string `json:"error_policy" yaml:"error_policy"`
+
+	// pause 策略暂停交易的时长，到期自动恢复；为 0 时需手动恢复，未配置时默认 5m
+	ErrorPauseDuration string `json:"error_pause_duration" yaml:"error_pause_duration"`
+
+	// 关闭行为配置
+	ShutdownConfig ShutdownConfig `json:"shutdown_config" yaml:"shutdown_config"`
+
+	// 审计日志配置
+	AuditConfig AuditConfig `json:"audit_config" yaml:"audit_config"`
+
+	// 冷归档配置
+	ArchiveConfig ArchiveConfig `json:"archive_config" yaml:"archive_config"`
+
+	// 通知渠道配置
+	NotifyConfig NotifyConfig `json:"notify_config" yaml:"notify_config"`
+
+	// 聊天机器人控制配置
+	BotConfig BotConfig `json:"bot_config" yaml:"bot_config"`
+
 	// 代理设置
 	Proxy string `json:"proxy" yaml:"proxy"`
 }
 
+// RunMode 返回运行模式，未配置时默认为实盘
+func (c *Config) RunMode() string {
+	if c.Mode == "" {
+		return ModeLive
+	}
+	return c.Mode
+}
+
+// OrderAmountUnit 返回交易量单位，未配置时为基础资产数量
+func (c *Config) OrderAmountUnit() string {
+	if c.TradingConfig.AmountUnit == "" {
+		return AmountUnitBase
+	}
+	return c.TradingConfig.AmountUnit
+}
+
+// DefaultErrorPauseDuration 未配置 error_pause_duration 时错误暂停的时长
+const DefaultErrorPauseDuration = 5 * time.Minute
+
+// ErrorPause 返回 pause 策略暂停交易的时长，0 表示不自动恢复
+func (c *Config) ErrorPause() time.Duration {
+	d, err := time.ParseDuration(c.ErrorPauseDuration)
+	if err != nil {
+		return DefaultErrorPauseDuration
+	}
+	return d
+}
+
+// ErrorPolicyFor 返回错误类别对应的处理策略，未配置时使用默认策略
+func (c *Config) ErrorPolicyFor(class string) string {
+	if policy, ok := c.ErrorPolicy[class]; ok {
+		return policy
+	}
+	if policy, ok := DefaultErrorPolicy[class]; ok {
+		return policy
+	}
+	return ErrorPolicySkip
+}
+
 type AIConfig struct {
 	MinConfidence    float64 ` json:"min_confidence" yaml:"min_confidence"`        // AI预测最小置信度
 	PredictTimeFrame string  `json:"predict_time_frame" yaml:"predict_time_frame"` // 预测时间范围
+	PredictHistory   string  `json:"predict_history" yaml:"predict_history"`       // 价格预测使用的历史行情时长(如 24h)，为空时只使用当前行情
 	ScamThreshold    float64 `json:"scam_threshold" yaml:"scam_threshold"`         // 诈骗判定阈值
-	APIKey           string  `json:"api_key" yaml:"api_key"`                       // AI服务API密钥
+	APIKey           string  `json:"api_key" yaml:"api_key" secret:"true"`         // AI服务API密钥
 	ModelType        string  `json:"model_type" yaml:"model_type"`                 // AI模型类型
+
+	// 生成参数，未设置时温度为 0.3，top_p 和 max_tokens 由服务端决定
+	Temperature *float64                       `json:"temperature" yaml:"temperature"`
+	TopP        *float64                       `json:"top_p" yaml:"top_p"`
+	MaxTokens   int                            `json:"max_tokens" yaml:"max_tokens"`
+	Methods     map[string]ai.GenerationParams `json:"methods" yaml:"methods"` // 分析方法 -> 生成参数覆盖项
+
+	// 挑战者模型，与当前模型分析同一条行情，只记录假设决策不下单，用于上线前对比
+	Challenger ChallengerConfig `json:"challenger" yaml:"challenger"`
+
+	// 诈骗检测的复查策略
+	ScamCheck ScamCheckConfig `json:"scam_check" yaml:"scam_check"`
+
+	// 置信度校准
+	Calibration CalibrationConfig `json:"calibration" yaml:"calibration"`
+
+	// 重大事件触发的重新分析
+	Reanalysis ReanalysisConfig `json:"reanalysis" yaml:"reanalysis"`
+
+	// AI 调用排队
+	Queue AIQueueConfig `json:"queue" yaml:"queue"`
+}
+
+// AIQueueConfig AI 调用按优先级排队：诈骗检测优先于价格预测和情绪分析，周期性的项目分析最后执行
+type AIQueueConfig struct {
+	MaxConcurrent int  `json:"max_concurrent" yaml:"max_concurrent"` // 每个 AI 服务（当前模型和挑战者模型分别计算）同时执行的调用数量上限，0 表示不排队
+	CancelStale   bool `json:"cancel_stale" yaml:"cancel_stale"`     // 同一交易对的新行情到达时取消旧行情排队中和执行中的 AI 调用，旧行情不再交易
+}
+
+// ReanalysisConfig 交易对发生重大事件时立即重新执行项目分析和诈骗检测，不等周期任务和复查间隔
+type ReanalysisConfig struct {
+	HolderConcentration float64 `json:"holder_concentration" yaml:"holder_concentration"` // 团队持仓占总量的比例相对上次观察上升超过该比例时触发，0 表示不检查
+	SocialSpike         float64 `json:"social_spike" yaml:"social_spike"`                 // 任一社交指标达到上次观察值的该倍数时触发，需大于 1，0 表示不检查
+	LiquidityDrop       bool    `json:"liquidity_drop" yaml:"liquidity_drop"`             // 流动性监控发出流动性撤出预警时触发
+	Listing             bool    `json:"listing" yaml:"listing"`                           // 交易所显示交易对开始交易（上架或恢复交易）时触发
+	Cooldown            string  `json:"cooldown" yaml:"cooldown"`                         // 同一交易对两次触发的最短间隔(如 30m)，为空时不限制
+}
+
+// Enabled 是否配置了任一触发事件
+func (c ReanalysisConfig) Enabled() bool {
+	return c.HolderConcentration > 0 || c.SocialSpike > 0 || c.LiquidityDrop || c.Listing
+}
+
+// CooldownPeriod 返回同一交易对两次触发的最短间隔，未配置时为 0
+func (c ReanalysisConfig) CooldownPeriod() time.Duration {
+	d, _ := time.ParseDuration(c.Cooldown)
+	return d
+}
+
+// CalibrationConfig 置信度校准：统计 window 内各置信度区间的预测到期后方向是否正确，
+// 区间样本达到 min_samples 后用经验准确率代替模型给出的置信度与 min_confidence 比较，window 为空时不校准
+type CalibrationConfig struct {
+	Window     string `json:"window" yaml:"window"`           // 统计窗口(如 168h)
+	Buckets    int    `json:"buckets" yaml:"buckets"`         // 置信度区间数量，默认 10
+	MinSamples int    `json:"min_samples" yaml:"min_samples"` // 区间内最少的到期预测数量，默认 20
+}
+
+// Enabled 是否启用置信度校准
+func (c CalibrationConfig) Enabled() bool {
+	return c.Window != ""
+}
+
+// Options 返回校准参数
+func (c CalibrationConfig) Options() calibration.Options {
+	window, _ := time.ParseDuration(c.Window)
+	return calibration.Options{Window: window, Buckets: c.Buckets, MinSamples: c.MinSamples}
+}
+
+// ScamCheckConfig 诈骗检测结论按交易对缓存，间隔内复用，持仓或流动性大幅变化时立即重新检测
+type ScamCheckConfig struct {
+	Interval           string  `json:"interval" yaml:"interval"`                         // 同一交易对两次检测的最短间隔(如 6h)，为空时每条行情都检测
+	Expiry             string  `json:"expiry" yaml:"expiry"`                             // 结论的有效期，重新检测失败时在有效期内沿用上次结论，为空时不沿用
+	MaxHolderChange    float64 `json:"max_holder_change" yaml:"max_holder_change"`       // 流通量或团队持仓相对上次检测的变化比例超过该值时立即重新检测，0 表示不检查
+	MaxLiquidityChange float64 `json:"max_liquidity_change" yaml:"max_liquidity_change"` // 流动性池规模相对上次检测的变化比例超过该值时立即重新检测，0 表示不检查
+}
+
+// CheckInterval 返回两次检测的最短间隔，未配置时为 0
+func (c ScamCheckConfig) CheckInterval() time.Duration {
+	d, _ := time.ParseDuration(c.Interval)
+	return d
+}
+
+// VerdictExpiry 返回结论的有效期，未配置时为 0
+func (c ScamCheckConfig) VerdictExpiry() time.Duration {
+	d, _ := time.ParseDuration(c.Expiry)
+	return d
+}
+
+// Generation 返回各分析方法的生成参数
+func (c AIConfig) Generation() ai.GenerationOptions {
+	return ai.GenerationOptions{
+		Default: ai.GenerationParams{
+			Temperature: c.Temperature,
+			TopP:        c.TopP,
+			MaxTokens:   c.MaxTokens,
+		},
+		Methods: c.Methods,
+	}
+}
+
+// ChallengerConfig A/B 对比的挑战者模型，model_type 为空时不启用
+type ChallengerConfig struct {
+	ModelType string `json:"model_type" yaml:"model_type"`         // 挑战者模型类型
+	APIKey    string `json:"api_key" yaml:"api_key" secret:"true"` // 为空时使用 ai_config.api_key
+}
+
+// Enabled 是否启用挑战者模型
+func (c ChallengerConfig) Enabled() bool {
+	return c.ModelType != ""
 }
 
 type TradingConfig struct {
 	MaxOrderAmount float64 `json:"max_order_amount" yaml:"max_order_amount"` // 单笔最大交易量
 	MinOrderAmount float64 `json:"min_order_amount" yaml:"min_order_amount"` // 单笔最小交易量
+	AmountUnit     string  `json:"amount_unit" yaml:"amount_unit"`           // 交易量单位(base/quote)，quote 时按计价资产金额下单，默认 base
 	PriceTolerance float64 `json:"price_tolerance" yaml:"price_tolerance"`   // 价格容差
-	OrderType      string  `json:"order_type" yaml:"order_type"`             // 订单类型(market/limit)
+	OrderType      string  `json:"order_type" yaml:"order_type"`             // 订单类型(market/limit/maker)，maker 按盘口挂单优先，参数见 maker
+	Strategy       string  `json:"strategy" yaml:"strategy"`                 // 策略名称，记录在交易日志中
+	FeeRate        float64 `json:"fee_rate" yaml:"fee_rate"`                 // 手续费率，用于绩效统计估算
+	CandleInterval string  `json:"candle_interval" yaml:"candle_interval"`   // K 线周期(如 1m/5m/1h)，设置后只在 K 线收盘时触发策略，为空时每条行情触发
+
+	// 趋势过滤周期(如 1h/4h)，每个交易对按这些周期额外订阅行情，任一周期趋势向下时不买入
+	TrendTimeframes []string `json:"trend_timeframes" yaml:"trend_timeframes"`
+
+	// 余额不足时把订单按可用余额缩小后重试一次，缩小后仍需不低于最小交易量并通过风险评估
+	AutoDownsize bool `json:"auto_downsize" yaml:"auto_downsize"`
+
+	// 挂单优先下单的参数，order_type 为 maker 时使用
+	Maker MakerConfig `json:"maker" yaml:"maker"`
+
+	// 组合订单：买入成交后同时挂出止盈和止损平仓单
+	Bracket BracketConfig `json:"bracket" yaml:"bracket"`
+
+	// 全部账户和交易对按时间窗口累计的下单名义金额上限，在风险评估之前检查
+	SpendLimit SpendLimitConfig `json:"spend_limit" yaml:"spend_limit"`
+}
+
+// SpendLimitConfig 每分钟/小时/天允许下单的最大名义金额(按估值资产计)，为 0 时不限制
+type SpendLimitConfig struct {
+	PerMinute float64 `json:"per_minute" yaml:"per_minute"`
+	PerHour   float64 `json:"per_hour" yaml:"per_hour"`
+	PerDay    float64 `json:"per_day" yaml:"per_day"`
+}
+
+// Limits 返回已配置的时间窗口上限
+func (c SpendLimitConfig) Limits() []risk.SpendLimit {
+	var limits []risk.SpendLimit
+	for _, limit := range []risk.SpendLimit{
+		{Window: time.Minute, Max: c.PerMinute},
+		{Window: time.Hour, Max: c.PerHour},
+		{Window: 24 * time.Hour, Max: c.PerDay},
+	} {
+		if limit.Max > 0 {
+			limits = append(limits, limit)
+		}
+	}
+	return limits
+}
+
+// MakerConfig 挂单优先下单：按盘口在价差内挂限价单，等待 wait 后仍未全部成交且信号仍然有效时，剩余数量改为市价单
+type MakerConfig struct {
+	Wait             string  `json:"wait" yaml:"wait"`                           // 限价单等待成交的时间，默认 30s
+	PollInterval     string  `json:"poll_interval" yaml:"poll_interval"`         // 等待期间查询订单状态的间隔，默认 2s
+	PriceImprovement float64 `json:"price_improvement" yaml:"price_improvement"` // 委托价从买一/卖一向对手价改善的价差比例，[0, 1)，默认 0
+}
+
+// Options 返回挂单优先下单的参数，未配置或无效的时长使用默认值
+func (c MakerConfig) Options() trading.MakerOptions {
+	opts := trading.MakerOptions{Wait: 30 * time.Second, PollInterval: 2 * time.Second, Improvement: c.PriceImprovement}
+	if d, err := time.ParseDuration(c.Wait); err == nil && d > 0 {
+		opts.Wait = d
+	}
+	if d, err := time.ParseDuration(c.PollInterval); err == nil && d > 0 {
+		opts.PollInterval = d
+	}
+	return opts
+}
+
+// BracketConfig 信号买单以组合订单下单：入场成交后按成交数量挂出止盈限价单和止损单，交易所支持 OCO 时使用原生 OCO，
+// 否则按行情在本地触发止损。价格按下单时的行情价（限价单为委托价）计算
+type BracketConfig struct {
+	TakeProfit float64 `json:"take_profit" yaml:"take_profit"` // 止盈价相对入场价的涨幅，如 0.06
+	StopLoss   float64 `json:"stop_loss" yaml:"stop_loss"`     // 止损价相对入场价的跌幅，如 0.03
+}
+
+// Enabled 是否以组合订单下买单
+func (c BracketConfig) Enabled() bool {
+	return c.TakeProfit > 0 && c.StopLoss > 0
+}
+
+type SymbolConfig struct {
+	MinConfidence   *float64             `json:"min_confidence" yaml:"min_confidence"`     // AI预测最小置信度
+	MinOrderAmount  *float64             `json:"min_order_amount" yaml:"min_order_amount"` // 单笔最小交易量
+	MaxOrderAmount  *float64             `json:"max_order_amount" yaml:"max_order_amount"` // 单笔最大交易量
+	RiskParams      *risk.RiskParameters `json:"risk_parameters" yaml:"risk_params"`       // 交易对风险限额，在账户限额之外额外检查
+	RefreshInterval string               `json:"refresh_interval" yaml:"refresh_interval"` // 行情刷新间隔
+	Strategy        string               `json:"strategy" yaml:"strategy"`                 // 交易对运行的策略，优先于账户和全局策略
+	TrendTimeframes []string             `json:"trend_timeframes" yaml:"trend_timeframes"` // 趋势过滤周期，设置后替换全局配置
+	HedgeRatio      *float64             `json:"hedge_ratio" yaml:"hedge_ratio"`           // 风险预警为 HIGH 时按持仓的该比例开合约空单对冲，代替紧急平仓
+	TakeProfit      []TakeProfitLevel    `json:"take_profit" yaml:"take_profit"`           // 分批止盈，设置后替换全局配置，空列表表示不止盈
+}
+
+// SymbolSettings 交易对合并全局配置后的生效配置
+type SymbolSettings struct {
+	MinConfidence   float64
+	MinOrderAmount  float64
+	MaxOrderAmount  float64
+	RiskParams      *risk.RiskParameters // 为空时只检查账户限额
+	RefreshInterval string
+	Strategy        string   // 为空时使用账户或全局策略
+	TrendTimeframes []string // 趋势过滤周期
+	HedgeRatio      float64  // 对冲比例，0 表示不对冲
+	TakeProfit      []TakeProfitLevel
+}
+
+// ForSymbol 返回交易对的生效配置：先按顺序应用交易对在品种池中的标签对应的 tag_overrides，
+// 再应用 symbol_overrides，未覆盖的项继承全局配置
+func (c *Config) ForSymbol(symbol string, tags ...string) SymbolSettings {
+	settings := SymbolSettings{
+		MinConfidence:   c.AIConfig.MinConfidence,
+		MinOrderAmount:  c.TradingConfig.MinOrderAmount,
+		MaxOrderAmount:  c.TradingConfig.MaxOrderAmount,
+		RefreshInterval: c.RefreshInterval,
+		TrendTimeframes: c.TradingConfig.TrendTimeframes,
+		TakeProfit:      c.PositionMonitorConfig.TakeProfit,
+	}
+
+	for _, tag := range tags {
+		for name, override := range c.TagOverrides {
+			if strings.EqualFold(name, tag) {
+				settings.apply(override)
+			}
+		}
+	}
+	if override, ok := c.SymbolOverrides[symbol]; ok {
+		settings.apply(override)
+	}
+	return settings
+}
+
+// apply 用覆盖项中设置了的项替换生效配置
+func (s *SymbolSettings) apply(override SymbolConfig) {
+	if override.MinConfidence != nil {
+		s.MinConfidence = *override.MinConfidence
+	}
+	if override.MinOrderAmount != nil {
+		s.MinOrderAmount = *override.MinOrderAmount
+	}
+	if override.MaxOrderAmount != nil {
+		s.MaxOrderAmount = *override.MaxOrderAmount
+	}
+	if override.RefreshInterval != "" {
+		s.RefreshInterval = override.RefreshInterval
+	}
+	if override.TrendTimeframes != nil {
+		s.TrendTimeframes = override.TrendTimeframes
+	}
+	if override.HedgeRatio != nil {
+		s.HedgeRatio = *override.HedgeRatio
+	}
+	if override.TakeProfit != nil {
+		s.TakeProfit = override.TakeProfit
+	}
+	if override.RiskParams != nil {
+		s.RiskParams = override.RiskParams
+	}
+	if override.Strategy != "" {
+		s.Strategy = override.Strategy
+	}
+}
+
+// Hedges 判断是否有交易对配置了对冲或配置了配对交易
+func (c *Config) Hedges() bool {
+	if len(c.Pairs) > 0 {
+		return true
+	}
+	for _, override := range c.SymbolOverrides {
+		if override.HedgeRatio != nil && *override.HedgeRatio > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// DeadmanConfig 死人开关：超过 timeout 未收到外部心跳（API 心跳或心跳文件被修改）时暂停下单
+type DeadmanConfig struct {
+	Timeout string `json:"timeout" yaml:"timeout"` // 心跳超时时间(如 10m)，为空时关闭
+	File    string `json:"file" yaml:"file"`       // 心跳文件，修改时间视为一次心跳，为空时只接受 API 心跳
+	Flatten bool   `json:"flatten" yaml:"flatten"` // 超时后同时清仓
+}
+
+// DrawdownConfig 回撤熔断：权益相对 window 内的峰值回撤超过 max_drawdown 时暂停下单，需手动恢复
+type DrawdownConfig struct {
+	MaxDrawdown float64 `json:"max_drawdown" yaml:"max_drawdown"` // 最大回撤比例(如 0.2)，0 表示不检查
+	Window      string  `json:"window" yaml:"window"`             // 峰值统计窗口，未配置时默认 720h
+	Flatten     bool    `json:"flatten" yaml:"flatten"`           // 熔断后同时清仓
+}
+
+// PeakWindow 返回峰值统计窗口，未配置时默认 30 天
+func (c DrawdownConfig) PeakWindow() time.Duration {
+	if d, err := time.ParseDuration(c.Window); err == nil && d > 0 {
+		return d
+	}
+	return 30 * 24 * time.Hour
+}
+
+// PositionMonitorConfig 持仓监控：按最新价格评估各账户持仓，浮亏超过 max_loss_per_trade、
+// 持仓时间超过 max_holding_period、市值超过 max_position_size 或总市值超过 max_exposure 时预警
+type PositionMonitorConfig struct {
+	Interval         string  `json:"interval" yaml:"interval"`                     // 检查间隔，默认 15s
+	MaxHoldingPeriod string  `json:"max_holding_period" yaml:"max_holding_period"` // 最长持仓时间，为空时不检查
+	MaxExposure      float64 `json:"max_exposure" yaml:"max_exposure"`             // 单个账户全部持仓市值上限（估值资产计价），0 表示不检查
+
+	// 风险预警为 MEDIUM 时卖出的持仓比例，(0, 1]，默认 0.5
+	ReduceFraction float64 `json:"reduce_fraction" yaml:"reduce_fraction"`
+
+	// 分批止盈：价格相对平均成本的涨幅依次达到各档 gain 时卖出 fraction 比例的持仓，可按交易对覆盖
+	TakeProfit []TakeProfitLevel `json:"take_profit" yaml:"take_profit"`
+}
+
+// TakeProfitLevel 一档止盈，fraction 为第一档止盈时持仓数量的比例，各档之和不超过 1
+type TakeProfitLevel struct {
+	Gain     float64 `json:"gain" yaml:"gain"`         // 相对平均成本的涨幅，如 0.05 表示 +5%
+	Fraction float64 `json:"fraction" yaml:"fraction"` // 卖出比例
+}
+
+// ReduceRatio 返回风险预警减仓的比例，未配置时为 0.5
+func (c PositionMonitorConfig) ReduceRatio() float64 {
+	if c.ReduceFraction <= 0 {
+		return 0.5
+	}
+	return c.ReduceFraction
+}
+
+// Options 返回账户的持仓监控配置，持仓来源由调用方设置
+func (c PositionMonitorConfig) Options(account string) risk.MonitorOptions {
+	options := risk.MonitorOptions{Account: account, MaxExposure: c.MaxExposure}
+	options.Interval, _ = time.ParseDuration(c.Interval)
+	options.MaxHoldingPeriod, _ = time.ParseDuration(c.MaxHoldingPeriod)
+	return options
+}
+
+// VolatilityConfig 按 interval 周期的 K 线计算各交易对最近 window 根 K 线的历史波动率和 ATR，K 线收盘时更新；
+// 风险检查按波动率估计 VaR，持仓监控按 ATR 止损，设置 risk_per_trade 时按止损距离计算下单数量，interval 为空时不启用
+type VolatilityConfig struct {
+	Interval      string  `json:"interval" yaml:"interval"`             // K 线周期(如 1h)
+	Window        int     `json:"window" yaml:"window"`                 // 计算使用的 K 线数量，默认 20
+	VaRConfidence float64 `json:"var_confidence" yaml:"var_confidence"` // VaR 置信度，默认 0.99
+	VaRHorizon    int     `json:"var_horizon" yaml:"var_horizon"`       // VaR 持有的 K 线数量，默认 1
+	StopATR       float64 `json:"stop_atr" yaml:"stop_atr"`             // 价格低于平均成本该倍数的 ATR 时平仓，0 表示不止损
+	RiskPerTrade  float64 `json:"risk_per_trade" yaml:"risk_per_trade"` // 每笔交易止损时的亏损金额（计价资产），0 表示按 max_order_amount 下单
+}
+
+// Enabled 是否启用波动率服务
+func (c VolatilityConfig) Enabled() bool {
+	return c.Interval != ""
+}
+
+// Options 返回波动率计算参数
+func (c VolatilityConfig) Options() volatility.Options {
+	interval, _ := time.ParseDuration(c.Interval)
+	return volatility.Options{Interval: interval, Window: c.Window}
+}
+
+// VaR 返回使用该波动率来源的 VaR 模型
+func (c VolatilityConfig) VaR(source risk.VolatilitySource) risk.VaRModel {
+	return risk.VaRModel{Source: source, Confidence: c.VaRConfidence, Horizon: c.VaRHorizon}
+}
+
+// ValuationConfig 统一估值：权益、持仓、风控限额和报告按 currency 计价，其他计价资产（如 BUSD、BTC）
+// 按最新价格换算，没有直接交易对时经过中间资产换算
+type ValuationConfig struct {
+	Currency    string   `json:"currency" yaml:"currency"`         // 估值资产，默认 USDT
+	RateSymbols []string `json:"rate_symbols" yaml:"rate_symbols"` // 只用于换算的交易对（如 BTCUSDT），定期采集价格，不参与交易
+}
+
+// Asset 返回估值资产，未配置时默认 USDT
+func (c ValuationConfig) Asset() string {
+	if c.Currency == "" {
+		return "USDT"
+	}
+	return c.Currency
+}
+
+// AlertConfig 内置告警：运行状态超过阈值时通过通知渠道告警，恢复后再通知一次，各项为 0 或空时不检查
+type AlertConfig struct {
+	Interval       string  `json:"interval" yaml:"interval"`                 // 检查间隔，默认 1m
+	MaxDataAge     string  `json:"max_data_age" yaml:"max_data_age"`         // 交易对行情超过该时长未更新时告警
+	MaxAIFailures  int     `json:"max_ai_failures" yaml:"max_ai_failures"`   // AI 调用连续失败达到该次数时告警
+	MaxRejectRatio float64 `json:"max_reject_ratio" yaml:"max_reject_ratio"` // 最近 reject_window 笔下单中被拒绝的比例超过该值时告警
+	RejectWindow   int     `json:"reject_window" yaml:"reject_window"`       // 统计拒单比例的下单笔数，默认 20，不足时不告警
+	StaleIntervals int     `json:"stale_intervals" yaml:"stale_intervals"`   // 交易对超过该倍数的刷新间隔未收到行情时预警并阻止新订单，直到行情恢复
+}
+
+// CheckInterval 返回告警检查间隔，未配置时默认 1 分钟
+func (c AlertConfig) CheckInterval() time.Duration {
+	if d, err := time.ParseDuration(c.Interval); err == nil && d > 0 {
+		return d
+	}
+	return time.Minute
+}
+
+// DataAge 返回行情过期阈值，未配置时为 0
+func (c AlertConfig) DataAge() time.Duration {
+	d, _ := time.ParseDuration(c.MaxDataAge)
+	return d
+}
+
+// OrderWindow 返回统计拒单比例的下单笔数，未配置时默认 20
+func (c AlertConfig) OrderWindow() int {
+	if c.RejectWindow > 0 {
+		return c.RejectWindow
+	}
+	return 20
+}
+
+type AutoDisableConfig struct {
+	MaxConsecutiveLosses int     `json:"max_consecutive_losses" yaml:"max_consecutive_losses"` // 连续亏损平仓达到该次数时停用交易对，0 表示不检查
+	RollingWindow        string  `json:"rolling_window" yaml:"rolling_window"`                 // 滚动盈亏统计窗口，为空时不检查
+	MinRollingPnL        float64 `json:"min_rolling_pnl" yaml:"min_rolling_pnl"`               // 窗口内已实现盈亏低于该值时停用交易对
+	ReviewPeriod         string  `json:"review_period" yaml:"review_period"`                   // 停用后自动恢复的等待时间，为空时只能手动恢复
+}
+
+// DiscoveryConfig 按筛选条件扫描全市场，候选交易对通过 AI 项目分析和诈骗检测后自动加入交易列表
+type DiscoveryConfig struct {
+	QuoteAsset       string  `json:"quote_asset" yaml:"quote_asset"`               // 只筛选以该资产计价的交易对，为空时不限制
+	MinQuoteVolume   float64 `json:"min_quote_volume" yaml:"min_quote_volume"`     // 24 小时成交额下限（计价资产）
+	TopGainers       int     `json:"top_gainers" yaml:"top_gainers"`               // 取 24 小时涨幅前 N 名，0 表示不按涨幅筛选
+	MinPriceChange   float64 `json:"min_price_change" yaml:"min_price_change"`     // 涨幅榜的最低涨幅（百分比）
+	VolumeSpikeRatio float64 `json:"volume_spike_ratio" yaml:"volume_spike_ratio"` // 成交额达到上次扫描的该倍数时视为放量，0 表示不检查
+	NewListings      bool    `json:"new_listings" yaml:"new_listings"`             // 是否筛选新上线的交易对
+	MaxCandidates    int     `json:"max_candidates" yaml:"max_candidates"`         // 每次扫描最多评估的候选数量，0 表示不限制
+	MaxSymbols       int     `json:"max_symbols" yaml:"max_symbols"`               // 交易列表的交易对总数上限，0 表示不限制
+
+	// 品种池中带有这些标签的交易对不参与评估，如 meme
+	ExcludeTags []string `json:"exclude_tags" yaml:"exclude_tags"`
+}
+
+// ArbitrageConfig 比较同一交易对在各交易所的报价，价差扣除双边手续费和滑点后超过 min_profit_bps 时
+// 推送 arbitrage 事件并发送通知，amount 大于 0 时同时在两个交易所下单
+type ArbitrageConfig struct {
+	Venues       []ArbitrageVenue `json:"venues" yaml:"venues"`                 // 参与比价的交易所，至少两个
+	Symbols      []string         `json:"symbols" yaml:"symbols"`               // 比价的交易对，为空时使用全部交易对
+	SlippageBps  float64          `json:"slippage_bps" yaml:"slippage_bps"`     // 单边预估滑点（基点）
+	MinProfitBps float64          `json:"min_profit_bps" yaml:"min_profit_bps"` // 扣除成本后要求的最小利润（基点）
+	Amount       float64          `json:"amount" yaml:"amount"`                 // 每次套利的基础资产数量，0 表示只检测和通知
+}
+
+// ArbitrageVenue 参与比价的交易所
+type ArbitrageVenue struct {
+	Source  string  `json:"source" yaml:"source"`     // 报价来源，market_data_config.sources 中的数据源名称
+	Account string  `json:"account" yaml:"account"`   // 在该交易所下单的账户，amount 大于 0 时必填
+	FeeRate float64 `json:"fee_rate" yaml:"fee_rate"` // 单边手续费率
+}
+
+// LiquidityConfig 监控代币在 DEX 上的流动性池，流动性被撤出或 LP 解锁时紧急平仓
+type LiquidityConfig struct {
+	Interval         string                   `json:"interval" yaml:"interval"`                     // 检查间隔，为空时不监控
+	MaxLiquidityDrop float64                  `json:"max_liquidity_drop" yaml:"max_liquidity_drop"` // 流动性相对峰值的最大跌幅，如 0.3 表示下降 30%
+	MinLockedRatio   float64                  `json:"min_locked_ratio" yaml:"min_locked_ratio"`     // 锁定或销毁的 LP 代币比例下限，0 表示不检查
+	Networks         map[string]NetworkConfig `json:"networks" yaml:"networks"`                     // 网络名称 -> 链和 DEX 配置
+	Tokens           map[string]TokenContract `json:"tokens" yaml:"tokens"`                         // 交易对 -> 代币合约
+}
+
+// NetworkConfig 一条链的节点和 Uniswap V2 兼容 DEX 配置
+type NetworkConfig struct {
+	RPCURL     string   `json:"rpc_url" yaml:"rpc_url" secret:"true"` // JSON-RPC 节点地址
+	Factory    string   `json:"factory" yaml:"factory"`               // 工厂合约地址
+	QuoteToken string   `json:"quote_token" yaml:"quote_token"`       // 池子的计价代币地址，如 WETH
+	Lockers    []string `json:"lockers" yaml:"lockers"`               // LP 锁仓合约地址
+}
+
+// TokenContract 交易对基础资产的代币合约
+type TokenContract struct {
+	Network         string `json:"network" yaml:"network"`
+	ContractAddress string `json:"contract_address" yaml:"contract_address"`
+}
+
+// WhaleConfig 通过 Whale Alert 追踪大额链上转账，交易所流入流出作为情绪分析输入，大额流入交易所时减仓
+type WhaleConfig struct {
+	APIKey         string  `json:"api_key" yaml:"api_key" secret:"true"`     // Whale Alert API 密钥，为空时不追踪
+	Interval       string  `json:"interval" yaml:"interval"`                 // 拉取间隔，未配置时默认 1m
+	Window         string  `json:"window" yaml:"window"`                     // 流入流出统计窗口，未配置时默认 24h
+	MinValueUSD    float64 `json:"min_value_usd" yaml:"min_value_usd"`       // 追踪的最小转账金额（美元）
+	AlertInflowUSD float64 `json:"alert_inflow_usd" yaml:"alert_inflow_usd"` // 单笔流入交易所达到该金额时发出减仓预警，0 表示不预警
+}
+
+// Enabled 是否配置了 API 密钥（示例配置中的占位符视为未配置）
+func (w WhaleConfig) Enabled() bool {
+	return !isPlaceholder(w.APIKey)
+}
+
+// PollInterval 返回拉取间隔，未配置时默认 1m
+func (w WhaleConfig) PollInterval() time.Duration {
+	if d, err := time.ParseDuration(w.Interval); err == nil && d > 0 {
+		return d
+	}
+	return time.Minute
+}
+
+// StatsWindow 返回流入流出统计窗口，未配置时默认 24h
+func (w WhaleConfig) StatsWindow() time.Duration {
+	if d, err := time.ParseDuration(w.Window); err == nil && d > 0 {
+		return d
+	}
+	return 24 * time.Hour
+}
+
+// OrderBookConfig 定期拉取各交易对的盘口深度，计算买卖盘失衡度和价差作为连续信号推送到事件流，
+// 卖盘明显更厚或价差过大时不开仓
+type OrderBookConfig struct {
+	Interval        string  `json:"interval" yaml:"interval"`                   // 拉取间隔，为空时不启用
+	Limit           int     `json:"limit" yaml:"limit"`                         // 每侧拉取的档位数量，未配置时默认 100
+	Band            float64 `json:"band" yaml:"band"`                           // 只统计中间价上下该比例内的挂单，未配置时默认 0.01
+	MaxAskImbalance float64 `json:"max_ask_imbalance" yaml:"max_ask_imbalance"` // 失衡度低于 -max_ask_imbalance（卖盘更厚）时不开仓，0 表示不过滤
+	MaxSpreadBps    float64 `json:"max_spread_bps" yaml:"max_spread_bps"`       // 买一卖一价差超过该基点数时不开仓，0 表示不过滤
+}
+
+// Enabled 是否配置了拉取间隔
+func (c OrderBookConfig) Enabled() bool {
+	return c.Interval != ""
+}
+
+// PollInterval 返回拉取间隔
+func (c OrderBookConfig) PollInterval() time.Duration {
+	d, _ := time.ParseDuration(c.Interval)
+	return d
+}
+
+// ExecutionConfig 各账户执行器外层的中间件：下单日志、频率限制、下单前风险复核、试运行和按客户端订单号去重。
+// 调用次数和耗时的指标始终记录
+type ExecutionConfig struct {
+	LogOrders         bool   `json:"log_orders" yaml:"log_orders"`                 // 记录每笔下单和撤单的结果
+	RateLimit         int    `json:"rate_limit" yaml:"rate_limit"`                 // 每个账户每分钟最多下单笔数，0 表示不限制
+	RecheckRisk       bool   `json:"recheck_risk" yaml:"recheck_risk"`             // 买单发送到交易所前按最新持仓再次风险评估
+	DryRun            bool   `json:"dry_run" yaml:"dry_run"`                       // 不向交易所下单和撤单，只记录订单
+	IdempotencyWindow string `json:"idempotency_window" yaml:"idempotency_window"` // 该时长内同一客户端订单号只下单一次，为空时不去重
+}
+
+// Window 返回去重窗口，未配置时为 0
+func (c ExecutionConfig) Window() time.Duration {
+	d, _ := time.ParseDuration(c.IdempotencyWindow)
+	return d
+}
+
+// RegimeConfig 按市场状态缩放风险限额：交易对平均年化波动率或权益回撤超过目标时，按 目标/当前 的比例
+// 缩小新订单的 max_position_size 和 max_order_amount，条件好转后每隔 recovery_interval 最多恢复 recovery_step
+type RegimeConfig struct {
+	VolatilityTarget float64 `json:"volatility_target" yaml:"volatility_target"` // 年化波动率目标(如 0.8)，需启用 volatility_config，0 表示不按波动率缩放
+	DrawdownTarget   float64 `json:"drawdown_target" yaml:"drawdown_target"`     // 回撤目标(如 0.05)，按权益快照计算，0 表示不按回撤缩放
+	MinScale         float64 `json:"min_scale" yaml:"min_scale"`                 // 缩放比例下限，默认 0.25
+	RecoveryStep     float64 `json:"recovery_step" yaml:"recovery_step"`         // 每次恢复最多增加的比例，默认 0.1
+	RecoveryInterval string  `json:"recovery_interval" yaml:"recovery_interval"` // 两次调整之间的最短间隔，只限制恢复，默认 1h
+}
+
+// Enabled 是否按市场状态缩放风险限额
+func (c RegimeConfig) Enabled() bool {
+	return c.VolatilityTarget > 0 || c.DrawdownTarget > 0
+}
+
+// Options 返回缩放参数
+func (c RegimeConfig) Options() regime.Options {
+	interval, _ := time.ParseDuration(c.RecoveryInterval)
+	return regime.Options{
+		VolatilityTarget: c.VolatilityTarget,
+		DrawdownTarget:   c.DrawdownTarget,
+		MinScale:         c.MinScale,
+		RecoveryStep:     c.RecoveryStep,
+		RecoveryInterval: interval,
+	}
+}
+
+// SentimentConfig 按交易对保存情绪分数，根据窗口内的情绪变化和情绪与价格的背离过滤开仓
+type SentimentConfig struct {
+	Window          string  `json:"window" yaml:"window"`                     // 情绪动量统计窗口，未配置时默认 24h
+	MaxDecline      float64 `json:"max_decline" yaml:"max_decline"`           // 窗口内情绪分数下降超过该值时不开仓，0 表示不过滤
+	BlockDivergence bool    `json:"block_divergence" yaml:"block_divergence"` // 价格上涨而情绪转差（看跌背离）时不开仓
+}
+
+// MomentumWindow 返回情绪动量统计窗口，未配置时默认 24h
+func (c SentimentConfig) MomentumWindow() time.Duration {
+	if d, err := time.ParseDuration(c.Window); err == nil && d > 0 {
+		return d
+	}
+	return 24 * time.Hour
+}
+
+// TradingStatusConfig 交易对暂停交易或下架时停止开仓；交易所公告下架时间后可在下架前自动平仓
+type TradingStatusConfig struct {
+	Delistings  map[string]string `json:"delistings" yaml:"delistings"`     // 交易对 -> 公告的下架时间(RFC3339)
+	CloseBefore string            `json:"close_before" yaml:"close_before"` // 下架前多久暂停交易对并平仓，为空时不自动平仓
+}
+
+// DelistingAt 返回交易对的下架时间
+func (c TradingStatusConfig) DelistingAt(symbol string) (time.Time, bool) {
+	value, ok := c.Delistings[symbol]
+	if !ok {
+		return time.Time{}, false
+	}
+	at, err := time.Parse(time.RFC3339, value)
+	return at, err == nil
+}
+
+// CloseLead 返回下架前平仓的提前量，未配置时返回 0，表示不自动平仓
+func (c TradingStatusConfig) CloseLead() time.Duration {
+	d, _ := time.ParseDuration(c.CloseBefore)
+	return d
+}
+
+// WalletStatusConfig 定期检查交易所系统维护和资产充提状态，提现暂停的资产停止买入，避免资金无法转出
+type WalletStatusConfig struct {
+	Interval      string `json:"interval" yaml:"interval"`             // 检查间隔(如 5m)，为空时不检查
+	BlockDeposits bool   `json:"block_deposits" yaml:"block_deposits"` // 充值暂停的资产同样停止买入
+}
+
+// CheckInterval 返回检查间隔，未配置时为 0
+func (c WalletStatusConfig) CheckInterval() time.Duration {
+	d, _ := time.ParseDuration(c.Interval)
+	return d
 }
 
 type Database struct {
-	ConnStr string `json:"conn_str" yaml:"conn_str"` // 数据库连接字符串
+	ConnStr string `json:"conn_str" yaml:"conn_str" secret:"true"` // 数据库连接字符串
+
+	// 备用数据库连接字符串，配置后行情和代币信息同时写入主库和备用库，任一写入成功即可，
+	// 主库故障时从备用库读取历史行情；为空时只使用主库
+	SecondaryConnStr string `json:"secondary_conn_str" yaml:"secondary_conn_str" secret:"true"`
+
+	// 同一交易对和数据源时间相差不超过该时长的行情视为重复，只保存第一条，避免进程快速重启后重复保存；
+	// 为空时只去除时间完全相同的行情
+	DedupTolerance string `json:"dedup_tolerance" yaml:"dedup_tolerance"`
+
+	// 只读副本连接字符串，配置后回测加载、报表和历史行情接口等大范围查询从副本读取，写入和实时交易循环只使用主库；
+	// 为空或连接失败时都读主库
+	ReplicaConnStr string `json:"replica_conn_str" yaml:"replica_conn_str" secret:"true"`
+}
+
+// Tolerance 返回行情去重的时间容差
+func (c Database) Tolerance() time.Duration {
+	d, _ := time.ParseDuration(c.DedupTolerance)
+	return d
 }
 
 type ExchangeConfig struct {
 	Debug     bool   `json:"debug" yaml:"debug"`
-	APIKey    string `json:"api_key" yaml:"api_key"`       // 交易所API密钥
-	SecretKey string `json:"secret_key" yaml:"secret_key"` // 交易所密钥
+	APIKey    string `json:"api_key" yaml:"api_key" secret:"true"`       // 交易所API密钥
+	SecretKey string `json:"secret_key" yaml:"secret_key" secret:"true"` // 交易所密钥
+
+	// 下单前按缓存的可用余额检查订单，余额不足时本地拒绝；缓存超过该时长重新查询账户，为空时不检查
+	BalanceCheckTTL string `json:"balance_check_ttl" yaml:"balance_check_ttl"`
+}
+
+// BalanceCheck 返回余额缓存的有效期，未配置或无效时为 0 表示不检查
+func (c ExchangeConfig) BalanceCheck() time.Duration {
+	if d, err := time.ParseDuration(c.BalanceCheckTTL); err == nil && d > 0 {
+		return d
+	}
+	return 0
+}
+
+// ExchangeBinance 交易账户使用的交易所
+const ExchangeBinance = "binance"
+
+// Exchanges 支持的交易所
+var Exchanges = []string{ExchangeBinance}
+
+// AIProviders 支持的 AI 服务商，模型类型以服务商名称为前缀，如 deepseek-chat
+var AIProviders = []string{"deepseek"}
+
+// MarketDataConfig 实时行情的来源。klines 时 Binance 支持的 K 线周期改为 WebSocket 推送，
+// 价格取收盘价，成交量和涨跌幅按 24 小时 K 线计算；不支持的周期(如 30s)仍轮询 24 小时行情
+type MarketDataConfig struct {
+	Source       string  `json:"source" yaml:"source"`               // 行情来源(ticker/klines)，默认 ticker
+	MaxDeviation float64 `json:"max_deviation" yaml:"max_deviation"` // 多个数据源时价格取报价中位数，单个数据源偏离中位数超过该比例时告警，0 表示不检查
+
+	Sources []DataSourceConfig `json:"sources" yaml:"sources"` // 实盘、模拟和影子模式使用的数据源，为空时只使用 Binance
+}
+
+// DataSourceConfig 一个数据源。代币信息和行情按 priority 从小到大依次尝试，前一个失败时使用下一个；
+// 多个数据源返回同名社交指标时取 priority 小的
+type DataSourceConfig struct {
+	Name      string            `json:"name" yaml:"name"`                     // 数据源(binance/coingecko/twitter)
+	Disabled  bool              `json:"disabled" yaml:"disabled"`             // 暂时停用，不必删除配置
+	APIKey    string            `json:"api_key" yaml:"api_key" secret:"true"` // coingecko 的 API key（可选）或 twitter 的 Bearer Token（必填）
+	BaseURL   string            `json:"base_url" yaml:"base_url"`             // 接口地址，为空时使用公开地址，CoinGecko 付费版填 https://pro-api.coingecko.com
+	Priority  int               `json:"priority" yaml:"priority"`             // 优先级，越小越先使用，相同时按配置顺序
+	RateLimit int               `json:"rate_limit" yaml:"rate_limit"`         // 每分钟请求数上限，超过时等待，0 为不限流
+	IDs       map[string]string `json:"ids" yaml:"ids"`                       // 基础资产在数据源中的标识：coingecko 为币种 ID（如 BTC: bitcoin，未配置时按代码搜索），twitter 为项目账号
+}
+
+// DataSourceConfigs 返回启用的数据源，按 priority 排序；未配置时只使用 Binance
+func (c MarketDataConfig) DataSourceConfigs() []DataSourceConfig {
+	if len(c.Sources) == 0 {
+		return []DataSourceConfig{{Name: DataSourceBinance}}
+	}
+	var sources []DataSourceConfig
+	for _, source := range c.Sources {
+		if !source.Disabled {
+			sources = append(sources, source)
+		}
+	}
+	slices.SortStableFunc(sources, func(a, b DataSourceConfig) int {
+		return a.Priority - b.Priority
+	})
+	return sources
+}
+
+// SocialScoreConfig 社交分数：各平台的社交指标按 log_scale 和 z_score 标准化后按 weights 加权求和，
+// 分数随原始指标一起保存
+type SocialScoreConfig struct {
+	Weights  map[string]float64 `json:"weights" yaml:"weights"`     // 指标 -> 权重，为空时使用默认权重，未列出的指标不计分
+	LogScale bool               `json:"log_scale" yaml:"log_scale"` // 指标先取 ln(1+x)，关注人数等跨数量级的指标不会压过其他指标
+	ZScore   bool               `json:"z_score" yaml:"z_score"`     // 按该指标在所有交易对最新值中的均值和标准差标准化，不同平台的指标可比
+}
+
+// Options 返回社交分数的计算参数
+func (c SocialScoreConfig) Options() social.Options {
+	return social.Options{Weights: c.Weights, LogScale: c.LogScale, ZScore: c.ZScore}
+}
+
+// CostConfig 交易成本：各交易所的 maker/taker 手续费率和市价单预估滑点，计入风险评估的潜在亏损
+type CostConfig struct {
+	Fees        map[string]FeeRates `json:"fees" yaml:"fees"`                 // 交易所 -> 手续费率
+	SlippageBps float64             `json:"slippage_bps" yaml:"slippage_bps"` // 市价单预估滑点（基点）
+}
+
+// FeeRates 手续费率，如 0.001 表示 0.1%
+type FeeRates struct {
+	Maker float64 `json:"maker" yaml:"maker"`
+	Taker float64 `json:"taker" yaml:"taker"`
+}
+
+// CostModel 返回交易所的交易成本模型，未配置该交易所时只计滑点
+func (c CostConfig) CostModel(exchange string) risk.CostModel {
+	fees := c.Fees[exchange]
+	return risk.CostModel{
+		MakerFeeRate: fees.Maker,
+		TakerFeeRate: fees.Taker,
+		SlippageBps:  c.SlippageBps,
+	}
+}
+
+// HasCredentials 是否配置了交易所密钥
+func (e ExchangeConfig) HasCredentials() bool {
+	return !isPlaceholder(e.APIKey) && !isPlaceholder(e.SecretKey)
+}
+
+// DefaultAccount 未配置 accounts 时唯一账户的名称
+const DefaultAccount = "default"
+
+type AccountConfig struct {
+	Name            string               `json:"name" yaml:"name"`                         // 账户名称，用于标记订单
+	Strategy        string               `json:"strategy" yaml:"strategy"`                 // 账户运行的策略，为空时使用 trading_config.strategy
+	Symbols         []string             `json:"symbols" yaml:"symbols"`                   // 账户交易的交易对，为空时交易全部
+	ExchangeConfig  ExchangeConfig       `json:"exchange_config" yaml:"exchange_config"`   // 账户的交易所密钥
+	RiskParams      *risk.RiskParameters `json:"risk_parameters" yaml:"risk_params"`       // 账户风险限额，为空时使用全局配置
+	InitialBalances map[string]float64   `json:"initial_balances" yaml:"initial_balances"` // 模拟交易初始余额，为空时使用 paper_config
+}
+
+// AccountConfigs 返回交易账户配置，未配置 accounts 时由全局配置生成唯一账户
+func (c *Config) AccountConfigs() []AccountConfig {
+	if len(c.Accounts) > 0 {
+		return c.Accounts
+	}
+	return []AccountConfig{{
+		Name:           DefaultAccount,
+		Strategy:       c.TradingConfig.Strategy,
+		ExchangeConfig: c.ExchangeConfig,
+	}}
+}
+
+// PairConfig 配对交易：y 与 x 的对数价格协整时，价差标准分数达到 entry_z 后买入被低估一侧的现货、
+// 开被高估一侧的合约空单，回落到 exit_z 以内平仓，超过 stop_z 止损
+type PairConfig struct {
+	Account  string  `json:"account" yaml:"account"`   // 下单账户，为空时使用第一个账户
+	Y        string  `json:"y" yaml:"y"`               // 被解释的交易对
+	X        string  `json:"x" yaml:"x"`               // 解释交易对
+	Window   int     `json:"window" yaml:"window"`     // 估计对冲比例和价差分布使用的样本数，至少 20
+	EntryZ   float64 `json:"entry_z" yaml:"entry_z"`   // 开仓阈值
+	ExitZ    float64 `json:"exit_z" yaml:"exit_z"`     // 平仓阈值，小于 entry_z
+	StopZ    float64 `json:"stop_z" yaml:"stop_z"`     // 止损阈值，大于 entry_z，0 表示不止损
+	Notional float64 `json:"notional" yaml:"notional"` // y 腿的名义金额（计价资产）
+}
+
+// Strategy 返回配对策略参数
+func (c PairConfig) Strategy(account string) pairs.Config {
+	return pairs.Config{
+		Account:  account,
+		Y:        c.Y,
+		X:        c.X,
+		Window:   c.Window,
+		EntryZ:   c.EntryZ,
+		ExitZ:    c.ExitZ,
+		StopZ:    c.StopZ,
+		Notional: c.Notional,
+	}
+}
+
+type PaperConfig struct {
+	InitialBalances map[string]float64 `json:"initial_balances" yaml:"initial_balances"`   // 初始资产余额
+	MinLatency      string             `json:"min_latency" yaml:"min_latency"`             // 模拟下单延迟下限，为空时不延迟
+	MaxLatency      string             `json:"max_latency" yaml:"max_latency"`             // 模拟下单延迟上限，为空时等于下限
+	RejectRate      float64            `json:"reject_rate" yaml:"reject_rate"`             // 随机拒单的概率
+	PartialFillRate float64            `json:"partial_fill_rate" yaml:"partial_fill_rate"` // 部分成交的概率
+	MinFillRatio    float64            `json:"min_fill_ratio" yaml:"min_fill_ratio"`       // 部分成交时成交比例的下限
+	Seed            int64              `json:"seed" yaml:"seed"`                           // 随机数种子，为 0 时每次运行不同
+}
+
+// Simulation 返回模拟执行器的成交条件
+func (c PaperConfig) Simulation() paper.Simulation {
+	minLatency, _ := time.ParseDuration(c.MinLatency)
+	maxLatency, _ := time.ParseDuration(c.MaxLatency)
+	if maxLatency < minLatency {
+		maxLatency = minLatency
+	}
+	return paper.Simulation{
+		MinLatency:      minLatency,
+		MaxLatency:      maxLatency,
+		RejectRate:      c.RejectRate,
+		PartialFillRate: c.PartialFillRate,
+		MinFillRatio:    c.MinFillRatio,
+		Seed:            c.Seed,
+	}
+}
+
+// ChaosConfig 在模拟交易中按概率注入数据源中断、存储延迟、AI 超时和交易所 5xx 错误，验证系统的故障处理
+type ChaosConfig struct {
+	Enabled   bool        `json:"enabled" yaml:"enabled"`
+	Seed      int64       `json:"seed" yaml:"seed"`           // 随机数种子，为 0 时每次运行不同
+	Collector FaultConfig `json:"collector" yaml:"collector"` // 行情和代币信息采集中断
+	Storage   FaultConfig `json:"storage" yaml:"storage"`     // 行情存储延迟和读写失败
+	AI        FaultConfig `json:"ai" yaml:"ai"`               // AI 分析延迟和超时
+	Exchange  FaultConfig `json:"exchange" yaml:"exchange"`   // 交易所下单和查询返回 5xx
+}
+
+// FaultConfig 一个组件的故障参数
+type FaultConfig struct {
+	ErrorRate float64 `json:"error_rate" yaml:"error_rate"` // 每次调用失败的概率
+	Latency   string  `json:"latency" yaml:"latency"`       // 每次调用额外增加的延迟，为空时不延迟
+	Outage    string  `json:"outage" yaml:"outage"`         // 失败后持续不可用的时长，为空时只有本次调用失败
+}
+
+// Fault 返回组件的故障参数
+func (c FaultConfig) Fault() chaos.Fault {
+	latency, _ := time.ParseDuration(c.Latency)
+	outage, _ := time.ParseDuration(c.Outage)
+	return chaos.Fault{Rate: c.ErrorRate, Latency: latency, Outage: outage}
+}
+
+// Options 返回故障注入参数
+func (c ChaosConfig) Options() chaos.Options {
+	return chaos.Options{
+		Seed: c.Seed,
+		Faults: map[chaos.Target]chaos.Fault{
+			chaos.TargetCollector: c.Collector.Fault(),
+			chaos.TargetStorage:   c.Storage.Fault(),
+			chaos.TargetAI:        c.AI.Fault(),
+			chaos.TargetExchange:  c.Exchange.Fault(),
+		},
+	}
+}
+
+// SupervisorConfig 长连接组件断开或退出后的重连退避，连续失败时等待时长从 initial_backoff 起逐次翻倍，不超过 max_backoff
+type SupervisorConfig struct {
+	InitialBackoff string  `json:"initial_backoff" yaml:"initial_backoff"` // 为空时为 1s
+	MaxBackoff     string  `json:"max_backoff" yaml:"max_backoff"`         // 为空时为 1m
+	Jitter         float64 `json:"jitter" yaml:"jitter"`                   // 等待时长随机缩短的最大比例，0-1
+}
+
+// Backoff 返回重连退避参数
+func (c SupervisorConfig) Backoff() supervisor.Backoff {
+	initial, _ := time.ParseDuration(c.InitialBackoff)
+	maxBackoff, _ := time.ParseDuration(c.MaxBackoff)
+	return supervisor.Backoff{Initial: initial, Max: maxBackoff, Jitter: c.Jitter}
+}
+
+type BacktestConfig struct {
+	Start        string `json:"start" yaml:"start"`                   // 回测开始时间(RFC3339)
+	End          string `json:"end" yaml:"end"`                       // 回测结束时间(RFC3339)
+	SocialMaxAge string `json:"social_max_age" yaml:"social_max_age"` // 早于行情时间超过该时长的社交指标快照视为缺失，为空时不限制
+}
+
+// SocialAge 返回社交指标快照的有效期，未配置时为 0
+func (c BacktestConfig) SocialAge() time.Duration {
+	d, _ := time.ParseDuration(c.SocialMaxAge)
+	return d
+}
+
+type APIConfig struct {
+	Addr        string            `json:"addr" yaml:"addr"`                           // 监听地址，如 "127.0.0.1:8080"，为空时不启动
+	GRPCAddr    string            `json:"grpc_addr" yaml:"grpc_addr"`                 // 只读 gRPC 接口的监听地址，如 "127.0.0.1:9090"，为空时不启动
+	Tokens      map[string]string `json:"tokens" yaml:"tokens" secret:"true"`         // 发起方名称 -> 访问令牌，修改类接口需携带，名称记录为审计发起方，等同于 control 角色的密钥
+	Keys        []APIKeyConfig    `json:"keys" yaml:"keys"`                           // 带角色和限流的 API 密钥
+	JWTSecret   string            `json:"jwt_secret" yaml:"jwt_secret" secret:"true"` // HS256 JWT 签名密钥，为空时不接受 JWT
+	RateLimit   int               `json:"rate_limit" yaml:"rate_limit"`               // 每个调用方每分钟的默认请求数上限，未认证请求按客户端地址计数，0 为不限流
+	RequireAuth bool              `json:"require_auth" yaml:"require_auth"`           // 查询类接口和 gRPC 接口也需认证
+}
+
+// APIKeyConfig 一个 API 密钥
+type APIKeyConfig struct {
+	Name      string `json:"name" yaml:"name"` // 发起方名称，记录为审计发起方
+	Key       string `json:"key" yaml:"key" secret:"true"`
+	Role      string `json:"role" yaml:"role"`             // read 只读查询，control 查询和交易控制
+	RateLimit int    `json:"rate_limit" yaml:"rate_limit"` // 每分钟请求数上限，0 时使用 api_config.rate_limit
+}
+
+// AuthOptions 返回 HTTP 和 gRPC 接口的认证配置，忽略未设置的密钥
+func (c APIConfig) AuthOptions() auth.Options {
+	opts := auth.Options{RateLimit: c.RateLimit, RequireAuth: c.RequireAuth}
+	for name, token := range c.ActorTokens() {
+		opts.Keys = append(opts.Keys, auth.Key{Name: name, Secret: token, Role: auth.RoleControl})
+	}
+	for _, key := range c.Keys {
+		if !isPlaceholder(key.Key) {
+			opts.Keys = append(opts.Keys, auth.Key{Name: key.Name, Secret: key.Key, Role: key.Role, RateLimit: key.RateLimit})
+		}
+	}
+	if !isPlaceholder(c.JWTSecret) {
+		opts.JWTSecret = c.JWTSecret
+	}
+	return opts
+}
+
+// ActorTokens 返回已设置的访问令牌，忽略空值和示例配置中的占位符
+func (c APIConfig) ActorTokens() map[string]string {
+	tokens := make(map[string]string)
+	for name, token := range c.Tokens {
+		if !isPlaceholder(token) {
+			tokens[name] = token
+		}
+	}
+	return tokens
+}
+
+type AuditConfig struct {
+	File string `json:"file" yaml:"file"` // 审计日志文件路径，为空时只写入数据库
+}
+
+// ArchiveConfig archive_data 任务的归档位置，destination 为 s3://bucket/prefix、gs://bucket/prefix 或 file:///path；
+// gs 通过 GCS 的 S3 兼容接口和 HMAC 密钥访问。归档文件固定为 gzip 压缩的 JSON Lines（.jsonl.gz），不支持 Parquet
+type ArchiveConfig struct {
+	Destination string   `json:"destination" yaml:"destination"`             // 归档位置
+	Endpoint    string   `json:"endpoint" yaml:"endpoint"`                   // 对象存储地址，为空时 s3 使用 AWS 区域地址，gs 使用 https://storage.googleapis.com
+	Region      string   `json:"region" yaml:"region"`                       // 签名使用的区域，为空时 s3 为 us-east-1，gs 为 auto
+	AccessKey   string   `json:"access_key" yaml:"access_key" secret:"true"` // 访问密钥 ID
+	SecretKey   string   `json:"secret_key" yaml:"secret_key" secret:"true"` // 访问密钥
+	Tables      []string `json:"tables" yaml:"tables"`                       // 归档的表，为空时归档全部支持的表
+}
+
+// Location 解析归档位置，返回协议（s3/gs/file）、存储桶（file 为目录）和对象前缀
+func (c ArchiveConfig) Location() (scheme, bucket, prefix string, err error) {
+	u, err := url.Parse(c.Destination)
+	if err != nil {
+		return "", "", "", err
+	}
+	switch u.Scheme {
+	case "s3", "gs":
+		if u.Host == "" {
+			return "", "", "", fmt.Errorf("missing bucket in %q", c.Destination)
+		}
+		return u.Scheme, u.Host, strings.Trim(u.Path, "/"), nil
+	case "file":
+		if u.Path == "" {
+			return "", "", "", fmt.Errorf("missing directory in %q", c.Destination)
+		}
+		return u.Scheme, u.Path, "", nil
+	default:
+		return "", "", "", fmt.Errorf("unsupported scheme %q, expected s3, gs or file", u.Scheme)
+	}
+}
+
+// ArchivedTables 返回归档的表，未配置时为全部支持的表
+func (c ArchiveConfig) ArchivedTables() []string {
+	if len(c.Tables) == 0 {
+		return ArchiveTables
+	}
+	return c.Tables
+}
+
+type NotifyConfig struct {
+	WebhookURL       string `json:"webhook_url" yaml:"webhook_url" secret:"true"`               // 通用 webhook 地址，兼容 Slack incoming webhook
+	TelegramBotToken string `json:"telegram_bot_token" yaml:"telegram_bot_token" secret:"true"` // Telegram Bot token
+	TelegramChatID   string `json:"telegram_chat_id" yaml:"telegram_chat_id"`                   // Telegram 接收消息的 chat id
+}
+
+// BotConfig 聊天机器人：通过 Telegram 或 Slack 命令查询持仓、盈亏和风控状态，暂停/恢复交易和清仓，
+// 只接受 allowed_chats 中会话的命令，所有命令写入审计日志
+type BotConfig struct {
+	Telegram           bool     `json:"telegram" yaml:"telegram"`                                       // 使用 notify_config.telegram_bot_token 长轮询接收命令
+	SlackSigningSecret string   `json:"slack_signing_secret" yaml:"slack_signing_secret" secret:"true"` // Slack App 的 signing secret，设置后在 API 服务上开放 POST /bot/slack
+	AllowedChats       []string `json:"allowed_chats" yaml:"allowed_chats"`                             // 允许发送命令的 Telegram chat id 或 Slack channel id
+}
+
+// SlackEnabled 是否接收 Slack slash command，忽略示例配置中的占位符
+func (c BotConfig) SlackEnabled() bool {
+	return !isPlaceholder(c.SlackSigningSecret)
+}
+
+type ShutdownConfig struct {
+	CancelOpenOrders bool   `json:"cancel_open_orders" yaml:"cancel_open_orders"` // 关闭时撤销未成交订单
+	Timeout          string `json:"timeout" yaml:"timeout"`                       // 关闭超时时间
+}
+
+type PipelineConfig struct {
+	Concurrency int `json:"concurrency" yaml:"concurrency"` // 同时处理行情的最大交易对数量
+	QueueSize   int `json:"queue_size" yaml:"queue_size"`   // 每个交易对的待处理队列长度
+
+	// 同一交易对排队的多条行情只分析最新一条，较早的行情仍保存、参与模拟撮合和 K 线聚合，只跳过分析
+	Coalesce bool `json:"coalesce" yaml:"coalesce"`
+}
+
+// EventFilterConfig 行情事件过滤：价格相对该交易对上次分析时的变动比例低于 min_price_change 时跳过 AI 分析和交易，
+// 行情保存、模拟撮合、配对交易和持仓风险监控照常进行；min_price_change 为 0 时不过滤
+type EventFilterConfig struct {
+	MinPriceChange float64 `json:"min_price_change" yaml:"min_price_change"` // 最小价格变动比例(如 0.002)
+	MaxQuietPeriod string  `json:"max_quiet_period" yaml:"max_quiet_period"` // 距上次分析超过该时长时不过滤(如 30m)，为空时只按价格变动过滤
+}
+
+// QuietPeriod 返回最长的连续过滤时长，未配置时为 0
+func (c EventFilterConfig) QuietPeriod() time.Duration {
+	d, _ := time.ParseDuration(c.MaxQuietPeriod)
+	return d
+}
+
+// 事件流的消息系统
+const (
+	StreamNATS  = "nats"
+	StreamKafka = "kafka"
+)
+
+// StreamConfig 事件流：将行情、AI 信号、交易（订单）和风险预警发布到 NATS 或 Kafka，主题为 <prefix>.<事件类型>，
+// 外部系统无需查询数据库即可订阅；driver 为空时不启用，回测时不发布
+type StreamConfig struct {
+	Driver     string   `json:"driver" yaml:"driver"`           // nats 或 kafka
+	URL        string   `json:"url" yaml:"url" secret:"true"`   // NATS 地址(nats://token@host:4222)或 Kafka REST Proxy 地址(http://host:8082)
+	Prefix     string   `json:"prefix" yaml:"prefix"`           // 主题前缀，默认 quantaflux
+	Events     []string `json:"events" yaml:"events"`           // 发布的事件类型，为空时发布全部
+	BufferSize int      `json:"buffer_size" yaml:"buffer_size"` // 待发布事件的队列长度，队列满时丢弃，默认 1024
+}
+
+// Enabled 是否启用事件流
+func (c StreamConfig) Enabled() bool {
+	return c.Driver != ""
+}
+
+// Options 返回事件流参数
+func (c StreamConfig) Options() stream.Options {
+	return stream.Options{Prefix: c.Prefix, Events: c.Events, BufferSize: c.BufferSize}
+}
+
+type LatencyBudget struct {
+	AI    string `json:"ai" yaml:"ai"`       // AI 分析阶段总耗时上限，超时跳过该条行情，避免按过期价格交易
+	Risk  string `json:"risk" yaml:"risk"`   // 单个账户风险检查的耗时上限
+	Order string `json:"order" yaml:"order"` // 单个账户下单的耗时上限
+}
+
+// StageBudget 返回阶段的耗时预算，未配置时返回 0 表示不限时
+func (c *Config) StageBudget(stage string) time.Duration {
+	var value string
+	switch stage {
+	case StageAI:
+		value = c.LatencyBudget.AI
+	case StageRisk:
+		value = c.LatencyBudget.Risk
+	case StageOrder:
+		value = c.LatencyBudget.Order
+	}
+	budget, err := time.ParseDuration(value)
+	if err != nil || budget < 0 {
+		return 0
+	}
+	return budget
+}
+
+// 交易所允许的最大 recvWindow
+const MaxRecvWindow = time.Minute
+
+type TimeSyncConfig struct {
+	RecvWindow string `json:"recv_window" yaml:"recv_window"` // 签名请求的有效时间窗口(如 5s)，最大 1m，为空时使用交易所默认值
+	Interval   string `json:"interval" yaml:"interval"`       // 定期同步服务器时间的间隔(如 30m)，为空时只在启动和时间戳被拒绝时同步
+	MaxDrift   string `json:"max_drift" yaml:"max_drift"`     // 本地时钟偏差超过该值时告警(如 1s)，为空时不告警
+}
+
+type JobConfig struct {
+	Name      string `json:"name" yaml:"name"`           // 任务名称
+	Type      string `json:"type" yaml:"type"`           // 任务类型
+	Interval  string `json:"interval" yaml:"interval"`   // 执行间隔
+	Retention string `json:"retention" yaml:"retention"` // 数据保留时长(prune_data)
+}
+
+// LogConfig 日志级别、格式和输出，modules 为各模块（collector、pipeline、scheduler、api、audit、notify、tracing）单独设置级别
+type LogConfig struct {
+	Level      string            `json:"level" yaml:"level"`             // debug/info/warn/error，默认 info
+	Format     string            `json:"format" yaml:"format"`           // json/text，默认 json
+	Output     string            `json:"output" yaml:"output"`           // stdout/stderr 或文件路径，默认 stdout
+	MaxSizeMB  int               `json:"max_size_mb" yaml:"max_size_mb"` // 输出到文件时超过该大小轮转，0 表示不轮转
+	MaxBackups int               `json:"max_backups" yaml:"max_backups"` // 保留的轮转文件数量，0 表示全部保留
+	Modules    map[string]string `json:"modules" yaml:"modules"`         // 模块 -> 级别
+}
+
+type TracingConfig struct {
+	Enabled       bool   `json:"enabled" yaml:"enabled"`               // 是否开启调用链追踪
+	MaxTraces     int    `json:"max_traces" yaml:"max_traces"`         // 内存中保留的调用链数量
+	SlowThreshold string `json:"slow_threshold" yaml:"slow_threshold"` // 超过该耗时的调用链写入日志，为空时不记录
 }
@@ -0,0 +1,818 @@
+package configs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/ai"
+	"github.com/songzhibin97/quantaflux/internal/auth"
+	"github.com/songzhibin97/quantaflux/internal/chaos"
+	"github.com/songzhibin97/quantaflux/internal/risk"
+	"github.com/songzhibin97/quantaflux/internal/supervisor"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	old := &Config{
+		Symbols:        []string{"BTCUSDT"},
+		RiskParams:     risk.RiskParameters{MaxPositionSize: 1000},
+		ExchangeConfig: ExchangeConfig{APIKey: "old"},
+	}
+	new := &Config{
+		Symbols:        []string{"BTCUSDT", "ETHUSDT"},
+		RiskParams:     risk.RiskParameters{MaxPositionSize: 2000},
+		ExchangeConfig: ExchangeConfig{APIKey: "new"},
+	}
+
+	changes := Diff(old, new)
+	assert.ElementsMatch(t, []string{
+		"symbols: [BTCUSDT] -> [BTCUSDT ETHUSDT]",
+		"risk_parameters.max_position_size: 1000 -> 2000",
+		"exchange_config.api_key: changed",
+	}, changes)
+
+	assert.Empty(t, Diff(old, old))
+
+	before, after := 0.7, 0.8
+	old.SymbolOverrides = map[string]SymbolConfig{"BTCUSDT": {MinConfidence: &before}}
+	new.SymbolOverrides = map[string]SymbolConfig{"BTCUSDT": {MinConfidence: &after, Strategy: "momentum"}}
+	changes = Diff(old, new)
+	assert.Contains(t, changes, "symbol_overrides.BTCUSDT.min_confidence: 0.7 -> 0.8")
+	assert.Contains(t, changes, "symbol_overrides.BTCUSDT.strategy:  -> momentum")
+}
+
+func TestDiff_Secrets(t *testing.T) {
+	const secret = "SECRETPLAIN"
+	tests := []struct {
+		name   string
+		set    func(c *Config)
+		change string
+	}{
+		{"ai api key", func(c *Config) { c.AIConfig.APIKey = secret }, "ai_config.api_key: changed"},
+		{"challenger api key", func(c *Config) { c.AIConfig.Challenger.APIKey = secret }, "ai_config.challenger.api_key: changed"},
+		{"database", func(c *Config) { c.Database.ConnStr = secret }, "database.conn_str: changed"},
+		{"secondary database", func(c *Config) { c.Database.SecondaryConnStr = secret }, "database.secondary_conn_str: changed"},
+		{"replica database", func(c *Config) { c.Database.ReplicaConnStr = secret }, "database.replica_conn_str: changed"},
+		{"exchange api key", func(c *Config) { c.ExchangeConfig.APIKey = secret }, "exchange_config.api_key: changed"},
+		{"exchange secret key", func(c *Config) { c.ExchangeConfig.SecretKey = secret }, "exchange_config.secret_key: changed"},
+		{"whale api key", func(c *Config) { c.WhaleConfig.APIKey = secret }, "whale_config.api_key: changed"},
+		{"archive access key", func(c *Config) { c.ArchiveConfig.AccessKey = secret }, "archive_config.access_key: changed"},
+		{"archive secret key", func(c *Config) { c.ArchiveConfig.SecretKey = secret }, "archive_config.secret_key: changed"},
+		{"webhook url", func(c *Config) { c.NotifyConfig.WebhookURL = "https://hooks.slack.com/services/" + secret }, "notify_config.webhook_url: changed"},
+		{"telegram bot token", func(c *Config) { c.NotifyConfig.TelegramBotToken = secret }, "notify_config.telegram_bot_token: changed"},
+		{"slack signing secret", func(c *Config) { c.BotConfig.SlackSigningSecret = secret }, "bot_config.slack_signing_secret: changed"},
+		{"jwt secret", func(c *Config) { c.APIConfig.JWTSecret = secret }, "api_config.jwt_secret: changed"},
+		{
+			"api key",
+			func(c *Config) { c.APIConfig.Keys = []APIKeyConfig{{Name: "grafana", Key: secret, Role: "read"}} },
+			"api_config.keys[0].key: changed",
+		},
+		{"stream url", func(c *Config) { c.StreamConfig.URL = "nats://" + secret + "@localhost:4222" }, "stream_config.url: changed"},
+		{
+			"data source api key",
+			func(c *Config) {
+				c.MarketDataConfig.Sources = []DataSourceConfig{{Name: DataSourceTwitter, APIKey: secret}}
+			},
+			"market_data_config.sources[0].api_key: changed",
+		},
+		{
+			"rpc url",
+			func(c *Config) {
+				c.LiquidityConfig.Networks = map[string]NetworkConfig{"ethereum": {RPCURL: "https://mainnet.infura.io/v3/" + secret}}
+			},
+			"liquidity_config.networks.ethereum.rpc_url: changed",
+		},
+		{
+			// 新增的账户逐项比较，不整体输出
+			"account exchange keys",
+			func(c *Config) {
+				c.Accounts = []AccountConfig{{Name: "x", ExchangeConfig: ExchangeConfig{APIKey: secret, SecretKey: secret}}}
+			},
+			"accounts[0].exchange_config.secret_key: changed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old, new := validConfig(), validConfig()
+			tt.set(new)
+			for _, changes := range [][]string{Diff(old, new), Diff(new, old)} {
+				for _, change := range changes {
+					assert.NotContains(t, change, secret)
+				}
+			}
+			assert.Contains(t, Diff(old, new), tt.change)
+		})
+	}
+
+	// 访问令牌只输出增删和修改的发起方名称
+	old, new := validConfig(), validConfig()
+	old.APIConfig.Tokens = map[string]string{"ops": secret, "ci": secret}
+	new.APIConfig.Tokens = map[string]string{"ops": secret + "2", "grafana": secret}
+	assert.Equal(t, []string{"api_config.tokens.ci: removed", "api_config.tokens.grafana: added", "api_config.tokens.ops: changed"}, Diff(old, new))
+
+	old, new = validConfig(), validConfig()
+	new.Accounts = []AccountConfig{{Name: "x", ExchangeConfig: ExchangeConfig{APIKey: secret}}}
+	assert.Equal(t, []string{"accounts[0]: added", "accounts[0].name:  -> x", "accounts[0].exchange_config.api_key: changed"}, Diff(old, new))
+	assert.Equal(t, []string{"accounts[0]: removed", "accounts[0].name: x -> ", "accounts[0].exchange_config.api_key: changed"}, Diff(new, old))
+}
+
+func validConfig() *Config {
+	return &Config{
+		Mode:            ModePaper,
+		Symbols:         []string{"BTCUSDT"},
+		RefreshInterval: "1m",
+		Database:        Database{ConnStr: "postgresql://localhost/quantaflux"},
+		AIConfig:        AIConfig{APIKey: "key", MinConfidence: 0.7, ScamThreshold: 0.8},
+		RiskParams: risk.RiskParameters{
+			MaxPositionSize: 1000,
+			MaxLossPerTrade: 100,
+			MaxDailyLoss:    500,
+			MaxLeverage:     1,
+			MinLiquidity:    1000,
+		},
+	}
+}
+
+func TestMarketDataConfig_DataSourceConfigs(t *testing.T) {
+	assert.Equal(t, []DataSourceConfig{{Name: DataSourceBinance}}, MarketDataConfig{}.DataSourceConfigs())
+
+	c := MarketDataConfig{Sources: []DataSourceConfig{
+		{Name: DataSourceTwitter, Priority: 2},
+		{Name: DataSourceCoinGecko, Priority: 1},
+		{Name: DataSourceBinance, Priority: 1, Disabled: true},
+		{Name: "other", Priority: 1},
+	}}
+	var names []string
+	for _, source := range c.DataSourceConfigs() {
+		names = append(names, source.Name)
+	}
+	assert.Equal(t, []string{DataSourceCoinGecko, "other", DataSourceTwitter}, names)
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, validConfig().Validate())
+
+	live := validConfig()
+	live.Mode = ModeLive
+	live.ExchangeConfig.APIKey = "<bn api_key>"
+	err := live.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exchange_config")
+
+	// 影子模式不需要交易所密钥
+	shadow := validConfig()
+	shadow.Mode = ModeShadow
+	assert.NoError(t, shadow.Validate())
+
+	candle := validConfig()
+	candle.TradingConfig.CandleInterval = "five minutes"
+	err = candle.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "trading_config.candle_interval")
+
+	unit := validConfig()
+	unit.TradingConfig.AmountUnit = "usd"
+	err = unit.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `trading_config.amount_unit: unknown amount unit "usd"`)
+
+	maker := validConfig()
+	maker.TradingConfig.OrderType = "maker"
+	assert.NoError(t, maker.Validate())
+	maker.TradingConfig.Maker = MakerConfig{Wait: "soon", PriceImprovement: 1}
+	err = maker.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "trading_config.maker.wait")
+	assert.Contains(t, err.Error(), "trading_config.maker.price_improvement")
+	assert.Equal(t, 2*time.Second, maker.TradingConfig.Maker.Options().PollInterval)
+
+	bracket := validConfig()
+	bracket.TradingConfig.Bracket = BracketConfig{TakeProfit: 0.06, StopLoss: 0.03}
+	assert.NoError(t, bracket.Validate())
+	assert.True(t, bracket.TradingConfig.Bracket.Enabled())
+	bracket.TradingConfig.OrderType = "maker"
+	bracket.TradingConfig.Bracket.StopLoss = 1
+	err = bracket.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "trading_config.bracket: take_profit must be positive")
+	assert.Contains(t, err.Error(), "trading_config.bracket: is not supported with order type maker")
+
+	balance := validConfig()
+	balance.ExchangeConfig.BalanceCheckTTL = "-1s"
+	balance.Accounts = []AccountConfig{{Name: "main", ExchangeConfig: ExchangeConfig{BalanceCheckTTL: "often"}}}
+	err = balance.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exchange_config.balance_check_ttl")
+	assert.Contains(t, err.Error(), "accounts[0].exchange_config.balance_check_ttl")
+	assert.Zero(t, balance.ExchangeConfig.BalanceCheck())
+
+	reanalysis := validConfig()
+	reanalysis.AIConfig.Reanalysis = ReanalysisConfig{SocialSpike: 0.5, Cooldown: "0s"}
+	err = reanalysis.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ai_config.reanalysis.social_spike")
+	assert.Contains(t, err.Error(), "ai_config.reanalysis.cooldown")
+
+	queue := validConfig()
+	queue.AIConfig.Queue.MaxConcurrent = -1
+	err = queue.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ai_config.queue.max_concurrent")
+
+	wallet := validConfig()
+	wallet.WalletStatusConfig.Interval = "hourly"
+	err = wallet.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "wallet_status_config.interval")
+
+	mirror := validConfig()
+	mirror.Database.SecondaryConnStr = mirror.Database.ConnStr
+	err = mirror.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "database.secondary_conn_str")
+
+	replica := validConfig()
+	replica.Database.ReplicaConnStr = replica.Database.ConnStr
+	err = replica.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "database.replica_conn_str")
+	replica.Database.ReplicaConnStr = "postgresql://replica:5432/quantaflux"
+	require.NoError(t, replica.Validate())
+
+	archive := validConfig()
+	archive.Jobs = []JobConfig{{Name: "archive", Type: JobArchiveData, Interval: "24h", Retention: "720h"}}
+	err = archive.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "archive_config.destination: is required")
+	archive.ArchiveConfig = ArchiveConfig{Destination: "ftp://host/archive", Tables: []string{"orders"}}
+	err = archive.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unsupported scheme "ftp"`)
+	assert.Contains(t, err.Error(), "archive_config.tables[0]")
+	archive.ArchiveConfig = ArchiveConfig{Destination: "gs://bucket/quantaflux/prod"}
+	require.NoError(t, archive.Validate())
+	scheme, bucket, prefix, err := archive.ArchiveConfig.Location()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"gs", "bucket", "quantaflux/prod"}, []string{scheme, bucket, prefix})
+
+	arb := validConfig()
+	arb.Jobs = []JobConfig{{Name: "arbitrage", Type: JobDetectArbitrage, Interval: "1m"}}
+	arb.ArbitrageConfig = ArbitrageConfig{Venues: []ArbitrageVenue{{Source: DataSourceBinance}}}
+	err = arb.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "arbitrage_config.venues: at least two venues")
+	arb.ArbitrageConfig = ArbitrageConfig{
+		Venues:  []ArbitrageVenue{{Source: DataSourceBinance, Account: DefaultAccount}, {Source: DataSourceCoinGecko, Account: "other"}},
+		Symbols: []string{"ETHUSDT"},
+		Amount:  0.1,
+	}
+	err = arb.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `arbitrage_config.venues[1].source: "coingecko" is not an enabled`)
+	assert.Contains(t, err.Error(), `arbitrage_config.venues[1].account: unknown account "other"`)
+	assert.Contains(t, err.Error(), `arbitrage_config.symbols: "ETHUSDT" is not in symbols`)
+	arb.MarketDataConfig.Sources = []DataSourceConfig{{Name: DataSourceBinance}, {Name: DataSourceCoinGecko}}
+	arb.ArbitrageConfig.Venues[1].Account = ""
+	arb.ArbitrageConfig.Symbols = nil
+	err = arb.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "arbitrage_config.venues[1].account: is required")
+	arb.ArbitrageConfig.Amount = 0
+	require.NoError(t, arb.Validate())
+
+	chaosConfig := validConfig()
+	chaosConfig.Mode = ModeShadow
+	chaosConfig.ChaosConfig = ChaosConfig{Enabled: true, AI: FaultConfig{ErrorRate: 1.5, Latency: "soon"}}
+	err = chaosConfig.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "chaos_config.enabled: fault injection is only allowed in paper mode")
+	assert.Contains(t, err.Error(), "chaos_config.ai.error_rate")
+	assert.Contains(t, err.Error(), "chaos_config.ai.latency")
+	chaosConfig.Mode = ModePaper
+	chaosConfig.ChaosConfig.AI = FaultConfig{ErrorRate: 0.5, Latency: "2s"}
+	require.NoError(t, chaosConfig.Validate())
+	assert.Equal(t, chaos.Fault{Rate: 0.5, Latency: 2 * time.Second}, chaosConfig.ChaosConfig.Options().Faults[chaos.TargetAI])
+
+	exits := validConfig()
+	exits.PositionMonitorConfig.TakeProfit = []TakeProfitLevel{{Gain: 0.1, Fraction: 0.6}, {Gain: 0.05, Fraction: 0.6}}
+	exits.SymbolOverrides = map[string]SymbolConfig{"BTCUSDT": {TakeProfit: []TakeProfitLevel{{Gain: 0.2, Fraction: 1.5}}}}
+	err = exits.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "position_monitor_config.take_profit[1].gain")
+	assert.Contains(t, err.Error(), "position_monitor_config.take_profit: fractions add up to 1.2")
+	assert.Contains(t, err.Error(), "symbol_overrides.BTCUSDT.take_profit[0].fraction")
+	exits.PositionMonitorConfig.TakeProfit = []TakeProfitLevel{{Gain: 0.05, Fraction: 0.3}, {Gain: 0.1, Fraction: 0.3}}
+	exits.SymbolOverrides = map[string]SymbolConfig{"BTCUSDT": {TakeProfit: []TakeProfitLevel{}}}
+	require.NoError(t, exits.Validate())
+	assert.Empty(t, exits.ForSymbol("BTCUSDT").TakeProfit)
+	assert.Len(t, exits.ForSymbol("ETHUSDT").TakeProfit, 2)
+	assert.Equal(t, 0.5, exits.PositionMonitorConfig.ReduceRatio())
+
+	book := validConfig()
+	book.OrderBookConfig = OrderBookConfig{Interval: "0s", Band: 1, MaxAskImbalance: 1.5}
+	err = book.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "order_book_config.interval")
+	assert.Contains(t, err.Error(), "order_book_config.band")
+	assert.Contains(t, err.Error(), "order_book_config.max_ask_imbalance")
+	book.OrderBookConfig = OrderBookConfig{Interval: "10s", Band: 0.02, MaxAskImbalance: 0.5}
+	require.NoError(t, book.Validate())
+	assert.Equal(t, 10*time.Second, book.OrderBookConfig.PollInterval())
+
+	dedup := validConfig()
+	dedup.Database.DedupTolerance = "-1s"
+	err = dedup.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "database.dedup_tolerance")
+	dedup.Database.DedupTolerance = "5s"
+	require.NoError(t, dedup.Validate())
+	assert.Equal(t, 5*time.Second, dedup.Database.Tolerance())
+
+	sup := validConfig()
+	sup.SupervisorConfig = SupervisorConfig{InitialBackoff: "10s", MaxBackoff: "5s", Jitter: 1.5}
+	err = sup.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "supervisor_config.max_backoff: must not be less than initial_backoff")
+	assert.Contains(t, err.Error(), "supervisor_config.jitter")
+	sup.SupervisorConfig = SupervisorConfig{InitialBackoff: "2s", MaxBackoff: "30s", Jitter: 0.1}
+	require.NoError(t, sup.Validate())
+	assert.Equal(t, supervisor.Backoff{Initial: 2 * time.Second, Max: 30 * time.Second, Jitter: 0.1}, sup.SupervisorConfig.Backoff())
+
+	execution := validConfig()
+	execution.ExecutionConfig = ExecutionConfig{RateLimit: -1, IdempotencyWindow: "soon"}
+	err = execution.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "execution_config.rate_limit")
+	assert.Contains(t, err.Error(), "execution_config.idempotency_window")
+	execution.ExecutionConfig = ExecutionConfig{RateLimit: 10, IdempotencyWindow: "1m"}
+	require.NoError(t, execution.Validate())
+	assert.Equal(t, time.Minute, execution.ExecutionConfig.Window())
+
+	regime := validConfig()
+	regime.RegimeConfig = RegimeConfig{VolatilityTarget: 0.8, DrawdownTarget: 1.5, MinScale: 2, RecoveryInterval: "hourly"}
+	err = regime.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "regime_config: volatility_target")
+	assert.Contains(t, err.Error(), "regime_config.volatility_target: requires volatility_config.interval")
+	assert.Contains(t, err.Error(), "regime_config.min_scale")
+	assert.Contains(t, err.Error(), "regime_config.recovery_interval")
+	regime.RegimeConfig = RegimeConfig{DrawdownTarget: 0.05, RecoveryInterval: "30m"}
+	require.NoError(t, regime.Validate())
+	assert.True(t, regime.RegimeConfig.Enabled())
+	assert.Equal(t, 30*time.Minute, regime.RegimeConfig.Options().RecoveryInterval)
+
+	spend := validConfig()
+	spend.TradingConfig.SpendLimit = SpendLimitConfig{PerMinute: -1, PerDay: 1000}
+	err = spend.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "trading_config.spend_limit.per_minute")
+	spend.TradingConfig.SpendLimit.PerMinute = 0
+	require.NoError(t, spend.Validate())
+	assert.Equal(t, []risk.SpendLimit{{Window: 24 * time.Hour, Max: 1000}}, spend.TradingConfig.SpendLimit.Limits())
+
+	logConfig := validConfig()
+	logConfig.LogConfig = LogConfig{Level: "verbose", Format: "xml", Modules: map[string]string{"pipeline": "debug", "api": "loud"}}
+	err = logConfig.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "log_config.level")
+	assert.Contains(t, err.Error(), `log_config.format: unknown format "xml"`)
+	assert.Contains(t, err.Error(), "log_config.modules.api")
+	assert.NotContains(t, err.Error(), "log_config.modules.pipeline")
+
+	temperature, topP := 2.5, 0.95
+	generation := validConfig()
+	generation.AIConfig.TopP = &topP
+	generation.AIConfig.Methods = map[string]ai.GenerationParams{
+		ai.MethodDetectScam: {Temperature: &temperature},
+		"summarize":         {},
+	}
+	err = generation.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ai_config.methods.detect_scam.temperature")
+	assert.Contains(t, err.Error(), "ai_config.methods.summarize: unknown analysis method")
+	assert.NotContains(t, err.Error(), "ai_config.top_p")
+
+	policy := validConfig()
+	policy.ErrorPolicy = map[string]string{ErrorClassExchange: "retry", "network": ErrorPolicySkip}
+	err = policy.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `error_policy.exchange: unknown policy "retry"`)
+	assert.Contains(t, err.Error(), "error_policy.network: unknown error class")
+
+	weights := validConfig()
+	weights.SocialScoreConfig.Weights = map[string]float64{"twitter_followers": -1, "github_stars": 0.5}
+	err = weights.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "social_score_config.weights.twitter_followers")
+	assert.NotContains(t, err.Error(), "social_score_config.weights.github_stars")
+
+	sources := validConfig()
+	sources.MarketDataConfig.Source = MarketDataKlines
+	sources.MarketDataConfig.Sources = []DataSourceConfig{
+		{Name: DataSourceCoinGecko, RateLimit: -1, BaseURL: "pro-api.coingecko.com"},
+		{Name: DataSourceTwitter},
+		{Name: DataSourceCoinGecko},
+		{Name: "coinmarketcap"},
+	}
+	err = sources.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "market_data_config.sources[0].rate_limit")
+	assert.Contains(t, err.Error(), "market_data_config.sources[0].base_url")
+	assert.Contains(t, err.Error(), "market_data_config.sources[1].api_key")
+	assert.Contains(t, err.Error(), "market_data_config.sources[1].ids")
+	assert.Contains(t, err.Error(), `market_data_config.sources[2].name: duplicate data source "coingecko"`)
+	assert.Contains(t, err.Error(), `market_data_config.sources[3].name: unknown data source "coinmarketcap"`)
+	assert.Contains(t, err.Error(), "market_data_config.source: klines streams from binance")
+
+	social := validConfig()
+	social.MarketDataConfig.Sources = []DataSourceConfig{{Name: DataSourceTwitter, APIKey: "token", IDs: map[string]string{"BTC": "bitcoin"}}}
+	err = social.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "market_data_config.sources: at least one enabled binance or coingecko source")
+
+	accounts := validConfig()
+	accounts.Accounts = []AccountConfig{
+		{Name: "trend", Symbols: []string{"BTCUSDT"}},
+		{Name: "trend", Symbols: []string{"DOGEUSDT"}, RiskParams: &risk.RiskParameters{}},
+	}
+	err = accounts.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `accounts[1].name: duplicate account name "trend"`)
+	assert.Contains(t, err.Error(), `accounts[1].symbols: "DOGEUSDT" is not in symbols`)
+	assert.Contains(t, err.Error(), "accounts[1].risk_parameters")
+
+	minConfidence, minAmount := 1.5, 0.5
+	overrides := validConfig()
+	overrides.TradingConfig = TradingConfig{MinOrderAmount: 0.01, MaxOrderAmount: 0.1}
+	overrides.Accounts = []AccountConfig{{Name: "trend", Strategy: "trend"}}
+	overrides.SymbolOverrides = map[string]SymbolConfig{
+		"BTCUSDT": {
+			MinConfidence:   &minConfidence,
+			MinOrderAmount:  &minAmount,
+			RefreshInterval: "-1s",
+			RiskParams:      &risk.RiskParameters{MaxPositionSize: 100},
+			Strategy:        "mean_reversion",
+		},
+		"DOGEUSDT": {},
+	}
+	err = overrides.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `symbol_overrides.DOGEUSDT: "DOGEUSDT" is not in symbols`)
+	assert.Contains(t, err.Error(), "symbol_overrides.BTCUSDT.min_confidence")
+	assert.Contains(t, err.Error(), "symbol_overrides.BTCUSDT.min_order_amount: 0.5 must not exceed max_order_amount 0.1")
+	assert.Contains(t, err.Error(), "symbol_overrides.BTCUSDT.refresh_interval")
+	assert.Contains(t, err.Error(), "symbol_overrides.BTCUSDT.risk_parameters")
+	assert.Contains(t, err.Error(), `symbol_overrides.BTCUSDT.strategy: "mean_reversion" conflicts with accounts[0].strategy "trend"`)
+
+	// 标签覆盖项不要求出现在 symbols 中
+	overrides.SymbolOverrides = nil
+	overrides.TagOverrides = map[string]SymbolConfig{
+		"meme": {MinConfidence: &minConfidence, Strategy: "mean_reversion"},
+		"L1":   {},
+	}
+	err = overrides.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "tag_overrides.meme.min_confidence")
+	assert.Contains(t, err.Error(), `tag_overrides.meme.strategy: "mean_reversion" conflicts with accounts[0].strategy "trend"`)
+	assert.NotContains(t, err.Error(), "tag_overrides.L1")
+
+	autoDisable := validConfig()
+	autoDisable.AutoDisableConfig = AutoDisableConfig{MaxConsecutiveLosses: -1, RollingWindow: "1d", ReviewPeriod: "0s"}
+	err = autoDisable.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "auto_disable_config.max_consecutive_losses")
+	assert.Contains(t, err.Error(), "auto_disable_config.rolling_window")
+	assert.Contains(t, err.Error(), "auto_disable_config.review_period")
+
+	history := validConfig()
+	history.AIConfig.PredictHistory = "one day"
+	err = history.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ai_config.predict_history")
+
+	budget := validConfig()
+	budget.LatencyBudget = LatencyBudget{AI: "5s", Order: "fast"}
+	err = budget.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "latency_budget.order")
+	assert.NotContains(t, err.Error(), "latency_budget.ai")
+
+	timeSync := validConfig()
+	timeSync.TimeSyncConfig = TimeSyncConfig{RecvWindow: "90s", Interval: "30m", MaxDrift: "-1s"}
+	err = timeSync.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "time_sync_config.recv_window: 1m30s exceeds the exchange limit")
+	assert.Contains(t, err.Error(), "time_sync_config.max_drift")
+	assert.NotContains(t, err.Error(), "time_sync_config.interval")
+
+	notify := validConfig()
+	notify.NotifyConfig.TelegramBotToken = "token"
+	notify.Jobs = []JobConfig{{Name: "daily_pnl_report", Type: JobPnLReport, Interval: "24h"}}
+	err = notify.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "notify_config")
+	assert.NotContains(t, err.Error(), "jobs[0]")
+
+	chatBot := validConfig()
+	chatBot.BotConfig = BotConfig{Telegram: true, SlackSigningSecret: "secret"}
+	err = chatBot.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "bot_config.telegram: requires notify_config.telegram_bot_token")
+	assert.Contains(t, err.Error(), "bot_config.slack_signing_secret: requires api_config.addr")
+	assert.Contains(t, err.Error(), "bot_config.allowed_chats")
+
+	grpc := validConfig()
+	grpc.APIConfig = APIConfig{Addr: "127.0.0.1:8080", GRPCAddr: "127.0.0.1:8080"}
+	err = grpc.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "api_config.grpc_addr")
+
+	keys := validConfig()
+	keys.APIConfig = APIConfig{
+		Tokens:    map[string]string{"cli": "cli-token"},
+		JWTSecret: "short",
+		RateLimit: -1,
+		Keys: []APIKeyConfig{
+			{Name: "cli", Key: "k1", Role: "read"},
+			{Name: "grafana", Key: "k2", Role: "admin", RateLimit: -5},
+		},
+	}
+	err = keys.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "api_config.jwt_secret")
+	assert.Contains(t, err.Error(), "api_config.rate_limit")
+	assert.Contains(t, err.Error(), `api_config.keys[0].name: duplicate name "cli"`)
+	assert.Contains(t, err.Error(), "api_config.keys[1].role")
+	assert.Contains(t, err.Error(), "api_config.keys[1].rate_limit")
+
+	pairConfigs := validConfig()
+	pairConfigs.Symbols = []string{"BTCUSDT", "ETHUSDT"}
+	pairConfigs.Pairs = []PairConfig{
+		{Y: "BTCUSDT", X: "ETHUSDT", Window: 60, EntryZ: 2, ExitZ: 0.5, StopZ: 4, Notional: 100},
+		{Account: "trend", Y: "BTCUSDT", X: "BTCUSDT", Window: 10, EntryZ: 2, ExitZ: 2, StopZ: 1},
+	}
+	err = pairConfigs.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `pairs[1].account: unknown account "trend"`)
+	assert.Contains(t, err.Error(), "pairs[1]: y and x must be different symbols")
+	assert.Contains(t, err.Error(), "pairs[1].window")
+	assert.Contains(t, err.Error(), "pairs[1]: exit_z must be non-negative and less than entry_z")
+	assert.Contains(t, err.Error(), "pairs[1].stop_z")
+	assert.Contains(t, err.Error(), "pairs[1].notional")
+	assert.NotContains(t, err.Error(), "pairs[0]")
+
+	filter := validConfig()
+	filter.EventFilterConfig = EventFilterConfig{MinPriceChange: 1.5, MaxQuietPeriod: "soon"}
+	err = filter.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "event_filter_config.min_price_change")
+	assert.Contains(t, err.Error(), "event_filter_config.max_quiet_period")
+
+	streaming := validConfig()
+	streaming.StreamConfig = StreamConfig{Driver: StreamNATS, URL: "localhost:4222", Events: []string{"trade", "orders"}, BufferSize: -1}
+	err = streaming.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "stream_config.url")
+	assert.Contains(t, err.Error(), `stream_config.events[1]: unknown event type "orders"`)
+	assert.NotContains(t, err.Error(), "stream_config.events[0]")
+	assert.Contains(t, err.Error(), "stream_config.buffer_size")
+	streaming.StreamConfig = StreamConfig{Driver: StreamKafka, URL: "http://localhost:8082"}
+	assert.NoError(t, streaming.Validate())
+	streaming.StreamConfig.Driver = "redis"
+	assert.ErrorContains(t, streaming.Validate(), "stream_config.driver")
+
+	vol := validConfig()
+	vol.VolatilityConfig = VolatilityConfig{Interval: "1h", Window: 1, VaRConfidence: 0.4, RiskPerTrade: 50}
+	err = vol.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "volatility_config.window")
+	assert.Contains(t, err.Error(), "volatility_config.var_confidence")
+	assert.Contains(t, err.Error(), "volatility_config.risk_per_trade: requires stop_atr")
+	assert.NotContains(t, err.Error(), "volatility_config.interval")
+
+	providers := validConfig()
+	providers.AIConfig.ModelType = "gpt-4o"
+	providers.AIConfig.Challenger.ModelType = "deepseek-reasoner"
+	providers.CostConfig.Fees = map[string]FeeRates{"okx": {Maker: 0.001}}
+	err = providers.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `ai_config.model_type: "gpt-4o" is not a model of a supported provider`)
+	assert.Contains(t, err.Error(), "cost_config.fees.okx: unknown exchange")
+	assert.NotContains(t, err.Error(), "ai_config.challenger")
+
+	issues := FieldErrors(err)
+	require.Len(t, issues, 2)
+	assert.ElementsMatch(t, []string{"ai_config.model_type", "cost_config.fees.okx"}, []string{issues[0].Field, issues[1].Field})
+
+	invalid := &Config{Mode: "demo", RefreshInterval: "soon"}
+	err = invalid.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "mode")
+	assert.Contains(t, err.Error(), "symbols")
+	assert.Contains(t, err.Error(), "refresh_interval")
+	assert.Contains(t, err.Error(), "database.conn_str")
+	assert.Contains(t, err.Error(), "risk_parameters")
+}
+
+func TestConfig_AccountConfigs(t *testing.T) {
+	config := validConfig()
+	config.TradingConfig.Strategy = "momentum"
+	config.ExchangeConfig.APIKey = "key"
+
+	accounts := config.AccountConfigs()
+	require.Len(t, accounts, 1)
+	assert.Equal(t, DefaultAccount, accounts[0].Name)
+	assert.Equal(t, "momentum", accounts[0].Strategy)
+	assert.Equal(t, "key", accounts[0].ExchangeConfig.APIKey)
+	assert.Nil(t, accounts[0].RiskParams)
+
+	config.Accounts = []AccountConfig{{Name: "a"}, {Name: "b"}}
+	assert.Len(t, config.AccountConfigs(), 2)
+}
+
+func TestConfig_ForSymbol(t *testing.T) {
+	minConfidence, maxAmount := 0.9, 0.5
+	config := validConfig()
+	config.TradingConfig = TradingConfig{MinOrderAmount: 0.01, MaxOrderAmount: 0.1}
+	config.SymbolOverrides = map[string]SymbolConfig{
+		"ETHUSDT": {
+			MinConfidence:   &minConfidence,
+			MaxOrderAmount:  &maxAmount,
+			RiskParams:      &risk.RiskParameters{MaxPositionSize: 200},
+			RefreshInterval: "5s",
+			Strategy:        "momentum",
+		},
+	}
+
+	assert.Equal(t, SymbolSettings{
+		MinConfidence:   0.7,
+		MinOrderAmount:  0.01,
+		MaxOrderAmount:  0.1,
+		RefreshInterval: "1m",
+	}, config.ForSymbol("BTCUSDT"))
+
+	assert.Equal(t, SymbolSettings{
+		MinConfidence:   0.9,
+		MinOrderAmount:  0.01,
+		MaxOrderAmount:  0.5,
+		RiskParams:      &risk.RiskParameters{MaxPositionSize: 200},
+		RefreshInterval: "5s",
+		Strategy:        "momentum",
+	}, config.ForSymbol("ETHUSDT"))
+
+	// 标签覆盖项优先级低于交易对覆盖项，标签不区分大小写
+	tagConfidence, tagAmount := 0.8, 0.05
+	config.TagOverrides = map[string]SymbolConfig{
+		"meme": {MinConfidence: &tagConfidence, MaxOrderAmount: &tagAmount},
+	}
+	assert.Equal(t, SymbolSettings{
+		MinConfidence:   0.8,
+		MinOrderAmount:  0.01,
+		MaxOrderAmount:  0.05,
+		RefreshInterval: "1m",
+	}, config.ForSymbol("BTCUSDT", "L1", "MEME"))
+	assert.Equal(t, 0.9, config.ForSymbol("ETHUSDT", "meme").MinConfidence)
+	assert.Equal(t, 0.5, config.ForSymbol("ETHUSDT", "meme").MaxOrderAmount)
+}
+
+func TestConfig_StageBudget(t *testing.T) {
+	config := &Config{LatencyBudget: LatencyBudget{AI: "5s", Risk: "200ms"}}
+	assert.Equal(t, 5*time.Second, config.StageBudget(StageAI))
+	assert.Equal(t, 200*time.Millisecond, config.StageBudget(StageRisk))
+	assert.Zero(t, config.StageBudget(StageOrder))
+	assert.Zero(t, config.StageBudget("other"))
+}
+
+func TestConfig_ErrorPolicyFor(t *testing.T) {
+	config := &Config{ErrorPolicy: map[string]string{ErrorClassProvider: ErrorPolicyPause}}
+	assert.Equal(t, ErrorPolicyPause, config.ErrorPolicyFor(ErrorClassProvider))
+	assert.Equal(t, ErrorPolicyHalt, config.ErrorPolicyFor(ErrorClassAuth))
+	assert.Equal(t, ErrorPolicySkip, config.ErrorPolicyFor("other"))
+
+	assert.Equal(t, DefaultErrorPauseDuration, config.ErrorPause())
+	config.ErrorPauseDuration = "0"
+	assert.Zero(t, config.ErrorPause())
+	config.ErrorPauseDuration = "30m"
+	assert.Equal(t, 30*time.Minute, config.ErrorPause())
+}
+
+func TestTunables(t *testing.T) {
+	config := validConfig()
+	next := config.ApplyTunables(map[string]float64{"ai_config.min_confidence": 0.9, "risk_parameters.max_leverage": 2, "unknown": 1})
+	assert.Equal(t, 0.9, next.AIConfig.MinConfidence)
+	assert.Equal(t, 2.0, next.RiskParams.MaxLeverage)
+	assert.NotEqual(t, 2.0, config.RiskParams.MaxLeverage)
+
+	tunable, ok := LookupTunable("trading_config.price_tolerance")
+	require.True(t, ok)
+	tunable.Set(next, 1.5)
+	err := ValidateTunable(next, tunable.Name)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "trading_config.price_tolerance")
+	assert.NoError(t, ValidateTunable(next, "ai_config.min_confidence"))
+
+	// 风险限额按整组校验
+	next.RiskParams.MinLiquidity = 0
+	assert.Error(t, ValidateTunable(next, "risk_parameters.min_liquidity"))
+	_, ok = LookupTunable("risk_parameters")
+	assert.False(t, ok)
+}
+
+const testYAML = `
+mode: paper
+symbols: [BTCUSDT]
+refresh_interval: 30s
+database:
+  conn_str: ${TEST_QF_DB:-postgresql://localhost/quantaflux}
+ai_config:
+  api_key: ${TEST_QF_AI_KEY}
+  min_confidence: 0.7
+risk_params:
+  max_position_size: 1000
+  max_loss_per_trade: 100
+  max_daily_loss: 500
+  max_leverage: 1
+  min_liquidity: 1000
+`
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("yaml with env", func(t *testing.T) {
+		path := filepath.Join(dir, "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(testYAML), 0o644))
+
+		_, err := Load(path)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "TEST_QF_AI_KEY")
+
+		t.Setenv("TEST_QF_AI_KEY", "secret")
+		config, err := Load(path)
+		require.NoError(t, err)
+		assert.Equal(t, "secret", config.AIConfig.APIKey)
+		assert.Equal(t, "postgresql://localhost/quantaflux", config.Database.ConnStr)
+		assert.Equal(t, 1000.0, config.RiskParams.MaxPositionSize)
+	})
+
+	t.Run("env values with special characters", func(t *testing.T) {
+		// 环境变量的值在解析后展开，引号、冒号和 # 不会破坏文件结构；注释中的变量不展开
+		t.Setenv("TEST_QF_AI_KEY", `se"cr: et # key`)
+		t.Setenv("TEST_QF_MAX_TRACES", "20")
+
+		path := filepath.Join(dir, "special.yaml")
+		content := testYAML + "tracing_config:\n  max_traces: ${TEST_QF_MAX_TRACES} # ${TEST_QF_UNSET}\n"
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		config, err := Load(path)
+		require.NoError(t, err)
+		assert.Equal(t, `se"cr: et # key`, config.AIConfig.APIKey)
+		assert.Equal(t, 20, config.TracingConfig.MaxTraces)
+
+		path = filepath.Join(dir, "special.json")
+		content = `{"mode": "paper", "symbols": ["BTCUSDT"], "database": {"conn_str": "${TEST_QF_DB:-postgresql://localhost/quantaflux}"},
+			"ai_config": {"api_key": "${TEST_QF_AI_KEY}", "min_confidence": 0.7},
+			"risk_parameters": {"max_position_size": 1000, "max_loss_per_trade": 100, "max_daily_loss": 500, "max_leverage": 1, "min_liquidity": 1000}}`
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		config, err = Load(path)
+		require.NoError(t, err)
+		assert.Equal(t, `se"cr: et # key`, config.AIConfig.APIKey)
+		assert.Equal(t, "postgresql://localhost/quantaflux", config.Database.ConnStr)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		path := filepath.Join(dir, "config.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"symbols": ["BTCUSDT"], "mode": "paper"}`), 0o644))
+
+		_, err := Load(path)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "database.conn_str")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := Load(filepath.Join(dir, "missing.json"))
+		assert.Error(t, err)
+	})
+}
+
+func TestAPIConfig_AuthOptions(t *testing.T) {
+	config := APIConfig{
+		Tokens:    map[string]string{"cli": "cli-token", "ops": "<ops token>"},
+		JWTSecret: "<jwt signing secret>",
+		RateLimit: 600,
+		Keys: []APIKeyConfig{
+			{Name: "grafana", Key: "read-key", Role: auth.RoleRead, RateLimit: 60},
+			{Name: "unset", Key: "", Role: auth.RoleRead},
+		},
+	}
+
+	opts := config.AuthOptions()
+	assert.Equal(t, []auth.Key{
+		{Name: "cli", Secret: "cli-token", Role: auth.RoleControl},
+		{Name: "grafana", Secret: "read-key", Role: auth.RoleRead, RateLimit: 60},
+	}, opts.Keys)
+	assert.Empty(t, opts.JWTSecret)
+	assert.Equal(t, 600, opts.RateLimit)
+}
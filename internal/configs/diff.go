@@ -0,0 +1,164 @@
+package configs
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Diff 比较两份配置，返回可读的变更描述，如 "risk_parameters.max_position_size: 1000 -> 2000"。
+// 标记了 secret:"true" 的字段只输出是否变化，map 类型的敏感字段只输出增删的键
+func Diff(old, new *Config) []string {
+	var changes []string
+	diffValue("", reflect.ValueOf(*old), reflect.ValueOf(*new), false, &changes)
+	return changes
+}
+
+func diffValue(path string, old, new reflect.Value, secret bool, changes *[]string) {
+	switch old.Kind() {
+	case reflect.Struct:
+		for i := 0; i < old.NumField(); i++ {
+			field := old.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			diffValue(joinPath(path, fieldName(field)), old.Field(i), new.Field(i), secret || isSecret(field), changes)
+		}
+		return
+	case reflect.Pointer:
+		if !old.IsNil() && !new.IsNil() {
+			diffValue(path, old.Elem(), new.Elem(), secret, changes)
+			return
+		}
+		if old.IsNil() != new.IsNil() && composite(old.Type()) {
+			diffOneSided(path, old, new, secret, changes)
+			return
+		}
+	case reflect.Map:
+		// 键为字符串的 map 按键逐项比较，如 symbol_overrides.BTCUSDT.min_confidence
+		if old.Type().Key().Kind() == reflect.String {
+			keys := make(map[string]bool)
+			for _, key := range append(old.MapKeys(), new.MapKeys()...) {
+				keys[key.String()] = true
+			}
+			for _, key := range slices.Sorted(maps.Keys(keys)) {
+				k := reflect.ValueOf(key).Convert(old.Type().Key())
+				oldValue, newValue := old.MapIndex(k), new.MapIndex(k)
+				if oldValue.IsValid() && newValue.IsValid() {
+					diffValue(joinPath(path, key), oldValue, newValue, secret, changes)
+					continue
+				}
+				diffOneSided(joinPath(path, key), oldValue, newValue, secret, changes)
+			}
+			return
+		}
+	case reflect.Slice:
+		// 元素为结构体等复合类型的切片按下标逐项比较，如 accounts[0].exchange_config.api_key
+		if composite(old.Type().Elem()) {
+			for i := 0; i < max(old.Len(), new.Len()); i++ {
+				var oldValue, newValue reflect.Value
+				if i < old.Len() {
+					oldValue = old.Index(i)
+				}
+				if i < new.Len() {
+					newValue = new.Index(i)
+				}
+				elemPath := fmt.Sprintf("%s[%d]", path, i)
+				if oldValue.IsValid() && newValue.IsValid() {
+					diffValue(elemPath, oldValue, newValue, secret, changes)
+					continue
+				}
+				diffOneSided(elemPath, oldValue, newValue, secret, changes)
+			}
+			return
+		}
+	}
+
+	if reflect.DeepEqual(old.Interface(), new.Interface()) {
+		return
+	}
+	if secret {
+		*changes = append(*changes, fmt.Sprintf("%s: changed", path))
+		return
+	}
+	*changes = append(*changes, fmt.Sprintf("%s: %v -> %v", path, display(old), display(new)))
+}
+
+// diffOneSided 输出只在一侧存在的值（新增的账户、map 项或指针）：复合类型不整体输出，
+// 而是与零值逐项比较，敏感字段同样被屏蔽；敏感的值只输出增删
+func diffOneSided(path string, old, new reflect.Value, secret bool, changes *[]string) {
+	added := !old.IsValid() || (old.Kind() == reflect.Pointer && old.IsNil())
+	value := old
+	if added {
+		value = new
+	}
+	if !secret && !composite(value.Type()) {
+		*changes = append(*changes, fmt.Sprintf("%s: %v -> %v", path, display(old), display(new)))
+		return
+	}
+
+	if added {
+		*changes = append(*changes, fmt.Sprintf("%s: added", path))
+	} else {
+		*changes = append(*changes, fmt.Sprintf("%s: removed", path))
+	}
+	if secret {
+		return
+	}
+	if value.Kind() == reflect.Pointer {
+		value = value.Elem()
+	}
+	zero := reflect.Zero(value.Type())
+	if added {
+		diffValue(path, zero, value, false, changes)
+	} else {
+		diffValue(path, value, zero, false, changes)
+	}
+}
+
+// composite 是否为需逐项比较的结构体、map、切片或指向它们的指针
+func composite(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice:
+		return true
+	case reflect.Pointer:
+		return composite(t.Elem())
+	}
+	return false
+}
+
+// isSecret 字段是否为密钥、令牌、连接字符串等敏感值
+func isSecret(field reflect.StructField) bool {
+	return field.Tag.Get("secret") == "true"
+}
+
+// display 返回用于输出的值，指针输出指向的值
+func display(v reflect.Value) any {
+	if !v.IsValid() {
+		return nil
+	}
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		return v.Elem().Interface()
+	}
+	return v.Interface()
+}
+
+func fieldName(field reflect.StructField) string {
+	tag := strings.Split(field.Tag.Get("json"), ",")[0]
+	if tag == "" || tag == "-" {
+		return field.Name
+	}
+	return strings.TrimSpace(tag)
+}
+
+func joinPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
@@ -0,0 +1,89 @@
+package configs
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/songzhibin97/quantaflux/internal/risk"
+)
+
+// Tunable 可在运行时通过参数注册表调整的阈值，名称与配置文件中的路径一致
+type Tunable struct {
+	Name        string
+	Description string
+	Get         func(c *Config) float64
+	Set         func(c *Config, value float64)
+}
+
+// Tunables 返回所有可在运行时调整的阈值：AI 置信度和诈骗阈值、价格容差以及全局风险限额。
+// 按交易对和按账户单独配置的值不受影响
+func Tunables() []Tunable {
+	return []Tunable{
+		{
+			Name:        "ai_config.min_confidence",
+			Description: "Minimum AI prediction confidence required to trade",
+			Get:         func(c *Config) float64 { return c.AIConfig.MinConfidence },
+			Set:         func(c *Config, v float64) { c.AIConfig.MinConfidence = v },
+		},
+		{
+			Name:        "ai_config.scam_threshold",
+			Description: "Scam probability above which a symbol is not traded",
+			Get:         func(c *Config) float64 { return c.AIConfig.ScamThreshold },
+			Set:         func(c *Config, v float64) { c.AIConfig.ScamThreshold = v },
+		},
+		{
+			Name:        "trading_config.price_tolerance",
+			Description: "Relative distance between predicted and current price required to trade",
+			Get:         func(c *Config) float64 { return c.TradingConfig.PriceTolerance },
+			Set:         func(c *Config, v float64) { c.TradingConfig.PriceTolerance = v },
+		},
+		riskTunable("max_position_size", "Maximum position size", func(p *risk.RiskParameters) *float64 { return &p.MaxPositionSize }),
+		riskTunable("max_loss_per_trade", "Maximum loss per trade", func(p *risk.RiskParameters) *float64 { return &p.MaxLossPerTrade }),
+		riskTunable("max_daily_loss", "Maximum daily loss", func(p *risk.RiskParameters) *float64 { return &p.MaxDailyLoss }),
+		riskTunable("max_leverage", "Maximum leverage", func(p *risk.RiskParameters) *float64 { return &p.MaxLeverage }),
+		riskTunable("min_liquidity", "Minimum liquidity of a traded symbol", func(p *risk.RiskParameters) *float64 { return &p.MinLiquidity }),
+	}
+}
+
+// riskTunable 全局风险限额中的一项
+func riskTunable(name, description string, field func(p *risk.RiskParameters) *float64) Tunable {
+	return Tunable{
+		Name:        "risk_parameters." + name,
+		Description: description,
+		Get:         func(c *Config) float64 { return *field(&c.RiskParams) },
+		Set:         func(c *Config, v float64) { *field(&c.RiskParams) = v },
+	}
+}
+
+// LookupTunable 按名称查找可调整的阈值
+func LookupTunable(name string) (Tunable, bool) {
+	for _, tunable := range Tunables() {
+		if tunable.Name == name {
+			return tunable, true
+		}
+	}
+	return Tunable{}, false
+}
+
+// ValidateTunable 校验配置中名为 name 的阈值，只返回与该阈值相关的问题，
+// 其它配置项（如运行时不完整的交易所密钥）不影响调整阈值
+func ValidateTunable(c *Config, name string) error {
+	var errs []error
+	for _, fe := range FieldErrors(c.Validate()) {
+		if fe.Field == name || strings.HasPrefix(name, fe.Field+".") {
+			errs = append(errs, fe)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ApplyTunables 返回按 values 修改阈值后的配置副本，不认识的名称忽略
+func (c *Config) ApplyTunables(values map[string]float64) *Config {
+	next := *c
+	for _, tunable := range Tunables() {
+		if value, ok := values[tunable.Name]; ok {
+			tunable.Set(&next, value)
+		}
+	}
+	return &next
+}
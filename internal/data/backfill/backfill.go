@@ -0,0 +1,157 @@
+package backfill
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/models"
+)
+
+// DefaultPageSize 单次请求的最大 K 线条数（Binance 上限）
+const DefaultPageSize = 1000
+
+// Warmup 计算 24 小时成交量和涨跌幅需要的预热时长
+const Warmup = 24 * time.Hour
+
+// Backfiller 从交易所拉取历史 K 线并批量写入存储
+// 每根 K 线按收盘时间转换为一条行情，成交量和涨跌幅按 24 小时滚动窗口计算，
+// 起点前会额外拉取 24 小时 K 线预热，写入的第一条行情也有完整的统计值；
+// 每页 K 线写入一批，数据源记为 SourceName(interval)，中断后再次运行从该交易对同一周期回填的
+// 最新行情时间继续，实时采集的行情不影响断点
+type Backfiller struct {
+	source   Source
+	storage  Storage
+	pageSize int
+}
+
+func NewBackfiller(source Source, storage Storage, pageSize int) *Backfiller {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	return &Backfiller{
+		source:   source,
+		storage:  storage,
+		pageSize: pageSize,
+	}
+}
+
+// SourceName 返回按 interval 回填的行情的数据源名称，不同周期的回填和实时行情互不覆盖
+func SourceName(interval time.Duration) string {
+	return fmt.Sprintf("backfill_%s", interval)
+}
+
+// Run 回填 symbol 在 [start, end] 内的行情，返回本次写入的行数；progress 在每批写入后调用，可为 nil
+func (b *Backfiller) Run(ctx context.Context, symbol string, interval time.Duration, start, end time.Time, progress func(Progress)) (int, error) {
+	if interval <= 0 {
+		return 0, fmt.Errorf("invalid interval: %s", interval)
+	}
+	if !end.After(start) {
+		return 0, fmt.Errorf("end %s must be after start %s", end, start)
+	}
+
+	source := SourceName(interval)
+	latest, err := b.storage.LatestMarketDataTime(ctx, symbol, source, start, end)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load backfill checkpoint: %w", err)
+	}
+	from := start
+	if !latest.IsZero() {
+		from = latest.Add(time.Millisecond)
+	}
+
+	window := NewRollingWindow(Warmup)
+	cursor := from.Add(-Warmup)
+	rows := 0
+	for cursor.Before(end) {
+		klines, err := b.source.Klines(ctx, symbol, interval, cursor, end, b.pageSize)
+		if err != nil {
+			return rows, fmt.Errorf("failed to fetch klines of %s: %w", symbol, err)
+		}
+		if len(klines) == 0 {
+			break
+		}
+
+		batch := make([]models.MarketData, 0, len(klines))
+		for _, k := range klines {
+			data := window.Add(symbol, k)
+			data.Source = source
+			if k.OpenTime.Before(from) || k.CloseTime.After(end) {
+				continue
+			}
+			batch = append(batch, data)
+		}
+
+		if len(batch) > 0 {
+			if err := b.storage.SaveMarketDataBatch(ctx, batch); err != nil {
+				return rows, fmt.Errorf("failed to save klines of %s: %w", symbol, err)
+			}
+			rows += len(batch)
+
+			if progress != nil {
+				current := batch[len(batch)-1].Timestamp
+				progress(Progress{
+					Symbol:  symbol,
+					Current: current,
+					Rows:    rows,
+					Percent: 100 * float64(current.Sub(start)) / float64(end.Sub(start)),
+				})
+			}
+		}
+
+		next := klines[len(klines)-1].OpenTime.Add(interval)
+		if !next.After(cursor) {
+			break
+		}
+		cursor = next
+	}
+
+	return rows, nil
+}
+
+// RollingWindow 维护最近一段时间的 K 线，用于计算滚动成交量和涨跌幅
+type RollingWindow struct {
+	span   time.Duration
+	klines []Kline
+	volume float64
+}
+
+func NewRollingWindow(span time.Duration) *RollingWindow {
+	return &RollingWindow{span: span}
+}
+
+// Add 加入一根 K 线并返回对应的行情
+func (w *RollingWindow) Add(symbol string, k Kline) models.MarketData {
+	w.klines = append(w.klines, k)
+	w.volume += k.Volume
+
+	// 去掉收盘时间早于窗口的 K 线
+	n := 0
+	for n < len(w.klines) && !w.klines[n].CloseTime.After(k.CloseTime.Add(-w.span)) {
+		w.volume -= w.klines[n].Volume
+		n++
+	}
+	w.klines = w.klines[n:]
+
+	return models.MarketData{
+		Symbol:         symbol,
+		Price:          k.Close,
+		Volume24h:      w.volume,
+		PriceChange1h:  w.change(k, time.Hour),
+		PriceChange24h: w.change(k, w.span),
+		Timestamp:      k.CloseTime,
+	}
+}
+
+// change 返回 k 相对 d 时长前的涨跌幅（百分比），以窗口内该时间点之后第一根 K 线的开盘价为基准
+func (w *RollingWindow) change(k Kline, d time.Duration) float64 {
+	since := k.CloseTime.Add(-d)
+	i := sort.Search(len(w.klines), func(i int) bool {
+		return w.klines[i].CloseTime.After(since)
+	})
+	if i == len(w.klines) || w.klines[i].Open == 0 {
+		return 0
+	}
+	return (k.Close - w.klines[i].Open) / w.klines[i].Open * 100
+}
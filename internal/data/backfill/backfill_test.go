@@ -0,0 +1,181 @@
+package backfill
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSource 按固定周期生成 K 线，价格每根加 1
+type fakeSource struct {
+	base  time.Time
+	calls int
+	fail  int // 第 fail 次调用返回错误，0 表示不失败
+}
+
+func (f *fakeSource) Klines(_ context.Context, _ string, interval time.Duration, start, end time.Time, limit int) ([]Kline, error) {
+	f.calls++
+	if f.fail > 0 && f.calls == f.fail {
+		return nil, errors.New("rate limited")
+	}
+
+	var klines []Kline
+	open := start.Truncate(interval)
+	if open.Before(start) {
+		open = open.Add(interval)
+	}
+	for ; !open.After(end) && len(klines) < limit; open = open.Add(interval) {
+		i := float64(open.Sub(f.base) / interval)
+		klines = append(klines, Kline{
+			OpenTime:  open,
+			CloseTime: open.Add(interval - time.Millisecond),
+			Open:      100 + i,
+			Close:     101 + i,
+			Volume:    1,
+		})
+	}
+	return klines, nil
+}
+
+type fakeStorage struct {
+	rows []models.MarketData
+}
+
+func (f *fakeStorage) SaveMarketDataBatch(_ context.Context, data []models.MarketData) error {
+	f.rows = append(f.rows, data...)
+	return nil
+}
+
+func (f *fakeStorage) LatestMarketDataTime(_ context.Context, symbol, source string, start, end time.Time) (time.Time, error) {
+	var latest time.Time
+	for _, d := range f.rows {
+		if d.Symbol == symbol && d.Source == source && !d.Timestamp.Before(start) && !d.Timestamp.After(end) && d.Timestamp.After(latest) {
+			latest = d.Timestamp
+		}
+	}
+	return latest, nil
+}
+
+func TestBackfiller_Run(t *testing.T) {
+	start := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	end := start.Add(6 * time.Hour)
+	source := &fakeSource{base: start.Add(-Warmup)}
+	store := &fakeStorage{}
+
+	var progress []Progress
+	rows, err := NewBackfiller(source, store, 100).Run(context.Background(), "BTCUSDT", time.Hour, start, end, func(p Progress) {
+		progress = append(progress, p)
+	})
+	require.NoError(t, err)
+
+	// 最后一根 K 线收盘时间超出范围不写入
+	assert.Equal(t, 6, rows)
+	require.Len(t, store.rows, 6)
+	first := store.rows[0]
+	assert.Equal(t, start.Add(time.Hour-time.Millisecond), first.Timestamp)
+	assert.Equal(t, 125.0, first.Price)
+	// 预热后 24 小时成交量完整
+	assert.Equal(t, 24.0, first.Volume24h)
+	assert.InDelta(t, (125.0-124.0)/124.0*100, first.PriceChange1h, 1e-9)
+	assert.InDelta(t, (125.0-101.0)/101.0*100, first.PriceChange24h, 1e-9)
+
+	require.NotEmpty(t, progress)
+	last := progress[len(progress)-1]
+	assert.Equal(t, 6, last.Rows)
+	assert.InDelta(t, 100*float64(last.Current.Sub(start))/float64(end.Sub(start)), last.Percent, 1e-9)
+}
+
+func TestBackfiller_Resume(t *testing.T) {
+	start := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	end := start.Add(12 * time.Hour)
+	store := &fakeStorage{}
+
+	// 第三次请求失败，已写入的批次保留
+	source := &fakeSource{base: start.Add(-Warmup), fail: 3}
+	b := NewBackfiller(source, store, 10)
+	rows, err := b.Run(context.Background(), "BTCUSDT", time.Hour, start, end, nil)
+	require.Error(t, err)
+	assert.Equal(t, len(store.rows), rows)
+	assert.Less(t, rows, 12)
+
+	// 再次运行从断点继续，不重复写入
+	source.fail = 0
+	resumed, err := b.Run(context.Background(), "BTCUSDT", time.Hour, start, end, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 12, rows+resumed)
+	require.Len(t, store.rows, 12)
+	for i, d := range store.rows {
+		assert.Equal(t, start.Add(time.Duration(i+1)*time.Hour-time.Millisecond), d.Timestamp)
+		assert.Equal(t, 24.0, d.Volume24h)
+	}
+
+	// 已完成的范围不再写入
+	again, err := b.Run(context.Background(), "BTCUSDT", time.Hour, start, end, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0, again)
+}
+
+func TestBackfiller_IgnoresLiveData(t *testing.T) {
+	start := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	end := start.Add(6 * time.Hour)
+
+	// 范围内已有实时采集的行情和其他周期的回填，不作为断点
+	store := &fakeStorage{rows: []models.MarketData{
+		{Symbol: "BTCUSDT", Source: "binance", Price: 1, Timestamp: end.Add(-time.Minute)},
+		{Symbol: "BTCUSDT", Source: SourceName(time.Minute), Price: 1, Timestamp: end.Add(-time.Minute)},
+	}}
+	rows, err := NewBackfiller(&fakeSource{base: start.Add(-Warmup)}, store, 100).Run(context.Background(), "BTCUSDT", time.Hour, start, end, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 6, rows)
+	for _, d := range store.rows[2:] {
+		assert.Equal(t, "backfill_1h0m0s", d.Source)
+	}
+}
+
+func TestBackfiller_InvalidRange(t *testing.T) {
+	start := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	b := NewBackfiller(&fakeSource{}, &fakeStorage{}, 0)
+
+	tests := []struct {
+		name     string
+		interval time.Duration
+		end      time.Time
+	}{
+		{name: "zero interval", interval: 0, end: start.Add(time.Hour)},
+		{name: "end before start", interval: time.Minute, end: start.Add(-time.Hour)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := b.Run(context.Background(), "BTCUSDT", tt.interval, start, tt.end, nil)
+			assert.Error(t, err)
+		})
+	}
+}
+
+// BenchmarkBackfiller_Run 衡量一个月 1 分钟 K 线的转换开销（不含网络和数据库）
+func BenchmarkBackfiller_Run(b *testing.B) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(30 * 24 * time.Hour)
+
+	for i := 0; i < b.N; i++ {
+		store := &discardStorage{}
+		backfiller := NewBackfiller(&fakeSource{base: start.Add(-Warmup)}, store, DefaultPageSize)
+		if _, err := backfiller.Run(context.Background(), "BTCUSDT", time.Minute, start, end, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+type discardStorage struct{}
+
+func (discardStorage) SaveMarketDataBatch(context.Context, []models.MarketData) error { return nil }
+
+func (discardStorage) LatestMarketDataTime(context.Context, string, string, time.Time, time.Time) (time.Time, error) {
+	return time.Time{}, nil
+}
@@ -0,0 +1,42 @@
+package backfill
+
+import (
+	"context"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/models"
+)
+
+// Kline 交易所返回的一根历史 K 线
+type Kline struct {
+	OpenTime  time.Time `json:"open_time"`
+	CloseTime time.Time `json:"close_time"`
+	Open      float64   `json:"open"`
+	High      float64   `json:"high"`
+	Low       float64   `json:"low"`
+	Close     float64   `json:"close"`
+	Volume    float64   `json:"volume"` // 成交量（基础资产）
+}
+
+// Source 历史 K 线数据源
+type Source interface {
+	// Klines returns at most limit klines of a symbol opened in [start, end], oldest first
+	Klines(ctx context.Context, symbol string, interval time.Duration, start, end time.Time, limit int) ([]Kline, error)
+}
+
+// Storage 回填数据的批量写入
+type Storage interface {
+	// SaveMarketDataBatch stores market data in bulk
+	SaveMarketDataBatch(ctx context.Context, data []models.MarketData) error
+
+	// LatestMarketDataTime returns the newest stored timestamp of a symbol from source in [start, end], zero if none
+	LatestMarketDataTime(ctx context.Context, symbol, source string, start, end time.Time) (time.Time, error)
+}
+
+// Progress 回填进度
+type Progress struct {
+	Symbol  string    `json:"symbol"`
+	Current time.Time `json:"current"` // 已写入的最新行情时间
+	Rows    int       `json:"rows"`    // 本次已写入的行数
+	Percent float64   `json:"percent"` // 时间范围完成百分比
+}
@@ -0,0 +1,134 @@
+package backfill
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/models"
+)
+
+// 每次从存储读取的待分析代币数量
+const metricsPageSize = 100
+
+// MetricsStorage 项目指标回填的读写
+type MetricsStorage interface {
+	// ListTokensWithoutMetrics returns at most limit tokens with symbol after the given one, ordered by symbol,
+	// whose latest project metrics were updated before since or which have no project metrics at all
+	ListTokensWithoutMetrics(ctx context.Context, since time.Time, after string, limit int) ([]models.TokenInfo, error)
+
+	// SaveProjectMetrics stores project metrics for a previously saved token
+	SaveProjectMetrics(ctx context.Context, metrics *models.ProjectMetrics) error
+}
+
+// ProjectAnalyzer 项目分析
+type ProjectAnalyzer interface {
+	AnalyzeProject(ctx context.Context, info *models.TokenInfo) (*models.ProjectMetrics, error)
+}
+
+// MetricsProgress 项目指标回填进度
+type MetricsProgress struct {
+	Symbol   string `json:"symbol"`
+	Analyzed int    `json:"analyzed"` // 本次已保存的代币数量
+	Failed   int    `json:"failed"`   // 本次分析或保存失败的代币数量
+	Err      error  `json:"-"`        // 当前代币失败的原因
+}
+
+// MetricsResult 项目指标回填结果
+type MetricsResult struct {
+	Analyzed int      `json:"analyzed"`
+	Failed   []string `json:"failed"` // 失败的交易对，再次运行时重试
+}
+
+// MetricsBackfiller 为缺少近期项目指标的代币执行项目分析并保存结果。
+// 每个代币分析后立即保存，已有近期指标的代币不会重复分析，中断后再次运行即可续传；
+// 两次分析之间至少间隔 interval，避免超出 AI 服务的请求频率限制
+type MetricsBackfiller struct {
+	storage  MetricsStorage
+	analyzer ProjectAnalyzer
+	interval time.Duration
+}
+
+func NewMetricsBackfiller(storage MetricsStorage, analyzer ProjectAnalyzer, interval time.Duration) *MetricsBackfiller {
+	return &MetricsBackfiller{
+		storage:  storage,
+		analyzer: analyzer,
+		interval: interval,
+	}
+}
+
+// Run 分析项目指标早于 maxAge 或没有项目指标的代币；单个代币失败时记录后继续，progress 在每个代币处理后调用，可为 nil
+func (b *MetricsBackfiller) Run(ctx context.Context, maxAge time.Duration, progress func(MetricsProgress)) (MetricsResult, error) {
+	result := MetricsResult{}
+	since := time.Now().Add(-maxAge)
+
+	var last time.Time
+	after := ""
+	for {
+		tokens, err := b.storage.ListTokensWithoutMetrics(ctx, since, after, metricsPageSize)
+		if err != nil {
+			return result, fmt.Errorf("failed to list tokens without metrics: %w", err)
+		}
+		if len(tokens) == 0 {
+			return result, nil
+		}
+
+		for i := range tokens {
+			token := &tokens[i]
+			after = token.Symbol
+
+			if err := b.wait(ctx, last); err != nil {
+				return result, err
+			}
+			last = time.Now()
+
+			err := b.analyze(ctx, token)
+			if ctx.Err() != nil {
+				return result, ctx.Err()
+			}
+			if err != nil {
+				result.Failed = append(result.Failed, token.Symbol)
+			} else {
+				result.Analyzed++
+			}
+			if progress != nil {
+				progress(MetricsProgress{Symbol: token.Symbol, Analyzed: result.Analyzed, Failed: len(result.Failed), Err: err})
+			}
+		}
+	}
+}
+
+// wait 距上一次分析不足 interval 时等待
+func (b *MetricsBackfiller) wait(ctx context.Context, last time.Time) error {
+	if last.IsZero() || b.interval <= 0 {
+		return nil
+	}
+	delay := b.interval - time.Since(last)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// analyze 分析单个代币并保存项目指标
+func (b *MetricsBackfiller) analyze(ctx context.Context, token *models.TokenInfo) error {
+	metrics, err := b.analyzer.AnalyzeProject(ctx, token)
+	if err != nil {
+		return fmt.Errorf("failed to analyze %s: %w", token.Symbol, err)
+	}
+
+	metrics.TokenInfo = *token
+	metrics.UpdatedAt = time.Now()
+	if err := b.storage.SaveProjectMetrics(ctx, metrics); err != nil {
+		return fmt.Errorf("failed to save project metrics of %s: %w", token.Symbol, err)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package backfill
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMetricsStorage 内存中的代币和各代币最新项目指标的更新时间
+type fakeMetricsStorage struct {
+	tokens  []string
+	updated map[string]time.Time
+}
+
+func (f *fakeMetricsStorage) ListTokensWithoutMetrics(_ context.Context, since time.Time, after string, limit int) ([]models.TokenInfo, error) {
+	sort.Strings(f.tokens)
+	var result []models.TokenInfo
+	for _, symbol := range f.tokens {
+		if symbol <= after || !f.updated[symbol].Before(since) {
+			continue
+		}
+		if len(result) == limit {
+			break
+		}
+		result = append(result, models.TokenInfo{Symbol: symbol})
+	}
+	return result, nil
+}
+
+func (f *fakeMetricsStorage) SaveProjectMetrics(_ context.Context, metrics *models.ProjectMetrics) error {
+	f.updated[metrics.TokenInfo.Symbol] = metrics.UpdatedAt
+	return nil
+}
+
+// fakeAnalyzer 对 fail 中的代币返回错误，分析第 cancelAt 个代币时取消 ctx
+type fakeAnalyzer struct {
+	fail     map[string]bool
+	calls    []string
+	cancelAt int
+	cancel   context.CancelFunc
+}
+
+func (f *fakeAnalyzer) AnalyzeProject(_ context.Context, info *models.TokenInfo) (*models.ProjectMetrics, error) {
+	f.calls = append(f.calls, info.Symbol)
+	if f.cancelAt > 0 && len(f.calls) == f.cancelAt {
+		f.cancel()
+		return nil, context.Canceled
+	}
+	if f.fail[info.Symbol] {
+		return nil, errors.New("provider unavailable")
+	}
+	return &models.ProjectMetrics{SocialScore: 50}, nil
+}
+
+func TestMetricsBackfiller_Run(t *testing.T) {
+	storage := &fakeMetricsStorage{
+		tokens: []string{"DOGE", "BTC", "ETH", "SOL"},
+		// ETH 有近期指标，不需要分析
+		updated: map[string]time.Time{"ETH": time.Now().Add(-time.Hour), "SOL": time.Now().Add(-30 * 24 * time.Hour)},
+	}
+
+	// 分析第二个代币时中断
+	ctx, cancel := context.WithCancel(context.Background())
+	analyzer := &fakeAnalyzer{fail: map[string]bool{"SOL": true}, cancelAt: 2, cancel: cancel}
+	backfiller := NewMetricsBackfiller(storage, analyzer, 0)
+	result, err := backfiller.Run(ctx, 7*24*time.Hour, nil)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, result.Analyzed)
+	assert.Equal(t, []string{"BTC", "DOGE"}, analyzer.calls)
+
+	// 再次运行从未完成的代币继续，单个代币失败不影响其他代币
+	analyzer = &fakeAnalyzer{fail: map[string]bool{"SOL": true}}
+	var progress []MetricsProgress
+	result, err = NewMetricsBackfiller(storage, analyzer, time.Millisecond).Run(context.Background(), 7*24*time.Hour, func(p MetricsProgress) {
+		progress = append(progress, p)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"DOGE", "SOL"}, analyzer.calls)
+	assert.Equal(t, MetricsResult{Analyzed: 1, Failed: []string{"SOL"}}, result)
+	require.Len(t, progress, 2)
+	assert.Error(t, progress[1].Err)
+
+	// 第三次运行只重试之前失败的代币
+	analyzer = &fakeAnalyzer{}
+	result, err = NewMetricsBackfiller(storage, analyzer, 0).Run(context.Background(), 7*24*time.Hour, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"SOL"}, analyzer.calls)
+	assert.Equal(t, 1, result.Analyzed)
+}
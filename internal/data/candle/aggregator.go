@@ -0,0 +1,88 @@
+package candle
+
+import (
+	"sync"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/models"
+)
+
+// Aggregator 将行情按固定周期聚合为 K 线
+// 周期按行情自身的时间戳划分而不是本地时钟，回测回放时结果可复现；
+// 一根 K 线在收到下一周期的第一条行情时收盘
+type Aggregator struct {
+	interval time.Duration
+
+	mu      sync.Mutex
+	current map[string]*models.Candle
+}
+
+func NewAggregator(interval time.Duration) *Aggregator {
+	return &Aggregator{
+		interval: interval,
+		current:  make(map[string]*models.Candle),
+	}
+}
+
+// Interval 返回聚合周期
+func (a *Aggregator) Interval() time.Duration {
+	return a.interval
+}
+
+// Add 加入一条行情，行情进入新周期时返回上一根已收盘的 K 线
+// 早于当前周期的乱序行情被忽略
+func (a *Aggregator) Add(data models.MarketData) (*models.Candle, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	openTime := data.Timestamp.Truncate(a.interval)
+	current, ok := a.current[data.Symbol]
+	if ok && openTime.Before(current.OpenTime) {
+		return nil, false
+	}
+
+	if ok && openTime.Equal(current.OpenTime) {
+		current.High = max(current.High, data.Price)
+		current.Low = min(current.Low, data.Price)
+		current.Close = data.Price
+		current.Ticks++
+		current.Last = data
+		return nil, false
+	}
+
+	a.current[data.Symbol] = &models.Candle{
+		Symbol:    data.Symbol,
+		Interval:  a.interval,
+		Open:      data.Price,
+		High:      data.Price,
+		Low:       data.Price,
+		Close:     data.Price,
+		Ticks:     1,
+		OpenTime:  openTime,
+		CloseTime: openTime.Add(a.interval),
+		Last:      data,
+	}
+
+	if !ok {
+		return nil, false
+	}
+	return current, true
+}
+
+// Resample 按周期对时间有序的行情降采样，每个周期保留最后一条（收盘价）
+func Resample(data []models.MarketData, interval time.Duration) []models.MarketData {
+	if interval <= 0 || len(data) == 0 {
+		return data
+	}
+
+	result := make([]models.MarketData, 0, len(data))
+	for _, d := range data {
+		n := len(result)
+		if n > 0 && result[n-1].Timestamp.Truncate(interval).Equal(d.Timestamp.Truncate(interval)) {
+			result[n-1] = d
+			continue
+		}
+		result = append(result, d)
+	}
+	return result
+}
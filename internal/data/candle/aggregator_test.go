@@ -0,0 +1,90 @@
+package candle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregator_Add(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tick := func(symbol string, offset time.Duration, price float64) models.MarketData {
+		return models.MarketData{Symbol: symbol, Price: price, Timestamp: base.Add(offset)}
+	}
+
+	agg := NewAggregator(5 * time.Minute)
+
+	steps := []struct {
+		data   models.MarketData
+		closed bool
+	}{
+		{data: tick("BTCUSDT", 0, 100)},
+		{data: tick("BTCUSDT", time.Minute, 105)},
+		{data: tick("ETHUSDT", 2*time.Minute, 10)},
+		{data: tick("BTCUSDT", 3*time.Minute, 95)},
+		{data: tick("BTCUSDT", 4*time.Minute, 102)},
+		{data: tick("BTCUSDT", 5*time.Minute, 103), closed: true},
+		// 乱序行情被忽略
+		{data: tick("BTCUSDT", 4*time.Minute+30*time.Second, 1)},
+	}
+
+	var closed *models.Candle
+	for i, step := range steps {
+		c, ok := agg.Add(step.data)
+		require.Equal(t, step.closed, ok, "step %d", i)
+		if ok {
+			closed = c
+		}
+	}
+
+	require.NotNil(t, closed)
+	assert.Equal(t, "BTCUSDT", closed.Symbol)
+	assert.Equal(t, 100.0, closed.Open)
+	assert.Equal(t, 105.0, closed.High)
+	assert.Equal(t, 95.0, closed.Low)
+	assert.Equal(t, 102.0, closed.Close)
+	assert.Equal(t, 4, closed.Ticks)
+	assert.Equal(t, base, closed.OpenTime)
+	assert.Equal(t, base.Add(5*time.Minute), closed.CloseTime)
+	assert.Equal(t, 102.0, closed.Last.Price)
+}
+
+func TestResample(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tick := func(offset time.Duration, price float64) models.MarketData {
+		return models.MarketData{Symbol: "BTCUSDT", Price: price, Timestamp: base.Add(offset)}
+	}
+	data := []models.MarketData{
+		tick(0, 100),
+		tick(time.Minute, 101),
+		tick(4*time.Minute, 102),
+		tick(5*time.Minute, 103),
+		tick(12*time.Minute, 104),
+	}
+
+	tests := []struct {
+		name     string
+		interval time.Duration
+		want     []float64
+	}{
+		{name: "keeps close of each period", interval: 5 * time.Minute, want: []float64{102, 103, 104}},
+		{name: "larger period", interval: time.Hour, want: []float64{104}},
+		{name: "zero interval keeps all", interval: 0, want: []float64{100, 101, 102, 103, 104}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var prices []float64
+			for _, d := range Resample(data, tt.interval) {
+				prices = append(prices, d.Price)
+			}
+			assert.Equal(t, tt.want, prices)
+		})
+	}
+
+	assert.Empty(t, Resample(nil, time.Minute))
+}
@@ -11,12 +11,15 @@ import (
 	"github.com/go-resty/resty/v2"
 	"github.com/songzhibin97/quantaflux/internal/utils/request"
 
+	"github.com/songzhibin97/quantaflux/internal/data"
+	"github.com/songzhibin97/quantaflux/internal/exchangeinfo"
 	"github.com/songzhibin97/quantaflux/internal/models"
 )
 
 type BinanceDataSource struct {
-	baseURL    string
-	httpClient *resty.Client
+	baseURL      string
+	httpClient   *resty.Client
+	exchangeInfo *exchangeinfo.Service
 }
 
 func NewBinanceDataSource() *BinanceDataSource {
@@ -30,18 +33,32 @@ func (b *BinanceDataSource) Name() string {
 	return "binance"
 }
 
+// SetExchangeInfo 设置共享的交易所元数据缓存，代币信息优先从缓存读取
+func (b *BinanceDataSource) SetExchangeInfo(info *exchangeinfo.Service) {
+	b.exchangeInfo = info
+}
+
 func (b *BinanceDataSource) CollectTokenInfo(ctx context.Context, symbol string) (*models.TokenInfo, error) {
 	// Binance API doesn't provide comprehensive token info
 	// We'll only get what's available from the symbol info endpoint
+	if b.exchangeInfo != nil {
+		if info, ok := b.exchangeInfo.Symbol(symbol); ok {
+			return &models.TokenInfo{
+				Symbol: info.BaseAsset,
+				Name:   info.BaseAsset,
+			}, nil
+		}
+	}
+
 	url := fmt.Sprintf("%s/api/v3/exchangeInfo?symbol=%s", b.baseURL, symbol)
 
 	resp, err := b.httpClient.R().Get(url)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return nil, fmt.Errorf("%w: failed to execute request: %w", data.ErrSourceUnavailable, err)
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode())
+		return nil, fmt.Errorf("%w: unexpected status code: %d", data.ErrSourceUnavailable, resp.StatusCode())
 	}
 
 	var result struct {
@@ -57,7 +74,7 @@ func (b *BinanceDataSource) CollectTokenInfo(ctx context.Context, symbol string)
 	}
 
 	if len(result.Symbols) == 0 {
-		return nil, fmt.Errorf("symbol not found")
+		return nil, fmt.Errorf("%w: %s", data.ErrSymbolNotFound, symbol)
 	}
 
 	return &models.TokenInfo{
@@ -72,11 +89,11 @@ func (b *BinanceDataSource) CollectMarketData(ctx context.Context, symbol string
 
 	resp, err := b.httpClient.R().Get(url)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return nil, fmt.Errorf("%w: failed to execute request: %w", data.ErrSourceUnavailable, err)
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode())
+		return nil, fmt.Errorf("%w: unexpected status code: %d", data.ErrSourceUnavailable, resp.StatusCode())
 	}
 
 	var ticker struct {
@@ -119,3 +136,54 @@ func (b *BinanceDataSource) CollectSocialMetrics(ctx context.Context, symbol str
 	// This is a placeholder that could be implemented by combining with other APIs
 	return map[string]float64{}, nil
 }
+
+// Tickers 返回全市场交易对的 24 小时行情，用于代币发现
+func (b *BinanceDataSource) Tickers(ctx context.Context) ([]models.MarketData, error) {
+	url := fmt.Sprintf("%s/api/v3/ticker/24hr", b.baseURL)
+
+	resp, err := b.httpClient.R().SetContext(ctx).Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to execute request: %w", data.ErrSourceUnavailable, err)
+	}
+
+	if resp.StatusCode() != http.StatusOK {
+		return nil, fmt.Errorf("%w: unexpected status code: %d", data.ErrSourceUnavailable, resp.StatusCode())
+	}
+
+	var tickers []struct {
+		Symbol             string `json:"symbol"`
+		LastPrice          string `json:"lastPrice"`
+		Volume             string `json:"volume"`
+		PriceChangePercent string `json:"priceChangePercent"`
+	}
+
+	if err := json.Unmarshal(resp.Body(), &tickers); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	now := time.Now()
+	result := make([]models.MarketData, 0, len(tickers))
+	for _, ticker := range tickers {
+		price, err := strconv.ParseFloat(ticker.LastPrice, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse price of %s: %w", ticker.Symbol, err)
+		}
+		volume, err := strconv.ParseFloat(ticker.Volume, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse volume of %s: %w", ticker.Symbol, err)
+		}
+		priceChange, err := strconv.ParseFloat(ticker.PriceChangePercent, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse price change of %s: %w", ticker.Symbol, err)
+		}
+
+		result = append(result, models.MarketData{
+			Symbol:         ticker.Symbol,
+			Price:          price,
+			Volume24h:      volume,
+			PriceChange24h: priceChange,
+			Timestamp:      now,
+		})
+	}
+	return result, nil
+}
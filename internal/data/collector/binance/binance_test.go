@@ -3,6 +3,7 @@ package binance
 import (
 	"context"
 	"encoding/json"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -10,6 +11,8 @@ import (
 
 	"github.com/go-resty/resty/v2"
 
+	"github.com/songzhibin97/quantaflux/internal/exchangeinfo"
+
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -186,10 +189,10 @@ func TestBinanceDataSource_CollectSocialMetrics(t *testing.T) {
 	ds := NewBinanceDataSource()
 	ctx := context.Background()
 
+	// Binance 不提供社交指标，返回空结果而不是错误，行情处理据此跳过诈骗检测
 	metrics, err := ds.CollectSocialMetrics(ctx, "BTCUSDT")
-	assert.Error(t, err)
-	assert.Nil(t, metrics)
-	assert.Contains(t, err.Error(), "social metrics not available")
+	assert.NoError(t, err)
+	assert.Empty(t, metrics)
 }
 
 func TestBinanceDataSource_ErrorHandling(t *testing.T) {
@@ -250,6 +253,9 @@ func TestBinanceIntegration(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping integration test in short mode")
 	}
+	if _, err := net.LookupHost("api.binance.com"); err != nil {
+		t.Skipf("Skipping integration test, binance api unreachable: %v", err)
+	}
 
 	ds := NewBinanceDataSource()
 	ctx := context.Background()
@@ -383,3 +389,106 @@ func BenchmarkBinanceDataSource(b *testing.B) {
 		}
 	})
 }
+
+func TestBinanceDataSource_Klines(t *testing.T) {
+	server, ds := setupTestServer(t, "/api/v3/klines", [][]interface{}{
+		{1735689600000, "100.0", "110.0", "95.0", "105.5", "12.5", 1735689659999, "1300.0", 10},
+		{1735689660000, "105.5", "106.0", "104.0", "104.0", "3.0", 1735689719999, "312.0", 4},
+	})
+	defer server.Close()
+
+	start := time.UnixMilli(1735689600000)
+	klines, err := ds.Klines(context.Background(), "BTCUSDT", time.Minute, start, start.Add(time.Hour), 1000)
+	require.NoError(t, err)
+	require.Len(t, klines, 2)
+
+	assert.Equal(t, start.UTC(), klines[0].OpenTime)
+	assert.Equal(t, time.UnixMilli(1735689659999).UTC(), klines[0].CloseTime)
+	assert.Equal(t, 105.5, klines[0].Close)
+	assert.Equal(t, 12.5, klines[0].Volume)
+	assert.Equal(t, 104.0, klines[1].Low)
+
+	_, err = ds.Klines(context.Background(), "BTCUSDT", 7*time.Minute, start, start.Add(time.Hour), 1000)
+	assert.Error(t, err)
+}
+
+func TestBinanceDataSource_Depth(t *testing.T) {
+	server, ds := setupTestServer(t, "/api/v3/depth", map[string]interface{}{
+		"lastUpdateId": 1027024,
+		"bids":         [][]string{{"99.9", "2.5"}, {"99.8", "1.0"}},
+		"asks":         [][]string{{"100.1", "4.0"}},
+	})
+	defer server.Close()
+
+	book, err := ds.Depth(context.Background(), "BTCUSDT", 100)
+	require.NoError(t, err)
+	assert.Equal(t, "BTCUSDT", book.Symbol)
+	require.Len(t, book.Bids, 2)
+	assert.Equal(t, 99.9, book.Bids[0].Price)
+	assert.Equal(t, 2.5, book.Bids[0].Quantity)
+	require.Len(t, book.Asks, 1)
+	assert.Equal(t, 4.0, book.Asks[0].Quantity)
+}
+
+func TestBinanceDataSource_Tickers(t *testing.T) {
+	server, ds := setupTestServer(t, "/api/v3/ticker/24hr", []map[string]string{
+		{"symbol": "BTCUSDT", "lastPrice": "50000.0", "volume": "10.5", "priceChangePercent": "2.5"},
+		{"symbol": "NEWUSDT", "lastPrice": "0.5", "volume": "2000000", "priceChangePercent": "-12.0"},
+	})
+	defer server.Close()
+
+	tickers, err := ds.Tickers(context.Background())
+	require.NoError(t, err)
+	require.Len(t, tickers, 2)
+
+	assert.Equal(t, "BTCUSDT", tickers[0].Symbol)
+	assert.Equal(t, 50000.0, tickers[0].Price)
+	assert.Equal(t, 10.5, tickers[0].Volume24h)
+	assert.Equal(t, "NEWUSDT", tickers[1].Symbol)
+	assert.Equal(t, -12.0, tickers[1].PriceChange24h)
+}
+
+func TestBinanceDataSource_ExchangeInfo(t *testing.T) {
+	response := map[string]interface{}{
+		"symbols": []map[string]interface{}{
+			{
+				"symbol":     "BTCUSDT",
+				"status":     "TRADING",
+				"baseAsset":  "BTC",
+				"quoteAsset": "USDT",
+				"filters": []map[string]string{
+					{"filterType": "PRICE_FILTER", "tickSize": "0.01000000"},
+					{"filterType": "LOT_SIZE", "minQty": "0.00001000", "stepSize": "0.00001000"},
+					{"filterType": "NOTIONAL", "minNotional": "5.00000000"},
+				},
+			},
+			{"symbol": "ETHBTC", "status": "BREAK", "baseAsset": "ETH", "quoteAsset": "BTC"},
+		},
+	}
+	server, ds := setupTestServer(t, "/api/v3/exchangeInfo", response)
+	defer server.Close()
+
+	symbols, err := ds.ExchangeInfo(context.Background())
+	require.NoError(t, err)
+	require.Len(t, symbols, 2)
+	assert.Equal(t, exchangeinfo.Symbol{
+		Symbol:      "BTCUSDT",
+		BaseAsset:   "BTC",
+		QuoteAsset:  "USDT",
+		Status:      "TRADING",
+		TickSize:    0.01,
+		StepSize:    0.00001,
+		MinQty:      0.00001,
+		MinNotional: 5,
+	}, symbols[0])
+	assert.False(t, symbols[1].Trading())
+
+	// 设置缓存后代币信息从缓存读取
+	info := exchangeinfo.NewService(ds)
+	require.NoError(t, info.Refresh(context.Background()))
+	ds.SetExchangeInfo(info)
+	server.Close()
+	token, err := ds.CollectTokenInfo(context.Background(), "ETHBTC")
+	require.NoError(t, err)
+	assert.Equal(t, "ETH", token.Symbol)
+}
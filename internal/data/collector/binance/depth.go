@@ -0,0 +1,65 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/songzhibin97/quantaflux/internal/data"
+	"github.com/songzhibin97/quantaflux/internal/orderbook"
+)
+
+// Depth implements orderbook.Source
+func (b *BinanceDataSource) Depth(ctx context.Context, symbol string, limit int) (*orderbook.Book, error) {
+	resp, err := b.httpClient.R().
+		SetContext(ctx).
+		SetQueryParams(map[string]string{
+			"symbol": symbol,
+			"limit":  strconv.Itoa(limit),
+		}).
+		Get(b.baseURL + "/api/v3/depth")
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to execute request: %w", data.ErrSourceUnavailable, err)
+	}
+
+	if resp.StatusCode() != http.StatusOK {
+		return nil, fmt.Errorf("%w: unexpected status code: %d", data.ErrSourceUnavailable, resp.StatusCode())
+	}
+
+	// 每一档是 [价格, 数量]，均以字符串返回
+	var raw struct {
+		Bids [][2]string `json:"bids"`
+		Asks [][2]string `json:"asks"`
+	}
+	if err := json.Unmarshal(resp.Body(), &raw); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	bids, err := parseLevels(raw.Bids)
+	if err != nil {
+		return nil, err
+	}
+	asks, err := parseLevels(raw.Asks)
+	if err != nil {
+		return nil, err
+	}
+	return &orderbook.Book{Symbol: symbol, Bids: bids, Asks: asks}, nil
+}
+
+func parseLevels(raw [][2]string) ([]orderbook.Level, error) {
+	levels := make([]orderbook.Level, 0, len(raw))
+	for _, fields := range raw {
+		price, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse depth price: %w", err)
+		}
+		quantity, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse depth quantity: %w", err)
+		}
+		levels = append(levels, orderbook.Level{Price: price, Quantity: quantity})
+	}
+	return levels, nil
+}
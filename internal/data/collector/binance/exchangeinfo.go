@@ -0,0 +1,77 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/songzhibin97/quantaflux/internal/data"
+	"github.com/songzhibin97/quantaflux/internal/exchangeinfo"
+)
+
+// exchangeInfoResponse exchangeInfo 接口的响应，过滤条件的字段按类型不同
+type exchangeInfoResponse struct {
+	Symbols []struct {
+		Symbol     string `json:"symbol"`
+		Status     string `json:"status"`
+		BaseAsset  string `json:"baseAsset"`
+		QuoteAsset string `json:"quoteAsset"`
+		Filters    []struct {
+			FilterType  string `json:"filterType"`
+			TickSize    string `json:"tickSize"`
+			StepSize    string `json:"stepSize"`
+			MinQty      string `json:"minQty"`
+			MinNotional string `json:"minNotional"`
+		} `json:"filters"`
+	} `json:"symbols"`
+}
+
+// ExchangeInfo 返回全部交易对的状态和过滤条件，实现 exchangeinfo.Source
+func (b *BinanceDataSource) ExchangeInfo(ctx context.Context) ([]exchangeinfo.Symbol, error) {
+	url := fmt.Sprintf("%s/api/v3/exchangeInfo", b.baseURL)
+
+	resp, err := b.httpClient.R().SetContext(ctx).Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to execute request: %w", data.ErrSourceUnavailable, err)
+	}
+
+	if resp.StatusCode() != http.StatusOK {
+		return nil, fmt.Errorf("%w: unexpected status code: %d", data.ErrSourceUnavailable, resp.StatusCode())
+	}
+
+	var result exchangeInfoResponse
+	if err := json.Unmarshal(resp.Body(), &result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	symbols := make([]exchangeinfo.Symbol, 0, len(result.Symbols))
+	for _, s := range result.Symbols {
+		symbol := exchangeinfo.Symbol{
+			Symbol:     s.Symbol,
+			BaseAsset:  s.BaseAsset,
+			QuoteAsset: s.QuoteAsset,
+			Status:     s.Status,
+		}
+		for _, f := range s.Filters {
+			switch f.FilterType {
+			case "PRICE_FILTER":
+				symbol.TickSize = parseFilter(f.TickSize)
+			case "LOT_SIZE":
+				symbol.StepSize = parseFilter(f.StepSize)
+				symbol.MinQty = parseFilter(f.MinQty)
+			case "NOTIONAL", "MIN_NOTIONAL":
+				symbol.MinNotional = parseFilter(f.MinNotional)
+			}
+		}
+		symbols = append(symbols, symbol)
+	}
+	return symbols, nil
+}
+
+// parseFilter 解析过滤条件的数值，无法解析时视为未设置
+func parseFilter(s string) float64 {
+	v, _ := strconv.ParseFloat(s, 64)
+	return v
+}
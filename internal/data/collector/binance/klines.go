@@ -0,0 +1,111 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/data"
+	"github.com/songzhibin97/quantaflux/internal/data/backfill"
+)
+
+// Binance 支持的 K 线周期
+var klineIntervals = map[time.Duration]string{
+	time.Minute:        "1m",
+	3 * time.Minute:    "3m",
+	5 * time.Minute:    "5m",
+	15 * time.Minute:   "15m",
+	30 * time.Minute:   "30m",
+	time.Hour:          "1h",
+	2 * time.Hour:      "2h",
+	4 * time.Hour:      "4h",
+	6 * time.Hour:      "6h",
+	8 * time.Hour:      "8h",
+	12 * time.Hour:     "12h",
+	24 * time.Hour:     "1d",
+	3 * 24 * time.Hour: "3d",
+	7 * 24 * time.Hour: "1w",
+}
+
+// Klines implements backfill.Source
+func (b *BinanceDataSource) Klines(ctx context.Context, symbol string, interval time.Duration, start, end time.Time, limit int) ([]backfill.Kline, error) {
+	name, ok := klineIntervals[interval]
+	if !ok {
+		return nil, fmt.Errorf("unsupported kline interval: %s", interval)
+	}
+
+	resp, err := b.httpClient.R().
+		SetContext(ctx).
+		SetQueryParams(map[string]string{
+			"symbol":    symbol,
+			"interval":  name,
+			"startTime": strconv.FormatInt(start.UnixMilli(), 10),
+			"endTime":   strconv.FormatInt(end.UnixMilli(), 10),
+			"limit":     strconv.Itoa(limit),
+		}).
+		Get(b.baseURL + "/api/v3/klines")
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to execute request: %w", data.ErrSourceUnavailable, err)
+	}
+
+	if resp.StatusCode() != http.StatusOK {
+		return nil, fmt.Errorf("%w: unexpected status code: %d", data.ErrSourceUnavailable, resp.StatusCode())
+	}
+
+	// 每根 K 线是一个数组：[开盘时间, 开, 高, 低, 收, 成交量, 收盘时间, ...]
+	var raw [][]json.RawMessage
+	if err := json.Unmarshal(resp.Body(), &raw); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	klines := make([]backfill.Kline, 0, len(raw))
+	for _, fields := range raw {
+		k, err := parseKline(fields)
+		if err != nil {
+			return nil, err
+		}
+		klines = append(klines, k)
+	}
+	return klines, nil
+}
+
+func parseKline(fields []json.RawMessage) (backfill.Kline, error) {
+	if len(fields) < 7 {
+		return backfill.Kline{}, fmt.Errorf("invalid kline: %d fields", len(fields))
+	}
+
+	var openTime, closeTime int64
+	if err := json.Unmarshal(fields[0], &openTime); err != nil {
+		return backfill.Kline{}, fmt.Errorf("failed to parse open time: %w", err)
+	}
+	if err := json.Unmarshal(fields[6], &closeTime); err != nil {
+		return backfill.Kline{}, fmt.Errorf("failed to parse close time: %w", err)
+	}
+
+	// 价格和成交量以字符串返回
+	var values [5]float64
+	for i := range values {
+		var s string
+		if err := json.Unmarshal(fields[i+1], &s); err != nil {
+			return backfill.Kline{}, fmt.Errorf("failed to parse kline field %d: %w", i+1, err)
+		}
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return backfill.Kline{}, fmt.Errorf("failed to parse kline field %d: %w", i+1, err)
+		}
+		values[i] = v
+	}
+
+	return backfill.Kline{
+		OpenTime:  time.UnixMilli(openTime).UTC(),
+		CloseTime: time.UnixMilli(closeTime).UTC(),
+		Open:      values[0],
+		High:      values[1],
+		Low:       values[2],
+		Close:     values[3],
+		Volume:    values[4],
+	}, nil
+}
@@ -0,0 +1,242 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/songzhibin97/quantaflux/internal/data"
+	"github.com/songzhibin97/quantaflux/internal/data/backfill"
+	"github.com/songzhibin97/quantaflux/internal/models"
+	"github.com/songzhibin97/quantaflux/internal/supervisor"
+)
+
+// K 线推送间隔约 2 秒，超过该时长没有消息视为连接失效
+const streamReadTimeout = time.Minute
+
+// Logger 行情推送的日志
+type Logger interface {
+	Error(msg string, fields ...interface{})
+	Info(msg string, fields ...interface{})
+}
+
+// KlineStream 通过 WebSocket 订阅 K 线，每根 K 线收盘时转换为一条行情。
+// 成交量和涨跌幅按 24 小时滚动窗口由 K 线计算，订阅和重连时通过 REST 接口补齐窗口内的 K 线
+type KlineStream struct {
+	source     *BinanceDataSource
+	wsURL      string
+	dialer     *websocket.Dialer
+	logger     Logger
+	supervisor *supervisor.Supervisor // 连接断开后按退避重连
+}
+
+func NewKlineStream(source *BinanceDataSource, logger Logger) *KlineStream {
+	return &KlineStream{
+		source:     source,
+		wsURL:      "wss://stream.binance.com:9443/stream",
+		dialer:     websocket.DefaultDialer,
+		logger:     logger,
+		supervisor: supervisor.New(supervisor.DefaultBackoff),
+	}
+}
+
+// SetSupervisor 设置管理连接的 supervisor，连接状态记录在其中
+func (k *KlineStream) SetSupervisor(s *supervisor.Supervisor) {
+	k.supervisor = s
+}
+
+func (k *KlineStream) Name() string {
+	return "binance_kline_stream"
+}
+
+// Supports 是否支持该周期的 K 线订阅
+func (k *KlineStream) Supports(interval time.Duration) bool {
+	_, ok := klineIntervals[interval]
+	return ok
+}
+
+// klineState 单个订阅的滚动窗口和最近一根已收盘 K 线的时间
+type klineState struct {
+	sub       data.Subscription
+	window    *backfill.RollingWindow
+	lastClose time.Time
+}
+
+// Stream 订阅 K 线推送，返回的 channel 在 ctx 取消后关闭；不支持的周期返回错误
+func (k *KlineStream) Stream(ctx context.Context, subscriptions []data.Subscription) (<-chan models.MarketData, error) {
+	states := make(map[string]*klineState, len(subscriptions))
+	streams := make([]string, 0, len(subscriptions))
+	for _, sub := range subscriptions {
+		name, ok := klineIntervals[sub.Interval]
+		if !ok {
+			return nil, fmt.Errorf("unsupported kline interval: %s", sub.Interval)
+		}
+		stream := strings.ToLower(sub.Symbol) + "@kline_" + name
+		if _, ok := states[stream]; ok {
+			continue
+		}
+		states[stream] = &klineState{sub: sub, window: backfill.NewRollingWindow(backfill.Warmup)}
+		streams = append(streams, stream)
+	}
+
+	out := make(chan models.MarketData, 100)
+	if len(streams) == 0 {
+		close(out)
+		return out, nil
+	}
+
+	go func() {
+		defer close(out)
+
+		k.supervisor.Run(ctx, k.Name(), func(ctx context.Context, connected func()) error {
+			k.fill(ctx, states)
+			err := k.run(ctx, streams, states, out, connected)
+			if err != nil && ctx.Err() == nil {
+				k.logger.Error("kline stream disconnected", "error", err)
+			}
+			return err
+		})
+	}()
+
+	return out, nil
+}
+
+// fill 通过 REST 接口补齐各订阅自上一根已收盘 K 线以来的 K 线，首次订阅时补齐 24 小时。
+// 补齐的 K 线只用于计算滚动统计，不生成行情，避免按过期价格交易
+func (k *KlineStream) fill(ctx context.Context, states map[string]*klineState) {
+	now := time.Now()
+	for _, state := range states {
+		from := state.lastClose.Add(time.Millisecond)
+		if state.lastClose.IsZero() || from.Before(now.Add(-backfill.Warmup)) {
+			from = now.Add(-backfill.Warmup)
+		}
+
+		for from.Before(now) {
+			klines, err := k.source.Klines(ctx, state.sub.Symbol, state.sub.Interval, from, now, backfill.DefaultPageSize)
+			if err != nil {
+				k.logger.Error("failed to fill klines", "symbol", state.sub.Symbol, "interval", state.sub.Interval, "error", err)
+				break
+			}
+			for _, kline := range klines {
+				// 最后一根 K 线可能尚未收盘
+				if kline.CloseTime.After(now) {
+					break
+				}
+				state.window.Add(state.sub.Symbol, kline)
+				state.lastClose = kline.CloseTime
+			}
+			if len(klines) < backfill.DefaultPageSize {
+				break
+			}
+			from = klines[len(klines)-1].OpenTime.Add(state.sub.Interval)
+		}
+	}
+}
+
+// klineEvent 组合订阅推送的 K 线消息
+type klineEvent struct {
+	Stream string `json:"stream"`
+	Data   struct {
+		Kline struct {
+			OpenTime  int64  `json:"t"`
+			CloseTime int64  `json:"T"`
+			Open      string `json:"o"`
+			High      string `json:"h"`
+			Low       string `json:"l"`
+			Close     string `json:"c"`
+			Volume    string `json:"v"`
+			Closed    bool   `json:"x"`
+		} `json:"k"`
+	} `json:"data"`
+}
+
+// run 建立连接并处理推送，直到连接断开或 ctx 取消；连接建立后调用 connected
+func (k *KlineStream) run(ctx context.Context, streams []string, states map[string]*klineState, out chan<- models.MarketData, connected func()) error {
+	conn, _, err := k.dialer.DialContext(ctx, k.wsURL+"?streams="+strings.Join(streams, "/"), nil)
+	if err != nil {
+		return fmt.Errorf("%w: failed to connect kline stream: %w", data.ErrSourceUnavailable, err)
+	}
+
+	var once sync.Once
+	closeConn := func() { once.Do(func() { _ = conn.Close() }) }
+	defer closeConn()
+
+	// ctx 取消时关闭连接，结束阻塞的读取
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			closeConn()
+		case <-done:
+		}
+	}()
+
+	k.logger.Info("kline stream connected", "streams", len(streams))
+	connected()
+	for {
+		_ = conn.SetReadDeadline(time.Now().Add(streamReadTimeout))
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+
+		var event klineEvent
+		if err := json.Unmarshal(message, &event); err != nil {
+			k.logger.Error("failed to decode kline event", "error", err)
+			continue
+		}
+		state, ok := states[event.Stream]
+		if !ok || !event.Data.Kline.Closed {
+			continue
+		}
+
+		kline, err := parseStreamKline(event)
+		if err != nil {
+			k.logger.Error("failed to parse kline event", "stream", event.Stream, "error", err)
+			continue
+		}
+		// 重连后补齐的 K 线可能与推送重复
+		if !kline.CloseTime.After(state.lastClose) {
+			continue
+		}
+		state.lastClose = kline.CloseTime
+
+		marketData := state.window.Add(state.sub.Symbol, kline)
+		marketData.Timeframe = state.sub.Timeframe()
+		marketData.Source = k.Name()
+		select {
+		case out <- marketData:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func parseStreamKline(event klineEvent) (backfill.Kline, error) {
+	k := event.Data.Kline
+	var values [5]float64
+	for i, s := range []string{k.Open, k.High, k.Low, k.Close, k.Volume} {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return backfill.Kline{}, fmt.Errorf("failed to parse kline field %d: %w", i+1, err)
+		}
+		values[i] = v
+	}
+
+	return backfill.Kline{
+		OpenTime:  time.UnixMilli(k.OpenTime).UTC(),
+		CloseTime: time.UnixMilli(k.CloseTime).UTC(),
+		Open:      values[0],
+		High:      values[1],
+		Low:       values[2],
+		Close:     values[3],
+		Volume:    values[4],
+	}, nil
+}
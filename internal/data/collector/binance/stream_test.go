@@ -0,0 +1,97 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/gorilla/websocket"
+
+	"github.com/songzhibin97/quantaflux/internal/data"
+	"github.com/songzhibin97/quantaflux/internal/supervisor"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Error(msg string, fields ...interface{}) {}
+func (nopLogger) Info(msg string, fields ...interface{})  {}
+
+func TestKlineStream_Stream(t *testing.T) {
+	// 最近两根已收盘的 1m K 线，用于预热滚动窗口
+	now := time.Now().Truncate(time.Minute)
+	first, second := now.Add(-2*time.Minute), now.Add(-time.Minute)
+	restKlines := fmt.Sprintf(`[[%d,"100","101","99","100","10",%d],[%d,"100","103","100","102","20",%d]]`,
+		first.UnixMilli(), second.UnixMilli()-1, second.UnixMilli(), now.UnixMilli()-1)
+
+	event := func(closed bool, close string) string {
+		return fmt.Sprintf(`{"stream":"btcusdt@kline_1m","data":{"e":"kline","s":"BTCUSDT","k":{"t":%d,"T":%d,"o":"102","h":"106","l":"101","c":"%s","v":"30","x":%t}}}`,
+			now.UnixMilli(), now.Add(time.Minute).UnixMilli()-1, close, closed)
+	}
+
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/klines":
+			assert.Equal(t, "BTCUSDT", r.URL.Query().Get("symbol"))
+			assert.Equal(t, "1m", r.URL.Query().Get("interval"))
+			_, _ = w.Write([]byte(restKlines))
+		case "/stream":
+			assert.Equal(t, "btcusdt@kline_1m", r.URL.Query().Get("streams"))
+			conn, err := upgrader.Upgrade(w, r, nil)
+			require.NoError(t, err)
+			defer conn.Close()
+			// 未收盘的 K 线不生成行情
+			require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(event(false, "104"))))
+			require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(event(true, "105"))))
+			_, _, _ = conn.ReadMessage()
+		}
+	}))
+	defer server.Close()
+
+	source := NewBinanceDataSource()
+	source.baseURL = server.URL
+	source.httpClient = resty.NewWithClient(server.Client())
+	stream := NewKlineStream(source, nopLogger{})
+	stream.wsURL = "ws" + strings.TrimPrefix(server.URL, "http") + "/stream"
+	sup := supervisor.New(supervisor.DefaultBackoff)
+	stream.SetSupervisor(sup)
+
+	assert.True(t, stream.Supports(time.Hour))
+	assert.False(t, stream.Supports(30*time.Second))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updates, err := stream.Stream(ctx, []data.Subscription{{Symbol: "BTCUSDT", Interval: time.Minute}})
+	require.NoError(t, err)
+
+	select {
+	case update := <-updates:
+		assert.Equal(t, "BTCUSDT", update.Symbol)
+		assert.Equal(t, "1m", update.Timeframe)
+		assert.InDelta(t, 105, update.Price, 1e-9)
+		assert.InDelta(t, 60, update.Volume24h, 1e-9)
+		assert.InDelta(t, 5, update.PriceChange1h, 1e-9)
+		assert.Equal(t, now.Add(time.Minute).Add(-time.Millisecond).UTC(), update.Timestamp)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no market data from kline stream")
+	}
+	status := sup.Status()
+	require.Len(t, status, 1)
+	assert.Equal(t, supervisor.StateConnected, status[0].State)
+
+	cancel()
+	for range updates {
+	}
+	assert.Equal(t, supervisor.StateStopped, sup.Status()[0].State)
+
+	_, err = stream.Stream(context.Background(), []data.Subscription{{Symbol: "BTCUSDT", Interval: 30 * time.Second}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,257 @@
+// Package coingecko 从 CoinGecko 获取行情、代币信息和社区指标
+package coingecko
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+
+	"github.com/songzhibin97/quantaflux/internal/data"
+	"github.com/songzhibin97/quantaflux/internal/models"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+	"github.com/songzhibin97/quantaflux/internal/utils/request"
+)
+
+const (
+	// PublicURL 公开接口地址
+	PublicURL = "https://api.coingecko.com"
+	// ProURL 付费接口地址，使用 x-cg-pro-api-key 认证
+	ProURL = "https://pro-api.coingecko.com"
+)
+
+// usdQuotes 按美元报价的稳定币，CoinGecko 不支持以它们作为计价货币
+var usdQuotes = map[string]bool{"USDT": true, "USDC": true, "BUSD": true, "FDUSD": true, "TUSD": true}
+
+type CoinGeckoDataSource struct {
+	baseURL    string
+	apiKey     string
+	httpClient *resty.Client
+
+	mu  sync.Mutex
+	ids map[string]string // 基础资产 -> 币种 ID，包括配置的和搜索得到的
+}
+
+// NewCoinGeckoDataSource 创建数据源，baseURL 为空时使用公开地址；ids 为基础资产到币种 ID 的映射，
+// 未配置的基础资产按代码搜索，取市值排名最高的同代码币种
+func NewCoinGeckoDataSource(baseURL, apiKey string, ids map[string]string) *CoinGeckoDataSource {
+	if baseURL == "" {
+		baseURL = PublicURL
+	}
+	known := make(map[string]string, len(ids))
+	for asset, id := range ids {
+		known[strings.ToUpper(asset)] = id
+	}
+	return &CoinGeckoDataSource{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		apiKey:     apiKey,
+		httpClient: request.Request,
+		ids:        known,
+	}
+}
+
+func (c *CoinGeckoDataSource) Name() string {
+	return "coingecko"
+}
+
+// get 请求接口并解析 JSON 响应
+func (c *CoinGeckoDataSource) get(ctx context.Context, path string, params map[string]string, result any) error {
+	req := c.httpClient.R().SetContext(ctx).SetQueryParams(params)
+	if c.apiKey != "" {
+		header := "x-cg-demo-api-key"
+		if c.baseURL == ProURL {
+			header = "x-cg-pro-api-key"
+		}
+		req.SetHeader(header, c.apiKey)
+	}
+
+	resp, err := req.Get(c.baseURL + path)
+	if err != nil {
+		return fmt.Errorf("%w: failed to execute request: %w", data.ErrSourceUnavailable, err)
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return fmt.Errorf("%w: unexpected status code: %d", data.ErrSourceUnavailable, resp.StatusCode())
+	}
+	if err := json.Unmarshal(resp.Body(), result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// coinID 返回交易对基础资产的币种 ID
+func (c *CoinGeckoDataSource) coinID(ctx context.Context, symbol string) (string, error) {
+	base, _, ok := trading.SplitSymbol(symbol)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", data.ErrSymbolNotFound, symbol)
+	}
+
+	c.mu.Lock()
+	id, ok := c.ids[base]
+	c.mu.Unlock()
+	if ok {
+		return id, nil
+	}
+
+	var result struct {
+		Coins []struct {
+			ID            string `json:"id"`
+			Symbol        string `json:"symbol"`
+			MarketCapRank int    `json:"market_cap_rank"`
+		} `json:"coins"`
+	}
+	if err := c.get(ctx, "/api/v3/search", map[string]string{"query": base}, &result); err != nil {
+		return "", err
+	}
+	best := -1
+	for i, coin := range result.Coins {
+		if !strings.EqualFold(coin.Symbol, base) || coin.MarketCapRank == 0 {
+			continue
+		}
+		if best < 0 || coin.MarketCapRank < result.Coins[best].MarketCapRank {
+			best = i
+		}
+	}
+	if best < 0 {
+		return "", fmt.Errorf("%w: %s", data.ErrSymbolNotFound, symbol)
+	}
+
+	id = result.Coins[best].ID
+	c.mu.Lock()
+	c.ids[base] = id
+	c.mu.Unlock()
+	return id, nil
+}
+
+// coin 币种详情中使用的字段
+type coin struct {
+	Symbol          string            `json:"symbol"`
+	Name            string            `json:"name"`
+	AssetPlatformID string            `json:"asset_platform_id"`
+	Platforms       map[string]string `json:"platforms"`
+	GenesisDate     string            `json:"genesis_date"`
+	MarketData      struct {
+		TotalSupply       float64 `json:"total_supply"`
+		CirculatingSupply float64 `json:"circulating_supply"`
+	} `json:"market_data"`
+	CommunityData struct {
+		TwitterFollowers         float64 `json:"twitter_followers"`
+		RedditSubscribers        float64 `json:"reddit_subscribers"`
+		TelegramChannelUserCount float64 `json:"telegram_channel_user_count"`
+	} `json:"community_data"`
+	DeveloperData struct {
+		Stars float64 `json:"stars"`
+	} `json:"developer_data"`
+}
+
+func (c *CoinGeckoDataSource) coin(ctx context.Context, symbol string) (*coin, error) {
+	id, err := c.coinID(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	var result coin
+	params := map[string]string{
+		"localization":   "false",
+		"tickers":        "false",
+		"market_data":    "true",
+		"community_data": "true",
+		"developer_data": "true",
+		"sparkline":      "false",
+	}
+	if err := c.get(ctx, "/api/v3/coins/"+id, params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *CoinGeckoDataSource) CollectTokenInfo(ctx context.Context, symbol string) (*models.TokenInfo, error) {
+	result, err := c.coin(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &models.TokenInfo{
+		Symbol:            strings.ToUpper(result.Symbol),
+		Name:              result.Name,
+		Network:           result.AssetPlatformID,
+		TotalSupply:       result.MarketData.TotalSupply,
+		CirculatingSupply: result.MarketData.CirculatingSupply,
+	}
+	if result.AssetPlatformID != "" {
+		info.ContractAddress = result.Platforms[result.AssetPlatformID]
+	}
+	if result.GenesisDate != "" {
+		if launched, err := time.Parse(time.DateOnly, result.GenesisDate); err == nil {
+			info.LaunchDate = launched
+		}
+	}
+	return info, nil
+}
+
+func (c *CoinGeckoDataSource) CollectMarketData(ctx context.Context, symbol string) (*models.MarketData, error) {
+	id, err := c.coinID(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	_, quote, _ := trading.SplitSymbol(symbol)
+	currency := strings.ToLower(quote)
+	if usdQuotes[quote] {
+		currency = "usd"
+	}
+
+	var result map[string]map[string]float64
+	params := map[string]string{
+		"ids":                     id,
+		"vs_currencies":           currency,
+		"include_24hr_vol":        "true",
+		"include_24hr_change":     "true",
+		"include_last_updated_at": "true",
+	}
+	if err := c.get(ctx, "/api/v3/simple/price", params, &result); err != nil {
+		return nil, err
+	}
+	prices, ok := result[id]
+	if !ok || prices[currency] <= 0 {
+		return nil, fmt.Errorf("%w: no %s price of %s", data.ErrSourceUnavailable, currency, id)
+	}
+
+	price := prices[currency]
+	timestamp := time.Now()
+	if updated := prices["last_updated_at"]; updated > 0 {
+		timestamp = time.Unix(int64(updated), 0)
+	}
+	return &models.MarketData{
+		Symbol: symbol,
+		Price:  price,
+		// CoinGecko 的成交量按计价货币计，换算为基础资产数量与交易所行情一致
+		Volume24h:      prices[currency+"_24h_vol"] / price,
+		PriceChange24h: prices[currency+"_24h_change"],
+		Timestamp:      timestamp,
+	}, nil
+}
+
+// CollectSocialMetrics 返回推特关注人数、Telegram 成员数、Reddit 订阅数和 GitHub 星标数
+func (c *CoinGeckoDataSource) CollectSocialMetrics(ctx context.Context, symbol string) (map[string]float64, error) {
+	result, err := c.coin(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := make(map[string]float64)
+	for name, value := range map[string]float64{
+		"twitter_followers": result.CommunityData.TwitterFollowers,
+		"telegram_members":  result.CommunityData.TelegramChannelUserCount,
+		"reddit_members":    result.CommunityData.RedditSubscribers,
+		"github_stars":      result.DeveloperData.Stars,
+	} {
+		// CoinGecko 未收录的指标为 0 或缺失，不覆盖其他数据源的值
+		if value > 0 {
+			metrics[name] = value
+		}
+	}
+	return metrics, nil
+}
@@ -0,0 +1,110 @@
+package coingecko
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+
+	"github.com/songzhibin97/quantaflux/internal/data"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupTestServer 按路径返回固定响应，记录收到的请求
+func setupTestServer(t *testing.T, responses map[string]any) (*httptest.Server, *CoinGeckoDataSource, *[]*http.Request) {
+	var requests []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		response, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(response))
+	}))
+	t.Cleanup(server.Close)
+
+	source := NewCoinGeckoDataSource(server.URL, "demo-key", map[string]string{"btc": "bitcoin"})
+	source.httpClient = resty.NewWithClient(server.Client())
+	return server, source, &requests
+}
+
+func TestCoinGeckoDataSource_CollectMarketData(t *testing.T) {
+	_, source, requests := setupTestServer(t, map[string]any{
+		"/api/v3/simple/price": map[string]any{
+			"bitcoin": map[string]any{"usd": 50000.0, "usd_24h_vol": 1e9, "usd_24h_change": -2.5, "last_updated_at": 1700000000},
+		},
+	})
+
+	marketData, err := source.CollectMarketData(context.Background(), "BTCUSDT")
+	require.NoError(t, err)
+	assert.Equal(t, "BTCUSDT", marketData.Symbol)
+	assert.Equal(t, 50000.0, marketData.Price)
+	assert.InDelta(t, 20000, marketData.Volume24h, 1e-9)
+	assert.Equal(t, -2.5, marketData.PriceChange24h)
+	assert.Equal(t, time.Unix(1700000000, 0), marketData.Timestamp)
+
+	require.Len(t, *requests, 1)
+	req := (*requests)[0]
+	assert.Equal(t, "bitcoin", req.URL.Query().Get("ids"))
+	assert.Equal(t, "usd", req.URL.Query().Get("vs_currencies"))
+	assert.Equal(t, "demo-key", req.Header.Get("x-cg-demo-api-key"))
+}
+
+func TestCoinGeckoDataSource_SearchCoinID(t *testing.T) {
+	_, source, requests := setupTestServer(t, map[string]any{
+		"/api/v3/search": map[string]any{
+			"coins": []map[string]any{
+				{"id": "pepe-fake", "symbol": "PEPE", "market_cap_rank": 900},
+				{"id": "pepe", "symbol": "PEPE", "market_cap_rank": 30},
+				{"id": "pepecoin", "symbol": "PEPECOIN", "market_cap_rank": 10},
+			},
+		},
+		"/api/v3/coins/pepe": map[string]any{
+			"symbol":            "pepe",
+			"name":              "Pepe",
+			"asset_platform_id": "ethereum",
+			"platforms":         map[string]string{"ethereum": "0x6982"},
+			"genesis_date":      "2023-04-14",
+			"market_data":       map[string]any{"total_supply": 420e12, "circulating_supply": 420e12},
+			"community_data":    map[string]any{"twitter_followers": 500000, "telegram_channel_user_count": nil},
+			"developer_data":    map[string]any{"stars": 0},
+		},
+	})
+
+	info, err := source.CollectTokenInfo(context.Background(), "PEPEUSDT")
+	require.NoError(t, err)
+	assert.Equal(t, "PEPE", info.Symbol)
+	assert.Equal(t, "ethereum", info.Network)
+	assert.Equal(t, "0x6982", info.ContractAddress)
+	assert.Equal(t, time.Date(2023, 4, 14, 0, 0, 0, 0, time.UTC), info.LaunchDate)
+	assert.Equal(t, 420e12, info.TotalSupply)
+
+	// 搜索结果被缓存，未收录的指标不返回
+	metrics, err := source.CollectSocialMetrics(context.Background(), "PEPEUSDT")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"twitter_followers": 500000}, metrics)
+	assert.Len(t, *requests, 3)
+}
+
+func TestCoinGeckoDataSource_Errors(t *testing.T) {
+	_, source, _ := setupTestServer(t, map[string]any{
+		"/api/v3/search":       map[string]any{"coins": []any{}},
+		"/api/v3/simple/price": map[string]any{},
+	})
+	ctx := context.Background()
+
+	_, err := source.CollectMarketData(ctx, "NOPEUSDT")
+	assert.ErrorIs(t, err, data.ErrSymbolNotFound)
+	_, err = source.CollectMarketData(ctx, "BTCUSDT")
+	assert.ErrorIs(t, err, data.ErrSourceUnavailable)
+	_, err = source.CollectTokenInfo(ctx, "BTCUSDT")
+	assert.ErrorIs(t, err, data.ErrSourceUnavailable)
+}
@@ -2,17 +2,23 @@ package collector
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
 
+	"github.com/songzhibin97/quantaflux/internal/clock"
+	"github.com/songzhibin97/quantaflux/internal/data"
 	"github.com/songzhibin97/quantaflux/internal/models"
 )
 
 // MultiSourceCollector implements DataCollector interface by aggregating multiple data sources
 type MultiSourceCollector struct {
-	sources []DataSource
-	logger  Logger
+	sources  []DataSource
+	streamer Streamer            // 推送行情源，为空时全部轮询
+	checker  *ConsistencyChecker // 多个数据源报价的一致性检查，为空时使用第一个可用数据源
+	logger   Logger
+	clock    clock.Clock // 轮询间隔使用的时钟，为空时使用系统时钟
 }
 
 type Logger interface {
@@ -20,6 +26,13 @@ type Logger interface {
 	Info(msg string, fields ...interface{})
 }
 
+// SourceConsensus 按多个数据源共识价格生成的行情的数据源名称
+const SourceConsensus = "consensus"
+
+// ErrNotSupported 数据源不提供该类数据，如只提供社交指标的数据源不提供行情；汇总时跳过，不记录错误
+var ErrNotSupported = errors.New("not supported by data source")
+
+// DataSource 单个数据源，NewMultiSourceCollector 按 sources 的顺序依次尝试
 type DataSource interface {
 	Name() string
 	CollectTokenInfo(ctx context.Context, symbol string) (*models.TokenInfo, error)
@@ -27,6 +40,15 @@ type DataSource interface {
 	CollectSocialMetrics(ctx context.Context, symbol string) (map[string]float64, error)
 }
 
+// Streamer 通过推送订阅行情的数据源
+type Streamer interface {
+	Name() string
+	// Supports reports whether subscriptions of the interval can be streamed
+	Supports(interval time.Duration) bool
+	// Stream returns a channel of market updates which is closed after ctx is done
+	Stream(ctx context.Context, subscriptions []data.Subscription) (<-chan models.MarketData, error)
+}
+
 func NewMultiSourceCollector(sources []DataSource, logger Logger) *MultiSourceCollector {
 	return &MultiSourceCollector{
 		sources: sources,
@@ -34,13 +56,37 @@ func NewMultiSourceCollector(sources []DataSource, logger Logger) *MultiSourceCo
 	}
 }
 
+// SetStreamer 设置推送行情源，支持的订阅周期改为推送，其余周期仍按间隔轮询
+func (c *MultiSourceCollector) SetStreamer(streamer Streamer) {
+	c.streamer = streamer
+}
+
+// SetClock 设置轮询间隔使用的时钟，需在 SubscribeMarketData 之前调用
+func (c *MultiSourceCollector) SetClock(clk clock.Clock) {
+	c.clock = clk
+}
+
+// SetConsistencyChecker 设置报价一致性检查，多个数据源时行情价格取各数据源报价的中位数
+func (c *MultiSourceCollector) SetConsistencyChecker(checker *ConsistencyChecker) {
+	c.checker = checker
+}
+
+// consensus 是否按多个数据源的共识价格生成行情
+func (c *MultiSourceCollector) consensus() bool {
+	return c.checker != nil && len(c.sources) > 1
+}
+
 // CollectTokenInfo implements DataCollector interface
 func (c *MultiSourceCollector) CollectTokenInfo(ctx context.Context, symbol string) (*models.TokenInfo, error) {
 	var result *models.TokenInfo
 	var err error
 
 	for _, source := range c.sources {
-		result, err = source.CollectTokenInfo(ctx, symbol)
+		info, sourceErr := source.CollectTokenInfo(ctx, symbol)
+		if errors.Is(sourceErr, ErrNotSupported) {
+			continue
+		}
+		result, err = info, sourceErr
 		if err == nil && result != nil {
 			c.logger.Info("collected token info", "source", source.Name(), "symbol", symbol)
 			return result, nil
@@ -48,55 +94,114 @@ func (c *MultiSourceCollector) CollectTokenInfo(ctx context.Context, symbol stri
 		c.logger.Error("failed to collect token info", "source", source.Name(), "error", err)
 	}
 
-	return nil, fmt.Errorf("failed to collect token info from all sources")
+	return nil, fmt.Errorf("%w: failed to collect token info from all sources: %w", data.ErrSourceUnavailable, err)
 }
 
 // CollectMarketData implements DataCollector interface
 func (c *MultiSourceCollector) CollectMarketData(ctx context.Context, symbol string) (*models.MarketData, error) {
+	if c.consensus() {
+		return c.collectConsensus(ctx, symbol)
+	}
+
 	var result *models.MarketData
 	var err error
 
 	for _, source := range c.sources {
-		result, err = source.CollectMarketData(ctx, symbol)
+		marketData, sourceErr := source.CollectMarketData(ctx, symbol)
+		if errors.Is(sourceErr, ErrNotSupported) {
+			continue
+		}
+		result, err = marketData, sourceErr
 		if err == nil && result != nil {
 			c.logger.Info("collected market data", "source", source.Name(), "symbol", symbol)
+			if result.Source == "" {
+				result.Source = source.Name()
+			}
 			return result, nil
 		}
 		c.logger.Error("failed to collect market data", "source", source.Name(), "error", err)
 	}
 
-	return nil, fmt.Errorf("failed to collect market data from all sources")
+	return nil, fmt.Errorf("%w: failed to collect market data from all sources: %w", data.ErrSourceUnavailable, err)
+}
+
+// collectConsensus 同时查询所有数据源，按报价中位数生成行情，报价偏离超过阈值的数据源记录错误日志
+func (c *MultiSourceCollector) collectConsensus(ctx context.Context, symbol string) (*models.MarketData, error) {
+	// 按数据源顺序保存结果，共识行情不受返回先后影响
+	results := make([]*models.MarketData, len(c.sources))
+	errs := make([]error, len(c.sources))
+	var wg sync.WaitGroup
+
+	for i, source := range c.sources {
+		wg.Add(1)
+		go func(i int, src DataSource) {
+			defer wg.Done()
+			results[i], errs[i] = src.CollectMarketData(ctx, symbol)
+		}(i, source)
+	}
+
+	wg.Wait()
+
+	quotes := make([]Quote, 0, len(c.sources))
+	for i, source := range c.sources {
+		if errors.Is(errs[i], ErrNotSupported) {
+			errs[i] = nil
+			continue
+		}
+		if errs[i] != nil || results[i] == nil {
+			c.logger.Error("failed to collect market data", "source", source.Name(), "error", errs[i])
+			continue
+		}
+		quotes = append(quotes, Quote{Source: source.Name(), Data: *results[i]})
+	}
+	if len(quotes) == 0 {
+		return nil, fmt.Errorf("%w: failed to collect market data from all sources: %w", data.ErrSourceUnavailable, errors.Join(errs...))
+	}
+
+	result, divergences := c.checker.Consensus(quotes)
+	for _, d := range divergences {
+		c.logger.Error("price divergence across sources", "source", d.Source, "symbol", symbol,
+			"price", d.Price, "consensus", d.Consensus, "deviation", d.Deviation)
+	}
+	c.logger.Info("collected market data", "sources", len(quotes), "symbol", symbol)
+	result.Source = SourceConsensus
+	return result, nil
 }
 
-// CollectSocialMetrics implements DataCollector interface
+// CollectSocialMetrics implements DataCollector interface; 多个数据源返回同名指标时取排在前面的数据源
 func (c *MultiSourceCollector) CollectSocialMetrics(ctx context.Context, symbol string) (map[string]float64, error) {
-	results := make(map[string]float64)
-	var mu sync.Mutex
+	collected := make([]map[string]float64, len(c.sources))
 	var wg sync.WaitGroup
 
-	for _, source := range c.sources {
+	for i, source := range c.sources {
 		wg.Add(1)
-		go func(src DataSource) {
+		go func(i int, src DataSource) {
 			defer wg.Done()
 
 			metrics, err := src.CollectSocialMetrics(ctx, symbol)
+			if errors.Is(err, ErrNotSupported) {
+				return
+			}
 			if err != nil {
 				c.logger.Error("failed to collect social metrics", "source", src.Name(), "error", err)
 				return
 			}
-
-			mu.Lock()
-			for k, v := range metrics {
-				results[k] = v
-			}
-			mu.Unlock()
+			collected[i] = metrics
 
 			c.logger.Info("collected social metrics", "source", src.Name(), "symbol", symbol)
-		}(source)
+		}(i, source)
 	}
 
 	wg.Wait()
 
+	// 从后往前合并，排在前面的数据源覆盖同名指标
+	results := make(map[string]float64)
+	for i := len(collected) - 1; i >= 0; i-- {
+		for k, v := range collected[i] {
+			results[k] = v
+		}
+	}
+
 	//if len(results) == 0 {
 	//	return nil, fmt.Errorf("failed to collect social metrics from all sources")
 	//}
@@ -104,41 +209,102 @@ func (c *MultiSourceCollector) CollectSocialMetrics(ctx context.Context, symbol
 	return results, nil
 }
 
-// SubscribeToMarketData implements DataCollector interface
-func (c *MultiSourceCollector) SubscribeToMarketData(ctx context.Context, symbols []string, refreshInterval time.Duration) (<-chan models.MarketData, error) {
+// SubscribeToMarketData implements DataCollector interface; subscriptions with the same interval share one poller
+func (c *MultiSourceCollector) SubscribeToMarketData(ctx context.Context, subscriptions []data.Subscription) (<-chan models.MarketData, error) {
 	out := make(chan models.MarketData, 100)
 	var wg sync.WaitGroup
 
-	// 启动所有数据源的订阅
-	for _, source := range c.sources {
+	// 推送源支持的周期改为推送
+	var streamed []data.Subscription
+	if c.streamer != nil {
+		polled := subscriptions[:0:0]
+		for _, sub := range subscriptions {
+			if c.streamer.Supports(sub.Interval) {
+				streamed = append(streamed, sub)
+			} else {
+				polled = append(polled, sub)
+			}
+		}
+		subscriptions = polled
+	}
+	if len(streamed) > 0 {
+		updates, err := c.streamer.Stream(ctx, streamed)
+		if err != nil {
+			return nil, fmt.Errorf("failed to subscribe %s: %w", c.streamer.Name(), err)
+		}
 		wg.Add(1)
-		go func(src DataSource) {
+		go func() {
 			defer wg.Done()
-
-			ticker := time.NewTicker(refreshInterval)
-			defer ticker.Stop()
-
-			for {
+			for update := range updates {
 				select {
+				case out <- update:
 				case <-ctx.Done():
-					return
-				case <-ticker.C:
-					for _, symbol := range symbols {
-						data, err := src.CollectMarketData(ctx, symbol)
-						if err != nil {
-							c.logger.Error("failed to collect market data", "source", src.Name(), "symbol", symbol, "error", err)
-							continue
-						}
+				}
+			}
+		}()
+	}
+
+	// 按周期分组，保持订阅顺序
+	var intervals []time.Duration
+	groups := make(map[time.Duration][]string)
+	for _, sub := range subscriptions {
+		if _, ok := groups[sub.Interval]; !ok {
+			intervals = append(intervals, sub.Interval)
+		}
+		groups[sub.Interval] = append(groups[sub.Interval], sub.Symbol)
+	}
+
+	// 启动所有数据源的订阅，按共识价格生成行情时所有数据源合并为一个订阅
+	type poller struct {
+		name    string
+		collect func(ctx context.Context, symbol string) (*models.MarketData, error)
+	}
+	var pollers []poller
+	if c.consensus() {
+		pollers = append(pollers, poller{name: SourceConsensus, collect: c.CollectMarketData})
+	} else {
+		for _, source := range c.sources {
+			pollers = append(pollers, poller{name: source.Name(), collect: source.CollectMarketData})
+		}
+	}
+
+	for _, src := range pollers {
+		for _, interval := range intervals {
+			wg.Add(1)
+			// Ticker 在启动 goroutine 之前创建，模拟时钟推进时不会错过第一次轮询
+			ticker := clock.OrReal(c.clock).NewTicker(interval)
+			go func(src poller, interval time.Duration, symbols []string) {
+				defer wg.Done()
+				defer ticker.Stop()
 
-						select {
-						case out <- *data:
-						default:
-							c.logger.Error("channel full, dropping market data", "source", src.Name(), "symbol", symbol)
+				timeframe := data.FormatInterval(interval)
+
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C():
+						for _, symbol := range symbols {
+							marketData, err := src.collect(ctx, symbol)
+							if err != nil {
+								c.logger.Error("failed to collect market data", "source", src.name, "symbol", symbol, "error", err)
+								continue
+							}
+							marketData.Timeframe = timeframe
+							if marketData.Source == "" {
+								marketData.Source = src.name
+							}
+
+							select {
+							case out <- *marketData:
+							default:
+								c.logger.Error("channel full, dropping market data", "source", src.name, "symbol", symbol, "timeframe", timeframe)
+							}
 						}
 					}
 				}
-			}
-		}(source)
+			}(src, interval, groups[interval])
+		}
 	}
 
 	// 等待所有goroutine结束后关闭channel
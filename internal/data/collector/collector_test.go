@@ -0,0 +1,77 @@
+package collector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// socialSource 只提供社交指标的数据源
+type socialSource struct {
+	name    string
+	metrics map[string]float64
+}
+
+func (s *socialSource) Name() string { return s.name }
+
+func (s *socialSource) CollectTokenInfo(ctx context.Context, symbol string) (*models.TokenInfo, error) {
+	return nil, ErrNotSupported
+}
+
+func (s *socialSource) CollectMarketData(ctx context.Context, symbol string) (*models.MarketData, error) {
+	return nil, ErrNotSupported
+}
+
+func (s *socialSource) CollectSocialMetrics(ctx context.Context, symbol string) (map[string]float64, error) {
+	return s.metrics, nil
+}
+
+func TestMultiSourceCollector_SourcePriority(t *testing.T) {
+	collector := NewMultiSourceCollector([]DataSource{
+		&socialSource{name: "twitter", metrics: map[string]float64{"twitter_followers": 100}},
+		&socialSource{name: "coingecko", metrics: map[string]float64{"twitter_followers": 90, "github_stars": 5}},
+		&priceSource{name: "binance", price: 42},
+	}, nopLogger{})
+
+	// 不提供行情的数据源被跳过
+	marketData, err := collector.CollectMarketData(context.Background(), "BTCUSDT")
+	require.NoError(t, err)
+	assert.Equal(t, 42.0, marketData.Price)
+
+	// 同名指标取排在前面的数据源
+	metrics, err := collector.CollectSocialMetrics(context.Background(), "BTCUSDT")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"twitter_followers": 100, "github_stars": 5}, metrics)
+}
+
+func TestRateLimited(t *testing.T) {
+	source := &priceSource{name: "binance", price: 42}
+	assert.Same(t, DataSource(source), RateLimited(source, 0))
+
+	// 每分钟 600 次：空闲后连续 600 次不等待，之后每 100ms 一次
+	limited := RateLimited(source, 600)
+	assert.Equal(t, "binance", limited.Name())
+	ctx := context.Background()
+	start := time.Now()
+	for i := 0; i < 600; i++ {
+		_, err := limited.CollectMarketData(ctx, "BTCUSDT")
+		require.NoError(t, err)
+	}
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+
+	start = time.Now()
+	_, err := limited.CollectMarketData(ctx, "BTCUSDT")
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 80*time.Millisecond)
+
+	// 等待期间取消
+	cancelled, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = limited.CollectSocialMetrics(cancelled, "BTCUSDT")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
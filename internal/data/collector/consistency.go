@@ -0,0 +1,96 @@
+package collector
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/models"
+)
+
+// Quote 单个数据源报告的行情
+type Quote struct {
+	Source string
+	Data   models.MarketData
+}
+
+// Divergence 数据源报告的价格偏离共识价格超过阈值，可能是数据源异常或单个交易所被拉盘
+type Divergence struct {
+	Symbol    string    `json:"symbol"`
+	Source    string    `json:"source"`
+	Price     float64   `json:"price"`
+	Consensus float64   `json:"consensus"`
+	Deviation float64   `json:"deviation"` // 相对共识价格的偏离比例
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ConsistencyChecker 比较多个数据源对同一交易对的报价，以中位数作为共识价格
+type ConsistencyChecker struct {
+	maxDeviation float64 // 偏离共识价格的比例上限，如 0.02 表示 2%
+
+	// OnDivergence 发现偏离时调用，可为空
+	OnDivergence func(Divergence)
+}
+
+func NewConsistencyChecker(maxDeviation float64) *ConsistencyChecker {
+	return &ConsistencyChecker{maxDeviation: maxDeviation}
+}
+
+// Consensus 返回共识行情和偏离共识价格超过阈值的数据源。
+// 价格取各数据源报价的中位数，其他字段取价格最接近中位数的数据源；quotes 为空时返回 nil
+func (c *ConsistencyChecker) Consensus(quotes []Quote) (*models.MarketData, []Divergence) {
+	if len(quotes) == 0 {
+		return nil, nil
+	}
+
+	prices := make([]float64, len(quotes))
+	for i, quote := range quotes {
+		prices[i] = quote.Data.Price
+	}
+	median := medianPrice(prices)
+
+	closest := quotes[0]
+	for _, quote := range quotes[1:] {
+		if math.Abs(quote.Data.Price-median) < math.Abs(closest.Data.Price-median) {
+			closest = quote
+		}
+	}
+	result := closest.Data
+	result.Price = median
+
+	if median <= 0 || c.maxDeviation <= 0 {
+		return &result, nil
+	}
+
+	var divergences []Divergence
+	for _, quote := range quotes {
+		deviation := math.Abs(quote.Data.Price-median) / median
+		if deviation <= c.maxDeviation {
+			continue
+		}
+		divergence := Divergence{
+			Symbol:    quote.Data.Symbol,
+			Source:    quote.Source,
+			Price:     quote.Data.Price,
+			Consensus: median,
+			Deviation: deviation,
+			Timestamp: quote.Data.Timestamp,
+		}
+		divergences = append(divergences, divergence)
+		if c.OnDivergence != nil {
+			c.OnDivergence(divergence)
+		}
+	}
+	return &result, divergences
+}
+
+// medianPrice 返回价格的中位数，数量为偶数时取中间两个的平均值
+func medianPrice(prices []float64) float64 {
+	sorted := slices.Clone(prices)
+	slices.Sort(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
@@ -0,0 +1,101 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/data"
+	"github.com/songzhibin97/quantaflux/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Error(msg string, fields ...interface{}) {}
+func (nopLogger) Info(msg string, fields ...interface{})  {}
+
+type priceSource struct {
+	name   string
+	price  float64
+	volume float64
+	err    error
+}
+
+func (p *priceSource) Name() string { return p.name }
+
+func (p *priceSource) CollectTokenInfo(ctx context.Context, symbol string) (*models.TokenInfo, error) {
+	return nil, errors.New("not supported")
+}
+
+func (p *priceSource) CollectMarketData(ctx context.Context, symbol string) (*models.MarketData, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &models.MarketData{Symbol: symbol, Price: p.price, Volume24h: p.volume, Timestamp: time.Now()}, nil
+}
+
+func (p *priceSource) CollectSocialMetrics(ctx context.Context, symbol string) (map[string]float64, error) {
+	return nil, nil
+}
+
+func TestConsistencyChecker_Consensus(t *testing.T) {
+	checker := NewConsistencyChecker(0.02)
+	var flagged []Divergence
+	checker.OnDivergence = func(d Divergence) { flagged = append(flagged, d) }
+
+	result, divergences := checker.Consensus([]Quote{
+		{Source: "a", Data: models.MarketData{Symbol: "PEPEUSDT", Price: 100, Volume24h: 1}},
+		{Source: "b", Data: models.MarketData{Symbol: "PEPEUSDT", Price: 130, Volume24h: 2}},
+		{Source: "c", Data: models.MarketData{Symbol: "PEPEUSDT", Price: 101, Volume24h: 3}},
+	})
+	require.NotNil(t, result)
+	assert.InDelta(t, 101, result.Price, 1e-9)
+	assert.InDelta(t, 3, result.Volume24h, 1e-9)
+	require.Len(t, divergences, 1)
+	assert.Equal(t, "b", divergences[0].Source)
+	assert.InDelta(t, 29.0/101, divergences[0].Deviation, 1e-9)
+	assert.Equal(t, divergences, flagged)
+
+	// 偶数个报价取中间两个的平均值
+	result, divergences = checker.Consensus([]Quote{
+		{Source: "a", Data: models.MarketData{Price: 100}},
+		{Source: "b", Data: models.MarketData{Price: 102}},
+	})
+	assert.InDelta(t, 101, result.Price, 1e-9)
+	assert.Empty(t, divergences)
+
+	result, _ = checker.Consensus(nil)
+	assert.Nil(t, result)
+}
+
+func TestMultiSourceCollector_CollectMarketDataConsensus(t *testing.T) {
+	ctx := context.Background()
+	sources := []DataSource{
+		&priceSource{name: "a", price: 100},
+		&priceSource{name: "b", price: 150},
+		&priceSource{name: "c", err: data.ErrSourceUnavailable},
+		&priceSource{name: "d", price: 102},
+	}
+	c := NewMultiSourceCollector(sources, nopLogger{})
+
+	// 未启用一致性检查时使用第一个可用数据源
+	marketData, err := c.CollectMarketData(ctx, "PEPEUSDT")
+	require.NoError(t, err)
+	assert.InDelta(t, 100, marketData.Price, 1e-9)
+	assert.Equal(t, "a", marketData.Source)
+
+	c.SetConsistencyChecker(NewConsistencyChecker(0.05))
+	marketData, err = c.CollectMarketData(ctx, "PEPEUSDT")
+	require.NoError(t, err)
+	assert.InDelta(t, 102, marketData.Price, 1e-9)
+	assert.Equal(t, SourceConsensus, marketData.Source)
+
+	c = NewMultiSourceCollector([]DataSource{sources[2], sources[2]}, nopLogger{})
+	c.SetConsistencyChecker(NewConsistencyChecker(0.05))
+	_, err = c.CollectMarketData(ctx, "PEPEUSDT")
+	assert.ErrorIs(t, err, data.ErrSourceUnavailable)
+}
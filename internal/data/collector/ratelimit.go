@@ -0,0 +1,83 @@
+package collector
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/models"
+)
+
+// rateLimitedSource 限制请求频率的数据源，令牌用完时等待，等待期间 ctx 取消则返回错误
+type rateLimitedSource struct {
+	DataSource
+
+	mu     sync.Mutex
+	rate   float64 // 每秒补充的令牌数
+	burst  float64 // 令牌桶容量
+	tokens float64 // 可用令牌，为负数时表示已预约的请求数
+	last   time.Time
+}
+
+// RateLimited 返回每分钟最多请求 perMinute 次的数据源，perMinute 不大于 0 时返回 source 本身。
+// 空闲后最多连续发出 perMinute 次请求，之后按平均间隔发出
+func RateLimited(source DataSource, perMinute int) DataSource {
+	if perMinute <= 0 {
+		return source
+	}
+	return &rateLimitedSource{
+		DataSource: source,
+		rate:       float64(perMinute) / 60,
+		burst:      float64(perMinute),
+		tokens:     float64(perMinute),
+		last:       time.Now(),
+	}
+}
+
+// wait 取一个令牌，没有可用令牌时预约并等待到令牌补充
+func (s *rateLimitedSource) wait(ctx context.Context) error {
+	s.mu.Lock()
+	now := time.Now()
+	s.tokens = min(s.burst, s.tokens+now.Sub(s.last).Seconds()*s.rate)
+	s.last = now
+	s.tokens--
+	delay := time.Duration(-s.tokens / s.rate * float64(time.Second))
+	s.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		// 归还预约的令牌
+		s.mu.Lock()
+		s.tokens++
+		s.mu.Unlock()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (s *rateLimitedSource) CollectTokenInfo(ctx context.Context, symbol string) (*models.TokenInfo, error) {
+	if err := s.wait(ctx); err != nil {
+		return nil, err
+	}
+	return s.DataSource.CollectTokenInfo(ctx, symbol)
+}
+
+func (s *rateLimitedSource) CollectMarketData(ctx context.Context, symbol string) (*models.MarketData, error) {
+	if err := s.wait(ctx); err != nil {
+		return nil, err
+	}
+	return s.DataSource.CollectMarketData(ctx, symbol)
+}
+
+func (s *rateLimitedSource) CollectSocialMetrics(ctx context.Context, symbol string) (map[string]float64, error) {
+	if err := s.wait(ctx); err != nil {
+		return nil, err
+	}
+	return s.DataSource.CollectSocialMetrics(ctx, symbol)
+}
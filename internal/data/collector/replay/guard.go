@@ -0,0 +1,116 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/data"
+	"github.com/songzhibin97/quantaflux/internal/models"
+)
+
+// checkClock 读取的数据时间晚于 context 中的模拟时钟（data.WithAsOf）时返回 data.ErrLookahead，
+// 未设置模拟时钟（如回放开始前加载数据）时不检查
+func checkClock(ctx context.Context, what string, t time.Time) error {
+	clock, ok := data.AsOf(ctx)
+	if !ok || !t.After(clock) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s at %s is after the simulated clock %s", data.ErrLookahead,
+		what, t.Format(time.RFC3339Nano), clock.Format(time.RFC3339Nano))
+}
+
+// GuardedStorage 包装回测使用的存储，禁止读取模拟时钟之后的数据，写入不受限制；
+// 策略状态保存在内存中，每次回测从空状态开始，不读写实盘的策略状态
+type GuardedStorage struct {
+	data.DataStorage
+	state *data.MemoryStrategyState
+}
+
+func NewGuardedStorage(storage data.DataStorage) *GuardedStorage {
+	return &GuardedStorage{DataStorage: storage, state: data.NewMemoryStrategyState()}
+}
+
+// GetStrategyState implements DataStorage interface
+func (g *GuardedStorage) GetStrategyState(ctx context.Context, namespace, key string) (*data.StrategyState, error) {
+	return g.state.GetStrategyState(ctx, namespace, key)
+}
+
+// SetStrategyState implements DataStorage interface
+func (g *GuardedStorage) SetStrategyState(ctx context.Context, state *data.StrategyState) error {
+	return g.state.SetStrategyState(ctx, state)
+}
+
+// GetHistoricalData implements DataStorage interface，查询范围或返回的行情晚于模拟时钟时报错
+func (g *GuardedStorage) GetHistoricalData(ctx context.Context, symbol string, start, end time.Time) ([]models.MarketData, error) {
+	if err := checkClock(ctx, "history query end of "+symbol, end); err != nil {
+		return nil, err
+	}
+	history, err := g.DataStorage.GetHistoricalData(ctx, symbol, start, end)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range history {
+		if err := checkClock(ctx, "market data of "+symbol, d.Timestamp); err != nil {
+			return nil, err
+		}
+	}
+	return history, nil
+}
+
+// GetProjectMetrics implements DataStorage interface，指标的更新时间晚于模拟时钟时报错
+func (g *GuardedStorage) GetProjectMetrics(ctx context.Context, symbol string) (*models.ProjectMetrics, error) {
+	metrics, err := g.DataStorage.GetProjectMetrics(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkClock(ctx, "project metrics of "+symbol, metrics.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return metrics, nil
+}
+
+// GuardedSocialStore 包装社交指标快照存储，禁止按模拟时钟之后的时间查询
+type GuardedSocialStore struct {
+	data.SocialStore
+}
+
+func NewGuardedSocialStore(store data.SocialStore) *GuardedSocialStore {
+	return &GuardedSocialStore{SocialStore: store}
+}
+
+// GetSocialSnapshotAsOf implements SocialStore interface
+func (g *GuardedSocialStore) GetSocialSnapshotAsOf(ctx context.Context, symbol string, at time.Time) (*models.SocialSnapshot, error) {
+	if err := checkClock(ctx, "social snapshot query of "+symbol, at); err != nil {
+		return nil, err
+	}
+	snapshot, err := g.SocialStore.GetSocialSnapshotAsOf(ctx, symbol, at)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkClock(ctx, "social snapshot of "+symbol, snapshot.Timestamp); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// GuardedCollector 包装回测使用的数据源，返回的行情晚于模拟时钟时报错
+type GuardedCollector struct {
+	data.DataCollector
+}
+
+func NewGuardedCollector(collector data.DataCollector) *GuardedCollector {
+	return &GuardedCollector{DataCollector: collector}
+}
+
+// CollectMarketData implements DataCollector interface
+func (g *GuardedCollector) CollectMarketData(ctx context.Context, symbol string) (*models.MarketData, error) {
+	d, err := g.DataCollector.CollectMarketData(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkClock(ctx, "market data of "+symbol, d.Timestamp); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
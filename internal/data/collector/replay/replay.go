@@ -0,0 +1,155 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/data"
+	"github.com/songzhibin97/quantaflux/internal/models"
+)
+
+// ReplayCollector implements DataCollector interface by replaying historical market data from storage
+type ReplayCollector struct {
+	storage data.DataStorage
+	start   time.Time
+	end     time.Time
+
+	social       data.SocialStore // 历史社交指标快照，为空时社交指标为空
+	socialMaxAge time.Duration    // 早于行情时间超过该时长的快照视为缺失，0 表示不限制
+}
+
+func NewReplayCollector(storage data.DataStorage, start, end time.Time) *ReplayCollector {
+	return &ReplayCollector{
+		storage: storage,
+		start:   start,
+		end:     end,
+	}
+}
+
+// SetSocialStore 设置历史社交指标快照的来源，maxAge 为 0 时不限制快照的时效
+func (c *ReplayCollector) SetSocialStore(store data.SocialStore, maxAge time.Duration) {
+	c.social = store
+	c.socialMaxAge = maxAge
+}
+
+// CollectTokenInfo implements DataCollector interface
+func (c *ReplayCollector) CollectTokenInfo(ctx context.Context, symbol string) (*models.TokenInfo, error) {
+	// 回放模式下不访问外部数据源，仅返回交易对本身
+	return &models.TokenInfo{
+		Symbol: symbol,
+		Name:   symbol,
+	}, nil
+}
+
+// CollectMarketData implements DataCollector interface
+func (c *ReplayCollector) CollectMarketData(ctx context.Context, symbol string) (*models.MarketData, error) {
+	history, err := c.storage.GetHistoricalData(ctx, symbol, c.start, c.end)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(history) == 0 {
+		return nil, fmt.Errorf("no historical data for symbol: %s", symbol)
+	}
+
+	return &history[len(history)-1], nil
+}
+
+// CollectSocialMetrics implements DataCollector interface，按 data.WithAsOf 标记的行情时间返回当时的社交指标，
+// 未标记时间时无法判断哪些数据已经发生，返回空指标
+func (c *ReplayCollector) CollectSocialMetrics(ctx context.Context, symbol string) (map[string]float64, error) {
+	at, ok := data.AsOf(ctx)
+	if !ok {
+		return map[string]float64{}, nil
+	}
+	return c.SocialMetricsAsOf(ctx, symbol, at)
+}
+
+// SocialMetricsAsOf 返回 at 时刻及之前最新的社交指标快照，没有快照或快照已过期时返回空指标
+func (c *ReplayCollector) SocialMetricsAsOf(ctx context.Context, symbol string, at time.Time) (map[string]float64, error) {
+	if c.social == nil {
+		return map[string]float64{}, nil
+	}
+
+	snapshot, err := c.social.GetSocialSnapshotAsOf(ctx, symbol, at)
+	if errors.Is(err, data.ErrNotFound) {
+		return map[string]float64{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if c.socialMaxAge > 0 && at.Sub(snapshot.Timestamp) > c.socialMaxAge {
+		return map[string]float64{}, nil
+	}
+	return snapshot.Metrics, nil
+}
+
+// SubscribeToMarketData implements DataCollector interface; the channel is closed once all data is replayed.
+// 每个交易对的第一个订阅回放全部历史行情，其他周期的订阅回放每个周期内的第一条行情
+func (c *ReplayCollector) SubscribeToMarketData(ctx context.Context, subscriptions []data.Subscription) (<-chan models.MarketData, error) {
+	var symbols []string
+	bySymbol := make(map[string][]data.Subscription)
+	for _, sub := range subscriptions {
+		if _, ok := bySymbol[sub.Symbol]; !ok {
+			symbols = append(symbols, sub.Symbol)
+		}
+		bySymbol[sub.Symbol] = append(bySymbol[sub.Symbol], sub)
+	}
+
+	var all []models.MarketData
+	for _, symbol := range symbols {
+		history, err := c.storage.GetHistoricalData(ctx, symbol, c.start, c.end)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load historical data for %s: %w", symbol, err)
+		}
+
+		// 其他周期的采样排在同一时间的行情之前
+		subs := bySymbol[symbol]
+		for _, sub := range subs[1:] {
+			all = append(all, sample(history, sub)...)
+		}
+		for _, d := range history {
+			d.Timeframe = subs[0].Timeframe()
+			all = append(all, d)
+		}
+	}
+
+	// 多个交易对按时间顺序合并回放
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].Timestamp.Before(all[j].Timestamp)
+	})
+
+	out := make(chan models.MarketData)
+	go func() {
+		defer close(out)
+
+		for _, d := range all {
+			select {
+			case <-ctx.Done():
+				return
+			case out <- d:
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// sample 按订阅周期取每个周期内的第一条行情
+func sample(history []models.MarketData, sub data.Subscription) []models.MarketData {
+	var result []models.MarketData
+	var bucket time.Time
+	for i, d := range history {
+		start := d.Timestamp.Truncate(sub.Interval)
+		if i > 0 && start.Equal(bucket) {
+			continue
+		}
+		bucket = start
+		d.Timeframe = sub.Timeframe()
+		result = append(result, d)
+	}
+	return result
+}
@@ -0,0 +1,154 @@
+package replay
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/data"
+	"github.com/songzhibin97/quantaflux/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryStorage struct {
+	data.DataStorage
+	history map[string][]models.MarketData
+}
+
+func (m *memoryStorage) GetHistoricalData(ctx context.Context, symbol string, start, end time.Time) ([]models.MarketData, error) {
+	return m.history[symbol], nil
+}
+
+func TestReplayCollector_SubscribeToMarketData(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var history []models.MarketData
+	for i := 0; i < 6; i++ {
+		history = append(history, models.MarketData{Symbol: "BTCUSDT", Price: float64(100 + i), Timestamp: start.Add(time.Duration(i) * 30 * time.Minute)})
+	}
+	storage := &memoryStorage{history: map[string][]models.MarketData{"BTCUSDT": history}}
+
+	collector := NewReplayCollector(storage, start, start.Add(3*time.Hour))
+	ch, err := collector.SubscribeToMarketData(context.Background(), []data.Subscription{
+		{Symbol: "BTCUSDT", Interval: time.Minute},
+		{Symbol: "BTCUSDT", Interval: time.Hour},
+	})
+	require.NoError(t, err)
+
+	var replayed []string
+	for d := range ch {
+		replayed = append(replayed, d.Timeframe+"@"+d.Timestamp.Format("15:04"))
+	}
+
+	// 1m 订阅回放全部行情，1h 订阅每小时回放第一条，排在同一时间的行情之前
+	assert.Equal(t, []string{
+		"1h@00:00", "1m@00:00", "1m@00:30",
+		"1h@01:00", "1m@01:00", "1m@01:30",
+		"1h@02:00", "1m@02:00", "1m@02:30",
+	}, replayed)
+}
+
+type memorySocialStore struct {
+	snapshots []models.SocialSnapshot
+}
+
+func (m *memorySocialStore) SaveSocialSnapshot(ctx context.Context, snapshot *models.SocialSnapshot) error {
+	m.snapshots = append(m.snapshots, *snapshot)
+	return nil
+}
+
+func (m *memorySocialStore) GetSocialSnapshotAsOf(ctx context.Context, symbol string, at time.Time) (*models.SocialSnapshot, error) {
+	var latest *models.SocialSnapshot
+	for i, s := range m.snapshots {
+		if s.Symbol == symbol && !s.Timestamp.After(at) {
+			latest = &m.snapshots[i]
+		}
+	}
+	if latest == nil {
+		return nil, data.ErrNotFound
+	}
+	return latest, nil
+}
+
+func TestReplayCollector_CollectSocialMetrics(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	social := &memorySocialStore{snapshots: []models.SocialSnapshot{
+		{Symbol: "BTCUSDT", Metrics: map[string]float64{"mentions": 10}, Timestamp: start},
+		{Symbol: "BTCUSDT", Metrics: map[string]float64{"mentions": 50}, Timestamp: start.Add(2 * time.Hour)},
+	}}
+	collector := NewReplayCollector(&memoryStorage{}, start, start.Add(24*time.Hour))
+	collector.SetSocialStore(social, 6*time.Hour)
+
+	// 未标记行情时间时不返回任何快照
+	metrics, err := collector.CollectSocialMetrics(context.Background(), "BTCUSDT")
+	require.NoError(t, err)
+	assert.Empty(t, metrics)
+
+	// 只使用行情时间及之前的快照
+	metrics, err = collector.CollectSocialMetrics(data.WithAsOf(context.Background(), start.Add(time.Hour)), "BTCUSDT")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"mentions": 10}, metrics)
+
+	metrics, err = collector.SocialMetricsAsOf(context.Background(), "BTCUSDT", start.Add(3*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"mentions": 50}, metrics)
+
+	// 快照过期或不存在时视为缺失
+	metrics, err = collector.SocialMetricsAsOf(context.Background(), "BTCUSDT", start.Add(9*time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, metrics)
+	metrics, err = collector.SocialMetricsAsOf(context.Background(), "ETHUSDT", start.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, metrics)
+}
+
+func TestGuardedStorage(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	history := []models.MarketData{
+		{Symbol: "BTCUSDT", Price: 100, Timestamp: start},
+		{Symbol: "BTCUSDT", Price: 101, Timestamp: start.Add(time.Hour)},
+	}
+	guarded := NewGuardedStorage(&memoryStorage{history: map[string][]models.MarketData{"BTCUSDT": history}})
+
+	// 未设置模拟时钟时不检查
+	result, err := guarded.GetHistoricalData(context.Background(), "BTCUSDT", start, start.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Len(t, result, 2)
+
+	ctx := data.WithAsOf(context.Background(), start.Add(30*time.Minute))
+	_, err = guarded.GetHistoricalData(ctx, "BTCUSDT", start, start.Add(time.Hour))
+	assert.ErrorIs(t, err, data.ErrLookahead)
+
+	// 查询范围未超过时钟，但存储返回了之后的行情
+	_, err = guarded.GetHistoricalData(ctx, "BTCUSDT", start, start.Add(30*time.Minute))
+	assert.ErrorIs(t, err, data.ErrLookahead)
+
+	ctx = data.WithAsOf(context.Background(), start.Add(time.Hour))
+	result, err = guarded.GetHistoricalData(ctx, "BTCUSDT", start, start.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Len(t, result, 2)
+}
+
+func TestGuardedCollector(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	history := []models.MarketData{
+		{Symbol: "BTCUSDT", Price: 100, Timestamp: start},
+		{Symbol: "BTCUSDT", Price: 101, Timestamp: start.Add(time.Hour)},
+	}
+	replay := NewReplayCollector(&memoryStorage{history: map[string][]models.MarketData{"BTCUSDT": history}}, start, start.Add(time.Hour))
+	social := &memorySocialStore{snapshots: []models.SocialSnapshot{{Symbol: "BTCUSDT", Metrics: map[string]float64{"mentions": 1}, Timestamp: start}}}
+	replay.SetSocialStore(NewGuardedSocialStore(social), 0)
+	collector := NewGuardedCollector(replay)
+
+	// 回放数据源返回区间内最新的行情，晚于模拟时钟
+	ctx := data.WithAsOf(context.Background(), start)
+	_, err := collector.CollectMarketData(ctx, "BTCUSDT")
+	assert.ErrorIs(t, err, data.ErrLookahead)
+
+	metrics, err := collector.CollectSocialMetrics(ctx, "BTCUSDT")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"mentions": 1}, metrics)
+	_, err = replay.SocialMetricsAsOf(ctx, "BTCUSDT", start.Add(time.Minute))
+	assert.ErrorIs(t, err, data.ErrLookahead)
+}
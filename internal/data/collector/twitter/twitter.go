@@ -0,0 +1,106 @@
+// Package twitter 通过 X(Twitter) API v2 获取项目账号的关注人数，只提供社交指标
+package twitter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+
+	"github.com/songzhibin97/quantaflux/internal/data"
+	"github.com/songzhibin97/quantaflux/internal/data/collector"
+	"github.com/songzhibin97/quantaflux/internal/models"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+	"github.com/songzhibin97/quantaflux/internal/utils/request"
+)
+
+// PublicURL 接口地址
+const PublicURL = "https://api.twitter.com"
+
+type TwitterDataSource struct {
+	baseURL     string
+	bearerToken string
+	accounts    map[string]string // 基础资产 -> 项目账号
+	httpClient  *resty.Client
+}
+
+// NewTwitterDataSource 创建数据源，baseURL 为空时使用公开地址；accounts 为基础资产到项目账号的映射，
+// 未配置账号的交易对没有推特指标
+func NewTwitterDataSource(baseURL, bearerToken string, accounts map[string]string) *TwitterDataSource {
+	if baseURL == "" {
+		baseURL = PublicURL
+	}
+	known := make(map[string]string, len(accounts))
+	for asset, account := range accounts {
+		known[strings.ToUpper(asset)] = strings.TrimPrefix(account, "@")
+	}
+	return &TwitterDataSource{
+		baseURL:     strings.TrimSuffix(baseURL, "/"),
+		bearerToken: bearerToken,
+		accounts:    known,
+		httpClient:  request.Request,
+	}
+}
+
+func (t *TwitterDataSource) Name() string {
+	return "twitter"
+}
+
+func (t *TwitterDataSource) CollectTokenInfo(ctx context.Context, symbol string) (*models.TokenInfo, error) {
+	return nil, collector.ErrNotSupported
+}
+
+func (t *TwitterDataSource) CollectMarketData(ctx context.Context, symbol string) (*models.MarketData, error) {
+	return nil, collector.ErrNotSupported
+}
+
+// CollectSocialMetrics 返回项目账号的关注人数和推文数
+func (t *TwitterDataSource) CollectSocialMetrics(ctx context.Context, symbol string) (map[string]float64, error) {
+	base, _, _ := trading.SplitSymbol(symbol)
+	account, ok := t.accounts[base]
+	if !ok {
+		return map[string]float64{}, nil
+	}
+
+	url := fmt.Sprintf("%s/2/users/by/username/%s", t.baseURL, account)
+	resp, err := t.httpClient.R().
+		SetContext(ctx).
+		SetAuthToken(t.bearerToken).
+		SetQueryParam("user.fields", "public_metrics").
+		Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to execute request: %w", data.ErrSourceUnavailable, err)
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return nil, fmt.Errorf("%w: unexpected status code: %d", data.ErrSourceUnavailable, resp.StatusCode())
+	}
+
+	var result struct {
+		Data *struct {
+			PublicMetrics struct {
+				FollowersCount float64 `json:"followers_count"`
+				TweetCount     float64 `json:"tweet_count"`
+			} `json:"public_metrics"`
+		} `json:"data"`
+		Errors []struct {
+			Detail string `json:"detail"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(resp.Body(), &result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if result.Data == nil {
+		if len(result.Errors) > 0 {
+			return nil, fmt.Errorf("twitter account %s: %s", account, result.Errors[0].Detail)
+		}
+		return nil, fmt.Errorf("twitter account %s not found", account)
+	}
+
+	return map[string]float64{
+		"twitter_followers": result.Data.PublicMetrics.FollowersCount,
+		"twitter_tweets":    result.Data.PublicMetrics.TweetCount,
+	}, nil
+}
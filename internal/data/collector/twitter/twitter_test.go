@@ -0,0 +1,60 @@
+package twitter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+
+	"github.com/songzhibin97/quantaflux/internal/data"
+	"github.com/songzhibin97/quantaflux/internal/data/collector"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTwitterDataSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		assert.Equal(t, "public_metrics", r.URL.Query().Get("user.fields"))
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/2/users/by/username/bitcoin":
+			_, _ = w.Write([]byte(`{"data":{"id":"1","username":"bitcoin","public_metrics":{"followers_count":7000000,"tweet_count":2500}}}`))
+		default:
+			_, _ = w.Write([]byte(`{"errors":[{"detail":"Could not find user"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	source := NewTwitterDataSource(server.URL, "token", map[string]string{"btc": "@bitcoin", "ETH": "missing"})
+	source.httpClient = resty.NewWithClient(server.Client())
+	ctx := context.Background()
+
+	metrics, err := source.CollectSocialMetrics(ctx, "BTCUSDT")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"twitter_followers": 7000000, "twitter_tweets": 2500}, metrics)
+
+	// 未配置账号的交易对没有指标
+	metrics, err = source.CollectSocialMetrics(ctx, "SOLUSDT")
+	require.NoError(t, err)
+	assert.Empty(t, metrics)
+
+	_, err = source.CollectSocialMetrics(ctx, "ETHUSDT")
+	assert.ErrorContains(t, err, "Could not find user")
+
+	_, err = source.CollectMarketData(ctx, "BTCUSDT")
+	assert.ErrorIs(t, err, collector.ErrNotSupported)
+	_, err = source.CollectTokenInfo(ctx, "BTCUSDT")
+	assert.ErrorIs(t, err, collector.ErrNotSupported)
+
+	unauthorized := NewTwitterDataSource(server.URL, "wrong", map[string]string{"BTC": "bitcoin"})
+	unauthorized.httpClient = resty.NewWithClient(server.Client())
+	_, err = unauthorized.CollectSocialMetrics(ctx, "BTCUSDT")
+	assert.ErrorIs(t, err, data.ErrSourceUnavailable)
+}
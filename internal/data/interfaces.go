@@ -2,11 +2,27 @@ package data
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/songzhibin97/quantaflux/internal/models"
 )
 
+var (
+	// ErrSymbolNotFound 交易对不存在
+	ErrSymbolNotFound = errors.New("symbol not found")
+	// ErrSourceUnavailable 数据源不可用
+	ErrSourceUnavailable = errors.New("data source unavailable")
+	// ErrNotFound 存储中没有对应记录
+	ErrNotFound = errors.New("record not found")
+	// ErrLookahead 回测中读取了模拟时钟之后的数据
+	ErrLookahead = errors.New("lookahead bias")
+	// ErrStateConflict 写入策略状态时版本与存储中的不一致，状态已被其他写入修改
+	ErrStateConflict = errors.New("strategy state version conflict")
+)
+
 // DataCollector 负责从各种源收集数据
 type DataCollector interface {
 	// CollectTokenInfo retrieves basic token information
@@ -18,8 +34,63 @@ type DataCollector interface {
 	// CollectSocialMetrics retrieves social media metrics
 	CollectSocialMetrics(ctx context.Context, symbol string) (map[string]float64, error)
 
-	// SubscribeToMarketData returns a channel for real-time market updates
-	SubscribeToMarketData(ctx context.Context, symbols []string, refreshInterval time.Duration) (<-chan models.MarketData, error)
+	// SubscribeToMarketData returns a channel for real-time market updates of the subscriptions,
+	// each update is tagged with the timeframe of the subscription that produced it
+	SubscribeToMarketData(ctx context.Context, subscriptions []Subscription) (<-chan models.MarketData, error)
+}
+
+// Subscription 一个交易对按指定周期的行情订阅，同一交易对可以同时订阅多个周期
+type Subscription struct {
+	Symbol   string
+	Interval time.Duration
+}
+
+// Timeframe 返回订阅周期的标签，如 30s、1m、1h
+func (s Subscription) Timeframe() string {
+	return FormatInterval(s.Interval)
+}
+
+// String 返回 BTCUSDT@1m 形式的订阅名称
+func (s Subscription) String() string {
+	return s.Symbol + "@" + s.Timeframe()
+}
+
+// FormatInterval 将周期格式化为最简的单位表示，如 1m 而不是 1m0s
+func FormatInterval(d time.Duration) string {
+	switch {
+	case d <= 0:
+		return ""
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	case d%time.Second == 0:
+		return fmt.Sprintf("%ds", d/time.Second)
+	default:
+		return d.String()
+	}
+}
+
+// SocialStore 保存原始社交指标快照，回测时按时间点查询，避免使用未来的数据
+type SocialStore interface {
+	// SaveSocialSnapshot stores a social metrics snapshot
+	SaveSocialSnapshot(ctx context.Context, snapshot *models.SocialSnapshot) error
+
+	// GetSocialSnapshotAsOf returns the latest snapshot taken at or before the given time, ErrNotFound if none
+	GetSocialSnapshotAsOf(ctx context.Context, symbol string, at time.Time) (*models.SocialSnapshot, error)
+}
+
+type asOfKey struct{}
+
+// WithAsOf 标记本次处理对应的数据时间，回测的数据源只返回该时间及之前的数据
+func WithAsOf(ctx context.Context, at time.Time) context.Context {
+	return context.WithValue(ctx, asOfKey{}, at)
+}
+
+// AsOf 返回 WithAsOf 标记的数据时间，未标记时返回 false
+func AsOf(ctx context.Context) (time.Time, bool) {
+	at, ok := ctx.Value(asOfKey{}).(time.Time)
+	return at, ok
 }
 
 // DataStorage 处理数据的持久化
@@ -35,4 +106,26 @@ type DataStorage interface {
 
 	// GetProjectMetrics retrieves project metrics
 	GetProjectMetrics(ctx context.Context, symbol string) (*models.ProjectMetrics, error)
+
+	StrategyStateStore
+}
+
+// StrategyStateStore 按命名空间保存策略状态（如网格价位、上次定投时间、配对持仓），值为 JSON，
+// 写入时按版本号做乐观并发控制
+type StrategyStateStore interface {
+	// GetStrategyState returns the state stored under the namespace and key, ErrNotFound if none
+	GetStrategyState(ctx context.Context, namespace, key string) (*StrategyState, error)
+
+	// SetStrategyState stores the state if the stored version equals state.Version (0 for a new key)
+	// and increments state.Version, ErrStateConflict otherwise
+	SetStrategyState(ctx context.Context, state *StrategyState) error
+}
+
+// StrategyState 一条策略状态
+type StrategyState struct {
+	Namespace string          `json:"namespace"` // 策略命名空间，如 pairs/main
+	Key       string          `json:"key"`
+	Value     json.RawMessage `json:"value"`
+	Version   int64           `json:"version"` // 每次写入加 1，0 表示尚未保存
+	UpdatedAt time.Time       `json:"updated_at"`
 }
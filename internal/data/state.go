@@ -0,0 +1,48 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// MemoryStrategyState 内存中的策略状态，回测使用，避免回测写入实盘的策略状态。可并发使用
+type MemoryStrategyState struct {
+	mu     sync.Mutex
+	states map[[2]string]StrategyState
+}
+
+func NewMemoryStrategyState() *MemoryStrategyState {
+	return &MemoryStrategyState{states: make(map[[2]string]StrategyState)}
+}
+
+// GetStrategyState implements StrategyStateStore interface
+func (m *MemoryStrategyState) GetStrategyState(ctx context.Context, namespace, key string) (*StrategyState, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, ok := m.states[[2]string{namespace, key}]
+	if !ok {
+		return nil, fmt.Errorf("%w: strategy state %s/%s", ErrNotFound, namespace, key)
+	}
+	state.Value = append([]byte(nil), state.Value...)
+	return &state, nil
+}
+
+// SetStrategyState implements StrategyStateStore interface
+func (m *MemoryStrategyState) SetStrategyState(ctx context.Context, state *StrategyState) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id := [2]string{state.Namespace, state.Key}
+	if m.states[id].Version != state.Version {
+		return fmt.Errorf("%w: %s/%s is at version %d, not %d", ErrStateConflict, state.Namespace, state.Key, m.states[id].Version, state.Version)
+	}
+	state.Version++
+	state.UpdatedAt = time.Now()
+	stored := *state
+	stored.Value = append([]byte(nil), state.Value...)
+	m.states[id] = stored
+	return nil
+}
@@ -0,0 +1,36 @@
+package data
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStrategyState(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStrategyState()
+
+	_, err := store.GetStrategyState(ctx, "grid/main", "BTCUSDT")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	state := &StrategyState{Namespace: "grid/main", Key: "BTCUSDT", Value: []byte(`{"levels":[1,2]}`)}
+	require.NoError(t, store.SetStrategyState(ctx, state))
+	assert.Equal(t, int64(1), state.Version)
+
+	// 另一个写入方基于旧版本写入时冲突
+	stale := &StrategyState{Namespace: "grid/main", Key: "BTCUSDT", Value: []byte(`{}`)}
+	assert.ErrorIs(t, store.SetStrategyState(ctx, stale), ErrStateConflict)
+
+	state.Value = []byte(`{"levels":[3]}`)
+	require.NoError(t, store.SetStrategyState(ctx, state))
+	loaded, err := store.GetStrategyState(ctx, "grid/main", "BTCUSDT")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), loaded.Version)
+	assert.JSONEq(t, `{"levels":[3]}`, string(loaded.Value))
+
+	// 命名空间互相隔离
+	_, err = store.GetStrategyState(ctx, "dca/main", "BTCUSDT")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/abtest"
+)
+
+// SaveDecision implements abtest.Store interface
+func (s *PostgresStorage) SaveDecision(ctx context.Context, d *abtest.Decision) error {
+	query := `
+        INSERT INTO ab_decisions (variant, model, mode, symbol, price, predicted_price, confidence, time_frame, side, tick_time, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+        RETURNING id
+    `
+
+	err := s.db.QueryRowContext(ctx, query,
+		d.Variant, d.Model, d.Mode, d.Symbol, d.Price, d.PredictedPrice, d.Confidence, d.TimeFrame, d.Side, d.TickTime, d.CreatedAt,
+	).Scan(&d.ID)
+	if err != nil {
+		return fmt.Errorf("failed to save analyzer decision: %w", err)
+	}
+	return nil
+}
+
+// ListDecisions implements abtest.Store interface
+func (s *PostgresStorage) ListDecisions(ctx context.Context, mode string, start, end time.Time) ([]abtest.Decision, error) {
+	query := `
+        SELECT id, variant, model, mode, symbol, price, predicted_price, confidence, time_frame, side, tick_time, created_at
+        FROM ab_decisions
+        WHERE mode = $1 AND tick_time BETWEEN $2 AND $3
+        ORDER BY tick_time ASC, id ASC
+    `
+
+	rows, err := s.reads.QueryContext(ctx, query, mode, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query analyzer decisions: %w", err)
+	}
+	defer rows.Close()
+
+	var result []abtest.Decision
+	for rows.Next() {
+		var d abtest.Decision
+		if err := rows.Scan(&d.ID, &d.Variant, &d.Model, &d.Mode, &d.Symbol, &d.Price, &d.PredictedPrice, &d.Confidence, &d.TimeFrame, &d.Side, &d.TickTime, &d.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan analyzer decision: %w", err)
+		}
+		result = append(result, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating analyzer decision rows: %w", err)
+	}
+
+	return result, nil
+}
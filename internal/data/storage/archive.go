@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/songzhibin97/quantaflux/internal/trading"
+)
+
+// archiveTable 可归档的表：按时间列划分，filter 排除仍在使用的行
+type archiveTable struct {
+	timeColumn string
+	filter     string
+	args       []any
+}
+
+// archiveTables 支持归档的表，未完成的订单不归档
+var archiveTables = map[string]archiveTable{
+	"market_data":   {timeColumn: "timestamp"},
+	"trade_journal": {timeColumn: "created_at", filter: "NOT (status = ANY($%d))", args: []any{pq.Array(trading.OpenOrderStatuses)}},
+	"audit_log":     {timeColumn: "created_at"},
+}
+
+// archiveWhere 返回按时间范围和过滤条件筛选的 WHERE 子句及参数，时间范围占用前两个参数
+func archiveWhere(table string, start, end time.Time, extra ...any) (archiveTable, string, []any, error) {
+	t, ok := archiveTables[table]
+	if !ok {
+		return t, "", nil, fmt.Errorf("table %s does not support archiving", table)
+	}
+	args := append([]any{start, end}, extra...)
+	where := fmt.Sprintf("%s >= $1 AND %s < $2", t.timeColumn, t.timeColumn)
+	if t.filter != "" {
+		where += " AND " + fmt.Sprintf(t.filter, len(args)+1)
+		args = append(args, t.args...)
+	}
+	return t, where, args, nil
+}
+
+// OldestArchivable 返回 table 中早于 before 的可归档行里最早的时间，没有时返回 false
+func (s *PostgresStorage) OldestArchivable(ctx context.Context, table string, before time.Time) (time.Time, bool, error) {
+	t, where, args, err := archiveWhere(table, time.Time{}, before)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	var oldest sql.NullTime
+	query := fmt.Sprintf(`SELECT MIN(%s) FROM %s WHERE %s`, t.timeColumn, table, where)
+	if err := s.db.QueryRowContext(ctx, query, args...).Scan(&oldest); err != nil {
+		return time.Time{}, false, fmt.Errorf("failed to query oldest %s row: %w", table, err)
+	}
+	return oldest.Time, oldest.Valid, nil
+}
+
+// ExportRows 将 table 中 [start, end) 的可归档行按 id 顺序逐行写为 JSON，返回行数和最大的 id
+func (s *PostgresStorage) ExportRows(ctx context.Context, table string, start, end time.Time, w io.Writer) (int, int64, error) {
+	_, where, args, err := archiveWhere(table, start, end)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	query := fmt.Sprintf(`SELECT id, row_to_json(t)::text FROM %s t WHERE %s ORDER BY id`, table, where)
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to export %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	var count int
+	var maxID int64
+	for rows.Next() {
+		var id int64
+		var line string
+		if err := rows.Scan(&id, &line); err != nil {
+			return count, maxID, fmt.Errorf("failed to scan %s row: %w", table, err)
+		}
+		if _, err := io.WriteString(w, line+"\n"); err != nil {
+			return count, maxID, err
+		}
+		count++
+		maxID = max(maxID, id)
+	}
+	if err := rows.Err(); err != nil {
+		return count, maxID, fmt.Errorf("failed to export %s: %w", table, err)
+	}
+	return count, maxID, nil
+}
+
+// DeleteRows 删除 table 中 [start, end) 且 id 不超过 maxID 的可归档行，导出后写入的行不会被删除
+func (s *PostgresStorage) DeleteRows(ctx context.Context, table string, start, end time.Time, maxID int64) (int64, error) {
+	_, where, args, err := archiveWhere(table, start, end, maxID)
+	if err != nil {
+		return 0, err
+	}
+
+	query := fmt.Sprintf(`DELETE FROM %s WHERE %s AND id <= $3`, table, where)
+	result, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete archived %s rows: %w", table, err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count deleted rows: %w", err)
+	}
+	return n, nil
+}
@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/songzhibin97/quantaflux/internal/audit"
+)
+
+// AppendAudit implements audit.Sink interface
+func (s *PostgresStorage) AppendAudit(ctx context.Context, entry *audit.Entry) error {
+	details, err := json.Marshal(entry.Details)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit details: %w", err)
+	}
+
+	query := `
+        INSERT INTO audit_log (actor, action, target, reason, details, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING id
+    `
+
+	err = s.db.QueryRowContext(ctx, query,
+		entry.Actor,
+		entry.Action,
+		entry.Target,
+		entry.Reason,
+		details,
+		entry.CreatedAt,
+	).Scan(&entry.ID)
+	if err != nil {
+		return fmt.Errorf("failed to append audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// ListAuditEntries implements audit.Storage interface
+func (s *PostgresStorage) ListAuditEntries(ctx context.Context, limit int) ([]audit.Entry, error) {
+	query := `
+        SELECT id, actor, action, target, reason, details, created_at
+        FROM audit_log
+        ORDER BY id DESC
+        LIMIT $1
+    `
+
+	rows, err := s.reads.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	var result []audit.Entry
+	for rows.Next() {
+		var entry audit.Entry
+		var details []byte
+		if err := rows.Scan(&entry.ID, &entry.Actor, &entry.Action, &entry.Target, &entry.Reason, &details, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		if err := json.Unmarshal(details, &entry.Details); err != nil {
+			return nil, fmt.Errorf("failed to parse audit details: %w", err)
+		}
+		result = append(result, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating audit log rows: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,156 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/songzhibin97/quantaflux/internal/models"
+)
+
+// SaveMarketDataBatch 使用 COPY 在一个事务内批量写入行情。先写入临时表再合并，
+// 同一交易对、数据源和时间的行情覆盖已有的一条，重复补齐同一区间不会产生重复行情
+func (s *PostgresStorage) SaveMarketDataBatch(ctx context.Context, data []models.MarketData) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	txn, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer txn.Rollback()
+
+	if _, err := txn.ExecContext(ctx, `CREATE TEMP TABLE market_data_batch (
+		symbol VARCHAR(50) NOT NULL,
+		source VARCHAR(50) NOT NULL,
+		price NUMERIC(18, 8),
+		volume_24h NUMERIC(18, 8),
+		market_cap NUMERIC(18, 8),
+		price_change_1h NUMERIC(10, 4),
+		price_change_24h NUMERIC(10, 4),
+		timestamp TIMESTAMP NOT NULL
+	) ON COMMIT DROP`); err != nil {
+		return fmt.Errorf("failed to create batch table: %w", err)
+	}
+
+	stmt, err := txn.PrepareContext(ctx, pq.CopyIn("market_data_batch",
+		"symbol", "source", "price", "volume_24h", "market_cap",
+		"price_change_1h", "price_change_24h", "timestamp",
+	))
+	if err != nil {
+		return fmt.Errorf("failed to prepare copy: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, d := range data {
+		if _, err := stmt.ExecContext(ctx,
+			d.Symbol,
+			d.Source,
+			d.Price,
+			d.Volume24h,
+			d.MarketCap,
+			d.PriceChange1h,
+			d.PriceChange24h,
+			d.Timestamp,
+		); err != nil {
+			return fmt.Errorf("failed to copy market data: %w", err)
+		}
+	}
+
+	// 无参数调用将缓冲的数据写入数据库
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return fmt.Errorf("failed to flush market data: %w", err)
+	}
+	if err := stmt.Close(); err != nil {
+		return fmt.Errorf("failed to close copy: %w", err)
+	}
+
+	// 批次内重复的行情只保留一条，否则同一条语句会两次更新同一行
+	if _, err := txn.ExecContext(ctx, `
+        INSERT INTO market_data (
+            symbol, source, price, volume_24h, market_cap,
+            price_change_1h, price_change_24h, timestamp
+        )
+        SELECT DISTINCT ON (symbol, source, timestamp)
+            symbol, source, price, volume_24h, market_cap,
+            price_change_1h, price_change_24h, timestamp
+        FROM market_data_batch
+        ORDER BY symbol, source, timestamp
+        ON CONFLICT (symbol, source, timestamp) DO UPDATE SET
+            price = EXCLUDED.price,
+            volume_24h = EXCLUDED.volume_24h,
+            market_cap = EXCLUDED.market_cap,
+            price_change_1h = EXCLUDED.price_change_1h,
+            price_change_24h = EXCLUDED.price_change_24h
+    `); err != nil {
+		return fmt.Errorf("failed to merge market data: %w", err)
+	}
+
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("failed to commit market data: %w", err)
+	}
+	return nil
+}
+
+// LatestMarketDataTime 返回交易对在 [start, end] 内来自 source 的最新行情时间，没有数据时返回零值
+func (s *PostgresStorage) LatestMarketDataTime(ctx context.Context, symbol, source string, start, end time.Time) (time.Time, error) {
+	var latest sql.NullTime
+	err := s.db.QueryRowContext(ctx,
+		`SELECT MAX(timestamp) FROM market_data WHERE symbol = $1 AND source = $2 AND timestamp BETWEEN $3 AND $4`,
+		symbol, source, start, end,
+	).Scan(&latest)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to query latest market data: %w", err)
+	}
+	return latest.Time, nil
+}
+
+// ListTokensWithoutMetrics 按交易对顺序返回 after 之后、最新项目指标早于 since 或没有项目指标的代币
+func (s *PostgresStorage) ListTokensWithoutMetrics(ctx context.Context, since time.Time, after string, limit int) ([]models.TokenInfo, error) {
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT t.symbol, COALESCE(t.name, ''), COALESCE(t.contract_address, ''),
+               COALESCE(t.network, ''), COALESCE(t.launch_type, ''), COALESCE(t.initial_price, 0),
+               COALESCE(t.total_supply, 0), COALESCE(t.circulating_supply, 0),
+               COALESCE(t.team_allocation, 0), COALESCE(t.vesting_schedule, '')
+        FROM token_info t
+        WHERE t.symbol > $2
+          AND NOT EXISTS (
+              SELECT 1 FROM project_metrics m
+              WHERE m.token_info_id = t.id AND m.updated_at >= $1
+          )
+        ORDER BY t.symbol
+        LIMIT $3
+    `, since, after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tokens without metrics: %w", err)
+	}
+	defer rows.Close()
+
+	var result []models.TokenInfo
+	for rows.Next() {
+		var info models.TokenInfo
+		if err := rows.Scan(
+			&info.Symbol,
+			&info.Name,
+			&info.ContractAddress,
+			&info.Network,
+			&info.LaunchType,
+			&info.InitialPrice,
+			&info.TotalSupply,
+			&info.CirculatingSupply,
+			&info.TeamAllocation,
+			&info.VestingSchedule,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan token info: %w", err)
+		}
+		result = append(result, info)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating token info rows: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/models"
+)
+
+// SaveEquitySnapshot implements EquityStorage interface
+func (s *PostgresStorage) SaveEquitySnapshot(ctx context.Context, snapshot *models.EquitySnapshot) error {
+	query := `
+        INSERT INTO equity_snapshots (equity, cash, exposure, timestamp, mode, account)
+        VALUES ($1, $2, $3, $4, $5, $6)
+    `
+
+	_, err := s.db.ExecContext(ctx, query,
+		snapshot.Equity,
+		snapshot.Cash,
+		snapshot.Exposure,
+		snapshot.Timestamp,
+		snapshot.Mode,
+		snapshot.Account,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save equity snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// GetEquitySnapshots implements EquityStorage interface
+func (s *PostgresStorage) GetEquitySnapshots(ctx context.Context, start, end time.Time) ([]models.EquitySnapshot, error) {
+	query := `
+        SELECT equity, cash, exposure, timestamp, mode, account
+        FROM equity_snapshots
+        WHERE timestamp BETWEEN $1 AND $2
+        ORDER BY timestamp ASC
+    `
+
+	rows, err := s.reads.QueryContext(ctx, query, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query equity snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	var result []models.EquitySnapshot
+	for rows.Next() {
+		var snapshot models.EquitySnapshot
+		if err := rows.Scan(&snapshot.Equity, &snapshot.Cash, &snapshot.Exposure, &snapshot.Timestamp, &snapshot.Mode, &snapshot.Account); err != nil {
+			return nil, fmt.Errorf("failed to scan equity snapshot: %w", err)
+		}
+		result = append(result, snapshot)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating equity snapshot rows: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,269 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/songzhibin97/quantaflux/internal/data"
+	"github.com/songzhibin97/quantaflux/internal/journal"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+)
+
+// RecordTrade implements TradeJournal interface
+func (s *PostgresStorage) RecordTrade(ctx context.Context, entry *journal.Entry) error {
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	marketData, err := json.Marshal(entry.MarketData)
+	if err != nil {
+		return fmt.Errorf("failed to marshal market data: %w", err)
+	}
+
+	prediction, err := json.Marshal(entry.Prediction)
+	if err != nil {
+		return fmt.Errorf("failed to marshal prediction: %w", err)
+	}
+
+	assessment, err := json.Marshal(entry.RiskAssessment)
+	if err != nil {
+		return fmt.Errorf("failed to marshal risk assessment: %w", err)
+	}
+
+	query := `
+        INSERT INTO trade_journal (
+            strategy, symbol, side, amount, price, order_type, status, order_id,
+            market_data, prediction, sentiment, scam_probability, risk_assessment, created_at, account, mode, filled_price, filled_amount, quote_amount, submitted_price,
+            client_order_id, fee, fee_asset, order_created_at, order_updated_at
+        ) VALUES (
+            $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25
+        )
+        RETURNING id
+    `
+
+	err = s.db.QueryRowContext(ctx, query,
+		entry.Strategy,
+		entry.Order.Symbol,
+		entry.Order.Side,
+		entry.Order.Amount,
+		entry.Order.Price,
+		entry.Order.OrderType,
+		entry.Order.Status,
+		entry.Order.OrderID,
+		marketData,
+		prediction,
+		entry.Sentiment,
+		entry.ScamProbability,
+		assessment,
+		entry.CreatedAt,
+		entry.Order.Account,
+		entry.Mode,
+		entry.Order.FilledPrice,
+		entry.Order.FilledAmount,
+		entry.Order.QuoteAmount,
+		entry.Order.SubmittedPrice,
+		entry.Order.ClientOrderID,
+		entry.Order.Fee,
+		entry.Order.FeeAsset,
+		nullTime(entry.Order.CreatedAt),
+		nullTime(entry.Order.UpdatedAt),
+	).Scan(&entry.ID)
+
+	if err != nil {
+		return fmt.Errorf("failed to record trade: %w", err)
+	}
+
+	return nil
+}
+
+// ListTrades implements TradeJournal interface
+func (s *PostgresStorage) ListTrades(ctx context.Context, symbol string, limit int) ([]journal.Entry, error) {
+	query := `
+        SELECT ` + journalColumns + `
+        FROM trade_journal
+        WHERE ($1 = '' OR symbol = $1)
+        ORDER BY created_at DESC
+        LIMIT $2
+    `
+
+	rows, err := s.reads.QueryContext(ctx, query, symbol, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trade journal: %w", err)
+	}
+
+	return scanJournalEntries(rows)
+}
+
+// ListTradesInRange implements TradeJournal interface
+func (s *PostgresStorage) ListTradesInRange(ctx context.Context, start, end time.Time) ([]journal.Entry, error) {
+	query := `
+        SELECT ` + journalColumns + `
+        FROM trade_journal
+        WHERE created_at BETWEEN $1 AND $2
+        ORDER BY created_at ASC
+    `
+
+	rows, err := s.reads.QueryContext(ctx, query, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trade journal: %w", err)
+	}
+
+	return scanJournalEntries(rows)
+}
+
+func scanJournalEntries(rows *sql.Rows) ([]journal.Entry, error) {
+	defer rows.Close()
+
+	var result []journal.Entry
+	for rows.Next() {
+		entry, err := scanJournalEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating trade journal rows: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetTrade implements TradeJournal interface
+func (s *PostgresStorage) GetTrade(ctx context.Context, account, symbol, orderID string) (*journal.Entry, error) {
+	query := `
+        SELECT ` + journalColumns + `
+        FROM trade_journal
+        WHERE order_id = $1 AND ($2 = '' OR account = $2) AND ($3 = '' OR symbol = $3)
+        ORDER BY created_at DESC
+        LIMIT 1
+    `
+
+	entry, err := scanJournalEntry(s.reads.QueryRowContext(ctx, query, orderID, account, symbol))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: no journal entry found for order: %s", data.ErrNotFound, orderID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// ListOpenTrades implements TradeJournal interface
+func (s *PostgresStorage) ListOpenTrades(ctx context.Context) ([]journal.Entry, error) {
+	query := `
+        SELECT ` + journalColumns + `
+        FROM trade_journal
+        WHERE status = ANY($1)
+        ORDER BY created_at ASC
+    `
+
+	rows, err := s.reads.QueryContext(ctx, query, pq.Array(trading.OpenOrderStatuses))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query open trades: %w", err)
+	}
+
+	return scanJournalEntries(rows)
+}
+
+// UpdateOrderStatus implements TradeJournal interface
+func (s *PostgresStorage) UpdateOrderStatus(ctx context.Context, account, symbol, orderID, status string) error {
+	query := `UPDATE trade_journal SET status = $1 WHERE account = $2 AND symbol = $3 AND order_id = $4`
+
+	if _, err := s.db.ExecContext(ctx, query, status, account, symbol, orderID); err != nil {
+		return fmt.Errorf("failed to update order status: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateOrderFill implements TradeJournal interface
+func (s *PostgresStorage) UpdateOrderFill(ctx context.Context, order trading.Order) error {
+	query := `
+        UPDATE trade_journal SET filled_amount = $1, filled_price = $2, fee = $3, fee_asset = $4, order_updated_at = $5
+        WHERE account = $6 AND symbol = $7 AND order_id = $8
+    `
+
+	if _, err := s.db.ExecContext(ctx, query, order.FilledAmount, order.FilledPrice, order.Fee, order.FeeAsset, nullTime(order.UpdatedAt),
+		order.Account, order.Symbol, order.OrderID); err != nil {
+		return fmt.Errorf("failed to update order fill: %w", err)
+	}
+
+	return nil
+}
+
+const journalColumns = `id, strategy, symbol, side, amount, price, order_type, status, order_id,
+               market_data, prediction, sentiment, scam_probability, risk_assessment, created_at, account, mode, filled_price, filled_amount, quote_amount, submitted_price,
+               client_order_id, fee, fee_asset, order_created_at, order_updated_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanJournalEntry(row rowScanner) (*journal.Entry, error) {
+	var entry journal.Entry
+	var marketData, prediction, assessment []byte
+	var orderCreated, orderUpdated sql.NullTime
+
+	err := row.Scan(
+		&entry.ID,
+		&entry.Strategy,
+		&entry.Order.Symbol,
+		&entry.Order.Side,
+		&entry.Order.Amount,
+		&entry.Order.Price,
+		&entry.Order.OrderType,
+		&entry.Order.Status,
+		&entry.Order.OrderID,
+		&marketData,
+		&prediction,
+		&entry.Sentiment,
+		&entry.ScamProbability,
+		&assessment,
+		&entry.CreatedAt,
+		&entry.Order.Account,
+		&entry.Mode,
+		&entry.Order.FilledPrice,
+		&entry.Order.FilledAmount,
+		&entry.Order.QuoteAmount,
+		&entry.Order.SubmittedPrice,
+		&entry.Order.ClientOrderID,
+		&entry.Order.Fee,
+		&entry.Order.FeeAsset,
+		&orderCreated,
+		&orderUpdated,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan journal entry: %w", err)
+	}
+
+	entry.Order.CreatedAt = orderCreated.Time
+	entry.Order.UpdatedAt = orderUpdated.Time
+
+	if err := json.Unmarshal(marketData, &entry.MarketData); err != nil {
+		return nil, fmt.Errorf("failed to parse market data: %w", err)
+	}
+	if err := json.Unmarshal(prediction, &entry.Prediction); err != nil {
+		return nil, fmt.Errorf("failed to parse prediction: %w", err)
+	}
+	if err := json.Unmarshal(assessment, &entry.RiskAssessment); err != nil {
+		return nil, fmt.Errorf("failed to parse risk assessment: %w", err)
+	}
+
+	return &entry, nil
+}
+
+// nullTime 零值时间保存为 NULL
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
@@ -0,0 +1,119 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/data"
+	"github.com/songzhibin97/quantaflux/internal/models"
+)
+
+// 镜像存储中的后端名称
+const (
+	BackendPrimary   = "primary"
+	BackendSecondary = "secondary"
+)
+
+// MirrorStorage 将行情和代币信息同时写入主存储和备用存储，两个后端并发写入、互不影响，
+// 任一后端写入成功即视为成功，两个都失败时才返回错误；读取优先使用主存储，失败时改读备用存储。
+// 策略状态依赖版本号控制并发，只保存在主存储
+type MirrorStorage struct {
+	primary   data.DataStorage
+	secondary data.DataStorage
+
+	// OnError 某个后端读写失败时调用，op 为失败的方法名，为空时忽略
+	OnError func(backend, op string, err error)
+}
+
+// NewMirrorStorage 创建写入 primary 和 secondary 的镜像存储
+func NewMirrorStorage(primary, secondary data.DataStorage) *MirrorStorage {
+	return &MirrorStorage{primary: primary, secondary: secondary}
+}
+
+// report 通知后端失败，记录不存在不算失败
+func (m *MirrorStorage) report(backend, op string, err error) {
+	if m.OnError != nil && !errors.Is(err, data.ErrNotFound) {
+		m.OnError(backend, op, err)
+	}
+}
+
+// write 并发写入两个后端，两个都失败时返回主存储的错误
+func (m *MirrorStorage) write(op string, fn func(data.DataStorage) error) error {
+	var primaryErr, secondaryErr error
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		secondaryErr = fn(m.secondary)
+	}()
+	primaryErr = fn(m.primary)
+	wg.Wait()
+
+	if primaryErr != nil {
+		m.report(BackendPrimary, op, primaryErr)
+	}
+	if secondaryErr != nil {
+		m.report(BackendSecondary, op, secondaryErr)
+	}
+	if primaryErr != nil && secondaryErr != nil {
+		return fmt.Errorf("%w (secondary: %v)", primaryErr, secondaryErr)
+	}
+	return nil
+}
+
+// SaveTokenInfo implements DataStorage interface
+func (m *MirrorStorage) SaveTokenInfo(ctx context.Context, info *models.TokenInfo) error {
+	return m.write("SaveTokenInfo", func(s data.DataStorage) error {
+		return s.SaveTokenInfo(ctx, info)
+	})
+}
+
+// SaveMarketData implements DataStorage interface
+func (m *MirrorStorage) SaveMarketData(ctx context.Context, marketData *models.MarketData) error {
+	return m.write("SaveMarketData", func(s data.DataStorage) error {
+		return s.SaveMarketData(ctx, marketData)
+	})
+}
+
+// GetHistoricalData implements DataStorage interface，主存储失败时读取备用存储
+func (m *MirrorStorage) GetHistoricalData(ctx context.Context, symbol string, start, end time.Time) ([]models.MarketData, error) {
+	history, err := m.primary.GetHistoricalData(ctx, symbol, start, end)
+	if err == nil || ctx.Err() != nil {
+		return history, err
+	}
+	m.report(BackendPrimary, "GetHistoricalData", err)
+	history, secondaryErr := m.secondary.GetHistoricalData(ctx, symbol, start, end)
+	if secondaryErr != nil {
+		m.report(BackendSecondary, "GetHistoricalData", secondaryErr)
+		return nil, err
+	}
+	return history, nil
+}
+
+// GetProjectMetrics implements DataStorage interface，主存储失败或没有记录时读取备用存储
+func (m *MirrorStorage) GetProjectMetrics(ctx context.Context, symbol string) (*models.ProjectMetrics, error) {
+	metrics, err := m.primary.GetProjectMetrics(ctx, symbol)
+	if err == nil || ctx.Err() != nil {
+		return metrics, err
+	}
+	m.report(BackendPrimary, "GetProjectMetrics", err)
+	metrics, secondaryErr := m.secondary.GetProjectMetrics(ctx, symbol)
+	if secondaryErr != nil {
+		m.report(BackendSecondary, "GetProjectMetrics", secondaryErr)
+		return nil, err
+	}
+	return metrics, nil
+}
+
+// GetStrategyState implements DataStorage interface，只读取主存储
+func (m *MirrorStorage) GetStrategyState(ctx context.Context, namespace, key string) (*data.StrategyState, error) {
+	return m.primary.GetStrategyState(ctx, namespace, key)
+}
+
+// SetStrategyState implements DataStorage interface，只写入主存储
+func (m *MirrorStorage) SetStrategyState(ctx context.Context, state *data.StrategyState) error {
+	return m.primary.SetStrategyState(ctx, state)
+}
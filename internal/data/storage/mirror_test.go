@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/songzhibin97/quantaflux/internal/data"
+	"github.com/songzhibin97/quantaflux/internal/models"
+)
+
+// memoryBackend 记录写入的行情，err 不为空时所有操作失败
+type memoryBackend struct {
+	*data.MemoryStrategyState
+
+	mu     sync.Mutex
+	err    error
+	market []models.MarketData
+}
+
+func (b *memoryBackend) SaveTokenInfo(ctx context.Context, info *models.TokenInfo) error {
+	return b.err
+}
+
+func (b *memoryBackend) SaveMarketData(ctx context.Context, d *models.MarketData) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.err != nil {
+		return b.err
+	}
+	b.market = append(b.market, *d)
+	return nil
+}
+
+func (b *memoryBackend) GetHistoricalData(ctx context.Context, symbol string, start, end time.Time) ([]models.MarketData, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.err != nil {
+		return nil, b.err
+	}
+	return append([]models.MarketData(nil), b.market...), nil
+}
+
+func (b *memoryBackend) GetProjectMetrics(ctx context.Context, symbol string) (*models.ProjectMetrics, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	return nil, data.ErrNotFound
+}
+
+func TestMirrorStorage(t *testing.T) {
+	ctx := context.Background()
+	primary := &memoryBackend{MemoryStrategyState: data.NewMemoryStrategyState()}
+	secondary := &memoryBackend{MemoryStrategyState: data.NewMemoryStrategyState()}
+	mirror := NewMirrorStorage(primary, secondary)
+	var failures []string
+	mirror.OnError = func(backend, op string, err error) {
+		failures = append(failures, backend+" "+op)
+	}
+
+	tick := &models.MarketData{Symbol: "BTCUSDT", Price: 50000}
+	require.NoError(t, mirror.SaveMarketData(ctx, tick))
+	assert.Len(t, primary.market, 1)
+	assert.Len(t, secondary.market, 1)
+
+	// 主存储故障时仍写入备用存储，读取改读备用存储
+	primary.err = errors.New("primary down")
+	require.NoError(t, mirror.SaveMarketData(ctx, tick))
+	assert.Len(t, secondary.market, 2)
+	history, err := mirror.GetHistoricalData(ctx, "BTCUSDT", time.Time{}, time.Now())
+	require.NoError(t, err)
+	assert.Len(t, history, 2)
+	assert.Equal(t, []string{"primary SaveMarketData", "primary GetHistoricalData"}, failures)
+
+	// 备用存储故障不影响主存储
+	primary.err, secondary.err = nil, errors.New("archive down")
+	require.NoError(t, mirror.SaveMarketData(ctx, tick))
+	assert.Len(t, primary.market, 2)
+
+	// 两个都失败时返回主存储的错误
+	primary.err = errors.New("primary down")
+	err = mirror.SaveTokenInfo(ctx, &models.TokenInfo{Symbol: "BTCUSDT"})
+	assert.ErrorIs(t, err, primary.err)
+
+	// 策略状态只写入主存储
+	require.NoError(t, mirror.SetStrategyState(ctx, &data.StrategyState{Namespace: "pairs/main", Key: "BTCUSDT-ETHUSDT", Value: []byte(`{}`)}))
+	_, err = primary.GetStrategyState(ctx, "pairs/main", "BTCUSDT-ETHUSDT")
+	require.NoError(t, err)
+	_, err = secondary.GetStrategyState(ctx, "pairs/main", "BTCUSDT-ETHUSDT")
+	assert.ErrorIs(t, err, data.ErrNotFound)
+
+	// 记录不存在不算失败
+	failures = nil
+	primary.err, secondary.err = nil, nil
+	_, err = mirror.GetProjectMetrics(ctx, "BTCUSDT")
+	assert.ErrorIs(t, err, data.ErrNotFound)
+	assert.Empty(t, failures)
+}
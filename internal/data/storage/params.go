@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/songzhibin97/quantaflux/internal/params"
+)
+
+// AppendParamChange implements params.Store interface
+func (s *PostgresStorage) AppendParamChange(ctx context.Context, change *params.Change) error {
+	query := `
+        INSERT INTO param_changes (name, value, previous, actor, reason, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING id
+    `
+
+	var value sql.NullFloat64
+	if change.Value != nil {
+		value = sql.NullFloat64{Float64: *change.Value, Valid: true}
+	}
+	err := s.db.QueryRowContext(ctx, query,
+		change.Name,
+		value,
+		change.Previous,
+		change.Actor,
+		change.Reason,
+		change.CreatedAt,
+	).Scan(&change.ID)
+	if err != nil {
+		return fmt.Errorf("failed to append parameter change: %w", err)
+	}
+
+	return nil
+}
+
+// LatestParamChanges implements params.Store interface
+func (s *PostgresStorage) LatestParamChanges(ctx context.Context) ([]params.Change, error) {
+	query := `
+        SELECT DISTINCT ON (name) id, name, value, previous, actor, reason, created_at
+        FROM param_changes
+        ORDER BY name, id DESC
+    `
+	return s.queryParamChanges(ctx, query)
+}
+
+// ListParamChanges implements params.Store interface
+func (s *PostgresStorage) ListParamChanges(ctx context.Context, name string, limit int) ([]params.Change, error) {
+	query := `
+        SELECT id, name, value, previous, actor, reason, created_at
+        FROM param_changes
+        WHERE ($1 = '' OR name = $1)
+        ORDER BY id DESC
+        LIMIT $2
+    `
+	return s.queryParamChanges(ctx, query, name, limit)
+}
+
+// queryParamChanges 参数修改记录从主库读取，修改后立即可见
+func (s *PostgresStorage) queryParamChanges(ctx context.Context, query string, args ...any) ([]params.Change, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query parameter changes: %w", err)
+	}
+	defer rows.Close()
+
+	var result []params.Change
+	for rows.Next() {
+		var change params.Change
+		var value sql.NullFloat64
+		if err := rows.Scan(&change.ID, &change.Name, &value, &change.Previous, &change.Actor, &change.Reason, &change.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan parameter change: %w", err)
+		}
+		if value.Valid {
+			change.Value = &value.Float64
+		}
+		result = append(result, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating parameter change rows: %w", err)
+	}
+
+	return result, nil
+}
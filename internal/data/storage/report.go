@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/songzhibin97/quantaflux/internal/analytics"
+)
+
+// SavePnLReport implements analytics.ReportStorage interface
+func (s *PostgresStorage) SavePnLReport(ctx context.Context, report *analytics.PnLReport) error {
+	value, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to marshal pnl report: %w", err)
+	}
+
+	query := `
+        INSERT INTO pnl_reports (period, start_time, end_time, report, created_at)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING id
+    `
+
+	err = s.db.QueryRowContext(ctx, query, report.Period, report.Start, report.End, value, report.CreatedAt).Scan(&report.ID)
+	if err != nil {
+		return fmt.Errorf("failed to save pnl report: %w", err)
+	}
+
+	return nil
+}
+
+// ListPnLReports implements analytics.ReportStorage interface
+func (s *PostgresStorage) ListPnLReports(ctx context.Context, period string, limit int) ([]analytics.PnLReport, error) {
+	query := `
+        SELECT id, report
+        FROM pnl_reports
+        WHERE ($1 = '' OR period = $1)
+        ORDER BY end_time DESC
+        LIMIT $2
+    `
+
+	rows, err := s.reads.QueryContext(ctx, query, period, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pnl reports: %w", err)
+	}
+	defer rows.Close()
+
+	var result []analytics.PnLReport
+	for rows.Next() {
+		var id int64
+		var value []byte
+		if err := rows.Scan(&id, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan pnl report: %w", err)
+		}
+
+		var report analytics.PnLReport
+		if err := json.Unmarshal(value, &report); err != nil {
+			return nil, fmt.Errorf("failed to parse pnl report: %w", err)
+		}
+		report.ID = id
+		result = append(result, report)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating pnl report rows: %w", err)
+	}
+
+	return result, nil
+}
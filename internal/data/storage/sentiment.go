@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/sentiment"
+)
+
+// SaveSentiment implements sentiment.Store interface
+func (s *PostgresStorage) SaveSentiment(ctx context.Context, score *sentiment.Score) error {
+	query := `
+        INSERT INTO sentiment_scores (symbol, score, price, timestamp)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id
+    `
+
+	err := s.db.QueryRowContext(ctx, query, score.Symbol, score.Score, score.Price, score.Timestamp).Scan(&score.ID)
+	if err != nil {
+		return fmt.Errorf("failed to save sentiment score: %w", err)
+	}
+	return nil
+}
+
+// ListSentiment implements sentiment.Store interface
+func (s *PostgresStorage) ListSentiment(ctx context.Context, symbol string, start, end time.Time) ([]sentiment.Score, error) {
+	query := `
+        SELECT id, symbol, score, price, timestamp
+        FROM sentiment_scores
+        WHERE symbol = $1 AND timestamp BETWEEN $2 AND $3
+        ORDER BY timestamp ASC, id ASC
+    `
+
+	rows, err := s.reads.QueryContext(ctx, query, symbol, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sentiment scores: %w", err)
+	}
+	defer rows.Close()
+
+	var result []sentiment.Score
+	for rows.Next() {
+		var score sentiment.Score
+		if err := rows.Scan(&score.ID, &score.Symbol, &score.Score, &score.Price, &score.Timestamp); err != nil {
+			return nil, fmt.Errorf("failed to scan sentiment score: %w", err)
+		}
+		result = append(result, score)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating sentiment score rows: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/data"
+	"github.com/songzhibin97/quantaflux/internal/models"
+)
+
+// SaveSocialSnapshot implements data.SocialStore interface
+func (s *PostgresStorage) SaveSocialSnapshot(ctx context.Context, snapshot *models.SocialSnapshot) error {
+	metrics, err := json.Marshal(snapshot.Metrics)
+	if err != nil {
+		return fmt.Errorf("failed to marshal social metrics: %w", err)
+	}
+	var components []byte
+	if snapshot.Components != nil {
+		if components, err = json.Marshal(snapshot.Components); err != nil {
+			return fmt.Errorf("failed to marshal social score components: %w", err)
+		}
+	}
+
+	query := `
+        INSERT INTO social_snapshots (symbol, metrics, score, components, timestamp)
+        VALUES ($1, $2, $3, $4, $5)
+    `
+	if _, err := s.db.ExecContext(ctx, query, snapshot.Symbol, metrics, snapshot.Score, components, snapshot.Timestamp); err != nil {
+		return fmt.Errorf("failed to save social snapshot: %w", err)
+	}
+	return nil
+}
+
+// GetSocialSnapshotAsOf implements data.SocialStore interface
+func (s *PostgresStorage) GetSocialSnapshotAsOf(ctx context.Context, symbol string, at time.Time) (*models.SocialSnapshot, error) {
+	query := `
+        SELECT symbol, metrics, score, components, timestamp
+        FROM social_snapshots
+        WHERE symbol = $1 AND timestamp <= $2
+        ORDER BY timestamp DESC, id DESC
+        LIMIT 1
+    `
+
+	var snapshot models.SocialSnapshot
+	var metrics, components []byte
+	err := s.reads.QueryRowContext(ctx, query, symbol, at).Scan(&snapshot.Symbol, &metrics, &snapshot.Score, &components, &snapshot.Timestamp)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: no social snapshot of %s before %s", data.ErrNotFound, symbol, at.Format(time.RFC3339))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query social snapshot: %w", err)
+	}
+	if err := json.Unmarshal(metrics, &snapshot.Metrics); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal social metrics: %w", err)
+	}
+	if components != nil {
+		if err := json.Unmarshal(components, &snapshot.Components); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal social score components: %w", err)
+		}
+	}
+	return &snapshot, nil
+}
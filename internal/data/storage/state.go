@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/songzhibin97/quantaflux/internal/state"
+)
+
+// loopStateKey system_state 表中主循环状态的键
+const loopStateKey = "trading_loop"
+
+// SaveLoopState implements state.Store interface
+func (s *PostgresStorage) SaveLoopState(ctx context.Context, loopState *state.LoopState) error {
+	value, err := json.Marshal(loopState)
+	if err != nil {
+		return fmt.Errorf("failed to marshal loop state: %w", err)
+	}
+
+	query := `
+        INSERT INTO system_state (key, value, updated_at)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (key) DO UPDATE SET
+            value = EXCLUDED.value,
+            updated_at = EXCLUDED.updated_at
+    `
+
+	if _, err := s.db.ExecContext(ctx, query, loopStateKey, value, loopState.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to save loop state: %w", err)
+	}
+
+	return nil
+}
+
+// LoadLoopState implements state.Store interface
+func (s *PostgresStorage) LoadLoopState(ctx context.Context) (*state.LoopState, error) {
+	var value []byte
+	err := s.db.QueryRowContext(ctx, `SELECT value FROM system_state WHERE key = $1`, loopStateKey).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load loop state: %w", err)
+	}
+
+	var loopState state.LoopState
+	if err := json.Unmarshal(value, &loopState); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal loop state: %w", err)
+	}
+
+	return &loopState, nil
+}
+
+// SaveSnapshot implements state.SnapshotStore interface
+func (s *PostgresStorage) SaveSnapshot(ctx context.Context, snapshot *state.Snapshot) error {
+	value, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+
+	query := `
+        INSERT INTO system_snapshots (label, mode, value, created_at)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id
+    `
+
+	if err := s.db.QueryRowContext(ctx, query, snapshot.Label, snapshot.Mode, value, snapshot.CreatedAt).Scan(&snapshot.ID); err != nil {
+		return fmt.Errorf("failed to save snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// LatestSnapshot implements state.SnapshotStore interface
+func (s *PostgresStorage) LatestSnapshot(ctx context.Context, mode string) (*state.Snapshot, error) {
+	var (
+		id    int64
+		value []byte
+	)
+	query := `SELECT id, value FROM system_snapshots WHERE mode = $1 ORDER BY created_at DESC, id DESC LIMIT 1`
+	err := s.db.QueryRowContext(ctx, query, mode).Scan(&id, &value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load snapshot: %w", err)
+	}
+
+	var snapshot state.Snapshot
+	if err := json.Unmarshal(value, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal snapshot: %w", err)
+	}
+	snapshot.ID = id
+
+	return &snapshot, nil
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/songzhibin97/quantaflux/internal/data"
 	"github.com/songzhibin97/quantaflux/internal/models"
 
 	_ "github.com/lib/pq"
@@ -14,6 +15,14 @@ import (
 
 type PostgresStorage struct {
 	db *sql.DB
+
+	// reads 执行查询的连接，默认为主库；Analytics 返回的存储在配置了只读副本时为副本
+	reads *sql.DB
+	// replica 只读副本，未配置时为空
+	replica *sql.DB
+
+	// 同一交易对和数据源时间相差不超过该时长的行情视为重复，只保留先写入的一条；为 0 时只合并时间相同的行情
+	dedupTolerance time.Duration
 }
 
 func NewPostgresStorage(connStr string) (*PostgresStorage, error) {
@@ -26,7 +35,7 @@ func NewPostgresStorage(connStr string) (*PostgresStorage, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	s := &PostgresStorage{db: db}
+	s := &PostgresStorage{db: db, reads: db}
 
 	err = s.initTables()
 	if err != nil {
@@ -36,6 +45,67 @@ func NewPostgresStorage(connStr string) (*PostgresStorage, error) {
 	return s, nil
 }
 
+// SetDedupTolerance 设置行情去重的时间容差，需在写入行情之前调用
+func (s *PostgresStorage) SetDedupTolerance(tolerance time.Duration) {
+	s.dedupTolerance = tolerance
+}
+
+// SetReadReplica 连接只读副本，之后 Analytics 返回的存储从副本查询；副本只读，不创建表
+func (s *PostgresStorage) SetReadReplica(connStr string) error {
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to read replica: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		return fmt.Errorf("failed to ping read replica: %w", err)
+	}
+	s.replica = db
+	return nil
+}
+
+// Analytics 返回用于回测加载、报表等大范围查询的存储：配置了只读副本时查询走副本，写入仍走主库；
+// 未配置副本时返回 s 本身。返回的存储与 s 共用连接，只需关闭 s
+func (s *PostgresStorage) Analytics() *PostgresStorage {
+	if s.replica == nil {
+		return s
+	}
+	analytics := *s
+	analytics.reads = s.replica
+	return &analytics
+}
+
+// Close closes the underlying database connections
+func (s *PostgresStorage) Close() error {
+	err := s.db.Close()
+	if s.replica != nil {
+		err = errors.Join(err, s.replica.Close())
+	}
+	return err
+}
+
+// Ping checks connectivity of the database queries are read from
+func (s *PostgresStorage) Ping(ctx context.Context) error {
+	if err := s.reads.PingContext(ctx); err != nil {
+		return fmt.Errorf("failed to ping database: %w", err)
+	}
+	return nil
+}
+
+// PingDatabase checks that connStr reaches the database without creating any tables
+func PingDatabase(ctx context.Context, connStr string) error {
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	if err := db.PingContext(ctx); err != nil {
+		return fmt.Errorf("failed to ping database: %w", err)
+	}
+	return nil
+}
+
 // SaveTokenInfo implements DataStorage interface
 func (s *PostgresStorage) SaveTokenInfo(ctx context.Context, info *models.TokenInfo) error {
 	query := `
@@ -80,25 +150,38 @@ func (s *PostgresStorage) SaveTokenInfo(ctx context.Context, info *models.TokenI
 	return nil
 }
 
-// SaveMarketData implements DataStorage interface
+// SaveMarketData implements DataStorage interface; 写入是幂等的：同一交易对、数据源和时间的行情覆盖已有的一条，
+// 与已有行情的时间相差不超过去重容差时不写入，进程快速重启后重复采集的行情不会保存两次
 func (s *PostgresStorage) SaveMarketData(ctx context.Context, data *models.MarketData) error {
 	query := `
         INSERT INTO market_data (
-            symbol, price, volume_24h, market_cap,
+            symbol, source, price, volume_24h, market_cap,
             price_change_1h, price_change_24h, timestamp
-        ) VALUES (
-            $1, $2, $3, $4, $5, $6, $7
         )
+        SELECT $1::VARCHAR, $2::VARCHAR, $3::NUMERIC, $4::NUMERIC, $5::NUMERIC, $6::NUMERIC, $7::NUMERIC, $8::TIMESTAMP
+        WHERE NOT EXISTS (
+            SELECT 1 FROM market_data
+            WHERE symbol = $1 AND source = $2 AND timestamp <> $8
+              AND timestamp BETWEEN $8 - make_interval(secs => $9) AND $8 + make_interval(secs => $9)
+        )
+        ON CONFLICT (symbol, source, timestamp) DO UPDATE SET
+            price = EXCLUDED.price,
+            volume_24h = EXCLUDED.volume_24h,
+            market_cap = EXCLUDED.market_cap,
+            price_change_1h = EXCLUDED.price_change_1h,
+            price_change_24h = EXCLUDED.price_change_24h
     `
 
 	_, err := s.db.ExecContext(ctx, query,
 		data.Symbol,
+		data.Source,
 		data.Price,
 		data.Volume24h,
 		data.MarketCap,
 		data.PriceChange1h,
 		data.PriceChange24h,
 		data.Timestamp,
+		s.dedupTolerance.Seconds(),
 	)
 
 	if err != nil {
@@ -111,14 +194,14 @@ func (s *PostgresStorage) SaveMarketData(ctx context.Context, data *models.Marke
 // GetHistoricalData implements DataStorage interface
 func (s *PostgresStorage) GetHistoricalData(ctx context.Context, symbol string, start, end time.Time) ([]models.MarketData, error) {
 	query := `
-        SELECT symbol, price, volume_24h, market_cap,
+        SELECT symbol, source, price, volume_24h, market_cap,
                price_change_1h, price_change_24h, timestamp
         FROM market_data
         WHERE symbol = $1 AND timestamp BETWEEN $2 AND $3
         ORDER BY timestamp ASC
     `
 
-	rows, err := s.db.QueryContext(ctx, query, symbol, start, end)
+	rows, err := s.reads.QueryContext(ctx, query, symbol, start, end)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query historical data: %w", err)
 	}
@@ -129,6 +212,7 @@ func (s *PostgresStorage) GetHistoricalData(ctx context.Context, symbol string,
 		var data models.MarketData
 		err := rows.Scan(
 			&data.Symbol,
+			&data.Source,
 			&data.Price,
 			&data.Volume24h,
 			&data.MarketCap,
@@ -167,13 +251,15 @@ func (s *PostgresStorage) GetProjectMetrics(ctx context.Context, symbol string)
 	var metrics models.ProjectMetrics
 	var tokenInfo models.TokenInfo
 
-	err := s.db.QueryRowContext(ctx, query, symbol).Scan(
-		&metrics.TokenInfo.Symbol,
+	var tokenInfoID int64
+	err := s.reads.QueryRowContext(ctx, query, symbol).Scan(
+		&tokenInfoID,
 		&metrics.SocialScore,
 		&metrics.DevelopmentScore,
 		&metrics.CommunityGrowth,
 		&metrics.MarketSentiment,
 		&metrics.RiskScore,
+		&metrics.UpdatedAt,
 		&tokenInfo.Symbol,
 		&tokenInfo.Name,
 		&tokenInfo.ContractAddress,
@@ -197,6 +283,57 @@ func (s *PostgresStorage) GetProjectMetrics(ctx context.Context, symbol string)
 	return &metrics, nil
 }
 
+// SaveProjectMetrics stores project metrics for a previously saved token
+func (s *PostgresStorage) SaveProjectMetrics(ctx context.Context, metrics *models.ProjectMetrics) error {
+	query := `
+        INSERT INTO project_metrics (
+            token_info_id, social_score, development_score,
+            community_growth, market_sentiment, risk_score, updated_at
+        )
+        SELECT id, $2, $3, $4, $5, $6, $7
+        FROM token_info
+        WHERE symbol = $1
+    `
+
+	updatedAt := metrics.UpdatedAt
+	if updatedAt.IsZero() {
+		updatedAt = time.Now()
+	}
+
+	result, err := s.db.ExecContext(ctx, query,
+		metrics.TokenInfo.Symbol,
+		metrics.SocialScore,
+		metrics.DevelopmentScore,
+		metrics.CommunityGrowth,
+		metrics.MarketSentiment,
+		metrics.RiskScore,
+		updatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save project metrics: %w", err)
+	}
+
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("%w: token info not found for symbol: %s", data.ErrNotFound, metrics.TokenInfo.Symbol)
+	}
+
+	return nil
+}
+
+// PruneMarketData deletes market data older than the given time
+func (s *PostgresStorage) PruneMarketData(ctx context.Context, before time.Time) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM market_data WHERE timestamp < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune market data: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count pruned rows: %w", err)
+	}
+	return n, nil
+}
+
 func (s *PostgresStorage) initTables() error {
 	queries := []string{
 		`CREATE TABLE IF NOT EXISTS token_info (
@@ -225,6 +362,17 @@ func (s *PostgresStorage) initTables() error {
 			price_change_24h NUMERIC(10, 4),
 			timestamp TIMESTAMP NOT NULL
 		)`,
+		`CREATE INDEX IF NOT EXISTS idx_market_data_symbol_timestamp ON market_data (symbol, timestamp)`,
+		`ALTER TABLE market_data ADD COLUMN IF NOT EXISTS source VARCHAR(50) NOT NULL DEFAULT ''`,
+		// 旧版本可能已保存重复的行情，创建唯一索引前删除，只保留最早写入的一条
+		`DO $$
+		BEGIN
+			IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_market_data_symbol_source_timestamp') THEN
+				DELETE FROM market_data a USING market_data b
+				WHERE a.symbol = b.symbol AND a.source = b.source AND a.timestamp = b.timestamp AND a.id > b.id;
+				CREATE UNIQUE INDEX idx_market_data_symbol_source_timestamp ON market_data (symbol, source, timestamp);
+			END IF;
+		END $$`,
 
 		`CREATE TABLE IF NOT EXISTS project_metrics (
 			id SERIAL PRIMARY KEY,
@@ -236,6 +384,157 @@ func (s *PostgresStorage) initTables() error {
 			risk_score NUMERIC(10, 4),
 			updated_at TIMESTAMP DEFAULT NOW()
 		)`,
+		`CREATE INDEX IF NOT EXISTS idx_project_metrics_token_updated ON project_metrics (token_info_id, updated_at DESC)`,
+
+		`CREATE TABLE IF NOT EXISTS trade_journal (
+			id SERIAL PRIMARY KEY,
+			strategy VARCHAR(100),
+			symbol VARCHAR(50) NOT NULL,
+			side VARCHAR(10),
+			amount NUMERIC(18, 8),
+			price NUMERIC(18, 8),
+			order_type VARCHAR(20),
+			status VARCHAR(20),
+			order_id VARCHAR(64),
+			market_data JSONB,
+			prediction JSONB,
+			sentiment NUMERIC(10, 4),
+			scam_probability NUMERIC(10, 4),
+			risk_assessment JSONB,
+			created_at TIMESTAMP NOT NULL,
+			account VARCHAR(100) NOT NULL DEFAULT '',
+			mode VARCHAR(20) NOT NULL DEFAULT '',
+			filled_price NUMERIC(18, 8) NOT NULL DEFAULT 0,
+			filled_amount NUMERIC(18, 8) NOT NULL DEFAULT 0
+		)`,
+		`ALTER TABLE trade_journal ADD COLUMN IF NOT EXISTS account VARCHAR(100) NOT NULL DEFAULT ''`,
+		`ALTER TABLE trade_journal ADD COLUMN IF NOT EXISTS mode VARCHAR(20) NOT NULL DEFAULT ''`,
+		`ALTER TABLE trade_journal ADD COLUMN IF NOT EXISTS filled_price NUMERIC(18, 8) NOT NULL DEFAULT 0`,
+		`ALTER TABLE trade_journal ADD COLUMN IF NOT EXISTS filled_amount NUMERIC(18, 8) NOT NULL DEFAULT 0`,
+		`ALTER TABLE trade_journal ADD COLUMN IF NOT EXISTS quote_amount NUMERIC(18, 8) NOT NULL DEFAULT 0`,
+		`ALTER TABLE trade_journal ADD COLUMN IF NOT EXISTS submitted_price NUMERIC(18, 8) NOT NULL DEFAULT 0`,
+		`ALTER TABLE trade_journal ADD COLUMN IF NOT EXISTS client_order_id VARCHAR(64) NOT NULL DEFAULT ''`,
+		`ALTER TABLE trade_journal ADD COLUMN IF NOT EXISTS fee NUMERIC(18, 8) NOT NULL DEFAULT 0`,
+		`ALTER TABLE trade_journal ADD COLUMN IF NOT EXISTS fee_asset VARCHAR(20) NOT NULL DEFAULT ''`,
+		`ALTER TABLE trade_journal ADD COLUMN IF NOT EXISTS order_created_at TIMESTAMP`,
+		`ALTER TABLE trade_journal ADD COLUMN IF NOT EXISTS order_updated_at TIMESTAMP`,
+
+		`CREATE INDEX IF NOT EXISTS idx_trade_journal_symbol_created ON trade_journal (symbol, created_at DESC)`,
+		// 未下单成功的影子订单没有订单号，不参与唯一约束
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_trade_journal_order ON trade_journal (account, symbol, order_id) WHERE order_id <> ''`,
+
+		`CREATE TABLE IF NOT EXISTS equity_snapshots (
+			id SERIAL PRIMARY KEY,
+			equity NUMERIC(24, 8),
+			cash NUMERIC(24, 8),
+			exposure NUMERIC(24, 8),
+			timestamp TIMESTAMP NOT NULL
+		)`,
+		`ALTER TABLE equity_snapshots ADD COLUMN IF NOT EXISTS mode VARCHAR(20) NOT NULL DEFAULT ''`,
+		// 多账户时每个账户单独保存快照，汇总快照的 account 为空
+		`ALTER TABLE equity_snapshots ADD COLUMN IF NOT EXISTS account VARCHAR(100) NOT NULL DEFAULT ''`,
+		`CREATE TABLE IF NOT EXISTS system_state (
+			key VARCHAR(100) PRIMARY KEY,
+			value JSONB NOT NULL,
+			updated_at TIMESTAMP NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS system_snapshots (
+			id BIGSERIAL PRIMARY KEY,
+			label VARCHAR(100) NOT NULL DEFAULT '',
+			mode VARCHAR(20) NOT NULL DEFAULT '',
+			value JSONB NOT NULL,
+			created_at TIMESTAMP NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_system_snapshots_mode_created ON system_snapshots (mode, created_at DESC)`,
+		`CREATE TABLE IF NOT EXISTS ab_decisions (
+			id BIGSERIAL PRIMARY KEY,
+			variant VARCHAR(20) NOT NULL,
+			model VARCHAR(100) NOT NULL DEFAULT '',
+			mode VARCHAR(20) NOT NULL DEFAULT '',
+			symbol VARCHAR(20) NOT NULL,
+			price DECIMAL NOT NULL,
+			predicted_price DECIMAL NOT NULL,
+			confidence DECIMAL NOT NULL,
+			time_frame VARCHAR(20) NOT NULL DEFAULT '',
+			side VARCHAR(10) NOT NULL DEFAULT '',
+			tick_time TIMESTAMP NOT NULL,
+			created_at TIMESTAMP NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_ab_decisions_mode_tick ON ab_decisions (mode, tick_time)`,
+		`CREATE TABLE IF NOT EXISTS sentiment_scores (
+			id BIGSERIAL PRIMARY KEY,
+			symbol VARCHAR(20) NOT NULL,
+			score DECIMAL NOT NULL,
+			price DECIMAL NOT NULL,
+			timestamp TIMESTAMP NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_sentiment_scores_symbol_timestamp ON sentiment_scores (symbol, timestamp)`,
+		`CREATE TABLE IF NOT EXISTS social_snapshots (
+			id BIGSERIAL PRIMARY KEY,
+			symbol VARCHAR(20) NOT NULL,
+			metrics JSONB NOT NULL,
+			timestamp TIMESTAMP NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_social_snapshots_symbol_timestamp ON social_snapshots (symbol, timestamp DESC)`,
+		`ALTER TABLE social_snapshots ADD COLUMN IF NOT EXISTS score DECIMAL NOT NULL DEFAULT 0`,
+		`ALTER TABLE social_snapshots ADD COLUMN IF NOT EXISTS components JSONB`,
+		`CREATE TABLE IF NOT EXISTS audit_log (
+			id BIGSERIAL PRIMARY KEY,
+			actor VARCHAR(20) NOT NULL,
+			action VARCHAR(50) NOT NULL,
+			target VARCHAR(100) NOT NULL DEFAULT '',
+			reason TEXT NOT NULL DEFAULT '',
+			details JSONB,
+			created_at TIMESTAMP NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS pnl_reports (
+			id BIGSERIAL PRIMARY KEY,
+			period VARCHAR(20) NOT NULL,
+			start_time TIMESTAMP NOT NULL,
+			end_time TIMESTAMP NOT NULL,
+			report JSONB NOT NULL,
+			created_at TIMESTAMP NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_pnl_reports_period_end ON pnl_reports (period, end_time DESC)`,
+		`CREATE TABLE IF NOT EXISTS symbol_universe (
+			symbol VARCHAR(50) PRIMARY KEY,
+			base_asset VARCHAR(20) NOT NULL DEFAULT '',
+			quote_asset VARCHAR(20) NOT NULL DEFAULT '',
+			status VARCHAR(20) NOT NULL DEFAULT '',
+			tick_size DECIMAL NOT NULL DEFAULT 0,
+			step_size DECIMAL NOT NULL DEFAULT 0,
+			tags TEXT[] NOT NULL DEFAULT '{}',
+			listed_at TIMESTAMP,
+			updated_at TIMESTAMP NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS strategy_state (
+			namespace VARCHAR(100) NOT NULL,
+			key VARCHAR(200) NOT NULL,
+			value JSONB NOT NULL,
+			version BIGINT NOT NULL,
+			updated_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (namespace, key)
+		)`,
+		// 参数注册表的修改记录，每个参数最近一次修改即为当前值，value 为空表示恢复配置文件中的值
+		`CREATE TABLE IF NOT EXISTS param_changes (
+			id BIGSERIAL PRIMARY KEY,
+			name VARCHAR(100) NOT NULL,
+			value DOUBLE PRECISION,
+			previous DOUBLE PRECISION NOT NULL,
+			actor VARCHAR(100) NOT NULL,
+			reason TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_param_changes_name_id ON param_changes (name, id DESC)`,
+		// 审计日志只允许追加，拒绝修改和删除
+		`CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+		BEGIN
+			RAISE EXCEPTION 'audit_log is append-only';
+		END;
+		$$ LANGUAGE plpgsql`,
+		`DROP TRIGGER IF EXISTS audit_log_append_only ON audit_log`,
+		`CREATE TRIGGER audit_log_append_only BEFORE UPDATE OR DELETE ON audit_log
+			FOR EACH ROW EXECUTE FUNCTION audit_log_append_only()`,
 	}
 
 	for _, query := range queries {
@@ -0,0 +1,453 @@
+package storage_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/songzhibin97/quantaflux/internal/data"
+	"github.com/songzhibin97/quantaflux/internal/data/storage"
+	"github.com/songzhibin97/quantaflux/internal/data/storage/storagetest"
+	"github.com/songzhibin97/quantaflux/internal/journal"
+	"github.com/songzhibin97/quantaflux/internal/models"
+	"github.com/songzhibin97/quantaflux/internal/params"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+)
+
+// 这些测试需要真实的 Postgres，见 storagetest 包的说明，没有可用的数据库时跳过
+func TestMain(m *testing.M) {
+	storagetest.Main(m)
+}
+
+var base = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func TestStorage_Migrations(t *testing.T) {
+	connStr := storagetest.ConnStr(t)
+
+	// 旧版本的交易日志表缺少后来增加的列
+	db, err := sql.Open("postgres", connStr)
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.Exec(`CREATE TABLE trade_journal (
+		id SERIAL PRIMARY KEY,
+		strategy VARCHAR(100),
+		symbol VARCHAR(50) NOT NULL,
+		side VARCHAR(10),
+		amount NUMERIC(18, 8),
+		price NUMERIC(18, 8),
+		order_type VARCHAR(20),
+		status VARCHAR(20),
+		order_id VARCHAR(64),
+		market_data JSONB,
+		prediction JSONB,
+		sentiment NUMERIC(10, 4),
+		scam_probability NUMERIC(10, 4),
+		risk_assessment JSONB,
+		created_at TIMESTAMP NOT NULL
+	)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO trade_journal (
+		strategy, symbol, side, amount, price, order_type, status, order_id,
+		market_data, prediction, sentiment, scam_probability, risk_assessment, created_at
+	) VALUES ('momentum', 'BTCUSDT', 'BUY', 1, 100, 'MARKET', 'FILLED', '1', '{}', 'null', 0, 0, 'null', $1)`, base)
+	require.NoError(t, err)
+
+	s, err := storage.NewPostgresStorage(connStr)
+	require.NoError(t, err)
+	defer s.Close()
+
+	entry, err := s.GetTrade(context.Background(), "", "BTCUSDT", "1")
+	require.NoError(t, err)
+	assert.Equal(t, "FILLED", entry.Order.Status)
+	assert.Empty(t, entry.Order.Account)
+	assert.Zero(t, entry.Order.Fee)
+
+	// 重复初始化不报错
+	again, err := storage.NewPostgresStorage(connStr)
+	require.NoError(t, err)
+	require.NoError(t, again.Close())
+}
+
+func TestStorage_MarketDataHistory(t *testing.T) {
+	s := storagetest.New(t)
+	ctx := context.Background()
+
+	// 乱序写入
+	for _, offset := range []int{3, 0, 2, 1} {
+		require.NoError(t, s.SaveMarketData(ctx, &models.MarketData{
+			Symbol:         "BTCUSDT",
+			Price:          100 + float64(offset),
+			Volume24h:      1000,
+			PriceChange24h: 1.5,
+			Timestamp:      base.Add(time.Duration(offset) * time.Minute),
+		}))
+	}
+	require.NoError(t, s.SaveMarketData(ctx, &models.MarketData{Symbol: "ETHUSDT", Price: 10, Timestamp: base}))
+
+	// 范围包含两端，按时间升序，只返回指定交易对
+	history, err := s.GetHistoricalData(ctx, "BTCUSDT", base.Add(time.Minute), base.Add(3*time.Minute))
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	for i, d := range history {
+		assert.Equal(t, "BTCUSDT", d.Symbol)
+		assert.Equal(t, 101+float64(i), d.Price)
+		assert.True(t, base.Add(time.Duration(i+1)*time.Minute).Equal(d.Timestamp), d.Timestamp)
+	}
+	assert.Equal(t, 1.5, history[0].PriceChange24h)
+
+	history, err = s.GetHistoricalData(ctx, "SOLUSDT", base, base.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, history)
+
+	pruned, err := s.PruneMarketData(ctx, base.Add(2*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), pruned)
+	history, err = s.GetHistoricalData(ctx, "BTCUSDT", base, base.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Len(t, history, 2)
+}
+
+func TestStorage_MarketDataDedup(t *testing.T) {
+	s := storagetest.New(t)
+	s.SetDedupTolerance(5 * time.Second)
+	ctx := context.Background()
+
+	// 同一时间的行情覆盖已有的一条，容差内的行情视为重复，其他数据源不受影响
+	require.NoError(t, s.SaveMarketData(ctx, &models.MarketData{Symbol: "BTCUSDT", Source: "binance", Price: 100, Timestamp: base}))
+	require.NoError(t, s.SaveMarketData(ctx, &models.MarketData{Symbol: "BTCUSDT", Source: "binance", Price: 101, Timestamp: base}))
+	require.NoError(t, s.SaveMarketData(ctx, &models.MarketData{Symbol: "BTCUSDT", Source: "binance", Price: 102, Timestamp: base.Add(3 * time.Second)}))
+	require.NoError(t, s.SaveMarketData(ctx, &models.MarketData{Symbol: "BTCUSDT", Source: "coingecko", Price: 103, Timestamp: base.Add(3 * time.Second)}))
+	require.NoError(t, s.SaveMarketData(ctx, &models.MarketData{Symbol: "BTCUSDT", Source: "binance", Price: 104, Timestamp: base.Add(time.Minute)}))
+
+	history, err := s.GetHistoricalData(ctx, "BTCUSDT", base, base.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	assert.Equal(t, "binance", history[0].Source)
+	assert.Equal(t, 101.0, history[0].Price)
+	assert.Equal(t, "coingecko", history[1].Source)
+	assert.Equal(t, 104.0, history[2].Price)
+
+	// 批量写入与已有行情和批次内的重复行情合并
+	require.NoError(t, s.SaveMarketDataBatch(ctx, []models.MarketData{
+		{Symbol: "BTCUSDT", Source: "binance", Price: 105, Timestamp: base.Add(time.Minute)},
+		{Symbol: "BTCUSDT", Source: "binance", Price: 106, Timestamp: base.Add(2 * time.Minute)},
+		{Symbol: "BTCUSDT", Source: "binance", Price: 106, Timestamp: base.Add(2 * time.Minute)},
+	}))
+	history, err = s.GetHistoricalData(ctx, "BTCUSDT", base, base.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, history, 4)
+	assert.Equal(t, 105.0, history[2].Price)
+	assert.Equal(t, 106.0, history[3].Price)
+
+	// 回填断点只看同一数据源的行情
+	latest, err := s.LatestMarketDataTime(ctx, "BTCUSDT", "backfill_1m0s", base, base.Add(time.Hour))
+	require.NoError(t, err)
+	assert.True(t, latest.IsZero())
+	require.NoError(t, s.SaveMarketDataBatch(ctx, []models.MarketData{{Symbol: "BTCUSDT", Source: "backfill_1m0s", Price: 107, Timestamp: base}}))
+	latest, err = s.LatestMarketDataTime(ctx, "BTCUSDT", "backfill_1m0s", base, base.Add(time.Hour))
+	require.NoError(t, err)
+	assert.True(t, latest.Equal(base))
+}
+
+func TestStorage_ReadReplica(t *testing.T) {
+	ctx := context.Background()
+	replicaConnStr := storagetest.ConnStr(t)
+	replica, err := storage.NewPostgresStorage(replicaConnStr)
+	require.NoError(t, err)
+	t.Cleanup(func() { replica.Close() })
+	require.NoError(t, replica.SaveMarketData(ctx, &models.MarketData{Symbol: "BTCUSDT", Source: "binance", Price: 100, Timestamp: base}))
+
+	s := storagetest.New(t)
+	assert.Same(t, s, s.Analytics())
+	require.NoError(t, s.SetReadReplica(replicaConnStr))
+	analytics := s.Analytics()
+
+	// 写入走主库，分析查询走副本
+	require.NoError(t, analytics.SaveMarketData(ctx, &models.MarketData{Symbol: "BTCUSDT", Source: "binance", Price: 200, Timestamp: base.Add(time.Minute)}))
+	history, err := s.GetHistoricalData(ctx, "BTCUSDT", base, base.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, 200.0, history[0].Price)
+
+	history, err = analytics.GetHistoricalData(ctx, "BTCUSDT", base, base.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, 100.0, history[0].Price)
+	assert.NoError(t, analytics.Ping(ctx))
+}
+
+func TestStorage_ProjectMetrics(t *testing.T) {
+	s := storagetest.New(t)
+	ctx := context.Background()
+
+	info := models.TokenInfo{Symbol: "NEWUSDT", Name: "New", Network: "ethereum", TotalSupply: 1000000}
+	require.NoError(t, s.SaveTokenInfo(ctx, &info))
+	// 重复保存时更新已有记录
+	info.Name = "New Token"
+	require.NoError(t, s.SaveTokenInfo(ctx, &info))
+
+	require.NoError(t, s.SaveProjectMetrics(ctx, &models.ProjectMetrics{TokenInfo: info, SocialScore: 0.4, UpdatedAt: base}))
+	require.NoError(t, s.SaveProjectMetrics(ctx, &models.ProjectMetrics{TokenInfo: info, SocialScore: 0.8, UpdatedAt: base.Add(time.Hour)}))
+
+	metrics, err := s.GetProjectMetrics(ctx, "NEWUSDT")
+	require.NoError(t, err)
+	assert.Equal(t, 0.8, metrics.SocialScore)
+	assert.Equal(t, "New Token", metrics.TokenInfo.Name)
+	assert.Equal(t, 1000000.0, metrics.TokenInfo.TotalSupply)
+
+	_, err = s.GetProjectMetrics(ctx, "MISSINGUSDT")
+	assert.Error(t, err)
+	err = s.SaveProjectMetrics(ctx, &models.ProjectMetrics{TokenInfo: models.TokenInfo{Symbol: "MISSINGUSDT"}})
+	assert.ErrorIs(t, err, data.ErrNotFound)
+}
+
+func TestStorage_SocialSnapshots(t *testing.T) {
+	s := storagetest.New(t)
+	ctx := context.Background()
+
+	// 导入的历史快照没有分数
+	require.NoError(t, s.SaveSocialSnapshot(ctx, &models.SocialSnapshot{
+		Symbol: "BTCUSDT", Metrics: map[string]float64{"twitter_followers": 100}, Timestamp: base,
+	}))
+	require.NoError(t, s.SaveSocialSnapshot(ctx, &models.SocialSnapshot{
+		Symbol:     "BTCUSDT",
+		Metrics:    map[string]float64{"twitter_followers": 200},
+		Score:      1.5,
+		Components: map[string]float64{"twitter_followers": 5},
+		Timestamp:  base.Add(time.Hour),
+	}))
+
+	snapshot, err := s.GetSocialSnapshotAsOf(ctx, "BTCUSDT", base.Add(30*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 100.0, snapshot.Metrics["twitter_followers"])
+	assert.Zero(t, snapshot.Score)
+	assert.Nil(t, snapshot.Components)
+
+	snapshot, err = s.GetSocialSnapshotAsOf(ctx, "BTCUSDT", base.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1.5, snapshot.Score)
+	assert.Equal(t, map[string]float64{"twitter_followers": 5}, snapshot.Components)
+
+	_, err = s.GetSocialSnapshotAsOf(ctx, "BTCUSDT", base.Add(-time.Hour))
+	assert.ErrorIs(t, err, data.ErrNotFound)
+}
+
+func TestStorage_Journal(t *testing.T) {
+	s := storagetest.New(t)
+	ctx := context.Background()
+
+	for i, symbol := range []string{"BTCUSDT", "ETHUSDT", "BTCUSDT"} {
+		entry := &journal.Entry{
+			Strategy: "momentum",
+			Mode:     "paper",
+			Order: trading.Order{
+				Symbol:  symbol,
+				Side:    "BUY",
+				Amount:  1,
+				Price:   100,
+				Status:  "NEW",
+				OrderID: fmt.Sprint(i + 1),
+				Account: "main",
+			},
+			CreatedAt: base.Add(time.Duration(i) * time.Minute),
+		}
+		require.NoError(t, s.RecordTrade(ctx, entry))
+		assert.NotZero(t, entry.ID)
+	}
+
+	// 最新的在前，可按交易对过滤
+	entries, err := s.ListTrades(ctx, "BTCUSDT", 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "3", entries[0].Order.OrderID)
+	entries, err = s.ListTrades(ctx, "", 1)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	// 同一账户和交易对的订单号唯一
+	err = s.RecordTrade(ctx, &journal.Entry{Order: trading.Order{Symbol: "BTCUSDT", OrderID: "1", Account: "main"}, CreatedAt: base})
+	assert.Error(t, err)
+
+	require.NoError(t, s.UpdateOrderStatus(ctx, "main", "BTCUSDT", "1", "FILLED"))
+	require.NoError(t, s.UpdateOrderFill(ctx, trading.Order{
+		Symbol: "BTCUSDT", OrderID: "1", Account: "main",
+		FilledAmount: 1, FilledPrice: 99.5, Fee: 0.1, FeeAsset: "USDT", UpdatedAt: base.Add(time.Hour),
+	}))
+	entry, err := s.GetTrade(ctx, "main", "BTCUSDT", "1")
+	require.NoError(t, err)
+	assert.Equal(t, "FILLED", entry.Order.Status)
+	assert.Equal(t, 99.5, entry.Order.FilledPrice)
+	assert.Equal(t, "USDT", entry.Order.FeeAsset)
+
+	open, err := s.ListOpenTrades(ctx)
+	require.NoError(t, err)
+	assert.Len(t, open, 2)
+
+	inRange, err := s.ListTradesInRange(ctx, base, base.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Len(t, inRange, 2)
+
+	_, err = s.GetTrade(ctx, "", "", "404")
+	assert.ErrorIs(t, err, data.ErrNotFound)
+}
+
+func TestStorage_Archive(t *testing.T) {
+	s := storagetest.New(t)
+	ctx := context.Background()
+
+	for i, status := range []string{"FILLED", "NEW", "CANCELED"} {
+		require.NoError(t, s.RecordTrade(ctx, &journal.Entry{
+			Order:     trading.Order{Symbol: "BTCUSDT", Side: "BUY", Amount: 1, Status: status, OrderID: fmt.Sprint(i + 1), Account: "main"},
+			CreatedAt: base.Add(time.Duration(i) * time.Minute),
+		}))
+	}
+
+	// 挂单中的订单不归档
+	oldest, ok, err := s.OldestArchivable(ctx, "trade_journal", base.Add(time.Hour))
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.True(t, base.Equal(oldest), oldest)
+
+	var buf bytes.Buffer
+	rows, maxID, err := s.ExportRows(ctx, "trade_journal", base, base.Add(time.Hour), &buf)
+	require.NoError(t, err)
+	assert.Equal(t, 2, rows)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	var row map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &row))
+	assert.Equal(t, "CANCELED", row["status"])
+
+	deleted, err := s.DeleteRows(ctx, "trade_journal", base, base.Add(time.Hour), maxID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+	open, err := s.ListOpenTrades(ctx)
+	require.NoError(t, err)
+	assert.Len(t, open, 1)
+	_, ok, err = s.OldestArchivable(ctx, "trade_journal", base.Add(time.Hour))
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, _, err = s.OldestArchivable(ctx, "system_state", base)
+	assert.Error(t, err)
+}
+
+func TestStorage_StrategyState(t *testing.T) {
+	s := storagetest.New(t)
+	ctx := context.Background()
+
+	_, err := s.GetStrategyState(ctx, "pairs/main", "BTCUSDT-ETHUSDT")
+	assert.ErrorIs(t, err, data.ErrNotFound)
+
+	state := &data.StrategyState{Namespace: "pairs/main", Key: "BTCUSDT-ETHUSDT", Value: []byte(`{"position":1}`)}
+	require.NoError(t, s.SetStrategyState(ctx, state))
+	assert.Equal(t, int64(1), state.Version)
+	assert.ErrorIs(t, s.SetStrategyState(ctx, &data.StrategyState{Namespace: "pairs/main", Key: "BTCUSDT-ETHUSDT", Value: []byte(`{}`)}), data.ErrStateConflict)
+
+	state.Value = []byte(`{"position":0}`)
+	require.NoError(t, s.SetStrategyState(ctx, state))
+	stale := *state
+	stale.Version = 1
+	assert.ErrorIs(t, s.SetStrategyState(ctx, &stale), data.ErrStateConflict)
+
+	loaded, err := s.GetStrategyState(ctx, "pairs/main", "BTCUSDT-ETHUSDT")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), loaded.Version)
+	assert.JSONEq(t, `{"position":0}`, string(loaded.Value))
+}
+
+func TestStorage_ParamChanges(t *testing.T) {
+	s := storagetest.New(t)
+	ctx := context.Background()
+
+	confidence, leverage := 0.8, 2.0
+	require.NoError(t, s.AppendParamChange(ctx, &params.Change{Name: "ai_config.min_confidence", Value: &confidence, Previous: 0.7, Actor: "ops", Reason: "too many losers", CreatedAt: base}))
+	require.NoError(t, s.AppendParamChange(ctx, &params.Change{Name: "risk_parameters.max_leverage", Value: &leverage, Previous: 1, Actor: "ops", CreatedAt: base}))
+	reset := &params.Change{Name: "ai_config.min_confidence", Previous: 0.8, Actor: "cli", CreatedAt: base.Add(time.Hour)}
+	require.NoError(t, s.AppendParamChange(ctx, reset))
+	assert.NotZero(t, reset.ID)
+
+	latest, err := s.LatestParamChanges(ctx)
+	require.NoError(t, err)
+	require.Len(t, latest, 2)
+	assert.Nil(t, latest[0].Value)
+	assert.Equal(t, 2.0, *latest[1].Value)
+
+	history, err := s.ListParamChanges(ctx, "ai_config.min_confidence", 10)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, "cli", history[0].Actor)
+	assert.Equal(t, "too many losers", history[1].Reason)
+	assert.Equal(t, 0.8, *history[1].Value)
+
+	history, err = s.ListParamChanges(ctx, "", 1)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, reset.ID, history[0].ID)
+}
+
+func TestStorage_ConcurrentWrites(t *testing.T) {
+	s := storagetest.New(t)
+	ctx := context.Background()
+
+	const writers, perWriter = 8, 25
+	var wg sync.WaitGroup
+	errs := make(chan error, writers*perWriter)
+	for w := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perWriter {
+				errs <- s.SaveMarketData(ctx, &models.MarketData{
+					Symbol:    "BTCUSDT",
+					Price:     float64(w*perWriter + i),
+					Timestamp: base.Add(time.Duration(w*perWriter+i) * time.Second),
+				})
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	history, err := s.GetHistoricalData(ctx, "BTCUSDT", base, base.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, history, writers*perWriter)
+	for i := 1; i < len(history); i++ {
+		assert.False(t, history[i].Timestamp.Before(history[i-1].Timestamp))
+	}
+
+	// 并发记录同一订单时只有一条成功
+	var recorded sync.WaitGroup
+	results := make(chan error, writers)
+	for range writers {
+		recorded.Add(1)
+		go func() {
+			defer recorded.Done()
+			results <- s.RecordTrade(ctx, &journal.Entry{
+				Order:     trading.Order{Symbol: "ETHUSDT", OrderID: "dup", Account: "main", Status: "NEW"},
+				CreatedAt: base,
+			})
+		}()
+	}
+	recorded.Wait()
+	close(results)
+	succeeded := 0
+	for err := range results {
+		if err == nil {
+			succeeded++
+		}
+	}
+	assert.Equal(t, 1, succeeded)
+}
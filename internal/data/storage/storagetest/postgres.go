@@ -0,0 +1,243 @@
+// Package storagetest 提供基于真实 Postgres 的存储集成测试夹具，供 storage 及下游包的测试使用。
+//
+// 设置 QUANTAFLUX_TEST_DB 时使用该数据库，否则通过 docker 启动一个临时的 Postgres 容器；
+// 两者都不可用或使用 go test -short 时跳过测试。每个测试使用独立的 schema，互不影响。
+package storagetest
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/data/storage"
+
+	_ "github.com/lib/pq"
+)
+
+const (
+	// EnvDatabase 已有测试库的连接串，设置后不启动容器；测试会在其中创建和删除 schema
+	EnvDatabase = "QUANTAFLUX_TEST_DB"
+	// EnvImage 启动容器使用的镜像，默认为 DefaultImage
+	EnvImage = "QUANTAFLUX_TEST_PG_IMAGE"
+	// DefaultImage 默认的 Postgres 镜像
+	DefaultImage = "postgres:16-alpine"
+
+	containerPassword = "quantaflux"
+	startupTimeout    = 60 * time.Second
+)
+
+// ErrUnavailable 没有可用的测试数据库
+var ErrUnavailable = errors.New("no test database available")
+
+// Postgres 一个测试用的 Postgres 实例
+type Postgres struct {
+	connStr   string
+	container string // 由夹具启动的容器 ID，使用已有数据库时为空
+}
+
+// StartPostgres 优先使用 QUANTAFLUX_TEST_DB，否则用 docker 启动容器并等待就绪；
+// 都不可用时返回 ErrUnavailable
+func StartPostgres(ctx context.Context) (*Postgres, error) {
+	if connStr := os.Getenv(EnvDatabase); connStr != "" {
+		if err := storage.PingDatabase(ctx, connStr); err != nil {
+			return nil, fmt.Errorf("%s is set but unreachable: %w", EnvDatabase, err)
+		}
+		return &Postgres{connStr: connStr}, nil
+	}
+
+	if _, err := exec.LookPath("docker"); err != nil {
+		return nil, fmt.Errorf("%w: set %s or install docker", ErrUnavailable, EnvDatabase)
+	}
+
+	image := os.Getenv(EnvImage)
+	if image == "" {
+		image = DefaultImage
+	}
+	// 只映射到本机的随机端口，--rm 使容器停止后自动删除
+	out, err := exec.CommandContext(ctx, "docker", "run", "-d", "--rm",
+		"-e", "POSTGRES_PASSWORD="+containerPassword,
+		"-p", "127.0.0.1::5432",
+		image).Output()
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to start postgres container: %v", ErrUnavailable, commandError(err))
+	}
+	p := &Postgres{container: strings.TrimSpace(string(out))}
+
+	if err := p.waitReady(ctx); err != nil {
+		_ = p.Stop()
+		return nil, err
+	}
+	return p, nil
+}
+
+// waitReady 查询容器映射的端口并等待数据库接受连接；
+// 镜像初始化时先启动只监听 unix socket 的临时实例，TCP 可连接即表示初始化完成
+func (p *Postgres) waitReady(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, startupTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, "docker", "port", p.container, "5432/tcp").Output()
+	if err != nil {
+		return fmt.Errorf("failed to inspect postgres container port: %v", commandError(err))
+	}
+	hostPort := strings.TrimSpace(strings.SplitN(string(out), "\n", 2)[0])
+	p.connStr = fmt.Sprintf("postgres://postgres:%s@%s/postgres?sslmode=disable", containerPassword, hostPort)
+
+	for {
+		pingCtx, pingCancel := context.WithTimeout(ctx, time.Second)
+		err := storage.PingDatabase(pingCtx, p.connStr)
+		pingCancel()
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("postgres container did not become ready: %w", err)
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+}
+
+// ConnStr 返回数据库的连接串
+func (p *Postgres) ConnStr() string {
+	return p.connStr
+}
+
+// Stop 停止由夹具启动的容器，使用已有数据库时不做任何事
+func (p *Postgres) Stop() error {
+	if p.container == "" {
+		return nil
+	}
+	if err := exec.Command("docker", "rm", "-f", p.container).Run(); err != nil {
+		return fmt.Errorf("failed to remove postgres container %s: %v", p.container, commandError(err))
+	}
+	return nil
+}
+
+// CreateSchema 创建一个随机命名的 schema，返回 search_path 指向该 schema 的连接串，
+// 以及删除该 schema 的清理函数
+func (p *Postgres) CreateSchema(ctx context.Context) (string, func() error, error) {
+	suffix := make([]byte, 6)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", nil, err
+	}
+	schema := "test_" + hex.EncodeToString(suffix)
+
+	db, err := sql.Open("postgres", p.connStr)
+	if err != nil {
+		return "", nil, err
+	}
+	if _, err := db.ExecContext(ctx, "CREATE SCHEMA "+schema); err != nil {
+		db.Close()
+		return "", nil, fmt.Errorf("failed to create schema %s: %w", schema, err)
+	}
+
+	drop := func() error {
+		defer db.Close()
+		if _, err := db.Exec("DROP SCHEMA " + schema + " CASCADE"); err != nil {
+			return fmt.Errorf("failed to drop schema %s: %w", schema, err)
+		}
+		return nil
+	}
+	return withSearchPath(p.connStr, schema), drop, nil
+}
+
+// withSearchPath 为 URL 或 key=value 形式的连接串设置 search_path
+func withSearchPath(connStr, schema string) string {
+	if u, err := url.Parse(connStr); err == nil && (u.Scheme == "postgres" || u.Scheme == "postgresql") {
+		q := u.Query()
+		q.Set("search_path", schema)
+		u.RawQuery = q.Encode()
+		return u.String()
+	}
+	return connStr + " search_path=" + schema
+}
+
+func commandError(err error) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return err
+}
+
+// 同一个测试二进制内的测试共用一个实例，由 Main 在测试结束后停止
+var shared struct {
+	once sync.Once
+	pg   *Postgres
+	err  error
+}
+
+// Shared 返回当前测试二进制共用的实例，首次调用时启动
+func Shared(ctx context.Context) (*Postgres, error) {
+	shared.once.Do(func() {
+		shared.pg, shared.err = StartPostgres(ctx)
+	})
+	return shared.pg, shared.err
+}
+
+// Main 供测试包的 TestMain 调用，运行测试后停止共用的容器：
+//
+//	func TestMain(m *testing.M) { storagetest.Main(m) }
+func Main(m *testing.M) {
+	code := m.Run()
+	if shared.pg != nil {
+		if err := shared.pg.Stop(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+	}
+	os.Exit(code)
+}
+
+// ConnStr 返回一个独立 schema 的连接串，测试结束时删除该 schema；
+// 没有可用的数据库或使用 -short 时跳过测试
+func ConnStr(t testing.TB) string {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("skipping storage integration test in short mode")
+	}
+
+	ctx := context.Background()
+	pg, err := Shared(ctx)
+	if errors.Is(err, ErrUnavailable) {
+		t.Skipf("skipping storage integration test: %v", err)
+	}
+	if err != nil {
+		t.Fatalf("failed to start test database: %v", err)
+	}
+
+	connStr, drop, err := pg.CreateSchema(ctx)
+	if err != nil {
+		t.Fatalf("failed to create test schema: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := drop(); err != nil {
+			t.Errorf("failed to clean up test schema: %v", err)
+		}
+	})
+	return connStr
+}
+
+// New 返回使用独立 schema 的 PostgresStorage，表已按当前版本建好，测试结束时关闭连接并删除 schema
+func New(t testing.TB) *storage.PostgresStorage {
+	t.Helper()
+	connStr := ConnStr(t)
+
+	s, err := storage.NewPostgresStorage(connStr)
+	if err != nil {
+		t.Fatalf("failed to open test storage: %v", err)
+	}
+	// Cleanup 后注册先执行，先关闭连接再删除 schema
+	t.Cleanup(func() { s.Close() })
+	return s
+}
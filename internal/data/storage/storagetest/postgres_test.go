@@ -0,0 +1,14 @@
+package storagetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithSearchPath(t *testing.T) {
+	assert.Equal(t, "postgres://u:p@localhost:5432/db?search_path=test_a&sslmode=disable",
+		withSearchPath("postgres://u:p@localhost:5432/db?sslmode=disable", "test_a"))
+	assert.Equal(t, "host=localhost dbname=db search_path=test_a",
+		withSearchPath("host=localhost dbname=db", "test_a"))
+}
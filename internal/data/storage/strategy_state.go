@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/data"
+)
+
+// GetStrategyState implements DataStorage interface
+func (s *PostgresStorage) GetStrategyState(ctx context.Context, namespace, key string) (*data.StrategyState, error) {
+	query := `
+        SELECT value, version, updated_at
+        FROM strategy_state
+        WHERE namespace = $1 AND key = $2
+    `
+
+	state := data.StrategyState{Namespace: namespace, Key: key}
+	var value []byte
+	err := s.db.QueryRowContext(ctx, query, namespace, key).Scan(&value, &state.Version, &state.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: strategy state %s/%s", data.ErrNotFound, namespace, key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get strategy state: %w", err)
+	}
+	state.Value = value
+	return &state, nil
+}
+
+// SetStrategyState implements DataStorage interface，版本为 0 时插入，否则只在存储中的版本一致时更新
+func (s *PostgresStorage) SetStrategyState(ctx context.Context, state *data.StrategyState) error {
+	now := time.Now()
+	var result sql.Result
+	var err error
+	if state.Version == 0 {
+		result, err = s.db.ExecContext(ctx, `
+            INSERT INTO strategy_state (namespace, key, value, version, updated_at)
+            VALUES ($1, $2, $3, 1, $4)
+            ON CONFLICT (namespace, key) DO NOTHING
+        `, state.Namespace, state.Key, []byte(state.Value), now)
+	} else {
+		result, err = s.db.ExecContext(ctx, `
+            UPDATE strategy_state
+            SET value = $3, version = version + 1, updated_at = $4
+            WHERE namespace = $1 AND key = $2 AND version = $5
+        `, state.Namespace, state.Key, []byte(state.Value), now, state.Version)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set strategy state: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to count updated rows: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: %s/%s is no longer at version %d", data.ErrStateConflict, state.Namespace, state.Key, state.Version)
+	}
+	state.Version++
+	state.UpdatedAt = now
+	return nil
+}
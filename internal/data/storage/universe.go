@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/songzhibin97/quantaflux/internal/universe"
+)
+
+// SaveSymbols implements universe.Store interface
+func (s *PostgresStorage) SaveSymbols(ctx context.Context, symbols []universe.Symbol) error {
+	txn, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer txn.Rollback()
+
+	query := `
+        INSERT INTO symbol_universe (symbol, base_asset, quote_asset, status, tick_size, step_size, tags, listed_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+        ON CONFLICT (symbol) DO UPDATE SET
+            base_asset = EXCLUDED.base_asset,
+            quote_asset = EXCLUDED.quote_asset,
+            status = EXCLUDED.status,
+            tick_size = EXCLUDED.tick_size,
+            step_size = EXCLUDED.step_size,
+            tags = EXCLUDED.tags,
+            listed_at = EXCLUDED.listed_at,
+            updated_at = EXCLUDED.updated_at
+    `
+	for _, symbol := range symbols {
+		listedAt := sql.NullTime{Time: symbol.ListedAt, Valid: !symbol.ListedAt.IsZero()}
+		// nil 切片会写入 NULL
+		tags := symbol.Tags
+		if tags == nil {
+			tags = []string{}
+		}
+		if _, err := txn.ExecContext(ctx, query,
+			symbol.Symbol, symbol.BaseAsset, symbol.QuoteAsset, symbol.Status, symbol.TickSize, symbol.StepSize,
+			pq.Array(tags), listedAt, symbol.UpdatedAt,
+		); err != nil {
+			return fmt.Errorf("failed to save symbol %s: %w", symbol.Symbol, err)
+		}
+	}
+
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("failed to commit symbol universe: %w", err)
+	}
+	return nil
+}
+
+// ListSymbols implements universe.Store interface
+func (s *PostgresStorage) ListSymbols(ctx context.Context) ([]universe.Symbol, error) {
+	query := `
+        SELECT symbol, base_asset, quote_asset, status, tick_size, step_size, tags, listed_at, updated_at
+        FROM symbol_universe
+        ORDER BY symbol ASC
+    `
+
+	rows, err := s.reads.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query symbol universe: %w", err)
+	}
+	defer rows.Close()
+
+	var result []universe.Symbol
+	for rows.Next() {
+		var symbol universe.Symbol
+		var listedAt sql.NullTime
+		if err := rows.Scan(&symbol.Symbol, &symbol.BaseAsset, &symbol.QuoteAsset, &symbol.Status, &symbol.TickSize, &symbol.StepSize,
+			pq.Array(&symbol.Tags), &listedAt, &symbol.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan symbol: %w", err)
+		}
+		symbol.ListedAt = listedAt.Time
+		result = append(result, symbol)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating symbol universe rows: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,185 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/songzhibin97/quantaflux/internal/ai"
+	"github.com/songzhibin97/quantaflux/internal/models"
+)
+
+// 候选代币入选原因
+const (
+	ReasonNewListing  = "new_listing"  // 上次扫描后新上线的交易对
+	ReasonTopGainer   = "top_gainer"   // 24 小时涨幅靠前
+	ReasonVolumeSpike = "volume_spike" // 成交额相对上次扫描放大
+)
+
+// TickerSource 提供全市场 24 小时行情
+type TickerSource interface {
+	// Tickers returns the 24h statistics of all symbols on the exchange.
+	Tickers(ctx context.Context) ([]models.MarketData, error)
+}
+
+// TokenInfoSource 提供代币信息，用于项目分析
+type TokenInfoSource interface {
+	CollectTokenInfo(ctx context.Context, symbol string) (*models.TokenInfo, error)
+}
+
+// Filters 候选代币筛选条件
+type Filters struct {
+	QuoteAsset       string  // 只筛选以该资产计价的交易对，如 USDT
+	MinQuoteVolume   float64 // 24 小时成交额下限（计价资产）
+	TopGainers       int     // 取 24 小时涨幅前 N 名，0 表示不按涨幅筛选
+	MinPriceChange   float64 // 涨幅榜的最低涨幅（百分比）
+	VolumeSpikeRatio float64 // 成交额达到上次扫描的该倍数时视为放量，0 表示不检查
+	NewListings      bool    // 是否筛选新上线的交易对
+}
+
+// Candidate 通过筛选的候选交易对
+type Candidate struct {
+	Symbol      string   `json:"symbol"`
+	Price       float64  `json:"price"`
+	QuoteVolume float64  `json:"quote_volume"`
+	PriceChange float64  `json:"price_change"`
+	Reasons     []string `json:"reasons"`
+}
+
+// Scanner 按筛选条件扫描全市场行情，记录上次扫描的交易对和成交额用于识别新上线和放量
+type Scanner struct {
+	source  TickerSource
+	filters Filters
+	volumes map[string]float64 // 上次扫描时各交易对的成交额，为 nil 表示尚未扫描
+}
+
+func NewScanner(source TickerSource, filters Filters) *Scanner {
+	return &Scanner{source: source, filters: filters}
+}
+
+// Scan 返回本次扫描的候选交易对，exclude 中的交易对（如已在交易）不参与筛选。
+// 首次扫描只建立基准，不产生新上线和放量候选
+func (s *Scanner) Scan(ctx context.Context, exclude []string) ([]Candidate, error) {
+	tickers, err := s.source.Tickers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tickers: %w", err)
+	}
+
+	previous := s.volumes
+	s.volumes = make(map[string]float64, len(tickers))
+
+	var eligible []Candidate
+	for _, t := range tickers {
+		if s.filters.QuoteAsset != "" && !strings.HasSuffix(t.Symbol, s.filters.QuoteAsset) {
+			continue
+		}
+		quoteVolume := t.Volume24h * t.Price
+		s.volumes[t.Symbol] = quoteVolume
+
+		if slices.Contains(exclude, t.Symbol) || quoteVolume < s.filters.MinQuoteVolume {
+			continue
+		}
+		eligible = append(eligible, Candidate{
+			Symbol:      t.Symbol,
+			Price:       t.Price,
+			QuoteVolume: quoteVolume,
+			PriceChange: t.PriceChange24h,
+		})
+	}
+
+	if s.filters.TopGainers > 0 {
+		sort.SliceStable(eligible, func(i, j int) bool {
+			return eligible[i].PriceChange > eligible[j].PriceChange
+		})
+		for i := 0; i < len(eligible) && i < s.filters.TopGainers; i++ {
+			if eligible[i].PriceChange >= s.filters.MinPriceChange {
+				eligible[i].Reasons = append(eligible[i].Reasons, ReasonTopGainer)
+			}
+		}
+	}
+
+	if previous != nil {
+		for i, c := range eligible {
+			last, seen := previous[c.Symbol]
+			switch {
+			case !seen && s.filters.NewListings:
+				eligible[i].Reasons = append(eligible[i].Reasons, ReasonNewListing)
+			case seen && s.filters.VolumeSpikeRatio > 0 && last > 0 && c.QuoteVolume >= last*s.filters.VolumeSpikeRatio:
+				eligible[i].Reasons = append(eligible[i].Reasons, ReasonVolumeSpike)
+			}
+		}
+	}
+
+	var candidates []Candidate
+	for _, c := range eligible {
+		if len(c.Reasons) > 0 {
+			candidates = append(candidates, c)
+		}
+	}
+	return candidates, nil
+}
+
+// Result 候选交易对的 AI 评估结果
+type Result struct {
+	Candidate
+	Metrics  *models.ProjectMetrics `json:"metrics,omitempty"`
+	Scam     *ai.ScamAnalysis       `json:"scam,omitempty"`
+	Promoted bool                   `json:"promoted"`
+	Reason   string                 `json:"reason,omitempty"` // 未通过评估的原因
+}
+
+// Evaluator 对候选交易对执行项目分析和诈骗检测
+type Evaluator struct {
+	tokens        TokenInfoSource
+	analyzer      ai.Analyzer
+	scamThreshold float64 // 诈骗概率超过该值时不通过
+}
+
+func NewEvaluator(tokens TokenInfoSource, analyzer ai.Analyzer, scamThreshold float64) *Evaluator {
+	return &Evaluator{tokens: tokens, analyzer: analyzer, scamThreshold: scamThreshold}
+}
+
+// Evaluate 依次评估候选交易对，单个候选失败不影响其他候选，错误合并返回
+func (e *Evaluator) Evaluate(ctx context.Context, candidates []Candidate) ([]Result, error) {
+	var (
+		results []Result
+		errs    []error
+	)
+	for _, c := range candidates {
+		result, err := e.evaluate(ctx, c)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", c.Symbol, err))
+			continue
+		}
+		results = append(results, *result)
+	}
+	return results, errors.Join(errs...)
+}
+
+func (e *Evaluator) evaluate(ctx context.Context, c Candidate) (*Result, error) {
+	tokenInfo, err := e.tokens.CollectTokenInfo(ctx, c.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics, err := e.analyzer.AnalyzeProject(ctx, tokenInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	scam, err := e.analyzer.DetectScam(ctx, metrics)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Candidate: c, Metrics: metrics, Scam: scam}
+	if scam.ScamProbability > e.scamThreshold {
+		result.Reason = fmt.Sprintf("scam probability %.2f exceeds threshold %.2f", scam.ScamProbability, e.scamThreshold)
+		return result, nil
+	}
+	result.Promoted = true
+	return result, nil
+}
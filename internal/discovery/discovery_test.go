@@ -0,0 +1,103 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/songzhibin97/quantaflux/internal/ai"
+	"github.com/songzhibin97/quantaflux/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubTickers struct {
+	tickers []models.MarketData
+}
+
+func (s *stubTickers) Tickers(ctx context.Context) ([]models.MarketData, error) {
+	return s.tickers, nil
+}
+
+func symbols(candidates []Candidate) []string {
+	var result []string
+	for _, c := range candidates {
+		result = append(result, c.Symbol)
+	}
+	return result
+}
+
+func TestScanner_Scan(t *testing.T) {
+	source := &stubTickers{tickers: []models.MarketData{
+		{Symbol: "BTCUSDT", Price: 100, Volume24h: 1000, PriceChange24h: 1},
+		{Symbol: "ETHUSDT", Price: 10, Volume24h: 1000, PriceChange24h: 8},
+		{Symbol: "DOGEUSDT", Price: 1, Volume24h: 50, PriceChange24h: 30},
+		{Symbol: "ETHBTC", Price: 0.05, Volume24h: 1e9, PriceChange24h: 20},
+	}}
+	scanner := NewScanner(source, Filters{
+		QuoteAsset:       "USDT",
+		MinQuoteVolume:   1000,
+		TopGainers:       2,
+		MinPriceChange:   5,
+		VolumeSpikeRatio: 3,
+		NewListings:      true,
+	})
+	ctx := context.Background()
+
+	// 首次扫描只有涨幅榜候选：DOGE 成交额不足，BTC 涨幅不足，ETHBTC 不是 USDT 计价
+	candidates, err := scanner.Scan(ctx, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"ETHUSDT"}, symbols(candidates))
+	assert.Equal(t, []string{ReasonTopGainer}, candidates[0].Reasons)
+
+	// 新上线交易对和成交额放大的交易对
+	source.tickers = []models.MarketData{
+		{Symbol: "BTCUSDT", Price: 100, Volume24h: 4000, PriceChange24h: 1},
+		{Symbol: "ETHUSDT", Price: 10, Volume24h: 1000, PriceChange24h: 8},
+		{Symbol: "NEWUSDT", Price: 2, Volume24h: 1000, PriceChange24h: 0},
+	}
+	candidates, err = scanner.Scan(ctx, []string{"ETHUSDT"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"BTCUSDT", "NEWUSDT"}, symbols(candidates))
+	assert.Equal(t, []string{ReasonVolumeSpike}, candidates[0].Reasons)
+	assert.Equal(t, []string{ReasonNewListing}, candidates[1].Reasons)
+	assert.InDelta(t, 400000, candidates[0].QuoteVolume, 1e-9)
+}
+
+type stubTokens struct{}
+
+func (stubTokens) CollectTokenInfo(ctx context.Context, symbol string) (*models.TokenInfo, error) {
+	if symbol == "GONEUSDT" {
+		return nil, errors.New("symbol not found")
+	}
+	return &models.TokenInfo{Symbol: symbol, Name: symbol}, nil
+}
+
+type stubAnalyzer struct {
+	ai.Analyzer
+	scam map[string]float64
+}
+
+func (s *stubAnalyzer) AnalyzeProject(ctx context.Context, info *models.TokenInfo) (*models.ProjectMetrics, error) {
+	return &models.ProjectMetrics{TokenInfo: *info}, nil
+}
+
+func (s *stubAnalyzer) DetectScam(ctx context.Context, projectData *models.ProjectMetrics) (*ai.ScamAnalysis, error) {
+	return &ai.ScamAnalysis{ScamProbability: s.scam[projectData.TokenInfo.Symbol]}, nil
+}
+
+func TestEvaluator_Evaluate(t *testing.T) {
+	analyzer := &stubAnalyzer{scam: map[string]float64{"GOODUSDT": 0.1, "RUGUSDT": 0.9}}
+	evaluator := NewEvaluator(stubTokens{}, analyzer, 0.7)
+
+	results, err := evaluator.Evaluate(context.Background(), []Candidate{
+		{Symbol: "GOODUSDT"}, {Symbol: "RUGUSDT"}, {Symbol: "GONEUSDT"},
+	})
+	assert.ErrorContains(t, err, "GONEUSDT")
+	require.Len(t, results, 2)
+
+	assert.True(t, results[0].Promoted)
+	assert.False(t, results[1].Promoted)
+	assert.Contains(t, results[1].Reason, "scam probability")
+}
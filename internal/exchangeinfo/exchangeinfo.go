@@ -0,0 +1,139 @@
+package exchangeinfo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// StatusTrading 正常交易的状态，其他状态（BREAK、HALT 等）不能下单
+const StatusTrading = "TRADING"
+
+// Symbol 交易对元数据，过滤条件未设置时为 0
+type Symbol struct {
+	Symbol      string  `json:"symbol"`
+	BaseAsset   string  `json:"base_asset"`
+	QuoteAsset  string  `json:"quote_asset"`
+	Status      string  `json:"status"`
+	TickSize    float64 `json:"tick_size"`    // 价格最小变动单位
+	StepSize    float64 `json:"step_size"`    // 数量最小变动单位
+	MinQty      float64 `json:"min_qty"`      // 最小下单数量
+	MinNotional float64 `json:"min_notional"` // 最小下单金额
+}
+
+// Trading 是否可以交易
+func (s Symbol) Trading() bool {
+	return s.Status == StatusTrading
+}
+
+// Check 检查下单数量和金额是否满足交易所的过滤条件，quantity 或 notional 为 0 时不检查该项
+func (s Symbol) Check(quantity, notional float64) error {
+	if quantity > 0 && quantity < s.MinQty {
+		return fmt.Errorf("quantity %v of %s is below min qty %v", quantity, s.Symbol, s.MinQty)
+	}
+	if notional > 0 && notional < s.MinNotional {
+		return fmt.Errorf("notional %v of %s is below min notional %v", notional, s.Symbol, s.MinNotional)
+	}
+	return nil
+}
+
+// Source 交易所元数据来源
+type Source interface {
+	ExchangeInfo(ctx context.Context) ([]Symbol, error)
+}
+
+// StatusChange 交易对状态变化，Current 为空表示交易对已下架
+type StatusChange struct {
+	Symbol   string `json:"symbol"`
+	Previous string `json:"previous"`
+	Current  string `json:"current"`
+}
+
+// Service 缓存交易所元数据，由周期任务刷新，供数据源和执行器共享，避免各自请求 exchangeInfo
+type Service struct {
+	source Source
+	// OnStatusChange 刷新时发现交易对状态变化后调用，首次加载不触发
+	OnStatusChange func(change StatusChange)
+
+	mu        sync.RWMutex
+	symbols   map[string]Symbol
+	updatedAt time.Time
+}
+
+func NewService(source Source) *Service {
+	return &Service{source: source}
+}
+
+// Refresh 重新加载元数据，失败时保留上一次的缓存
+func (s *Service) Refresh(ctx context.Context) error {
+	list, err := s.source.ExchangeInfo(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to refresh exchange info: %w", err)
+	}
+
+	symbols := make(map[string]Symbol, len(list))
+	for _, symbol := range list {
+		symbols[symbol.Symbol] = symbol
+	}
+
+	s.mu.Lock()
+	previous, loaded := s.symbols, !s.updatedAt.IsZero()
+	s.symbols = symbols
+	s.updatedAt = time.Now()
+	s.mu.Unlock()
+
+	if loaded && s.OnStatusChange != nil {
+		for _, change := range diff(previous, symbols) {
+			s.OnStatusChange(change)
+		}
+	}
+	return nil
+}
+
+// diff 返回两次加载之间状态变化的交易对，新上架的交易对不算状态变化
+func diff(previous, current map[string]Symbol) []StatusChange {
+	var changes []StatusChange
+	for name, before := range previous {
+		after, ok := current[name]
+		if !ok {
+			changes = append(changes, StatusChange{Symbol: name, Previous: before.Status})
+			continue
+		}
+		if after.Status != before.Status {
+			changes = append(changes, StatusChange{Symbol: name, Previous: before.Status, Current: after.Status})
+		}
+	}
+	return changes
+}
+
+// Symbol 返回交易对的元数据，未加载或交易所没有该交易对时返回 false
+func (s *Service) Symbol(symbol string) (Symbol, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	info, ok := s.symbols[symbol]
+	return info, ok
+}
+
+// Symbols 返回全部交易对的元数据，按交易对排序，未加载时为空
+func (s *Service) Symbols() []Symbol {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := make([]Symbol, 0, len(s.symbols))
+	for _, info := range s.symbols {
+		list = append(list, info)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Symbol < list[j].Symbol })
+	return list
+}
+
+// UpdatedAt 最近一次成功刷新的时间，未加载时为零值
+func (s *Service) UpdatedAt() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.updatedAt
+}
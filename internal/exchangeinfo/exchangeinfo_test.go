@@ -0,0 +1,79 @@
+package exchangeinfo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubSource struct {
+	symbols []Symbol
+	err     error
+	calls   int
+}
+
+func (s *stubSource) ExchangeInfo(ctx context.Context) ([]Symbol, error) {
+	s.calls++
+	return s.symbols, s.err
+}
+
+func TestService_Refresh(t *testing.T) {
+	ctx := context.Background()
+	source := &stubSource{symbols: []Symbol{
+		{Symbol: "BTCUSDT", BaseAsset: "BTC", QuoteAsset: "USDT", Status: StatusTrading, MinQty: 0.0001, MinNotional: 5},
+		{Symbol: "ETHUSDT", Status: StatusTrading},
+		{Symbol: "LUNAUSDT", Status: StatusTrading},
+	}}
+	service := NewService(source)
+
+	var changes []StatusChange
+	service.OnStatusChange = func(change StatusChange) {
+		changes = append(changes, change)
+	}
+
+	_, ok := service.Symbol("BTCUSDT")
+	assert.False(t, ok)
+	assert.True(t, service.UpdatedAt().IsZero())
+
+	// 首次加载不触发状态变化
+	require.NoError(t, service.Refresh(ctx))
+	assert.Empty(t, changes)
+	btc, ok := service.Symbol("BTCUSDT")
+	require.True(t, ok)
+	assert.True(t, btc.Trading())
+	assert.Equal(t, "BTC", btc.BaseAsset)
+
+	// ETH 暂停交易，LUNA 下架，新上架的 SOL 不算状态变化
+	source.symbols = []Symbol{
+		{Symbol: "BTCUSDT", Status: StatusTrading},
+		{Symbol: "ETHUSDT", Status: "BREAK"},
+		{Symbol: "SOLUSDT", Status: StatusTrading},
+	}
+	require.NoError(t, service.Refresh(ctx))
+	assert.ElementsMatch(t, []StatusChange{
+		{Symbol: "ETHUSDT", Previous: StatusTrading, Current: "BREAK"},
+		{Symbol: "LUNAUSDT", Previous: StatusTrading},
+	}, changes)
+	eth, _ := service.Symbol("ETHUSDT")
+	assert.False(t, eth.Trading())
+
+	// 刷新失败时保留缓存
+	source.err = errors.New("unavailable")
+	assert.Error(t, service.Refresh(ctx))
+	_, ok = service.Symbol("SOLUSDT")
+	assert.True(t, ok)
+	assert.Equal(t, 3, source.calls)
+}
+
+func TestSymbol_Check(t *testing.T) {
+	symbol := Symbol{Symbol: "BTCUSDT", MinQty: 0.001, MinNotional: 5}
+
+	assert.NoError(t, symbol.Check(0.01, 10))
+	assert.Error(t, symbol.Check(0.0001, 10))
+	assert.Error(t, symbol.Check(0.01, 1))
+	// 市价单按金额下单时不检查数量
+	assert.NoError(t, symbol.Check(0, 10))
+}
@@ -0,0 +1,108 @@
+package fx
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/songzhibin97/quantaflux/internal/trading"
+)
+
+// ErrNoRate 缺少换算所需的交易对价格
+var ErrNoRate = errors.New("no conversion rate")
+
+// Converter 根据交易对的最新价格在资产之间换算金额，
+// 没有直接交易对时经过中间资产换算，如 ETH -> BTC -> USDT，优先使用换算次数最少的路径
+type Converter struct {
+	mu     sync.RWMutex
+	prices map[string]map[string]float64 // 基础资产 -> 计价资产 -> 最新价格
+}
+
+// NewConverter creates a new Converter instance
+func NewConverter() *Converter {
+	return &Converter{prices: make(map[string]map[string]float64)}
+}
+
+// Update 记录交易对的最新价格，无法拆分的交易对和非正价格忽略
+func (c *Converter) Update(symbol string, price float64) {
+	base, quote, ok := trading.SplitSymbol(symbol)
+	if !ok || price <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.prices[base] == nil {
+		c.prices[base] = make(map[string]float64)
+	}
+	c.prices[base][quote] = price
+}
+
+// Rate 返回 1 单位 from 资产折合的 to 资产数量，没有直接或反向交易对时按广度优先查找中间资产
+func (c *Converter) Rate(from, to string) (float64, error) {
+	if from == to {
+		return 1, nil
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	rates := map[string]float64{from: 1}
+	queue := []string{from}
+	for len(queue) > 0 {
+		asset := queue[0]
+		queue = queue[1:]
+		for _, next := range c.neighbors(asset) {
+			if _, seen := rates[next]; seen {
+				continue
+			}
+			rate, _ := c.direct(asset, next)
+			rates[next] = rates[asset] * rate
+			if next == to {
+				return rates[next], nil
+			}
+			queue = append(queue, next)
+		}
+	}
+	return 0, fmt.Errorf("%w from %s to %s", ErrNoRate, from, to)
+}
+
+// Convert 将 from 资产的数量换算为 to 资产，数量为 0 时不需要价格
+func (c *Converter) Convert(amount float64, from, to string) (float64, error) {
+	if amount == 0 {
+		return 0, nil
+	}
+	rate, err := c.Rate(from, to)
+	if err != nil {
+		return 0, err
+	}
+	return amount * rate, nil
+}
+
+// neighbors 返回与资产有交易对价格的资产，按名称排序使换算路径稳定，调用方需持有读锁
+func (c *Converter) neighbors(asset string) []string {
+	var assets []string
+	for quote := range c.prices[asset] {
+		assets = append(assets, quote)
+	}
+	for base, quotes := range c.prices {
+		if _, ok := quotes[asset]; ok {
+			assets = append(assets, base)
+		}
+	}
+	slices.Sort(assets)
+	return slices.Compact(assets)
+}
+
+// direct 按直接或反向交易对换算，调用方需持有读锁
+func (c *Converter) direct(from, to string) (float64, bool) {
+	if price, ok := c.prices[from][to]; ok {
+		return price, true
+	}
+	if price, ok := c.prices[to][from]; ok {
+		return 1 / price, true
+	}
+	return 0, false
+}
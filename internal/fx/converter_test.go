@@ -0,0 +1,51 @@
+package fx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConverter_Rate(t *testing.T) {
+	converter := NewConverter()
+	converter.Update("BTCUSDT", 50000)
+	converter.Update("ETHBTC", 0.05)
+	converter.Update("BNBBUSD", 300)
+	converter.Update("UNKNOWN", 1)
+
+	tests := []struct {
+		name     string
+		from, to string
+		want     float64
+		wantErr  bool
+	}{
+		{name: "same asset", from: "USDT", to: "USDT", want: 1},
+		{name: "direct", from: "BTC", to: "USDT", want: 50000},
+		{name: "inverse", from: "USDT", to: "BTC", want: 1.0 / 50000},
+		{name: "through bridge", from: "ETH", to: "USDT", want: 2500},
+		{name: "inverse through bridge", from: "USDT", to: "ETH", want: 1.0 / 2500},
+		{name: "missing quote rate", from: "BNB", to: "USDT", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, err := converter.Rate(tt.from, tt.to)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrNoRate)
+				return
+			}
+			require.NoError(t, err)
+			assert.InDelta(t, tt.want, rate, tt.want*1e-9)
+		})
+	}
+
+	// 补充计价资产之间的价格后可以经过 USDT 换算
+	converter.Update("BUSDUSDT", 0.999)
+	value, err := converter.Convert(2, "BNB", "USDT")
+	require.NoError(t, err)
+	assert.InDelta(t, 599.4, value, 1e-9)
+
+	value, err = converter.Convert(0, "DOGE", "USDT")
+	require.NoError(t, err)
+	assert.Zero(t, value)
+}
@@ -0,0 +1,105 @@
+package grpcapi
+
+import (
+	"time"
+
+	pb "github.com/songzhibin97/quantaflux/internal/grpcapi/quantafluxpb"
+	"github.com/songzhibin97/quantaflux/internal/journal"
+	"github.com/songzhibin97/quantaflux/internal/models"
+	"github.com/songzhibin97/quantaflux/internal/risk"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// 以下函数将内部模型转换为 proto/quantaflux.proto 中对应的消息
+
+// timestamp 零值时间不输出字段，与 JSON 接口省略空时间一致
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func toMarketData(d *models.MarketData) *pb.MarketData {
+	return &pb.MarketData{
+		Symbol:          d.Symbol,
+		Price:           d.Price,
+		Volume_24H:      d.Volume24h,
+		MarketCap:       d.MarketCap,
+		PriceChange_1H:  d.PriceChange1h,
+		PriceChange_24H: d.PriceChange24h,
+		Timeframe:       d.Timeframe,
+		Timestamp:       timestamp(d.Timestamp),
+	}
+}
+
+func toTokenInfo(info *models.TokenInfo) *pb.TokenInfo {
+	return &pb.TokenInfo{
+		Symbol:            info.Symbol,
+		Name:              info.Name,
+		ContractAddress:   info.ContractAddress,
+		Network:           info.Network,
+		LaunchType:        info.LaunchType,
+		LaunchDate:        timestamp(info.LaunchDate),
+		InitialPrice:      info.InitialPrice,
+		TotalSupply:       info.TotalSupply,
+		CirculatingSupply: info.CirculatingSupply,
+		TeamAllocation:    info.TeamAllocation,
+		VestingSchedule:   info.VestingSchedule,
+	}
+}
+
+func toRiskSnapshot(state *risk.RiskState) *pb.RiskSnapshot {
+	return &pb.RiskSnapshot{
+		Parameters: &pb.RiskParameters{
+			MaxPositionSize: state.Parameters.MaxPositionSize,
+			MaxLossPerTrade: state.Parameters.MaxLossPerTrade,
+			MaxDailyLoss:    state.Parameters.MaxDailyLoss,
+			MaxLeverage:     state.Parameters.MaxLeverage,
+			MinLiquidity:    state.Parameters.MinLiquidity,
+		},
+		DailyLoss:       state.DailyLoss,
+		DailyVolume:     state.DailyVolume,
+		DailyTradeCount: int32(state.DailyTradeCount),
+		StatsReset:      timestamp(state.StatsReset),
+		TradingPaused:   state.TradingPaused,
+		PausedSymbols:   state.PausedSymbols,
+		StaleSymbols:    state.StaleSymbols,
+	}
+}
+
+func toOrder(o *trading.Order) *pb.Order {
+	return &pb.Order{
+		Symbol:        o.Symbol,
+		Side:          o.Side,
+		Amount:        o.Amount,
+		QuoteAmount:   o.QuoteAmount,
+		Price:         o.Price,
+		FilledAmount:  o.FilledAmount,
+		FilledPrice:   o.FilledPrice,
+		OrderType:     o.OrderType,
+		Status:        o.Status,
+		OrderId:       o.OrderID,
+		Account:       o.Account,
+		ClientOrderId: o.ClientOrderID,
+		Fee:           o.Fee,
+		FeeAsset:      o.FeeAsset,
+		CreatedAt:     timestamp(o.CreatedAt),
+		UpdatedAt:     timestamp(o.UpdatedAt),
+	}
+}
+
+func toOrderEntry(entry *journal.Entry) *pb.OrderEntry {
+	return &pb.OrderEntry{
+		Id:              entry.ID,
+		Strategy:        entry.Strategy,
+		Mode:            entry.Mode,
+		Order:           toOrder(&entry.Order),
+		MarketData:      toMarketData(&entry.MarketData),
+		Sentiment:       entry.Sentiment,
+		ScamProbability: entry.ScamProbability,
+		CreatedAt:       timestamp(entry.CreatedAt),
+	}
+}
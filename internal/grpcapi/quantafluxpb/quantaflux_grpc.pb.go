@@ -0,0 +1,522 @@
+// QuantaFlux gRPC API，只读查询接口，与 REST API 的 GET 接口对应。
+// 修改后在 internal/grpcapi 目录执行 go generate 重新生成 quantafluxpb。
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: quantaflux.proto
+
+package quantafluxpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	CollectorService_GetMarketData_FullMethodName = "/quantaflux.v1.CollectorService/GetMarketData"
+	CollectorService_GetTokenInfo_FullMethodName  = "/quantaflux.v1.CollectorService/GetTokenInfo"
+)
+
+// CollectorServiceClient is the client API for CollectorService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// CollectorService 通过数据源实时查询行情和代币信息
+type CollectorServiceClient interface {
+	GetMarketData(ctx context.Context, in *GetMarketDataRequest, opts ...grpc.CallOption) (*MarketData, error)
+	GetTokenInfo(ctx context.Context, in *GetTokenInfoRequest, opts ...grpc.CallOption) (*TokenInfo, error)
+}
+
+type collectorServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCollectorServiceClient(cc grpc.ClientConnInterface) CollectorServiceClient {
+	return &collectorServiceClient{cc}
+}
+
+func (c *collectorServiceClient) GetMarketData(ctx context.Context, in *GetMarketDataRequest, opts ...grpc.CallOption) (*MarketData, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MarketData)
+	err := c.cc.Invoke(ctx, CollectorService_GetMarketData_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *collectorServiceClient) GetTokenInfo(ctx context.Context, in *GetTokenInfoRequest, opts ...grpc.CallOption) (*TokenInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TokenInfo)
+	err := c.cc.Invoke(ctx, CollectorService_GetTokenInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CollectorServiceServer is the server API for CollectorService service.
+// All implementations must embed UnimplementedCollectorServiceServer
+// for forward compatibility.
+//
+// CollectorService 通过数据源实时查询行情和代币信息
+type CollectorServiceServer interface {
+	GetMarketData(context.Context, *GetMarketDataRequest) (*MarketData, error)
+	GetTokenInfo(context.Context, *GetTokenInfoRequest) (*TokenInfo, error)
+	mustEmbedUnimplementedCollectorServiceServer()
+}
+
+// UnimplementedCollectorServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCollectorServiceServer struct{}
+
+func (UnimplementedCollectorServiceServer) GetMarketData(context.Context, *GetMarketDataRequest) (*MarketData, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMarketData not implemented")
+}
+func (UnimplementedCollectorServiceServer) GetTokenInfo(context.Context, *GetTokenInfoRequest) (*TokenInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTokenInfo not implemented")
+}
+func (UnimplementedCollectorServiceServer) mustEmbedUnimplementedCollectorServiceServer() {}
+func (UnimplementedCollectorServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeCollectorServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CollectorServiceServer will
+// result in compilation errors.
+type UnsafeCollectorServiceServer interface {
+	mustEmbedUnimplementedCollectorServiceServer()
+}
+
+func RegisterCollectorServiceServer(s grpc.ServiceRegistrar, srv CollectorServiceServer) {
+	// If the following call pancis, it indicates UnimplementedCollectorServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&CollectorService_ServiceDesc, srv)
+}
+
+func _CollectorService_GetMarketData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMarketDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CollectorServiceServer).GetMarketData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CollectorService_GetMarketData_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CollectorServiceServer).GetMarketData(ctx, req.(*GetMarketDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CollectorService_GetTokenInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTokenInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CollectorServiceServer).GetTokenInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CollectorService_GetTokenInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CollectorServiceServer).GetTokenInfo(ctx, req.(*GetTokenInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CollectorService_ServiceDesc is the grpc.ServiceDesc for CollectorService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CollectorService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "quantaflux.v1.CollectorService",
+	HandlerType: (*CollectorServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetMarketData",
+			Handler:    _CollectorService_GetMarketData_Handler,
+		},
+		{
+			MethodName: "GetTokenInfo",
+			Handler:    _CollectorService_GetTokenInfo_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "quantaflux.proto",
+}
+
+const (
+	StorageService_GetHistoricalData_FullMethodName = "/quantaflux.v1.StorageService/GetHistoricalData"
+)
+
+// StorageServiceClient is the client API for StorageService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// StorageService 查询已保存的历史行情
+type StorageServiceClient interface {
+	GetHistoricalData(ctx context.Context, in *GetHistoricalDataRequest, opts ...grpc.CallOption) (*GetHistoricalDataResponse, error)
+}
+
+type storageServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewStorageServiceClient(cc grpc.ClientConnInterface) StorageServiceClient {
+	return &storageServiceClient{cc}
+}
+
+func (c *storageServiceClient) GetHistoricalData(ctx context.Context, in *GetHistoricalDataRequest, opts ...grpc.CallOption) (*GetHistoricalDataResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetHistoricalDataResponse)
+	err := c.cc.Invoke(ctx, StorageService_GetHistoricalData_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// StorageServiceServer is the server API for StorageService service.
+// All implementations must embed UnimplementedStorageServiceServer
+// for forward compatibility.
+//
+// StorageService 查询已保存的历史行情
+type StorageServiceServer interface {
+	GetHistoricalData(context.Context, *GetHistoricalDataRequest) (*GetHistoricalDataResponse, error)
+	mustEmbedUnimplementedStorageServiceServer()
+}
+
+// UnimplementedStorageServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedStorageServiceServer struct{}
+
+func (UnimplementedStorageServiceServer) GetHistoricalData(context.Context, *GetHistoricalDataRequest) (*GetHistoricalDataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHistoricalData not implemented")
+}
+func (UnimplementedStorageServiceServer) mustEmbedUnimplementedStorageServiceServer() {}
+func (UnimplementedStorageServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafeStorageServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to StorageServiceServer will
+// result in compilation errors.
+type UnsafeStorageServiceServer interface {
+	mustEmbedUnimplementedStorageServiceServer()
+}
+
+func RegisterStorageServiceServer(s grpc.ServiceRegistrar, srv StorageServiceServer) {
+	// If the following call pancis, it indicates UnimplementedStorageServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&StorageService_ServiceDesc, srv)
+}
+
+func _StorageService_GetHistoricalData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHistoricalDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServiceServer).GetHistoricalData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StorageService_GetHistoricalData_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServiceServer).GetHistoricalData(ctx, req.(*GetHistoricalDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// StorageService_ServiceDesc is the grpc.ServiceDesc for StorageService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var StorageService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "quantaflux.v1.StorageService",
+	HandlerType: (*StorageServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetHistoricalData",
+			Handler:    _StorageService_GetHistoricalData_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "quantaflux.proto",
+}
+
+const (
+	RiskService_GetRiskSnapshot_FullMethodName = "/quantaflux.v1.RiskService/GetRiskSnapshot"
+)
+
+// RiskServiceClient is the client API for RiskService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// RiskService 查询账户的风险状态
+type RiskServiceClient interface {
+	GetRiskSnapshot(ctx context.Context, in *GetRiskSnapshotRequest, opts ...grpc.CallOption) (*RiskSnapshot, error)
+}
+
+type riskServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRiskServiceClient(cc grpc.ClientConnInterface) RiskServiceClient {
+	return &riskServiceClient{cc}
+}
+
+func (c *riskServiceClient) GetRiskSnapshot(ctx context.Context, in *GetRiskSnapshotRequest, opts ...grpc.CallOption) (*RiskSnapshot, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RiskSnapshot)
+	err := c.cc.Invoke(ctx, RiskService_GetRiskSnapshot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RiskServiceServer is the server API for RiskService service.
+// All implementations must embed UnimplementedRiskServiceServer
+// for forward compatibility.
+//
+// RiskService 查询账户的风险状态
+type RiskServiceServer interface {
+	GetRiskSnapshot(context.Context, *GetRiskSnapshotRequest) (*RiskSnapshot, error)
+	mustEmbedUnimplementedRiskServiceServer()
+}
+
+// UnimplementedRiskServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedRiskServiceServer struct{}
+
+func (UnimplementedRiskServiceServer) GetRiskSnapshot(context.Context, *GetRiskSnapshotRequest) (*RiskSnapshot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRiskSnapshot not implemented")
+}
+func (UnimplementedRiskServiceServer) mustEmbedUnimplementedRiskServiceServer() {}
+func (UnimplementedRiskServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafeRiskServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RiskServiceServer will
+// result in compilation errors.
+type UnsafeRiskServiceServer interface {
+	mustEmbedUnimplementedRiskServiceServer()
+}
+
+func RegisterRiskServiceServer(s grpc.ServiceRegistrar, srv RiskServiceServer) {
+	// If the following call pancis, it indicates UnimplementedRiskServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&RiskService_ServiceDesc, srv)
+}
+
+func _RiskService_GetRiskSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRiskSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RiskServiceServer).GetRiskSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RiskService_GetRiskSnapshot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RiskServiceServer).GetRiskSnapshot(ctx, req.(*GetRiskSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// RiskService_ServiceDesc is the grpc.ServiceDesc for RiskService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RiskService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "quantaflux.v1.RiskService",
+	HandlerType: (*RiskServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetRiskSnapshot",
+			Handler:    _RiskService_GetRiskSnapshot_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "quantaflux.proto",
+}
+
+const (
+	OrderService_ListOrders_FullMethodName = "/quantaflux.v1.OrderService/ListOrders"
+	OrderService_GetOrder_FullMethodName   = "/quantaflux.v1.OrderService/GetOrder"
+)
+
+// OrderServiceClient is the client API for OrderService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// OrderService 查询交易日志中的订单
+type OrderServiceClient interface {
+	ListOrders(ctx context.Context, in *ListOrdersRequest, opts ...grpc.CallOption) (*ListOrdersResponse, error)
+	GetOrder(ctx context.Context, in *GetOrderRequest, opts ...grpc.CallOption) (*OrderEntry, error)
+}
+
+type orderServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewOrderServiceClient(cc grpc.ClientConnInterface) OrderServiceClient {
+	return &orderServiceClient{cc}
+}
+
+func (c *orderServiceClient) ListOrders(ctx context.Context, in *ListOrdersRequest, opts ...grpc.CallOption) (*ListOrdersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListOrdersResponse)
+	err := c.cc.Invoke(ctx, OrderService_ListOrders_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) GetOrder(ctx context.Context, in *GetOrderRequest, opts ...grpc.CallOption) (*OrderEntry, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrderEntry)
+	err := c.cc.Invoke(ctx, OrderService_GetOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// OrderServiceServer is the server API for OrderService service.
+// All implementations must embed UnimplementedOrderServiceServer
+// for forward compatibility.
+//
+// OrderService 查询交易日志中的订单
+type OrderServiceServer interface {
+	ListOrders(context.Context, *ListOrdersRequest) (*ListOrdersResponse, error)
+	GetOrder(context.Context, *GetOrderRequest) (*OrderEntry, error)
+	mustEmbedUnimplementedOrderServiceServer()
+}
+
+// UnimplementedOrderServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedOrderServiceServer struct{}
+
+func (UnimplementedOrderServiceServer) ListOrders(context.Context, *ListOrdersRequest) (*ListOrdersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOrders not implemented")
+}
+func (UnimplementedOrderServiceServer) GetOrder(context.Context, *GetOrderRequest) (*OrderEntry, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrder not implemented")
+}
+func (UnimplementedOrderServiceServer) mustEmbedUnimplementedOrderServiceServer() {}
+func (UnimplementedOrderServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeOrderServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to OrderServiceServer will
+// result in compilation errors.
+type UnsafeOrderServiceServer interface {
+	mustEmbedUnimplementedOrderServiceServer()
+}
+
+func RegisterOrderServiceServer(s grpc.ServiceRegistrar, srv OrderServiceServer) {
+	// If the following call pancis, it indicates UnimplementedOrderServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&OrderService_ServiceDesc, srv)
+}
+
+func _OrderService_ListOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOrdersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).ListOrders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_ListOrders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).ListOrders(ctx, req.(*ListOrdersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_GetOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).GetOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_GetOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).GetOrder(ctx, req.(*GetOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// OrderService_ServiceDesc is the grpc.ServiceDesc for OrderService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var OrderService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "quantaflux.v1.OrderService",
+	HandlerType: (*OrderServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListOrders",
+			Handler:    _OrderService_ListOrders_Handler,
+		},
+		{
+			MethodName: "GetOrder",
+			Handler:    _OrderService_GetOrder_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "quantaflux.proto",
+}
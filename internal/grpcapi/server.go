@@ -0,0 +1,306 @@
+package grpcapi
+
+//go:generate protoc -I proto --go_out=quantafluxpb --go_opt=paths=source_relative --go-grpc_out=quantafluxpb --go-grpc_opt=paths=source_relative quantaflux.proto
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/api"
+	"github.com/songzhibin97/quantaflux/internal/auth"
+	"github.com/songzhibin97/quantaflux/internal/data"
+	pb "github.com/songzhibin97/quantaflux/internal/grpcapi/quantafluxpb"
+	"github.com/songzhibin97/quantaflux/internal/journal"
+	"github.com/songzhibin97/quantaflux/internal/models"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultOrderLimit   = 50
+	defaultHistorySpan  = 24 * time.Hour
+	shutdownGracePeriod = 5 * time.Second
+)
+
+// History 历史行情查询，data.DataStorage 实现了该接口
+type History interface {
+	GetHistoricalData(ctx context.Context, symbol string, start, end time.Time) ([]models.MarketData, error)
+}
+
+// Logger 日志接口
+type Logger interface {
+	Error(msg string, fields ...interface{})
+	Info(msg string, fields ...interface{})
+}
+
+// Server 按 proto/quantaflux.proto 提供只读的 gRPC 接口
+type Server struct {
+	pb.UnimplementedCollectorServiceServer
+	pb.UnimplementedStorageServiceServer
+	pb.UnimplementedRiskServiceServer
+	pb.UnimplementedOrderServiceServer
+
+	addr      string
+	system    api.System
+	collector data.DataCollector // 为空时 CollectorService 不可用
+	history   History
+	journal   journal.TradeJournal
+	auth      *auth.Authenticator
+	logger    Logger
+}
+
+// NewServer creates a new Server instance
+func NewServer(addr string, system api.System, collector data.DataCollector, history History, tradeJournal journal.TradeJournal, logger Logger) *Server {
+	return &Server{
+		addr:      addr,
+		system:    system,
+		collector: collector,
+		history:   history,
+		journal:   tradeJournal,
+		auth:      auth.New(auth.Options{}),
+		logger:    logger,
+	}
+}
+
+// SetAuthenticator 设置认证配置，所有方法按只读角色检查，令牌通过 authorization 元数据传递；
+// 未设置时不需认证
+func (s *Server) SetAuthenticator(authenticator *auth.Authenticator) {
+	s.auth = authenticator
+}
+
+// Register 创建 gRPC 服务并注册所有接口，opts 附加在认证和错误转换拦截器之后
+func (s *Server) Register(opts ...grpc.ServerOption) *grpc.Server {
+	srv := grpc.NewServer(append([]grpc.ServerOption{grpc.ChainUnaryInterceptor(s.intercept)}, opts...)...)
+	pb.RegisterCollectorServiceServer(srv, s)
+	pb.RegisterStorageServiceServer(srv, s)
+	pb.RegisterRiskServiceServer(srv, s)
+	pb.RegisterOrderServiceServer(srv, s)
+	return srv
+}
+
+// Start 启动 gRPC 服务，ctx 取消时优雅关闭，超过宽限期仍未结束的调用被中断
+func (s *Server) Start(ctx context.Context) error {
+	lis, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	srv := s.Register()
+
+	errCh := make(chan error, 1)
+	go func() {
+		s.logger.Info("grpc server listening", "addr", lis.Addr().String())
+		errCh <- srv.Serve(lis)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(shutdownGracePeriod):
+		srv.Stop()
+	}
+	return nil
+}
+
+// intercept 按只读角色认证每次调用，并将方法返回的错误转换为 gRPC 状态
+func (s *Server) intercept(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	var token string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			token, _ = strings.CutPrefix(values[0], "Bearer ")
+		}
+	}
+	if _, err := s.auth.Check(token, auth.RoleRead, clientAddr(ctx)); err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp, err := handler(ctx, req)
+	if err != nil {
+		err = toStatus(err)
+		if status.Code(err) == codes.Internal {
+			s.logger.Error("grpc call failed", "method", info.FullMethod, "err", err)
+		}
+	}
+	return resp, err
+}
+
+// clientAddr 返回客户端 IP，用于未认证请求的限流
+func clientAddr(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// toStatus 将方法返回的错误转换为 gRPC 状态，已是状态的错误原样返回
+func toStatus(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	code := codes.Internal
+	switch {
+	case errors.Is(err, data.ErrNotFound), errors.Is(err, api.ErrAccountNotFound):
+		code = codes.NotFound
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	case errors.Is(err, auth.ErrUnauthenticated):
+		code = codes.Unauthenticated
+	case errors.Is(err, auth.ErrPermissionDenied):
+		code = codes.PermissionDenied
+	case errors.Is(err, auth.ErrRateLimited):
+		code = codes.ResourceExhausted
+	}
+	return status.Error(code, err.Error())
+}
+
+// GetMarketData 通过数据源查询交易对的实时行情
+func (s *Server) GetMarketData(ctx context.Context, req *pb.GetMarketDataRequest) (*pb.MarketData, error) {
+	symbol, err := requireSymbol(req.GetSymbol())
+	if err != nil {
+		return nil, err
+	}
+	if s.collector == nil {
+		return nil, status.Error(codes.Unavailable, "collector is not available")
+	}
+	d, err := s.collector.CollectMarketData(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	return toMarketData(d), nil
+}
+
+// GetTokenInfo 通过数据源查询代币信息
+func (s *Server) GetTokenInfo(ctx context.Context, req *pb.GetTokenInfoRequest) (*pb.TokenInfo, error) {
+	symbol, err := requireSymbol(req.GetSymbol())
+	if err != nil {
+		return nil, err
+	}
+	if s.collector == nil {
+		return nil, status.Error(codes.Unavailable, "collector is not available")
+	}
+	info, err := s.collector.CollectTokenInfo(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	return toTokenInfo(info), nil
+}
+
+// GetHistoricalData 未指定 end 时为当前时间，未指定 start 时为 end 之前 24 小时
+func (s *Server) GetHistoricalData(ctx context.Context, req *pb.GetHistoricalDataRequest) (*pb.GetHistoricalDataResponse, error) {
+	symbol, err := requireSymbol(req.GetSymbol())
+	if err != nil {
+		return nil, err
+	}
+	start, err := fromTimestamp("start", req.GetStart())
+	if err != nil {
+		return nil, err
+	}
+	end, err := fromTimestamp("end", req.GetEnd())
+	if err != nil {
+		return nil, err
+	}
+	if end.IsZero() {
+		end = time.Now()
+	}
+	if start.IsZero() {
+		start = end.Add(-defaultHistorySpan)
+	}
+	if !start.Before(end) {
+		return nil, status.Error(codes.InvalidArgument, "start must be before end")
+	}
+
+	history, err := s.history.GetHistoricalData(ctx, symbol, start, end)
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.GetHistoricalDataResponse{Data: make([]*pb.MarketData, len(history))}
+	for i := range history {
+		resp.Data[i] = toMarketData(&history[i])
+	}
+	return resp, nil
+}
+
+// GetRiskSnapshot 查询账户的风险状态，未指定账户时为主账户
+func (s *Server) GetRiskSnapshot(ctx context.Context, req *pb.GetRiskSnapshotRequest) (*pb.RiskSnapshot, error) {
+	state, err := s.system.RiskState(ctx, req.GetAccount())
+	if err != nil {
+		return nil, err
+	}
+	return toRiskSnapshot(state), nil
+}
+
+// ListOrders 按时间倒序返回交易日志中的订单，未指定 limit 时返回最近 50 条
+func (s *Server) ListOrders(ctx context.Context, req *pb.ListOrdersRequest) (*pb.ListOrdersResponse, error) {
+	limit := int(req.GetLimit())
+	if limit < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid limit: %d", limit)
+	}
+	if limit == 0 {
+		limit = defaultOrderLimit
+	}
+
+	entries, err := s.journal.ListTrades(ctx, req.GetSymbol(), limit)
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.ListOrdersResponse{Orders: make([]*pb.OrderEntry, len(entries))}
+	for i := range entries {
+		resp.Orders[i] = toOrderEntry(&entries[i])
+	}
+	return resp, nil
+}
+
+// GetOrder 按订单号查询交易日志中的订单
+func (s *Server) GetOrder(ctx context.Context, req *pb.GetOrderRequest) (*pb.OrderEntry, error) {
+	if req.GetOrderId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
+	entry, err := s.journal.GetTrade(ctx, req.GetAccount(), req.GetSymbol(), req.GetOrderId())
+	if err != nil {
+		return nil, err
+	}
+	return toOrderEntry(entry), nil
+}
+
+func requireSymbol(symbol string) (string, error) {
+	symbol = strings.ToUpper(symbol)
+	if symbol == "" {
+		return "", status.Error(codes.InvalidArgument, "symbol is required")
+	}
+	return symbol, nil
+}
+
+// fromTimestamp 未设置时返回零值时间
+func fromTimestamp(name string, ts *timestamppb.Timestamp) (time.Time, error) {
+	if ts == nil {
+		return time.Time{}, nil
+	}
+	if err := ts.CheckValid(); err != nil {
+		return time.Time{}, status.Errorf(codes.InvalidArgument, "invalid %s: %v", name, err)
+	}
+	return ts.AsTime(), nil
+}
@@ -0,0 +1,238 @@
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/songzhibin97/quantaflux/internal/api"
+	"github.com/songzhibin97/quantaflux/internal/auth"
+	"github.com/songzhibin97/quantaflux/internal/data"
+	pb "github.com/songzhibin97/quantaflux/internal/grpcapi/quantafluxpb"
+	"github.com/songzhibin97/quantaflux/internal/journal"
+	"github.com/songzhibin97/quantaflux/internal/models"
+	"github.com/songzhibin97/quantaflux/internal/risk"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Error(msg string, fields ...interface{}) {}
+func (nopLogger) Info(msg string, fields ...interface{})  {}
+
+// 未实现的方法由嵌入的空接口触发 panic，测试只用到重写的方法
+type fakeSystem struct {
+	api.System
+}
+
+func (fakeSystem) RiskState(ctx context.Context, account string) (*risk.RiskState, error) {
+	if account != "" && account != "main" {
+		return nil, api.ErrAccountNotFound
+	}
+	return &risk.RiskState{
+		Parameters:      risk.RiskParameters{MaxPositionSize: 1000, MaxLeverage: 2},
+		DailyLoss:       12.5,
+		DailyTradeCount: 3,
+		TradingPaused:   true,
+		PausedSymbols:   []string{"ETHUSDT", "SOLUSDT"},
+		StaleSymbols:    []string{"DOGEUSDT"},
+	}, nil
+}
+
+type fakeCollector struct {
+	data.DataCollector
+}
+
+func (fakeCollector) CollectMarketData(ctx context.Context, symbol string) (*models.MarketData, error) {
+	if symbol != "BTCUSDT" {
+		return nil, fmt.Errorf("%w: %s", data.ErrNotFound, symbol)
+	}
+	return &models.MarketData{Symbol: symbol, Price: 65000.5, Volume24h: 1200, Timestamp: time.Unix(1700000000, 500).UTC()}, nil
+}
+
+type fakeHistory struct {
+	start, end time.Time
+}
+
+func (f *fakeHistory) GetHistoricalData(ctx context.Context, symbol string, start, end time.Time) ([]models.MarketData, error) {
+	f.start, f.end = start, end
+	return []models.MarketData{{Symbol: symbol, Price: 1}, {}}, nil
+}
+
+type fakeJournal struct {
+	journal.TradeJournal
+	limit int
+}
+
+func (f *fakeJournal) ListTrades(ctx context.Context, symbol string, limit int) ([]journal.Entry, error) {
+	f.limit = limit
+	return []journal.Entry{{ID: 7, Strategy: "momentum", Order: trading.Order{Symbol: "BTCUSDT", OrderID: "42", Status: "FILLED"}}}, nil
+}
+
+func (f *fakeJournal) GetTrade(ctx context.Context, account, symbol, orderID string) (*journal.Entry, error) {
+	return nil, fmt.Errorf("%w: no journal entry found for order: %s", data.ErrNotFound, orderID)
+}
+
+// newTestClient 通过内存连接启动服务，返回使用官方 gRPC 客户端的连接
+func newTestClient(t *testing.T, s *Server) *grpc.ClientConn {
+	lis := bufconn.Listen(1 << 20)
+	srv := s.Register()
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func newTestServer() (*Server, *fakeHistory, *fakeJournal) {
+	history, tradeJournal := &fakeHistory{}, &fakeJournal{}
+	return NewServer(":0", fakeSystem{}, fakeCollector{}, history, tradeJournal, nopLogger{}), history, tradeJournal
+}
+
+func TestServer_GetMarketData(t *testing.T) {
+	ctx := context.Background()
+	s, _, _ := newTestServer()
+	client := pb.NewCollectorServiceClient(newTestClient(t, s))
+
+	d, err := client.GetMarketData(ctx, &pb.GetMarketDataRequest{Symbol: "btcusdt"})
+	require.NoError(t, err)
+	assert.Equal(t, "BTCUSDT", d.Symbol)
+	assert.Equal(t, 65000.5, d.Price)
+	assert.Equal(t, 1200.0, d.Volume_24H)
+	assert.Equal(t, time.Unix(1700000000, 500).UTC(), d.Timestamp.AsTime())
+
+	_, err = client.GetMarketData(ctx, &pb.GetMarketDataRequest{Symbol: "DOGEUSDT"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "DOGEUSDT")
+
+	_, err = client.GetMarketData(ctx, &pb.GetMarketDataRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, "symbol is required", status.Convert(err).Message())
+}
+
+func TestServer_CollectorUnavailable(t *testing.T) {
+	s := NewServer(":0", fakeSystem{}, nil, &fakeHistory{}, &fakeJournal{}, nopLogger{})
+	client := pb.NewCollectorServiceClient(newTestClient(t, s))
+
+	_, err := client.GetTokenInfo(context.Background(), &pb.GetTokenInfoRequest{Symbol: "BTCUSDT"})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestServer_GetHistoricalData(t *testing.T) {
+	ctx := context.Background()
+	s, history, _ := newTestServer()
+	client := pb.NewStorageServiceClient(newTestClient(t, s))
+
+	end := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	resp, err := client.GetHistoricalData(ctx, &pb.GetHistoricalDataRequest{Symbol: "BTCUSDT", End: timestamppb.New(end)})
+	require.NoError(t, err)
+	assert.Equal(t, end, history.end.UTC())
+	assert.Equal(t, end.Add(-defaultHistorySpan), history.start.UTC())
+	require.Len(t, resp.Data, 2)
+	assert.Equal(t, "BTCUSDT", resp.Data[0].Symbol)
+	assert.Nil(t, resp.Data[1].Timestamp)
+
+	_, err = client.GetHistoricalData(ctx, &pb.GetHistoricalDataRequest{Symbol: "BTCUSDT", Start: timestamppb.New(end), End: timestamppb.New(end.Add(-time.Hour))})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, "start must be before end", status.Convert(err).Message())
+
+	_, err = client.GetHistoricalData(ctx, &pb.GetHistoricalDataRequest{Symbol: "BTCUSDT", Start: &timestamppb.Timestamp{Nanos: -1}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestServer_GetRiskSnapshot(t *testing.T) {
+	ctx := context.Background()
+	s, _, _ := newTestServer()
+	client := pb.NewRiskServiceClient(newTestClient(t, s))
+
+	snapshot, err := client.GetRiskSnapshot(ctx, &pb.GetRiskSnapshotRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, 12.5, snapshot.DailyLoss)
+	assert.Equal(t, int32(3), snapshot.DailyTradeCount)
+	assert.True(t, snapshot.TradingPaused)
+	assert.Equal(t, []string{"ETHUSDT", "SOLUSDT"}, snapshot.PausedSymbols)
+	assert.Equal(t, []string{"DOGEUSDT"}, snapshot.StaleSymbols)
+	assert.Equal(t, 1000.0, snapshot.Parameters.MaxPositionSize)
+	assert.Equal(t, 2.0, snapshot.Parameters.MaxLeverage)
+
+	_, err = client.GetRiskSnapshot(ctx, &pb.GetRiskSnapshotRequest{Account: "sub"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestServer_Orders(t *testing.T) {
+	ctx := context.Background()
+	s, _, tradeJournal := newTestServer()
+	client := pb.NewOrderServiceClient(newTestClient(t, s))
+
+	resp, err := client.ListOrders(ctx, &pb.ListOrdersRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, defaultOrderLimit, tradeJournal.limit)
+	require.Len(t, resp.Orders, 1)
+	assert.Equal(t, int64(7), resp.Orders[0].Id)
+	assert.Equal(t, "momentum", resp.Orders[0].Strategy)
+	assert.Equal(t, "42", resp.Orders[0].Order.OrderId)
+	assert.Equal(t, "FILLED", resp.Orders[0].Order.Status)
+
+	_, err = client.ListOrders(ctx, &pb.ListOrdersRequest{Limit: -1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.GetOrder(ctx, &pb.GetOrderRequest{OrderId: "99"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "99")
+}
+
+func TestServer_Errors(t *testing.T) {
+	ctx := context.Background()
+	s, _, _ := newTestServer()
+	conn := newTestClient(t, s)
+
+	err := conn.Invoke(ctx, "/quantaflux.v1.OrderService/CancelOrder", &pb.GetOrderRequest{}, &pb.OrderEntry{})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+
+	expired, cancel := context.WithTimeout(ctx, -time.Second)
+	defer cancel()
+	_, err = pb.NewRiskServiceClient(conn).GetRiskSnapshot(expired, &pb.GetRiskSnapshotRequest{})
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+}
+
+func TestServer_Auth(t *testing.T) {
+	s, _, _ := newTestServer()
+	s.SetAuthenticator(auth.New(auth.Options{
+		RequireAuth: true,
+		Keys:        []auth.Key{{Name: "grafana", Secret: "read-key", Role: auth.RoleRead, RateLimit: 1}},
+	}))
+	client := pb.NewRiskServiceClient(newTestClient(t, s))
+
+	_, err := client.GetRiskSnapshot(context.Background(), &pb.GetRiskSnapshotRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer read-key")
+	_, err = client.GetRiskSnapshot(ctx, &pb.GetRiskSnapshotRequest{})
+	assert.NoError(t, err)
+	_, err = client.GetRiskSnapshot(ctx, &pb.GetRiskSnapshotRequest{})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestToMarketData_ZeroTimeOmitted(t *testing.T) {
+	// 零值时间不输出字段，客户端按未设置处理
+	b, err := proto.Marshal(toMarketData(&models.MarketData{Symbol: "BTCUSDT"}))
+	require.NoError(t, err)
+	assert.Equal(t, append([]byte{0x0a, 0x07}, "BTCUSDT"...), b)
+}
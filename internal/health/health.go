@@ -0,0 +1,156 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const defaultTimeout = 5 * time.Second
+
+type check struct {
+	name     string
+	fn       CheckFunc
+	liveness bool
+}
+
+// Checker 汇总各依赖的健康检查
+// 存活检查(liveness)用于判断进程是否需要重启，就绪检查(readiness)额外包含外部依赖
+type Checker struct {
+	timeout time.Duration
+
+	mu     sync.RWMutex
+	checks []check
+}
+
+func NewChecker(timeout time.Duration) *Checker {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &Checker{timeout: timeout}
+}
+
+// AddLiveness 注册存活检查，同时计入就绪检查
+func (c *Checker) AddLiveness(name string, fn CheckFunc) {
+	c.add(check{name: name, fn: fn, liveness: true})
+}
+
+// AddReadiness 注册就绪检查
+func (c *Checker) AddReadiness(name string, fn CheckFunc) {
+	c.add(check{name: name, fn: fn})
+}
+
+func (c *Checker) add(chk check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks = append(c.checks, chk)
+}
+
+// Liveness 执行存活检查
+func (c *Checker) Liveness(ctx context.Context) *Report {
+	return c.run(ctx, true)
+}
+
+// Readiness 执行全部检查
+func (c *Checker) Readiness(ctx context.Context) *Report {
+	return c.run(ctx, false)
+}
+
+// run 并发执行检查，每项检查受 timeout 限制
+func (c *Checker) run(ctx context.Context, livenessOnly bool) *Report {
+	c.mu.RLock()
+	checks := make([]check, 0, len(c.checks))
+	for _, chk := range c.checks {
+		if !livenessOnly || chk.liveness {
+			checks = append(checks, chk)
+		}
+	}
+	c.mu.RUnlock()
+
+	report := &Report{
+		Status: StatusOK,
+		Checks: make(map[string]Result, len(checks)),
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, chk := range checks {
+		wg.Add(1)
+		go func(chk check) {
+			defer wg.Done()
+
+			result := c.runCheck(ctx, chk)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[chk.name] = result
+			if result.Status != StatusOK {
+				report.Status = StatusFail
+			}
+		}(chk)
+	}
+	wg.Wait()
+
+	return report
+}
+
+func (c *Checker) runCheck(ctx context.Context, chk check) Result {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	errCh := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				errCh <- fmt.Errorf("check panicked: %v", r)
+			}
+		}()
+		errCh <- chk.fn(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = fmt.Errorf("check timed out: %w", ctx.Err())
+	}
+
+	result := Result{
+		Status:   StatusOK,
+		Duration: time.Since(start).String(),
+	}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// PingCheck 将 Pinger 包装为检查函数
+func PingCheck(p Pinger) CheckFunc {
+	return p.Ping
+}
+
+// FreshnessCheck 在 last 返回的时间距今超过 maxAge 时判定为不健康
+// last 返回零值表示尚未收到数据，启动后 maxAge 内视为健康
+func FreshnessCheck(last func() time.Time, maxAge time.Duration) CheckFunc {
+	started := time.Now()
+	return func(ctx context.Context) error {
+		t := last()
+		if t.IsZero() {
+			if time.Since(started) > maxAge {
+				return fmt.Errorf("no data received since start %s ago", time.Since(started).Round(time.Second))
+			}
+			return nil
+		}
+
+		if age := time.Since(t); age > maxAge {
+			return fmt.Errorf("last data received %s ago, exceeds %s", age.Round(time.Second), maxAge)
+		}
+		return nil
+	}
+}
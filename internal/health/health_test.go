@@ -0,0 +1,56 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecker(t *testing.T) {
+	checker := NewChecker(50 * time.Millisecond)
+	checker.AddLiveness("collector", func(ctx context.Context) error { return nil })
+	checker.AddReadiness("database", func(ctx context.Context) error { return errors.New("connection refused") })
+	checker.AddReadiness("exchange", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	live := checker.Liveness(context.Background())
+	assert.True(t, live.Healthy())
+	assert.Len(t, live.Checks, 1)
+
+	ready := checker.Readiness(context.Background())
+	assert.False(t, ready.Healthy())
+	assert.Len(t, ready.Checks, 3)
+	assert.Equal(t, StatusOK, ready.Checks["collector"].Status)
+	assert.Equal(t, "connection refused", ready.Checks["database"].Error)
+	assert.Equal(t, StatusFail, ready.Checks["exchange"].Status)
+}
+
+func TestFreshnessCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		last    time.Time
+		maxAge  time.Duration
+		wantErr bool
+	}{
+		{"fresh", time.Now(), time.Minute, false},
+		{"stale", time.Now().Add(-2 * time.Minute), time.Minute, true},
+		{"no data within grace period", time.Time{}, time.Minute, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := FreshnessCheck(func() time.Time { return tt.last }, tt.maxAge)
+			err := check(context.Background())
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
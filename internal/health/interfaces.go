@@ -0,0 +1,38 @@
+package health
+
+import (
+	"context"
+)
+
+// Pinger is implemented by dependencies that can report their connectivity
+type Pinger interface {
+	// Ping checks whether the dependency is reachable
+	Ping(ctx context.Context) error
+}
+
+// CheckFunc 单项健康检查，返回 nil 表示健康
+type CheckFunc func(ctx context.Context) error
+
+// 检查结果状态
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// Result 单项检查结果
+type Result struct {
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// Report 健康检查汇总
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Healthy 所有检查均通过时返回 true
+func (r *Report) Healthy() bool {
+	return r.Status == StatusOK
+}
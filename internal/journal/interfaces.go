@@ -0,0 +1,51 @@
+package journal
+
+import (
+	"context"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/ai"
+	"github.com/songzhibin97/quantaflux/internal/models"
+	"github.com/songzhibin97/quantaflux/internal/risk"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+)
+
+// TradeJournal 记录每笔下单及其决策上下文
+// 订单号只在交易所的单个交易对内唯一，订单以 (account, symbol, order_id) 标识
+type TradeJournal interface {
+	// RecordTrade stores a placed order together with its decision context
+	RecordTrade(ctx context.Context, entry *Entry) error
+
+	// ListTrades retrieves the most recent journal entries, optionally filtered by symbol
+	ListTrades(ctx context.Context, symbol string, limit int) ([]Entry, error)
+
+	// ListTradesInRange retrieves journal entries created in a time range, oldest first
+	ListTradesInRange(ctx context.Context, start, end time.Time) ([]Entry, error)
+
+	// GetTrade retrieves the journal entry of a specific order, empty account or symbol matches any
+	GetTrade(ctx context.Context, account, symbol, orderID string) (*Entry, error)
+
+	// ListOpenTrades retrieves journal entries whose orders are not yet in a final state
+	ListOpenTrades(ctx context.Context) ([]Entry, error)
+
+	// UpdateOrderStatus updates the recorded status of an order
+	UpdateOrderStatus(ctx context.Context, account, symbol, orderID, status string) error
+
+	// UpdateOrderFill updates the recorded filled quantity, average fill price, fee and update time
+	// of the order identified by its account, symbol and order ID
+	UpdateOrderFill(ctx context.Context, order trading.Order) error
+}
+
+// Entry 交易日志条目
+type Entry struct {
+	ID              int64                `json:"id"`
+	Strategy        string               `json:"strategy"`
+	Mode            string               `json:"mode"` // 下单时的运行模式，shadow 表示只记录不下单的模拟订单
+	Order           trading.Order        `json:"order"`
+	MarketData      models.MarketData    `json:"market_data"`
+	Prediction      *ai.PricePrediction  `json:"prediction,omitempty"`
+	Sentiment       float64              `json:"sentiment"`
+	ScamProbability float64              `json:"scam_probability"`
+	RiskAssessment  *risk.RiskAssessment `json:"risk_assessment,omitempty"`
+	CreatedAt       time.Time            `json:"created_at"`
+}
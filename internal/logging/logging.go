@@ -0,0 +1,157 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// 日志输出格式
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// 标准输出目标，其他值视为文件路径
+const (
+	OutputStdout = "stdout"
+	OutputStderr = "stderr"
+)
+
+// Options 日志配置
+type Options struct {
+	Level      string            // 默认级别：debug/info/warn/error，为空时为 info
+	Format     string            // 输出格式：json/text，为空时为 json
+	Output     string            // stdout/stderr 或文件路径，为空时为 stdout
+	MaxSizeMB  int               // 日志文件超过该大小时轮转，0 表示不轮转
+	MaxBackups int               // 保留的轮转文件数量，0 表示全部保留
+	Modules    map[string]string // 模块 -> 级别，覆盖默认级别
+	AddSource  bool              // 记录调用位置
+}
+
+// Loggers 按模块分配日志级别的日志工厂，所有模块共用同一输出
+type Loggers struct {
+	handler slog.Handler
+	level   slog.Level
+	modules map[string]slog.Level
+	closer  io.Closer
+}
+
+// ParseLevel 解析日志级别名称，为空时为 info
+func ParseLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("unknown log level %q, expected one of debug, info, warn, error", name)
+	}
+}
+
+// New 根据配置创建日志工厂，输出到文件时需调用 Close
+func New(opts Options) (*Loggers, error) {
+	level, err := ParseLevel(opts.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	modules := make(map[string]slog.Level, len(opts.Modules))
+	for module, name := range opts.Modules {
+		moduleLevel, err := ParseLevel(name)
+		if err != nil {
+			return nil, fmt.Errorf("module %s: %w", module, err)
+		}
+		modules[module] = moduleLevel
+	}
+
+	var (
+		w      io.Writer
+		closer io.Closer
+	)
+	switch opts.Output {
+	case "", OutputStdout:
+		w = os.Stdout
+	case OutputStderr:
+		w = os.Stderr
+	default:
+		file, err := NewRotatingFile(opts.Output, int64(opts.MaxSizeMB)*1024*1024, opts.MaxBackups)
+		if err != nil {
+			return nil, err
+		}
+		w, closer = file, file
+	}
+
+	// 级别由 levelHandler 按模块过滤，底层 handler 接收所有级别
+	handlerOpts := &slog.HandlerOptions{AddSource: opts.AddSource, Level: slog.LevelDebug}
+	var handler slog.Handler
+	switch opts.Format {
+	case "", FormatJSON:
+		handler = slog.NewJSONHandler(w, handlerOpts)
+	case FormatText:
+		handler = slog.NewTextHandler(w, handlerOpts)
+	default:
+		if closer != nil {
+			_ = closer.Close()
+		}
+		return nil, fmt.Errorf("unknown log format %q, expected json or text", opts.Format)
+	}
+
+	return &Loggers{
+		handler: handler,
+		level:   level,
+		modules: modules,
+		closer:  closer,
+	}, nil
+}
+
+// Logger 返回使用默认级别的日志
+func (l *Loggers) Logger() *slog.Logger {
+	return slog.New(&levelHandler{min: l.level, next: l.handler})
+}
+
+// Module 返回模块日志，记录带有 module 字段，未单独配置级别的模块使用默认级别
+func (l *Loggers) Module(name string) *slog.Logger {
+	level, ok := l.modules[name]
+	if !ok {
+		level = l.level
+	}
+	return slog.New(&levelHandler{min: level, next: l.handler}).With("module", name)
+}
+
+// Close 关闭日志文件，输出到标准输出时不做任何事
+func (l *Loggers) Close() error {
+	if l.closer == nil {
+		return nil
+	}
+	return l.closer.Close()
+}
+
+// levelHandler 按最低级别过滤记录
+type levelHandler struct {
+	min  slog.Level
+	next slog.Handler
+}
+
+func (h *levelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.min && h.next.Enabled(ctx, level)
+}
+
+func (h *levelHandler) Handle(ctx context.Context, record slog.Record) error {
+	return h.next.Handle(ctx, record)
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelHandler{min: h.min, next: h.next.WithAttrs(attrs)}
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{min: h.min, next: h.next.WithGroup(name)}
+}
@@ -0,0 +1,72 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggers_ModuleLevels(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quantaflux.log")
+	loggers, err := New(Options{
+		Level:   "warn",
+		Format:  FormatText,
+		Output:  path,
+		Modules: map[string]string{"pipeline": "debug"},
+	})
+	require.NoError(t, err)
+
+	loggers.Logger().Info("root info")
+	loggers.Logger().Warn("root warn")
+	loggers.Module("scheduler").Info("scheduler info")
+	loggers.Module("pipeline").Debug("pipeline debug")
+	require.NoError(t, loggers.Close())
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	out := string(content)
+	assert.NotContains(t, out, "root info")
+	assert.Contains(t, out, "root warn")
+	assert.NotContains(t, out, "scheduler info")
+	assert.Contains(t, out, "pipeline debug")
+	assert.Contains(t, out, "module=pipeline")
+}
+
+func TestNew_InvalidOptions(t *testing.T) {
+	_, err := New(Options{Level: "verbose"})
+	assert.Error(t, err)
+
+	_, err = New(Options{Format: "xml"})
+	assert.Error(t, err)
+
+	_, err = New(Options{Modules: map[string]string{"api": "loud"}})
+	assert.ErrorContains(t, err, "module api")
+}
+
+func TestRotatingFile_Rotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	file, err := NewRotatingFile(path, 10, 2)
+	require.NoError(t, err)
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err := file.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	require.NoError(t, file.Close())
+
+	read := func(name string) string {
+		content, err := os.ReadFile(name)
+		require.NoError(t, err)
+		return strings.TrimSpace(string(content))
+	}
+	assert.Equal(t, "fourth", read(path))
+	assert.Equal(t, "third", read(path+".1"))
+	assert.Equal(t, "second", read(path+".2"))
+	// 超出保留数量的文件被删除
+	_, err = os.Stat(path + ".3")
+	assert.True(t, os.IsNotExist(err))
+}
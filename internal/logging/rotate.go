@@ -0,0 +1,101 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// RotatingFile 按大小轮转的日志文件：当前文件写满后依次重命名为 path.1、path.2 ...，
+// path.1 为最近一次轮转的文件
+type RotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// NewRotatingFile 打开（追加写入）日志文件，maxSize 为 0 时不轮转，maxBackups 为 0 时保留所有轮转文件
+func NewRotatingFile(path string, maxSize int64, maxBackups int) (*RotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	r := &RotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *RotatingFile) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+	r.file, r.size = file, info.Size()
+	return nil
+}
+
+// Write 写入一条日志，写入后超过大小上限时先轮转。单条日志不会被拆分到两个文件
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.maxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// rotate 关闭当前文件，依次后移已有的轮转文件，超出保留数量的删除
+func (r *RotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+
+	last := r.maxBackups
+	if last <= 0 {
+		// 不限数量时找到第一个不存在的编号
+		for last = 1; ; last++ {
+			if _, err := os.Stat(r.backup(last)); os.IsNotExist(err) {
+				break
+			}
+		}
+	} else {
+		_ = os.Remove(r.backup(last))
+	}
+	for i := last - 1; i >= 1; i-- {
+		_ = os.Rename(r.backup(i), r.backup(i+1))
+	}
+	if err := os.Rename(r.path, r.backup(1)); err != nil {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+
+	return r.open()
+}
+
+func (r *RotatingFile) backup(i int) string {
+	return fmt.Sprintf("%s.%d", r.path, i)
+}
+
+// Close 关闭日志文件
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
@@ -0,0 +1,183 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Registry 指标注册表，以 Prometheus 文本格式导出
+type Registry struct {
+	mu      sync.RWMutex
+	vectors []*vector
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// NewCounter 注册一个带标签的计数器，labels 为标签名
+func (r *Registry) NewCounter(name, help string, labels ...string) *Counter {
+	return &Counter{vector: r.register(name, help, "counter", labels)}
+}
+
+// NewGauge 注册一个带标签的仪表盘指标，labels 为标签名
+func (r *Registry) NewGauge(name, help string, labels ...string) *Gauge {
+	return &Gauge{vector: r.register(name, help, "gauge", labels)}
+}
+
+func (r *Registry) register(name, help, kind string, labels []string) *vector {
+	v := &vector{
+		name:   name,
+		help:   help,
+		kind:   kind,
+		labels: labels,
+		values: make(map[string]*series),
+	}
+
+	r.mu.Lock()
+	r.vectors = append(r.vectors, v)
+	r.mu.Unlock()
+	return v
+}
+
+// WriteTo 以 Prometheus 文本格式写出全部指标
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.RLock()
+	vectors := make([]*vector, len(r.vectors))
+	copy(vectors, r.vectors)
+	r.mu.RUnlock()
+
+	var sb strings.Builder
+	for _, v := range vectors {
+		v.write(&sb)
+	}
+	n, err := io.WriteString(w, sb.String())
+	return int64(n), err
+}
+
+// ServeHTTP implements http.Handler
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = r.WriteTo(w)
+}
+
+// Counter 单调递增的计数器，按标签值区分序列，nil 计数器的操作为空操作
+type Counter struct {
+	vector *vector
+}
+
+// Inc 计数加一，values 按注册时的标签顺序给出
+func (c *Counter) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+// Add 计数增加 delta，delta 为负数时忽略
+func (c *Counter) Add(delta float64, values ...string) {
+	if c == nil || delta < 0 {
+		return
+	}
+	c.vector.update(values, func(v float64) float64 { return v + delta })
+}
+
+// Value 返回标签值对应的当前计数
+func (c *Counter) Value(values ...string) float64 {
+	if c == nil {
+		return 0
+	}
+	return c.vector.value(values)
+}
+
+// Gauge 可任意设置的当前值，按标签值区分序列，nil 指标的操作为空操作
+type Gauge struct {
+	vector *vector
+}
+
+// Set 设置标签值对应的当前值
+func (g *Gauge) Set(value float64, values ...string) {
+	if g == nil {
+		return
+	}
+	g.vector.update(values, func(float64) float64 { return value })
+}
+
+// Value 返回标签值对应的当前值
+func (g *Gauge) Value(values ...string) float64 {
+	if g == nil {
+		return 0
+	}
+	return g.vector.value(values)
+}
+
+type series struct {
+	labels []string
+	value  float64
+}
+
+// vector 同名指标按标签值区分的全部序列
+type vector struct {
+	name   string
+	help   string
+	kind   string
+	labels []string
+
+	mu     sync.Mutex
+	values map[string]*series
+}
+
+func (v *vector) update(values []string, fn func(float64) float64) {
+	key := strings.Join(values, "\xff")
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	s, ok := v.values[key]
+	if !ok {
+		s = &series{labels: values}
+		v.values[key] = s
+	}
+	s.value = fn(s.value)
+}
+
+func (v *vector) value(values []string) float64 {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if s, ok := v.values[strings.Join(values, "\xff")]; ok {
+		return s.value
+	}
+	return 0
+}
+
+func (v *vector) write(sb *strings.Builder) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	fmt.Fprintf(sb, "# HELP %s %s\n", v.name, v.help)
+	fmt.Fprintf(sb, "# TYPE %s %s\n", v.name, v.kind)
+
+	keys := make([]string, 0, len(v.values))
+	for key := range v.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		s := v.values[key]
+		sb.WriteString(v.name)
+		if len(v.labels) > 0 {
+			pairs := make([]string, 0, len(v.labels))
+			for i, label := range v.labels {
+				var value string
+				if i < len(s.labels) {
+					value = s.labels[i]
+				}
+				pairs = append(pairs, label+"="+strconv.Quote(value))
+			}
+			sb.WriteString("{" + strings.Join(pairs, ",") + "}")
+		}
+		sb.WriteString(" " + strconv.FormatFloat(s.value, 'g', -1, 64) + "\n")
+	}
+}
@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+	timeouts := registry.NewCounter("quantaflux_stage_timeouts_total", "Ticks whose stage exceeded its latency budget.", "stage", "symbol")
+	ticks := registry.NewCounter("quantaflux_ticks_total", "Processed ticks.")
+
+	timeouts.Inc("ai", "BTCUSDT")
+	timeouts.Inc("ai", "BTCUSDT")
+	timeouts.Add(3, "order", "ETHUSDT")
+	timeouts.Add(-1, "order", "ETHUSDT")
+	ticks.Inc()
+
+	assert.Equal(t, float64(2), timeouts.Value("ai", "BTCUSDT"))
+	assert.Equal(t, float64(3), timeouts.Value("order", "ETHUSDT"))
+	assert.Equal(t, float64(0), timeouts.Value("risk", "BTCUSDT"))
+
+	rec := httptest.NewRecorder()
+	registry.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `# HELP quantaflux_stage_timeouts_total Ticks whose stage exceeded its latency budget.
+# TYPE quantaflux_stage_timeouts_total counter
+quantaflux_stage_timeouts_total{stage="ai",symbol="BTCUSDT"} 2
+quantaflux_stage_timeouts_total{stage="order",symbol="ETHUSDT"} 3
+# HELP quantaflux_ticks_total Processed ticks.
+# TYPE quantaflux_ticks_total counter
+quantaflux_ticks_total 1
+`, rec.Body.String())
+
+	var nilCounter *Counter
+	nilCounter.Inc("ai")
+	assert.Equal(t, float64(0), nilCounter.Value("ai"))
+}
+
+func TestGauge(t *testing.T) {
+	registry := NewRegistry()
+	drift := registry.NewGauge("quantaflux_exchange_clock_offset_seconds", "Local clock minus exchange server time.", "account")
+
+	drift.Set(0.25, "default")
+	drift.Set(-1.5, "default")
+	drift.Set(0.1, "trend")
+
+	assert.Equal(t, -1.5, drift.Value("default"))
+
+	rec := httptest.NewRecorder()
+	registry.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, `# HELP quantaflux_exchange_clock_offset_seconds Local clock minus exchange server time.
+# TYPE quantaflux_exchange_clock_offset_seconds gauge
+quantaflux_exchange_clock_offset_seconds{account="default"} -1.5
+quantaflux_exchange_clock_offset_seconds{account="trend"} 0.1
+`, rec.Body.String())
+
+	var nilGauge *Gauge
+	nilGauge.Set(1)
+	assert.Zero(t, nilGauge.Value())
+}
@@ -36,5 +36,40 @@ type MarketData struct {
 	MarketCap      float64   `json:"market_cap"`
 	PriceChange1h  float64   `json:"price_change_1h"`
 	PriceChange24h float64   `json:"price_change_24h"`
+	Timeframe      string    `json:"timeframe,omitempty"` // 产生该行情的订阅周期，如 1m、1h
+	Source         string    `json:"source,omitempty"`    // 采集该行情的数据源，按多个数据源共识价格生成时为 consensus
 	Timestamp      time.Time `json:"timestamp"`
 }
+
+// SocialSnapshot 某一时刻采集到的原始社交指标和按当时配置计算的社交分数，供回测按时间点查询
+type SocialSnapshot struct {
+	Symbol     string             `json:"symbol"`
+	Metrics    map[string]float64 `json:"metrics"`
+	Score      float64            `json:"score"`                // 社交分数
+	Components map[string]float64 `json:"components,omitempty"` // 参与计分的指标标准化后的值
+	Timestamp  time.Time          `json:"timestamp"`
+}
+
+// EquitySnapshot 账户权益快照
+type EquitySnapshot struct {
+	Equity    float64   `json:"equity"`            // 总权益（计价货币）
+	Cash      float64   `json:"cash"`              // 现金余额
+	Exposure  float64   `json:"exposure"`          // 持仓市值
+	Mode      string    `json:"mode"`              // 运行模式，影子和模拟模式的权益为模拟账户
+	Account   string    `json:"account,omitempty"` // 交易账户，为空表示所有账户的汇总
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Candle K 线，由行情按固定周期聚合
+type Candle struct {
+	Symbol    string        `json:"symbol"`
+	Interval  time.Duration `json:"interval"`
+	Open      float64       `json:"open"`
+	High      float64       `json:"high"`
+	Low       float64       `json:"low"`
+	Close     float64       `json:"close"`
+	Ticks     int           `json:"ticks"`      // 参与聚合的行情数量
+	OpenTime  time.Time     `json:"open_time"`  // 周期开始时间
+	CloseTime time.Time     `json:"close_time"` // 周期结束时间
+	Last      MarketData    `json:"last"`       // 周期内最后一条行情
+}
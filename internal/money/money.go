@@ -0,0 +1,46 @@
+package money
+
+import "github.com/shopspring/decimal"
+
+// FromFloat 按 float64 的最短十进制表示转换为十进制数，记账时数值从这里进入，0.1 即为 0.1
+func FromFloat(v float64) decimal.Decimal {
+	return decimal.NewFromFloat(v)
+}
+
+// Parse 解析交易所返回的十进制字符串，空字符串为 0
+func Parse(s string) (decimal.Decimal, error) {
+	if s == "" {
+		return decimal.Zero, nil
+	}
+	return decimal.NewFromString(s)
+}
+
+// ParseFloat 解析交易所返回的十进制字符串并转换为 float64，无法解析时返回 0
+func ParseFloat(s string) float64 {
+	d, err := Parse(s)
+	if err != nil {
+		return 0
+	}
+	return Float(d)
+}
+
+// Float 将记账结果转换为最接近的 float64，用于存储和对外接口
+func Float(d decimal.Decimal) float64 {
+	f, _ := d.Float64()
+	return f
+}
+
+// Add 按十进制计算 a + b
+func Add(a, b float64) float64 {
+	return Float(FromFloat(a).Add(FromFloat(b)))
+}
+
+// Sub 按十进制计算 a - b
+func Sub(a, b float64) float64 {
+	return Float(FromFloat(a).Sub(FromFloat(b)))
+}
+
+// Mul 按十进制计算 a * b
+func Mul(a, b float64) float64 {
+	return Float(FromFloat(a).Mul(FromFloat(b)))
+}
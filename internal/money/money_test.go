@@ -0,0 +1,36 @@
+package money
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArithmetic(t *testing.T) {
+	assert.Equal(t, 0.3, Add(0.1, 0.2))
+	assert.Equal(t, 0.7, Sub(1, 0.3))
+	assert.Equal(t, 0.0693, Mul(0.33, 0.21))
+
+	// 多次累加不累积误差
+	sum := FromFloat(0)
+	for range 1000 {
+		sum = sum.Add(FromFloat(0.1))
+	}
+	assert.Equal(t, 100.0, Float(sum))
+}
+
+func TestParse(t *testing.T) {
+	d, err := Parse("0.00000150")
+	require.NoError(t, err)
+	assert.Equal(t, "0.0000015", d.String())
+
+	d, err = Parse("")
+	require.NoError(t, err)
+	assert.True(t, d.IsZero())
+
+	_, err = Parse("abc")
+	assert.Error(t, err)
+	assert.Zero(t, ParseFloat("abc"))
+	assert.Equal(t, 65432.1, ParseFloat("65432.10000000"))
+}
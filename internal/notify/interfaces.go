@@ -0,0 +1,28 @@
+package notify
+
+import "context"
+
+// 消息级别
+const (
+	LevelInfo     = "info"
+	LevelWarning  = "warning"
+	LevelCritical = "critical"
+)
+
+// Notifier 向外部渠道推送通知
+type Notifier interface {
+	// Notify delivers a message to the channel
+	Notify(ctx context.Context, msg Message) error
+}
+
+// Message 通知消息
+type Message struct {
+	Title string `json:"title"`
+	Text  string `json:"text"`
+	Level string `json:"level"`
+}
+
+// Logger 日志接口
+type Logger interface {
+	Info(msg string, fields ...interface{})
+}
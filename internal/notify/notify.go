@@ -0,0 +1,50 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Multi 将消息推送到所有渠道，单个渠道失败不影响其他渠道
+type Multi struct {
+	notifiers []Notifier
+}
+
+func NewMulti(notifiers ...Notifier) *Multi {
+	return &Multi{notifiers: notifiers}
+}
+
+// Notify implements Notifier
+func (m *Multi) Notify(ctx context.Context, msg Message) error {
+	var errs []error
+	for _, n := range m.notifiers {
+		if err := n.Notify(ctx, msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// LogNotifier 将通知写入日志，未配置外部渠道时使用
+type LogNotifier struct {
+	logger Logger
+}
+
+func NewLogNotifier(logger Logger) *LogNotifier {
+	return &LogNotifier{logger: logger}
+}
+
+// Notify implements Notifier
+func (l *LogNotifier) Notify(ctx context.Context, msg Message) error {
+	l.logger.Info("notification", "title", msg.Title, "level", msg.Level, "text", msg.Text)
+	return nil
+}
+
+// format 将消息格式化为纯文本
+func format(msg Message) string {
+	if msg.Level == "" || msg.Level == LevelInfo {
+		return fmt.Sprintf("%s\n%s", msg.Title, msg.Text)
+	}
+	return fmt.Sprintf("[%s] %s\n%s", msg.Level, msg.Title, msg.Text)
+}
@@ -0,0 +1,98 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeNotifier struct {
+	messages []Message
+	err      error
+}
+
+func (f *fakeNotifier) Notify(ctx context.Context, msg Message) error {
+	f.messages = append(f.messages, msg)
+	return f.err
+}
+
+func TestMulti(t *testing.T) {
+	failing := &fakeNotifier{err: errors.New("unreachable")}
+	ok := &fakeNotifier{}
+
+	err := NewMulti(failing, ok).Notify(context.Background(), Message{Title: "daily report"})
+	assert.Error(t, err)
+	assert.Len(t, failing.messages, 1)
+	assert.Len(t, ok.messages, 1)
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{name: "delivered", status: http.StatusOK},
+		{name: "rejected", status: http.StatusBadRequest, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body map[string]string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			err := NewWebhookNotifier(server.URL).Notify(context.Background(), Message{Title: "halted", Text: "auth error", Level: LevelCritical})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "[critical] halted\nauth error", body["text"])
+			assert.Equal(t, LevelCritical, body["level"])
+		})
+	}
+}
+
+func TestTelegramNotifier(t *testing.T) {
+	var path string
+	var body map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+	}))
+	defer server.Close()
+
+	notifier := NewTelegramNotifier("token", "42")
+	notifier.endpoint = server.URL
+
+	require.NoError(t, notifier.Notify(context.Background(), Message{Title: "weekly report", Text: "pnl: 10"}))
+	assert.Equal(t, "/bottoken/sendMessage", path)
+	assert.Equal(t, "42", body["chat_id"])
+	assert.Equal(t, "weekly report\npnl: 10", body["text"])
+}
+
+func TestNotifier_RedactsURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	// 连接失败时错误中不包含 URL 里的 token
+	telegram := NewTelegramNotifier("123:secret", "42")
+	telegram.endpoint = server.URL
+	err := telegram.Notify(context.Background(), Message{Title: "halted"})
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "secret")
+
+	err = NewWebhookNotifier(server.URL+"/services/secret").Notify(context.Background(), Message{Title: "halted"})
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "secret")
+}
@@ -0,0 +1,39 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/songzhibin97/quantaflux/internal/utils/request"
+)
+
+const telegramEndpoint = "https://api.telegram.org"
+
+// TelegramNotifier 通过 Telegram Bot 推送通知
+type TelegramNotifier struct {
+	endpoint string
+	token    string
+	chatID   string
+}
+
+func NewTelegramNotifier(token, chatID string) *TelegramNotifier {
+	return &TelegramNotifier{endpoint: telegramEndpoint, token: token, chatID: chatID}
+}
+
+// Notify implements Notifier
+func (t *TelegramNotifier) Notify(ctx context.Context, msg Message) error {
+	resp, err := request.Request.R().
+		SetContext(ctx).
+		SetBody(map[string]string{
+			"chat_id": t.chatID,
+			"text":    format(msg),
+		}).
+		Post(fmt.Sprintf("%s/bot%s/sendMessage", t.endpoint, t.token))
+	if err != nil {
+		return fmt.Errorf("failed to send telegram notification: %w", request.RedactURL(err))
+	}
+	if resp.IsError() {
+		return fmt.Errorf("telegram notification failed: status=%d, body=%s", resp.StatusCode(), resp.String())
+	}
+	return nil
+}
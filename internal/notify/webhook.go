@@ -0,0 +1,36 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/songzhibin97/quantaflux/internal/utils/request"
+)
+
+// WebhookNotifier 以 JSON POST 推送通知，text 字段兼容 Slack incoming webhook
+type WebhookNotifier struct {
+	url string
+}
+
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{url: url}
+}
+
+// Notify implements Notifier
+func (w *WebhookNotifier) Notify(ctx context.Context, msg Message) error {
+	resp, err := request.Request.R().
+		SetContext(ctx).
+		SetBody(map[string]string{
+			"text":  format(msg),
+			"title": msg.Title,
+			"level": msg.Level,
+		}).
+		Post(w.url)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook notification: %w", request.RedactURL(err))
+	}
+	if resp.IsError() {
+		return fmt.Errorf("webhook notification failed: status=%d, body=%s", resp.StatusCode(), resp.String())
+	}
+	return nil
+}
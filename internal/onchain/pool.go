@@ -0,0 +1,211 @@
+package onchain
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/risk"
+)
+
+// ErrPoolNotFound 代币与计价代币之间没有流动性池
+var ErrPoolNotFound = errors.New("liquidity pool not found")
+
+// Uniswap V2 兼容合约的方法选择器
+var (
+	selectorGetPair     = mustDecode("e6a43905") // getPair(address,address)
+	selectorGetReserves = mustDecode("0902f1ac") // getReserves()
+	selectorToken0      = mustDecode("0dfe1681") // token0()
+	selectorTotalSupply = mustDecode("18160ddd") // totalSupply()
+	selectorBalanceOf   = mustDecode("70a08231") // balanceOf(address)
+	selectorDecimals    = mustDecode("313ce567") // decimals()
+)
+
+// BurnAddresses 销毁 LP 代币常用的地址，持有的 LP 视为永久锁定
+var BurnAddresses = []string{
+	"0x0000000000000000000000000000000000000000",
+	"0x000000000000000000000000000000000000dEaD",
+}
+
+// Network 一条链上的 DEX 配置
+type Network struct {
+	Client     *Client
+	Factory    string   // Uniswap V2 兼容的工厂合约地址
+	QuoteToken string   // 池子的计价代币地址，如 WETH、WBNB
+	Lockers    []string // LP 锁仓合约地址，与销毁地址一起计入锁定比例
+}
+
+// PairSource 通过 Uniswap V2 兼容的工厂合约查找代币与计价代币的交易对，读取储备量和 LP 锁定情况
+type PairSource struct {
+	networks map[string]Network
+
+	mu       sync.Mutex
+	pairs    map[string]string // 网络/代币 -> 交易对地址
+	decimals map[string]int    // 网络/代币 -> 精度
+}
+
+func NewPairSource(networks map[string]Network) *PairSource {
+	return &PairSource{
+		networks: networks,
+		pairs:    make(map[string]string),
+		decimals: make(map[string]int),
+	}
+}
+
+// PoolState implements risk.LiquiditySource
+func (p *PairSource) PoolState(ctx context.Context, token risk.LiquidityToken) (*risk.PoolState, error) {
+	network, ok := p.networks[token.Network]
+	if !ok {
+		return nil, fmt.Errorf("network %q is not configured", token.Network)
+	}
+
+	pair, err := p.pair(ctx, network, token)
+	if err != nil {
+		return nil, err
+	}
+
+	token0, err := network.Client.Call(ctx, pair, selectorToken0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token0 of %s: %w", pair, err)
+	}
+	reserves, err := network.Client.Call(ctx, pair, selectorGetReserves)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reserves of %s: %w", pair, err)
+	}
+	if len(reserves) < 64 || len(token0) < 32 {
+		return nil, fmt.Errorf("invalid reserves of %s", pair)
+	}
+
+	// 计价代币的储备量反映可被撤出的流动性
+	quoteReserve := new(big.Int).SetBytes(reserves[32:64])
+	if strings.EqualFold(decodeAddress(token0), network.QuoteToken) {
+		quoteReserve = new(big.Int).SetBytes(reserves[:32])
+	}
+	decimals, err := p.tokenDecimals(ctx, token.Network, network.Client, network.QuoteToken)
+	if err != nil {
+		return nil, err
+	}
+
+	locked, err := p.lockedRatio(ctx, network, pair)
+	if err != nil {
+		return nil, err
+	}
+
+	return &risk.PoolState{
+		Pool:        pair,
+		Liquidity:   scale(quoteReserve, decimals),
+		LockedRatio: locked,
+		Timestamp:   time.Now(),
+	}, nil
+}
+
+// pair 查找并缓存代币与计价代币的交易对地址
+func (p *PairSource) pair(ctx context.Context, network Network, token risk.LiquidityToken) (string, error) {
+	key := token.Network + "/" + strings.ToLower(token.ContractAddress)
+	p.mu.Lock()
+	pair, ok := p.pairs[key]
+	p.mu.Unlock()
+	if ok {
+		return pair, nil
+	}
+
+	data := append(append(clone(selectorGetPair), encodeAddress(token.ContractAddress)...), encodeAddress(network.QuoteToken)...)
+	out, err := network.Client.Call(ctx, network.Factory, data)
+	if err != nil {
+		return "", fmt.Errorf("failed to get pair of %s: %w", token.Symbol, err)
+	}
+	if len(out) < 32 {
+		return "", fmt.Errorf("invalid pair of %s", token.Symbol)
+	}
+	pair = decodeAddress(out)
+	if pair == BurnAddresses[0] {
+		return "", fmt.Errorf("%w: %s on %s", ErrPoolNotFound, token.Symbol, token.Network)
+	}
+
+	p.mu.Lock()
+	p.pairs[key] = pair
+	p.mu.Unlock()
+	return pair, nil
+}
+
+// lockedRatio 计算销毁地址和锁仓合约持有的 LP 代币占总量的比例
+func (p *PairSource) lockedRatio(ctx context.Context, network Network, pair string) (float64, error) {
+	out, err := network.Client.Call(ctx, pair, selectorTotalSupply)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get LP supply of %s: %w", pair, err)
+	}
+	total := new(big.Int).SetBytes(out)
+	if total.Sign() == 0 {
+		return 0, nil
+	}
+
+	locked := new(big.Int)
+	for _, holder := range slices.Concat(BurnAddresses, network.Lockers) {
+		out, err := network.Client.Call(ctx, pair, append(clone(selectorBalanceOf), encodeAddress(holder)...))
+		if err != nil {
+			return 0, fmt.Errorf("failed to get LP balance of %s: %w", holder, err)
+		}
+		locked.Add(locked, new(big.Int).SetBytes(out))
+	}
+
+	ratio, _ := new(big.Rat).SetFrac(locked, total).Float64()
+	return ratio, nil
+}
+
+func (p *PairSource) tokenDecimals(ctx context.Context, networkName string, client *Client, token string) (int, error) {
+	key := networkName + "/" + strings.ToLower(token)
+	p.mu.Lock()
+	decimals, ok := p.decimals[key]
+	p.mu.Unlock()
+	if ok {
+		return decimals, nil
+	}
+
+	out, err := client.Call(ctx, token, selectorDecimals)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get decimals of %s: %w", token, err)
+	}
+	decimals = int(new(big.Int).SetBytes(out).Int64())
+
+	p.mu.Lock()
+	p.decimals[key] = decimals
+	p.mu.Unlock()
+	return decimals, nil
+}
+
+// encodeAddress 按 ABI 规则将地址左侧补零到 32 字节
+func encodeAddress(address string) []byte {
+	raw, _ := hex.DecodeString(strings.TrimPrefix(strings.ToLower(address), "0x"))
+	out := make([]byte, 32)
+	copy(out[32-len(raw):], raw)
+	return out
+}
+
+// decodeAddress 取 32 字节 ABI 编码值的低 20 字节作为地址
+func decodeAddress(word []byte) string {
+	return "0x" + hex.EncodeToString(word[12:32])
+}
+
+func scale(amount *big.Int, decimals int) float64 {
+	f, _ := new(big.Float).SetInt(amount).Float64()
+	return f / math.Pow10(decimals)
+}
+
+func clone(b []byte) []byte {
+	return append([]byte(nil), b...)
+}
+
+func mustDecode(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
@@ -0,0 +1,84 @@
+package onchain
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/songzhibin97/quantaflux/internal/risk"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testFactory = "0x5c69bee701ef814a2b6a3edd4b1652cb9cc5aa6f"
+	testWETH    = "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"
+	testToken   = "0x6982508145454ce325ddbe47a25d4ec3d2311933"
+	testPair    = "0xa43fe16908251ee70ef74718545e4fe6c5ccec9f"
+	testLocker  = "0x663a5c229c09b049e36dcc11a9b0d4a8eb9db214"
+)
+
+func word(v *big.Int) string {
+	return hex.EncodeToString(v.FillBytes(make([]byte, 32)))
+}
+
+func ether(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil))
+}
+
+// setupNode 模拟 EVM 节点，按合约地址和调用数据返回结果
+func setupNode(t *testing.T, results map[string]string) *Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req rpcRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "eth_call", req.Method)
+
+		call := req.Params[0].(map[string]any)
+		key := strings.ToLower(call["to"].(string)) + ":" + strings.TrimPrefix(call["data"].(string), "0x")
+		result, ok := results[key]
+		if !ok {
+			t.Errorf("unexpected call %s", key)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": "0x" + result})
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(server.URL)
+	client.httpClient = resty.NewWithClient(server.Client())
+	return client
+}
+
+func TestPairSource_PoolState(t *testing.T) {
+	addr := func(a string) string { return hex.EncodeToString(encodeAddress(a)) }
+	client := setupNode(t, map[string]string{
+		testFactory + ":e6a43905" + addr(testToken) + addr(testWETH): addr(testPair),
+		testPair + ":0dfe1681": addr(testToken),
+		// 储备量：代币 1e6，WETH 50
+		testPair + ":0902f1ac":                          word(ether(1_000_000)) + word(ether(50)) + word(big.NewInt(0)),
+		testWETH + ":313ce567":                          word(big.NewInt(18)),
+		testPair + ":18160ddd":                          word(ether(100)),
+		testPair + ":70a08231" + addr(BurnAddresses[0]): word(big.NewInt(0)),
+		testPair + ":70a08231" + addr(BurnAddresses[1]): word(ether(60)),
+		testPair + ":70a08231" + addr(testLocker):       word(ether(20)),
+	})
+
+	source := NewPairSource(map[string]Network{
+		"eth": {Client: client, Factory: testFactory, QuoteToken: testWETH, Lockers: []string{testLocker}},
+	})
+
+	pool, err := source.PoolState(context.Background(), risk.LiquidityToken{Symbol: "PEPEUSDT", Network: "eth", ContractAddress: testToken})
+	require.NoError(t, err)
+	assert.Equal(t, testPair, pool.Pool)
+	assert.InDelta(t, 50, pool.Liquidity, 1e-9)
+	assert.InDelta(t, 0.8, pool.LockedRatio, 1e-9)
+
+	_, err = source.PoolState(context.Background(), risk.LiquidityToken{Symbol: "PEPEUSDT", Network: "bsc", ContractAddress: testToken})
+	assert.Error(t, err)
+}
@@ -0,0 +1,75 @@
+package onchain
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/songzhibin97/quantaflux/internal/utils/request"
+)
+
+// Client EVM 节点的 JSON-RPC 客户端，只支持只读的 eth_call
+type Client struct {
+	rpcURL     string
+	httpClient *resty.Client
+	id         atomic.Int64
+}
+
+func NewClient(rpcURL string) *Client {
+	return &Client{rpcURL: rpcURL, httpClient: request.Request}
+}
+
+type rpcRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      int64  `json:"id"`
+	Method  string `json:"method"`
+	Params  []any  `json:"params"`
+}
+
+type rpcResponse struct {
+	Result string `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Call 在最新区块执行合约只读调用，返回 ABI 编码的结果
+func (c *Client) Call(ctx context.Context, to string, data []byte) ([]byte, error) {
+	req := rpcRequest{
+		JSONRPC: "2.0",
+		ID:      c.id.Add(1),
+		Method:  "eth_call",
+		Params: []any{
+			map[string]string{"to": to, "data": "0x" + hex.EncodeToString(data)},
+			"latest",
+		},
+	}
+
+	resp, err := c.httpClient.R().SetContext(ctx).SetBody(req).Post(c.rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode())
+	}
+
+	var result rpcResponse
+	if err := json.Unmarshal(resp.Body(), &result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if result.Error != nil {
+		return nil, fmt.Errorf("rpc error %d: %s", result.Error.Code, result.Error.Message)
+	}
+
+	out, err := hex.DecodeString(strings.TrimPrefix(result.Result, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode call result: %w", err)
+	}
+	return out, nil
+}
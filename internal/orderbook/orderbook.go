@@ -0,0 +1,144 @@
+package orderbook
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// 默认参数
+const (
+	DefaultLimit = 100  // 拉取的档位数量
+	DefaultBand  = 0.01 // 统计中间价上下 1% 内的挂单
+)
+
+// Level 一个价格档位
+type Level struct {
+	Price    float64 `json:"price"`
+	Quantity float64 `json:"quantity"`
+}
+
+// Book 盘口深度快照，买盘按价格从高到低，卖盘按价格从低到高
+type Book struct {
+	Symbol    string    `json:"symbol"`
+	Bids      []Level   `json:"bids"`
+	Asks      []Level   `json:"asks"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Source 提供盘口深度
+type Source interface {
+	// Depth returns up to limit levels on each side of the order book
+	Depth(ctx context.Context, symbol string, limit int) (*Book, error)
+}
+
+// Signal 由盘口深度计算的连续信号
+type Signal struct {
+	Symbol    string  `json:"symbol"`
+	Mid       float64 `json:"mid"`        // 买一和卖一的中间价
+	SpreadBps float64 `json:"spread_bps"` // 买一卖一价差相对中间价的基点数
+	BidDepth  float64 `json:"bid_depth"`  // 中间价下方 band 内买单的名义金额
+	AskDepth  float64 `json:"ask_depth"`  // 中间价上方 band 内卖单的名义金额
+	// Imbalance 买卖盘失衡度 (BidDepth-AskDepth)/(BidDepth+AskDepth)，-1 到 1，为负表示卖盘更厚
+	Imbalance float64 `json:"imbalance"`
+	// AskWall band 内最大一档卖单占卖盘名义金额的比例，越接近 1 表示卖压集中在单个价位
+	AskWall   float64   `json:"ask_wall"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Compute 计算盘口信号，只统计中间价上下 band 比例内的挂单；买盘或卖盘为空时返回错误
+func Compute(book *Book, band float64) (Signal, error) {
+	if len(book.Bids) == 0 || len(book.Asks) == 0 {
+		return Signal{}, fmt.Errorf("empty order book of %s", book.Symbol)
+	}
+	if band <= 0 {
+		band = DefaultBand
+	}
+
+	bestBid, bestAsk := book.Bids[0].Price, book.Asks[0].Price
+	mid := (bestBid + bestAsk) / 2
+	signal := Signal{
+		Symbol:    book.Symbol,
+		Mid:       mid,
+		SpreadBps: (bestAsk - bestBid) / mid * 10000,
+		Timestamp: book.Timestamp,
+	}
+
+	for _, level := range book.Bids {
+		if level.Price < mid*(1-band) {
+			break
+		}
+		signal.BidDepth += level.Price * level.Quantity
+	}
+	var largestAsk float64
+	for _, level := range book.Asks {
+		if level.Price > mid*(1+band) {
+			break
+		}
+		notional := level.Price * level.Quantity
+		signal.AskDepth += notional
+		largestAsk = max(largestAsk, notional)
+	}
+
+	if total := signal.BidDepth + signal.AskDepth; total > 0 {
+		signal.Imbalance = (signal.BidDepth - signal.AskDepth) / total
+	}
+	if signal.AskDepth > 0 {
+		signal.AskWall = largestAsk / signal.AskDepth
+	}
+	return signal, nil
+}
+
+// Tracker 拉取各交易对的盘口深度并保存最近一次计算的信号。可并发使用
+type Tracker struct {
+	source Source
+	limit  int
+	band   float64
+
+	mu      sync.RWMutex
+	signals map[string]Signal
+}
+
+func NewTracker(source Source, limit int, band float64) *Tracker {
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if band <= 0 {
+		band = DefaultBand
+	}
+	return &Tracker{
+		source:  source,
+		limit:   limit,
+		band:    band,
+		signals: make(map[string]Signal),
+	}
+}
+
+// Poll 拉取交易对的盘口深度并更新信号，快照没有时间时使用 now
+func (t *Tracker) Poll(ctx context.Context, symbol string, now time.Time) (Signal, error) {
+	book, err := t.source.Depth(ctx, symbol, t.limit)
+	if err != nil {
+		return Signal{}, fmt.Errorf("failed to fetch order book of %s: %w", symbol, err)
+	}
+	if book.Timestamp.IsZero() {
+		book.Timestamp = now
+	}
+	signal, err := Compute(book, t.band)
+	if err != nil {
+		return Signal{}, err
+	}
+
+	t.mu.Lock()
+	t.signals[symbol] = signal
+	t.mu.Unlock()
+	return signal, nil
+}
+
+// Signal 返回交易对最近一次计算的信号
+func (t *Tracker) Signal(symbol string) (Signal, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	signal, ok := t.signals[symbol]
+	return signal, ok
+}
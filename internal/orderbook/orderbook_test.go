@@ -0,0 +1,72 @@
+package orderbook
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSource struct {
+	book *Book
+	err  error
+}
+
+func (f *fakeSource) Depth(_ context.Context, symbol string, limit int) (*Book, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	book := *f.book
+	book.Symbol = symbol
+	return &book, nil
+}
+
+func TestCompute(t *testing.T) {
+	book := &Book{
+		Symbol: "BTCUSDT",
+		Bids:   []Level{{Price: 99.9, Quantity: 10}, {Price: 99.5, Quantity: 10}, {Price: 90, Quantity: 1000}},
+		Asks:   []Level{{Price: 100.1, Quantity: 10}, {Price: 100.5, Quantity: 50}, {Price: 110, Quantity: 1000}},
+	}
+
+	// band 外的挂单不计入
+	signal, err := Compute(book, 0.01)
+	require.NoError(t, err)
+	assert.InDelta(t, 100, signal.Mid, 1e-9)
+	assert.InDelta(t, 20, signal.SpreadBps, 1e-9)
+	assert.InDelta(t, 1994, signal.BidDepth, 1e-9)
+	assert.InDelta(t, 6026, signal.AskDepth, 1e-9)
+	assert.InDelta(t, (1994.0-6026)/(1994+6026), signal.Imbalance, 1e-9)
+	assert.InDelta(t, 5025.0/6026, signal.AskWall, 1e-9)
+
+	_, err = Compute(&Book{Symbol: "BTCUSDT", Bids: book.Bids}, 0.01)
+	assert.Error(t, err)
+}
+
+func TestTracker_Poll(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	source := &fakeSource{book: &Book{
+		Bids: []Level{{Price: 99, Quantity: 3}},
+		Asks: []Level{{Price: 101, Quantity: 1}},
+	}}
+	tracker := NewTracker(source, 0, 0)
+
+	_, ok := tracker.Signal("BTCUSDT")
+	assert.False(t, ok)
+
+	signal, err := tracker.Poll(ctx, "BTCUSDT", now)
+	require.NoError(t, err)
+	assert.Equal(t, now, signal.Timestamp)
+	assert.InDelta(t, 0.5, signal.Imbalance, 0.01)
+
+	// 拉取失败时保留上一次的信号
+	source.err = errors.New("timeout")
+	_, err = tracker.Poll(ctx, "BTCUSDT", now.Add(time.Minute))
+	assert.Error(t, err)
+	cached, ok := tracker.Signal("BTCUSDT")
+	require.True(t, ok)
+	assert.Equal(t, signal, cached)
+}
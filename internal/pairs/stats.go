@@ -0,0 +1,138 @@
+package pairs
+
+import "math"
+
+// Engle-Granger 两变量（含常数项）协整检验 5% 显著性水平的临界值（MacKinnon 渐近值）
+const engleGrangerCritical = -3.34
+
+// Cointegration 两个价格序列的协整检验结果，残差 = y - Alpha - Beta*x
+type Cointegration struct {
+	Alpha        float64 `json:"alpha"`
+	Beta         float64 `json:"beta"`         // 对冲比例
+	Statistic    float64 `json:"statistic"`    // 残差 Dickey-Fuller 检验的 t 统计量，越小越平稳
+	Cointegrated bool    `json:"cointegrated"` // 统计量低于 5% 临界值
+}
+
+// Correlation 返回两个序列的皮尔逊相关系数，长度不同、少于 2 个样本或方差为 0 时返回 0
+func Correlation(x, y []float64) float64 {
+	if len(x) != len(y) || len(x) < 2 {
+		return 0
+	}
+	mx, my := mean(x), mean(y)
+	var sxy, sxx, syy float64
+	for i := range x {
+		dx, dy := x[i]-mx, y[i]-my
+		sxy += dx * dy
+		sxx += dx * dx
+		syy += dy * dy
+	}
+	if sxx == 0 || syy == 0 {
+		return 0
+	}
+	return sxy / math.Sqrt(sxx*syy)
+}
+
+// RollingCorrelation 返回每个长度为 window 的滑动窗口的相关系数，第 i 个结果对应 [i, i+window) 的样本
+func RollingCorrelation(x, y []float64, window int) []float64 {
+	if len(x) != len(y) || window < 2 || len(x) < window {
+		return nil
+	}
+	result := make([]float64, 0, len(x)-window+1)
+	for i := 0; i+window <= len(x); i++ {
+		result = append(result, Correlation(x[i:i+window], y[i:i+window]))
+	}
+	return result
+}
+
+// Regress 按最小二乘拟合 y = alpha + beta*x，x 方差为 0 时 beta 为 0
+func Regress(x, y []float64) (alpha, beta float64) {
+	if len(x) != len(y) || len(x) == 0 {
+		return 0, 0
+	}
+	mx, my := mean(x), mean(y)
+	var sxy, sxx float64
+	for i := range x {
+		dx := x[i] - mx
+		sxy += dx * (y[i] - my)
+		sxx += dx * dx
+	}
+	if sxx > 0 {
+		beta = sxy / sxx
+	}
+	return my - beta*mx, beta
+}
+
+// ZScore 返回最后一个值相对整个序列均值的标准分数，标准差为 0 时返回 0
+func ZScore(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+	m := mean(values)
+	var ss float64
+	for _, v := range values {
+		ss += (v - m) * (v - m)
+	}
+	std := math.Sqrt(ss / float64(len(values)-1))
+	if std == 0 {
+		return 0
+	}
+	return (values[len(values)-1] - m) / std
+}
+
+// EngleGranger 两步法协整检验：先回归 y = alpha + beta*x，再对残差做 Dickey-Fuller 检验
+func EngleGranger(x, y []float64) Cointegration {
+	alpha, beta := Regress(x, y)
+	residuals := make([]float64, len(y))
+	for i := range y {
+		residuals[i] = y[i] - alpha - beta*x[i]
+	}
+	statistic := dickeyFuller(residuals)
+	return Cointegration{
+		Alpha:        alpha,
+		Beta:         beta,
+		Statistic:    statistic,
+		Cointegrated: statistic < engleGrangerCritical,
+	}
+}
+
+// dickeyFuller 回归 Δs_t = c + γ·s_{t-1}，返回 γ 的 t 统计量，样本不足或无法估计时返回 0
+func dickeyFuller(series []float64) float64 {
+	n := len(series) - 1
+	if n < 3 {
+		return 0
+	}
+	lagged := series[:n]
+	diffs := make([]float64, n)
+	for i := range diffs {
+		diffs[i] = series[i+1] - series[i]
+	}
+
+	c, gamma := Regress(lagged, diffs)
+	ml := mean(lagged)
+	var sse, sxx float64
+	for i := range diffs {
+		e := diffs[i] - c - gamma*lagged[i]
+		sse += e * e
+		sxx += (lagged[i] - ml) * (lagged[i] - ml)
+	}
+	if sxx == 0 {
+		return 0
+	}
+	se := math.Sqrt(sse / float64(n-2) / sxx)
+	if se == 0 {
+		// 残差完全由回归解释，视为极其平稳（γ<0）或完全不平稳
+		if gamma < 0 {
+			return math.Inf(-1)
+		}
+		return 0
+	}
+	return gamma / se
+}
+
+func mean(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
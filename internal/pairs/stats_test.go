@@ -0,0 +1,65 @@
+package pairs
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// randomWalk 生成固定种子的随机游走
+func randomWalk(r *rand.Rand, n int, start float64) []float64 {
+	series := make([]float64, n)
+	series[0] = start
+	for i := 1; i < n; i++ {
+		series[i] = series[i-1] + r.NormFloat64()*0.01
+	}
+	return series
+}
+
+func TestCorrelation(t *testing.T) {
+	x := []float64{1, 2, 3, 4, 5}
+	assert.InDelta(t, 1, Correlation(x, []float64{2, 4, 6, 8, 10}), 1e-9)
+	assert.InDelta(t, -1, Correlation(x, []float64{5, 4, 3, 2, 1}), 1e-9)
+	assert.Zero(t, Correlation(x, []float64{1, 1, 1, 1, 1}))
+	assert.Zero(t, Correlation(x, []float64{1, 2}))
+
+	rolling := RollingCorrelation(x, []float64{1, 2, 3, 2, 1}, 3)
+	require.Len(t, rolling, 3)
+	assert.InDelta(t, 1, rolling[0], 1e-9)
+	assert.InDelta(t, 0, rolling[1], 1e-9)
+	assert.InDelta(t, -1, rolling[2], 1e-9)
+	assert.Nil(t, RollingCorrelation(x, x, 10))
+}
+
+func TestRegressAndZScore(t *testing.T) {
+	alpha, beta := Regress([]float64{1, 2, 3, 4}, []float64{3, 5, 7, 9})
+	assert.InDelta(t, 1, alpha, 1e-9)
+	assert.InDelta(t, 2, beta, 1e-9)
+
+	assert.InDelta(t, 1.161895, ZScore([]float64{1, 2, 3, 4}), 1e-6)
+	assert.Zero(t, ZScore([]float64{2, 2, 2}))
+}
+
+func TestEngleGranger(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	x := randomWalk(r, 300, 4)
+
+	// y 与 x 的残差均值回归
+	y := make([]float64, len(x))
+	var noise float64
+	for i := range x {
+		noise = 0.5*noise + r.NormFloat64()*0.002
+		y[i] = 0.3 + 1.5*x[i] + noise
+	}
+	result := EngleGranger(x, y)
+	assert.True(t, result.Cointegrated, "statistic %f", result.Statistic)
+	assert.InDelta(t, 1.5, result.Beta, 0.05)
+
+	// 两个独立的随机游走不协整
+	independent := EngleGranger(x, randomWalk(r, 300, 4))
+	assert.False(t, independent.Cointegrated, "statistic %f", independent.Statistic)
+	assert.False(t, math.IsNaN(independent.Statistic))
+}
@@ -0,0 +1,217 @@
+package pairs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/songzhibin97/quantaflux/internal/trading"
+)
+
+// 配对交易的动作
+const (
+	ActionOpenLong  = "open_long"  // 做多价差：买入 Y 现货，开 X 合约空单
+	ActionOpenShort = "open_short" // 做空价差：开 Y 合约空单，买入 X 现货
+	ActionClose     = "close"      // 价差回归，平仓
+	ActionStop      = "stop"       // 价差继续偏离超过止损阈值，平仓
+)
+
+// Config 配对交易参数
+type Config struct {
+	Account  string  // 下单账户，记录在现货订单上
+	Y        string  // 被解释的交易对，价差 = ln(Y) - alpha - beta*ln(X)
+	X        string  // 解释交易对
+	Window   int     // 估计对冲比例和价差分布使用的样本数
+	EntryZ   float64 // 价差标准分数的绝对值达到该值时开仓
+	ExitZ    float64 // 持仓后价差标准分数的绝对值回落到该值以内时平仓
+	StopZ    float64 // 持仓后价差标准分数的绝对值超过该值时止损，0 表示不止损
+	Notional float64 // Y 腿的名义金额（计价资产），X 腿按对冲比例缩放
+}
+
+// Signal 一次价格更新后的价差状态和执行的动作
+type Signal struct {
+	ZScore      float64         `json:"z_score"`
+	Correlation float64         `json:"correlation"`
+	Test        Cointegration   `json:"test"`
+	Action      string          `json:"action"`  // 为空时没有交易
+	Spot        []trading.Order `json:"spot"`    // 现货订单
+	Futures     []trading.Order `json:"futures"` // 合约订单
+}
+
+// Strategy 配对交易：两个交易对的对数价格协整时，价差偏离均值超过 EntryZ 个标准差后
+// 买入被低估一侧的现货、开被高估一侧的合约空单，价差回归后平仓。现货不能卖空，空头腿使用合约
+type Strategy struct {
+	config   Config
+	executor trading.TradeExecutor
+	hedger   trading.Hedger
+
+	mu       sync.Mutex
+	prices   map[string]float64
+	ys, xs   []float64 // 对数价格样本，最多保留 Window 个
+	position int       // 1 做多价差，-1 做空价差，0 空仓
+	spotLeg  trading.Order
+	shortLeg string // 合约空单的交易对
+}
+
+// NewStrategy creates a new Strategy instance
+func NewStrategy(config Config, executor trading.TradeExecutor, hedger trading.Hedger) *Strategy {
+	return &Strategy{config: config, executor: executor, hedger: hedger, prices: make(map[string]float64)}
+}
+
+// Symbols 返回配对的两个交易对
+func (s *Strategy) Symbols() (y, x string) {
+	return s.config.Y, s.config.X
+}
+
+// OnPrice 记录交易对的最新价格，两个交易对都有价格后每次更新生成一个样本，
+// 样本达到 Window 个后按价差的标准分数开平仓；与配对无关的交易对返回 nil
+func (s *Strategy) OnPrice(ctx context.Context, symbol string, price float64) (*Signal, error) {
+	if (symbol != s.config.Y && symbol != s.config.X) || price <= 0 {
+		return nil, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prices[symbol] = price
+	py, px := s.prices[s.config.Y], s.prices[s.config.X]
+	if py <= 0 || px <= 0 {
+		return nil, nil
+	}
+	s.ys = append(s.ys, math.Log(py))
+	s.xs = append(s.xs, math.Log(px))
+	if len(s.ys) > s.config.Window {
+		s.ys, s.xs = s.ys[len(s.ys)-s.config.Window:], s.xs[len(s.xs)-s.config.Window:]
+	}
+	if len(s.ys) < s.config.Window {
+		return nil, nil
+	}
+
+	signal := &Signal{Test: EngleGranger(s.xs, s.ys), Correlation: Correlation(s.xs, s.ys)}
+	spreads := make([]float64, len(s.ys))
+	for i := range s.ys {
+		spreads[i] = s.ys[i] - signal.Test.Alpha - signal.Test.Beta*s.xs[i]
+	}
+	signal.ZScore = ZScore(spreads)
+
+	z := math.Abs(signal.ZScore)
+	var err error
+	switch {
+	case s.position == 0 && signal.Test.Cointegrated && signal.Test.Beta > 0 && z >= s.config.EntryZ:
+		signal.Action = ActionOpenLong
+		if signal.ZScore > 0 {
+			signal.Action = ActionOpenShort
+		}
+		err = s.open(ctx, signal, py, px)
+	case s.position != 0 && s.config.StopZ > 0 && z >= s.config.StopZ:
+		signal.Action = ActionStop
+		err = s.close(ctx, signal)
+	case s.position != 0 && z <= s.config.ExitZ:
+		signal.Action = ActionClose
+		err = s.close(ctx, signal)
+	}
+	return signal, err
+}
+
+// open 先开合约空单，再买入另一侧现货；现货买入失败时平掉空单，避免留下单边持仓
+func (s *Strategy) open(ctx context.Context, signal *Signal, py, px float64) error {
+	longSymbol, longPrice := s.config.Y, py
+	shortSymbol, shortAmount := s.config.X, signal.Test.Beta*s.config.Notional/px
+	longAmount := s.config.Notional / py
+	position := 1
+	if signal.Action == ActionOpenShort {
+		longSymbol, longPrice = s.config.X, px
+		longAmount = signal.Test.Beta * s.config.Notional / px
+		shortSymbol, shortAmount = s.config.Y, s.config.Notional/py
+		position = -1
+	}
+
+	short, err := s.hedger.OpenHedge(ctx, shortSymbol, shortAmount)
+	if err != nil {
+		return fmt.Errorf("failed to short %s: %w", shortSymbol, err)
+	}
+	signal.Futures = append(signal.Futures, *short)
+
+	long := trading.Order{Account: s.config.Account, Symbol: longSymbol, Side: "buy", Amount: longAmount, Price: longPrice, OrderType: "market"}
+	err = s.executor.PlaceOrder(ctx, &long)
+	if err == nil && long.ExecutedAmount() <= 0 {
+		err = fmt.Errorf("buy order of %s was not filled, status: %s", longSymbol, long.Status)
+	}
+	if long.OrderID != "" {
+		signal.Spot = append(signal.Spot, long)
+	}
+	if err != nil {
+		err = fmt.Errorf("failed to buy %s: %w", longSymbol, err)
+		unwind, unwindErr := s.hedger.CloseHedge(ctx, shortSymbol)
+		if unwindErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to unwind short %s: %w", shortSymbol, unwindErr))
+		}
+		if unwind != nil {
+			signal.Futures = append(signal.Futures, *unwind)
+		}
+		return err
+	}
+
+	s.position, s.spotLeg, s.shortLeg = position, long, shortSymbol
+	return nil
+}
+
+// close 卖出现货腿并平掉合约空单，两条腿分别执行，失败的一侧保留持仓状态以便下次重试
+func (s *Strategy) close(ctx context.Context, signal *Signal) error {
+	var errs []error
+	if s.spotLeg.ExecutedAmount() > 0 {
+		sell := trading.Order{Account: s.config.Account, Symbol: s.spotLeg.Symbol, Side: "sell", Amount: s.spotLeg.ExecutedAmount(), Price: s.prices[s.spotLeg.Symbol], OrderType: "market"}
+		if err := s.executor.PlaceOrder(ctx, &sell); err != nil {
+			errs = append(errs, fmt.Errorf("failed to sell %s: %w", sell.Symbol, err))
+		} else {
+			signal.Spot = append(signal.Spot, sell)
+			s.spotLeg = trading.Order{}
+		}
+	}
+	if s.shortLeg != "" {
+		order, err := s.hedger.CloseHedge(ctx, s.shortLeg)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to cover %s: %w", s.shortLeg, err))
+		} else {
+			if order != nil {
+				signal.Futures = append(signal.Futures, *order)
+			}
+			s.shortLeg = ""
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	s.position = 0
+	return nil
+}
+
+// State 配对的持仓状态，保存后重启时恢复，避免遗忘未平仓的价差头寸
+type State struct {
+	Position int           `json:"position"`  // 1 做多价差，-1 做空价差，0 空仓
+	SpotLeg  trading.Order `json:"spot_leg"`  // 未卖出的现货腿
+	ShortLeg string        `json:"short_leg"` // 未平仓的合约空单交易对
+}
+
+// State 返回当前的持仓状态
+func (s *Strategy) State() State {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return State{Position: s.position, SpotLeg: s.spotLeg, ShortLeg: s.shortLeg}
+}
+
+// RestoreState 恢复保存的持仓状态，价格样本重新积累
+func (s *Strategy) RestoreState(state State) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.position, s.spotLeg, s.shortLeg = state.Position, state.SpotLeg, state.ShortLeg
+}
+
+// Position 返回当前持仓方向：1 做多价差，-1 做空价差，0 空仓
+func (s *Strategy) Position() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.position
+}
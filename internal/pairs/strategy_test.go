@@ -0,0 +1,99 @@
+package pairs
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/songzhibin97/quantaflux/internal/trading/paper"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStrategy(t *testing.T) {
+	ctx := context.Background()
+	executor := paper.NewPaperExecutor(map[string]float64{"USDT": 10000})
+	strategy := NewStrategy(Config{Account: "pairs", Y: "ETHUSDT", X: "BTCUSDT", Window: 99, EntryZ: 2, ExitZ: 0.5, StopZ: 6, Notional: 1000}, executor, executor)
+
+	r := rand.New(rand.NewSource(2))
+	logX := math.Log(100)
+	var noise float64
+	// tick 推送两个交易对的价格，shock 为 ETH 对数价格的额外偏离
+	tick := func(shock float64) *Signal {
+		logX += r.NormFloat64() * 0.01
+		noise = 0.3*noise + r.NormFloat64()*0.002
+		x, y := math.Exp(logX), math.Exp(0.5+1.2*logX+noise+shock)
+
+		executor.UpdateMarketPrice("BTCUSDT", x)
+		executor.UpdateMarketPrice("ETHUSDT", y)
+		_, err := strategy.OnPrice(ctx, "BTCUSDT", x)
+		require.NoError(t, err)
+		signal, err := strategy.OnPrice(ctx, "ETHUSDT", y)
+		require.NoError(t, err)
+		return signal
+	}
+
+	_, err := strategy.OnPrice(ctx, "SOLUSDT", 10)
+	require.NoError(t, err)
+	// 两个交易对的价格交替更新，样本数为奇数，冲击时 ETH 更新后刚好达到窗口
+	for len(strategy.ys) < 97 {
+		assert.Nil(t, tick(0))
+	}
+
+	// ETH 相对 BTC 被低估：买入 ETH 现货，开 BTC 合约空单
+	signal := tick(-0.03)
+	require.NotNil(t, signal)
+	require.Equal(t, ActionOpenLong, signal.Action, "z-score %f, statistic %f", signal.ZScore, signal.Test.Statistic)
+	assert.True(t, signal.Test.Cointegrated)
+	assert.Greater(t, signal.Correlation, 0.5)
+	require.Len(t, signal.Spot, 1)
+	assert.Equal(t, "ETHUSDT", signal.Spot[0].Symbol)
+	assert.Equal(t, "pairs", signal.Spot[0].Account)
+	assert.InDelta(t, 1000, signal.Spot[0].Amount*signal.Spot[0].Price, 1e-6)
+	require.Len(t, signal.Futures, 1)
+	assert.Equal(t, "BTCUSDT", signal.Futures[0].Symbol)
+	assert.InDelta(t, signal.Test.Beta*1000, signal.Futures[0].Amount*signal.Futures[0].Price, 1e-6)
+	assert.Equal(t, 1, strategy.Position())
+
+	// 重启后恢复持仓状态
+	restored := NewStrategy(strategy.config, executor, executor)
+	restored.RestoreState(strategy.State())
+	assert.Equal(t, 1, restored.Position())
+	assert.Equal(t, "BTCUSDT", restored.State().ShortLeg)
+	assert.Equal(t, "ETHUSDT", restored.State().SpotLeg.Symbol)
+
+	// 价差回归后两条腿一起平仓
+	for i := 0; i < 20 && strategy.Position() != 0; i++ {
+		signal = tick(0)
+	}
+	assert.Equal(t, ActionClose, signal.Action)
+	assert.Equal(t, 0, strategy.Position())
+	require.Len(t, signal.Spot, 1)
+	assert.Equal(t, "sell", signal.Spot[0].Side)
+	hedged, err := executor.HedgeAmount(ctx, "BTCUSDT")
+	require.NoError(t, err)
+	assert.Zero(t, hedged)
+}
+
+func TestStrategy_UnwindOnFailedLeg(t *testing.T) {
+	ctx := context.Background()
+	// 没有计价资产余额，现货买入失败，需要平掉已开的空单
+	executor := paper.NewPaperExecutor(map[string]float64{})
+	strategy := NewStrategy(Config{Y: "ETHUSDT", X: "BTCUSDT", Window: 3, EntryZ: 0.1, Notional: 1000}, executor, executor)
+	strategy.ys = []float64{0, 0}
+	strategy.xs = []float64{0, 0}
+
+	signal := &Signal{Action: ActionOpenShort, Test: Cointegration{Beta: 1}}
+	executor.UpdateMarketPrice("BTCUSDT", 100)
+	executor.UpdateMarketPrice("ETHUSDT", 10)
+	err := strategy.open(ctx, signal, 10, 100)
+	assert.Error(t, err)
+	assert.Equal(t, 0, strategy.Position())
+	require.Len(t, signal.Futures, 2)
+	assert.Equal(t, "buy", signal.Futures[1].Side)
+	hedged, err := executor.HedgeAmount(ctx, "ETHUSDT")
+	require.NoError(t, err)
+	assert.Zero(t, hedged)
+}
@@ -0,0 +1,154 @@
+package params
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/audit"
+)
+
+var (
+	// ErrUnknownParam 参数不在注册表中
+	ErrUnknownParam = errors.New("unknown parameter")
+
+	// ErrNotLoaded 注册表尚未从存储加载，此时修改会覆盖已保存的值
+	ErrNotLoaded = errors.New("parameter registry is not loaded")
+
+	// ErrInvalidValue 参数值未通过配置校验
+	ErrInvalidValue = errors.New("invalid parameter value")
+)
+
+// Change 一次参数修改记录
+type Change struct {
+	ID       int64    `json:"id"`
+	Name     string   `json:"name"`
+	Value    *float64 `json:"value"`    // 修改后的值，为空表示恢复配置文件中的值
+	Previous float64  `json:"previous"` // 修改前生效的值
+	Actor    string   `json:"actor"`    // 修改的发起方
+	Reason   string   `json:"reason"`
+	// CreatedAt 修改时间
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Param 参数的当前状态
+type Param struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Value       float64 `json:"value"`      // 当前生效的值
+	Default     float64 `json:"default"`    // 配置文件中的值
+	Overridden  bool    `json:"overridden"` // 是否在运行时修改过，恢复配置文件的值后为 false
+}
+
+// Store 持久化参数的修改记录，最近一次修改即为参数当前的值
+type Store interface {
+	// AppendParamChange appends a change and sets its ID
+	AppendParamChange(ctx context.Context, change *Change) error
+
+	// LatestParamChanges returns the most recent change of every parameter
+	LatestParamChanges(ctx context.Context) ([]Change, error)
+
+	// ListParamChanges returns up to limit changes of the parameter, all parameters when name is empty, newest first
+	ListParamChanges(ctx context.Context, name string, limit int) ([]Change, error)
+}
+
+// Registry 集中保存运行时修改的阈值：修改先写入存储再生效，每次修改记录发起方、时间和原因。
+// 注册表只保存覆盖值，未修改的参数使用配置文件中的值。可并发使用
+type Registry struct {
+	store Store
+	names []string
+
+	mu        sync.RWMutex
+	overrides map[string]float64
+	loaded    bool
+}
+
+// NewRegistry 创建只接受 names 中参数的注册表
+func NewRegistry(store Store, names []string) *Registry {
+	return &Registry{store: store, names: slices.Sorted(slices.Values(names)), overrides: make(map[string]float64)}
+}
+
+// Load 从存储加载各参数最近一次修改后的值，已不在注册表中的参数忽略
+func (r *Registry) Load(ctx context.Context) error {
+	changes, err := r.store.LatestParamChanges(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load parameters: %w", err)
+	}
+
+	overrides := make(map[string]float64)
+	for _, change := range changes {
+		if change.Value != nil && r.known(change.Name) {
+			overrides[change.Name] = *change.Value
+		}
+	}
+	r.mu.Lock()
+	r.overrides = overrides
+	r.loaded = true
+	r.mu.Unlock()
+	return nil
+}
+
+// Names 返回注册表中的参数名称，按名称排序
+func (r *Registry) Names() []string {
+	return slices.Clone(r.names)
+}
+
+// Overrides 返回运行时修改过的参数值
+func (r *Registry) Overrides() map[string]float64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return maps.Clone(r.overrides)
+}
+
+// Set 保存参数的新值；previous 为修改前生效的值，发起方取自 ctx
+func (r *Registry) Set(ctx context.Context, name string, value, previous float64, reason string, now time.Time) (*Change, error) {
+	return r.save(ctx, &Change{Name: name, Value: &value, Previous: previous, Reason: reason, CreatedAt: now})
+}
+
+// Reset 删除参数的覆盖值，恢复使用配置文件中的值
+func (r *Registry) Reset(ctx context.Context, name string, previous float64, reason string, now time.Time) (*Change, error) {
+	return r.save(ctx, &Change{Name: name, Previous: previous, Reason: reason, CreatedAt: now})
+}
+
+func (r *Registry) save(ctx context.Context, change *Change) (*Change, error) {
+	if !r.known(change.Name) {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownParam, change.Name)
+	}
+	change.Actor = audit.ActorFromContext(ctx)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.loaded {
+		return nil, ErrNotLoaded
+	}
+	if err := r.store.AppendParamChange(ctx, change); err != nil {
+		return nil, fmt.Errorf("failed to save parameter %s: %w", change.Name, err)
+	}
+	if change.Value != nil {
+		r.overrides[change.Name] = *change.Value
+	} else {
+		delete(r.overrides, change.Name)
+	}
+	return change, nil
+}
+
+// History 返回参数的修改记录，name 为空时返回所有参数的，最新的在前
+func (r *Registry) History(ctx context.Context, name string, limit int) ([]Change, error) {
+	if name != "" && !r.known(name) {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownParam, name)
+	}
+	changes, err := r.store.ListParamChanges(ctx, name, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list parameter changes: %w", err)
+	}
+	return changes, nil
+}
+
+func (r *Registry) known(name string) bool {
+	_, ok := slices.BinarySearch(r.names, name)
+	return ok
+}
@@ -0,0 +1,84 @@
+package params
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/audit"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryStore struct {
+	changes []Change
+}
+
+func (m *memoryStore) AppendParamChange(ctx context.Context, change *Change) error {
+	change.ID = int64(len(m.changes) + 1)
+	m.changes = append(m.changes, *change)
+	return nil
+}
+
+func (m *memoryStore) LatestParamChanges(ctx context.Context) ([]Change, error) {
+	latest := make(map[string]Change)
+	for _, change := range m.changes {
+		latest[change.Name] = change
+	}
+	var result []Change
+	for _, change := range latest {
+		result = append(result, change)
+	}
+	return result, nil
+}
+
+func (m *memoryStore) ListParamChanges(ctx context.Context, name string, limit int) ([]Change, error) {
+	var result []Change
+	for i := len(m.changes) - 1; i >= 0 && len(result) < limit; i-- {
+		if name == "" || m.changes[i].Name == name {
+			result = append(result, m.changes[i])
+		}
+	}
+	return result, nil
+}
+
+func TestRegistry(t *testing.T) {
+	ctx := audit.WithActor(context.Background(), "ops")
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := &memoryStore{}
+	registry := NewRegistry(store, []string{"ai.min_confidence", "ai.scam_threshold"})
+
+	_, err := registry.Set(ctx, "ai.min_confidence", 0.8, 0.7, "too many losers", now)
+	require.ErrorIs(t, err, ErrNotLoaded)
+	require.NoError(t, registry.Load(ctx))
+
+	_, err = registry.Set(ctx, "trading.unknown", 1, 0, "", now)
+	require.ErrorIs(t, err, ErrUnknownParam)
+
+	change, err := registry.Set(ctx, "ai.min_confidence", 0.8, 0.7, "too many losers", now)
+	require.NoError(t, err)
+	assert.Equal(t, "ops", change.Actor)
+	assert.Equal(t, 0.7, change.Previous)
+	_, err = registry.Set(ctx, "ai.scam_threshold", 0.5, 0.6, "", now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"ai.min_confidence": 0.8, "ai.scam_threshold": 0.5}, registry.Overrides())
+
+	// 恢复配置文件中的值后不再覆盖
+	_, err = registry.Reset(ctx, "ai.scam_threshold", 0.5, "revert", now.Add(2*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"ai.min_confidence": 0.8}, registry.Overrides())
+
+	// 重新加载后保留修改
+	reloaded := NewRegistry(store, registry.Names())
+	require.NoError(t, reloaded.Load(ctx))
+	assert.Equal(t, registry.Overrides(), reloaded.Overrides())
+
+	history, err := registry.History(ctx, "ai.scam_threshold", 10)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Nil(t, history[0].Value)
+	assert.Equal(t, "revert", history[0].Reason)
+	_, err = registry.History(ctx, "trading.unknown", 10)
+	assert.ErrorIs(t, err, ErrUnknownParam)
+}
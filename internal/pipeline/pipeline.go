@@ -0,0 +1,190 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+
+	"github.com/songzhibin97/quantaflux/internal/models"
+)
+
+// Handler 处理单条行情数据
+type Handler func(ctx context.Context, data models.MarketData) error
+
+// Logger 日志接口
+type Logger interface {
+	Error(msg string, fields ...interface{})
+	Info(msg string, fields ...interface{})
+}
+
+// Pipeline 按交易对分发行情数据：同一交易对的数据在独立 worker 中顺序处理，
+// 所有 worker 共享并发上限，避免单个慢调用阻塞其他交易对
+type Pipeline struct {
+	handler   Handler
+	logger    Logger
+	sem       chan struct{}
+	queueSize int
+	blocking  bool
+	coalesce  bool
+	inline    bool
+
+	mu      sync.Mutex
+	workers map[string]chan models.MarketData
+	wg      sync.WaitGroup
+	closed  bool
+}
+
+// Options 流水线配置
+type Options struct {
+	Concurrency int  // 同时处理的最大数量
+	QueueSize   int  // 每个交易对的缓冲队列长度
+	Blocking    bool // 队列满时阻塞等待而不是丢弃
+	Coalesce    bool // 同一交易对同一周期排队的多条行情中，较早的标记为已被取代，见 Superseded
+	Inline      bool // 在 Dispatch 的调用方中直接处理，不经过队列和 worker，严格按投递顺序处理（回测使用）
+}
+
+type supersededKey struct{}
+
+// Superseded 行情是否已被同一交易对同一周期排队中更新的行情取代。
+// 被取代的行情仍会交给 handler，由 handler 决定跳过哪些阶段（如只保存和撮合，不再分析）
+func Superseded(ctx context.Context) bool {
+	superseded, _ := ctx.Value(supersededKey{}).(bool)
+	return superseded
+}
+
+func New(handler Handler, opts Options, logger Logger) *Pipeline {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 1
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 16
+	}
+
+	return &Pipeline{
+		handler:   handler,
+		logger:    logger,
+		sem:       make(chan struct{}, opts.Concurrency),
+		queueSize: opts.QueueSize,
+		blocking:  opts.Blocking,
+		coalesce:  opts.Coalesce,
+		inline:    opts.Inline,
+		workers:   make(map[string]chan models.MarketData),
+	}
+}
+
+// Dispatch 将行情数据投递到对应交易对的 worker；Inline 时处理完才返回
+func (p *Pipeline) Dispatch(ctx context.Context, data models.MarketData) {
+	if p.inline {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := p.handler(ctx, data); err != nil {
+			p.logger.Error("Error handling market data", "symbol", data.Symbol, "err", err)
+		}
+		return
+	}
+
+	queue := p.queue(ctx, data.Symbol)
+	if queue == nil {
+		return
+	}
+
+	if p.blocking {
+		select {
+		case queue <- data:
+		case <-ctx.Done():
+		}
+		return
+	}
+
+	select {
+	case queue <- data:
+	default:
+		p.logger.Error("worker queue full, dropping market data", "symbol", data.Symbol)
+	}
+}
+
+// Close 停止接收新数据并等待所有 worker 处理完已排队的数据
+func (p *Pipeline) Close() {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		for _, queue := range p.workers {
+			close(queue)
+		}
+	}
+	p.mu.Unlock()
+
+	p.wg.Wait()
+}
+
+func (p *Pipeline) queue(ctx context.Context, symbol string) chan models.MarketData {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil
+	}
+
+	queue, ok := p.workers[symbol]
+	if !ok {
+		queue = make(chan models.MarketData, p.queueSize)
+		p.workers[symbol] = queue
+		p.wg.Add(1)
+		go p.work(ctx, queue)
+	}
+	return queue
+}
+
+func (p *Pipeline) work(ctx context.Context, queue chan models.MarketData) {
+	defer p.wg.Done()
+
+	for data := range queue {
+		batch := []models.MarketData{data}
+		if p.coalesce {
+			batch = p.drain(queue, batch)
+		}
+
+		for i, data := range batch {
+			select {
+			case p.sem <- struct{}{}:
+			case <-ctx.Done():
+				continue
+			}
+
+			handlerCtx := ctx
+			if superseded(data, batch[i+1:]) {
+				handlerCtx = context.WithValue(ctx, supersededKey{}, true)
+			}
+			if err := p.handler(handlerCtx, data); err != nil {
+				p.logger.Error("Error handling market data", "symbol", data.Symbol, "err", err)
+			}
+
+			<-p.sem
+		}
+	}
+}
+
+// drain 取出队列中已排队的全部行情，按到达顺序处理
+func (p *Pipeline) drain(queue chan models.MarketData, batch []models.MarketData) []models.MarketData {
+	for {
+		select {
+		case data, ok := <-queue:
+			if !ok {
+				return batch
+			}
+			batch = append(batch, data)
+		default:
+			return batch
+		}
+	}
+}
+
+// superseded 之后是否还有同一周期的行情。不同周期（如趋势过滤周期）的行情互不取代
+func superseded(data models.MarketData, later []models.MarketData) bool {
+	for _, next := range later {
+		if next.Timeframe == data.Timeframe {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Error(msg string, fields ...interface{}) {}
+func (nopLogger) Info(msg string, fields ...interface{})  {}
+
+func TestPipeline_PerSymbolOrdering(t *testing.T) {
+	var mu sync.Mutex
+	seen := make(map[string][]float64)
+
+	p := New(func(ctx context.Context, data models.MarketData) error {
+		mu.Lock()
+		seen[data.Symbol] = append(seen[data.Symbol], data.Price)
+		mu.Unlock()
+		return nil
+	}, Options{Concurrency: 4, Blocking: true}, nopLogger{})
+
+	ctx := context.Background()
+	for i := 0; i < 50; i++ {
+		p.Dispatch(ctx, models.MarketData{Symbol: "BTCUSDT", Price: float64(i)})
+		p.Dispatch(ctx, models.MarketData{Symbol: "ETHUSDT", Price: float64(i)})
+	}
+	p.Close()
+
+	for _, symbol := range []string{"BTCUSDT", "ETHUSDT"} {
+		assert.Len(t, seen[symbol], 50)
+		for i, price := range seen[symbol] {
+			assert.Equal(t, float64(i), price)
+		}
+	}
+}
+
+func TestPipeline_Inline(t *testing.T) {
+	var seen []string
+	p := New(func(ctx context.Context, data models.MarketData) error {
+		seen = append(seen, data.Symbol)
+		return nil
+	}, Options{Inline: true}, nopLogger{})
+
+	// 处理完才返回，不同交易对之间也按投递顺序处理
+	ctx := context.Background()
+	var want []string
+	for i := 0; i < 20; i++ {
+		symbol := []string{"BTCUSDT", "ETHUSDT", "SOLUSDT"}[i%3]
+		p.Dispatch(ctx, models.MarketData{Symbol: symbol})
+		want = append(want, symbol)
+		assert.Len(t, seen, i+1)
+	}
+	p.Close()
+	assert.Equal(t, want, seen)
+}
+
+func TestPipeline_ConcurrencyLimit(t *testing.T) {
+	var running, maxRunning int32
+
+	p := New(func(ctx context.Context, data models.MarketData) error {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return nil
+	}, Options{Concurrency: 2}, nopLogger{})
+
+	ctx := context.Background()
+	for _, symbol := range []string{"A", "B", "C", "D", "E"} {
+		p.Dispatch(ctx, models.MarketData{Symbol: symbol})
+	}
+	p.Close()
+
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxRunning), int32(2))
+}
+
+func TestPipeline_SlowSymbolDoesNotBlockOthers(t *testing.T) {
+	release := make(chan struct{})
+	done := make(chan string, 1)
+
+	p := New(func(ctx context.Context, data models.MarketData) error {
+		if data.Symbol == "SLOW" {
+			<-release
+			return nil
+		}
+		done <- data.Symbol
+		return nil
+	}, Options{Concurrency: 2}, nopLogger{})
+
+	ctx := context.Background()
+	p.Dispatch(ctx, models.MarketData{Symbol: "SLOW"})
+	p.Dispatch(ctx, models.MarketData{Symbol: "FAST"})
+
+	select {
+	case symbol := <-done:
+		assert.Equal(t, "FAST", symbol)
+	case <-time.After(time.Second):
+		t.Fatal("fast symbol was blocked by slow symbol")
+	}
+
+	close(release)
+	p.Close()
+}
+
+func TestPipeline_CoalesceStaleTicks(t *testing.T) {
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var seen, superseded []float64
+
+	p := New(func(ctx context.Context, data models.MarketData) error {
+		if data.Price == 0 {
+			<-release
+		}
+		mu.Lock()
+		seen = append(seen, data.Price)
+		if Superseded(ctx) {
+			superseded = append(superseded, data.Price)
+		}
+		mu.Unlock()
+		return nil
+	}, Options{Concurrency: 1, Coalesce: true}, nopLogger{})
+
+	ctx := context.Background()
+	// 第一条处理较慢，期间同一交易对排队多条行情
+	p.Dispatch(ctx, models.MarketData{Symbol: "BTCUSDT", Timeframe: "1m", Price: 0})
+	time.Sleep(20 * time.Millisecond)
+	p.Dispatch(ctx, models.MarketData{Symbol: "BTCUSDT", Timeframe: "1m", Price: 1})
+	p.Dispatch(ctx, models.MarketData{Symbol: "BTCUSDT", Timeframe: "1h", Price: 10})
+	p.Dispatch(ctx, models.MarketData{Symbol: "BTCUSDT", Timeframe: "1m", Price: 2})
+	p.Dispatch(ctx, models.MarketData{Symbol: "BTCUSDT", Timeframe: "1m", Price: 3})
+	close(release)
+	p.Close()
+
+	// 每条行情都按顺序交给 handler，执行周期只有最新一条未被取代，趋势周期的行情不被覆盖
+	assert.Equal(t, []float64{0, 1, 10, 2, 3}, seen)
+	assert.Equal(t, []float64{1, 2}, superseded)
+}
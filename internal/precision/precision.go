@@ -0,0 +1,126 @@
+package precision
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/songzhibin97/quantaflux/internal/exchangeinfo"
+)
+
+// DefaultDecimals 交易对精度未知时使用的小数位数，与 Binance 资产精度一致
+const DefaultDecimals = 8
+
+// 按步长取整前加上的容差，避免 0.3/0.1 这类浮点误差导致少取一个步长
+const epsilon = 1e-9
+
+// Source 交易对精度来源，*exchangeinfo.Service 实现了该接口
+type Source interface {
+	Symbol(symbol string) (exchangeinfo.Symbol, bool)
+}
+
+// Rules 交易对的价格和数量精度，步长为 0 时按 DefaultDecimals 位小数处理
+type Rules struct {
+	TickSize         float64 `json:"tick_size"`
+	StepSize         float64 `json:"step_size"`
+	PriceDecimals    int     `json:"price_decimals"`
+	QuantityDecimals int     `json:"quantity_decimals"`
+}
+
+// NewRules 根据价格和数量步长生成精度规则
+func NewRules(tickSize, stepSize float64) Rules {
+	return Rules{
+		TickSize:         tickSize,
+		StepSize:         stepSize,
+		PriceDecimals:    Decimals(tickSize),
+		QuantityDecimals: Decimals(stepSize),
+	}
+}
+
+// Price 将价格四舍五入到价格步长
+func (r Rules) Price(price float64) string {
+	if r.TickSize > 0 {
+		price = math.Round(price/r.TickSize) * r.TickSize
+	}
+	return Format(price, r.PriceDecimals)
+}
+
+// Quantity 将数量向下取整到数量步长，下单数量不会超过可用余额
+func (r Rules) Quantity(quantity float64) string {
+	if r.StepSize > 0 {
+		quantity = math.Floor(quantity/r.StepSize+epsilon) * r.StepSize
+	}
+	return Format(quantity, r.QuantityDecimals)
+}
+
+// Quote 将计价资产金额向下取整到价格精度
+func (r Rules) Quote(amount float64) string {
+	scale := math.Pow10(r.PriceDecimals)
+	return Format(math.Floor(amount*scale+epsilon)/scale, r.PriceDecimals)
+}
+
+// Formatter 按交易所的交易对精度格式化下单参数和展示的数值，每个交易所使用一个实例；
+// 没有精度来源时所有交易对按 DefaultDecimals 位小数处理
+type Formatter struct {
+	source Source
+}
+
+// NewFormatter creates a new Formatter instance
+func NewFormatter(source Source) *Formatter {
+	return &Formatter{source: source}
+}
+
+// Rules 返回交易对的精度规则，交易对不在来源中时使用默认精度
+func (f *Formatter) Rules(symbol string) Rules {
+	if f.source != nil {
+		if info, ok := f.source.Symbol(symbol); ok {
+			return NewRules(info.TickSize, info.StepSize)
+		}
+	}
+	return NewRules(0, 0)
+}
+
+// Price 按交易对的价格步长格式化价格
+func (f *Formatter) Price(symbol string, price float64) string {
+	return f.Rules(symbol).Price(price)
+}
+
+// Quantity 按交易对的数量步长格式化数量
+func (f *Formatter) Quantity(symbol string, quantity float64) string {
+	return f.Rules(symbol).Quantity(quantity)
+}
+
+// Quote 按交易对的价格精度格式化计价资产金额
+func (f *Formatter) Quote(symbol string, amount float64) string {
+	return f.Rules(symbol).Quote(amount)
+}
+
+// Decimals 返回步长的小数位数，如 0.001 为 3、1 为 0，步长不大于 0 时返回 DefaultDecimals
+func Decimals(step float64) int {
+	if step <= 0 {
+		return DefaultDecimals
+	}
+	s := strconv.FormatFloat(step, 'f', -1, 64)
+	_, fraction, ok := strings.Cut(s, ".")
+	if !ok {
+		return 0
+	}
+	return len(fraction)
+}
+
+// Format 按固定小数位数格式化数值并去掉末尾的 0，消除 FormatFloat(v, 'f', -1, 64) 输出的浮点误差
+func Format(v float64, decimals int) string {
+	s := strconv.FormatFloat(v, 'f', decimals, 64)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	if s == "-0" {
+		return "0"
+	}
+	return s
+}
+
+// String 按 DefaultDecimals 位小数格式化数值，用于日志和错误信息
+func String(v float64) string {
+	return Format(v, DefaultDecimals)
+}
@@ -0,0 +1,60 @@
+package precision
+
+import (
+	"testing"
+
+	"github.com/songzhibin97/quantaflux/internal/exchangeinfo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubSource map[string]exchangeinfo.Symbol
+
+func (s stubSource) Symbol(symbol string) (exchangeinfo.Symbol, bool) {
+	info, ok := s[symbol]
+	return info, ok
+}
+
+func TestDecimals(t *testing.T) {
+	assert.Equal(t, 2, Decimals(0.01))
+	assert.Equal(t, 8, Decimals(0.00000001))
+	assert.Equal(t, 0, Decimals(1))
+	assert.Equal(t, 0, Decimals(10))
+	assert.Equal(t, DefaultDecimals, Decimals(0))
+}
+
+func TestFormat(t *testing.T) {
+	assert.Equal(t, "0.3", Format(0.1+0.2, 8))
+	assert.Equal(t, "100", Format(100, 2))
+	assert.Equal(t, "1234.5", Format(1234.5, 2))
+	assert.Equal(t, "0", Format(-0.000000001, 8))
+	assert.Equal(t, "0.00000001", String(0.00000001))
+}
+
+func TestFormatter(t *testing.T) {
+	formatter := NewFormatter(stubSource{
+		"BTCUSDT":  {Symbol: "BTCUSDT", TickSize: 0.01, StepSize: 0.00001},
+		"SHIBUSDT": {Symbol: "SHIBUSDT", TickSize: 0.00000001, StepSize: 1},
+	})
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{name: "price rounded to tick", got: formatter.Price("BTCUSDT", 65432.1049999), want: "65432.1"},
+		{name: "quantity floored to step", got: formatter.Quantity("BTCUSDT", 0.0015299), want: "0.00152"},
+		{name: "quantity without float error", got: formatter.Quantity("BTCUSDT", 0.1+0.2), want: "0.3"},
+		{name: "quote floored to price precision", got: formatter.Quote("BTCUSDT", 99.999), want: "99.99"},
+		{name: "integer step", got: formatter.Quantity("SHIBUSDT", 1234567.89), want: "1234567"},
+		{name: "small tick", got: formatter.Price("SHIBUSDT", 0.0000123456789), want: "0.00001235"},
+		{name: "unknown symbol", got: formatter.Price("DOGEUSDT", 0.123456789), want: "0.12345679"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.got)
+		})
+	}
+
+	assert.Equal(t, "0.3", NewFormatter(nil).Quantity("BTCUSDT", 0.1+0.2))
+}
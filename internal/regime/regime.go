@@ -0,0 +1,110 @@
+package regime
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// 缩放比例变化小于该值时不调整，避免波动率的微小变化频繁记录参数修改
+const minChange = 0.01
+
+// Options 按市场状态缩放风险限额的参数
+type Options struct {
+	VolatilityTarget float64       // 年化波动率超过该值时按 目标/当前 的比例缩小，0 表示不按波动率缩放
+	DrawdownTarget   float64       // 回撤超过该值时按 目标/当前 的比例缩小，0 表示不按回撤缩放
+	MinScale         float64       // 缩放比例下限，默认 0.25
+	RecoveryStep     float64       // 每次恢复时缩放比例最多增加的值，默认 0.1
+	RecoveryInterval time.Duration // 两次调整之间的最短间隔，只限制恢复，默认 1h
+}
+
+// Adjustment 一次缩放比例的调整
+type Adjustment struct {
+	Previous   float64 `json:"previous"`
+	Scale      float64 `json:"scale"`
+	Volatility float64 `json:"volatility"` // 调整时的年化波动率
+	Drawdown   float64 `json:"drawdown"`   // 调整时的权益回撤
+	Reason     string  `json:"reason"`
+}
+
+// Scaler 根据波动率和权益回撤计算风险限额的缩放比例：条件恶化时立即缩小到目标比例，
+// 条件好转后每隔 RecoveryInterval 最多恢复 RecoveryStep，比例始终在 [MinScale, 1] 之间。可并发使用
+type Scaler struct {
+	options Options
+
+	mu         sync.Mutex
+	scale      float64
+	volatility float64
+	drawdown   float64
+	adjusted   time.Time // 上次调整的时间
+}
+
+// NewScaler creates a new Scaler instance
+func NewScaler(options Options) *Scaler {
+	if options.MinScale <= 0 || options.MinScale > 1 {
+		options.MinScale = 0.25
+	}
+	if options.RecoveryStep <= 0 {
+		options.RecoveryStep = 0.1
+	}
+	if options.RecoveryInterval <= 0 {
+		options.RecoveryInterval = time.Hour
+	}
+	return &Scaler{options: options, scale: 1}
+}
+
+// Scale 返回当前的缩放比例，1 表示不缩放
+func (s *Scaler) Scale() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.scale
+}
+
+// ObserveVolatility 记录最新的年化波动率，缩放比例变化时返回调整
+func (s *Scaler) ObserveVolatility(volatility float64, now time.Time) (Adjustment, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.volatility = volatility
+	return s.adjust(now)
+}
+
+// ObserveDrawdown 记录最新的权益回撤比例，缩放比例变化时返回调整
+func (s *Scaler) ObserveDrawdown(drawdown float64, now time.Time) (Adjustment, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.drawdown = drawdown
+	return s.adjust(now)
+}
+
+func (s *Scaler) adjust(now time.Time) (Adjustment, bool) {
+	target, reason := s.target()
+	next := s.scale
+	switch {
+	case target <= s.scale-minChange:
+		next = target
+	case target > s.scale && (target >= s.scale+minChange || target == 1) && now.Sub(s.adjusted) >= s.options.RecoveryInterval:
+		next = min(target, s.scale+s.options.RecoveryStep)
+		reason = fmt.Sprintf("market conditions calmed, recovering toward %.2f", target)
+	default:
+		return Adjustment{}, false
+	}
+
+	adjustment := Adjustment{Previous: s.scale, Scale: next, Volatility: s.volatility, Drawdown: s.drawdown, Reason: reason}
+	s.scale = next
+	s.adjusted = now
+	return adjustment, true
+}
+
+// target 返回当前条件下的目标比例，按波动率和回撤分别计算取较小者；reason 说明限制比例的条件
+func (s *Scaler) target() (float64, string) {
+	target, reason := 1.0, ""
+	if o := s.options.VolatilityTarget; o > 0 && s.volatility > o {
+		target = o / s.volatility
+		reason = fmt.Sprintf("volatility %.2f above target %.2f", s.volatility, o)
+	}
+	if o := s.options.DrawdownTarget; o > 0 && s.drawdown > o && o/s.drawdown < target {
+		target = o / s.drawdown
+		reason = fmt.Sprintf("drawdown %.2f%% above target %.2f%%", s.drawdown*100, o*100)
+	}
+	return max(target, s.options.MinScale), reason
+}
@@ -0,0 +1,50 @@
+package regime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScaler(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	scaler := NewScaler(Options{VolatilityTarget: 0.8, DrawdownTarget: 0.1, MinScale: 0.3, RecoveryStep: 0.2, RecoveryInterval: time.Hour})
+	assert.Equal(t, 1.0, scaler.Scale())
+
+	// 条件正常时不调整
+	_, ok := scaler.ObserveVolatility(0.6, now)
+	assert.False(t, ok)
+
+	// 波动率升高时立即缩小到目标比例
+	adjustment, ok := scaler.ObserveVolatility(1.6, now)
+	require.True(t, ok)
+	assert.Equal(t, 1.0, adjustment.Previous)
+	assert.InDelta(t, 0.5, adjustment.Scale, 1e-9)
+	assert.Contains(t, adjustment.Reason, "volatility")
+
+	// 回撤更严重时以回撤为准，不低于下限
+	adjustment, ok = scaler.ObserveDrawdown(0.5, now.Add(time.Minute))
+	require.True(t, ok)
+	assert.Equal(t, 0.3, adjustment.Scale)
+	assert.Contains(t, adjustment.Reason, "drawdown")
+	_, ok = scaler.ObserveDrawdown(0.6, now.Add(2*time.Minute))
+	assert.False(t, ok)
+
+	// 条件好转后按间隔和步长逐步恢复
+	_, ok = scaler.ObserveDrawdown(0, now.Add(30*time.Minute))
+	assert.False(t, ok)
+	_, ok = scaler.ObserveVolatility(0.5, now.Add(40*time.Minute))
+	assert.False(t, ok)
+	adjustment, ok = scaler.ObserveVolatility(0.5, now.Add(2*time.Hour))
+	require.True(t, ok)
+	assert.InDelta(t, 0.5, adjustment.Scale, 1e-9)
+	_, ok = scaler.ObserveVolatility(0.5, now.Add(150*time.Minute))
+	assert.False(t, ok)
+
+	for i := 3; i <= 5; i++ {
+		scaler.ObserveVolatility(0.5, now.Add(time.Duration(i)*time.Hour))
+	}
+	assert.Equal(t, 1.0, scaler.Scale())
+}
@@ -0,0 +1,28 @@
+package risk
+
+import "github.com/songzhibin97/quantaflux/internal/trading"
+
+// CostModel 交易成本模型：限价单按 maker 费率、市价单按 taker 费率收取手续费，市价单另计预估滑点
+type CostModel struct {
+	MakerFeeRate float64 `json:"maker_fee_rate"`
+	TakerFeeRate float64 `json:"taker_fee_rate"`
+	SlippageBps  float64 `json:"slippage_bps"` // 市价单预估滑点（基点）
+}
+
+// OrderCost 返回订单本身的预估手续费和滑点
+func (m CostModel) OrderCost(order *trading.Order) float64 {
+	value := order.Value()
+	if order.OrderType == "market" {
+		return value * (m.TakerFeeRate + m.SlippageBps/10000)
+	}
+	return value * m.MakerFeeRate
+}
+
+// TradeCost 返回订单的预估交易成本。买入开仓时还需在之后卖出平仓，按市价单计入平仓成本
+func (m CostModel) TradeCost(order *trading.Order) float64 {
+	cost := m.OrderCost(order)
+	if order.Side == "buy" {
+		cost += order.Value() * (m.TakerFeeRate + m.SlippageBps/10000)
+	}
+	return cost
+}
@@ -2,11 +2,19 @@ package risk
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/songzhibin97/quantaflux/internal/trading"
 )
 
+var (
+	// ErrRiskRejected 交易未通过风险检查
+	ErrRiskRejected = errors.New("risk rejected")
+	// ErrInvalidParameters 风险参数不合法
+	ErrInvalidParameters = errors.New("invalid risk parameters")
+)
+
 // RiskManager defines methods for risk management
 type RiskManager interface {
 	// CheckTradeRisk evaluates the risk of a potential trade
@@ -17,15 +25,24 @@ type RiskManager interface {
 
 	// MonitorPositions monitors open positions for risk
 	MonitorPositions(ctx context.Context) (<-chan RiskAlert, error)
+
+	// GetRiskState returns current risk parameters and daily statistics
+	GetRiskState(ctx context.Context) (*RiskState, error)
+}
+
+// StatsRestorer is implemented by risk managers whose daily statistics can be restored from a snapshot
+type StatsRestorer interface {
+	// RestoreDailyStats restores the daily statistics if they belong to the current statistics period
+	RestoreDailyStats(state *RiskState)
 }
 
 // RiskParameters 风险参数配置
 type RiskParameters struct {
-	MaxPositionSize float64 `json:"max_position_size"`
-	MaxLossPerTrade float64 `json:"max_loss_per_trade"`
-	MaxDailyLoss    float64 `json:"max_daily_loss"`
-	MaxLeverage     float64 `json:"max_leverage"`
-	MinLiquidity    float64 `json:"min_liquidity"`
+	MaxPositionSize float64 `json:"max_position_size" yaml:"max_position_size"`
+	MaxLossPerTrade float64 `json:"max_loss_per_trade" yaml:"max_loss_per_trade"`
+	MaxDailyLoss    float64 `json:"max_daily_loss" yaml:"max_daily_loss"`
+	MaxLeverage     float64 `json:"max_leverage" yaml:"max_leverage"`
+	MinLiquidity    float64 `json:"min_liquidity" yaml:"min_liquidity"`
 }
 
 // RiskAssessment 风险评估结果
@@ -34,13 +51,104 @@ type RiskAssessment struct {
 	RiskLevel       float64  `json:"risk_level"`
 	RiskFactors     []string `json:"risk_factors"`
 	Recommendations []string `json:"recommendations"`
+	EstimatedCost   float64  `json:"estimated_cost"` // 预估手续费和滑点，买入时包括之后平仓的成本
+	ValueAtRisk     float64  `json:"value_at_risk"`  // 价格不利变动造成的预估损失
+}
+
+// RiskState 当前风险状态
+type RiskState struct {
+	Parameters      RiskParameters `json:"parameters"`
+	PositionScale   float64        `json:"position_scale"` // 新订单的最大仓位相对 Parameters 的缩放比例
+	DailyLoss       float64        `json:"daily_loss"`
+	DailyVolume     float64        `json:"daily_volume"`
+	DailyTradeCount int            `json:"daily_trade_count"`
+	StatsReset      time.Time      `json:"stats_reset"`
+	TradingPaused   bool           `json:"trading_paused"` // 全局暂停下单
+	PausedSymbols   []string       `json:"paused_symbols"` // 暂停下单的交易对
+	StaleSymbols    []string       `json:"stale_symbols"`  // 行情停滞、恢复前阻止新订单的交易对
+}
+
+// 风险预警的严重程度
+const (
+	SeverityHigh   = "HIGH"   // 熔断：暂停交易对并清仓
+	SeverityMedium = "MEDIUM" // 减仓
+	SeverityLow    = "LOW"    // 只记录
+)
+
+// 持仓监控的预警类型
+const (
+	AlertPositionLoss     = "Position Loss"     // 持仓浮亏超过单笔最大亏损
+	AlertStalePosition    = "Stale Position"    // 持仓时间过长
+	AlertPositionExposure = "Position Exposure" // 单个持仓市值超过最大仓位
+	AlertTotalExposure    = "Total Exposure"    // 全部持仓市值超过敞口上限
+	AlertStopLoss         = "Stop Loss"         // 价格跌破按 ATR 计算的止损价
+)
+
+// PositionScaler 可按市场状态缩放新订单最大仓位的风险管理器，持仓监控仍按原限额检查
+type PositionScaler interface {
+	// SetPositionScale scales MaxPositionSize of new orders, 1 restores the configured limit
+	SetPositionScale(scale float64)
+}
+
+// VolatilitySource 交易对的波动率来源，*volatility.Service 实现了该接口
+type VolatilitySource interface {
+	// Volatility returns the standard deviation of per-candle log returns
+	Volatility(symbol string) (float64, bool)
+	// RelativeATR returns the average true range as a fraction of the latest close
+	RelativeATR(symbol string) (float64, bool)
+}
+
+// Position 账户在交易对上的持仓，成本按平均成本法计算，金额按估值资产计价
+type Position struct {
+	Account       string    `json:"account"`
+	Symbol        string    `json:"symbol"`
+	Amount        float64   `json:"amount"`
+	AvgCost       float64   `json:"avg_cost"`
+	OpenedAt      time.Time `json:"opened_at"` // 本轮持仓开始的时间
+	Price         float64   `json:"price"`     // 最新价格
+	Value         float64   `json:"value"`     // 按最新价格计算的市值
+	UnrealizedPnL float64   `json:"unrealized_pnl"`
+}
+
+// PositionSource 提供账户当前持仓及其最新估值，由交易系统实现
+type PositionSource interface {
+	// OpenPositions returns the open positions of the account valued at the latest prices
+	OpenPositions(ctx context.Context, account string) ([]Position, error)
+}
+
+// MonitorOptions 持仓监控配置
+type MonitorOptions struct {
+	Account          string         // 监控的账户
+	Positions        PositionSource // 持仓来源，为空时不检查持仓
+	Interval         time.Duration  // 检查间隔，默认 15s
+	MaxHoldingPeriod time.Duration  // 持仓超过该时长时预警，0 表示不检查
+	MaxExposure      float64        // 全部持仓市值之和的上限，0 表示不检查
+
+	// 按 ATR 止损：价格低于平均成本 StopATR 个 ATR 时预警平仓，0 或未设置波动率来源时不检查
+	Volatility VolatilitySource
+	StopATR    float64
+}
+
+func (o MonitorOptions) interval() time.Duration {
+	if o.Interval > 0 {
+		return o.Interval
+	}
+	return 15 * time.Second
 }
 
 // RiskAlert 风险预警信息
 type RiskAlert struct {
 	Symbol      string    `json:"symbol"`
 	AlertType   string    `json:"alert_type"`
-	Severity    string    `json:"severity"`
+	Severity    string    `json:"severity"` // SeverityHigh/SeverityMedium/SeverityLow
 	Description string    `json:"description"`
 	Timestamp   time.Time `json:"timestamp"`
 }
+
+// SymbolPerformance 交易对近期平仓表现，用于自动停用判断
+type SymbolPerformance struct {
+	Symbol            string  `json:"symbol"`
+	ConsecutiveLosses int     `json:"consecutive_losses"` // 最近连续亏损的平仓次数
+	RollingPnL        float64 `json:"rolling_pnl"`        // 统计窗口内的已实现盈亏
+	ClosedTrades      int     `json:"closed_trades"`      // 统计窗口内的平仓次数
+}
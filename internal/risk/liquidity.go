@@ -0,0 +1,149 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// 流动性预警类型
+const (
+	AlertLiquidityWithdrawn = "Liquidity Withdrawn" // 池子流动性相对峰值大幅下降
+	AlertLPUnlocked         = "LP Unlocked"         // 锁定或销毁的 LP 代币比例低于阈值
+)
+
+// LiquidityToken 需要监控流动性池的代币
+type LiquidityToken struct {
+	Symbol          string `json:"symbol"`
+	Network         string `json:"network"`
+	ContractAddress string `json:"contract_address"`
+}
+
+// PoolState 代币流动性池的链上状态
+type PoolState struct {
+	Pool        string    `json:"pool"`         // 池子（LP 代币）地址
+	Liquidity   float64   `json:"liquidity"`    // 池中计价代币的数量
+	LockedRatio float64   `json:"locked_ratio"` // 锁定或销毁的 LP 代币占总量的比例
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// LiquiditySource 查询代币流动性池状态的数据源
+type LiquiditySource interface {
+	// PoolState returns the current state of the token's liquidity pool.
+	PoolState(ctx context.Context, token LiquidityToken) (*PoolState, error)
+}
+
+// LiquidityMonitor 定期检查代币的流动性池，流动性被撤出或 LP 解锁时发出 HIGH 级别预警
+type LiquidityMonitor struct {
+	source    LiquiditySource
+	tokens    func() []LiquidityToken // 每次检查时获取监控列表，交易对变化后自动生效
+	maxDrop   float64                 // 流动性相对峰值的最大跌幅，如 0.3 表示下降 30%
+	minLocked float64                 // LP 锁定比例下限，0 表示不检查
+
+	mu     sync.Mutex
+	peaks  map[string]float64   // 各交易对观察到的最高流动性
+	latest map[string]PoolState // 各交易对最近一次检查的池子状态
+}
+
+func NewLiquidityMonitor(source LiquiditySource, tokens func() []LiquidityToken, maxDrop, minLocked float64) *LiquidityMonitor {
+	return &LiquidityMonitor{
+		source:    source,
+		tokens:    tokens,
+		maxDrop:   maxDrop,
+		minLocked: minLocked,
+		peaks:     make(map[string]float64),
+		latest:    make(map[string]PoolState),
+	}
+}
+
+// Check 检查单个代币的流动性池，没有风险时返回 nil
+func (m *LiquidityMonitor) Check(ctx context.Context, token LiquidityToken) (*RiskAlert, error) {
+	pool, err := m.source.PoolState(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get liquidity pool of %s: %w", token.Symbol, err)
+	}
+
+	m.mu.Lock()
+	peak := m.peaks[token.Symbol]
+	if pool.Liquidity > peak {
+		m.peaks[token.Symbol] = pool.Liquidity
+	}
+	m.latest[token.Symbol] = *pool
+	m.mu.Unlock()
+
+	if peak > 0 && m.maxDrop > 0 && pool.Liquidity <= peak*(1-m.maxDrop) {
+		return &RiskAlert{
+			Symbol:    token.Symbol,
+			AlertType: AlertLiquidityWithdrawn,
+			Severity:  SeverityHigh,
+			Description: fmt.Sprintf("liquidity of %s pool %s dropped from %.4f to %.4f (%.1f%%)",
+				token.Symbol, pool.Pool, peak, pool.Liquidity, (1-pool.Liquidity/peak)*100),
+			Timestamp: time.Now(),
+		}, nil
+	}
+
+	if m.minLocked > 0 && pool.LockedRatio < m.minLocked {
+		return &RiskAlert{
+			Symbol:    token.Symbol,
+			AlertType: AlertLPUnlocked,
+			Severity:  SeverityHigh,
+			Description: fmt.Sprintf("only %.1f%% of %s pool %s LP tokens are locked, below %.1f%%",
+				pool.LockedRatio*100, token.Symbol, pool.Pool, m.minLocked*100),
+			Timestamp: time.Now(),
+		}, nil
+	}
+	return nil, nil
+}
+
+// Latest 返回交易对最近一次检查的池子状态，尚未检查时返回 false
+func (m *LiquidityMonitor) Latest(symbol string) (PoolState, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pool, ok := m.latest[symbol]
+	return pool, ok
+}
+
+// Monitor 按间隔检查所有代币，单个代币查询失败时记录为 LOW 级别预警，不影响其他代币
+func (m *LiquidityMonitor) Monitor(ctx context.Context, interval time.Duration) <-chan RiskAlert {
+	alerts := make(chan RiskAlert, 100)
+
+	go func() {
+		defer close(alerts)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			for _, token := range m.tokens() {
+				alert, err := m.Check(ctx, token)
+				if err != nil {
+					alert = &RiskAlert{
+						Symbol:      token.Symbol,
+						AlertType:   "Liquidity Check Failed",
+						Severity:    SeverityLow,
+						Description: err.Error(),
+						Timestamp:   time.Now(),
+					}
+				}
+				if alert == nil {
+					continue
+				}
+
+				select {
+				case alerts <- *alert:
+				default:
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return alerts
+}
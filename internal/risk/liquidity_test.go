@@ -0,0 +1,66 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubLiquidity struct {
+	pools map[string]*PoolState
+}
+
+func (s *stubLiquidity) PoolState(ctx context.Context, token LiquidityToken) (*PoolState, error) {
+	pool, ok := s.pools[token.Symbol]
+	if !ok {
+		return nil, errors.New("pool not found")
+	}
+	return pool, nil
+}
+
+func TestLiquidityMonitor_Check(t *testing.T) {
+	source := &stubLiquidity{pools: map[string]*PoolState{
+		"PEPEUSDT": {Pool: "0xpair", Liquidity: 100, LockedRatio: 0.9},
+	}}
+	token := LiquidityToken{Symbol: "PEPEUSDT", Network: "eth", ContractAddress: "0xtoken"}
+	monitor := NewLiquidityMonitor(source, func() []LiquidityToken { return []LiquidityToken{token} }, 0.3, 0.5)
+	ctx := context.Background()
+
+	_, ok := monitor.Latest("PEPEUSDT")
+	assert.False(t, ok)
+
+	alert, err := monitor.Check(ctx, token)
+	require.NoError(t, err)
+	assert.Nil(t, alert)
+	latest, ok := monitor.Latest("PEPEUSDT")
+	require.True(t, ok)
+	assert.Equal(t, 100.0, latest.Liquidity)
+
+	// 跌幅未超过阈值
+	source.pools["PEPEUSDT"] = &PoolState{Pool: "0xpair", Liquidity: 80, LockedRatio: 0.9}
+	alert, err = monitor.Check(ctx, token)
+	require.NoError(t, err)
+	assert.Nil(t, alert)
+
+	// 相对峰值下降超过 30%
+	source.pools["PEPEUSDT"] = &PoolState{Pool: "0xpair", Liquidity: 60, LockedRatio: 0.9}
+	alert, err = monitor.Check(ctx, token)
+	require.NoError(t, err)
+	require.NotNil(t, alert)
+	assert.Equal(t, AlertLiquidityWithdrawn, alert.AlertType)
+	assert.Equal(t, SeverityHigh, alert.Severity)
+
+	// LP 解锁
+	monitor = NewLiquidityMonitor(source, nil, 0.3, 0.5)
+	source.pools["PEPEUSDT"] = &PoolState{Pool: "0xpair", Liquidity: 60, LockedRatio: 0.1}
+	alert, err = monitor.Check(ctx, token)
+	require.NoError(t, err)
+	require.NotNil(t, alert)
+	assert.Equal(t, AlertLPUnlocked, alert.AlertType)
+
+	_, err = monitor.Check(ctx, LiquidityToken{Symbol: "GONEUSDT"})
+	assert.Error(t, err)
+}
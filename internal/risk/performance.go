@@ -0,0 +1,160 @@
+package risk
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/money"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+
+	"github.com/shopspring/decimal"
+)
+
+type positionKey struct {
+	account string
+	symbol  string
+}
+
+// costBasis 按十进制记账，多次加仓和部分平仓不累积浮点误差
+type costBasis struct {
+	amount   decimal.Decimal
+	avgCost  decimal.Decimal
+	openedAt time.Time // 本轮持仓（从空仓开始）第一笔买入的时间
+}
+
+type closedTrade struct {
+	pnl decimal.Decimal
+	at  time.Time
+}
+
+type symbolStats struct {
+	consecutiveLosses int
+	closed            []closedTrade
+}
+
+// PerformanceTracker 按平均成本法回放成交，跟踪每个交易对的平仓盈亏
+type PerformanceTracker struct {
+	mu        sync.Mutex
+	positions map[positionKey]*costBasis
+	symbols   map[string]*symbolStats
+}
+
+func NewPerformanceTracker() *PerformanceTracker {
+	return &PerformanceTracker{
+		positions: make(map[positionKey]*costBasis),
+		symbols:   make(map[string]*symbolStats),
+	}
+}
+
+// Record 按已成交数量记录一笔成交，部分成交的订单可多次传入每次新增的成交；
+// 卖出平仓时返回已实现盈亏，未成交或买入时 closed 为 false
+func (t *PerformanceTracker) Record(order trading.Order, at time.Time) (pnl float64, closed bool) {
+	amount := order.ExecutedAmount()
+	if amount <= 0 {
+		return 0, false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	filled, price := money.FromFloat(amount), money.FromFloat(order.ExecutedPrice())
+	key := positionKey{account: order.Account, symbol: order.Symbol}
+	pos, ok := t.positions[key]
+	if !ok {
+		pos = &costBasis{}
+		t.positions[key] = pos
+	}
+
+	switch order.Side {
+	case "buy":
+		if !pos.amount.IsPositive() {
+			pos.openedAt = at
+		}
+		total := pos.amount.Add(filled)
+		pos.avgCost = pos.amount.Mul(pos.avgCost).Add(filled.Mul(price)).Div(total)
+		pos.amount = total
+		return 0, false
+	case "sell":
+		// 超出已知持仓的部分没有成本，不计入盈亏
+		matched := decimal.Min(filled, pos.amount)
+		if !matched.IsPositive() {
+			return 0, false
+		}
+		realized := matched.Mul(price.Sub(pos.avgCost))
+		pos.amount = pos.amount.Sub(matched)
+
+		stats := t.stats(order.Symbol)
+		if realized.IsNegative() {
+			stats.consecutiveLosses++
+		} else {
+			stats.consecutiveLosses = 0
+		}
+		stats.closed = append(stats.closed, closedTrade{pnl: realized, at: at})
+		return money.Float(realized), true
+	}
+	return 0, false
+}
+
+// Positions 返回账户当前未平仓的持仓，按交易对排序，Price 和市值由调用方按最新价格计算
+func (t *PerformanceTracker) Positions(account string) []Position {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var positions []Position
+	for key, pos := range t.positions {
+		if key.account != account || !pos.amount.IsPositive() {
+			continue
+		}
+		positions = append(positions, Position{
+			Account:  key.account,
+			Symbol:   key.symbol,
+			Amount:   money.Float(pos.amount),
+			AvgCost:  money.Float(pos.avgCost),
+			OpenedAt: pos.openedAt,
+		})
+	}
+	sort.Slice(positions, func(i, j int) bool { return positions[i].Symbol < positions[j].Symbol })
+	return positions
+}
+
+// Performance 返回交易对的连续亏损次数和 since 之后的已实现盈亏，早于 since 的平仓记录随之丢弃
+func (t *PerformanceTracker) Performance(symbol string, since time.Time) SymbolPerformance {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats := t.stats(symbol)
+	i := 0
+	for i < len(stats.closed) && stats.closed[i].at.Before(since) {
+		i++
+	}
+	stats.closed = stats.closed[i:]
+
+	performance := SymbolPerformance{
+		Symbol:            symbol,
+		ConsecutiveLosses: stats.consecutiveLosses,
+		ClosedTrades:      len(stats.closed),
+	}
+	rolling := decimal.Zero
+	for _, trade := range stats.closed {
+		rolling = rolling.Add(trade.pnl)
+	}
+	performance.RollingPnL = money.Float(rolling)
+	return performance
+}
+
+// Reset 清空交易对的连续亏损和平仓记录，持仓成本保留，用于重新启用交易对
+func (t *PerformanceTracker) Reset(symbol string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.symbols, symbol)
+}
+
+func (t *PerformanceTracker) stats(symbol string) *symbolStats {
+	stats, ok := t.symbols[symbol]
+	if !ok {
+		stats = &symbolStats{}
+		t.symbols[symbol] = stats
+	}
+	return stats
+}
@@ -0,0 +1,97 @@
+package risk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/trading"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPerformanceTracker(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	order := func(side string, amount, price float64) trading.Order {
+		return trading.Order{Account: "default", Symbol: "BTCUSDT", Side: side, Amount: amount, Price: price, Status: "FILLED"}
+	}
+
+	tracker := NewPerformanceTracker()
+
+	tests := []struct {
+		name       string
+		order      trading.Order
+		at         time.Time
+		wantPnL    float64
+		wantClosed bool
+		wantLosses int
+	}{
+		{name: "open position", order: order("buy", 2, 100), at: start, wantClosed: false},
+		{name: "losing close", order: order("sell", 1, 90), at: start.Add(time.Hour), wantPnL: -10, wantClosed: true, wantLosses: 1},
+		{name: "unfilled order ignored", order: trading.Order{Symbol: "BTCUSDT", Side: "sell", Amount: 1, Price: 50, Status: "NEW"}, at: start.Add(2 * time.Hour), wantLosses: 1},
+		{name: "second losing close", order: order("sell", 1, 80), at: start.Add(3 * time.Hour), wantPnL: -20, wantClosed: true, wantLosses: 2},
+		{name: "sell without position ignored", order: order("sell", 1, 80), at: start.Add(4 * time.Hour), wantLosses: 2},
+		{name: "reopen", order: order("buy", 1, 100), at: start.Add(5 * time.Hour), wantLosses: 2},
+		{name: "winning close resets streak", order: order("sell", 1, 130), at: start.Add(6 * time.Hour), wantPnL: 30, wantClosed: true, wantLosses: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pnl, closed := tracker.Record(tt.order, tt.at)
+			assert.InDelta(t, tt.wantPnL, pnl, 1e-9)
+			assert.Equal(t, tt.wantClosed, closed)
+			assert.Equal(t, tt.wantLosses, tracker.Performance("BTCUSDT", start).ConsecutiveLosses)
+		})
+	}
+
+	performance := tracker.Performance("BTCUSDT", start)
+	assert.InDelta(t, 0, performance.RollingPnL, 1e-9)
+	assert.Equal(t, 3, performance.ClosedTrades)
+
+	// 窗口之外的平仓不计入滚动盈亏
+	performance = tracker.Performance("BTCUSDT", start.Add(2*time.Hour))
+	assert.InDelta(t, 10, performance.RollingPnL, 1e-9)
+	assert.Equal(t, 2, performance.ClosedTrades)
+
+	tracker.Reset("BTCUSDT")
+	assert.Equal(t, SymbolPerformance{Symbol: "BTCUSDT"}, tracker.Performance("BTCUSDT", start))
+}
+
+func TestPerformanceTracker_DecimalCost(t *testing.T) {
+	tracker := NewPerformanceTracker()
+	for range 10 {
+		tracker.Record(trading.Order{Account: "default", Symbol: "ETHUSDT", Side: "buy", Amount: 0.1, Price: 0.3, Status: "FILLED"}, time.Now())
+	}
+
+	positions := tracker.Positions("default")
+	require.Len(t, positions, 1)
+	assert.Equal(t, 1.0, positions[0].Amount)
+	assert.Equal(t, 0.3, positions[0].AvgCost)
+
+	pnl, closed := tracker.Record(trading.Order{Account: "default", Symbol: "ETHUSDT", Side: "sell", Amount: 1, Price: 0.6, Status: "FILLED"}, time.Now())
+	assert.True(t, closed)
+	assert.Equal(t, 0.3, pnl)
+	assert.Empty(t, tracker.Positions("default"))
+}
+
+func TestPerformanceTracker_Positions(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracker := NewPerformanceTracker()
+	tracker.Record(trading.Order{Account: "main", Symbol: "ETHUSDT", Side: "buy", Amount: 1, Price: 100, Status: "FILLED"}, start)
+	tracker.Record(trading.Order{Account: "main", Symbol: "ETHUSDT", Side: "buy", Amount: 1, Price: 200, Status: "FILLED"}, start.Add(time.Hour))
+	tracker.Record(trading.Order{Account: "main", Symbol: "BTCUSDT", Side: "buy", Amount: 1, Price: 100, Status: "FILLED"}, start)
+	tracker.Record(trading.Order{Account: "main", Symbol: "BTCUSDT", Side: "sell", Amount: 1, Price: 110, Status: "FILLED"}, start.Add(time.Hour))
+	tracker.Record(trading.Order{Account: "other", Symbol: "ETHUSDT", Side: "buy", Amount: 1, Price: 100, Status: "FILLED"}, start)
+
+	// 加仓不改变开仓时间，已平仓和其他账户的持仓不返回
+	assert.Equal(t, []Position{
+		{Account: "main", Symbol: "ETHUSDT", Amount: 2, AvgCost: 150, OpenedAt: start},
+	}, tracker.Positions("main"))
+
+	// 平仓后重新开仓从新的成交时间计算
+	tracker.Record(trading.Order{Account: "main", Symbol: "BTCUSDT", Side: "buy", Amount: 0.5, Price: 120, Status: "FILLED"}, start.Add(2*time.Hour))
+	positions := tracker.Positions("main")
+	require.Len(t, positions, 2)
+	assert.Equal(t, "BTCUSDT", positions[0].Symbol)
+	assert.Equal(t, start.Add(2*time.Hour), positions[0].OpenedAt)
+}
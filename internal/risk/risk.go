@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/songzhibin97/quantaflux/internal/clock"
 	"github.com/songzhibin97/quantaflux/internal/trading"
 )
 
@@ -18,30 +19,80 @@ type BasicRiskManager struct {
 		tradeCount    int
 	}
 	statsReset time.Time
+	costs      CostModel
+	vaR        VaRModel
+	monitor    MonitorOptions
+	clock      clock.Clock
+	scale      float64 // 新订单使用的最大仓位缩放比例
 }
 
 func NewBasicRiskManager(initialParams RiskParameters) *BasicRiskManager {
 	return &BasicRiskManager{
 		params:     initialParams,
 		statsReset: time.Now(),
+		clock:      clock.Real(),
+		scale:      1,
 	}
 }
 
+// SetClock 设置按天重置统计和持仓监控使用的时钟，默认为系统时钟；需在 MonitorPositions 之前调用
+func (rm *BasicRiskManager) SetClock(c clock.Clock) {
+	rm.paramsMu.Lock()
+	defer rm.paramsMu.Unlock()
+	rm.clock = clock.OrReal(c)
+	rm.statsReset = rm.clock.Now()
+}
+
+// SetMonitor 设置持仓监控的持仓来源和阈值，需在 MonitorPositions 之前调用；未设置持仓来源时只按天重置统计
+func (rm *BasicRiskManager) SetMonitor(options MonitorOptions) {
+	rm.paramsMu.Lock()
+	defer rm.paramsMu.Unlock()
+	rm.monitor = options
+}
+
+// SetCostModel 设置潜在亏损计算使用的交易成本模型，未设置时不计交易成本
+func (rm *BasicRiskManager) SetCostModel(costs CostModel) {
+	rm.paramsMu.Lock()
+	defer rm.paramsMu.Unlock()
+	rm.costs = costs
+}
+
+// SetVaR 设置潜在亏损计算使用的 VaR 模型，未设置时按订单价值的 10% 估计价格不利变动
+func (rm *BasicRiskManager) SetVaR(model VaRModel) {
+	rm.paramsMu.Lock()
+	defer rm.paramsMu.Unlock()
+	rm.vaR = model
+}
+
+// SetPositionScale implements PositionScaler
+func (rm *BasicRiskManager) SetPositionScale(scale float64) {
+	rm.paramsMu.Lock()
+	defer rm.paramsMu.Unlock()
+	rm.scale = scale
+}
+
 func (rm *BasicRiskManager) CheckTradeRisk(ctx context.Context, order *trading.Order) (*RiskAssessment, error) {
 	rm.paramsMu.RLock()
 	params := rm.params
+	params.MaxPositionSize *= rm.scale
+	costs := rm.costs
+	vaR := rm.vaR
 	rm.paramsMu.RUnlock()
 
+	// 计算订单总值、预估的手续费和滑点以及价格不利变动的损失
+	orderValue := order.Value()
+	tradeCost := costs.TradeCost(order)
+	valueAtRisk := orderValue * vaR.AdverseMove(order.Symbol)
+
 	assessment := &RiskAssessment{
 		IsAcceptable:    true,
 		RiskLevel:       0,
 		RiskFactors:     make([]string, 0),
 		Recommendations: make([]string, 0),
+		EstimatedCost:   tradeCost,
+		ValueAtRisk:     valueAtRisk,
 	}
 
-	// 计算订单总值
-	orderValue := order.Amount * order.Price
-
 	// 检查仓位大小 - 这是最主要的风险检查
 	if orderValue > params.MaxPositionSize {
 		assessment.IsAcceptable = false
@@ -52,7 +103,8 @@ func (rm *BasicRiskManager) CheckTradeRisk(ctx context.Context, order *trading.O
 			fmt.Sprintf("Reduce position size below %.2f", params.MaxPositionSize))
 	} else {
 		// 只有在仓位没有超过限制的情况下，才检查潜在亏损
-		potentialLoss := orderValue * 0.1
+		// 潜在亏损包括价格不利变动和开平仓的交易成本
+		potentialLoss := valueAtRisk + tradeCost
 		if order.Side == "buy" && potentialLoss > params.MaxLossPerTrade {
 			assessment.IsAcceptable = false
 			assessment.RiskLevel += 0.25
@@ -64,7 +116,7 @@ func (rm *BasicRiskManager) CheckTradeRisk(ctx context.Context, order *trading.O
 	}
 
 	// 检查当日总亏损限制
-	if rm.dailyStats.totalLoss+orderValue*0.1 > params.MaxDailyLoss {
+	if rm.dailyStats.totalLoss+valueAtRisk+tradeCost > params.MaxDailyLoss {
 		assessment.IsAcceptable = false
 		assessment.RiskLevel += 0.25
 		assessment.RiskFactors = append(assessment.RiskFactors,
@@ -107,7 +159,7 @@ func (rm *BasicRiskManager) CheckTradeRisk(ctx context.Context, order *trading.O
 func (rm *BasicRiskManager) SetRiskParameters(ctx context.Context, params *RiskParameters) error {
 	if params.MaxPositionSize <= 0 || params.MaxLossPerTrade <= 0 ||
 		params.MaxDailyLoss <= 0 || params.MaxLeverage <= 0 || params.MinLiquidity <= 0 {
-		return fmt.Errorf("invalid risk parameters: all values must be positive")
+		return fmt.Errorf("%w: all values must be positive", ErrInvalidParameters)
 	}
 
 	rm.paramsMu.Lock()
@@ -117,50 +169,98 @@ func (rm *BasicRiskManager) SetRiskParameters(ctx context.Context, params *RiskP
 	return nil
 }
 
+func (rm *BasicRiskManager) GetRiskState(ctx context.Context) (*RiskState, error) {
+	rm.paramsMu.RLock()
+	defer rm.paramsMu.RUnlock()
+
+	return &RiskState{
+		Parameters:      rm.params,
+		PositionScale:   rm.scale,
+		DailyLoss:       rm.dailyStats.totalLoss,
+		DailyVolume:     rm.dailyStats.tradingVolume,
+		DailyTradeCount: rm.dailyStats.tradeCount,
+		StatsReset:      rm.statsReset,
+	}, nil
+}
+
+// RestoreDailyStats implements StatsRestorer; 快照中的统计已超过一天时不恢复
+func (rm *BasicRiskManager) RestoreDailyStats(state *RiskState) {
+	if state == nil {
+		return
+	}
+
+	rm.paramsMu.Lock()
+	defer rm.paramsMu.Unlock()
+
+	if rm.clock.Now().Sub(state.StatsReset) >= 24*time.Hour {
+		return
+	}
+
+	rm.dailyStats.totalLoss = state.DailyLoss
+	rm.dailyStats.tradingVolume = state.DailyVolume
+	rm.dailyStats.tradeCount = state.DailyTradeCount
+	rm.statsReset = state.StatsReset
+}
+
+// MonitorPositions 按 MonitorOptions.Interval 从持仓来源加载持仓并按最新价格评估：
+// 浮亏超过 max_loss_per_trade、持仓时间超过 MaxHoldingPeriod、价格跌破 ATR 止损价、单个持仓市值超过 max_position_size
+// 或全部持仓市值超过 MaxExposure 时发出预警，同一持仓的同类预警在恢复之前只发一次
 func (rm *BasicRiskManager) MonitorPositions(ctx context.Context) (<-chan RiskAlert, error) {
 	alerts := make(chan RiskAlert, 100)
 
+	rm.paramsMu.RLock()
+	monitor := rm.monitor
+	now := rm.clock
+	rm.paramsMu.RUnlock()
+
+	// Ticker 在启动 goroutine 之前创建，模拟时钟推进时不会错过触发
+	ticker := now.NewTicker(monitor.interval())
+	dayReset := now.NewTicker(24 * time.Hour)
+
 	go func() {
 		defer close(alerts)
-
-		ticker := time.NewTicker(15 * time.Second)
 		defer ticker.Stop()
-
-		dayReset := time.NewTicker(24 * time.Hour)
 		defer dayReset.Stop()
 
+		// 正在预警的持仓：预警类型 + 交易对
+		active := make(map[string]bool)
 		for {
 			select {
 			case <-ctx.Done():
 				return
 
-			case <-dayReset.C:
+			case <-dayReset.C():
 				rm.paramsMu.Lock()
 				rm.dailyStats.totalLoss = 0
 				rm.dailyStats.tradingVolume = 0
 				rm.dailyStats.tradeCount = 0
-				rm.statsReset = time.Now()
+				rm.statsReset = now.Now()
 				rm.paramsMu.Unlock()
 
-			case <-ticker.C:
-				positions := rm.getCurrentPositions()
-				for _, pos := range positions {
-					if pos.UnrealizedPnL < -rm.params.MaxLossPerTrade {
-						alert := RiskAlert{
-							Symbol:      pos.Symbol,
-							AlertType:   "Position Loss",
-							Severity:    getSeverityLevel(pos.UnrealizedPnL),
-							Description: fmt.Sprintf("Position loss exceeded threshold for %s", pos.Symbol),
-							Timestamp:   time.Now(),
-						}
-
-						select {
-						case alerts <- alert:
-						default:
-							// Channel full, could log this situation
-						}
+			case at := <-ticker.C():
+				if monitor.Positions == nil {
+					continue
+				}
+				// 加载失败时跳过本轮，下一轮重试
+				positions, err := monitor.Positions.OpenPositions(ctx, monitor.Account)
+				if err != nil {
+					continue
+				}
+
+				current := make(map[string]bool)
+				for _, alert := range rm.evaluatePositions(positions, at) {
+					key := alert.AlertType + "/" + alert.Symbol
+					current[key] = true
+					if active[key] {
+						continue
+					}
+					select {
+					case alerts <- alert:
+					default:
+						// Channel full, could log this situation
 					}
 				}
+				active = current
 			}
 		}
 	}()
@@ -168,24 +268,84 @@ func (rm *BasicRiskManager) MonitorPositions(ctx context.Context) (<-chan RiskAl
 	return alerts, nil
 }
 
-// Position represents a current trading position
-type Position struct {
-	Symbol        string
-	UnrealizedPnL float64
-}
+// evaluatePositions 按风险参数和监控阈值评估持仓，返回触发的预警
+func (rm *BasicRiskManager) evaluatePositions(positions []Position, now time.Time) []RiskAlert {
+	rm.paramsMu.RLock()
+	params := rm.params
+	monitor := rm.monitor
+	rm.paramsMu.RUnlock()
+
+	var alerts []RiskAlert
+	var exposure float64
+	var largest *Position
+	for i, pos := range positions {
+		exposure += pos.Value
+		if largest == nil || pos.Value > largest.Value {
+			largest = &positions[i]
+		}
 
-func (rm *BasicRiskManager) getCurrentPositions() []Position {
-	// 这个方法需要实际实现，连接到交易系统
-	return []Position{}
+		if pos.UnrealizedPnL < -params.MaxLossPerTrade {
+			alerts = append(alerts, RiskAlert{
+				Symbol:      pos.Symbol,
+				AlertType:   AlertPositionLoss,
+				Severity:    getSeverityLevel(pos.UnrealizedPnL),
+				Description: fmt.Sprintf("Unrealized loss %.2f of %s exceeds max loss per trade %.2f", -pos.UnrealizedPnL, pos.Symbol, params.MaxLossPerTrade),
+				Timestamp:   now,
+			})
+		}
+		if monitor.MaxHoldingPeriod > 0 && !pos.OpenedAt.IsZero() && now.Sub(pos.OpenedAt) > monitor.MaxHoldingPeriod {
+			alerts = append(alerts, RiskAlert{
+				Symbol:      pos.Symbol,
+				AlertType:   AlertStalePosition,
+				Severity:    SeverityLow,
+				Description: fmt.Sprintf("Position in %s has been held for %s, longer than %s", pos.Symbol, now.Sub(pos.OpenedAt).Round(time.Minute), monitor.MaxHoldingPeriod),
+				Timestamp:   now,
+			})
+		}
+		if monitor.StopATR > 0 && monitor.Volatility != nil && pos.AvgCost > 0 {
+			if atr, ok := monitor.Volatility.RelativeATR(pos.Symbol); ok {
+				if stop := pos.AvgCost * (1 - monitor.StopATR*atr); pos.Price <= stop {
+					alerts = append(alerts, RiskAlert{
+						Symbol:      pos.Symbol,
+						AlertType:   AlertStopLoss,
+						Severity:    SeverityMedium,
+						Description: fmt.Sprintf("Price %.8g of %s fell below stop %.8g, %.2f ATR under average cost %.8g", pos.Price, pos.Symbol, stop, monitor.StopATR, pos.AvgCost),
+						Timestamp:   now,
+					})
+				}
+			}
+		}
+		if pos.Value > params.MaxPositionSize {
+			alerts = append(alerts, RiskAlert{
+				Symbol:      pos.Symbol,
+				AlertType:   AlertPositionExposure,
+				Severity:    SeverityMedium,
+				Description: fmt.Sprintf("Position value %.2f of %s exceeds max position size %.2f", pos.Value, pos.Symbol, params.MaxPositionSize),
+				Timestamp:   now,
+			})
+		}
+	}
+
+	// 总敞口超限时对市值最大的持仓减仓
+	if monitor.MaxExposure > 0 && exposure > monitor.MaxExposure {
+		alerts = append(alerts, RiskAlert{
+			Symbol:      largest.Symbol,
+			AlertType:   AlertTotalExposure,
+			Severity:    SeverityMedium,
+			Description: fmt.Sprintf("Total position value %.2f exceeds max exposure %.2f, largest position is %s", exposure, monitor.MaxExposure, largest.Symbol),
+			Timestamp:   now,
+		})
+	}
+	return alerts
 }
 
 func getSeverityLevel(pnl float64) string {
 	switch {
 	case pnl < -10000:
-		return "HIGH"
+		return SeverityHigh
 	case pnl < -5000:
-		return "MEDIUM"
+		return SeverityMedium
 	default:
-		return "LOW"
+		return SeverityLow
 	}
 }
@@ -2,9 +2,12 @@ package risk
 
 import (
 	"context"
+	"math"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/songzhibin97/quantaflux/internal/clock"
 	"github.com/songzhibin97/quantaflux/internal/trading"
 
 	"github.com/stretchr/testify/assert"
@@ -84,6 +87,20 @@ func TestBasicRiskManager_CheckTradeRisk(t *testing.T) {
 			wantRiskLevel:  0.4, // 0.3 (size) + 0.1 (market)
 			wantFactors:    2,
 		},
+		{
+			name: "quote amount exceeds position size",
+			order: trading.Order{
+				Symbol:      "BTC-USDT",
+				Side:        "buy",
+				QuoteAmount: 20000.0,
+				Price:       1000.0,
+				OrderType:   "market",
+				Status:      "new",
+			},
+			wantAcceptable: false,
+			wantRiskLevel:  0.4,
+			wantFactors:    2,
+		},
 	}
 
 	for _, tt := range tests {
@@ -107,6 +124,117 @@ func TestBasicRiskManager_CheckTradeRisk(t *testing.T) {
 	}
 }
 
+func TestBasicRiskManager_CheckTradeRiskCosts(t *testing.T) {
+	params := RiskParameters{
+		MaxPositionSize: 10000.0,
+		MaxLossPerTrade: 100.0,
+		MaxDailyLoss:    3000.0,
+		MaxLeverage:     1,
+		MinLiquidity:    1000,
+	}
+	order := &trading.Order{Symbol: "BTCUSDT", Side: "buy", Amount: 0.93, Price: 1000, OrderType: "market"}
+
+	// 不计交易成本时潜在亏损 93 未超过限额
+	rm := NewBasicRiskManager(params)
+	assessment, err := rm.CheckTradeRisk(context.Background(), order)
+	require.NoError(t, err)
+	assert.True(t, assessment.IsAcceptable)
+	assert.Zero(t, assessment.EstimatedCost)
+
+	// 市价买入：开仓 taker 费率和滑点，加上平仓的 taker 费率和滑点
+	rm.SetCostModel(CostModel{MakerFeeRate: 0.001, TakerFeeRate: 0.002, SlippageBps: 30})
+	assessment, err = rm.CheckTradeRisk(context.Background(), order)
+	require.NoError(t, err)
+	assert.InDelta(t, 930*0.005*2, assessment.EstimatedCost, 1e-9)
+	assert.False(t, assessment.IsAcceptable)
+	assert.Contains(t, assessment.RiskFactors, "Potential loss exceeds maximum allowed per trade")
+
+	// 限价单开仓按 maker 费率，不计滑点
+	limit := *order
+	limit.OrderType = "limit"
+	assessment, err = rm.CheckTradeRisk(context.Background(), &limit)
+	require.NoError(t, err)
+	assert.InDelta(t, 930*0.001+930*0.005, assessment.EstimatedCost, 1e-9)
+	assert.True(t, assessment.IsAcceptable)
+}
+
+// stubVolatility 交易对 -> 波动率，ATR 比例与波动率相同
+type stubVolatility map[string]float64
+
+func (v stubVolatility) Volatility(symbol string) (float64, bool) {
+	vol, ok := v[symbol]
+	return vol, ok
+}
+
+func (v stubVolatility) RelativeATR(symbol string) (float64, bool) {
+	return v.Volatility(symbol)
+}
+
+func TestBasicRiskManager_CheckTradeRiskVaR(t *testing.T) {
+	params := RiskParameters{
+		MaxPositionSize: 10000.0,
+		MaxLossPerTrade: 100.0,
+		MaxDailyLoss:    3000.0,
+		MaxLeverage:     1,
+		MinLiquidity:    1000,
+	}
+	order := &trading.Order{Symbol: "BTCUSDT", Side: "buy", Amount: 2, Price: 1000, OrderType: "limit"}
+
+	// 默认按 10% 的不利变动估计，潜在亏损 200 超过限额
+	rm := NewBasicRiskManager(params)
+	assessment, err := rm.CheckTradeRisk(context.Background(), order)
+	require.NoError(t, err)
+	assert.InDelta(t, 200, assessment.ValueAtRisk, 1e-9)
+	assert.False(t, assessment.IsAcceptable)
+
+	// 波动率 1.5%，99% 置信度下持有 1 根 K 线的不利变动约 3.43%
+	rm.SetVaR(VaRModel{Source: stubVolatility{"BTCUSDT": 0.015}, Confidence: 0.99, Horizon: 1})
+	assessment, err = rm.CheckTradeRisk(context.Background(), order)
+	require.NoError(t, err)
+	assert.InDelta(t, 2000*(1-math.Exp(-2.326348*0.015)), assessment.ValueAtRisk, 1e-3)
+	assert.True(t, assessment.IsAcceptable)
+
+	// 持有 4 根 K 线时变动按 √4 放大，潜在亏损约 135 超过限额
+	rm.SetVaR(VaRModel{Source: stubVolatility{"BTCUSDT": 0.015}, Horizon: 4})
+	assessment, err = rm.CheckTradeRisk(context.Background(), order)
+	require.NoError(t, err)
+	assert.InDelta(t, 2000*(1-math.Exp(-2.326348*0.03)), assessment.ValueAtRisk, 1e-3)
+	assert.False(t, assessment.IsAcceptable)
+
+	// 波动率尚未就绪的交易对仍按 10% 估计
+	other := *order
+	other.Symbol = "ETHUSDT"
+	assessment, err = rm.CheckTradeRisk(context.Background(), &other)
+	require.NoError(t, err)
+	assert.InDelta(t, 200, assessment.ValueAtRisk, 1e-9)
+}
+
+func TestBasicRiskManager_PositionScale(t *testing.T) {
+	ctx := context.Background()
+	rm := NewBasicRiskManager(RiskParameters{MaxPositionSize: 1000, MaxLossPerTrade: 1000, MaxDailyLoss: 3000, MaxLeverage: 1, MinLiquidity: 1000})
+	order := &trading.Order{Symbol: "BTCUSDT", Side: "sell", Amount: 8, Price: 100, OrderType: "limit"}
+
+	assessment, err := rm.CheckTradeRisk(ctx, order)
+	require.NoError(t, err)
+	assert.True(t, assessment.IsAcceptable)
+
+	// 缩小后按缩放后的最大仓位检查新订单，风险状态中的参数不变
+	rm.SetPositionScale(0.5)
+	assessment, err = rm.CheckTradeRisk(ctx, order)
+	require.NoError(t, err)
+	assert.False(t, assessment.IsAcceptable)
+	assert.Contains(t, assessment.Recommendations, "Reduce position size below 500.00")
+
+	state, err := rm.GetRiskState(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1000.0, state.Parameters.MaxPositionSize)
+	assert.Equal(t, 0.5, state.PositionScale)
+
+	// 持仓监控仍按原限额检查，不因缩放强制减仓
+	alerts := rm.evaluatePositions([]Position{{Symbol: "BTCUSDT", Amount: 8, Price: 100, Value: 800}}, time.Now())
+	assert.Empty(t, alerts)
+}
+
 func TestBasicRiskManager_SetRiskParameters(t *testing.T) {
 	rm := NewBasicRiskManager(RiskParameters{})
 	ctx := context.Background()
@@ -188,6 +316,161 @@ func TestBasicRiskManager_MonitorPositions(t *testing.T) {
 	assert.False(t, ok, "alerts channel should be closed")
 }
 
+func TestBasicRiskManager_DailyResetWithFakeClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+	rm := NewBasicRiskManager(RiskParameters{MaxDailyLoss: 3000})
+	rm.SetClock(fake)
+
+	// 超过一天的快照不恢复
+	rm.RestoreDailyStats(&RiskState{DailyLoss: 500, StatsReset: start.Add(-25 * time.Hour)})
+	state, err := rm.GetRiskState(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, state.DailyLoss)
+
+	rm.RestoreDailyStats(&RiskState{DailyLoss: 500, DailyTradeCount: 2, StatsReset: start.Add(-time.Hour)})
+	state, err = rm.GetRiskState(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 500.0, state.DailyLoss)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, err = rm.MonitorPositions(ctx)
+	require.NoError(t, err)
+
+	fake.Advance(24 * time.Hour)
+	require.Eventually(t, func() bool {
+		state, err := rm.GetRiskState(context.Background())
+		return err == nil && state.DailyLoss == 0 && state.DailyTradeCount == 0
+	}, time.Second, time.Millisecond)
+	state, err = rm.GetRiskState(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, start.Add(24*time.Hour), state.StatsReset)
+}
+
+// staticPositions 返回固定持仓的 PositionSource
+type staticPositions struct {
+	mu        sync.Mutex
+	positions []Position
+}
+
+func (s *staticPositions) OpenPositions(ctx context.Context, account string) ([]Position, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Position(nil), s.positions...), nil
+}
+
+func (s *staticPositions) set(positions ...Position) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.positions = positions
+}
+
+func TestBasicRiskManager_EvaluatePositions(t *testing.T) {
+	now := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	rm := NewBasicRiskManager(RiskParameters{MaxPositionSize: 1000, MaxLossPerTrade: 100, MaxDailyLoss: 500, MaxLeverage: 1, MinLiquidity: 1})
+	rm.SetMonitor(MonitorOptions{
+		MaxHoldingPeriod: 72 * time.Hour,
+		MaxExposure:      1500,
+		Volatility:       stubVolatility{"BTCUSDT": 0.02},
+		StopATR:          2,
+	})
+
+	tests := []struct {
+		name      string
+		positions []Position
+		want      map[string]string // 预警类型 -> 严重程度
+	}{
+		{
+			name:      "healthy",
+			positions: []Position{{Symbol: "BTCUSDT", Value: 500, UnrealizedPnL: -50, OpenedAt: now.Add(-time.Hour)}},
+			want:      map[string]string{},
+		},
+		{
+			name:      "loss",
+			positions: []Position{{Symbol: "BTCUSDT", Value: 500, UnrealizedPnL: -6000, OpenedAt: now.Add(-time.Hour)}},
+			want:      map[string]string{AlertPositionLoss: SeverityMedium},
+		},
+		{
+			name:      "stale",
+			positions: []Position{{Symbol: "BTCUSDT", Value: 500, OpenedAt: now.Add(-96 * time.Hour)}},
+			want:      map[string]string{AlertStalePosition: SeverityLow},
+		},
+		{
+			name:      "atr stop",
+			positions: []Position{{Symbol: "BTCUSDT", Value: 500, AvgCost: 100, Price: 95.9, OpenedAt: now}},
+			want:      map[string]string{AlertStopLoss: SeverityMedium},
+		},
+		{
+			name:      "above atr stop",
+			positions: []Position{{Symbol: "BTCUSDT", Value: 500, AvgCost: 100, Price: 96.1, OpenedAt: now}},
+			want:      map[string]string{},
+		},
+		{
+			name:      "no volatility estimate",
+			positions: []Position{{Symbol: "ETHUSDT", Value: 500, AvgCost: 100, Price: 50, OpenedAt: now}},
+			want:      map[string]string{},
+		},
+		{
+			name:      "position exposure",
+			positions: []Position{{Symbol: "BTCUSDT", Value: 1200, OpenedAt: now}},
+			want:      map[string]string{AlertPositionExposure: SeverityMedium},
+		},
+		{
+			name: "total exposure",
+			positions: []Position{
+				{Symbol: "BTCUSDT", Value: 900, OpenedAt: now},
+				{Symbol: "ETHUSDT", Value: 800, OpenedAt: now},
+			},
+			want: map[string]string{AlertTotalExposure: SeverityMedium},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make(map[string]string)
+			for _, alert := range rm.evaluatePositions(tt.positions, now) {
+				got[alert.AlertType] = alert.Severity
+				if alert.AlertType == AlertTotalExposure {
+					// 总敞口预警针对市值最大的持仓
+					assert.Equal(t, "BTCUSDT", alert.Symbol)
+				}
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBasicRiskManager_MonitorPositionSource(t *testing.T) {
+	source := &staticPositions{}
+	source.set(Position{Symbol: "BTCUSDT", Value: 500, UnrealizedPnL: -200})
+
+	rm := NewBasicRiskManager(RiskParameters{MaxPositionSize: 1000, MaxLossPerTrade: 100, MaxDailyLoss: 500, MaxLeverage: 1, MinLiquidity: 1})
+	rm.SetMonitor(MonitorOptions{Account: "main", Positions: source, Interval: 5 * time.Millisecond})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	alerts, err := rm.MonitorPositions(ctx)
+	require.NoError(t, err)
+
+	alert := <-alerts
+	assert.Equal(t, AlertPositionLoss, alert.AlertType)
+	assert.Equal(t, "BTCUSDT", alert.Symbol)
+	assert.Equal(t, SeverityLow, alert.Severity)
+
+	// 持续浮亏不重复预警，恢复后再次亏损时重新预警
+	time.Sleep(30 * time.Millisecond)
+	assert.Empty(t, alerts)
+	source.set(Position{Symbol: "BTCUSDT", Value: 500})
+	time.Sleep(30 * time.Millisecond)
+	source.set(Position{Symbol: "BTCUSDT", Value: 500, UnrealizedPnL: -200})
+	select {
+	case alert = <-alerts:
+		assert.Equal(t, AlertPositionLoss, alert.AlertType)
+	case <-time.After(time.Second):
+		t.Fatal("expected a new alert after the loss recovered")
+	}
+}
+
 func TestGetSeverityLevel(t *testing.T) {
 	tests := []struct {
 		name string
@@ -0,0 +1,120 @@
+package risk
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrSpendLimit 时间窗口内下单的名义金额超过上限
+var ErrSpendLimit = errors.New("spend limit exceeded")
+
+// SpendLimitError 超过上限的时间窗口和金额，errors.Is(err, ErrSpendLimit) 成立
+type SpendLimitError struct {
+	Limit    SpendLimit
+	Spent    float64 // 窗口内已占用的金额
+	Notional float64 // 被拒绝订单的金额
+}
+
+func (e *SpendLimitError) Error() string {
+	return fmt.Sprintf("%v: %.2f already placed within %s, %.2f more exceeds the limit of %.2f",
+		ErrSpendLimit, e.Spent, e.Limit.Window, e.Notional, e.Limit.Max)
+}
+
+func (e *SpendLimitError) Unwrap() error {
+	return ErrSpendLimit
+}
+
+// SpendLimit 一个时间窗口内允许下单的最大名义金额
+type SpendLimit struct {
+	Window time.Duration
+	Max    float64
+}
+
+// spend 一笔计入额度的下单
+type spend struct {
+	id       uint64
+	at       time.Time
+	notional float64
+}
+
+// SpendGovernor 按滑动时间窗口统计全部账户和交易对下单的名义金额，超过任一窗口的上限时拒绝新订单，
+// 防止策略异常或被操纵的 AI 输出在短时间内连续下单。可并发使用
+type SpendGovernor struct {
+	mu     sync.Mutex
+	nextID uint64
+	spends []spend // 按时间先后排列
+}
+
+func NewSpendGovernor() *SpendGovernor {
+	return &SpendGovernor{}
+}
+
+// Reserve 在 now 时占用 notional 的额度，任一窗口内已占用的金额加上 notional 超过上限时返回 *SpendLimitError；
+// 订单最终没有下单时调用返回的 release 归还额度
+func (g *SpendGovernor) Reserve(now time.Time, notional float64, limits []SpendLimit) (release func(), err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var longest time.Duration
+	for _, limit := range limits {
+		longest = max(longest, limit.Window)
+	}
+	g.prune(now.Add(-longest))
+
+	for _, limit := range limits {
+		if limit.Max <= 0 || limit.Window <= 0 {
+			continue
+		}
+		spent := g.spent(now.Add(-limit.Window))
+		if spent+notional > limit.Max {
+			return func() {}, &SpendLimitError{Limit: limit, Spent: spent, Notional: notional}
+		}
+	}
+
+	g.nextID++
+	id := g.nextID
+	g.spends = append(g.spends, spend{id: id, at: now, notional: notional})
+	return func() { g.release(id) }, nil
+}
+
+// Spent 返回 now 之前 window 内已占用的额度
+func (g *SpendGovernor) Spent(now time.Time, window time.Duration) float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.spent(now.Add(-window))
+}
+
+// spent 统计 since 之后的占用，调用方需持有锁
+func (g *SpendGovernor) spent(since time.Time) float64 {
+	var total float64
+	for _, s := range g.spends {
+		if s.at.After(since) {
+			total += s.notional
+		}
+	}
+	return total
+}
+
+// prune 删除 before 及之前的占用，调用方需持有锁
+func (g *SpendGovernor) prune(before time.Time) {
+	i := 0
+	for i < len(g.spends) && !g.spends[i].at.After(before) {
+		i++
+	}
+	g.spends = g.spends[i:]
+}
+
+func (g *SpendGovernor) release(id uint64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for i, s := range g.spends {
+		if s.id == id {
+			g.spends = append(g.spends[:i], g.spends[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package risk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpendGovernor(t *testing.T) {
+	governor := NewSpendGovernor()
+	limits := []SpendLimit{{Window: time.Minute, Max: 100}, {Window: time.Hour, Max: 250}}
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	_, err := governor.Reserve(now, 60, limits)
+	require.NoError(t, err)
+	release, err := governor.Reserve(now.Add(10*time.Second), 40, limits)
+	require.NoError(t, err)
+
+	// 一分钟内超过 100
+	_, err = governor.Reserve(now.Add(20*time.Second), 10, limits)
+	assert.ErrorIs(t, err, ErrSpendLimit)
+
+	// 未下单的订单归还额度
+	release()
+	_, err = governor.Reserve(now.Add(20*time.Second), 30, limits)
+	require.NoError(t, err)
+	assert.Equal(t, 90.0, governor.Spent(now.Add(30*time.Second), time.Minute))
+
+	// 一分钟后分钟额度恢复，小时额度继续累计
+	_, err = governor.Reserve(now.Add(2*time.Minute), 100, limits)
+	require.NoError(t, err)
+	_, err = governor.Reserve(now.Add(4*time.Minute), 100, limits)
+	var limitErr *SpendLimitError
+	require.ErrorAs(t, err, &limitErr)
+	assert.ErrorIs(t, err, ErrSpendLimit)
+	assert.Equal(t, time.Hour, limitErr.Limit.Window)
+	assert.Equal(t, 190.0, limitErr.Spent)
+
+	// 没有限额时不限制
+	_, err = governor.Reserve(now.Add(5*time.Minute), 1e9, nil)
+	assert.NoError(t, err)
+}
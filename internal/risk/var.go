@@ -0,0 +1,33 @@
+package risk
+
+import "math"
+
+// defaultAdverseMove 没有波动率估计时假设的不利价格变动比例
+const defaultAdverseMove = 0.1
+
+// VaRModel 参数法 VaR：按交易对的历史波动率估计持有 Horizon 根 K 线后在 Confidence 置信度下的不利价格变动，
+// 代替固定的 10% 计算潜在亏损；未设置波动率来源或交易对的波动率尚未就绪时仍按 10% 计算
+type VaRModel struct {
+	Source     VolatilitySource
+	Confidence float64 // 置信度，默认 0.99
+	Horizon    int     // 持有的 K 线数量，默认 1
+}
+
+// AdverseMove 返回交易对价格的不利变动比例，对数收益率服从正态分布时为 1-exp(-z*σ*√horizon)
+func (m VaRModel) AdverseMove(symbol string) float64 {
+	if m.Source == nil {
+		return defaultAdverseMove
+	}
+	vol, ok := m.Source.Volatility(symbol)
+	if !ok || vol <= 0 {
+		return defaultAdverseMove
+	}
+
+	confidence := m.Confidence
+	if confidence <= 0.5 || confidence >= 1 {
+		confidence = 0.99
+	}
+	horizon := max(m.Horizon, 1)
+	z := math.Sqrt2 * math.Erfinv(2*confidence-1)
+	return 1 - math.Exp(-z*vol*math.Sqrt(float64(horizon)))
+}
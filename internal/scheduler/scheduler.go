@@ -0,0 +1,184 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/clock"
+)
+
+// JobFunc 定时任务执行函数
+type JobFunc func(ctx context.Context) error
+
+// Logger 日志接口
+type Logger interface {
+	Error(msg string, fields ...interface{})
+	Info(msg string, fields ...interface{})
+}
+
+// JobStatus 任务运行状态
+type JobStatus struct {
+	Name         string        `json:"name"`
+	Interval     string        `json:"interval"`
+	Running      bool          `json:"running"`
+	LastRun      time.Time     `json:"last_run"`
+	NextRun      time.Time     `json:"next_run"`
+	LastDuration time.Duration `json:"last_duration"`
+	LastError    string        `json:"last_error,omitempty"`
+	RunCount     int           `json:"run_count"`
+	FailCount    int           `json:"fail_count"`
+}
+
+type job struct {
+	name     string
+	interval time.Duration
+	fn       JobFunc
+	status   JobStatus
+}
+
+// Scheduler 按固定间隔执行周期任务，同一任务不会并发执行
+type Scheduler struct {
+	logger Logger
+	clock  clock.Clock
+
+	mu      sync.RWMutex
+	jobs    map[string]*job
+	started bool
+}
+
+func New(logger Logger) *Scheduler {
+	return &Scheduler{
+		logger: logger,
+		clock:  clock.Real(),
+		jobs:   make(map[string]*job),
+	}
+}
+
+// SetClock 设置任务计时使用的时钟，默认为系统时钟；必须在 Start 之前调用
+func (s *Scheduler) SetClock(c clock.Clock) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clock = clock.OrReal(c)
+}
+
+// Register 注册周期任务，必须在 Start 之前调用
+func (s *Scheduler) Register(name string, interval time.Duration, fn JobFunc) error {
+	if interval <= 0 {
+		return fmt.Errorf("invalid interval for job %s: %s", name, interval)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return fmt.Errorf("scheduler already started")
+	}
+	if _, ok := s.jobs[name]; ok {
+		return fmt.Errorf("job already registered: %s", name)
+	}
+
+	s.jobs[name] = &job{
+		name:     name,
+		interval: interval,
+		fn:       fn,
+		status: JobStatus{
+			Name:     name,
+			Interval: interval.String(),
+		},
+	}
+	return nil
+}
+
+// Start 启动所有任务，ctx 取消时停止
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	s.started = true
+	// Ticker 在启动 goroutine 之前创建，模拟时钟推进时不会错过第一次触发
+	tickers := make(map[*job]clock.Ticker, len(s.jobs))
+	for _, j := range s.jobs {
+		j.status.NextRun = s.clock.Now().Add(j.interval)
+		tickers[j] = s.clock.NewTicker(j.interval)
+	}
+	s.mu.Unlock()
+
+	for j, ticker := range tickers {
+		go s.loop(ctx, j, ticker)
+	}
+}
+
+// RunNow 立即执行指定任务
+func (s *Scheduler) RunNow(ctx context.Context, name string) error {
+	s.mu.RLock()
+	j, ok := s.jobs[name]
+	s.mu.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("job not found: %s", name)
+	}
+	return s.execute(ctx, j)
+}
+
+// Status 返回所有任务的运行状态
+func (s *Scheduler) Status() []JobStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]JobStatus, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		result = append(result, j.status)
+	}
+	sort.Slice(result, func(i, k int) bool {
+		return result[i].Name < result[k].Name
+	})
+	return result
+}
+
+func (s *Scheduler) loop(ctx context.Context, j *job, ticker clock.Ticker) {
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+			if err := s.execute(ctx, j); err != nil {
+				s.logger.Error("scheduled job failed", "job", j.name, "error", err)
+			}
+		}
+	}
+}
+
+func (s *Scheduler) execute(ctx context.Context, j *job) error {
+	s.mu.Lock()
+	if j.status.Running {
+		s.mu.Unlock()
+		return fmt.Errorf("job %s is already running", j.name)
+	}
+	j.status.Running = true
+	now := s.clock
+	s.mu.Unlock()
+
+	start := now.Now()
+	err := j.fn(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j.status.Running = false
+	j.status.LastRun = start
+	j.status.LastDuration = now.Now().Sub(start)
+	j.status.NextRun = start.Add(j.interval)
+	j.status.RunCount++
+	j.status.LastError = ""
+	if err != nil {
+		j.status.FailCount++
+		j.status.LastError = err.Error()
+		return err
+	}
+
+	s.logger.Info("scheduled job finished", "job", j.name, "duration", j.status.LastDuration)
+	return nil
+}
@@ -0,0 +1,105 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Error(msg string, fields ...interface{}) {}
+func (nopLogger) Info(msg string, fields ...interface{})  {}
+
+func TestScheduler_Register(t *testing.T) {
+	s := New(nopLogger{})
+
+	require.NoError(t, s.Register("job", time.Minute, func(ctx context.Context) error { return nil }))
+	assert.Error(t, s.Register("job", time.Minute, func(ctx context.Context) error { return nil }))
+	assert.Error(t, s.Register("bad", 0, func(ctx context.Context) error { return nil }))
+
+	s.Start(context.Background())
+	assert.Error(t, s.Register("late", time.Minute, func(ctx context.Context) error { return nil }))
+}
+
+func TestScheduler_RunsPeriodically(t *testing.T) {
+	var count int32
+	s := New(nopLogger{})
+	require.NoError(t, s.Register("tick", 10*time.Millisecond, func(ctx context.Context) error {
+		atomic.AddInt32(&count, 1)
+		return nil
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	s.Start(ctx)
+	<-ctx.Done()
+
+	assert.GreaterOrEqual(t, atomic.LoadInt32(&count), int32(3))
+}
+
+func TestScheduler_FakeClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+	runs := make(chan struct{}, 10)
+
+	s := New(nopLogger{})
+	s.SetClock(fake)
+	require.NoError(t, s.Register("hourly", time.Hour, func(ctx context.Context) error {
+		runs <- struct{}{}
+		return nil
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.Start(ctx)
+	assert.Equal(t, start.Add(time.Hour), s.Status()[0].NextRun)
+
+	fake.Advance(59 * time.Minute)
+	select {
+	case <-runs:
+		t.Fatal("job ran before its interval elapsed")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	fake.Advance(time.Minute)
+	select {
+	case <-runs:
+	case <-time.After(time.Second):
+		t.Fatal("job did not run after its interval elapsed")
+	}
+	require.Eventually(t, func() bool { return s.Status()[0].RunCount == 1 }, time.Second, time.Millisecond)
+	status := s.Status()[0]
+	assert.Equal(t, start.Add(time.Hour), status.LastRun)
+	assert.Equal(t, start.Add(2*time.Hour), status.NextRun)
+}
+
+func TestScheduler_Status(t *testing.T) {
+	s := New(nopLogger{})
+	require.NoError(t, s.Register("fails", time.Hour, func(ctx context.Context) error {
+		return errors.New("boom")
+	}))
+	require.NoError(t, s.Register("ok", time.Hour, func(ctx context.Context) error {
+		return nil
+	}))
+
+	ctx := context.Background()
+	assert.Error(t, s.RunNow(ctx, "fails"))
+	assert.NoError(t, s.RunNow(ctx, "ok"))
+	assert.Error(t, s.RunNow(ctx, "missing"))
+
+	status := s.Status()
+	require.Len(t, status, 2)
+	assert.Equal(t, "fails", status[0].Name)
+	assert.Equal(t, 1, status[0].FailCount)
+	assert.Equal(t, "boom", status[0].LastError)
+	assert.Equal(t, "ok", status[1].Name)
+	assert.Equal(t, 1, status[1].RunCount)
+	assert.Empty(t, status[1].LastError)
+}
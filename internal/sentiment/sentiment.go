@@ -0,0 +1,63 @@
+package sentiment
+
+import (
+	"context"
+	"time"
+)
+
+// Score 一次情绪分析的结果和当时的价格
+type Score struct {
+	ID        int64     `json:"id"`
+	Symbol    string    `json:"symbol"`
+	Score     float64   `json:"score"` // 情绪分数，-1 到 1
+	Price     float64   `json:"price"` // 分析时的行情价格
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Store 保存和查询交易对的情绪分数
+type Store interface {
+	// SaveSentiment persists a score and sets its ID
+	SaveSentiment(ctx context.Context, score *Score) error
+
+	// ListSentiment returns scores of the symbol whose timestamp is in [start, end], oldest first
+	ListSentiment(ctx context.Context, symbol string, start, end time.Time) ([]Score, error)
+}
+
+// Momentum 一段时间内情绪分数的变化
+type Momentum struct {
+	Samples     int     `json:"samples"`       // 参与计算的分数数量
+	Score       float64 `json:"score"`         // 最新情绪分数
+	Change      float64 `json:"change"`        // 最新分数减最早分数
+	RatePerHour float64 `json:"rate_per_hour"` // 每小时的分数变化
+	PriceChange float64 `json:"price_change"`  // 同期价格的相对变化
+	// Divergence 情绪与价格反向变化时为情绪变化量：为正表示价格下跌而情绪转好，
+	// 为负表示价格上涨而情绪转差，同向或无变化时为 0
+	Divergence float64 `json:"divergence"`
+}
+
+// Compute 按时间顺序的情绪分数计算动量，少于两个分数时只返回最新分数
+func Compute(scores []Score) Momentum {
+	var m Momentum
+	m.Samples = len(scores)
+	if len(scores) == 0 {
+		return m
+	}
+
+	first, last := scores[0], scores[len(scores)-1]
+	m.Score = last.Score
+	if len(scores) < 2 {
+		return m
+	}
+
+	m.Change = last.Score - first.Score
+	if hours := last.Timestamp.Sub(first.Timestamp).Hours(); hours > 0 {
+		m.RatePerHour = m.Change / hours
+	}
+	if first.Price > 0 {
+		m.PriceChange = (last.Price - first.Price) / first.Price
+	}
+	if m.Change*m.PriceChange < 0 {
+		m.Divergence = m.Change
+	}
+	return m
+}
@@ -0,0 +1,37 @@
+package sentiment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompute(t *testing.T) {
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, Momentum{}, Compute(nil))
+	assert.Equal(t, Momentum{Samples: 1, Score: 0.4}, Compute([]Score{{Score: 0.4, Price: 100, Timestamp: start}}))
+
+	// 价格上涨而情绪转差，看跌背离
+	m := Compute([]Score{
+		{Score: 0.6, Price: 100, Timestamp: start},
+		{Score: 0.5, Price: 104, Timestamp: start.Add(time.Hour)},
+		{Score: 0.2, Price: 110, Timestamp: start.Add(2 * time.Hour)},
+	})
+	assert.Equal(t, 3, m.Samples)
+	assert.InDelta(t, 0.2, m.Score, 1e-9)
+	assert.InDelta(t, -0.4, m.Change, 1e-9)
+	assert.InDelta(t, -0.2, m.RatePerHour, 1e-9)
+	assert.InDelta(t, 0.1, m.PriceChange, 1e-9)
+	assert.InDelta(t, -0.4, m.Divergence, 1e-9)
+
+	// 情绪和价格同向变化时没有背离
+	m = Compute([]Score{
+		{Score: 0.1, Price: 100, Timestamp: start},
+		{Score: 0.3, Price: 102, Timestamp: start.Add(30 * time.Minute)},
+	})
+	assert.InDelta(t, 0.2, m.Change, 1e-9)
+	assert.InDelta(t, 0.4, m.RatePerHour, 1e-9)
+	assert.Zero(t, m.Divergence)
+}
@@ -0,0 +1,102 @@
+// Package social 把各平台的原始社交指标按可配置的权重汇总为社交分数
+package social
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// DefaultWeights 未配置权重时使用的指标权重
+var DefaultWeights = map[string]float64{
+	"twitter_followers": 0.3,
+	"telegram_members":  0.3,
+	"github_stars":      0.2,
+	"reddit_members":    0.2,
+}
+
+// Options 分数计算参数
+type Options struct {
+	Weights  map[string]float64 // 指标 -> 权重，为空时使用 DefaultWeights，未列出的指标不计分
+	LogScale bool               // 指标先取 ln(1+x)，关注人数等跨数量级的指标不会压过其他指标
+	ZScore   bool               // 按该指标在所有交易对最新值中的均值和标准差标准化，不同平台的指标可比
+}
+
+// Score 一次计算的社交分数
+type Score struct {
+	Total      float64            `json:"total"`      // 各指标标准化值的加权和
+	Components map[string]float64 `json:"components"` // 参与计分的指标标准化后、加权前的值
+}
+
+// Scorer 计算社交分数，启用 ZScore 时记录各交易对每个指标的最新值；可并发使用
+type Scorer struct {
+	opts Options
+
+	mu     sync.Mutex
+	latest map[string]map[string]float64 // 指标 -> 交易对 -> 最新的缩放后的值
+}
+
+func NewScorer(opts Options) *Scorer {
+	if len(opts.Weights) == 0 {
+		opts.Weights = DefaultWeights
+	}
+	return &Scorer{opts: opts, latest: make(map[string]map[string]float64)}
+}
+
+// Score 计算交易对的社交分数。启用 ZScore 时指标先更新为该交易对的最新值，
+// 参与比较的交易对少于两个或各交易对的值相同时该指标标准化值为 0
+func (s *Scorer) Score(symbol string, metrics map[string]float64) Score {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := Score{Components: make(map[string]float64)}
+	names := make([]string, 0, len(metrics))
+	for name := range metrics {
+		if _, ok := s.opts.Weights[name]; ok {
+			names = append(names, name)
+		}
+	}
+	// 按名称顺序累加，相同输入的结果完全一致
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := metrics[name]
+		if s.opts.LogScale {
+			value = math.Log1p(math.Max(value, 0))
+		}
+		if s.opts.ZScore {
+			value = s.zScore(name, symbol, value)
+		}
+		result.Components[name] = value
+		result.Total += value * s.opts.Weights[name]
+	}
+	return result
+}
+
+// zScore 记录交易对的最新值并返回其在所有交易对最新值中的标准分数，调用方需持有锁
+func (s *Scorer) zScore(name, symbol string, value float64) float64 {
+	values, ok := s.latest[name]
+	if !ok {
+		values = make(map[string]float64)
+		s.latest[name] = values
+	}
+	values[symbol] = value
+	if len(values) < 2 {
+		return 0
+	}
+
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+	var variance float64
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	std := math.Sqrt(variance / float64(len(values)))
+	if std == 0 {
+		return 0
+	}
+	return (value - mean) / std
+}
@@ -0,0 +1,45 @@
+package social
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScorer_DefaultWeights(t *testing.T) {
+	scorer := NewScorer(Options{})
+	score := scorer.Score("BTCUSDT", map[string]float64{
+		"twitter_followers": 1000,
+		"github_stars":      100,
+		"unknown":           1e9,
+	})
+
+	assert.InDelta(t, 1000*0.3+100*0.2, score.Total, 1e-9)
+	assert.Equal(t, map[string]float64{"twitter_followers": 1000, "github_stars": 100}, score.Components)
+}
+
+func TestScorer_LogScale(t *testing.T) {
+	scorer := NewScorer(Options{Weights: map[string]float64{"twitter_followers": 1, "github_stars": 1}, LogScale: true})
+	score := scorer.Score("BTCUSDT", map[string]float64{"twitter_followers": math.E*math.E - 1, "github_stars": -5})
+
+	assert.InDelta(t, 2, score.Components["twitter_followers"], 1e-9)
+	assert.Zero(t, score.Components["github_stars"])
+	assert.InDelta(t, 2, score.Total, 1e-9)
+}
+
+func TestScorer_ZScore(t *testing.T) {
+	scorer := NewScorer(Options{Weights: map[string]float64{"twitter_followers": 0.5}, ZScore: true})
+
+	// 只有一个交易对时无法比较
+	assert.Zero(t, scorer.Score("BTCUSDT", map[string]float64{"twitter_followers": 300}).Total)
+
+	// 两个交易对的均值 200、标准差 100
+	score := scorer.Score("DOGEUSDT", map[string]float64{"twitter_followers": 100})
+	assert.InDelta(t, -1, score.Components["twitter_followers"], 1e-9)
+	assert.InDelta(t, -0.5, score.Total, 1e-9)
+
+	// 同一交易对只保留最新值
+	score = scorer.Score("BTCUSDT", map[string]float64{"twitter_followers": 100})
+	assert.Zero(t, score.Total)
+}
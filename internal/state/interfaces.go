@@ -0,0 +1,34 @@
+package state
+
+import (
+	"context"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/trading"
+)
+
+// Store 持久化交易主循环的运行状态，用于崩溃后恢复
+type Store interface {
+	// SaveLoopState persists the latest loop state, replacing the previous one
+	SaveLoopState(ctx context.Context, state *LoopState) error
+
+	// LoadLoopState returns the last persisted loop state, or nil if none exists
+	LoadLoopState(ctx context.Context) (*LoopState, error)
+}
+
+// LoopState 交易主循环状态
+type LoopState struct {
+	LastTicks     map[string]time.Time `json:"last_ticks"`     // 每个交易对最后处理的行情时间
+	PendingOrders []OrderIntent        `json:"pending_orders"` // 已决定下单但尚未确认结果的订单
+	Paused        bool                 `json:"paused"`         // 交易是否已暂停
+	PausedSymbols []string             `json:"paused_symbols"` // 单独暂停的交易对
+	DisabledUntil map[string]time.Time `json:"disabled_until"` // 因表现不佳自动停用的交易对及自动恢复时间，零值表示只能手动恢复
+	UpdatedAt     time.Time            `json:"updated_at"`
+}
+
+// OrderIntent 下单意图，在调用交易所前记录，收到结果后移除
+type OrderIntent struct {
+	ID        string        `json:"id"`
+	Order     trading.Order `json:"order"`
+	CreatedAt time.Time     `json:"created_at"`
+}
@@ -0,0 +1,44 @@
+package state
+
+import (
+	"context"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/risk"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+)
+
+// SnapshotStore 保存系统完整状态快照，用于蓝绿部署时新实例接管运行上下文
+type SnapshotStore interface {
+	// SaveSnapshot persists a snapshot and sets its ID
+	SaveSnapshot(ctx context.Context, snapshot *Snapshot) error
+
+	// LatestSnapshot returns the most recent snapshot of the run mode, or nil if none exists
+	LatestSnapshot(ctx context.Context, mode string) (*Snapshot, error)
+}
+
+// Snapshot 系统完整状态快照
+type Snapshot struct {
+	ID        int64             `json:"id"`
+	Label     string            `json:"label"`
+	Mode      string            `json:"mode"`
+	Loop      LoopState         `json:"loop"`
+	Accounts  []AccountSnapshot `json:"accounts"`
+	Strategy  StrategyState     `json:"strategy"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// AccountSnapshot 交易账户的持仓、挂单和风险状态
+type AccountSnapshot struct {
+	Name       string                 `json:"name"`
+	Balances   map[string]float64     `json:"balances"` // 交易对涉及的资产余额
+	OpenOrders []trading.Order        `json:"open_orders"`
+	Risk       risk.RiskState         `json:"risk"`                // 风险参数和当日统计
+	Simulated  *trading.ExecutorState `json:"simulated,omitempty"` // 模拟执行器的内存状态，实盘账户为空
+}
+
+// StrategyState 策略运行中积累的状态
+type StrategyState struct {
+	Trends          map[string][]float64 `json:"trends"`           // 交易对@周期 -> 趋势过滤的最近价格
+	PromotedSymbols []string             `json:"promoted_symbols"` // 代币发现加入的交易对
+}
@@ -0,0 +1,79 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/songzhibin97/quantaflux/internal/utils/request"
+)
+
+// Kafka REST Proxy v2 的 JSON 消息格式
+const kafkaJSONContentType = "application/vnd.kafka.json.v2+json"
+
+// KafkaRESTPublisher 通过 Kafka REST Proxy（v2 API）发布消息，不直接连接 broker
+type KafkaRESTPublisher struct {
+	url string
+}
+
+// NewKafkaRESTPublisher 根据 REST Proxy 地址创建发布者，如 http://localhost:8082
+func NewKafkaRESTPublisher(proxyURL string) *KafkaRESTPublisher {
+	return &KafkaRESTPublisher{url: strings.TrimRight(proxyURL, "/")}
+}
+
+type kafkaRecord struct {
+	Key   string          `json:"key,omitempty"`
+	Value json.RawMessage `json:"value"`
+}
+
+type kafkaResponse struct {
+	Offsets []struct {
+		Partition int    `json:"partition"`
+		Offset    int64  `json:"offset"`
+		ErrorCode *int   `json:"error_code"`
+		Error     string `json:"error"`
+	} `json:"offsets"`
+}
+
+// Publish implements Publisher，同一主题的消息合并为一次请求
+func (k *KafkaRESTPublisher) Publish(ctx context.Context, messages []Message) error {
+	var topics []string
+	records := make(map[string][]kafkaRecord)
+	for _, msg := range messages {
+		if _, ok := records[msg.Topic]; !ok {
+			topics = append(topics, msg.Topic)
+		}
+		records[msg.Topic] = append(records[msg.Topic], kafkaRecord{Key: msg.Key, Value: msg.Value})
+	}
+
+	for _, topic := range topics {
+		resp, err := request.Request.R().
+			SetContext(ctx).
+			SetHeader("Content-Type", kafkaJSONContentType).
+			SetBody(map[string][]kafkaRecord{"records": records[topic]}).
+			Post(k.url + "/topics/" + url.PathEscape(topic))
+		if err != nil {
+			return fmt.Errorf("failed to publish to kafka topic %s: %w", topic, err)
+		}
+		if resp.IsError() {
+			return fmt.Errorf("kafka rest proxy rejected topic %s: status=%d, body=%s", topic, resp.StatusCode(), resp.String())
+		}
+		var result kafkaResponse
+		if err := json.Unmarshal(resp.Body(), &result); err != nil {
+			return fmt.Errorf("invalid kafka rest proxy response for topic %s: %w", topic, err)
+		}
+		for _, offset := range result.Offsets {
+			if offset.ErrorCode != nil || offset.Error != "" {
+				return fmt.Errorf("kafka rest proxy failed to write to topic %s partition %d: %s", topic, offset.Partition, offset.Error)
+			}
+		}
+	}
+	return nil
+}
+
+// Close implements Publisher
+func (k *KafkaRESTPublisher) Close() error {
+	return nil
+}
@@ -0,0 +1,212 @@
+package stream
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	natsDefaultPort = "4222"
+	natsDialTimeout = 5 * time.Second
+)
+
+// NATSPublisher 按 NATS 文本协议发布消息（核心 NATS，不使用 JetStream），
+// 连接断开后在下一次发布时重连；不支持 TLS
+type NATSPublisher struct {
+	address string
+	connect natsConnect
+
+	mu     sync.Mutex
+	conn   net.Conn
+	writer *bufio.Writer
+}
+
+// natsConnect CONNECT 命令的参数
+type natsConnect struct {
+	Verbose   bool   `json:"verbose"`
+	Pedantic  bool   `json:"pedantic"`
+	Name      string `json:"name"`
+	Lang      string `json:"lang"`
+	Version   string `json:"version"`
+	User      string `json:"user,omitempty"`
+	Pass      string `json:"pass,omitempty"`
+	AuthToken string `json:"auth_token,omitempty"`
+}
+
+// natsInfo 服务端 INFO 命令中用到的字段
+type natsInfo struct {
+	TLSRequired bool `json:"tls_required"`
+}
+
+// NewNATSPublisher 根据 nats://[user:pass@|token@]host[:port] 创建发布者，连接在首次发布时建立
+func NewNATSPublisher(rawURL, name string) (*NATSPublisher, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid nats url: %w", err)
+	}
+	if u.Scheme != "nats" || u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid nats url %q, expected nats://host:port", rawURL)
+	}
+
+	address := u.Host
+	if u.Port() == "" {
+		address = net.JoinHostPort(u.Hostname(), natsDefaultPort)
+	}
+	connect := natsConnect{Name: name, Lang: "go", Version: "1"}
+	if u.User != nil {
+		if pass, ok := u.User.Password(); ok {
+			connect.User, connect.Pass = u.User.Username(), pass
+		} else {
+			connect.AuthToken = u.User.Username()
+		}
+	}
+	return &NATSPublisher{address: address, connect: connect}, nil
+}
+
+// Publish implements Publisher
+func (p *NATSPublisher) Publish(ctx context.Context, messages []Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.conn == nil {
+		if err := p.dial(ctx); err != nil {
+			return err
+		}
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = p.conn.SetWriteDeadline(deadline)
+	}
+
+	for _, msg := range messages {
+		fmt.Fprintf(p.writer, "PUB %s %d\r\n", msg.Topic, len(msg.Value))
+		p.writer.Write(msg.Value)
+		p.writer.WriteString("\r\n")
+	}
+	if err := p.writer.Flush(); err != nil {
+		p.closeLocked()
+		return fmt.Errorf("failed to publish to nats: %w", err)
+	}
+	return nil
+}
+
+// Close implements Publisher
+func (p *NATSPublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.closeLocked()
+}
+
+func (p *NATSPublisher) closeLocked() error {
+	if p.conn == nil {
+		return nil
+	}
+	err := p.conn.Close()
+	p.conn, p.writer = nil, nil
+	return err
+}
+
+// dial 建立连接并完成握手：读取 INFO，发送 CONNECT 和 PING，等待 PONG 确认认证通过
+func (p *NATSPublisher) dial(ctx context.Context) error {
+	dialer := net.Dialer{Timeout: natsDialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", p.address)
+	if err != nil {
+		return fmt.Errorf("failed to connect to nats %s: %w", p.address, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	reader := bufio.NewReader(conn)
+	if err := p.handshake(conn, reader); err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("nats handshake with %s failed: %w", p.address, err)
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	p.conn, p.writer = conn, bufio.NewWriter(conn)
+	go p.readLoop(conn, reader)
+	return nil
+}
+
+func (p *NATSPublisher) handshake(conn net.Conn, reader *bufio.Reader) error {
+	line, err := readLine(reader)
+	if err != nil {
+		return err
+	}
+	payload, ok := strings.CutPrefix(line, "INFO ")
+	if !ok {
+		return fmt.Errorf("unexpected greeting %q", line)
+	}
+	var info natsInfo
+	if err := json.Unmarshal([]byte(payload), &info); err != nil {
+		return fmt.Errorf("invalid INFO: %w", err)
+	}
+	if info.TLSRequired {
+		return errors.New("server requires TLS, which is not supported")
+	}
+
+	connect, err := json.Marshal(p.connect)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(conn, "CONNECT %s\r\nPING\r\n", connect); err != nil {
+		return err
+	}
+	for {
+		line, err := readLine(reader)
+		if err != nil {
+			return err
+		}
+		switch {
+		case line == "PONG":
+			return nil
+		case strings.HasPrefix(line, "-ERR"):
+			return errors.New(strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+	}
+}
+
+// readLoop 回复服务端的 PING 保持连接，收到错误或连接断开时关闭连接，下一次发布时重连
+func (p *NATSPublisher) readLoop(conn net.Conn, reader *bufio.Reader) {
+	for {
+		line, err := readLine(reader)
+		if err != nil || strings.HasPrefix(line, "-ERR") {
+			break
+		}
+		if line == "PING" {
+			p.mu.Lock()
+			if p.conn == conn {
+				_, err = p.writer.WriteString("PONG\r\n")
+				if err == nil {
+					err = p.writer.Flush()
+				}
+			}
+			p.mu.Unlock()
+			if err != nil {
+				break
+			}
+		}
+	}
+
+	p.mu.Lock()
+	if p.conn == conn {
+		p.closeLocked()
+	}
+	p.mu.Unlock()
+}
+
+func readLine(reader *bufio.Reader) (string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -0,0 +1,176 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+// 默认参数
+const (
+	DefaultPrefix     = "quantaflux"
+	DefaultBufferSize = 1024
+	maxBatchSize      = 100
+	publishTimeout    = 5 * time.Second
+)
+
+// 发布结果，用于 OnDelivery 统计
+const (
+	ResultPublished = "published"
+	ResultDropped   = "dropped"
+	ResultFailed    = "failed"
+)
+
+// Event 发布到消息系统的事件，格式与仪表盘 WebSocket 推送一致
+type Event struct {
+	Type      string      `json:"type"`
+	Data      interface{} `json:"data"`
+	Timestamp time.Time   `json:"timestamp"`
+}
+
+// Message 发往一个主题的消息
+type Message struct {
+	Topic string
+	Key   string // 分区键，Kafka 按交易对分区保证同一交易对的事件有序，NATS 忽略
+	Value []byte
+}
+
+// Publisher 消息系统的客户端
+type Publisher interface {
+	// Publish sends the messages in order
+	Publish(ctx context.Context, messages []Message) error
+
+	// Close releases the connection
+	Close() error
+}
+
+// Logger 日志接口
+type Logger interface {
+	Error(msg string, fields ...interface{})
+}
+
+// Options 事件流参数
+type Options struct {
+	Prefix     string   // 主题前缀，主题为 <prefix>.<事件类型>，默认 quantaflux
+	Events     []string // 发布的事件类型，为空时发布全部
+	BufferSize int      // 待发布事件的队列长度，默认 1024
+}
+
+// Streamer 将行情、信号、订单和风险预警异步发布到消息系统：事件先进入队列，由 Run 批量发送，
+// 队列满时丢弃新事件，消息系统故障不阻塞交易流程
+type Streamer struct {
+	publisher Publisher
+	options   Options
+	logger    Logger
+	queue     chan Message
+
+	// OnDelivery 每批事件发送后按结果回调，用于统计指标
+	OnDelivery func(result string, count int)
+}
+
+// NewStreamer creates a new Streamer instance
+func NewStreamer(publisher Publisher, options Options, logger Logger) *Streamer {
+	if options.Prefix == "" {
+		options.Prefix = DefaultPrefix
+	}
+	if options.BufferSize <= 0 {
+		options.BufferSize = DefaultBufferSize
+	}
+	return &Streamer{
+		publisher: publisher,
+		options:   options,
+		logger:    logger,
+		queue:     make(chan Message, options.BufferSize),
+	}
+}
+
+// Topic 返回事件类型对应的主题
+func (s *Streamer) Topic(eventType string) string {
+	return s.options.Prefix + "." + eventType
+}
+
+// Publish 将事件加入发布队列，key 为分区键（通常是交易对）；未启用、未订阅该事件类型或队列满时不发布
+func (s *Streamer) Publish(eventType, key string, data interface{}) {
+	if s == nil {
+		return
+	}
+	if len(s.options.Events) > 0 && !slices.Contains(s.options.Events, eventType) {
+		return
+	}
+
+	value, err := json.Marshal(Event{Type: eventType, Data: data, Timestamp: time.Now()})
+	if err != nil {
+		s.logger.Error("failed to marshal stream event", "type", eventType, "err", err)
+		return
+	}
+
+	select {
+	case s.queue <- Message{Topic: s.Topic(eventType), Key: key, Value: value}:
+	default:
+		s.deliver(ResultDropped, 1)
+	}
+}
+
+// Run 批量发送队列中的事件，直到 ctx 取消后发送剩余事件并关闭连接
+func (s *Streamer) Run(ctx context.Context) {
+	defer func() {
+		if err := s.publisher.Close(); err != nil {
+			s.logger.Error("failed to close stream publisher", "err", err)
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.drain()
+			return
+		case msg := <-s.queue:
+			s.send(s.batch(msg))
+		}
+	}
+}
+
+// batch 返回以 first 开头、包含队列中已有事件的一批消息
+func (s *Streamer) batch(first Message) []Message {
+	batch := []Message{first}
+	for len(batch) < maxBatchSize {
+		select {
+		case msg := <-s.queue:
+			batch = append(batch, msg)
+		default:
+			return batch
+		}
+	}
+	return batch
+}
+
+// drain 退出前发送队列中剩余的事件
+func (s *Streamer) drain() {
+	for {
+		select {
+		case msg := <-s.queue:
+			s.send(s.batch(msg))
+		default:
+			return
+		}
+	}
+}
+
+func (s *Streamer) send(batch []Message) {
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+
+	if err := s.publisher.Publish(ctx, batch); err != nil {
+		s.logger.Error("failed to publish stream events", "count", len(batch), "err", err)
+		s.deliver(ResultFailed, len(batch))
+		return
+	}
+	s.deliver(ResultPublished, len(batch))
+}
+
+func (s *Streamer) deliver(result string, count int) {
+	if s.OnDelivery != nil {
+		s.OnDelivery(result, count)
+	}
+}
@@ -0,0 +1,193 @@
+package stream
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Error(msg string, fields ...interface{}) {}
+
+type recordingPublisher struct {
+	mu      sync.Mutex
+	batches [][]Message
+	err     error
+	closed  bool
+}
+
+func (r *recordingPublisher) Publish(ctx context.Context, messages []Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.batches = append(r.batches, messages)
+	return r.err
+}
+
+func (r *recordingPublisher) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closed = true
+	return nil
+}
+
+func TestStreamer(t *testing.T) {
+	publisher := &recordingPublisher{}
+	streamer := NewStreamer(publisher, Options{Prefix: "qf", Events: []string{"trade", "risk_alert"}, BufferSize: 2}, nopLogger{})
+	results := make(map[string]int)
+	streamer.OnDelivery = func(result string, count int) { results[result] += count }
+
+	// 未订阅的事件类型不发布，队列满时丢弃
+	streamer.Publish("market_data", "BTCUSDT", map[string]float64{"price": 100})
+	streamer.Publish("trade", "BTCUSDT", map[string]string{"side": "buy"})
+	streamer.Publish("risk_alert", "ETHUSDT", map[string]string{"severity": "HIGH"})
+	streamer.Publish("trade", "ETHUSDT", nil)
+	assert.Equal(t, 1, results[ResultDropped])
+
+	// 退出时发送队列中剩余的事件并关闭连接
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	streamer.Run(ctx)
+	assert.True(t, publisher.closed)
+	require.Len(t, publisher.batches, 1)
+	batch := publisher.batches[0]
+	require.Len(t, batch, 2)
+	assert.Equal(t, "qf.trade", batch[0].Topic)
+	assert.Equal(t, "BTCUSDT", batch[0].Key)
+	assert.Equal(t, "qf.risk_alert", batch[1].Topic)
+	assert.Equal(t, 2, results[ResultPublished])
+
+	var event Event
+	require.NoError(t, json.Unmarshal(batch[0].Value, &event))
+	assert.Equal(t, "trade", event.Type)
+	assert.Equal(t, map[string]interface{}{"side": "buy"}, event.Data)
+
+	// 发送失败时按批次计数
+	publisher.err = errors.New("broker down")
+	streamer.Publish("trade", "BTCUSDT", nil)
+	streamer.Run(ctx)
+	assert.Equal(t, 1, results[ResultFailed])
+
+	var disabled *Streamer
+	disabled.Publish("trade", "BTCUSDT", nil)
+}
+
+// fakeNATS 模拟 NATS 服务端，记录收到的 PUB 消息
+func fakeNATS(t *testing.T, token string) (string, <-chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	published := make(chan string, 16)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				io.WriteString(conn, `INFO {"server_id":"test","auth_required":true}`+"\r\n")
+				for {
+					line, err := readLine(reader)
+					if err != nil {
+						return
+					}
+					switch {
+					case strings.HasPrefix(line, "CONNECT "):
+						if !strings.Contains(line, `"auth_token":"`+token+`"`) {
+							io.WriteString(conn, "-ERR 'Authorization Violation'\r\n")
+							return
+						}
+					case line == "PING":
+						io.WriteString(conn, "PONG\r\n")
+					case strings.HasPrefix(line, "PUB "):
+						payload, err := readLine(reader)
+						if err != nil {
+							return
+						}
+						published <- strings.Fields(line)[1] + " " + payload
+					}
+				}
+			}()
+		}
+	}()
+	return listener.Addr().String(), published
+}
+
+func TestNATSPublisher(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	address, published := fakeNATS(t, "secret")
+
+	_, err := NewNATSPublisher("http://"+address, "quantaflux")
+	assert.Error(t, err)
+
+	denied, err := NewNATSPublisher("nats://wrong@"+address, "quantaflux")
+	require.NoError(t, err)
+	err = denied.Publish(ctx, []Message{{Topic: "qf.trade", Value: []byte(`{}`)}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Authorization Violation")
+
+	publisher, err := NewNATSPublisher("nats://secret@"+address, "quantaflux")
+	require.NoError(t, err)
+	defer publisher.Close()
+	require.NoError(t, publisher.Publish(ctx, []Message{
+		{Topic: "qf.trade", Value: []byte(`{"type":"trade"}`)},
+		{Topic: "qf.market_data", Value: []byte(`{"type":"market_data"}`)},
+	}))
+	assert.Equal(t, `qf.trade {"type":"trade"}`, <-published)
+	assert.Equal(t, `qf.market_data {"type":"market_data"}`, <-published)
+
+	// 连接断开后重连
+	require.NoError(t, publisher.Close())
+	require.NoError(t, publisher.Publish(ctx, []Message{{Topic: "qf.trade", Value: []byte(`{}`)}}))
+	assert.Equal(t, `qf.trade {}`, <-published)
+}
+
+func TestKafkaRESTPublisher(t *testing.T) {
+	requests := make(map[string][]kafkaRecord)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, kafkaJSONContentType, r.Header.Get("Content-Type"))
+		var body struct {
+			Records []kafkaRecord `json:"records"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		requests[r.URL.Path] = body.Records
+		if r.URL.Path == "/topics/qf.broken" {
+			io.WriteString(w, `{"offsets":[{"partition":0,"offset":null,"error_code":50003,"error":"Kafka error"}]}`)
+			return
+		}
+		io.WriteString(w, `{"offsets":[{"partition":0,"offset":1,"error_code":null,"error":null}]}`)
+	}))
+	defer server.Close()
+
+	publisher := NewKafkaRESTPublisher(server.URL + "/")
+	err := publisher.Publish(context.Background(), []Message{
+		{Topic: "qf.trade", Key: "BTCUSDT", Value: []byte(`{"a":1}`)},
+		{Topic: "qf.market_data", Value: []byte(`{"b":2}`)},
+		{Topic: "qf.trade", Key: "ETHUSDT", Value: []byte(`{"c":3}`)},
+	})
+	require.NoError(t, err)
+	require.Len(t, requests["/topics/qf.trade"], 2)
+	assert.Equal(t, "ETHUSDT", requests["/topics/qf.trade"][1].Key)
+	assert.JSONEq(t, `{"c":3}`, string(requests["/topics/qf.trade"][1].Value))
+	require.Len(t, requests["/topics/qf.market_data"], 1)
+
+	err = publisher.Publish(context.Background(), []Message{{Topic: "qf.broken", Value: []byte(`{}`)}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Kafka error")
+}
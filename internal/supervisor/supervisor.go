@@ -0,0 +1,193 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// State 长连接组件的连接状态
+type State string
+
+const (
+	StateConnecting State = "connecting" // 正在建立连接
+	StateConnected  State = "connected"  // 连接正常
+	StateBackoff    State = "backoff"    // 连接断开，等待重连
+	StateStopped    State = "stopped"    // ctx 取消后停止
+)
+
+// Backoff 重连的退避参数：第 n 次连续失败后等待 Initial*2^(n-1)，不超过 Max，
+// 再按 Jitter 比例随机缩短，避免多个组件同时重连
+type Backoff struct {
+	Initial time.Duration
+	Max     time.Duration
+	Jitter  float64 // 0-1，等待时长在 [(1-Jitter)*d, d] 内随机
+}
+
+// DefaultBackoff 默认退避参数
+var DefaultBackoff = Backoff{Initial: time.Second, Max: time.Minute, Jitter: 0.2}
+
+// delay 返回第 attempt 次连续失败后的等待时长，r 为 [0,1) 的随机数
+func (b Backoff) delay(attempt int, r float64) time.Duration {
+	d := b.Initial
+	for i := 1; i < attempt && d < b.Max; i++ {
+		d *= 2
+	}
+	if b.Max > 0 && d > b.Max {
+		d = b.Max
+	}
+	jitter := min(max(b.Jitter, 0), 1)
+	return d - time.Duration(float64(d)*jitter*r)
+}
+
+// Status 组件的连接状态
+type Status struct {
+	Name      string    `json:"name"`
+	State     State     `json:"state"`
+	Restarts  int       `json:"restarts"`             // 累计重启次数
+	LastError string    `json:"last_error,omitempty"` // 最近一次断开的原因
+	Since     time.Time `json:"since"`                // 进入当前状态的时间
+}
+
+// RunFunc 组件的一次运行，连接建立后调用 connected，直到连接断开或 ctx 取消时返回
+type RunFunc func(ctx context.Context, connected func()) error
+
+// Supervisor 管理长连接组件（行情推送、后台监控等）的运行，组件返回或 panic 后按退避重启，
+// 并记录各组件的连接状态。可并发使用
+type Supervisor struct {
+	// OnStateChange 组件状态变化时调用，可为空
+	OnStateChange func(status Status)
+
+	backoff Backoff
+	now     func() time.Time
+
+	mu         sync.Mutex
+	rng        *rand.Rand
+	components map[string]*Status
+}
+
+func New(backoff Backoff) *Supervisor {
+	if backoff.Initial <= 0 {
+		backoff.Initial = DefaultBackoff.Initial
+	}
+	if backoff.Max < backoff.Initial {
+		backoff.Max = max(DefaultBackoff.Max, backoff.Initial)
+	}
+	return &Supervisor{
+		backoff:    backoff,
+		now:        time.Now,
+		rng:        rand.New(rand.NewSource(time.Now().UnixNano())),
+		components: make(map[string]*Status),
+	}
+}
+
+// Go 在后台运行组件，见 Run
+func (s *Supervisor) Go(ctx context.Context, name string, fn RunFunc) {
+	go s.Run(ctx, name, fn)
+}
+
+// Run 运行组件直到 ctx 取消：每次 fn 返回后等待退避时长再重新运行。
+// 本次运行建立过连接时，下一次退避从 Initial 重新开始
+func (s *Supervisor) Run(ctx context.Context, name string, fn RunFunc) {
+	attempt := 0
+	for {
+		s.setState(name, StateConnecting, nil)
+		connected := false
+		err := s.runOnce(ctx, fn, func() {
+			connected = true
+			s.setState(name, StateConnected, nil)
+		})
+		if ctx.Err() != nil {
+			s.setState(name, StateStopped, nil)
+			return
+		}
+		if err == nil {
+			err = fmt.Errorf("%s exited", name)
+		}
+
+		if connected {
+			attempt = 0
+		}
+		attempt++
+		s.mu.Lock()
+		delay := s.backoff.delay(attempt, s.rng.Float64())
+		s.mu.Unlock()
+		s.setState(name, StateBackoff, err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			s.setState(name, StateStopped, nil)
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// runOnce 运行一次组件，panic 视为运行失败
+func (s *Supervisor) runOnce(ctx context.Context, fn RunFunc, connected func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fn(ctx, connected)
+}
+
+func (s *Supervisor) setState(name string, state State, err error) {
+	s.mu.Lock()
+	status, ok := s.components[name]
+	if !ok {
+		status = &Status{Name: name}
+		s.components[name] = status
+	}
+	if state == StateBackoff {
+		status.Restarts++
+	}
+	if err != nil {
+		status.LastError = err.Error()
+	}
+	status.State = state
+	status.Since = s.now()
+	snapshot := *status
+	onChange := s.OnStateChange
+	s.mu.Unlock()
+
+	if onChange != nil {
+		onChange(snapshot)
+	}
+}
+
+// Status 返回各组件的连接状态，按名称排序
+func (s *Supervisor) Status() []Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := make([]Status, 0, len(s.components))
+	for _, status := range s.components {
+		result = append(result, *status)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// Check 健康检查，有组件未连接时返回错误；已停止的组件不计入
+func (s *Supervisor) Check(context.Context) error {
+	var errs []error
+	for _, status := range s.Status() {
+		switch status.State {
+		case StateConnected, StateStopped:
+			continue
+		}
+		if status.LastError != "" {
+			errs = append(errs, fmt.Errorf("%s %s: %s", status.Name, status.State, status.LastError))
+		} else {
+			errs = append(errs, fmt.Errorf("%s %s", status.Name, status.State))
+		}
+	}
+	return errors.Join(errs...)
+}
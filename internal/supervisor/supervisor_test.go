@@ -0,0 +1,106 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackoff_Delay(t *testing.T) {
+	b := Backoff{Initial: time.Second, Max: 5 * time.Second}
+	assert.Equal(t, time.Second, b.delay(1, 0.5))
+	assert.Equal(t, 2*time.Second, b.delay(2, 0.5))
+	assert.Equal(t, 4*time.Second, b.delay(3, 0.5))
+	assert.Equal(t, 5*time.Second, b.delay(4, 0.5))
+	assert.Equal(t, 5*time.Second, b.delay(100, 0.5))
+
+	// 抖动只缩短等待时长
+	b.Jitter = 0.2
+	assert.Equal(t, 4*time.Second, b.delay(3, 0))
+	assert.Equal(t, 3600*time.Millisecond, b.delay(3, 0.5))
+}
+
+func TestSupervisor_Run(t *testing.T) {
+	s := New(Backoff{Initial: time.Millisecond, Max: 2 * time.Millisecond})
+
+	var (
+		mu     sync.Mutex
+		states []State
+	)
+	s.OnStateChange = func(status Status) {
+		mu.Lock()
+		defer mu.Unlock()
+		states = append(states, status.State)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runs := 0
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.Run(ctx, "stream", func(ctx context.Context, connected func()) error {
+			runs++
+			switch runs {
+			case 1:
+				return errors.New("dial failed")
+			case 2:
+				panic("boom")
+			default:
+				connected()
+				<-ctx.Done()
+				return ctx.Err()
+			}
+		})
+	}()
+
+	require.Eventually(t, func() bool {
+		status := s.Status()
+		return len(status) == 1 && status[0].State == StateConnected
+	}, time.Second, time.Millisecond)
+
+	status := s.Status()[0]
+	assert.Equal(t, "stream", status.Name)
+	assert.Equal(t, 2, status.Restarts)
+	assert.Equal(t, "panic: boom", status.LastError)
+	assert.NoError(t, s.Check(ctx))
+
+	cancel()
+	<-done
+	assert.Equal(t, StateStopped, s.Status()[0].State)
+	assert.NoError(t, s.Check(context.Background()))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []State{
+		StateConnecting, StateBackoff,
+		StateConnecting, StateBackoff,
+		StateConnecting, StateConnected,
+		StateStopped,
+	}, states)
+}
+
+func TestSupervisor_Check(t *testing.T) {
+	s := New(Backoff{Initial: time.Hour})
+	assert.NoError(t, s.Check(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.Go(ctx, "stream", func(context.Context, func()) error {
+		return errors.New("dial failed")
+	})
+
+	require.Eventually(t, func() bool {
+		status := s.Status()
+		return len(status) == 1 && status[0].State == StateBackoff
+	}, time.Second, time.Millisecond)
+	err := s.Check(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "stream backoff: dial failed")
+}
@@ -0,0 +1,75 @@
+package tracing
+
+import (
+	"sync"
+	"time"
+)
+
+// Logger 日志接口
+type Logger interface {
+	Info(msg string, fields ...interface{})
+}
+
+// Recorder 在内存中保留最近的调用链，供 API 查询
+type Recorder struct {
+	max int
+
+	mu     sync.RWMutex
+	traces []Trace
+}
+
+func NewRecorder(max int) *Recorder {
+	if max <= 0 {
+		max = 100
+	}
+	return &Recorder{max: max}
+}
+
+// Export implements Exporter
+func (r *Recorder) Export(trace Trace) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.traces = append(r.traces, trace)
+	if len(r.traces) > r.max {
+		r.traces = r.traces[len(r.traces)-r.max:]
+	}
+}
+
+// Traces 返回最近的调用链，按时间先后排列
+func (r *Recorder) Traces() []Trace {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]Trace, len(r.traces))
+	copy(result, r.traces)
+	return result
+}
+
+// LogExporter 记录耗时超过阈值的调用链及各步骤耗时
+type LogExporter struct {
+	logger    Logger
+	threshold time.Duration
+}
+
+func NewLogExporter(logger Logger, threshold time.Duration) *LogExporter {
+	return &LogExporter{logger: logger, threshold: threshold}
+}
+
+// Export implements Exporter
+func (e *LogExporter) Export(trace Trace) {
+	if trace.Duration < e.threshold {
+		return
+	}
+
+	steps := make(map[string]string, len(trace.Spans))
+	for _, span := range trace.Spans {
+		steps[span.Name] = span.Duration.String()
+	}
+	e.logger.Info("slow trace",
+		"trace_id", trace.TraceID,
+		"name", trace.Name,
+		"duration", trace.Duration.String(),
+		"spans", steps,
+	)
+}
@@ -0,0 +1,32 @@
+package tracing
+
+import (
+	"time"
+)
+
+// Exporter receives completed traces
+type Exporter interface {
+	// Export is called once the root span of a trace has ended
+	Export(trace Trace)
+}
+
+// Trace 一次完整调用链，包含根 span 及其所有子 span
+type Trace struct {
+	TraceID  string        `json:"trace_id"`
+	Name     string        `json:"name"`
+	Start    time.Time     `json:"start"`
+	Duration time.Duration `json:"duration"`
+	Spans    []SpanData    `json:"spans"`
+}
+
+// SpanData 已结束 span 的数据
+type SpanData struct {
+	TraceID    string                 `json:"trace_id"`
+	SpanID     string                 `json:"span_id"`
+	ParentID   string                 `json:"parent_id,omitempty"`
+	Name       string                 `json:"name"`
+	Start      time.Time              `json:"start"`
+	Duration   time.Duration          `json:"duration"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+}
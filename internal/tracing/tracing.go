@@ -0,0 +1,155 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sort"
+	"sync"
+	"time"
+)
+
+type spanKey struct{}
+
+// Tracer 创建 span 并在根 span 结束时导出整条调用链
+// 为 nil 时所有操作均为空操作，便于在未开启追踪时直接调用
+type Tracer struct {
+	exporters []Exporter
+}
+
+func NewTracer(exporters ...Exporter) *Tracer {
+	return &Tracer{exporters: exporters}
+}
+
+// Span 调用链中的一个步骤，通过 ctx 传递父子关系
+type Span struct {
+	tracer *Tracer
+	trace  *traceBuffer
+	data   SpanData
+
+	mu    sync.Mutex
+	ended bool
+}
+
+// traceBuffer 收集同一调用链中已结束的 span
+type traceBuffer struct {
+	mu    sync.Mutex
+	spans []SpanData
+}
+
+// Start 开始一个 span，ctx 中已有 span 时作为其子 span
+func (t *Tracer) Start(ctx context.Context, name string, attrs ...interface{}) (context.Context, *Span) {
+	if t == nil {
+		return ctx, nil
+	}
+
+	span := &Span{
+		tracer: t,
+		data: SpanData{
+			SpanID: newID(8),
+			Name:   name,
+			Start:  time.Now(),
+		},
+	}
+
+	if parent := SpanFromContext(ctx); parent != nil {
+		span.trace = parent.trace
+		span.data.TraceID = parent.data.TraceID
+		span.data.ParentID = parent.data.SpanID
+	} else {
+		span.trace = &traceBuffer{}
+		span.data.TraceID = newID(16)
+	}
+
+	span.SetAttributes(attrs...)
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// SpanFromContext 返回 ctx 中当前的 span，没有时返回 nil
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// SetAttributes 以 key, value 交替的形式设置属性
+func (s *Span) SetAttributes(attrs ...interface{}) {
+	if s == nil || len(attrs) == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.data.Attributes == nil {
+		s.data.Attributes = make(map[string]interface{}, len(attrs)/2)
+	}
+	for i := 0; i+1 < len(attrs); i += 2 {
+		if key, ok := attrs[i].(string); ok {
+			s.data.Attributes[key] = attrs[i+1]
+		}
+	}
+}
+
+// RecordError 记录错误，err 为 nil 时忽略
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Error = err.Error()
+}
+
+// End 结束 span，根 span 结束时导出整条调用链，重复调用无效
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.Duration = time.Since(s.data.Start)
+	data := s.data
+	s.mu.Unlock()
+
+	s.trace.mu.Lock()
+	s.trace.spans = append(s.trace.spans, data)
+	s.trace.mu.Unlock()
+
+	if data.ParentID == "" {
+		s.tracer.export(s.trace, data)
+	}
+}
+
+func (t *Tracer) export(buf *traceBuffer, root SpanData) {
+	buf.mu.Lock()
+	spans := make([]SpanData, len(buf.spans))
+	copy(spans, buf.spans)
+	buf.mu.Unlock()
+
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].Start.Before(spans[j].Start)
+	})
+
+	trace := Trace{
+		TraceID:  root.TraceID,
+		Name:     root.Name,
+		Start:    root.Start,
+		Duration: root.Duration,
+		Spans:    spans,
+	}
+	for _, exporter := range t.exporters {
+		exporter.Export(trace)
+	}
+}
+
+func newID(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
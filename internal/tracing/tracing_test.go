@@ -0,0 +1,63 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracer(t *testing.T) {
+	recorder := NewRecorder(10)
+	tracer := NewTracer(recorder)
+
+	ctx, root := tracer.Start(context.Background(), "tick", "symbol", "BTCUSDT")
+	_, save := tracer.Start(ctx, "storage.save_market_data")
+	save.End()
+	_, predict := tracer.Start(ctx, "ai.predict_price")
+	predict.RecordError(errors.New("timeout"))
+	predict.End()
+
+	assert.Empty(t, recorder.Traces(), "trace is exported when the root span ends")
+	root.End()
+	root.End()
+
+	traces := recorder.Traces()
+	require.Len(t, traces, 1)
+	trace := traces[0]
+	assert.Equal(t, "tick", trace.Name)
+	require.Len(t, trace.Spans, 3)
+
+	assert.Equal(t, "tick", trace.Spans[0].Name)
+	assert.Equal(t, "BTCUSDT", trace.Spans[0].Attributes["symbol"])
+	for _, span := range trace.Spans[1:] {
+		assert.Equal(t, trace.TraceID, span.TraceID)
+		assert.Equal(t, trace.Spans[0].SpanID, span.ParentID)
+	}
+	assert.Equal(t, "timeout", trace.Spans[2].Error)
+}
+
+func TestTracer_Nil(t *testing.T) {
+	var tracer *Tracer
+	ctx, span := tracer.Start(context.Background(), "tick")
+	assert.Nil(t, span)
+	assert.Nil(t, SpanFromContext(ctx))
+
+	span.SetAttributes("symbol", "BTCUSDT")
+	span.RecordError(errors.New("ignored"))
+	span.End()
+}
+
+func TestRecorder_Max(t *testing.T) {
+	recorder := NewRecorder(2)
+	for _, name := range []string{"a", "b", "c"} {
+		recorder.Export(Trace{Name: name})
+	}
+
+	traces := recorder.Traces()
+	require.Len(t, traces, 2)
+	assert.Equal(t, "b", traces[0].Name)
+	assert.Equal(t, "c", traces[1].Name)
+}
@@ -2,21 +2,45 @@ package binance
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/songzhibin97/quantaflux/internal/exchangeinfo"
+	"github.com/songzhibin97/quantaflux/internal/money"
+	"github.com/songzhibin97/quantaflux/internal/precision"
 	"github.com/songzhibin97/quantaflux/internal/trading"
 
 	"github.com/adshao/go-binance/v2"
+	"github.com/adshao/go-binance/v2/common"
+	"github.com/shopspring/decimal"
 )
 
 // BinanceExecutor implements TradeExecutor interface for Binance
 type BinanceExecutor struct {
-	client    *binance.Client
-	apiKey    string
-	secretKey string
-	mu        sync.RWMutex
+	client     *binance.Client
+	apiKey     string
+	secretKey  string
+	recvWindow int64 // 签名请求的有效时间窗口（毫秒），0 使用交易所默认值
+	mu         sync.RWMutex
+
+	// 共享的交易所元数据缓存，下单前按过滤条件检查，未设置时不检查
+	exchangeInfo *exchangeinfo.Service
+	// 按交易对精度格式化下单的价格和数量
+	format *precision.Formatter
+
+	// 需要在下一次签名请求前同步服务器时间：创建时和时间戳被拒绝（-1021）后设置
+	needsTimeSync atomic.Bool
+
+	// 下单前检查余额使用的可用余额缓存，balanceTTL 为 0 时不检查
+	balanceMu  sync.Mutex
+	balanceTTL time.Duration
+	balances   map[string]float64
+	balancesAt time.Time
 }
 
 // NewBinanceExecutor creates a new BinanceExecutor instance
@@ -28,18 +52,131 @@ func NewBinanceExecutor(apiKey, secretKey string, debug ...bool) *BinanceExecuto
 
 	client := binance.NewClient(apiKey, secretKey)
 
-	return &BinanceExecutor{
+	executor := &BinanceExecutor{
 		client:    client,
 		apiKey:    apiKey,
 		secretKey: secretKey,
+		format:    precision.NewFormatter(nil),
 	}
+	executor.needsTimeSync.Store(true)
+	return executor
 }
 
-// PlaceOrder implements order placement for Binance
-func (b *BinanceExecutor) PlaceOrder(ctx context.Context, order *trading.Order) error {
+// SetRecvWindow 设置签名请求的有效时间窗口，0 使用交易所默认值（5s）
+func (b *BinanceExecutor) SetRecvWindow(window time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.recvWindow = window.Milliseconds()
+}
+
+// SetBalanceCheck 开启下单前的余额检查：可用余额缓存超过 ttl 时重新查询账户，
+// 下单成功后在缓存中扣除占用的资金，余额不足的订单在本地拒绝；ttl 为 0 时不检查
+func (b *BinanceExecutor) SetBalanceCheck(ttl time.Duration) {
+	b.balanceMu.Lock()
+	defer b.balanceMu.Unlock()
+
+	b.balanceTTL = ttl
+	b.balancesAt = time.Time{}
+}
+
+// SetExchangeInfo 设置交易所元数据缓存，低于最小下单数量或金额的订单在本地拒绝，不再请求交易所；
+// 下单的价格和数量按缓存的价格和数量步长格式化
+func (b *BinanceExecutor) SetExchangeInfo(info *exchangeinfo.Service) {
+	b.exchangeInfo = info
+	if info != nil {
+		b.format = precision.NewFormatter(info)
+	}
+}
+
+// checkFilters 按缓存的过滤条件检查订单，交易对不在缓存中时交由交易所校验
+func (b *BinanceExecutor) checkFilters(order *trading.Order, quantity, notional float64) error {
+	if b.exchangeInfo == nil {
+		return nil
+	}
+	info, ok := b.exchangeInfo.Symbol(order.Symbol)
+	if !ok {
+		return nil
+	}
+	if err := info.Check(quantity, notional); err != nil {
+		return fmt.Errorf("%w: %w", trading.ErrOrderRejected, err)
+	}
+	return nil
+}
+
+// SyncTime implements trading.TimeSyncer，签名请求的时间戳按服务器时间校准
+func (b *BinanceExecutor) SyncTime(ctx context.Context) (time.Duration, error) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
+	offset, err := b.client.NewSetServerTimeService().Do(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to sync server time: %w", wrapError(err))
+	}
+	b.needsTimeSync.Store(false)
+	return time.Duration(offset) * time.Millisecond, nil
+}
+
+// ensureTimeSync 在签名请求前按需同步服务器时间，需在持有锁之前调用
+func (b *BinanceExecutor) ensureTimeSync(ctx context.Context) error {
+	if !b.needsTimeSync.Load() {
+		return nil
+	}
+	_, err := b.SyncTime(ctx)
+	return err
+}
+
+// requestOptions 返回签名请求的公共参数，需在持有锁时调用
+func (b *BinanceExecutor) requestOptions() []binance.RequestOption {
+	if b.recvWindow <= 0 {
+		return nil
+	}
+	return []binance.RequestOption{binance.WithRecvWindow(b.recvWindow)}
+}
+
+// checkTimestamp 时间戳被拒绝时标记下一次请求前重新同步服务器时间
+func (b *BinanceExecutor) checkTimestamp(err error) bool {
+	var apiErr *common.APIError
+	if errors.As(err, &apiErr) && apiErr.Code == -1021 {
+		b.needsTimeSync.Store(true)
+		return true
+	}
+	return false
+}
+
+// signed 执行签名请求：按需先同步服务器时间，时间戳被拒绝时重新同步后重试一次，
+// 返回的错误已映射为领域错误
+func (b *BinanceExecutor) signed(ctx context.Context, do func(opts ...binance.RequestOption) error) error {
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if err := b.ensureTimeSync(ctx); err != nil {
+			return err
+		}
+
+		b.mu.RLock()
+		err = do(b.requestOptions()...)
+		b.mu.RUnlock()
+
+		if err == nil {
+			return nil
+		}
+		if !b.checkTimestamp(err) {
+			break
+		}
+	}
+	return wrapError(err)
+}
+
+// Ping checks connectivity to the Binance API
+func (b *BinanceExecutor) Ping(ctx context.Context) error {
+	if err := b.client.NewPingService().Do(ctx); err != nil {
+		return fmt.Errorf("failed to ping binance: %w", wrapError(err))
+	}
+	return nil
+}
+
+// PlaceOrder implements order placement for Binance
+func (b *BinanceExecutor) PlaceOrder(ctx context.Context, order *trading.Order) error {
 	// Convert order type to Binance format
 	var orderType binance.OrderType
 	switch order.OrderType {
@@ -48,7 +185,7 @@ func (b *BinanceExecutor) PlaceOrder(ctx context.Context, order *trading.Order)
 	case "limit":
 		orderType = binance.OrderTypeLimit
 	default:
-		return fmt.Errorf("unsupported order type: %s", order.OrderType)
+		return fmt.Errorf("%w: unsupported order type: %s", trading.ErrOrderRejected, order.OrderType)
 	}
 
 	// Convert side to Binance format
@@ -59,54 +196,202 @@ func (b *BinanceExecutor) PlaceOrder(ctx context.Context, order *trading.Order)
 	case "sell":
 		side = binance.SideTypeSell
 	default:
-		return fmt.Errorf("invalid side: %s", order.Side)
+		return fmt.Errorf("%w: invalid side: %s", trading.ErrOrderRejected, order.Side)
+	}
+
+	// 客户端订单号由调用方指定时用于幂等下单，未指定时生成
+	if order.ClientOrderID == "" {
+		order.ClientOrderID = trading.NewClientOrderID()
 	}
 
 	// Create order request
 	orderService := b.client.NewCreateOrderService().
 		Symbol(order.Symbol).
 		Side(side).
-		Type(orderType)
+		Type(orderType).
+		NewClientOrderID(order.ClientOrderID)
 
-	// Set quantity
-	quantity := strconv.FormatFloat(order.Amount, 'f', -1, 64)
-	orderService.Quantity(quantity)
+	// 市价单按计价资产金额下单（quoteOrderQty），其余按数量下单，限价单未设置数量时按金额和委托价格换算
+	if order.UsesQuoteAmount() {
+		if err := b.checkFilters(order, 0, order.QuoteAmount); err != nil {
+			return err
+		}
+		orderService.QuoteOrderQty(b.format.Quote(order.Symbol, order.QuoteAmount))
+	} else {
+		amount := order.BaseAmount(order.Price)
+		if amount <= 0 {
+			return fmt.Errorf("%w: invalid amount: %s", trading.ErrOrderRejected, precision.String(amount))
+		}
+		if err := b.checkFilters(order, amount, amount*order.Price); err != nil {
+			return err
+		}
+		order.Amount = amount
+		orderService.Quantity(b.format.Quantity(order.Symbol, amount))
+	}
+
+	if err := b.checkFunds(ctx, order); err != nil {
+		return err
+	}
 
 	// Set price for limit orders
 	if orderType == binance.OrderTypeLimit {
-		price := strconv.FormatFloat(order.Price, 'f', -1, 64)
 		orderService.TimeInForce(binance.TimeInForceTypeGTC)
-		orderService.Price(price)
+		orderService.Price(b.format.Price(order.Symbol, order.Price))
 	}
 
 	// Execute order
-	result, err := orderService.Do(ctx)
+	var result *binance.CreateOrderResponse
+	err := b.signed(ctx, func(opts ...binance.RequestOption) (err error) {
+		result, err = orderService.Do(ctx, opts...)
+		return err
+	})
 	if err != nil {
+		if errors.Is(err, trading.ErrInsufficientFunds) {
+			b.invalidateBalances()
+		}
 		return fmt.Errorf("failed to place order: %w", err)
 	}
+	b.reserveFunds(order)
 
 	// Update order with response data
 	order.Status = string(result.Status)
 	order.RawOrderID = result.OrderID
 	order.OrderID = strconv.FormatInt(result.OrderID, 10)
+	order.FilledAmount, _ = strconv.ParseFloat(result.ExecutedQuantity, 64)
+	order.FilledPrice = averagePrice(result.ExecutedQuantity, result.CummulativeQuoteQuantity)
+	if order.UsesQuoteAmount() {
+		order.Amount = order.FilledAmount
+	}
+	order.CreatedAt = time.UnixMilli(result.TransactTime)
+	order.UpdatedAt = order.CreatedAt
+	fee := decimal.Zero
+	for _, fill := range result.Fills {
+		commission, _ := money.Parse(fill.Commission)
+		fee = fee.Add(commission)
+		order.FeeAsset = fill.CommissionAsset
+	}
+	order.Fee = money.Float(fee)
 	return nil
 }
 
+// PlaceOCO implements trading.OCOExecutor：止盈为 LIMIT_MAKER 限价单，止损为触发后按市价成交的 STOP_LOSS 单
+func (b *BinanceExecutor) PlaceOCO(ctx context.Context, oco *trading.OCOOrder) error {
+	var side binance.SideType
+	switch oco.Side {
+	case "buy":
+		side = binance.SideTypeBuy
+	case "sell":
+		side = binance.SideTypeSell
+	default:
+		return fmt.Errorf("%w: invalid side: %s", trading.ErrOrderRejected, oco.Side)
+	}
+	if err := b.checkFilters(&trading.Order{Symbol: oco.Symbol}, oco.Amount, oco.Amount*oco.TakeProfit); err != nil {
+		return err
+	}
+
+	ocoService := b.client.NewCreateOCOService().
+		Symbol(oco.Symbol).
+		Side(side).
+		Quantity(b.format.Quantity(oco.Symbol, oco.Amount)).
+		Price(b.format.Price(oco.Symbol, oco.TakeProfit)).
+		StopPrice(b.format.Price(oco.Symbol, oco.StopLoss))
+
+	var result *binance.CreateOCOResponse
+	err := b.signed(ctx, func(opts ...binance.RequestOption) (err error) {
+		result, err = ocoService.Do(ctx, opts...)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to place oco order: %w", err)
+	}
+
+	oco.ListID = strconv.FormatInt(result.OrderListID, 10)
+	for _, report := range result.OrderReports {
+		price, _ := strconv.ParseFloat(report.Price, 64)
+		order := trading.Order{
+			Symbol:         report.Symbol,
+			Side:           oco.Side,
+			Amount:         oco.Amount,
+			Price:          price,
+			SubmittedPrice: price,
+			OrderType:      "limit",
+			Status:         string(report.Status),
+			OrderID:        strconv.FormatInt(report.OrderID, 10),
+			RawOrderID:     report.OrderID,
+			ClientOrderID:  report.ClientOrderID,
+			FilledPrice:    averagePrice(report.ExecutedQuantity, report.CummulativeQuoteQuantity),
+			CreatedAt:      time.UnixMilli(result.TransactionTime),
+			UpdatedAt:      time.UnixMilli(result.TransactionTime),
+		}
+		order.FilledAmount, _ = strconv.ParseFloat(report.ExecutedQuantity, 64)
+		if report.Type == binance.OrderTypeLimitMaker {
+			oco.ProfitOrder = order
+			continue
+		}
+		order.Price, order.SubmittedPrice, order.OrderType = oco.StopLoss, 0, "market"
+		oco.StopOrder = order
+	}
+	return nil
+}
+
+// CancelOCO implements trading.OCOExecutor
+func (b *BinanceExecutor) CancelOCO(ctx context.Context, symbol, listID string) error {
+	id, err := strconv.ParseInt(listID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid order list ID: %w", err)
+	}
+
+	err = b.signed(ctx, func(opts ...binance.RequestOption) error {
+		_, err := b.client.NewCancelOCOService().
+			Symbol(symbol).
+			OrderListID(id).
+			Do(ctx, opts...)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to cancel oco order: %w", err)
+	}
+	return nil
+}
+
+// averagePrice 由成交数量和成交金额计算成交均价，未成交时返回 0
+func averagePrice(executedQty, quoteQty string) float64 {
+	qty, err := money.Parse(executedQty)
+	if err != nil || !qty.IsPositive() {
+		return 0
+	}
+	quote, _ := money.Parse(quoteQty)
+	return money.Float(quote.Div(qty))
+}
+
+// BestQuote implements trading.QuoteProvider，查询盘口买一卖一价
+func (b *BinanceExecutor) BestQuote(ctx context.Context, symbol string) (trading.Quote, error) {
+	tickers, err := b.client.NewListBookTickersService().Symbol(symbol).Do(ctx)
+	if err != nil {
+		return trading.Quote{}, fmt.Errorf("failed to get book ticker: %w", wrapError(err))
+	}
+	for _, ticker := range tickers {
+		if ticker.Symbol == symbol {
+			return trading.Quote{Bid: money.ParseFloat(ticker.BidPrice), Ask: money.ParseFloat(ticker.AskPrice)}, nil
+		}
+	}
+	return trading.Quote{}, fmt.Errorf("%w: no book ticker for %s", trading.ErrExchangeUnavailable, symbol)
+}
+
 // CancelOrder implements order cancellation for Binance
 func (b *BinanceExecutor) CancelOrder(ctx context.Context, symbol string, orderID string) error {
-	b.mu.Lock()
-	defer b.mu.Unlock()
-
 	id, err := strconv.ParseInt(orderID, 10, 64)
 	if err != nil {
 		return fmt.Errorf("invalid order ID: %w", err)
 	}
 
-	_, err = b.client.NewCancelOrderService().
-		Symbol(symbol).
-		OrderID(id).
-		Do(ctx)
-
+	err = b.signed(ctx, func(opts ...binance.RequestOption) error {
+		_, err := b.client.NewCancelOrderService().
+			Symbol(symbol).
+			OrderID(id).
+			Do(ctx, opts...)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to cancel order: %w", err)
 	}
@@ -116,59 +401,189 @@ func (b *BinanceExecutor) CancelOrder(ctx context.Context, symbol string, orderI
 
 // GetOrderStatus implements order status retrieval for Binance
 func (b *BinanceExecutor) GetOrderStatus(ctx context.Context, symbol, orderID string) (*trading.Order, error) {
-	b.mu.RLock()
-	defer b.mu.RUnlock()
-
 	id, err := strconv.ParseInt(orderID, 10, 64)
 	if err != nil {
 		return nil, fmt.Errorf("invalid order ID: %w", err)
 	}
 
-	result, err := b.client.NewGetOrderService().
-		Symbol(symbol).
-		OrderID(id).
-		Do(ctx)
-
+	var result *binance.Order
+	err = b.signed(ctx, func(opts ...binance.RequestOption) (err error) {
+		result, err = b.client.NewGetOrderService().
+			Symbol(symbol).
+			OrderID(id).
+			Do(ctx, opts...)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get order status: %w", err)
 	}
 
 	price, _ := strconv.ParseFloat(result.Price, 64)
 	amount, _ := strconv.ParseFloat(result.OrigQuantity, 64)
+	filled, _ := strconv.ParseFloat(result.ExecutedQuantity, 64)
+
+	order := &trading.Order{
+		Symbol:        result.Symbol,
+		Side:          string(result.Side),
+		Amount:        amount,
+		Price:         price,
+		OrderType:     string(result.Type),
+		Status:        string(result.Status),
+		OrderID:       strconv.FormatInt(result.OrderID, 10),
+		RawOrderID:    result.OrderID,
+		ClientOrderID: result.ClientOrderID,
+		FilledAmount:  filled,
+		FilledPrice:   averagePrice(result.ExecutedQuantity, result.CummulativeQuoteQuantity),
+		CreatedAt:     time.UnixMilli(result.Time),
+		UpdatedAt:     time.UnixMilli(result.UpdateTime),
+	}
+
+	// 订单查询不返回手续费，有成交时从成交记录汇总
+	if filled > 0 {
+		if err := b.orderFees(ctx, order); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
 
-	return &trading.Order{
-		Symbol:     result.Symbol,
-		Side:       string(result.Side),
-		Amount:     amount,
-		Price:      price,
-		OrderType:  string(result.Type),
-		Status:     string(result.Status),
-		OrderID:    strconv.FormatInt(result.OrderID, 10),
-		RawOrderID: result.OrderID,
-	}, nil
+// orderFees 从订单的成交记录汇总手续费
+func (b *BinanceExecutor) orderFees(ctx context.Context, order *trading.Order) error {
+	var trades []*binance.TradeV3
+	err := b.signed(ctx, func(opts ...binance.RequestOption) (err error) {
+		trades, err = b.client.NewListTradesService().
+			Symbol(order.Symbol).
+			OrderId(order.RawOrderID).
+			Do(ctx, opts...)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get order trades: %w", err)
+	}
+
+	fee := money.FromFloat(order.Fee)
+	for _, trade := range trades {
+		commission, _ := money.Parse(trade.Commission)
+		fee = fee.Add(commission)
+		order.FeeAsset = trade.CommissionAsset
+	}
+	order.Fee = money.Float(fee)
+	return nil
 }
 
 // GetBalance implements balance retrieval for Binance
 func (b *BinanceExecutor) GetBalance(ctx context.Context, symbol string) (float64, error) {
-	b.mu.RLock()
-	defer b.mu.RUnlock()
-
-	// Get account information
-	account, err := b.client.NewGetAccountService().Do(ctx)
+	balances, err := b.fetchBalances(ctx)
 	if err != nil {
-		return 0, fmt.Errorf("failed to get account info: %w", err)
+		return 0, err
 	}
 
 	// Find balance for specified symbol
+	free, ok := balances[symbol]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", trading.ErrBalanceNotFound, symbol)
+	}
+	return free, nil
+}
+
+// fetchBalances 查询账户所有资产的可用余额，同时更新下单前检查使用的缓存
+func (b *BinanceExecutor) fetchBalances(ctx context.Context) (map[string]float64, error) {
+	var account *binance.Account
+	err := b.signed(ctx, func(opts ...binance.RequestOption) (err error) {
+		account, err = b.client.NewGetAccountService().Do(ctx, opts...)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account info: %w", err)
+	}
+
+	balances := make(map[string]float64, len(account.Balances))
 	for _, balance := range account.Balances {
-		if balance.Asset == symbol {
-			free, err := strconv.ParseFloat(balance.Free, 64)
-			if err != nil {
-				return 0, fmt.Errorf("failed to parse balance: %w", err)
-			}
-			return free, nil
+		free, err := strconv.ParseFloat(balance.Free, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse balance: %w", err)
 		}
+		balances[balance.Asset] = free
 	}
 
-	return 0, fmt.Errorf("balance not found for symbol: %s", symbol)
+	b.balanceMu.Lock()
+	b.balances = balances
+	b.balancesAt = time.Now()
+	b.balanceMu.Unlock()
+	return balances, nil
+}
+
+// checkFunds 未开启余额检查时直接返回；缓存过期时重新查询余额，按委托价格（市价单为参考价格）检查订单，
+// 价格未知的市价买单不检查
+func (b *BinanceExecutor) checkFunds(ctx context.Context, order *trading.Order) error {
+	b.balanceMu.Lock()
+	ttl, fresh := b.balanceTTL, time.Since(b.balancesAt) < b.balanceTTL
+	b.balanceMu.Unlock()
+	if ttl <= 0 {
+		return nil
+	}
+	if !fresh {
+		if _, err := b.fetchBalances(ctx); err != nil {
+			return err
+		}
+	}
+
+	b.balanceMu.Lock()
+	defer b.balanceMu.Unlock()
+	return trading.CheckFunds(order, order.Price, func(asset string) float64 { return b.balances[asset] })
+}
+
+// reserveFunds 下单成功后在缓存中扣除订单占用的资金，买入得到的资产等下次查询账户时更新
+func (b *BinanceExecutor) reserveFunds(order *trading.Order) {
+	b.balanceMu.Lock()
+	defer b.balanceMu.Unlock()
+
+	if b.balanceTTL <= 0 || b.balances == nil {
+		return
+	}
+	if asset, amount, ok := trading.RequiredFunds(order, order.Price); ok {
+		b.balances[asset] = money.Sub(b.balances[asset], amount)
+	}
+}
+
+// invalidateBalances 交易所报告余额不足时丢弃缓存，下一次下单前重新查询
+func (b *BinanceExecutor) invalidateBalances() {
+	b.balanceMu.Lock()
+	defer b.balanceMu.Unlock()
+	b.balancesAt = time.Time{}
+}
+
+// wrapError 将 Binance API 错误映射为 trading 包的领域错误
+func wrapError(err error) error {
+	var apiErr *common.APIError
+	if !errors.As(err, &apiErr) {
+		// 非 API 错误通常是网络问题
+		return fmt.Errorf("%w: %w", trading.ErrExchangeUnavailable, err)
+	}
+
+	switch apiErr.Code {
+	case -1022, -2014, -2015:
+		// 签名错误、API 密钥格式错误或无权限
+		return fmt.Errorf("%w: %w", trading.ErrUnauthorized, err)
+	case -2011, -2013:
+		// 撤单或查询的订单不存在
+		return fmt.Errorf("%w: %w", trading.ErrOrderNotFound, err)
+	case -2010:
+		// 下单被拒绝，余额不足时同时映射为 ErrInsufficientFunds
+		if strings.Contains(strings.ToLower(apiErr.Message), "insufficient balance") {
+			return fmt.Errorf("%w: %w: %w", trading.ErrOrderRejected, trading.ErrInsufficientFunds, err)
+		}
+		return fmt.Errorf("%w: %w", trading.ErrOrderRejected, err)
+	case -1013, -1100, -1111, -1121:
+		// 过滤器校验失败、参数不合法或交易对无效
+		return fmt.Errorf("%w: %w", trading.ErrOrderRejected, err)
+	case -1001, -1003, -1015:
+		// 服务内部错误或请求频率超限
+		return fmt.Errorf("%w: %w", trading.ErrExchangeUnavailable, err)
+	case -1021:
+		// 时间戳超出 recvWindow，重新同步服务器时间后仍被拒绝
+		return fmt.Errorf("%w: %w", trading.ErrTimestampRejected, err)
+	default:
+		return err
+	}
 }
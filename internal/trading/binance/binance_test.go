@@ -2,17 +2,26 @@ package binance
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"strconv"
 	"testing"
 	"time"
 
 	"github.com/adshao/go-binance/v2"
+	"github.com/adshao/go-binance/v2/common"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/songzhibin97/quantaflux/internal/exchangeinfo"
 	"github.com/songzhibin97/quantaflux/internal/trading"
+	"github.com/songzhibin97/quantaflux/internal/trading/binance/binancetest"
 )
 
 func init() {
@@ -44,6 +53,9 @@ func TestBinanceExecutor_Integration(t *testing.T) {
 
 	apiKey := os.Getenv("BINANCE_API_KEY")
 	secretKey := os.Getenv("BINANCE_SECRET_KEY")
+	if apiKey == "" || secretKey == "" {
+		t.Skip("BINANCE_API_KEY and BINANCE_SECRET_KEY not set")
+	}
 
 	executor := NewBinanceExecutor(apiKey, secretKey)
 	ctx := context.Background()
@@ -159,3 +171,440 @@ func TestBinanceExecutor_Integration(t *testing.T) {
 		require.Equal(t, "FILLED", orderStatus.Status)
 	})
 }
+
+func TestWrapError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"network", errors.New("connection reset"), trading.ErrExchangeUnavailable},
+		{"invalid api key", &common.APIError{Code: -2015, Message: "Invalid API-key"}, trading.ErrUnauthorized},
+		{"insufficient balance", &common.APIError{Code: -2010, Message: "Account has insufficient balance"}, trading.ErrInsufficientFunds},
+		{"order rejected", &common.APIError{Code: -2010, Message: "Order would immediately match and take."}, trading.ErrOrderRejected},
+		{"unknown order", &common.APIError{Code: -2013, Message: "Order does not exist"}, trading.ErrOrderNotFound},
+		{"rate limit", &common.APIError{Code: -1003, Message: "Too many requests"}, trading.ErrExchangeUnavailable},
+		{"timestamp outside recv window", &common.APIError{Code: -1021, Message: "Timestamp for this request is outside of the recvWindow."}, trading.ErrTimestampRejected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, wrapError(tt.err), tt.want)
+		})
+	}
+}
+
+func TestBinanceExecutor_TimeSync(t *testing.T) {
+	const drift = 2 * time.Second
+	var timeCalls, accountCalls, rejects int
+	var timestamps []int64
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v3/time":
+			timeCalls++
+			// 本地时钟比服务器快 2s
+			_ = json.NewEncoder(w).Encode(map[string]int64{"serverTime": time.Now().Add(-drift).UnixMilli()})
+		case "/api/v3/account":
+			accountCalls++
+			assert.Equal(t, "10000", r.URL.Query().Get("recvWindow"))
+			ts, _ := strconv.ParseInt(r.URL.Query().Get("timestamp"), 10, 64)
+			timestamps = append(timestamps, ts)
+			if rejects > 0 {
+				rejects--
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"code":-1021,"msg":"Timestamp for this request is outside of the recvWindow."}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"balances":[{"asset":"USDT","free":"100.5","locked":"0"}]}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	executor := NewBinanceExecutor("key", "secret")
+	executor.client.BaseURL = server.URL
+	executor.SetRecvWindow(10 * time.Second)
+	ctx := context.Background()
+
+	// 首次签名请求前自动同步服务器时间
+	balance, err := executor.GetBalance(ctx, "USDT")
+	require.NoError(t, err)
+	assert.Equal(t, 100.5, balance)
+	assert.Equal(t, 1, timeCalls)
+	require.Len(t, timestamps, 1)
+	assert.InDelta(t, time.Now().Add(-drift).UnixMilli(), timestamps[0], 500)
+
+	// 时间戳被拒绝后重新同步并重试一次
+	rejects = 1
+	balance, err = executor.GetBalance(ctx, "USDT")
+	require.NoError(t, err)
+	assert.Equal(t, 100.5, balance)
+	assert.Equal(t, 2, timeCalls)
+	assert.Equal(t, 3, accountCalls)
+
+	// 重试仍被拒绝时返回时间戳错误，不再继续重试
+	rejects = 2
+	_, err = executor.GetBalance(ctx, "USDT")
+	assert.ErrorIs(t, err, trading.ErrTimestampRejected)
+	assert.NotErrorIs(t, err, trading.ErrExchangeUnavailable)
+	assert.Equal(t, 3, timeCalls)
+	assert.Equal(t, 5, accountCalls)
+
+	// 下一次请求前重新同步
+	_, err = executor.GetBalance(ctx, "USDT")
+	require.NoError(t, err)
+	assert.Equal(t, 4, timeCalls)
+
+	offset, err := executor.SyncTime(ctx)
+	require.NoError(t, err)
+	assert.InDelta(t, drift.Seconds(), offset.Seconds(), 0.5)
+}
+
+func TestBinanceExecutor_OrderDetails(t *testing.T) {
+	var clientOrderID string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/api/v3/time":
+			_ = json.NewEncoder(w).Encode(map[string]int64{"serverTime": time.Now().UnixMilli()})
+		case r.URL.Path == "/api/v3/order" && r.Method == http.MethodPost:
+			_ = r.ParseForm()
+			clientOrderID = r.Form.Get("newClientOrderId")
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"symbol": "BTCUSDT", "orderId": 42, "clientOrderId": clientOrderID, "transactTime": 1700000000000,
+				"status": "FILLED", "executedQty": "0.002", "cummulativeQuoteQty": "100",
+				"fills": []map[string]string{
+					{"price": "50000", "qty": "0.0015", "commission": "0.0000015", "commissionAsset": "BTC"},
+					{"price": "50000", "qty": "0.0005", "commission": "0.0000005", "commissionAsset": "BTC"},
+				},
+			})
+		case r.URL.Path == "/api/v3/order":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"symbol": "BTCUSDT", "orderId": 42, "clientOrderId": clientOrderID, "price": "0", "origQty": "0.002",
+				"executedQty": "0.002", "cummulativeQuoteQty": "100", "status": "FILLED", "type": "MARKET", "side": "BUY",
+				"time": 1700000000000, "updateTime": 1700000001000,
+			})
+		case r.URL.Path == "/api/v3/myTrades":
+			assert.Equal(t, "42", r.URL.Query().Get("orderId"))
+			_ = json.NewEncoder(w).Encode([]map[string]any{
+				{"orderId": 42, "commission": "0.0000015", "commissionAsset": "BTC"},
+				{"orderId": 42, "commission": "0.0000005", "commissionAsset": "BTC"},
+			})
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	executor := NewBinanceExecutor("key", "secret")
+	executor.client.BaseURL = server.URL
+	ctx := context.Background()
+
+	// 未指定客户端订单号时生成并提交给交易所
+	order := &trading.Order{Symbol: "BTCUSDT", Side: "buy", Amount: 0.002, OrderType: "market"}
+	require.NoError(t, executor.PlaceOrder(ctx, order))
+	assert.NotEmpty(t, order.ClientOrderID)
+	assert.Equal(t, clientOrderID, order.ClientOrderID)
+	assert.Equal(t, time.UnixMilli(1700000000000), order.CreatedAt)
+	assert.InDelta(t, 0.000002, order.Fee, 1e-12)
+	assert.Equal(t, "BTC", order.FeeAsset)
+	assert.InDelta(t, 50000, order.FilledPrice, 1e-6)
+
+	status, err := executor.GetOrderStatus(ctx, "BTCUSDT", order.OrderID)
+	require.NoError(t, err)
+	assert.Equal(t, order.ClientOrderID, status.ClientOrderID)
+	assert.Equal(t, time.UnixMilli(1700000001000), status.UpdatedAt)
+	assert.InDelta(t, 0.000002, status.Fee, 1e-12)
+	assert.Equal(t, "BTC", status.FeeAsset)
+}
+
+type stubExchangeInfo []exchangeinfo.Symbol
+
+func (s stubExchangeInfo) ExchangeInfo(ctx context.Context) ([]exchangeinfo.Symbol, error) {
+	return s, nil
+}
+
+func TestBinanceExecutor_Precision(t *testing.T) {
+	var form url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v3/time":
+			_ = json.NewEncoder(w).Encode(map[string]int64{"serverTime": time.Now().UnixMilli()})
+		case "/api/v3/order":
+			_ = r.ParseForm()
+			form = r.Form
+			_ = json.NewEncoder(w).Encode(map[string]any{"symbol": "BTCUSDT", "orderId": 42, "status": "NEW", "executedQty": "0"})
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	info := exchangeinfo.NewService(stubExchangeInfo{{Symbol: "BTCUSDT", TickSize: 0.01, StepSize: 0.00001}})
+	require.NoError(t, info.Refresh(ctx))
+	executor := NewBinanceExecutor("key", "secret")
+	executor.client.BaseURL = server.URL
+
+	// 未加载精度时按默认精度格式化，不输出浮点误差
+	order := &trading.Order{Symbol: "BTCUSDT", Side: "buy", Amount: 0.1 + 0.2, Price: 65432.1049999, OrderType: "limit"}
+	require.NoError(t, executor.PlaceOrder(ctx, order))
+	assert.Equal(t, "0.3", form.Get("quantity"))
+	assert.Equal(t, "65432.1049999", form.Get("price"))
+
+	// 按交易对的数量和价格步长格式化
+	executor.SetExchangeInfo(info)
+	order = &trading.Order{Symbol: "BTCUSDT", Side: "buy", Amount: 0.0015299, Price: 65432.1049999, OrderType: "limit"}
+	require.NoError(t, executor.PlaceOrder(ctx, order))
+	assert.Equal(t, "0.00152", form.Get("quantity"))
+	assert.Equal(t, "65432.1", form.Get("price"))
+
+	order = &trading.Order{Symbol: "BTCUSDT", Side: "buy", QuoteAmount: 99.999, OrderType: "market"}
+	require.NoError(t, executor.PlaceOrder(ctx, order))
+	assert.Equal(t, "99.99", form.Get("quoteOrderQty"))
+}
+
+func newMockExchange(t *testing.T) (*binancetest.Server, *BinanceExecutor) {
+	exchange := binancetest.NewServer("key", "secret")
+	t.Cleanup(exchange.Close)
+	exchange.AddSymbol(exchangeinfo.Symbol{
+		Symbol: "BTCUSDT", BaseAsset: "BTC", QuoteAsset: "USDT",
+		TickSize: 0.01, StepSize: 0.0001, MinQty: 0.0001, MinNotional: 5,
+	}, 50000)
+	exchange.SetBalance("USDT", 10000)
+
+	executor := NewBinanceExecutor("key", "secret")
+	executor.client.BaseURL = exchange.URL()
+	return exchange, executor
+}
+
+func TestBinanceExecutor_MockExchange(t *testing.T) {
+	exchange, executor := newMockExchange(t)
+	exchange.SetCommission(0.0005, 0.001)
+	ctx := context.Background()
+
+	// 市价单立即成交，手续费从买入的资产中扣除
+	order := &trading.Order{Symbol: "BTCUSDT", Side: "buy", Amount: 0.1, OrderType: "market"}
+	require.NoError(t, executor.PlaceOrder(ctx, order))
+	assert.Equal(t, "FILLED", order.Status)
+	assert.Equal(t, 0.1, order.FilledAmount)
+	assert.Equal(t, 50000.0, order.FilledPrice)
+	assert.InDelta(t, 0.0001, order.Fee, 1e-12)
+	assert.Equal(t, "BTC", order.FeeAsset)
+	balance, err := executor.GetBalance(ctx, "BTC")
+	require.NoError(t, err)
+	assert.InDelta(t, 0.0999, balance, 1e-12)
+
+	// 限价单部分成交后查询到成交数量和手续费，撤单后状态为 CANCELED
+	limit := &trading.Order{Symbol: "BTCUSDT", Side: "buy", Amount: 0.1, Price: 49000, OrderType: "limit"}
+	require.NoError(t, executor.PlaceOrder(ctx, limit))
+	assert.Equal(t, "NEW", limit.Status)
+	require.NoError(t, exchange.Fill(limit.RawOrderID, 0.04))
+	status, err := executor.GetOrderStatus(ctx, "BTCUSDT", limit.OrderID)
+	require.NoError(t, err)
+	assert.Equal(t, "PARTIALLY_FILLED", status.Status)
+	assert.Equal(t, 0.04, status.FilledAmount)
+	assert.Equal(t, 49000.0, status.FilledPrice)
+	assert.InDelta(t, 0.00002, status.Fee, 1e-12)
+	assert.Equal(t, limit.ClientOrderID, status.ClientOrderID)
+
+	require.NoError(t, executor.CancelOrder(ctx, "BTCUSDT", limit.OrderID))
+	status, err = executor.GetOrderStatus(ctx, "BTCUSDT", limit.OrderID)
+	require.NoError(t, err)
+	assert.Equal(t, "CANCELED", status.Status)
+	_, locked := exchange.Balance("USDT")
+	assert.Zero(t, locked)
+
+	// 交易所的错误码映射为领域错误
+	err = executor.CancelOrder(ctx, "BTCUSDT", limit.OrderID)
+	assert.ErrorIs(t, err, trading.ErrOrderNotFound)
+	_, err = executor.GetOrderStatus(ctx, "BTCUSDT", "404")
+	assert.ErrorIs(t, err, trading.ErrOrderNotFound)
+	err = executor.PlaceOrder(ctx, &trading.Order{Symbol: "BTCUSDT", Side: "buy", Amount: 1, OrderType: "market"})
+	assert.ErrorIs(t, err, trading.ErrOrderRejected)
+	err = executor.PlaceOrder(ctx, &trading.Order{Symbol: "BTCUSDT", Side: "buy", Amount: 0.00015, Price: 49000, OrderType: "limit"})
+	assert.ErrorIs(t, err, trading.ErrOrderRejected)
+	err = executor.PlaceOrder(ctx, &trading.Order{Symbol: "XXXUSDT", Side: "buy", Amount: 1, OrderType: "market"})
+	assert.ErrorIs(t, err, trading.ErrOrderRejected)
+	_, err = executor.GetBalance(ctx, "ETH")
+	assert.ErrorIs(t, err, trading.ErrBalanceNotFound)
+
+	exchange.FailNext("/api/v3/account", http.StatusTooManyRequests, -1003, "Too many requests.")
+	_, err = executor.GetBalance(ctx, "USDT")
+	assert.ErrorIs(t, err, trading.ErrExchangeUnavailable)
+
+	wrongKey := NewBinanceExecutor("other", "secret")
+	wrongKey.client.BaseURL = exchange.URL()
+	_, err = wrongKey.GetBalance(ctx, "USDT")
+	assert.ErrorIs(t, err, trading.ErrUnauthorized)
+
+	// 客户端订单号重复的下单被拒绝，不会重复成交
+	resting := &trading.Order{Symbol: "BTCUSDT", Side: "buy", Amount: 0.01, Price: 40000, OrderType: "limit", ClientOrderID: "retry-1"}
+	require.NoError(t, executor.PlaceOrder(ctx, resting))
+	retry := *resting
+	assert.ErrorIs(t, executor.PlaceOrder(ctx, &retry), trading.ErrOrderRejected)
+}
+
+func TestBinanceExecutor_MockExchangeClockSkew(t *testing.T) {
+	exchange, executor := newMockExchange(t)
+	ctx := context.Background()
+
+	_, err := executor.GetBalance(ctx, "USDT")
+	require.NoError(t, err)
+
+	// 服务器时钟变化后时间戳被拒绝，重新同步后重试成功
+	exchange.SetClockSkew(-10 * time.Second)
+	balance, err := executor.GetBalance(ctx, "USDT")
+	require.NoError(t, err)
+	assert.Equal(t, 10000.0, balance)
+	assert.Equal(t, 2, exchange.Requests(http.MethodGet, "/api/v3/time"))
+	assert.Equal(t, 3, exchange.Requests(http.MethodGet, "/api/v3/account"))
+}
+
+func TestBinanceExecutor_BalanceCheck(t *testing.T) {
+	exchange, executor := newMockExchange(t)
+	executor.SetBalanceCheck(time.Minute)
+	ctx := context.Background()
+
+	// 余额不足的订单在本地拒绝，不请求交易所
+	err := executor.PlaceOrder(ctx, &trading.Order{Symbol: "BTCUSDT", Side: "buy", Amount: 0.3, Price: 49000, OrderType: "limit"})
+	var insufficient *trading.InsufficientFundsError
+	require.ErrorAs(t, err, &insufficient)
+	assert.ErrorIs(t, err, trading.ErrOrderRejected)
+	assert.Equal(t, "USDT", insufficient.Asset)
+	assert.Equal(t, 10000.0, insufficient.Available)
+	assert.InDelta(t, 14700, insufficient.Required, 1e-9)
+	assert.Zero(t, exchange.Requests(http.MethodPost, "/api/v3/order"))
+
+	// 下单成功后在缓存中扣除占用的资金，缓存有效期内不再查询账户
+	for i := 0; i < 2; i++ {
+		require.NoError(t, executor.PlaceOrder(ctx, &trading.Order{Symbol: "BTCUSDT", Side: "buy", Amount: 0.1, Price: 49000, OrderType: "limit"}))
+	}
+	err = executor.PlaceOrder(ctx, &trading.Order{Symbol: "BTCUSDT", Side: "buy", Amount: 0.1, Price: 49000, OrderType: "limit"})
+	assert.ErrorIs(t, err, trading.ErrInsufficientFunds)
+	assert.Equal(t, 2, exchange.Requests(http.MethodPost, "/api/v3/order"))
+	assert.Equal(t, 1, exchange.Requests(http.MethodGet, "/api/v3/account"))
+
+	// 关闭后交给交易所校验
+	executor.SetBalanceCheck(0)
+	err = executor.PlaceOrder(ctx, &trading.Order{Symbol: "BTCUSDT", Side: "buy", Amount: 0.1, Price: 49000, OrderType: "limit"})
+	assert.ErrorIs(t, err, trading.ErrInsufficientFunds)
+	assert.Equal(t, 3, exchange.Requests(http.MethodPost, "/api/v3/order"))
+}
+
+func TestBinanceExecutor_WalletStatus(t *testing.T) {
+	exchange, executor := newMockExchange(t)
+	ctx := context.Background()
+	var provider trading.WalletStatusProvider = executor
+
+	status, err := provider.SystemStatus(ctx)
+	require.NoError(t, err)
+	assert.False(t, status.Maintenance)
+
+	exchange.SetMaintenance(true)
+	status, err = provider.SystemStatus(ctx)
+	require.NoError(t, err)
+	assert.True(t, status.Maintenance)
+	assert.Equal(t, "system_maintenance", status.Message)
+
+	exchange.SetWalletStatus("BTC", true, false)
+	statuses, err := provider.AssetStatuses(ctx)
+	require.NoError(t, err)
+	byAsset := make(map[string]trading.AssetStatus)
+	for _, s := range statuses {
+		byAsset[s.Asset] = s
+	}
+	assert.Equal(t, trading.AssetStatus{Asset: "BTC", DepositEnabled: true, WithdrawEnabled: false}, byAsset["BTC"])
+	assert.Equal(t, trading.AssetStatus{Asset: "USDT", DepositEnabled: true, WithdrawEnabled: true}, byAsset["USDT"])
+}
+
+func TestPlaceMakerOrder_MockExchange(t *testing.T) {
+	exchange, executor := newMockExchange(t)
+	exchange.SetSpread("BTCUSDT", 10)
+	ctx := context.Background()
+
+	quote, err := executor.BestQuote(ctx, "BTCUSDT")
+	require.NoError(t, err)
+	assert.Equal(t, trading.Quote{Bid: 49995, Ask: 50005}, quote)
+
+	// 挂在买一的限价单未成交，撤单后剩余数量按市价成交
+	opts := trading.MakerOptions{Wait: 10 * time.Millisecond, PollInterval: 5 * time.Millisecond}
+	order := &trading.Order{Symbol: "BTCUSDT", Side: "buy", Amount: 0.1, OrderType: "maker"}
+	require.NoError(t, trading.PlaceMakerOrder(ctx, executor, executor, order, opts, func(trading.Quote) bool { return true }))
+	assert.Equal(t, "market", order.OrderType)
+	assert.Equal(t, "FILLED", order.Status)
+	assert.Equal(t, 0.1, order.FilledAmount)
+
+	orders := exchange.Orders()
+	require.Len(t, orders, 2)
+	assert.Equal(t, "LIMIT", orders[0].Type)
+	assert.Equal(t, "CANCELED", orders[0].Status)
+	assert.Equal(t, "MARKET", orders[1].Type)
+	_, locked := exchange.Balance("USDT")
+	assert.Zero(t, locked)
+
+	_, err = executor.BestQuote(ctx, "XXXUSDT")
+	assert.Error(t, err)
+}
+
+func TestOrderManager_NativeOCO(t *testing.T) {
+	exchange, executor := newMockExchange(t)
+	ctx := context.Background()
+	manager := trading.NewOrderManager(executor)
+
+	// 入场单成交后由交易所的 OCO 订单止盈止损
+	bracket := &trading.BracketOrder{
+		Entry:      trading.Order{Symbol: "BTCUSDT", Side: "buy", Amount: 0.1, OrderType: "market"},
+		TakeProfit: 55000,
+		StopLoss:   45000,
+	}
+	require.NoError(t, manager.PlaceBracket(ctx, bracket))
+	assert.True(t, bracket.Native)
+	assert.Equal(t, trading.BracketActive, bracket.Status)
+	assert.Equal(t, 55000.0, bracket.ProfitOrder.Price)
+	assert.Equal(t, 45000.0, bracket.StopOrder.Price)
+	_, locked := exchange.Balance("BTC")
+	assert.InDelta(t, 0.1, locked, 1e-12)
+
+	// 止损触发后止盈单过期
+	exchange.SetPrice("BTCUSDT", 44000)
+	require.NoError(t, manager.Sync(ctx))
+	b := manager.Brackets()[0]
+	assert.Equal(t, trading.BracketClosed, b.Status)
+	assert.Equal(t, "FILLED", b.StopOrder.Status)
+	assert.Equal(t, 44000.0, b.StopOrder.FilledPrice)
+	assert.Equal(t, "EXPIRED", b.ProfitOrder.Status)
+	free, _ := exchange.Balance("USDT")
+	assert.InDelta(t, 10000-5000+4400, free, 1e-9)
+
+	// 撤销组合订单时撤销整个 OCO 订单组
+	bracket = &trading.BracketOrder{
+		Entry:      trading.Order{Symbol: "BTCUSDT", Side: "buy", Amount: 0.1, OrderType: "market"},
+		TakeProfit: 50000,
+		StopLoss:   40000,
+	}
+	require.NoError(t, manager.PlaceBracket(ctx, bracket))
+	require.NoError(t, manager.CancelBracket(ctx, bracket.ID))
+	for _, order := range exchange.Orders() {
+		assert.NotContains(t, []string{binancetest.StatusNew, binancetest.StatusPartiallyFilled}, order.Status, order.OrderID)
+	}
+	free, locked = exchange.Balance("BTC")
+	assert.InDelta(t, 0.1, free, 1e-12)
+	assert.Zero(t, locked)
+
+	// 止盈止损价格与最新价格不符时 OCO 被拒绝，入场仓位按市价平掉
+	bracket = &trading.BracketOrder{
+		Entry:      trading.Order{Symbol: "BTCUSDT", Side: "buy", Amount: 0.1, OrderType: "market"},
+		TakeProfit: 43000,
+		StopLoss:   30000,
+	}
+	err := manager.PlaceBracket(ctx, bracket)
+	assert.ErrorIs(t, err, trading.ErrOrderRejected)
+	assert.Equal(t, trading.BracketFailed, bracket.Status)
+	free, _ = exchange.Balance("BTC")
+	assert.InDelta(t, 0.1, free, 1e-12)
+}
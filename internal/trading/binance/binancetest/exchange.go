@@ -0,0 +1,367 @@
+package binancetest
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/songzhibin97/quantaflux/internal/exchangeinfo"
+	"github.com/songzhibin97/quantaflux/internal/money"
+)
+
+// 订单状态，与交易所一致
+const (
+	StatusNew             = "NEW"
+	StatusPartiallyFilled = "PARTIALLY_FILLED"
+	StatusFilled          = "FILLED"
+	StatusCanceled        = "CANCELED"
+	StatusExpired         = "EXPIRED"
+)
+
+// 订单类型
+const (
+	TypeMarket        = "MARKET"
+	TypeLimit         = "LIMIT"
+	TypeLimitMaker    = "LIMIT_MAKER"
+	TypeStopLoss      = "STOP_LOSS"
+	TypeStopLossLimit = "STOP_LOSS_LIMIT"
+)
+
+// APIError 交易所返回的错误
+type APIError struct {
+	Status  int    // HTTP 状态码
+	Code    int64  `json:"code"`
+	Message string `json:"msg"`
+}
+
+func (e *APIError) Error() string {
+	return strconv.FormatInt(e.Code, 10) + " " + e.Message
+}
+
+func apiError(status int, code int64, msg string) *APIError {
+	return &APIError{Status: status, Code: code, Message: msg}
+}
+
+// Order 订单的快照
+type Order struct {
+	Symbol        string
+	OrderID       int64
+	ClientOrderID string
+	ListID        int64 // 所属 OCO 订单组，不属于订单组时为 -1
+	Side          string
+	Type          string
+	Price         float64
+	StopPrice     float64
+	Quantity      float64
+	ExecutedQty   float64
+	QuoteQty      float64 // 累计成交金额
+	Status        string
+	Time          int64
+	UpdateTime    int64
+}
+
+// Trade 一笔成交
+type Trade struct {
+	ID              int64
+	Symbol          string
+	OrderID         int64
+	ListID          int64
+	Price           float64
+	Quantity        float64
+	QuoteQty        float64
+	Commission      float64
+	CommissionAsset string
+	Time            int64
+	IsBuyer         bool
+	IsMaker         bool
+}
+
+// order 交易所内部的订单，数量和金额按十进制记账
+type order struct {
+	symbol        string
+	id            int64
+	clientOrderID string
+	listID        int64
+	side          string
+	orderType     string
+	timeInForce   string
+	price         decimal.Decimal // 委托价格，市价单和止损单为 0
+	stopPrice     decimal.Decimal
+	quantity      decimal.Decimal
+	executed      decimal.Decimal
+	quote         decimal.Decimal
+	status        string
+	triggered     bool // 止损单已触发
+	time          int64
+	updateTime    int64
+}
+
+func (o *order) open() bool {
+	return o.status == StatusNew || o.status == StatusPartiallyFilled
+}
+
+func (o *order) remaining() decimal.Decimal {
+	return o.quantity.Sub(o.executed)
+}
+
+func (o *order) snapshot() Order {
+	return Order{
+		Symbol:        o.symbol,
+		OrderID:       o.id,
+		ClientOrderID: o.clientOrderID,
+		ListID:        o.listID,
+		Side:          o.side,
+		Type:          o.orderType,
+		Price:         money.Float(o.price),
+		StopPrice:     money.Float(o.stopPrice),
+		Quantity:      money.Float(o.quantity),
+		ExecutedQty:   money.Float(o.executed),
+		QuoteQty:      money.Float(o.quote),
+		Status:        o.status,
+		Time:          o.time,
+		UpdateTime:    o.updateTime,
+	}
+}
+
+// orderList OCO 订单组，两个订单共用一份冻结资金
+type orderList struct {
+	id            int64
+	clientID      string
+	symbol        string
+	orders        []*order
+	done          bool
+	transactTime  int64
+	lockedAsset   string
+	lockedBalance decimal.Decimal
+}
+
+// balance 资产余额
+type balance struct {
+	free   decimal.Decimal
+	locked decimal.Decimal
+}
+
+// market 交易对的元数据和最新价格
+type market struct {
+	info   exchangeinfo.Symbol
+	price  decimal.Decimal
+	spread decimal.Decimal // 盘口价差，买一卖一价对称分布在最新价两侧
+}
+
+// lock 把 amount 从可用转为冻结，可用余额不足时返回 false，调用方需持有锁
+func (s *Server) lock(asset string, amount decimal.Decimal) bool {
+	b := s.balance(asset)
+	if b.free.LessThan(amount) {
+		return false
+	}
+	b.free = b.free.Sub(amount)
+	b.locked = b.locked.Add(amount)
+	return true
+}
+
+// unlock 把 amount 从冻结转回可用，调用方需持有锁
+func (s *Server) unlock(asset string, amount decimal.Decimal) {
+	b := s.balance(asset)
+	b.locked = b.locked.Sub(amount)
+	b.free = b.free.Add(amount)
+}
+
+func (s *Server) balance(asset string) *balance {
+	b, ok := s.balances[asset]
+	if !ok {
+		b = &balance{}
+		s.balances[asset] = b
+	}
+	return b
+}
+
+// required 返回按 price 买卖 qty 需要的资产和数量：买入需要计价资产，卖出需要基础资产
+func required(m *market, side string, qty, price decimal.Decimal) (string, decimal.Decimal) {
+	if side == "BUY" {
+		return m.info.QuoteAsset, qty.Mul(price)
+	}
+	return m.info.BaseAsset, qty
+}
+
+// lockPrice 返回挂单冻结资金按的价格：限价单按委托价格，止损单按触发价格和限价中较高者
+func lockPrice(o *order) decimal.Decimal {
+	if o.orderType == TypeStopLoss || o.orderType == TypeStopLossLimit {
+		return decimal.Max(o.price, o.stopPrice)
+	}
+	return o.price
+}
+
+// fill 按 price 成交订单的 qty，结算余额和手续费并记录成交，调用方需持有锁；
+// 成交前释放订单的冻结资金，部分成交后按剩余数量重新冻结
+func (s *Server) fill(o *order, qty, price decimal.Decimal, maker bool) {
+	m := s.markets[o.symbol]
+	s.release(o)
+
+	base, quote := m.info.BaseAsset, m.info.QuoteAsset
+	cost := qty.Mul(price)
+	rate := s.takerRate
+	if maker {
+		rate = s.makerRate
+	}
+	var commission decimal.Decimal
+	var commissionAsset string
+	if o.side == "BUY" {
+		commission, commissionAsset = qty.Mul(rate), base
+		s.balance(quote).free = s.balance(quote).free.Sub(cost)
+		s.balance(base).free = s.balance(base).free.Add(qty.Sub(commission))
+	} else {
+		commission, commissionAsset = cost.Mul(rate), quote
+		s.balance(base).free = s.balance(base).free.Sub(qty)
+		s.balance(quote).free = s.balance(quote).free.Add(cost.Sub(commission))
+	}
+
+	now := s.serverTime()
+	o.executed = o.executed.Add(qty)
+	o.quote = o.quote.Add(cost)
+	o.updateTime = now
+	if o.remaining().IsPositive() {
+		o.status = StatusPartiallyFilled
+		if o.orderType != TypeMarket {
+			s.lockOrder(o)
+		}
+	} else {
+		o.status = StatusFilled
+	}
+
+	s.nextTradeID++
+	s.trades = append(s.trades, &Trade{
+		ID:              s.nextTradeID,
+		Symbol:          o.symbol,
+		OrderID:         o.id,
+		ListID:          o.listID,
+		Price:           money.Float(price),
+		Quantity:        money.Float(qty),
+		QuoteQty:        money.Float(cost),
+		Commission:      money.Float(commission),
+		CommissionAsset: commissionAsset,
+		Time:            now,
+		IsBuyer:         o.side == "BUY",
+		IsMaker:         maker,
+	})
+
+	// OCO 订单组中任一订单成交后另一个订单过期
+	if list, ok := s.lists[o.listID]; ok && !list.done {
+		s.finishList(list, o, StatusExpired)
+	}
+}
+
+// lockOrder 按剩余数量冻结挂单的资金，可用余额不足时返回 false，调用方需持有锁
+func (s *Server) lockOrder(o *order) bool {
+	asset, amount := required(s.markets[o.symbol], o.side, o.remaining(), lockPrice(o))
+	if !s.lock(asset, amount) {
+		return false
+	}
+	s.locks[o.id] = amount
+	return true
+}
+
+// release 释放订单及其所在订单组剩余的冻结资金，调用方需持有锁
+func (s *Server) release(o *order) {
+	if list, ok := s.lists[o.listID]; ok && list.lockedBalance.IsPositive() {
+		s.unlock(list.lockedAsset, list.lockedBalance)
+		list.lockedBalance = decimal.Zero
+	}
+	if amount, ok := s.locks[o.id]; ok {
+		m := s.markets[o.symbol]
+		asset := m.info.QuoteAsset
+		if o.side == "SELL" {
+			asset = m.info.BaseAsset
+		}
+		s.unlock(asset, amount)
+		delete(s.locks, o.id)
+	}
+}
+
+// cancel 撤销订单并释放冻结资金，订单属于订单组时撤销整个订单组，调用方需持有锁
+func (s *Server) cancel(o *order) {
+	if list, ok := s.lists[o.listID]; ok {
+		s.finishList(list, nil, StatusCanceled)
+		return
+	}
+	s.release(o)
+	o.status = StatusCanceled
+	o.updateTime = s.serverTime()
+}
+
+// finishList 结束订单组：除 except 外仍未结束的订单置为 status，释放订单组的冻结资金
+func (s *Server) finishList(list *orderList, except *order, status string) {
+	now := s.serverTime()
+	for _, o := range list.orders {
+		if o != except && o.open() {
+			s.release(o)
+			o.status = status
+			o.updateTime = now
+		}
+	}
+	list.done = true
+	if list.lockedBalance.IsPositive() {
+		s.unlock(list.lockedAsset, list.lockedBalance)
+		list.lockedBalance = decimal.Zero
+	}
+}
+
+// match 按交易对的最新价格撮合挂单：限价单被穿价时按委托价格全部成交（maker），
+// 止损单触及触发价后按最新价格市价成交，带限价的止损单转为限价挂单，调用方需持有锁
+func (s *Server) match(symbol string) {
+	m := s.markets[symbol]
+	ids := make([]int64, 0, len(s.orders))
+	for id, o := range s.orders {
+		if o.symbol == symbol && o.open() {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	for _, id := range ids {
+		o := s.orders[id]
+		// 同组的另一个订单成交后已过期
+		if !o.open() {
+			continue
+		}
+		switch o.orderType {
+		case TypeStopLoss, TypeStopLossLimit:
+			if !o.triggered && stopReached(o.side, o.stopPrice, m.price) {
+				o.triggered = true
+				if list, ok := s.lists[o.listID]; ok && !list.done {
+					// 止损单触发后止盈单过期，订单组的冻结资金转为止损单冻结
+					s.finishList(list, o, StatusExpired)
+					s.lockOrder(o)
+				}
+			}
+			if !o.triggered {
+				continue
+			}
+			if o.orderType == TypeStopLoss {
+				s.fill(o, o.remaining(), m.price, false)
+			} else if crosses(o.side, o.price, m.price) {
+				s.fill(o, o.remaining(), o.price, true)
+			}
+		default:
+			if crosses(o.side, o.price, m.price) {
+				s.fill(o, o.remaining(), o.price, true)
+			}
+		}
+	}
+}
+
+// crosses 判断限价单是否可按 price 成交
+func crosses(side string, limit, price decimal.Decimal) bool {
+	if side == "BUY" {
+		return price.LessThanOrEqual(limit)
+	}
+	return price.GreaterThanOrEqual(limit)
+}
+
+// stopReached 判断止损单是否触发：卖出止损在价格跌至触发价时触发，买入止损在涨至触发价时触发
+func stopReached(side string, stop, price decimal.Decimal) bool {
+	if side == "BUY" {
+		return price.GreaterThanOrEqual(stop)
+	}
+	return price.LessThanOrEqual(stop)
+}
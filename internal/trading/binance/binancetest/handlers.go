@@ -0,0 +1,743 @@
+package binancetest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/songzhibin97/quantaflux/internal/exchangeinfo"
+)
+
+// 常见的错误
+var (
+	errInvalidSymbol       = apiError(http.StatusBadRequest, -1121, "Invalid symbol.")
+	errInsufficientBalance = apiError(http.StatusBadRequest, -2010, "Account has insufficient balance for requested action.")
+	errDuplicateOrder      = apiError(http.StatusBadRequest, -2010, "Duplicate order sent.")
+	errImmediateMatch      = apiError(http.StatusBadRequest, -2010, "Order would immediately match and take.")
+	errPriceRelationship   = apiError(http.StatusBadRequest, -2010, "The relationship of the prices for the orders is not correct.")
+	errOrderNotExist       = apiError(http.StatusBadRequest, -2013, "Order does not exist.")
+	errUnknownOrder        = apiError(http.StatusBadRequest, -2011, "Unknown order sent.")
+	errMarketClosed        = apiError(http.StatusBadRequest, -1013, "Market is closed.")
+	errOrderIDRequired     = apiError(http.StatusBadRequest, -1102, "Param 'origClientOrderId' or 'orderId' must be sent, but both were empty/null!")
+	errOrderListIDRequired = apiError(http.StatusBadRequest, -1102, "Param 'listClientOrderId' or 'orderListId' must be sent, but both were empty/null!")
+	errInvalidTimeInForce  = apiError(http.StatusBadRequest, -1115, "Invalid timeInForce.")
+)
+
+func mandatory(name string) *APIError {
+	return apiError(http.StatusBadRequest, -1102, "Mandatory parameter '"+name+"' was not sent, was empty/null, or malformed.")
+}
+
+func filterFailure(filter string) *APIError {
+	return apiError(http.StatusBadRequest, -1013, "Filter failure: "+filter)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, err *APIError) {
+	writeJSON(w, err.Status, err)
+}
+
+func intParam(params url.Values, name string) (int64, error) {
+	v := params.Get(name)
+	if v == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(v, 10, 64)
+}
+
+// decimalParam 解析非负的数值参数，未设置时返回 0
+func decimalParam(params url.Values, name string) (decimal.Decimal, *APIError) {
+	v := params.Get(name)
+	if v == "" {
+		return decimal.Zero, nil
+	}
+	d, err := decimal.NewFromString(v)
+	if err != nil || d.IsNegative() {
+		return decimal.Zero, apiError(http.StatusBadRequest, -1100, "Illegal characters found in parameter '"+name+"'.")
+	}
+	return d, nil
+}
+
+// format 按交易所的格式输出数值，保留 8 位小数
+func format(d decimal.Decimal) string {
+	return d.StringFixed(8)
+}
+
+func formatFloat(v float64) string {
+	return format(decimal.NewFromFloat(v))
+}
+
+// market 返回请求的交易对
+func (s *Server) market(params url.Values) (*market, *APIError) {
+	symbol := params.Get("symbol")
+	if symbol == "" {
+		return nil, mandatory("symbol")
+	}
+	m, ok := s.markets[symbol]
+	if !ok {
+		return nil, errInvalidSymbol
+	}
+	return m, nil
+}
+
+// checkFilters 按 PRICE_FILTER、LOT_SIZE 和 NOTIONAL 过滤条件检查订单，price 为 0 时不检查价格步长
+func checkFilters(info exchangeinfo.Symbol, price, qty, notional decimal.Decimal) *APIError {
+	if tick := decimal.NewFromFloat(info.TickSize); price.IsPositive() && tick.IsPositive() && !price.Mod(tick).IsZero() {
+		return filterFailure("PRICE_FILTER")
+	}
+	if step := decimal.NewFromFloat(info.StepSize); step.IsPositive() && !qty.Mod(step).IsZero() {
+		return filterFailure("LOT_SIZE")
+	}
+	if qty.LessThan(decimal.NewFromFloat(info.MinQty)) {
+		return filterFailure("LOT_SIZE")
+	}
+	if notional.LessThan(decimal.NewFromFloat(info.MinNotional)) {
+		return filterFailure("NOTIONAL")
+	}
+	return nil
+}
+
+type filterJSON struct {
+	FilterType  string `json:"filterType"`
+	MinPrice    string `json:"minPrice,omitempty"`
+	MaxPrice    string `json:"maxPrice,omitempty"`
+	TickSize    string `json:"tickSize,omitempty"`
+	MinQty      string `json:"minQty,omitempty"`
+	MaxQty      string `json:"maxQty,omitempty"`
+	StepSize    string `json:"stepSize,omitempty"`
+	MinNotional string `json:"minNotional,omitempty"`
+}
+
+type symbolJSON struct {
+	Symbol                     string       `json:"symbol"`
+	Status                     string       `json:"status"`
+	BaseAsset                  string       `json:"baseAsset"`
+	QuoteAsset                 string       `json:"quoteAsset"`
+	OrderTypes                 []string     `json:"orderTypes"`
+	OcoAllowed                 bool         `json:"ocoAllowed"`
+	QuoteOrderQtyMarketAllowed bool         `json:"quoteOrderQtyMarketAllowed"`
+	IsSpotTradingAllowed       bool         `json:"isSpotTradingAllowed"`
+	Filters                    []filterJSON `json:"filters"`
+}
+
+func (s *Server) exchangeInfo(params url.Values) (any, *APIError) {
+	symbols := make([]symbolJSON, 0, len(s.markets))
+	for name, m := range s.markets {
+		if want := params.Get("symbol"); want != "" && want != name {
+			continue
+		}
+		info := m.info
+		symbols = append(symbols, symbolJSON{
+			Symbol:                     info.Symbol,
+			Status:                     info.Status,
+			BaseAsset:                  info.BaseAsset,
+			QuoteAsset:                 info.QuoteAsset,
+			OrderTypes:                 []string{TypeLimit, TypeLimitMaker, TypeMarket, TypeStopLoss, TypeStopLossLimit},
+			OcoAllowed:                 true,
+			QuoteOrderQtyMarketAllowed: true,
+			IsSpotTradingAllowed:       true,
+			Filters: []filterJSON{
+				{FilterType: "PRICE_FILTER", MinPrice: formatFloat(info.TickSize), MaxPrice: "1000000.00000000", TickSize: formatFloat(info.TickSize)},
+				{FilterType: "LOT_SIZE", MinQty: formatFloat(info.MinQty), MaxQty: "9000000.00000000", StepSize: formatFloat(info.StepSize)},
+				{FilterType: "NOTIONAL", MinNotional: formatFloat(info.MinNotional)},
+			},
+		})
+	}
+	if len(symbols) == 0 && params.Get("symbol") != "" {
+		return nil, errInvalidSymbol
+	}
+	sort.Slice(symbols, func(i, j int) bool { return symbols[i].Symbol < symbols[j].Symbol })
+	return map[string]any{"timezone": "UTC", "serverTime": s.serverTime(), "symbols": symbols}, nil
+}
+
+func (s *Server) tickerPrice(params url.Values) (any, *APIError) {
+	type price struct {
+		Symbol string `json:"symbol"`
+		Price  string `json:"price"`
+	}
+	if params.Get("symbol") != "" {
+		m, apiErr := s.market(params)
+		if apiErr != nil {
+			return nil, apiErr
+		}
+		return price{Symbol: m.info.Symbol, Price: format(m.price)}, nil
+	}
+	prices := make([]price, 0, len(s.markets))
+	for name, m := range s.markets {
+		prices = append(prices, price{Symbol: name, Price: format(m.price)})
+	}
+	sort.Slice(prices, func(i, j int) bool { return prices[i].Symbol < prices[j].Symbol })
+	return prices, nil
+}
+
+func (s *Server) bookTicker(params url.Values) (any, *APIError) {
+	m, apiErr := s.market(params)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	half := m.spread.Div(decimal.NewFromInt(2))
+	return map[string]string{
+		"symbol":   m.info.Symbol,
+		"bidPrice": format(m.price.Sub(half)),
+		"bidQty":   "1000",
+		"askPrice": format(m.price.Add(half)),
+		"askQty":   "1000",
+	}, nil
+}
+
+// orderJSON 订单查询、下单和撤单的响应
+type orderJSON struct {
+	Symbol              string     `json:"symbol"`
+	OrderID             int64      `json:"orderId"`
+	OrderListID         int64      `json:"orderListId"`
+	ClientOrderID       string     `json:"clientOrderId"`
+	OrigClientOrderID   string     `json:"origClientOrderId,omitempty"`
+	TransactTime        int64      `json:"transactTime,omitempty"`
+	Price               string     `json:"price"`
+	OrigQty             string     `json:"origQty"`
+	ExecutedQty         string     `json:"executedQty"`
+	CummulativeQuoteQty string     `json:"cummulativeQuoteQty"`
+	Status              string     `json:"status"`
+	TimeInForce         string     `json:"timeInForce"`
+	Type                string     `json:"type"`
+	Side                string     `json:"side"`
+	StopPrice           string     `json:"stopPrice,omitempty"`
+	Time                int64      `json:"time,omitempty"`
+	UpdateTime          int64      `json:"updateTime,omitempty"`
+	IsWorking           bool       `json:"isWorking,omitempty"`
+	Fills               []fillJSON `json:"fills,omitempty"`
+}
+
+type fillJSON struct {
+	TradeID         int64  `json:"tradeId"`
+	Price           string `json:"price"`
+	Qty             string `json:"qty"`
+	Commission      string `json:"commission"`
+	CommissionAsset string `json:"commissionAsset"`
+}
+
+func orderResponse(o *order) orderJSON {
+	return orderJSON{
+		Symbol:              o.symbol,
+		OrderID:             o.id,
+		OrderListID:         o.listID,
+		ClientOrderID:       o.clientOrderID,
+		Price:               format(o.price),
+		OrigQty:             format(o.quantity),
+		ExecutedQty:         format(o.executed),
+		CummulativeQuoteQty: format(o.quote),
+		Status:              o.status,
+		TimeInForce:         o.timeInForce,
+		Type:                o.orderType,
+		Side:                o.side,
+		StopPrice:           format(o.stopPrice),
+	}
+}
+
+// newOrder 创建订单并分配订单号，未指定客户端订单号时生成，调用方需持有锁
+func (s *Server) newOrder(symbol, side, orderType, clientOrderID string) *order {
+	s.nextOrderID++
+	if clientOrderID == "" {
+		clientOrderID = "binancetest-" + strconv.FormatInt(s.nextOrderID, 10)
+	}
+	now := s.serverTime()
+	return &order{
+		symbol:        symbol,
+		id:            s.nextOrderID,
+		clientOrderID: clientOrderID,
+		listID:        -1,
+		side:          side,
+		orderType:     orderType,
+		status:        StatusNew,
+		time:          now,
+		updateTime:    now,
+	}
+}
+
+// duplicate 判断交易对是否已有使用该客户端订单号的未结束订单
+func (s *Server) duplicate(symbol, clientOrderID string) bool {
+	if clientOrderID == "" {
+		return false
+	}
+	for _, o := range s.orders {
+		if o.symbol == symbol && o.clientOrderID == clientOrderID && o.open() {
+			return true
+		}
+	}
+	return false
+}
+
+func validSide(side string) bool {
+	return side == "BUY" || side == "SELL"
+}
+
+func (s *Server) createOrder(params url.Values) (any, *APIError) {
+	m, apiErr := s.market(params)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	side, orderType := params.Get("side"), params.Get("type")
+	if !validSide(side) {
+		return nil, apiError(http.StatusBadRequest, -1117, "Invalid side.")
+	}
+	if orderType != TypeMarket && orderType != TypeLimit && orderType != TypeLimitMaker {
+		return nil, apiError(http.StatusBadRequest, -1116, "Invalid orderType.")
+	}
+	if m.info.Status != exchangeinfo.StatusTrading {
+		return nil, errMarketClosed
+	}
+	clientOrderID := params.Get("newClientOrderId")
+	if s.duplicate(m.info.Symbol, clientOrderID) {
+		return nil, errDuplicateOrder
+	}
+
+	qty, apiErr := decimalParam(params, "quantity")
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	price, apiErr := decimalParam(params, "price")
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	timeInForce := params.Get("timeInForce")
+
+	switch orderType {
+	case TypeMarket:
+		quoteQty, apiErr := decimalParam(params, "quoteOrderQty")
+		if apiErr != nil {
+			return nil, apiErr
+		}
+		if qty.IsZero() == quoteQty.IsZero() {
+			return nil, mandatory("quantity")
+		}
+		// 按金额下单时成交数量按数量步长向下取整
+		if qty.IsZero() {
+			if !m.price.IsPositive() {
+				return nil, filterFailure("NOTIONAL")
+			}
+			qty = quoteQty.Div(m.price)
+			if step := decimal.NewFromFloat(m.info.StepSize); step.IsPositive() {
+				qty = qty.Div(step).Floor().Mul(step)
+			} else {
+				qty = qty.Truncate(8)
+			}
+		}
+		if apiErr := checkFilters(m.info, decimal.Zero, qty, qty.Mul(m.price)); apiErr != nil {
+			return nil, apiErr
+		}
+	case TypeLimit, TypeLimitMaker:
+		if qty.IsZero() {
+			return nil, mandatory("quantity")
+		}
+		if price.IsZero() {
+			return nil, mandatory("price")
+		}
+		if orderType == TypeLimit {
+			switch timeInForce {
+			case "":
+				return nil, mandatory("timeInForce")
+			case "GTC", "IOC", "FOK":
+			default:
+				return nil, errInvalidTimeInForce
+			}
+		}
+		if apiErr := checkFilters(m.info, price, qty, qty.Mul(price)); apiErr != nil {
+			return nil, apiErr
+		}
+		if orderType == TypeLimitMaker && crosses(side, price, m.price) {
+			return nil, errImmediateMatch
+		}
+	}
+
+	// 市价单按最新价格检查余额，限价单按委托价格冻结资金
+	var locked decimal.Decimal
+	if orderType == TypeMarket {
+		asset, amount := required(m, side, qty, m.price)
+		if s.balance(asset).free.LessThan(amount) {
+			return nil, errInsufficientBalance
+		}
+	} else {
+		asset, amount := required(m, side, qty, price)
+		if !s.lock(asset, amount) {
+			return nil, errInsufficientBalance
+		}
+		locked = amount
+	}
+
+	o := s.newOrder(m.info.Symbol, side, orderType, clientOrderID)
+	o.quantity, o.timeInForce = qty, timeInForce
+	s.orders[o.id] = o
+	if orderType == TypeMarket {
+		s.fill(o, qty, m.price, false)
+	} else {
+		o.price = price
+		s.locks[o.id] = locked
+		// 穿价的限价单按最新价格立即成交，IOC 和 FOK 未成交时过期
+		if crosses(side, price, m.price) {
+			s.fill(o, qty, m.price, false)
+		} else if timeInForce == "IOC" || timeInForce == "FOK" {
+			s.release(o)
+			o.status = StatusExpired
+		}
+	}
+
+	resp := orderResponse(o)
+	resp.TransactTime = o.time
+	resp.Fills = []fillJSON{}
+	for _, t := range s.trades {
+		if t.OrderID == o.id {
+			resp.Fills = append(resp.Fills, fillJSON{
+				TradeID:         t.ID,
+				Price:           formatFloat(t.Price),
+				Qty:             formatFloat(t.Quantity),
+				Commission:      formatFloat(t.Commission),
+				CommissionAsset: t.CommissionAsset,
+			})
+		}
+	}
+	return resp, nil
+}
+
+// findOrder 按 orderId 或 origClientOrderId 查找交易对的订单，不存在时返回 notFound
+func (s *Server) findOrder(params url.Values, notFound *APIError) (*order, *APIError) {
+	m, apiErr := s.market(params)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	id, err := intParam(params, "orderId")
+	if err != nil {
+		return nil, mandatory("orderId")
+	}
+	clientOrderID := params.Get("origClientOrderId")
+	if id == 0 && clientOrderID == "" {
+		return nil, errOrderIDRequired
+	}
+	for _, o := range s.orders {
+		if o.symbol != m.info.Symbol {
+			continue
+		}
+		if (id != 0 && o.id == id) || (id == 0 && o.clientOrderID == clientOrderID) {
+			return o, nil
+		}
+	}
+	return nil, notFound
+}
+
+func (s *Server) getOrder(params url.Values) (any, *APIError) {
+	o, apiErr := s.findOrder(params, errOrderNotExist)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	resp := orderResponse(o)
+	resp.Time, resp.UpdateTime, resp.IsWorking = o.time, o.updateTime, o.open()
+	return resp, nil
+}
+
+func (s *Server) cancelOrder(params url.Values) (any, *APIError) {
+	o, apiErr := s.findOrder(params, errUnknownOrder)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	if !o.open() {
+		return nil, errUnknownOrder
+	}
+	s.cancel(o)
+	resp := orderResponse(o)
+	resp.OrigClientOrderID = o.clientOrderID
+	resp.TransactTime = o.updateTime
+	return resp, nil
+}
+
+func (s *Server) openOrders(params url.Values) (any, *APIError) {
+	symbol := params.Get("symbol")
+	if symbol != "" {
+		if _, apiErr := s.market(params); apiErr != nil {
+			return nil, apiErr
+		}
+	}
+	result := []orderJSON{}
+	for _, o := range s.orders {
+		if o.open() && (symbol == "" || o.symbol == symbol) {
+			resp := orderResponse(o)
+			resp.Time, resp.UpdateTime, resp.IsWorking = o.time, o.updateTime, true
+			result = append(result, resp)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].OrderID < result[j].OrderID })
+	return result, nil
+}
+
+// listJSON OCO 下单和撤单的响应
+type listJSON struct {
+	OrderListID       int64       `json:"orderListId"`
+	ContingencyType   string      `json:"contingencyType"`
+	ListStatusType    string      `json:"listStatusType"`
+	ListOrderStatus   string      `json:"listOrderStatus"`
+	ListClientOrderID string      `json:"listClientOrderId"`
+	TransactionTime   int64       `json:"transactionTime"`
+	Symbol            string      `json:"symbol"`
+	Orders            []listOrder `json:"orders"`
+	OrderReports      []orderJSON `json:"orderReports"`
+}
+
+type listOrder struct {
+	Symbol        string `json:"symbol"`
+	OrderID       int64  `json:"orderId"`
+	ClientOrderID string `json:"clientOrderId"`
+}
+
+func listResponse(list *orderList, transactionTime int64) listJSON {
+	resp := listJSON{
+		OrderListID:       list.id,
+		ContingencyType:   "OCO",
+		ListStatusType:    "EXEC_STARTED",
+		ListOrderStatus:   "EXECUTING",
+		ListClientOrderID: list.clientID,
+		TransactionTime:   transactionTime,
+		Symbol:            list.symbol,
+	}
+	if list.done {
+		resp.ListStatusType, resp.ListOrderStatus = "ALL_DONE", "ALL_DONE"
+	}
+	for _, o := range list.orders {
+		resp.Orders = append(resp.Orders, listOrder{Symbol: o.symbol, OrderID: o.id, ClientOrderID: o.clientOrderID})
+		resp.OrderReports = append(resp.OrderReports, orderResponse(o))
+	}
+	return resp
+}
+
+// createOCO 创建 OCO 订单组：LIMIT_MAKER 止盈单和 STOP_LOSS 止损单（设置 stopLimitPrice 时为 STOP_LOSS_LIMIT），
+// 卖出时要求止盈价 > 最新价格 > 止损触发价，买入时相反
+func (s *Server) createOCO(params url.Values) (any, *APIError) {
+	m, apiErr := s.market(params)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	side := params.Get("side")
+	if !validSide(side) {
+		return nil, apiError(http.StatusBadRequest, -1117, "Invalid side.")
+	}
+	if m.info.Status != exchangeinfo.StatusTrading {
+		return nil, errMarketClosed
+	}
+
+	values := make(map[string]decimal.Decimal)
+	for _, name := range []string{"quantity", "price", "stopPrice", "stopLimitPrice"} {
+		v, apiErr := decimalParam(params, name)
+		if apiErr != nil {
+			return nil, apiErr
+		}
+		if v.IsZero() && name != "stopLimitPrice" {
+			return nil, mandatory(name)
+		}
+		values[name] = v
+	}
+	qty, price, stopPrice, stopLimitPrice := values["quantity"], values["price"], values["stopPrice"], values["stopLimitPrice"]
+	if stopLimitPrice.IsPositive() && params.Get("stopLimitTimeInForce") == "" {
+		return nil, mandatory("stopLimitTimeInForce")
+	}
+	for _, p := range []decimal.Decimal{price, stopPrice, stopLimitPrice} {
+		if apiErr := checkFilters(m.info, p, qty, qty.Mul(price)); apiErr != nil {
+			return nil, apiErr
+		}
+	}
+	if side == "SELL" && !(price.GreaterThan(m.price) && m.price.GreaterThan(stopPrice)) ||
+		side == "BUY" && !(price.LessThan(m.price) && m.price.LessThan(stopPrice)) {
+		return nil, errPriceRelationship
+	}
+	for _, name := range []string{"limitClientOrderId", "stopClientOrderId"} {
+		if s.duplicate(m.info.Symbol, params.Get(name)) {
+			return nil, errDuplicateOrder
+		}
+	}
+
+	// 两个订单共用一份冻结资金：卖出冻结数量，买入按较高的价格冻结金额
+	lockedAsset, lockedBalance := m.info.BaseAsset, qty
+	if side == "BUY" {
+		lockedAsset, lockedBalance = m.info.QuoteAsset, qty.Mul(decimal.Max(price, stopPrice, stopLimitPrice))
+	}
+	if !s.lock(lockedAsset, lockedBalance) {
+		return nil, errInsufficientBalance
+	}
+
+	s.nextListID++
+	list := &orderList{
+		id:            s.nextListID,
+		clientID:      params.Get("listClientOrderId"),
+		symbol:        m.info.Symbol,
+		transactTime:  s.serverTime(),
+		lockedAsset:   lockedAsset,
+		lockedBalance: lockedBalance,
+	}
+	if list.clientID == "" {
+		list.clientID = "binancetest-list-" + strconv.FormatInt(list.id, 10)
+	}
+
+	stopType := TypeStopLoss
+	if stopLimitPrice.IsPositive() {
+		stopType = TypeStopLossLimit
+	}
+	stop := s.newOrder(m.info.Symbol, side, stopType, params.Get("stopClientOrderId"))
+	stop.quantity, stop.stopPrice, stop.price = qty, stopPrice, stopLimitPrice
+	stop.timeInForce = params.Get("stopLimitTimeInForce")
+	limit := s.newOrder(m.info.Symbol, side, TypeLimitMaker, params.Get("limitClientOrderId"))
+	limit.quantity, limit.price = qty, price
+	for _, o := range []*order{stop, limit} {
+		o.listID = list.id
+		s.orders[o.id] = o
+		list.orders = append(list.orders, o)
+	}
+	s.lists[list.id] = list
+	return listResponse(list, list.transactTime), nil
+}
+
+func (s *Server) cancelOCO(params url.Values) (any, *APIError) {
+	m, apiErr := s.market(params)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	id, err := intParam(params, "orderListId")
+	if err != nil {
+		return nil, mandatory("orderListId")
+	}
+	clientID := params.Get("listClientOrderId")
+	if id == 0 && clientID == "" {
+		return nil, errOrderListIDRequired
+	}
+	for _, list := range s.lists {
+		if list.symbol != m.info.Symbol || (id != 0 && list.id != id) || (id == 0 && list.clientID != clientID) {
+			continue
+		}
+		// 订单组已结束但止损单已触发仍在挂单时，撤销止损单
+		open := false
+		for _, o := range list.orders {
+			open = open || o.open()
+		}
+		if !open {
+			break
+		}
+		s.finishList(list, nil, StatusCanceled)
+		return listResponse(list, s.serverTime()), nil
+	}
+	return nil, errUnknownOrder
+}
+
+type tradeJSON struct {
+	ID              int64  `json:"id"`
+	Symbol          string `json:"symbol"`
+	OrderID         int64  `json:"orderId"`
+	OrderListID     int64  `json:"orderListId"`
+	Price           string `json:"price"`
+	Qty             string `json:"qty"`
+	QuoteQty        string `json:"quoteQty"`
+	Commission      string `json:"commission"`
+	CommissionAsset string `json:"commissionAsset"`
+	Time            int64  `json:"time"`
+	IsBuyer         bool   `json:"isBuyer"`
+	IsMaker         bool   `json:"isMaker"`
+	IsBestMatch     bool   `json:"isBestMatch"`
+}
+
+func (s *Server) myTrades(params url.Values) (any, *APIError) {
+	m, apiErr := s.market(params)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	orderID, err := intParam(params, "orderId")
+	if err != nil {
+		return nil, mandatory("orderId")
+	}
+	result := []tradeJSON{}
+	for _, t := range s.trades {
+		if t.Symbol != m.info.Symbol || (orderID != 0 && t.OrderID != orderID) {
+			continue
+		}
+		result = append(result, tradeJSON{
+			ID:              t.ID,
+			Symbol:          t.Symbol,
+			OrderID:         t.OrderID,
+			OrderListID:     t.ListID,
+			Price:           formatFloat(t.Price),
+			Qty:             formatFloat(t.Quantity),
+			QuoteQty:        formatFloat(t.QuoteQty),
+			Commission:      formatFloat(t.Commission),
+			CommissionAsset: t.CommissionAsset,
+			Time:            t.Time,
+			IsBuyer:         t.IsBuyer,
+			IsMaker:         t.IsMaker,
+			IsBestMatch:     true,
+		})
+	}
+	return result, nil
+}
+
+func (s *Server) account() any {
+	type balanceJSON struct {
+		Asset  string `json:"asset"`
+		Free   string `json:"free"`
+		Locked string `json:"locked"`
+	}
+	balances := make([]balanceJSON, 0, len(s.balances))
+	for asset, b := range s.balances {
+		balances = append(balances, balanceJSON{Asset: asset, Free: format(b.free), Locked: format(b.locked)})
+	}
+	sort.Slice(balances, func(i, j int) bool { return balances[i].Asset < balances[j].Asset })
+	return map[string]any{
+		"makerCommission": s.makerRate.Shift(4).IntPart(),
+		"takerCommission": s.takerRate.Shift(4).IntPart(),
+		"canTrade":        true,
+		"canWithdraw":     true,
+		"canDeposit":      true,
+		"updateTime":      s.serverTime(),
+		"accountType":     "SPOT",
+		"balances":        balances,
+		"permissions":     []string{"SPOT"},
+	}
+}
+
+// systemStatus 返回系统状态，status 为 1 表示系统维护
+func (s *Server) systemStatus() any {
+	if s.maintenance {
+		return map[string]any{"status": 1, "msg": "system_maintenance"}
+	}
+	return map[string]any{"status": 0, "msg": "normal"}
+}
+
+// coinsInfo 返回有余额或设置过开关的资产的充值和提现开关
+func (s *Server) coinsInfo() any {
+	type coinJSON struct {
+		Coin              string `json:"coin"`
+		DepositAllEnable  bool   `json:"depositAllEnable"`
+		WithdrawAllEnable bool   `json:"withdrawAllEnable"`
+		Free              string `json:"free"`
+		Locked            string `json:"locked"`
+	}
+	assets := make(map[string]bool, len(s.balances)+len(s.wallets))
+	for asset := range s.balances {
+		assets[asset] = true
+	}
+	for asset := range s.wallets {
+		assets[asset] = true
+	}
+	coins := make([]coinJSON, 0, len(assets))
+	for asset := range assets {
+		coin := coinJSON{Coin: asset, DepositAllEnable: true, WithdrawAllEnable: true, Free: "0", Locked: "0"}
+		if w, ok := s.wallets[asset]; ok {
+			coin.DepositAllEnable, coin.WithdrawAllEnable = w.deposit, w.withdraw
+		}
+		if b, ok := s.balances[asset]; ok {
+			coin.Free, coin.Locked = format(b.free), format(b.locked)
+		}
+		coins = append(coins, coin)
+	}
+	sort.Slice(coins, func(i, j int) bool { return coins[i].Coin < coins[j].Coin })
+	return coins
+}
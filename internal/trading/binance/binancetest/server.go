@@ -0,0 +1,397 @@
+// Package binancetest 提供模拟 Binance 现货 REST 接口的测试服务器，用于在没有测试网密钥的情况下
+// 确定性地测试执行器和 OrderManager。
+//
+// 服务器校验 API 密钥、HMAC 签名和时间戳窗口，按交易对的过滤条件和账户余额接受或拒绝订单，
+// 返回与交易所相同的错误码；限价单和止损单在 SetPrice 更新价格时撮合，OCO 订单组任一订单成交后
+// 另一个订单过期。FailNext 可让下一次请求返回指定的错误，用于测试重试和错误映射。
+package binancetest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/songzhibin97/quantaflux/internal/exchangeinfo"
+	"github.com/songzhibin97/quantaflux/internal/money"
+)
+
+const (
+	// defaultRecvWindow 请求未指定 recvWindow 时的时间窗口（毫秒）
+	defaultRecvWindow = 5000
+	// maxRecvWindow 允许的最大 recvWindow（毫秒）
+	maxRecvWindow = 60000
+	// futureTolerance 请求时间戳允许超前服务器时间的范围（毫秒）
+	futureTolerance = 1000
+)
+
+// Server 模拟交易所，所有方法可并发调用
+type Server struct {
+	apiKey    string
+	secretKey string
+	ts        *httptest.Server
+
+	mu          sync.Mutex
+	clockSkew   time.Duration // 服务器时钟相对本地时钟的偏差
+	makerRate   decimal.Decimal
+	takerRate   decimal.Decimal
+	markets     map[string]*market
+	balances    map[string]*balance
+	orders      map[int64]*order
+	lists       map[int64]*orderList
+	locks       map[int64]decimal.Decimal // 不属于订单组的挂单冻结的资金
+	trades      []*Trade
+	nextOrderID int64
+	nextListID  int64
+	nextTradeID int64
+	failures    map[string][]*APIError // 按请求路径注入的错误，依次返回
+	requests    map[string]int         // 按 "METHOD /path" 统计的请求次数
+	maintenance bool                   // 交易所是否处于系统维护
+	wallets     map[string]*wallet     // 资产的充值和提现开关，未设置的资产均开启
+}
+
+// wallet 资产的充值和提现开关
+type wallet struct {
+	deposit  bool
+	withdraw bool
+}
+
+// NewServer 启动模拟交易所，只接受 apiKey 和 secretKey 签名的请求；使用完毕后调用 Close
+func NewServer(apiKey, secretKey string) *Server {
+	s := &Server{
+		apiKey:    apiKey,
+		secretKey: secretKey,
+		markets:   make(map[string]*market),
+		balances:  make(map[string]*balance),
+		orders:    make(map[int64]*order),
+		lists:     make(map[int64]*orderList),
+		locks:     make(map[int64]decimal.Decimal),
+		failures:  make(map[string][]*APIError),
+		requests:  make(map[string]int),
+		wallets:   make(map[string]*wallet),
+	}
+	s.ts = httptest.NewServer(s)
+	return s
+}
+
+// URL 返回服务器地址，用作 go-binance 客户端的 BaseURL
+func (s *Server) URL() string {
+	return s.ts.URL
+}
+
+// Close 关闭服务器
+func (s *Server) Close() {
+	s.ts.Close()
+}
+
+// AddSymbol 添加交易对及其最新价格，Status 为空时为 TRADING
+func (s *Server) AddSymbol(info exchangeinfo.Symbol, price float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if info.Status == "" {
+		info.Status = exchangeinfo.StatusTrading
+	}
+	s.markets[info.Symbol] = &market{info: info, price: money.FromFloat(price)}
+}
+
+// SetPrice 更新交易对的最新价格并撮合挂单
+func (s *Server) SetPrice(symbol string, price float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.markets[symbol]
+	if !ok {
+		panic("binancetest: unknown symbol " + symbol)
+	}
+	m.price = money.FromFloat(price)
+	s.match(symbol)
+}
+
+// SetSpread 设置交易对的盘口价差，bookTicker 的买一卖一价为最新价减加半个价差，默认为 0
+func (s *Server) SetSpread(symbol string, spread float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.markets[symbol]
+	if !ok {
+		panic("binancetest: unknown symbol " + symbol)
+	}
+	m.spread = money.FromFloat(spread)
+}
+
+// SetBalance 设置资产的可用余额
+func (s *Server) SetBalance(asset string, free float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.balance(asset).free = money.FromFloat(free)
+}
+
+// Balance 返回资产的可用和冻结余额
+func (s *Server) Balance(asset string) (free, locked float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b := s.balance(asset)
+	return money.Float(b.free), money.Float(b.locked)
+}
+
+// SetCommission 设置挂单（maker）和吃单（taker）的手续费率，手续费从得到的资产中扣除
+func (s *Server) SetCommission(maker, taker float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.makerRate, s.takerRate = money.FromFloat(maker), money.FromFloat(taker)
+}
+
+// SetClockSkew 设置服务器时钟相对本地时钟的偏差，用于测试时间同步
+func (s *Server) SetClockSkew(skew time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.clockSkew = skew
+}
+
+// SetMaintenance 设置交易所是否处于系统维护
+func (s *Server) SetMaintenance(maintenance bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.maintenance = maintenance
+}
+
+// SetWalletStatus 设置资产的充值和提现开关
+func (s *Server) SetWalletStatus(asset string, deposit, withdraw bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.wallets[asset] = &wallet{deposit: deposit, withdraw: withdraw}
+}
+
+// FailNext 让下一次请求 path（如 /api/v3/order）的请求返回指定的错误，多次调用时依次返回
+func (s *Server) FailNext(path string, status int, code int64, msg string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures[path] = append(s.failures[path], apiError(status, code, msg))
+}
+
+// Requests 返回 method 和 path 的请求次数
+func (s *Server) Requests(method, path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests[method+" "+path]
+}
+
+// Order 返回订单的快照
+func (s *Server) Order(orderID int64) (Order, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.orders[orderID]
+	if !ok {
+		return Order{}, false
+	}
+	return o.snapshot(), true
+}
+
+// Orders 返回所有订单的快照，按订单号排列
+func (s *Server) Orders() []Order {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]Order, 0, len(s.orders))
+	for _, o := range s.orders {
+		result = append(result, o.snapshot())
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].OrderID < result[j].OrderID })
+	return result
+}
+
+// Trades 返回所有成交
+func (s *Server) Trades() []Trade {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]Trade, 0, len(s.trades))
+	for _, t := range s.trades {
+		result = append(result, *t)
+	}
+	return result
+}
+
+// Fill 按委托价格成交挂单的 qty，用于模拟部分成交；止损单按最新价格成交
+func (s *Server) Fill(orderID int64, qty float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.orders[orderID]
+	if !ok || !o.open() {
+		return fmt.Errorf("binancetest: order %d is not open", orderID)
+	}
+	amount := money.FromFloat(qty)
+	if !amount.IsPositive() || amount.GreaterThan(o.remaining()) {
+		return fmt.Errorf("binancetest: invalid fill quantity %v for order %d", qty, orderID)
+	}
+	price, maker := o.price, true
+	if o.orderType == TypeStopLoss {
+		price, maker = s.markets[o.symbol].price, false
+	}
+	s.fill(o, amount, price, maker)
+	return nil
+}
+
+// serverTime 返回服务器时间（毫秒），调用方需持有锁
+func (s *Server) serverTime() int64 {
+	return time.Now().Add(s.clockSkew).UnixMilli()
+}
+
+// signedEndpoints 需要签名的接口，其余接口公开访问
+var signedEndpoints = map[string]bool{
+	"/api/v3/order":      true,
+	"/api/v3/order/oco":  true,
+	"/api/v3/orderList":  true,
+	"/api/v3/openOrders": true,
+	"/api/v3/myTrades":   true,
+	"/api/v3/account":    true,
+
+	"/sapi/v1/capital/config/getall": true,
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests[r.Method+" "+r.URL.Path]++
+	if queued := s.failures[r.URL.Path]; len(queued) > 0 {
+		s.failures[r.URL.Path] = queued[1:]
+		writeError(w, queued[0])
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, apiError(http.StatusBadRequest, -1100, "Illegal characters found in a parameter."))
+		return
+	}
+	params, err := requestParams(r.URL.RawQuery, string(body))
+	if err != nil {
+		writeError(w, apiError(http.StatusBadRequest, -1100, "Illegal characters found in a parameter."))
+		return
+	}
+	if signedEndpoints[r.URL.Path] {
+		if apiErr := s.authenticate(r, string(body), params); apiErr != nil {
+			writeError(w, apiErr)
+			return
+		}
+	}
+
+	var result any
+	var apiErr *APIError
+	switch r.Method + " " + r.URL.Path {
+	case "GET /api/v3/ping":
+		result = struct{}{}
+	case "GET /api/v3/time":
+		result = map[string]int64{"serverTime": s.serverTime()}
+	case "GET /api/v3/exchangeInfo":
+		result, apiErr = s.exchangeInfo(params)
+	case "GET /api/v3/ticker/price":
+		result, apiErr = s.tickerPrice(params)
+	case "GET /api/v3/ticker/bookTicker":
+		result, apiErr = s.bookTicker(params)
+	case "POST /api/v3/order":
+		result, apiErr = s.createOrder(params)
+	case "GET /api/v3/order":
+		result, apiErr = s.getOrder(params)
+	case "DELETE /api/v3/order":
+		result, apiErr = s.cancelOrder(params)
+	case "GET /api/v3/openOrders":
+		result, apiErr = s.openOrders(params)
+	case "POST /api/v3/order/oco":
+		result, apiErr = s.createOCO(params)
+	case "DELETE /api/v3/orderList":
+		result, apiErr = s.cancelOCO(params)
+	case "GET /api/v3/myTrades":
+		result, apiErr = s.myTrades(params)
+	case "GET /api/v3/account":
+		result = s.account()
+	case "GET /sapi/v1/system/status":
+		result = s.systemStatus()
+	case "GET /sapi/v1/capital/config/getall":
+		result = s.coinsInfo()
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	if apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// requestParams 合并查询参数和表单参数，go-binance 的 DELETE 请求也把参数放在请求体中
+func requestParams(query, body string) (url.Values, error) {
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	form, err := url.ParseQuery(body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range form {
+		params[k] = append(params[k], v...)
+	}
+	return params, nil
+}
+
+// authenticate 校验 API 密钥、签名和时间戳：签名为 HMAC-SHA256(secret, 查询串去掉 signature 后 + 请求体)
+func (s *Server) authenticate(r *http.Request, body string, params url.Values) *APIError {
+	if r.Header.Get("X-MBX-APIKEY") != s.apiKey {
+		return apiError(http.StatusUnauthorized, -2015, "Invalid API-key, IP, or permissions for action.")
+	}
+
+	query := r.URL.RawQuery
+	idx := strings.LastIndex(query, "signature=")
+	if idx < 0 {
+		return apiError(http.StatusBadRequest, -1102, "Mandatory parameter 'signature' was not sent, was empty/null, or malformed.")
+	}
+	signature := query[idx+len("signature="):]
+	payload := strings.TrimSuffix(query[:idx], "&") + body
+	mac := hmac.New(sha256.New, []byte(s.secretKey))
+	mac.Write([]byte(payload))
+	if !hmac.Equal([]byte(signature), []byte(hex.EncodeToString(mac.Sum(nil)))) {
+		return apiError(http.StatusBadRequest, -1022, "Signature for this request is not valid.")
+	}
+
+	timestamp, err := intParam(params, "timestamp")
+	if err != nil || timestamp == 0 {
+		return apiError(http.StatusBadRequest, -1102, "Mandatory parameter 'timestamp' was not sent, was empty/null, or malformed.")
+	}
+	window := int64(defaultRecvWindow)
+	if params.Get("recvWindow") != "" {
+		window, err = intParam(params, "recvWindow")
+		if err != nil || window <= 0 || window > maxRecvWindow {
+			return apiError(http.StatusBadRequest, -1131, "recvWindow must be less than 60000")
+		}
+	}
+	now := s.serverTime()
+	if timestamp > now+futureTolerance || now-timestamp > window {
+		return apiError(http.StatusBadRequest, -1021, "Timestamp for this request is outside of the recvWindow.")
+	}
+	return nil
+}
@@ -0,0 +1,305 @@
+package binancetest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/adshao/go-binance/v2"
+	"github.com/adshao/go-binance/v2/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/songzhibin97/quantaflux/internal/exchangeinfo"
+)
+
+func newTestServer(t *testing.T) (*Server, *binance.Client) {
+	s := NewServer("key", "secret")
+	t.Cleanup(s.Close)
+	s.AddSymbol(exchangeinfo.Symbol{
+		Symbol: "BTCUSDT", BaseAsset: "BTC", QuoteAsset: "USDT",
+		TickSize: 0.01, StepSize: 0.0001, MinQty: 0.0001, MinNotional: 5,
+	}, 50000)
+	s.SetBalance("USDT", 10000)
+	s.SetBalance("BTC", 1)
+
+	client := binance.NewClient("key", "secret")
+	client.BaseURL = s.URL()
+	return s, client
+}
+
+func apiCode(t *testing.T, err error) int64 {
+	t.Helper()
+	var apiErr *common.APIError
+	require.True(t, errors.As(err, &apiErr), "expected api error, got %v", err)
+	return apiErr.Code
+}
+
+func TestServer_Authentication(t *testing.T) {
+	s, client := newTestServer(t)
+	ctx := context.Background()
+
+	_, err := client.NewGetAccountService().Do(ctx)
+	require.NoError(t, err)
+
+	wrongKey := binance.NewClient("other", "secret")
+	wrongKey.BaseURL = s.URL()
+	_, err = wrongKey.NewGetAccountService().Do(ctx)
+	assert.Equal(t, int64(-2015), apiCode(t, err))
+
+	wrongSecret := binance.NewClient("key", "other")
+	wrongSecret.BaseURL = s.URL()
+	_, err = wrongSecret.NewGetAccountService().Do(ctx)
+	assert.Equal(t, int64(-1022), apiCode(t, err))
+
+	// 服务器时钟落后时时间戳超前被拒绝，同步时间后恢复
+	s.SetClockSkew(-10 * time.Second)
+	_, err = client.NewGetAccountService().Do(ctx)
+	assert.Equal(t, int64(-1021), apiCode(t, err))
+	_, err = client.NewSetServerTimeService().Do(ctx)
+	require.NoError(t, err)
+	_, err = client.NewGetAccountService().Do(ctx)
+	require.NoError(t, err)
+
+	// 公开接口不需要签名
+	require.NoError(t, wrongKey.NewPingService().Do(ctx))
+}
+
+func TestServer_MarketOrder(t *testing.T) {
+	s, client := newTestServer(t)
+	s.SetCommission(0.0005, 0.001)
+	ctx := context.Background()
+
+	res, err := client.NewCreateOrderService().Symbol("BTCUSDT").Side(binance.SideTypeBuy).
+		Type(binance.OrderTypeMarket).Quantity("0.1").NewClientOrderID("buy-1").Do(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, binance.OrderStatusTypeFilled, res.Status)
+	assert.Equal(t, "buy-1", res.ClientOrderID)
+	assert.Equal(t, "0.10000000", res.ExecutedQuantity)
+	assert.Equal(t, "5000.00000000", res.CummulativeQuoteQuantity)
+	require.Len(t, res.Fills, 1)
+	assert.Equal(t, "0.00010000", res.Fills[0].Commission)
+	assert.Equal(t, "BTC", res.Fills[0].CommissionAsset)
+
+	// 手续费从买入的资产中扣除
+	free, locked := s.Balance("BTC")
+	assert.InDelta(t, 1.0999, free, 1e-12)
+	assert.Zero(t, locked)
+	free, _ = s.Balance("USDT")
+	assert.InDelta(t, 5000, free, 1e-9)
+
+	// 按金额下单时数量按步长向下取整
+	res, err = client.NewCreateOrderService().Symbol("BTCUSDT").Side(binance.SideTypeBuy).
+		Type(binance.OrderTypeMarket).QuoteOrderQty("100.99").Do(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "0.00200000", res.ExecutedQuantity)
+	assert.NotEmpty(t, res.ClientOrderID)
+
+	trades, err := client.NewListTradesService().Symbol("BTCUSDT").OrderId(res.OrderID).Do(ctx)
+	require.NoError(t, err)
+	require.Len(t, trades, 1)
+	assert.Equal(t, "100.00000000", trades[0].QuoteQuantity)
+	assert.False(t, trades[0].IsMaker)
+}
+
+func TestServer_LimitOrder(t *testing.T) {
+	s, client := newTestServer(t)
+	s.SetCommission(0.0005, 0.001)
+	ctx := context.Background()
+
+	res, err := client.NewCreateOrderService().Symbol("BTCUSDT").Side(binance.SideTypeBuy).
+		Type(binance.OrderTypeLimit).TimeInForce(binance.TimeInForceTypeGTC).
+		Quantity("0.1").Price("49000").Do(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, binance.OrderStatusTypeNew, res.Status)
+	free, locked := s.Balance("USDT")
+	assert.InDelta(t, 5100, free, 1e-9)
+	assert.InDelta(t, 4900, locked, 1e-9)
+
+	// 部分成交后冻结剩余部分
+	require.NoError(t, s.Fill(res.OrderID, 0.04))
+	order, err := client.NewGetOrderService().Symbol("BTCUSDT").OrderID(res.OrderID).Do(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, binance.OrderStatusTypePartiallyFilled, order.Status)
+	assert.Equal(t, "0.04000000", order.ExecutedQuantity)
+	assert.Equal(t, "1960.00000000", order.CummulativeQuoteQuantity)
+	_, locked = s.Balance("USDT")
+	assert.InDelta(t, 2940, locked, 1e-9)
+
+	// 价格跌到委托价时剩余部分按委托价成交
+	s.SetPrice("BTCUSDT", 48900)
+	order, err = client.NewGetOrderService().Symbol("BTCUSDT").OrigClientOrderID(res.ClientOrderID).Do(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, binance.OrderStatusTypeFilled, order.Status)
+	free, locked = s.Balance("USDT")
+	assert.InDelta(t, 5100, free, 1e-9)
+	assert.Zero(t, locked)
+	free, _ = s.Balance("BTC")
+	assert.InDelta(t, 1.09995, free, 1e-12)
+	trades := s.Trades()
+	require.Len(t, trades, 2)
+	assert.True(t, trades[1].IsMaker)
+
+	// 撤单释放冻结资金，已结束的订单不能再撤
+	res, err = client.NewCreateOrderService().Symbol("BTCUSDT").Side(binance.SideTypeSell).
+		Type(binance.OrderTypeLimit).TimeInForce(binance.TimeInForceTypeGTC).
+		Quantity("0.5").Price("60000").Do(ctx)
+	require.NoError(t, err)
+	_, locked = s.Balance("BTC")
+	assert.InDelta(t, 0.5, locked, 1e-12)
+	open, err := client.NewListOpenOrdersService().Symbol("BTCUSDT").Do(ctx)
+	require.NoError(t, err)
+	assert.Len(t, open, 1)
+
+	canceled, err := client.NewCancelOrderService().Symbol("BTCUSDT").OrderID(res.OrderID).Do(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, binance.OrderStatusTypeCanceled, canceled.Status)
+	_, locked = s.Balance("BTC")
+	assert.Zero(t, locked)
+	_, err = client.NewCancelOrderService().Symbol("BTCUSDT").OrderID(res.OrderID).Do(ctx)
+	assert.Equal(t, int64(-2011), apiCode(t, err))
+	_, err = client.NewGetOrderService().Symbol("BTCUSDT").OrderID(404).Do(ctx)
+	assert.Equal(t, int64(-2013), apiCode(t, err))
+
+	// 穿价的限价单立即按最新价格成交
+	res, err = client.NewCreateOrderService().Symbol("BTCUSDT").Side(binance.SideTypeSell).
+		Type(binance.OrderTypeLimit).TimeInForce(binance.TimeInForceTypeGTC).
+		Quantity("0.1").Price("48000").Do(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, binance.OrderStatusTypeFilled, res.Status)
+	assert.Equal(t, "4890.00000000", res.CummulativeQuoteQuantity)
+}
+
+func TestServer_Rejections(t *testing.T) {
+	s, client := newTestServer(t)
+	ctx := context.Background()
+
+	limit := func(side binance.SideType, qty, price string) *binance.CreateOrderService {
+		return client.NewCreateOrderService().Symbol("BTCUSDT").Side(side).Type(binance.OrderTypeLimit).
+			TimeInForce(binance.TimeInForceTypeGTC).Quantity(qty).Price(price)
+	}
+	tests := []struct {
+		name    string
+		service *binance.CreateOrderService
+		code    int64
+		msg     string
+	}{
+		{"invalid symbol", client.NewCreateOrderService().Symbol("XXXUSDT").Side(binance.SideTypeBuy).Type(binance.OrderTypeMarket).Quantity("1"), -1121, "Invalid symbol."},
+		{"lot size step", limit(binance.SideTypeBuy, "0.00015", "49000"), -1013, "Filter failure: LOT_SIZE"},
+		{"price tick", limit(binance.SideTypeBuy, "0.001", "49000.005"), -1013, "Filter failure: PRICE_FILTER"},
+		{"min notional", limit(binance.SideTypeBuy, "0.0001", "49000"), -1013, "Filter failure: NOTIONAL"},
+		{"insufficient quote", limit(binance.SideTypeBuy, "1", "49000"), -2010, "Account has insufficient balance for requested action."},
+		{"insufficient base", limit(binance.SideTypeSell, "2", "51000"), -2010, "Account has insufficient balance for requested action."},
+		{"missing time in force", client.NewCreateOrderService().Symbol("BTCUSDT").Side(binance.SideTypeBuy).Type(binance.OrderTypeLimit).Quantity("0.1").Price("49000"), -1102, ""},
+		{"limit maker would take", client.NewCreateOrderService().Symbol("BTCUSDT").Side(binance.SideTypeBuy).Type(binance.OrderTypeLimitMaker).Quantity("0.1").Price("51000"), -2010, "Order would immediately match and take."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.service.Do(ctx)
+			var apiErr *common.APIError
+			require.True(t, errors.As(err, &apiErr), err)
+			assert.Equal(t, tt.code, apiErr.Code)
+			if tt.msg != "" {
+				assert.Equal(t, tt.msg, apiErr.Message)
+			}
+		})
+	}
+	assert.Empty(t, s.Orders())
+
+	// 未结束订单的客户端订单号不能重复
+	_, err := limit(binance.SideTypeBuy, "0.1", "49000").NewClientOrderID("dup").Do(ctx)
+	require.NoError(t, err)
+	_, err = limit(binance.SideTypeBuy, "0.1", "49000").NewClientOrderID("dup").Do(ctx)
+	assert.Equal(t, int64(-2010), apiCode(t, err))
+
+	// 停止交易的交易对
+	s.AddSymbol(exchangeinfo.Symbol{Symbol: "OLDUSDT", BaseAsset: "OLD", QuoteAsset: "USDT", Status: "BREAK"}, 1)
+	_, err = client.NewCreateOrderService().Symbol("OLDUSDT").Side(binance.SideTypeBuy).Type(binance.OrderTypeMarket).Quantity("1").Do(ctx)
+	assert.Equal(t, int64(-1013), apiCode(t, err))
+}
+
+func TestServer_OCO(t *testing.T) {
+	s, client := newTestServer(t)
+	ctx := context.Background()
+
+	oco := func() *binance.CreateOCOResponse {
+		res, err := client.NewCreateOCOService().Symbol("BTCUSDT").Side(binance.SideTypeSell).
+			Quantity("0.5").Price("55000").StopPrice("45000").Do(ctx)
+		require.NoError(t, err)
+		return res
+	}
+
+	// 止盈价和止损价必须位于最新价格两侧
+	_, err := client.NewCreateOCOService().Symbol("BTCUSDT").Side(binance.SideTypeSell).
+		Quantity("0.5").Price("49000").StopPrice("45000").Do(ctx)
+	assert.Equal(t, int64(-2010), apiCode(t, err))
+
+	res := oco()
+	require.Len(t, res.OrderReports, 2)
+	assert.Equal(t, binance.OrderTypeStopLoss, res.OrderReports[0].Type)
+	assert.Equal(t, binance.OrderTypeLimitMaker, res.OrderReports[1].Type)
+	assert.Equal(t, "EXEC_STARTED", res.ListStatusType)
+	// 两个订单共用一份冻结资金
+	_, locked := s.Balance("BTC")
+	assert.InDelta(t, 0.5, locked, 1e-12)
+
+	// 止盈单成交后止损单过期
+	s.SetPrice("BTCUSDT", 55000)
+	stop, _ := s.Order(res.OrderReports[0].OrderID)
+	profit, _ := s.Order(res.OrderReports[1].OrderID)
+	assert.Equal(t, StatusExpired, stop.Status)
+	assert.Equal(t, StatusFilled, profit.Status)
+	free, locked := s.Balance("BTC")
+	assert.InDelta(t, 0.5, free, 1e-12)
+	assert.Zero(t, locked)
+
+	// 止损触发后按最新价格成交，止盈单过期
+	s.SetPrice("BTCUSDT", 50000)
+	res = oco()
+	s.SetPrice("BTCUSDT", 44000)
+	stop, _ = s.Order(res.OrderReports[0].OrderID)
+	profit, _ = s.Order(res.OrderReports[1].OrderID)
+	assert.Equal(t, StatusFilled, stop.Status)
+	assert.Equal(t, 22000.0, stop.QuoteQty)
+	assert.Equal(t, StatusExpired, profit.Status)
+	free, locked = s.Balance("BTC")
+	assert.Zero(t, free)
+	assert.Zero(t, locked)
+
+	// 撤销订单组释放冻结资金
+	s.SetPrice("BTCUSDT", 50000)
+	s.SetBalance("BTC", 1)
+	res = oco()
+	canceled, err := client.NewCancelOCOService().Symbol("BTCUSDT").OrderListID(res.OrderListID).Do(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "ALL_DONE", canceled.ListStatusType)
+	for _, report := range canceled.OrderReports {
+		assert.Equal(t, binance.OrderStatusTypeCanceled, report.Status)
+	}
+	free, locked = s.Balance("BTC")
+	assert.Equal(t, 1.0, free)
+	assert.Zero(t, locked)
+	_, err = client.NewCancelOCOService().Symbol("BTCUSDT").OrderListID(res.OrderListID).Do(ctx)
+	assert.Equal(t, int64(-2011), apiCode(t, err))
+}
+
+func TestServer_FailNext(t *testing.T) {
+	s, client := newTestServer(t)
+	ctx := context.Background()
+
+	s.FailNext("/api/v3/account", http.StatusTooManyRequests, -1003, "Too many requests.")
+	_, err := client.NewGetAccountService().Do(ctx)
+	assert.Equal(t, int64(-1003), apiCode(t, err))
+	account, err := client.NewGetAccountService().Do(ctx)
+	require.NoError(t, err)
+	assert.Len(t, account.Balances, 2)
+	assert.Equal(t, 2, s.Requests(http.MethodGet, "/api/v3/account"))
+
+	info, err := client.NewExchangeInfoService().Symbol("BTCUSDT").Do(ctx)
+	require.NoError(t, err)
+	require.Len(t, info.Symbols, 1)
+	assert.Equal(t, "0.01000000", info.Symbols[0].PriceFilter().TickSize)
+	assert.Equal(t, "0.00010000", info.Symbols[0].LotSizeFilter().StepSize)
+}
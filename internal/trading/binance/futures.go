@@ -0,0 +1,113 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/precision"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+
+	"github.com/adshao/go-binance/v2/futures"
+)
+
+// FuturesHedger implements trading.Hedger on Binance USDⓈ-M perpetual futures,
+// 合约与现货使用同名交易对，账户需为单向持仓模式
+type FuturesHedger struct {
+	client *futures.Client
+	// 合约的数量步长与现货不同，未加载合约元数据，按默认精度格式化数量
+	format *precision.Formatter
+}
+
+// NewFuturesHedger creates a new FuturesHedger instance
+func NewFuturesHedger(apiKey, secretKey string, debug ...bool) *FuturesHedger {
+	debug = append(debug, false)
+	if debug[0] {
+		futures.UseTestnet = true
+	}
+	return &FuturesHedger{client: futures.NewClient(apiKey, secretKey), format: precision.NewFormatter(nil)}
+}
+
+// OpenHedge implements trading.Hedger，以市价单开合约空单
+func (h *FuturesHedger) OpenHedge(ctx context.Context, symbol string, amount float64) (*trading.Order, error) {
+	if amount <= 0 {
+		return nil, fmt.Errorf("%w: invalid amount: %s", trading.ErrOrderRejected, precision.String(amount))
+	}
+	order, err := h.marketOrder(ctx, symbol, futures.SideTypeSell, amount, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open hedge: %w", err)
+	}
+	return order, nil
+}
+
+// CloseHedge implements trading.Hedger，以只减仓市价单平掉合约空单
+func (h *FuturesHedger) CloseHedge(ctx context.Context, symbol string) (*trading.Order, error) {
+	amount, err := h.HedgeAmount(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	if amount <= 0 {
+		return nil, nil
+	}
+	order, err := h.marketOrder(ctx, symbol, futures.SideTypeBuy, amount, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to close hedge: %w", err)
+	}
+	return order, nil
+}
+
+// HedgeAmount implements trading.Hedger，多头合约持仓不视为对冲，返回 0
+func (h *FuturesHedger) HedgeAmount(ctx context.Context, symbol string) (float64, error) {
+	positions, err := h.client.NewGetPositionRiskService().Symbol(symbol).Do(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get futures position: %w", wrapError(err))
+	}
+
+	var amount float64
+	for _, position := range positions {
+		if position.Symbol != symbol {
+			continue
+		}
+		size, _ := strconv.ParseFloat(position.PositionAmt, 64)
+		if size < 0 {
+			amount += math.Abs(size)
+		}
+	}
+	return amount, nil
+}
+
+// marketOrder 下合约市价单并返回成交结果
+func (h *FuturesHedger) marketOrder(ctx context.Context, symbol string, side futures.SideType, amount float64, reduceOnly bool) (*trading.Order, error) {
+	result, err := h.client.NewCreateOrderService().
+		Symbol(symbol).
+		Side(side).
+		Type(futures.OrderTypeMarket).
+		Quantity(h.format.Quantity(symbol, amount)).
+		ReduceOnly(reduceOnly).
+		Do(ctx)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	order := &trading.Order{
+		Symbol:        symbol,
+		Side:          "sell",
+		Amount:        amount,
+		OrderType:     "market",
+		Status:        string(result.Status),
+		OrderID:       strconv.FormatInt(result.OrderID, 10),
+		RawOrderID:    result.OrderID,
+		ClientOrderID: result.ClientOrderID,
+		CreatedAt:     time.UnixMilli(result.UpdateTime),
+		UpdatedAt:     time.UnixMilli(result.UpdateTime),
+	}
+	if side == futures.SideTypeBuy {
+		order.Side = "buy"
+	}
+	order.FilledAmount, _ = strconv.ParseFloat(result.ExecutedQuantity, 64)
+	order.FilledPrice, _ = strconv.ParseFloat(result.AvgPrice, 64)
+	order.Price = order.FilledPrice
+	return order, nil
+}
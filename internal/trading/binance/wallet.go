@@ -0,0 +1,64 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/adshao/go-binance/v2"
+
+	"github.com/songzhibin97/quantaflux/internal/trading"
+)
+
+// systemStatusPath 交易所系统状态接口，go-binance 未提供
+const systemStatusPath = "/sapi/v1/system/status"
+
+// SystemStatus implements trading.WalletStatusProvider，查询交易所是否处于系统维护
+func (b *BinanceExecutor) SystemStatus(ctx context.Context) (trading.SystemStatus, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.client.BaseURL+systemStatusPath, nil)
+	if err != nil {
+		return trading.SystemStatus{}, err
+	}
+	resp, err := b.client.HTTPClient.Do(req)
+	if err != nil {
+		return trading.SystemStatus{}, fmt.Errorf("%w: failed to get system status: %v", trading.ErrExchangeUnavailable, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return trading.SystemStatus{}, fmt.Errorf("%w: system status returned %s", trading.ErrExchangeUnavailable, resp.Status)
+	}
+
+	var status struct {
+		Status int    `json:"status"` // 0 正常，1 系统维护
+		Msg    string `json:"msg"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return trading.SystemStatus{}, fmt.Errorf("failed to decode system status: %w", err)
+	}
+	return trading.SystemStatus{Maintenance: status.Status != 0, Message: status.Msg}, nil
+}
+
+// AssetStatuses implements trading.WalletStatusProvider，查询所有资产的充值和提现开关
+func (b *BinanceExecutor) AssetStatuses(ctx context.Context) ([]trading.AssetStatus, error) {
+	var coins []*binance.CoinInfo
+	// 该接口不接受 recv_window 选项，只沿用时间同步和时间戳重试
+	err := b.signed(ctx, func(...binance.RequestOption) error {
+		var err error
+		coins, err = b.client.NewGetAllCoinsInfoService().Do(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get coin info: %w", err)
+	}
+
+	statuses := make([]trading.AssetStatus, 0, len(coins))
+	for _, coin := range coins {
+		statuses = append(statuses, trading.AssetStatus{
+			Asset:           coin.Coin,
+			DepositEnabled:  coin.DepositAllEnable,
+			WithdrawEnabled: coin.WithdrawAllEnable,
+		})
+	}
+	return statuses, nil
+}
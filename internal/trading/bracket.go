@@ -0,0 +1,370 @@
+package trading
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// 组合订单状态
+const (
+	BracketPending  = "PENDING"  // 入场单未成交
+	BracketActive   = "ACTIVE"   // 入场单已成交，止盈止损生效
+	BracketClosed   = "CLOSED"   // 止盈或止损已成交
+	BracketCanceled = "CANCELED" // 入场单未成交即撤销，或平仓单被撤销
+	BracketFailed   = "FAILED"   // 平仓单创建失败，入场仓位已按市价平掉
+)
+
+// BracketOrder 组合订单：入场单成交后按成交数量同时挂出止盈和止损平仓单，任一成交后撤销另一个
+type BracketOrder struct {
+	ID          string  `json:"id"`
+	Entry       Order   `json:"entry"`
+	TakeProfit  float64 `json:"take_profit"`       // 止盈价，平仓限价单的委托价格
+	StopLoss    float64 `json:"stop_loss"`         // 止损触发价
+	ProfitOrder Order   `json:"profit_order"`      // 止盈平仓单，入场成交后创建
+	StopOrder   Order   `json:"stop_order"`        // 止损平仓单：原生 OCO 时随止盈单一起创建，模拟时在触发止损后按市价创建
+	Native      bool    `json:"native"`            // 平仓单由交易所的 OCO 订单实现
+	ListID      string  `json:"list_id,omitempty"` // 交易所 OCO 订单组 ID
+	Status      string  `json:"status"`
+}
+
+// OCOOrder 交易所原生的二选一订单组：同数量的止盈限价单和止损单，任一成交后交易所撤销另一个
+type OCOOrder struct {
+	Symbol      string
+	Side        string
+	Amount      float64
+	TakeProfit  float64
+	StopLoss    float64
+	ListID      string // 以下字段下单后由执行器设置
+	ProfitOrder Order
+	StopOrder   Order
+}
+
+// OCOExecutor is implemented by executors that support one-cancels-the-other orders natively
+type OCOExecutor interface {
+	// PlaceOCO places a take-profit limit order and a stop-loss order for the same amount
+	PlaceOCO(ctx context.Context, oco *OCOOrder) error
+
+	// CancelOCO cancels the remaining orders of an OCO order list
+	CancelOCO(ctx context.Context, symbol, listID string) error
+}
+
+// exitSide 返回平仓单的方向
+func (b *BracketOrder) exitSide() string {
+	if b.Entry.Side == "sell" {
+		return "buy"
+	}
+	return "sell"
+}
+
+// validate 检查止盈止损价格：买入时止损价低于止盈价，卖出时相反，限价入场单的价格应位于两者之间。
+// 市价单的价格只是预估，不参与检查
+func (b *BracketOrder) validate() error {
+	if b.TakeProfit <= 0 || b.StopLoss <= 0 {
+		return fmt.Errorf("%w: take profit and stop loss prices are required", ErrOrderRejected)
+	}
+
+	low, high := b.StopLoss, b.TakeProfit
+	switch b.Entry.Side {
+	case "buy":
+	case "sell":
+		low, high = high, low
+	default:
+		return fmt.Errorf("%w: invalid side: %s", ErrOrderRejected, b.Entry.Side)
+	}
+	limit := b.Entry.OrderType == "limit" && b.Entry.Price > 0
+	if low >= high || (limit && (b.Entry.Price <= low || b.Entry.Price >= high)) {
+		return fmt.Errorf("%w: %s entry at %g requires stop loss %g and take profit %g on opposite sides of the entry price",
+			ErrOrderRejected, b.Entry.Side, b.Entry.Price, b.StopLoss, b.TakeProfit)
+	}
+	return nil
+}
+
+// stopTriggered 判断价格是否触及止损价
+func (b *BracketOrder) stopTriggered(price float64) bool {
+	if b.Entry.Side == "sell" {
+		return price >= b.StopLoss
+	}
+	return price <= b.StopLoss
+}
+
+// exitOrder 返回按入场单成交数量平仓的订单
+func (b *BracketOrder) exitOrder(orderType string, amount, price float64) Order {
+	order := Order{
+		Account:   b.Entry.Account,
+		Symbol:    b.Entry.Symbol,
+		Side:      b.exitSide(),
+		Amount:    amount,
+		Price:     price,
+		OrderType: orderType,
+	}
+	if orderType == "limit" {
+		order.SubmittedPrice = price
+	}
+	return order
+}
+
+// OrderManager 管理组合订单：执行器支持 OCO 时由交易所撤销另一个平仓单，
+// 否则挂出止盈限价单，由 OnPrice 按行情在本地触发止损
+type OrderManager struct {
+	executor TradeExecutor
+	mu       sync.Mutex
+	brackets map[string]*BracketOrder
+	nextID   int64
+}
+
+func NewOrderManager(executor TradeExecutor) *OrderManager {
+	return &OrderManager{
+		executor: executor,
+		brackets: make(map[string]*BracketOrder),
+	}
+}
+
+// PlaceBracket 下入场单，入场单立即成交时同时挂出平仓单；入场单被拒绝时不创建任何订单，
+// 平仓单创建失败时按市价平掉入场仓位，不留下没有止损保护的持仓
+func (m *OrderManager) PlaceBracket(ctx context.Context, bracket *BracketOrder) error {
+	if err := bracket.validate(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.executor.PlaceOrder(ctx, &bracket.Entry); err != nil {
+		return err
+	}
+	m.nextID++
+	bracket.ID = "bracket-" + strconv.FormatInt(m.nextID, 10)
+	bracket.Status = BracketPending
+
+	stored := *bracket
+	m.brackets[stored.ID] = &stored
+
+	var err error
+	if !IsOpenStatus(stored.Entry.Status) {
+		err = m.activate(ctx, &stored)
+	}
+	*bracket = stored
+	return err
+}
+
+// activate 入场单成交后按成交数量挂出平仓单，需持有锁
+func (m *OrderManager) activate(ctx context.Context, b *BracketOrder) error {
+	amount := b.Entry.ExecutedAmount()
+	if amount <= 0 {
+		b.Status = BracketCanceled
+		return nil
+	}
+
+	var err error
+	if oco, ok := As[OCOExecutor](m.executor); ok {
+		order := &OCOOrder{Symbol: b.Entry.Symbol, Side: b.exitSide(), Amount: amount, TakeProfit: b.TakeProfit, StopLoss: b.StopLoss}
+		if err = oco.PlaceOCO(ctx, order); err == nil {
+			b.Native = true
+			b.ListID = order.ListID
+			b.ProfitOrder, b.StopOrder = order.ProfitOrder, order.StopOrder
+			b.ProfitOrder.Account, b.StopOrder.Account = b.Entry.Account, b.Entry.Account
+			b.Status = BracketActive
+			return nil
+		}
+	} else {
+		profit := b.exitOrder("limit", amount, b.TakeProfit)
+		if err = m.executor.PlaceOrder(ctx, &profit); err == nil {
+			b.ProfitOrder = profit
+			b.Status = BracketActive
+			return nil
+		}
+	}
+
+	b.Status = BracketFailed
+	closing := b.exitOrder("market", amount, b.Entry.ExecutedPrice())
+	if closeErr := m.executor.PlaceOrder(ctx, &closing); closeErr != nil {
+		return errors.Join(fmt.Errorf("failed to place exit orders: %w", err), fmt.Errorf("failed to close entry position: %w", closeErr))
+	}
+	b.StopOrder = closing
+	return fmt.Errorf("failed to place exit orders, entry position closed: %w", err)
+}
+
+// Sync 查询未结束的组合订单的入场单和平仓单状态：入场单成交后挂出平仓单，平仓单成交后结束
+func (m *OrderManager) Sync(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var errs []error
+	for _, b := range m.brackets {
+		if err := m.sync(ctx, b); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", b.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sync 同步单个组合订单，需持有锁
+func (m *OrderManager) sync(ctx context.Context, b *BracketOrder) error {
+	switch b.Status {
+	case BracketPending:
+		if err := m.refresh(ctx, &b.Entry); err != nil {
+			return err
+		}
+		if IsOpenStatus(b.Entry.Status) {
+			return nil
+		}
+		return m.activate(ctx, b)
+	case BracketActive:
+		if err := m.refresh(ctx, &b.ProfitOrder); err != nil {
+			return err
+		}
+		if !b.Native {
+			// 模拟止损只在 OnPrice 中触发，止盈单结束（成交或在外部被撤销）即结束
+			if !IsOpenStatus(b.ProfitOrder.Status) {
+				b.Status = BracketClosed
+			}
+			return nil
+		}
+		if err := m.refresh(ctx, &b.StopOrder); err != nil {
+			return err
+		}
+		if !IsOpenStatus(b.ProfitOrder.Status) && !IsOpenStatus(b.StopOrder.Status) {
+			b.Status = BracketClosed
+		}
+	}
+	return nil
+}
+
+// refresh 从执行器查询订单的最新状态和成交
+func (m *OrderManager) refresh(ctx context.Context, order *Order) error {
+	latest, err := m.executor.GetOrderStatus(ctx, order.Symbol, order.OrderID)
+	if err != nil {
+		return err
+	}
+	order.Status = latest.Status
+	if latest.FilledAmount > 0 {
+		order.FilledAmount = latest.FilledAmount
+	}
+	if latest.FilledPrice > 0 {
+		order.FilledPrice = latest.FilledPrice
+	}
+	return nil
+}
+
+// OnPrice 按交易对的最新价格触发本地模拟的止损：撤销止盈单后按未平仓数量市价平仓。
+// 模拟执行器按行情撮合时，应在更新执行器的价格之后调用
+func (m *OrderManager) OnPrice(ctx context.Context, symbol string, price float64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var errs []error
+	for _, b := range m.brackets {
+		if b.Native || b.Status != BracketActive || b.Entry.Symbol != symbol || !b.stopTriggered(price) {
+			continue
+		}
+		if err := m.triggerStop(ctx, b, price); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", b.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// triggerStop 撤销止盈单并市价平掉剩余仓位，需持有锁
+func (m *OrderManager) triggerStop(ctx context.Context, b *BracketOrder, price float64) error {
+	if IsOpenStatus(b.ProfitOrder.Status) {
+		// 撤单失败时止盈单可能已经成交，以查询到的状态为准
+		cancelErr := m.executor.CancelOrder(ctx, b.ProfitOrder.Symbol, b.ProfitOrder.OrderID)
+		if err := m.refresh(ctx, &b.ProfitOrder); err != nil {
+			return errors.Join(cancelErr, err)
+		}
+		if IsOpenStatus(b.ProfitOrder.Status) {
+			return fmt.Errorf("failed to cancel take profit order: %w", cancelErr)
+		}
+	}
+
+	remaining := b.Entry.ExecutedAmount() - b.ProfitOrder.ExecutedAmount()
+	if remaining <= 0 {
+		b.Status = BracketClosed
+		return nil
+	}
+	stop := b.exitOrder("market", remaining, price)
+	if err := m.executor.PlaceOrder(ctx, &stop); err != nil {
+		return fmt.Errorf("failed to place stop loss order: %w", err)
+	}
+	b.StopOrder = stop
+	b.Status = BracketClosed
+	return nil
+}
+
+// CancelBracket 撤销组合订单：入场单未成交时撤销入场单，已成交时撤销平仓单，已有的持仓不平掉
+func (m *OrderManager) CancelBracket(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b, ok := m.brackets[id]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrOrderNotFound, id)
+	}
+
+	var err error
+	switch {
+	case b.Status == BracketPending:
+		err = m.executor.CancelOrder(ctx, b.Entry.Symbol, b.Entry.OrderID)
+	case b.Status == BracketActive && b.Native:
+		oco, _ := As[OCOExecutor](m.executor)
+		err = oco.CancelOCO(ctx, b.Entry.Symbol, b.ListID)
+	case b.Status == BracketActive:
+		err = m.executor.CancelOrder(ctx, b.ProfitOrder.Symbol, b.ProfitOrder.OrderID)
+	default:
+		return fmt.Errorf("bracket already %s: %s", b.Status, id)
+	}
+	if err != nil {
+		return err
+	}
+	b.Status = BracketCanceled
+	return nil
+}
+
+// Restore 恢复保存的组合订单，如重启前未结束的组合订单，新组合订单的序号从其中最大的序号之后开始
+func (m *OrderManager) Restore(brackets []BracketOrder) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, b := range brackets {
+		stored := b
+		m.brackets[stored.ID] = &stored
+		m.nextID = max(m.nextID, bracketSeq(stored.ID))
+	}
+}
+
+// Prune 移除已结束的组合订单
+func (m *OrderManager) Prune() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, b := range m.brackets {
+		if b.Status != BracketPending && b.Status != BracketActive {
+			delete(m.brackets, id)
+		}
+	}
+}
+
+// Brackets 返回所有组合订单的副本，按创建顺序排列
+func (m *OrderManager) Brackets() []BracketOrder {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := make([]BracketOrder, 0, len(m.brackets))
+	for _, b := range m.brackets {
+		result = append(result, *b)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return bracketSeq(result[i].ID) < bracketSeq(result[j].ID)
+	})
+	return result
+}
+
+// bracketSeq 返回组合订单 ID 中的序号
+func bracketSeq(id string) int64 {
+	seq, _ := strconv.ParseInt(id[len("bracket-"):], 10, 64)
+	return seq
+}
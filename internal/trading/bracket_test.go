@@ -0,0 +1,299 @@
+package trading
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeExecutor 市价单立即成交、限价单挂单的执行器，由测试设置订单状态
+type fakeExecutor struct {
+	orders map[string]*Order
+	nextID int
+	reject func(order *Order) bool
+}
+
+func newFakeExecutor() *fakeExecutor {
+	return &fakeExecutor{orders: make(map[string]*Order)}
+}
+
+func (f *fakeExecutor) PlaceOrder(ctx context.Context, order *Order) error {
+	if f.reject != nil && f.reject(order) {
+		return ErrOrderRejected
+	}
+	f.nextID++
+	order.OrderID = strconv.Itoa(f.nextID)
+	order.Status = "NEW"
+	if order.OrderType == "market" {
+		order.Status = "FILLED"
+		order.FilledAmount = order.Amount
+		order.FilledPrice = order.Price
+	}
+	stored := *order
+	f.orders[order.OrderID] = &stored
+	return nil
+}
+
+func (f *fakeExecutor) CancelOrder(ctx context.Context, symbol string, orderID string) error {
+	order, ok := f.orders[orderID]
+	if !ok || !IsOpenStatus(order.Status) {
+		return ErrOrderNotFound
+	}
+	order.Status = "CANCELED"
+	return nil
+}
+
+func (f *fakeExecutor) GetOrderStatus(ctx context.Context, symbol, orderID string) (*Order, error) {
+	order, ok := f.orders[orderID]
+	if !ok {
+		return nil, ErrOrderNotFound
+	}
+	result := *order
+	return &result, nil
+}
+
+func (f *fakeExecutor) GetBalance(ctx context.Context, symbol string) (float64, error) {
+	return 0, nil
+}
+
+// fill 将订单标记为按 amount 成交
+func (f *fakeExecutor) fill(orderID string, amount float64, status string) {
+	order := f.orders[orderID]
+	order.FilledAmount = amount
+	order.FilledPrice = order.Price
+	order.Status = status
+}
+
+// fakeOCOExecutor 原生支持 OCO 的执行器
+type fakeOCOExecutor struct {
+	*fakeExecutor
+	canceled []string
+}
+
+func (f *fakeOCOExecutor) PlaceOCO(ctx context.Context, oco *OCOOrder) error {
+	profit := Order{Symbol: oco.Symbol, Side: oco.Side, Amount: oco.Amount, Price: oco.TakeProfit, OrderType: "limit"}
+	stop := Order{Symbol: oco.Symbol, Side: oco.Side, Amount: oco.Amount, Price: oco.StopLoss, OrderType: "limit"}
+	_ = f.PlaceOrder(ctx, &profit)
+	_ = f.PlaceOrder(ctx, &stop)
+	oco.ListID = "list-1"
+	oco.ProfitOrder, oco.StopOrder = profit, stop
+	return nil
+}
+
+func (f *fakeOCOExecutor) CancelOCO(ctx context.Context, symbol, listID string) error {
+	f.canceled = append(f.canceled, listID)
+	return nil
+}
+
+func TestOrderManager_EmulatedBracket(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("take profit", func(t *testing.T) {
+		executor := newFakeExecutor()
+		manager := NewOrderManager(executor)
+
+		bracket := &BracketOrder{
+			Entry:      Order{Account: "main", Symbol: "BTCUSDT", Side: "buy", Amount: 1, Price: 100, OrderType: "limit"},
+			TakeProfit: 110,
+			StopLoss:   95,
+		}
+		require.NoError(t, manager.PlaceBracket(ctx, bracket))
+		assert.Equal(t, BracketPending, bracket.Status)
+
+		// 入场单成交后按成交数量挂出止盈单
+		executor.fill(bracket.Entry.OrderID, 0.6, "CANCELED")
+		require.NoError(t, manager.Sync(ctx))
+		active := manager.Brackets()[0]
+		assert.Equal(t, BracketActive, active.Status)
+		assert.False(t, active.Native)
+		assert.Equal(t, Order{Account: "main", Symbol: "BTCUSDT", Side: "sell", Amount: 0.6, Price: 110, SubmittedPrice: 110,
+			OrderType: "limit", Status: "NEW", OrderID: active.ProfitOrder.OrderID}, active.ProfitOrder)
+
+		executor.fill(active.ProfitOrder.OrderID, 0.6, "FILLED")
+		require.NoError(t, manager.Sync(ctx))
+		assert.Equal(t, BracketClosed, manager.Brackets()[0].Status)
+
+		// 已结束的组合订单不再触发止损
+		require.NoError(t, manager.OnPrice(ctx, "BTCUSDT", 90))
+		assert.Empty(t, manager.Brackets()[0].StopOrder.OrderID)
+	})
+
+	t.Run("stop loss", func(t *testing.T) {
+		executor := newFakeExecutor()
+		manager := NewOrderManager(executor)
+
+		bracket := &BracketOrder{
+			Entry:      Order{Symbol: "BTCUSDT", Side: "buy", Amount: 1, Price: 100, OrderType: "market"},
+			TakeProfit: 110,
+			StopLoss:   95,
+		}
+		require.NoError(t, manager.PlaceBracket(ctx, bracket))
+		assert.Equal(t, BracketActive, bracket.Status)
+		executor.fill(bracket.ProfitOrder.OrderID, 0.25, "PARTIALLY_FILLED")
+
+		require.NoError(t, manager.OnPrice(ctx, "ETHUSDT", 90))
+		require.NoError(t, manager.OnPrice(ctx, "BTCUSDT", 96))
+		assert.Equal(t, BracketActive, manager.Brackets()[0].Status)
+
+		// 触及止损价后撤销止盈单，剩余仓位市价平仓
+		require.NoError(t, manager.OnPrice(ctx, "BTCUSDT", 94))
+		closed := manager.Brackets()[0]
+		assert.Equal(t, BracketClosed, closed.Status)
+		assert.Equal(t, "CANCELED", closed.ProfitOrder.Status)
+		assert.Equal(t, "market", closed.StopOrder.OrderType)
+		assert.Equal(t, "sell", closed.StopOrder.Side)
+		assert.InDelta(t, 0.75, closed.StopOrder.Amount, 1e-9)
+	})
+
+	t.Run("exit order rejected", func(t *testing.T) {
+		executor := newFakeExecutor()
+		executor.reject = func(order *Order) bool { return order.OrderType == "limit" }
+		manager := NewOrderManager(executor)
+
+		bracket := &BracketOrder{
+			Entry:      Order{Symbol: "BTCUSDT", Side: "buy", Amount: 1, Price: 100, OrderType: "market"},
+			TakeProfit: 110,
+			StopLoss:   95,
+		}
+		err := manager.PlaceBracket(ctx, bracket)
+		assert.ErrorIs(t, err, ErrOrderRejected)
+		assert.Equal(t, BracketFailed, bracket.Status)
+		assert.Equal(t, "FILLED", bracket.StopOrder.Status)
+		assert.InDelta(t, 1, bracket.StopOrder.Amount, 1e-9)
+	})
+
+	t.Run("entry rejected", func(t *testing.T) {
+		executor := newFakeExecutor()
+		executor.reject = func(order *Order) bool { return true }
+		manager := NewOrderManager(executor)
+
+		err := manager.PlaceBracket(ctx, &BracketOrder{
+			Entry:      Order{Symbol: "BTCUSDT", Side: "buy", Amount: 1, Price: 100, OrderType: "market"},
+			TakeProfit: 110,
+			StopLoss:   95,
+		})
+		assert.ErrorIs(t, err, ErrOrderRejected)
+		assert.Empty(t, manager.Brackets())
+	})
+
+	t.Run("cancel pending", func(t *testing.T) {
+		executor := newFakeExecutor()
+		manager := NewOrderManager(executor)
+
+		bracket := &BracketOrder{
+			Entry:      Order{Symbol: "BTCUSDT", Side: "buy", Amount: 1, Price: 100, OrderType: "limit"},
+			TakeProfit: 110,
+			StopLoss:   95,
+		}
+		require.NoError(t, manager.PlaceBracket(ctx, bracket))
+		require.NoError(t, manager.CancelBracket(ctx, bracket.ID))
+		assert.Equal(t, "CANCELED", executor.orders[bracket.Entry.OrderID].Status)
+		assert.Equal(t, BracketCanceled, manager.Brackets()[0].Status)
+		assert.Error(t, manager.CancelBracket(ctx, bracket.ID))
+		assert.ErrorIs(t, manager.CancelBracket(ctx, "bracket-9"), ErrOrderNotFound)
+	})
+}
+
+func TestOrderManager_NativeBracket(t *testing.T) {
+	ctx := context.Background()
+	executor := &fakeOCOExecutor{fakeExecutor: newFakeExecutor()}
+	manager := NewOrderManager(executor)
+
+	bracket := &BracketOrder{
+		Entry:      Order{Symbol: "BTCUSDT", Side: "buy", Amount: 1, Price: 100, OrderType: "market"},
+		TakeProfit: 110,
+		StopLoss:   95,
+	}
+	require.NoError(t, manager.PlaceBracket(ctx, bracket))
+	assert.True(t, bracket.Native)
+	assert.Equal(t, "list-1", bracket.ListID)
+	assert.NotEmpty(t, bracket.StopOrder.OrderID)
+
+	// 交易所负责止损，本地不触发
+	require.NoError(t, manager.OnPrice(ctx, "BTCUSDT", 90))
+	assert.Equal(t, BracketActive, manager.Brackets()[0].Status)
+
+	// 止损成交后交易所撤销止盈单
+	executor.fill(bracket.StopOrder.OrderID, 1, "FILLED")
+	executor.orders[bracket.ProfitOrder.OrderID].Status = "EXPIRED"
+	require.NoError(t, manager.Sync(ctx))
+	assert.Equal(t, BracketClosed, manager.Brackets()[0].Status)
+
+	second := &BracketOrder{
+		Entry:      Order{Symbol: "BTCUSDT", Side: "buy", Amount: 1, Price: 100, OrderType: "market"},
+		TakeProfit: 110,
+		StopLoss:   95,
+	}
+	require.NoError(t, manager.PlaceBracket(ctx, second))
+	require.NoError(t, manager.CancelBracket(ctx, second.ID))
+	assert.Equal(t, []string{"list-1"}, executor.canceled)
+}
+
+func TestOrderManager_RestoreAndPrune(t *testing.T) {
+	ctx := context.Background()
+	executor := newFakeExecutor()
+	manager := NewOrderManager(executor)
+
+	first := &BracketOrder{
+		Entry:      Order{Symbol: "BTCUSDT", Side: "buy", Amount: 1, Price: 100, OrderType: "market"},
+		TakeProfit: 110,
+		StopLoss:   95,
+	}
+	require.NoError(t, manager.PlaceBracket(ctx, first))
+	second := &BracketOrder{
+		Entry:      Order{Symbol: "BTCUSDT", Side: "buy", Amount: 1, Price: 100, OrderType: "market"},
+		TakeProfit: 110,
+		StopLoss:   95,
+	}
+	require.NoError(t, manager.PlaceBracket(ctx, second))
+	require.NoError(t, manager.CancelBracket(ctx, second.ID))
+
+	// 只保留未结束的组合订单
+	manager.Prune()
+	saved := manager.Brackets()
+	require.Len(t, saved, 1)
+	assert.Equal(t, first.ID, saved[0].ID)
+
+	// 重启后恢复的组合订单继续按行情触发止损，新组合订单的序号在恢复的序号之后
+	restored := NewOrderManager(executor)
+	restored.Restore(saved)
+	require.NoError(t, restored.OnPrice(ctx, "BTCUSDT", 94))
+	assert.Equal(t, BracketClosed, restored.Brackets()[0].Status)
+	third := &BracketOrder{
+		Entry:      Order{Symbol: "BTCUSDT", Side: "buy", Amount: 1, Price: 100, OrderType: "market"},
+		TakeProfit: 110,
+		StopLoss:   95,
+	}
+	require.NoError(t, restored.PlaceBracket(ctx, third))
+	assert.Equal(t, "bracket-2", third.ID)
+}
+
+func TestBracketOrder_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		bracket BracketOrder
+		wantErr bool
+	}{
+		{name: "long", bracket: BracketOrder{Entry: Order{Side: "buy", Price: 100, OrderType: "limit"}, TakeProfit: 110, StopLoss: 95}},
+		{name: "short", bracket: BracketOrder{Entry: Order{Side: "sell", Price: 100, OrderType: "limit"}, TakeProfit: 90, StopLoss: 105}},
+		{name: "market without price", bracket: BracketOrder{Entry: Order{Side: "buy", OrderType: "market"}, TakeProfit: 110, StopLoss: 95}},
+		{name: "market estimate outside", bracket: BracketOrder{Entry: Order{Side: "buy", Price: 120, OrderType: "market"}, TakeProfit: 110, StopLoss: 95}},
+		{name: "inverted", bracket: BracketOrder{Entry: Order{Side: "buy", Price: 100, OrderType: "limit"}, TakeProfit: 95, StopLoss: 110}, wantErr: true},
+		{name: "entry outside", bracket: BracketOrder{Entry: Order{Side: "buy", Price: 120, OrderType: "limit"}, TakeProfit: 110, StopLoss: 95}, wantErr: true},
+		{name: "missing stop", bracket: BracketOrder{Entry: Order{Side: "buy", Price: 100, OrderType: "limit"}, TakeProfit: 110}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.bracket.validate()
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, ErrOrderRejected))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
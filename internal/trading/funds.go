@@ -0,0 +1,65 @@
+package trading
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// InsufficientFundsError 下单前检查发现可用余额不足，errors.Is 同时匹配 ErrInsufficientFunds 和 ErrOrderRejected
+type InsufficientFundsError struct {
+	Asset     string  // 不足的资产：买入为计价资产，卖出为基础资产
+	Available float64 // 可用余额
+	Required  float64 // 订单需要的数量
+}
+
+func (e *InsufficientFundsError) Error() string {
+	return fmt.Sprintf("insufficient %s balance: have %s, need %s", e.Asset,
+		strconv.FormatFloat(e.Available, 'f', -1, 64), strconv.FormatFloat(e.Required, 'f', -1, 64))
+}
+
+func (e *InsufficientFundsError) Is(target error) bool {
+	return target == ErrInsufficientFunds || target == ErrOrderRejected
+}
+
+// Affordable 返回可用余额能支付的订单比例，数量和金额按该比例缩小后可以下单
+func (e *InsufficientFundsError) Affordable() float64 {
+	if e.Required <= 0 || e.Available <= 0 {
+		return 0
+	}
+	return min(e.Available/e.Required, 1)
+}
+
+// RequiredFunds 返回订单需要占用的资产和数量：买入为按 price 计算的计价资产金额，按金额下的市价单为该金额；
+// 卖出为基础资产数量。无法拆分交易对或买入价格未知时返回 false
+func RequiredFunds(order *Order, price float64) (string, float64, bool) {
+	base, quote, ok := SplitSymbol(order.Symbol)
+	if !ok {
+		return "", 0, false
+	}
+	switch order.Side {
+	case "buy":
+		if order.UsesQuoteAmount() {
+			return quote, order.QuoteAmount, true
+		}
+		if price <= 0 {
+			return "", 0, false
+		}
+		return quote, order.BaseAmount(price) * price, true
+	case "sell":
+		return base, order.BaseAmount(price), true
+	default:
+		return "", 0, false
+	}
+}
+
+// CheckFunds 按可用余额检查订单，余额不足时返回 *InsufficientFundsError，无法计算所需资金时不检查
+func CheckFunds(order *Order, price float64, available func(asset string) float64) error {
+	asset, required, ok := RequiredFunds(order, price)
+	if !ok || required <= 0 {
+		return nil
+	}
+	if have := available(asset); have < required {
+		return &InsufficientFundsError{Asset: asset, Available: have, Required: required}
+	}
+	return nil
+}
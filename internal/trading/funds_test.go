@@ -0,0 +1,30 @@
+package trading
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckFunds(t *testing.T) {
+	balances := map[string]float64{"USDT": 300, "BTC": 0.5}
+	available := func(asset string) float64 { return balances[asset] }
+
+	assert.NoError(t, CheckFunds(&Order{Symbol: "BTCUSDT", Side: "buy", Amount: 3, OrderType: "limit"}, 100, available))
+	assert.NoError(t, CheckFunds(&Order{Symbol: "BTCUSDT", Side: "buy", Amount: 3, OrderType: "market"}, 0, available))
+
+	err := CheckFunds(&Order{Symbol: "BTCUSDT", Side: "buy", QuoteAmount: 400, OrderType: "market"}, 0, available)
+	var insufficient *InsufficientFundsError
+	require.ErrorAs(t, err, &insufficient)
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+	assert.ErrorIs(t, err, ErrOrderRejected)
+	assert.Equal(t, "USDT", insufficient.Asset)
+	assert.Equal(t, 0.75, insufficient.Affordable())
+
+	err = CheckFunds(&Order{Symbol: "BTCUSDT", Side: "sell", Amount: 2, OrderType: "market"}, 100, available)
+	require.ErrorAs(t, err, &insufficient)
+	assert.Equal(t, "BTC", insufficient.Asset)
+	assert.Equal(t, 0.25, insufficient.Affordable())
+	assert.Equal(t, "insufficient BTC balance: have 0.5, need 2", err.Error())
+}
@@ -2,6 +2,33 @@ package trading
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/money"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	// ErrExchangeUnavailable 交易所无法访问
+	ErrExchangeUnavailable = errors.New("exchange unavailable")
+	// ErrUnauthorized 交易所拒绝了 API 密钥
+	ErrUnauthorized = errors.New("exchange unauthorized")
+	// ErrOrderRejected 交易所拒绝了订单（余额不足、参数不合法等）
+	ErrOrderRejected = errors.New("order rejected")
+	// ErrOrderNotFound 订单不存在
+	ErrOrderNotFound = errors.New("order not found")
+	// ErrInsufficientFunds 可用余额不足以支付订单，同时属于 ErrOrderRejected
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	// ErrBalanceNotFound 账户中没有该资产
+	ErrBalanceNotFound = errors.New("balance not found")
+	// ErrTimestampRejected 请求时间戳超出交易所允许的时间窗口，重新同步时间后可重试
+	ErrTimestampRejected = errors.New("timestamp rejected")
 )
 
 // TradeExecutor defines methods for executing trades
@@ -19,14 +46,174 @@ type TradeExecutor interface {
 	GetBalance(ctx context.Context, symbol string) (float64, error)
 }
 
-// Order 订单结构
+// Order 订单结构。数值字段为 float64 以兼容交易日志、API 和数据库，成交数量、均价和手续费的累加与差值
+// 按十进制计算（internal/money）后再转换回 float64
 type Order struct {
-	Symbol     string  // 交易对
-	Side       string  // buy 或 sell
-	Amount     float64 // 数量
-	Price      float64 // 价格（市价单可为0）
-	OrderType  string  // market 或 limit
-	Status     string  // 订单状态
-	OrderID    string  // 订单ID字符串格式
-	RawOrderID int64   // 订单ID数字格式
+	Symbol         string    `json:"symbol"`          // 交易对
+	Side           string    `json:"side"`            // buy 或 sell
+	Amount         float64   `json:"amount"`          // 数量（基础资产）
+	QuoteAmount    float64   `json:"quote_amount"`    // 计价资产金额，市价单设置后按金额下单，成交后 Amount 为实际成交数量
+	Price          float64   `json:"price"`           // 订单价格：限价单为委托价格，市价单为下单依据的参考价格（可为0）
+	SubmittedPrice float64   `json:"submitted_price"` // 提交给交易所的委托价格，市价单为 0
+	FilledAmount   float64   `json:"filled_amount"`   // 已成交数量，部分成交时小于 Amount
+	FilledPrice    float64   `json:"filled_price"`    // 成交均价，未成交或交易所未返回时为 0
+	OrderType      string    `json:"order_type"`      // market 或 limit
+	Status         string    `json:"status"`          // 订单状态
+	OrderID        string    `json:"order_id"`        // 订单ID字符串格式
+	RawOrderID     int64     `json:"raw_order_id"`    // 订单ID数字格式
+	Account        string    `json:"account"`         // 下单账户
+	ClientOrderID  string    `json:"client_order_id"` // 客户端订单号，未设置时由执行器下单前生成
+	Fee            float64   `json:"fee"`             // 已成交部分的手续费
+	FeeAsset       string    `json:"fee_asset"`       // 手续费资产，交易所按成交扣除，买入通常为基础资产，卖出为计价资产
+	CreatedAt      time.Time `json:"created_at"`      // 交易所接受订单的时间
+	UpdatedAt      time.Time `json:"updated_at"`      // 订单状态或成交最近一次变化的时间
+}
+
+// NewClientOrderID 生成客户端订单号，符合交易所的格式要求（字母、数字和 -，不超过 36 位）
+func NewClientOrderID() string {
+	var b [12]byte
+	_, _ = rand.Read(b[:])
+	return "qf-" + hex.EncodeToString(b[:])
+}
+
+// ExecutedPrice 返回成交均价，没有成交均价时使用委托价格
+func (o Order) ExecutedPrice() float64 {
+	if o.FilledPrice > 0 {
+		return o.FilledPrice
+	}
+	return o.Price
+}
+
+// ExecutedAmount 返回已成交数量，未记录成交数量的已成交订单按委托数量计算
+func (o Order) ExecutedAmount() float64 {
+	if o.FilledAmount > 0 {
+		return o.FilledAmount
+	}
+	if o.Status == "FILLED" {
+		return o.Amount
+	}
+	return 0
+}
+
+// FillSince 返回相对 prev 新增的成交：FilledAmount 为新增数量，FilledPrice 为新增部分的成交均价，
+// Fee 为新增部分的手续费，没有新增成交时返回 false
+func (o Order) FillSince(prev Order) (Order, bool) {
+	amount := money.FromFloat(o.ExecutedAmount()).Sub(money.FromFloat(prev.ExecutedAmount()))
+	if !amount.IsPositive() {
+		return Order{}, false
+	}
+
+	fill := o
+	fill.FilledAmount = money.Float(amount)
+	fill.FilledPrice = money.Float(o.notional().Sub(prev.notional()).Div(amount))
+	if prev.FeeAsset == "" || prev.FeeAsset == o.FeeAsset {
+		fill.Fee = money.Sub(o.Fee, prev.Fee)
+	}
+	return fill, true
+}
+
+// notional 返回已成交部分的十进制成交金额
+func (o Order) notional() decimal.Decimal {
+	return money.FromFloat(o.ExecutedAmount()).Mul(money.FromFloat(o.ExecutedPrice()))
+}
+
+// Value 返回订单金额：设置了计价资产金额时使用该金额，否则为数量乘委托价格
+func (o Order) Value() float64 {
+	if o.QuoteAmount > 0 {
+		return o.QuoteAmount
+	}
+	return o.Amount * o.Price
+}
+
+// UsesQuoteAmount 判断订单是否由交易所按计价资产金额成交（市价单且设置了 QuoteAmount）
+func (o Order) UsesQuoteAmount() bool {
+	return o.OrderType == "market" && o.QuoteAmount > 0
+}
+
+// BaseAmount 返回按 price 成交时的基础资产数量，未设置 Amount 时由计价资产金额换算
+func (o Order) BaseAmount(price float64) float64 {
+	if o.Amount > 0 || o.QuoteAmount <= 0 {
+		return o.Amount
+	}
+	return QuoteToBase(o.QuoteAmount, price)
+}
+
+// QuoteToBase 将计价资产金额按价格换算为基础资产数量，向下取整到 8 位小数
+func QuoteToBase(quoteAmount, price float64) float64 {
+	if price <= 0 {
+		return 0
+	}
+	return math.Floor(quoteAmount/price*1e8) / 1e8
+}
+
+// TimeSyncer is implemented by executors that sign requests with the local clock
+type TimeSyncer interface {
+	// SyncTime fetches the exchange server time, corrects request timestamps and returns the local clock offset
+	SyncTime(ctx context.Context) (time.Duration, error)
+}
+
+// MarketPriceUpdater is implemented by executors that simulate fills from market prices
+type MarketPriceUpdater interface {
+	// UpdateMarketPrice records the latest market price of a symbol
+	UpdateMarketPrice(symbol string, price float64)
+}
+
+// Hedger opens futures positions that offset spot holdings of the same symbol
+type Hedger interface {
+	// OpenHedge opens or adds to a short futures position of amount base asset
+	OpenHedge(ctx context.Context, symbol string, amount float64) (*Order, error)
+
+	// CloseHedge closes the whole short futures position of the symbol, returning nil when there is none
+	CloseHedge(ctx context.Context, symbol string) (*Order, error)
+
+	// HedgeAmount returns the size of the short futures position in base asset
+	HedgeAmount(ctx context.Context, symbol string) (float64, error)
+}
+
+// HedgePosition 对冲合约空单的数量和开仓均价
+type HedgePosition struct {
+	Amount     float64 `json:"amount"`
+	EntryPrice float64 `json:"entry_price"`
+}
+
+// ExecutorState 模拟执行器的内存状态，用于快照和恢复
+type ExecutorState struct {
+	Balances   map[string]float64       `json:"balances"`
+	OpenOrders []Order                  `json:"open_orders"`
+	Hedges     map[string]HedgePosition `json:"hedges,omitempty"`
+}
+
+// StatefulExecutor is implemented by executors that keep balances and orders in memory
+type StatefulExecutor interface {
+	// ExportState returns a copy of the balances and open orders
+	ExportState() ExecutorState
+
+	// RestoreState replaces the balances and open orders
+	RestoreState(state ExecutorState)
+}
+
+// 未终结的订单状态
+var OpenOrderStatuses = []string{"NEW", "PARTIALLY_FILLED"}
+
+// IsOpenStatus 判断订单是否仍在挂单中
+func IsOpenStatus(status string) bool {
+	for _, s := range OpenOrderStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// 常见计价资产，用于从交易对中拆分出基础资产
+var QuoteAssets = []string{"USDT", "BUSD", "USDC", "FDUSD", "BTC", "ETH", "BNB"}
+
+// SplitSymbol 将交易对拆分为基础资产和计价资产
+func SplitSymbol(symbol string) (base string, quote string, ok bool) {
+	for _, q := range QuoteAssets {
+		if strings.HasSuffix(symbol, q) && len(symbol) > len(q) {
+			return strings.TrimSuffix(symbol, q), q, true
+		}
+	}
+	return "", "", false
 }
@@ -0,0 +1,182 @@
+package trading
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/money"
+)
+
+// Quote 盘口最优报价
+type Quote struct {
+	Bid float64 `json:"bid"` // 买一价
+	Ask float64 `json:"ask"` // 卖一价
+}
+
+// Valid 判断买一、卖一价都存在且没有交叉
+func (q Quote) Valid() bool {
+	return q.Bid > 0 && q.Ask > 0 && q.Bid <= q.Ask
+}
+
+// Mid 返回买一卖一的中间价
+func (q Quote) Mid() float64 {
+	return (q.Bid + q.Ask) / 2
+}
+
+// MakerPrice 返回挂单价格：买入从买一、卖出从卖一向盘口内移动 improvement 倍价差，
+// improvement 小于 1 时价格不会越过对手价
+func (q Quote) MakerPrice(side string, improvement float64) float64 {
+	spread := q.Ask - q.Bid
+	if side == "sell" {
+		return q.Ask - spread*improvement
+	}
+	return q.Bid + spread*improvement
+}
+
+// QuoteProvider is implemented by executors that can query the best bid and ask of the order book
+type QuoteProvider interface {
+	// BestQuote returns the best bid and ask price of a symbol
+	BestQuote(ctx context.Context, symbol string) (Quote, error)
+}
+
+// MakerOptions 挂单优先下单的参数
+type MakerOptions struct {
+	Wait         time.Duration // 限价单等待成交的时间，超时后撤单
+	PollInterval time.Duration // 等待期间查询订单状态的间隔，为 0 时只在等待结束时查询
+	Improvement  float64       // 委托价格从同侧最优价向对手价改善的价差比例，0 为挂在买一/卖一，需小于 1
+}
+
+// PlaceMakerOrder 挂单优先下单：按盘口在价差内挂限价单，等待 opts.Wait 后仍未全部成交时撤单，
+// valid 按撤单后的最新盘口判断信号仍然有效时，剩余数量改为市价单成交，减少吃单手续费。
+// 完成后 order 为最后提交的订单，数量、成交均价和手续费合并了限价单和市价单两部分；
+// 限价单下单失败时 order 不变，市价单失败时 order 为已撤销的限价单，同时返回错误
+func PlaceMakerOrder(ctx context.Context, executor TradeExecutor, quotes QuoteProvider, order *Order, opts MakerOptions, valid func(Quote) bool) error {
+	quote, err := quotes.BestQuote(ctx, order.Symbol)
+	if err != nil {
+		return fmt.Errorf("failed to get order book quote: %w", err)
+	}
+	if !quote.Valid() {
+		return fmt.Errorf("%w: invalid order book quote for %s: bid %g, ask %g", ErrExchangeUnavailable, order.Symbol, quote.Bid, quote.Ask)
+	}
+
+	limit := *order
+	limit.OrderType = "limit"
+	limit.Price = quote.MakerPrice(order.Side, opts.Improvement)
+	limit.SubmittedPrice = limit.Price
+	limit.Amount = order.BaseAmount(limit.Price)
+	if err := executor.PlaceOrder(ctx, &limit); err != nil {
+		return err
+	}
+
+	// 撤单和查询最终成交不受调用方取消的影响，避免留下无人管理的挂单
+	cleanup := context.WithoutCancel(ctx)
+	if IsOpenStatus(limit.Status) {
+		waitFill(ctx, executor, &limit, opts)
+	}
+	if IsOpenStatus(limit.Status) {
+		if err := executor.CancelOrder(cleanup, limit.Symbol, limit.OrderID); err != nil && !errors.Is(err, ErrOrderNotFound) {
+			*order = limit
+			return fmt.Errorf("failed to cancel unfilled maker order %s: %w", limit.OrderID, err)
+		}
+		// 撤单前可能有新的成交，以交易所的最终状态为准
+		if err := refreshOrder(cleanup, executor, &limit); err != nil {
+			*order = limit
+			return err
+		}
+	}
+
+	filled := limit.ExecutedAmount()
+	remaining := money.Sub(limit.Amount, filled)
+	if remaining <= 0 || ctx.Err() != nil {
+		*order = limit
+		return nil
+	}
+	quote, err = quotes.BestQuote(ctx, order.Symbol)
+	if err != nil || !quote.Valid() || !valid(quote) {
+		*order = limit
+		return nil
+	}
+
+	market := limit
+	market.OrderType = "market"
+	market.Price = quote.Mid()
+	market.SubmittedPrice = 0
+	market.Amount = remaining
+	market.QuoteAmount = 0
+	market.FilledAmount, market.FilledPrice, market.Fee, market.FeeAsset = 0, 0, 0, ""
+	market.Status, market.OrderID, market.RawOrderID, market.ClientOrderID = "", "", 0, ""
+	if err := executor.PlaceOrder(ctx, &market); err != nil {
+		*order = limit
+		return fmt.Errorf("failed to escalate maker order %s to market: %w", limit.OrderID, err)
+	}
+
+	*order = mergeFills(limit, market)
+	return nil
+}
+
+// waitFill 在 opts.Wait 内按间隔查询订单状态，直到订单终结、等待结束或 ctx 取消
+func waitFill(ctx context.Context, executor TradeExecutor, order *Order, opts MakerOptions) {
+	timer := time.NewTimer(opts.Wait)
+	defer timer.Stop()
+
+	var poll <-chan time.Time
+	if opts.PollInterval > 0 {
+		ticker := time.NewTicker(opts.PollInterval)
+		defer ticker.Stop()
+		poll = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			// 查询失败时按仍在挂单处理，由调用方撤单后再确认
+			_ = refreshOrder(ctx, executor, order)
+			return
+		case <-poll:
+			if err := refreshOrder(ctx, executor, order); err == nil && !IsOpenStatus(order.Status) {
+				return
+			}
+		}
+	}
+}
+
+// refreshOrder 用交易所返回的订单状态和成交更新 order，保留下单时设置的账户、客户端订单号和委托参数
+func refreshOrder(ctx context.Context, executor TradeExecutor, order *Order) error {
+	current, err := executor.GetOrderStatus(ctx, order.Symbol, order.OrderID)
+	if err != nil {
+		return fmt.Errorf("failed to get status of maker order %s: %w", order.OrderID, err)
+	}
+	order.Status = current.Status
+	order.FilledAmount = current.FilledAmount
+	order.FilledPrice = current.FilledPrice
+	if current.Fee > 0 || current.FeeAsset != "" {
+		order.Fee, order.FeeAsset = current.Fee, current.FeeAsset
+	}
+	if !current.UpdatedAt.IsZero() {
+		order.UpdatedAt = current.UpdatedAt
+	}
+	return nil
+}
+
+// mergeFills 合并挂单部分成交的限价单和剩余数量的市价单，返回以市价单为准的订单
+func mergeFills(limit, market Order) Order {
+	merged := market
+	merged.Amount = money.Add(limit.ExecutedAmount(), market.Amount)
+	merged.CreatedAt = limit.CreatedAt
+
+	filled := money.FromFloat(limit.ExecutedAmount()).Add(money.FromFloat(market.ExecutedAmount()))
+	merged.FilledAmount = money.Float(filled)
+	if filled.IsPositive() {
+		merged.FilledPrice = money.Float(limit.notional().Add(market.notional()).Div(filled))
+	}
+	if limit.FeeAsset == "" || limit.FeeAsset == market.FeeAsset {
+		merged.Fee = money.Add(limit.Fee, market.Fee)
+	} else if market.FeeAsset == "" {
+		merged.Fee, merged.FeeAsset = limit.Fee, limit.FeeAsset
+	}
+	return merged
+}
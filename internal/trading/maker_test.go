@@ -0,0 +1,106 @@
+package trading
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeQuotes 返回固定盘口的报价源，按调用顺序依次返回 quotes，最后一个重复使用
+type fakeQuotes struct {
+	quotes []Quote
+}
+
+func (f *fakeQuotes) BestQuote(ctx context.Context, symbol string) (Quote, error) {
+	quote := f.quotes[0]
+	if len(f.quotes) > 1 {
+		f.quotes = f.quotes[1:]
+	}
+	return quote, nil
+}
+
+// fillingExecutor 第一次查询订单状态时按 amount 成交限价单
+type fillingExecutor struct {
+	*fakeExecutor
+	amount float64
+}
+
+func (f *fillingExecutor) GetOrderStatus(ctx context.Context, symbol, orderID string) (*Order, error) {
+	if order := f.orders[orderID]; order != nil && order.OrderType == "limit" && order.FilledAmount == 0 {
+		status := "PARTIALLY_FILLED"
+		if f.amount >= order.Amount {
+			status = "FILLED"
+		}
+		f.fill(orderID, f.amount, status)
+	}
+	return f.fakeExecutor.GetOrderStatus(ctx, symbol, orderID)
+}
+
+func TestQuote_MakerPrice(t *testing.T) {
+	quote := Quote{Bid: 100, Ask: 102}
+	assert.True(t, quote.Valid())
+	assert.Equal(t, 101.0, quote.Mid())
+	assert.Equal(t, 100.0, quote.MakerPrice("buy", 0))
+	assert.Equal(t, 100.5, quote.MakerPrice("buy", 0.25))
+	assert.Equal(t, 102.0, quote.MakerPrice("sell", 0))
+	assert.Equal(t, 101.5, quote.MakerPrice("sell", 0.25))
+	assert.False(t, Quote{Bid: 103, Ask: 102}.Valid())
+}
+
+func TestPlaceMakerOrder(t *testing.T) {
+	ctx := context.Background()
+	opts := MakerOptions{Wait: 20 * time.Millisecond, PollInterval: time.Millisecond, Improvement: 0.5}
+	always := func(Quote) bool { return true }
+
+	t.Run("filled as maker", func(t *testing.T) {
+		executor := &fillingExecutor{fakeExecutor: newFakeExecutor(), amount: 1}
+		order := &Order{Account: "main", Symbol: "BTCUSDT", Side: "buy", Amount: 1, Price: 110, OrderType: "maker"}
+		require.NoError(t, PlaceMakerOrder(ctx, executor, &fakeQuotes{quotes: []Quote{{Bid: 100, Ask: 102}}}, order, opts, always))
+
+		assert.Equal(t, "limit", order.OrderType)
+		assert.Equal(t, 101.0, order.Price)
+		assert.Equal(t, 101.0, order.SubmittedPrice)
+		assert.Equal(t, "FILLED", order.Status)
+		assert.Equal(t, "main", order.Account)
+		assert.Len(t, executor.orders, 1)
+	})
+
+	t.Run("escalated to market", func(t *testing.T) {
+		executor := &fillingExecutor{fakeExecutor: newFakeExecutor(), amount: 0.4}
+		quotes := &fakeQuotes{quotes: []Quote{{Bid: 100, Ask: 102}, {Bid: 104, Ask: 106}}}
+		order := &Order{Symbol: "BTCUSDT", Side: "sell", Amount: 1, OrderType: "maker"}
+		require.NoError(t, PlaceMakerOrder(ctx, executor, quotes, order, opts, always))
+
+		// 限价单撤销，剩余 0.6 按最新中间价的市价单成交
+		assert.Equal(t, "CANCELED", executor.orders["1"].Status)
+		assert.Equal(t, 0.6, executor.orders["2"].Amount)
+		assert.Equal(t, "market", order.OrderType)
+		assert.Equal(t, "2", order.OrderID)
+		assert.Equal(t, 1.0, order.Amount)
+		assert.Equal(t, 1.0, order.FilledAmount)
+		assert.InDelta(t, (0.4*101+0.6*105)/1, order.FilledPrice, 1e-9)
+	})
+
+	t.Run("signal no longer valid", func(t *testing.T) {
+		executor := newFakeExecutor()
+		order := &Order{Symbol: "BTCUSDT", Side: "buy", Amount: 1, OrderType: "maker"}
+		require.NoError(t, PlaceMakerOrder(ctx, executor, &fakeQuotes{quotes: []Quote{{Bid: 100, Ask: 102}}}, order, opts,
+			func(Quote) bool { return false }))
+
+		assert.Equal(t, "limit", order.OrderType)
+		assert.Equal(t, "CANCELED", order.Status)
+		assert.Zero(t, order.FilledAmount)
+		assert.Len(t, executor.orders, 1)
+	})
+
+	t.Run("invalid quote", func(t *testing.T) {
+		executor := newFakeExecutor()
+		order := &Order{Symbol: "BTCUSDT", Side: "buy", Amount: 1, OrderType: "maker"}
+		err := PlaceMakerOrder(ctx, executor, &fakeQuotes{quotes: []Quote{{}}}, order, opts, always)
+		assert.ErrorIs(t, err, ErrExchangeUnavailable)
+		assert.Empty(t, executor.orders)
+	})
+}
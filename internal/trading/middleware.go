@@ -0,0 +1,389 @@
+package trading
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited 账户下单频率超过 RateLimit 的限制，订单没有发送到交易所
+var ErrRateLimited = errors.New("order rate limit exceeded")
+
+// StatusDryRun 试运行的订单状态，订单没有发送到交易所
+const StatusDryRun = "DRY_RUN"
+
+// 执行器操作名称，用于日志和指标
+const (
+	OpPlaceOrder     = "place_order"
+	OpCancelOrder    = "cancel_order"
+	OpGetOrderStatus = "get_order_status"
+	OpGetBalance     = "get_balance"
+)
+
+// Middleware 包装执行器，在交易所调用前后加入日志、指标、限流等通用处理，交易所适配器只负责下单本身
+type Middleware func(next TradeExecutor) TradeExecutor
+
+// Chain 按顺序组合中间件，第一个中间件在最外层，最先处理调用
+func Chain(executor TradeExecutor, middlewares ...Middleware) TradeExecutor {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		executor = middlewares[i](executor)
+	}
+	return executor
+}
+
+// Wrapper is implemented by executors that wrap another executor
+type Wrapper interface {
+	// Unwrap returns the wrapped executor
+	Unwrap() TradeExecutor
+}
+
+// As 沿 Unwrap 链查找实现了 T 的执行器，用于访问被中间件包装的执行器的可选接口，如 TimeSyncer、QuoteProvider。
+// 可选接口的调用不经过中间件
+func As[T any](executor TradeExecutor) (T, bool) {
+	for executor != nil {
+		if t, ok := executor.(T); ok {
+			return t, true
+		}
+		wrapper, ok := executor.(Wrapper)
+		if !ok {
+			break
+		}
+		executor = wrapper.Unwrap()
+	}
+	var zero T
+	return zero, false
+}
+
+// Wrapped 中间件的基础实现，所有调用直接转发给被包装的执行器，中间件嵌入后只需实现要处理的方法
+type Wrapped struct {
+	Next TradeExecutor
+}
+
+func (w Wrapped) PlaceOrder(ctx context.Context, order *Order) error {
+	return w.Next.PlaceOrder(ctx, order)
+}
+
+func (w Wrapped) CancelOrder(ctx context.Context, symbol string, orderID string) error {
+	return w.Next.CancelOrder(ctx, symbol, orderID)
+}
+
+func (w Wrapped) GetOrderStatus(ctx context.Context, symbol, orderID string) (*Order, error) {
+	return w.Next.GetOrderStatus(ctx, symbol, orderID)
+}
+
+func (w Wrapped) GetBalance(ctx context.Context, symbol string) (float64, error) {
+	return w.Next.GetBalance(ctx, symbol)
+}
+
+// Unwrap implements Wrapper
+func (w Wrapped) Unwrap() TradeExecutor {
+	return w.Next
+}
+
+// Logger 执行器中间件的日志
+type Logger interface {
+	Error(msg string, fields ...interface{})
+	Info(msg string, fields ...interface{})
+}
+
+// Logging 记录下单和撤单的结果，查询不记录
+func Logging(logger Logger) Middleware {
+	return func(next TradeExecutor) TradeExecutor {
+		return &loggingExecutor{Wrapped: Wrapped{Next: next}, logger: logger}
+	}
+}
+
+type loggingExecutor struct {
+	Wrapped
+	logger Logger
+}
+
+func (e *loggingExecutor) PlaceOrder(ctx context.Context, order *Order) error {
+	err := e.Next.PlaceOrder(ctx, order)
+	if err != nil {
+		e.logger.Error("order failed", "account", order.Account, "symbol", order.Symbol, "side", order.Side,
+			"type", order.OrderType, "amount", order.Amount, "quote_amount", order.QuoteAmount, "client_order_id", order.ClientOrderID, "err", err)
+		return err
+	}
+	e.logger.Info("order placed", "account", order.Account, "symbol", order.Symbol, "side", order.Side,
+		"type", order.OrderType, "amount", order.Amount, "quote_amount", order.QuoteAmount, "order_id", order.OrderID, "status", order.Status)
+	return nil
+}
+
+func (e *loggingExecutor) CancelOrder(ctx context.Context, symbol string, orderID string) error {
+	err := e.Next.CancelOrder(ctx, symbol, orderID)
+	if err != nil {
+		e.logger.Error("cancel order failed", "symbol", symbol, "order_id", orderID, "err", err)
+		return err
+	}
+	e.logger.Info("order cancelled", "symbol", symbol, "order_id", orderID)
+	return nil
+}
+
+// Observer 接收每次执行器调用的操作、结果和耗时
+type Observer func(op string, err error, elapsed time.Duration)
+
+// Metrics 统计每次执行器调用的结果和耗时
+func Metrics(observe Observer) Middleware {
+	return func(next TradeExecutor) TradeExecutor {
+		return &metricsExecutor{Wrapped: Wrapped{Next: next}, observe: observe}
+	}
+}
+
+type metricsExecutor struct {
+	Wrapped
+	observe Observer
+}
+
+func (e *metricsExecutor) PlaceOrder(ctx context.Context, order *Order) error {
+	start := time.Now()
+	err := e.Next.PlaceOrder(ctx, order)
+	e.observe(OpPlaceOrder, err, time.Since(start))
+	return err
+}
+
+func (e *metricsExecutor) CancelOrder(ctx context.Context, symbol string, orderID string) error {
+	start := time.Now()
+	err := e.Next.CancelOrder(ctx, symbol, orderID)
+	e.observe(OpCancelOrder, err, time.Since(start))
+	return err
+}
+
+func (e *metricsExecutor) GetOrderStatus(ctx context.Context, symbol, orderID string) (*Order, error) {
+	start := time.Now()
+	order, err := e.Next.GetOrderStatus(ctx, symbol, orderID)
+	e.observe(OpGetOrderStatus, err, time.Since(start))
+	return order, err
+}
+
+func (e *metricsExecutor) GetBalance(ctx context.Context, symbol string) (float64, error) {
+	start := time.Now()
+	balance, err := e.Next.GetBalance(ctx, symbol)
+	e.observe(OpGetBalance, err, time.Since(start))
+	return balance, err
+}
+
+// RateLimit 按令牌桶限制下单频率，每分钟最多 perMinute 笔，超出时返回 ErrRateLimited；撤单和查询不受限制
+func RateLimit(perMinute int) Middleware {
+	return func(next TradeExecutor) TradeExecutor {
+		return &rateLimitExecutor{Wrapped: Wrapped{Next: next}, capacity: float64(perMinute), tokens: float64(perMinute), now: time.Now}
+	}
+}
+
+type rateLimitExecutor struct {
+	Wrapped
+	capacity float64
+	now      func() time.Time
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func (e *rateLimitExecutor) PlaceOrder(ctx context.Context, order *Order) error {
+	if !e.allow() {
+		return ErrRateLimited
+	}
+	return e.Next.PlaceOrder(ctx, order)
+}
+
+// allow 按经过的时间补充令牌，有令牌时消耗一个
+func (e *rateLimitExecutor) allow() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := e.now()
+	if !e.last.IsZero() {
+		e.tokens = min(e.capacity, e.tokens+now.Sub(e.last).Minutes()*e.capacity)
+	}
+	e.last = now
+	if e.tokens < 1 {
+		return false
+	}
+	e.tokens--
+	return true
+}
+
+// RiskCheck 在订单发送到交易所前再次检查，check 返回错误时不下单并原样返回错误；只检查买入，卖出平仓不受限制
+func RiskCheck(check func(ctx context.Context, order *Order) error) Middleware {
+	return func(next TradeExecutor) TradeExecutor {
+		return &riskCheckExecutor{Wrapped: Wrapped{Next: next}, check: check}
+	}
+}
+
+type riskCheckExecutor struct {
+	Wrapped
+	check func(ctx context.Context, order *Order) error
+}
+
+func (e *riskCheckExecutor) PlaceOrder(ctx context.Context, order *Order) error {
+	if order.Side == "buy" {
+		if err := e.check(ctx, order); err != nil {
+			return err
+		}
+	}
+	return e.Next.PlaceOrder(ctx, order)
+}
+
+// Guard 在买入订单发送到交易所前占用额度，reserve 返回错误时不下单并原样返回错误；
+// 下单失败时调用 reserve 返回的 release 归还额度。卖出平仓不受限制
+func Guard(reserve func(ctx context.Context, order *Order) (release func(), err error)) Middleware {
+	return func(next TradeExecutor) TradeExecutor {
+		return &guardExecutor{Wrapped: Wrapped{Next: next}, reserve: reserve}
+	}
+}
+
+type guardExecutor struct {
+	Wrapped
+	reserve func(ctx context.Context, order *Order) (func(), error)
+}
+
+func (e *guardExecutor) PlaceOrder(ctx context.Context, order *Order) error {
+	if order.Side != "buy" {
+		return e.Next.PlaceOrder(ctx, order)
+	}
+	release, err := e.reserve(ctx, order)
+	if err != nil {
+		return err
+	}
+	if err := e.Next.PlaceOrder(ctx, order); err != nil {
+		release()
+		return err
+	}
+	return nil
+}
+
+// 试运行订单号的前缀
+const dryRunPrefix = "dry-run-"
+
+// DryRun 不向交易所下单和撤单，订单状态为 StatusDryRun；余额和其他订单的查询照常转发。
+// 试运行执行器同时实现 OCOExecutor，通过 As 查找时不会越过它直接向交易所挂出 OCO 订单
+func DryRun() Middleware {
+	return func(next TradeExecutor) TradeExecutor {
+		return &dryRunExecutor{Wrapped: Wrapped{Next: next}, orders: make(map[string]Order)}
+	}
+}
+
+type dryRunExecutor struct {
+	Wrapped
+
+	mu     sync.Mutex
+	orders map[string]Order
+}
+
+func (e *dryRunExecutor) PlaceOrder(_ context.Context, order *Order) error {
+	e.record(order)
+	return nil
+}
+
+// record 为订单生成试运行订单号并保存
+func (e *dryRunExecutor) record(order *Order) {
+	if order.ClientOrderID == "" {
+		order.ClientOrderID = NewClientOrderID()
+	}
+	order.OrderID = dryRunPrefix + order.ClientOrderID
+	order.Status = StatusDryRun
+	order.CreatedAt = time.Now()
+	order.UpdatedAt = order.CreatedAt
+
+	e.mu.Lock()
+	e.orders[order.OrderID] = *order
+	e.mu.Unlock()
+}
+
+// PlaceOCO implements OCOExecutor
+func (e *dryRunExecutor) PlaceOCO(_ context.Context, oco *OCOOrder) error {
+	oco.ProfitOrder = Order{Symbol: oco.Symbol, Side: oco.Side, OrderType: "limit", Amount: oco.Amount, Price: oco.TakeProfit}
+	oco.StopOrder = Order{Symbol: oco.Symbol, Side: oco.Side, OrderType: "market", Amount: oco.Amount, Price: oco.StopLoss}
+	e.record(&oco.ProfitOrder)
+	e.record(&oco.StopOrder)
+	oco.ListID = dryRunPrefix + NewClientOrderID()
+	return nil
+}
+
+// CancelOCO implements OCOExecutor
+func (e *dryRunExecutor) CancelOCO(ctx context.Context, symbol, listID string) error {
+	if strings.HasPrefix(listID, dryRunPrefix) {
+		return nil
+	}
+	oco, ok := As[OCOExecutor](e.Next)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrOrderNotFound, listID)
+	}
+	return oco.CancelOCO(ctx, symbol, listID)
+}
+
+func (e *dryRunExecutor) CancelOrder(ctx context.Context, symbol string, orderID string) error {
+	if strings.HasPrefix(orderID, dryRunPrefix) {
+		return nil
+	}
+	return e.Next.CancelOrder(ctx, symbol, orderID)
+}
+
+func (e *dryRunExecutor) GetOrderStatus(ctx context.Context, symbol, orderID string) (*Order, error) {
+	if !strings.HasPrefix(orderID, dryRunPrefix) {
+		return e.Next.GetOrderStatus(ctx, symbol, orderID)
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	order, ok := e.orders[orderID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrOrderNotFound, orderID)
+	}
+	return &order, nil
+}
+
+// Idempotency 按客户端订单号去重：window 内同一客户端订单号再次下单时不发送到交易所，返回第一次下单的结果。
+// 未设置客户端订单号的订单在下单前生成
+func Idempotency(window time.Duration) Middleware {
+	return func(next TradeExecutor) TradeExecutor {
+		return &idempotencyExecutor{Wrapped: Wrapped{Next: next}, window: window, now: time.Now, placed: make(map[string]placedOrder)}
+	}
+}
+
+// placedOrder 已下单的订单和下单时间
+type placedOrder struct {
+	order Order
+	at    time.Time
+}
+
+type idempotencyExecutor struct {
+	Wrapped
+	window time.Duration
+	now    func() time.Time
+
+	mu     sync.Mutex
+	placed map[string]placedOrder
+}
+
+func (e *idempotencyExecutor) PlaceOrder(ctx context.Context, order *Order) error {
+	if order.ClientOrderID == "" {
+		order.ClientOrderID = NewClientOrderID()
+	}
+
+	now := e.now()
+	e.mu.Lock()
+	for id, placed := range e.placed {
+		if now.Sub(placed.at) > e.window {
+			delete(e.placed, id)
+		}
+	}
+	if placed, ok := e.placed[order.ClientOrderID]; ok {
+		e.mu.Unlock()
+		*order = placed.order
+		return nil
+	}
+	e.mu.Unlock()
+
+	if err := e.Next.PlaceOrder(ctx, order); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	e.placed[order.ClientOrderID] = placedOrder{order: *order, at: now}
+	e.mu.Unlock()
+	return nil
+}
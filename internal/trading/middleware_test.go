@@ -0,0 +1,161 @@
+package trading
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingLogger 记录日志消息
+type recordingLogger struct {
+	messages []string
+}
+
+func (l *recordingLogger) Error(msg string, fields ...interface{}) {
+	l.messages = append(l.messages, msg)
+}
+func (l *recordingLogger) Info(msg string, fields ...interface{}) {
+	l.messages = append(l.messages, msg)
+}
+
+func TestChain(t *testing.T) {
+	ctx := context.Background()
+	inner := &fakeOCOExecutor{fakeExecutor: newFakeExecutor()}
+	logger := &recordingLogger{}
+	var ops []string
+	executor := Chain(inner,
+		Metrics(func(op string, err error, elapsed time.Duration) { ops = append(ops, op) }),
+		Logging(logger),
+	)
+
+	require.NoError(t, executor.PlaceOrder(ctx, &Order{Symbol: "BTCUSDT", Side: "buy", OrderType: "market", Amount: 1, Price: 100}))
+	assert.Error(t, executor.CancelOrder(ctx, "BTCUSDT", "missing"))
+	_, err := executor.GetBalance(ctx, "USDT")
+	require.NoError(t, err)
+	assert.Equal(t, []string{OpPlaceOrder, OpCancelOrder, OpGetBalance}, ops)
+	assert.Equal(t, []string{"order placed", "cancel order failed"}, logger.messages)
+
+	// 可选接口沿 Unwrap 链查找
+	oco, ok := As[OCOExecutor](executor)
+	require.True(t, ok)
+	assert.Same(t, inner, oco)
+	_, ok = As[TimeSyncer](executor)
+	assert.False(t, ok)
+}
+
+func TestRateLimit(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	executor := RateLimit(2)(newFakeExecutor()).(*rateLimitExecutor)
+	executor.now = func() time.Time { return now }
+
+	require.NoError(t, executor.PlaceOrder(ctx, &Order{Symbol: "BTCUSDT", OrderType: "market"}))
+	require.NoError(t, executor.PlaceOrder(ctx, &Order{Symbol: "BTCUSDT", OrderType: "market"}))
+	assert.ErrorIs(t, executor.PlaceOrder(ctx, &Order{Symbol: "BTCUSDT", OrderType: "market"}), ErrRateLimited)
+
+	// 每分钟补充 2 个令牌
+	now = now.Add(30 * time.Second)
+	require.NoError(t, executor.PlaceOrder(ctx, &Order{Symbol: "BTCUSDT", OrderType: "market"}))
+	assert.ErrorIs(t, executor.PlaceOrder(ctx, &Order{Symbol: "BTCUSDT", OrderType: "market"}), ErrRateLimited)
+}
+
+func TestRiskCheck(t *testing.T) {
+	ctx := context.Background()
+	rejected := errors.New("position limit")
+	inner := newFakeExecutor()
+	executor := RiskCheck(func(ctx context.Context, order *Order) error { return rejected })(inner)
+
+	assert.ErrorIs(t, executor.PlaceOrder(ctx, &Order{Symbol: "BTCUSDT", Side: "buy", OrderType: "market"}), rejected)
+	assert.Empty(t, inner.orders)
+
+	// 卖出不检查
+	require.NoError(t, executor.PlaceOrder(ctx, &Order{Symbol: "BTCUSDT", Side: "sell", OrderType: "market"}))
+	assert.Len(t, inner.orders, 1)
+}
+
+func TestGuard(t *testing.T) {
+	ctx := context.Background()
+	limited := errors.New("spend limit")
+	inner := newFakeExecutor()
+	var reserved, released int
+	executor := Guard(func(ctx context.Context, order *Order) (func(), error) {
+		if order.Amount > 1 {
+			return nil, limited
+		}
+		reserved++
+		return func() { released++ }, nil
+	})(inner)
+
+	require.NoError(t, executor.PlaceOrder(ctx, &Order{Symbol: "BTCUSDT", Side: "buy", OrderType: "market", Amount: 1}))
+	assert.ErrorIs(t, executor.PlaceOrder(ctx, &Order{Symbol: "BTCUSDT", Side: "buy", OrderType: "market", Amount: 2}), limited)
+	assert.Len(t, inner.orders, 1)
+
+	// 交易所拒绝的订单归还额度
+	inner.reject = func(order *Order) bool { return true }
+	assert.ErrorIs(t, executor.PlaceOrder(ctx, &Order{Symbol: "BTCUSDT", Side: "buy", OrderType: "market", Amount: 1}), ErrOrderRejected)
+	assert.Equal(t, 2, reserved)
+	assert.Equal(t, 1, released)
+
+	// 卖出不占用额度
+	inner.reject = nil
+	require.NoError(t, executor.PlaceOrder(ctx, &Order{Symbol: "BTCUSDT", Side: "sell", OrderType: "market", Amount: 5}))
+	assert.Equal(t, 2, reserved)
+}
+
+func TestDryRun(t *testing.T) {
+	ctx := context.Background()
+	inner := &fakeOCOExecutor{fakeExecutor: newFakeExecutor()}
+	executor := Chain(inner, DryRun())
+
+	order := &Order{Symbol: "BTCUSDT", Side: "buy", OrderType: "limit", Amount: 1, Price: 100}
+	require.NoError(t, executor.PlaceOrder(ctx, order))
+	assert.Empty(t, inner.orders)
+	assert.Equal(t, StatusDryRun, order.Status)
+	assert.NotEmpty(t, order.ClientOrderID)
+
+	status, err := executor.GetOrderStatus(ctx, "BTCUSDT", order.OrderID)
+	require.NoError(t, err)
+	assert.Equal(t, *order, *status)
+	require.NoError(t, executor.CancelOrder(ctx, "BTCUSDT", order.OrderID))
+
+	// OCO 订单同样不发送到交易所
+	oco, ok := As[OCOExecutor](executor)
+	require.True(t, ok)
+	list := &OCOOrder{Symbol: "BTCUSDT", Side: "sell", Amount: 1, TakeProfit: 110, StopLoss: 90}
+	require.NoError(t, oco.PlaceOCO(ctx, list))
+	assert.Empty(t, inner.orders)
+	assert.Empty(t, inner.canceled)
+	assert.Equal(t, StatusDryRun, list.StopOrder.Status)
+	require.NoError(t, oco.CancelOCO(ctx, "BTCUSDT", list.ListID))
+	assert.Empty(t, inner.canceled)
+}
+
+func TestIdempotency(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	inner := newFakeExecutor()
+	executor := Idempotency(time.Minute)(inner).(*idempotencyExecutor)
+	executor.now = func() time.Time { return now }
+
+	first := &Order{Symbol: "BTCUSDT", Side: "buy", OrderType: "market", Amount: 1, ClientOrderID: "retry-1"}
+	require.NoError(t, executor.PlaceOrder(ctx, first))
+
+	// 窗口内重试返回第一次下单的结果
+	retry := &Order{Symbol: "BTCUSDT", Side: "buy", OrderType: "market", Amount: 1, ClientOrderID: "retry-1"}
+	require.NoError(t, executor.PlaceOrder(ctx, retry))
+	assert.Equal(t, first.OrderID, retry.OrderID)
+	assert.Len(t, inner.orders, 1)
+
+	// 未设置客户端订单号的订单不去重
+	require.NoError(t, executor.PlaceOrder(ctx, &Order{Symbol: "BTCUSDT", OrderType: "market"}))
+	assert.Len(t, inner.orders, 2)
+
+	// 窗口过后重新下单
+	now = now.Add(2 * time.Minute)
+	require.NoError(t, executor.PlaceOrder(ctx, &Order{Symbol: "BTCUSDT", OrderType: "market", ClientOrderID: "retry-1"}))
+	assert.Len(t, inner.orders, 3)
+}
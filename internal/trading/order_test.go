@@ -0,0 +1,52 @@
+package trading
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrder_ExecutedAmount(t *testing.T) {
+	tests := []struct {
+		name  string
+		order Order
+		want  float64
+	}{
+		{name: "filled without quantity", order: Order{Amount: 2, Status: "FILLED"}, want: 2},
+		{name: "partially filled", order: Order{Amount: 2, FilledAmount: 0.5, Status: "PARTIALLY_FILLED"}, want: 0.5},
+		{name: "canceled after partial fill", order: Order{Amount: 2, FilledAmount: 1, Status: "CANCELED"}, want: 1},
+		{name: "new", order: Order{Amount: 2, Status: "NEW"}, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.order.ExecutedAmount())
+		})
+	}
+}
+
+func TestOrder_FillSince(t *testing.T) {
+	prev := Order{Amount: 2, Price: 100, FilledAmount: 0.5, FilledPrice: 98, Status: "PARTIALLY_FILLED"}
+	curr := Order{Amount: 2, Price: 100, FilledAmount: 2, FilledPrice: 99.5, Status: "FILLED"}
+
+	fill, ok := curr.FillSince(prev)
+	assert.True(t, ok)
+	assert.InDelta(t, 1.5, fill.FilledAmount, 1e-9)
+	assert.InDelta(t, (2*99.5-0.5*98)/1.5, fill.FilledPrice, 1e-9)
+
+	_, ok = curr.FillSince(curr)
+	assert.False(t, ok)
+
+	// 新增数量和均价按十进制计算，0.3 - 0.1 为 0.2 而不是 0.19999999999999998
+	prev = Order{Amount: 0.3, FilledAmount: 0.1, FilledPrice: 100.1, Status: "PARTIALLY_FILLED"}
+	curr = Order{Amount: 0.3, FilledAmount: 0.3, FilledPrice: 100.3, Status: "FILLED"}
+	fill, ok = curr.FillSince(prev)
+	assert.True(t, ok)
+	assert.Equal(t, 0.2, fill.FilledAmount)
+	assert.Equal(t, 100.4, fill.FilledPrice)
+}
+
+func TestOrder_BaseAmount(t *testing.T) {
+	assert.Equal(t, 1.5, Order{Amount: 1.5, QuoteAmount: 100}.BaseAmount(10))
+	assert.Equal(t, 0.00333333, Order{QuoteAmount: 100}.BaseAmount(30000))
+	assert.Zero(t, Order{QuoteAmount: 100}.BaseAmount(0))
+}
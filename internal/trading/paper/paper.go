@@ -0,0 +1,468 @@
+package paper
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/clock"
+	"github.com/songzhibin97/quantaflux/internal/money"
+	"github.com/songzhibin97/quantaflux/internal/precision"
+	"github.com/songzhibin97/quantaflux/internal/trading"
+
+	"github.com/shopspring/decimal"
+)
+
+// PaperExecutor implements TradeExecutor interface with simulated fills
+type PaperExecutor struct {
+	mu         sync.RWMutex
+	balances   map[string]decimal.Decimal // 按十进制记账，避免多次成交累积浮点误差
+	orders     map[string]*trading.Order
+	lastPrices map[string]float64
+	hedges     map[string]trading.HedgePosition // 模拟的对冲合约空单，不占用保证金，平仓时盈亏计入计价资产
+	nextID     int64
+	idPrefix   string // 每次创建时不同，订单号在多次运行之间不重复
+	sim        Simulation
+	rng        *rand.Rand
+	clock      clock.Clock // 订单时间戳使用的时钟，回测时为模拟时钟
+}
+
+// Simulation 模拟成交的执行条件，零值表示立即下单、全部成交、不随机拒单
+type Simulation struct {
+	MinLatency      time.Duration // 下单延迟下限
+	MaxLatency      time.Duration // 下单延迟上限，延迟在上下限之间均匀分布，期间行情变化会影响市价单的成交价
+	RejectRate      float64       // 随机拒单的概率
+	PartialFillRate float64       // 部分成交的概率：立即成交的订单只成交一部分，其余部分过期；挂单每次被穿价只成交剩余数量的一部分
+	MinFillRatio    float64       // 部分成交时成交比例的下限
+	Seed            int64         // 随机数种子，为 0 时每次运行不同
+	MakerFeeRate    float64       // 挂单成交的手续费率，和交易所一样从买入得到的资产中扣除
+	TakerFeeRate    float64       // 立即成交的手续费率
+}
+
+// NewPaperExecutor creates a new PaperExecutor instance with initial balances
+func NewPaperExecutor(initialBalances map[string]float64) *PaperExecutor {
+	balances := make(map[string]decimal.Decimal, len(initialBalances))
+	for asset, amount := range initialBalances {
+		balances[asset] = money.FromFloat(amount)
+	}
+
+	return &PaperExecutor{
+		balances:   balances,
+		orders:     make(map[string]*trading.Order),
+		lastPrices: make(map[string]float64),
+		hedges:     make(map[string]trading.HedgePosition),
+		idPrefix:   "paper-" + strconv.FormatInt(time.Now().UnixNano(), 36),
+		clock:      clock.Real(),
+	}
+}
+
+// SetClock 设置订单创建和更新时间使用的时钟，默认为系统时钟；模拟下单延迟仍按实际时间等待
+func (p *PaperExecutor) SetClock(c clock.Clock) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clock = clock.OrReal(c)
+}
+
+// SetSimulation 设置模拟成交的延迟、部分成交和拒单条件
+func (p *PaperExecutor) SetSimulation(sim Simulation) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	seed := sim.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	p.sim = sim
+	p.rng = rand.New(rand.NewSource(seed))
+}
+
+// roll 以概率 rate 返回 true，调用方需持有锁
+func (p *PaperExecutor) roll(rate float64) bool {
+	return rate > 0 && p.rng != nil && p.rng.Float64() < rate
+}
+
+// fillRatio 返回一次部分成交的成交比例，调用方需持有锁
+func (p *PaperExecutor) fillRatio() float64 {
+	return p.sim.MinFillRatio + p.rng.Float64()*(1-p.sim.MinFillRatio)
+}
+
+// latency 返回本次下单的模拟延迟
+func (p *PaperExecutor) latency() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delay := p.sim.MinLatency
+	if spread := p.sim.MaxLatency - p.sim.MinLatency; spread > 0 && p.rng != nil {
+		delay += time.Duration(p.rng.Int63n(int64(spread) + 1))
+	}
+	return delay
+}
+
+// UpdateMarketPrice records the latest market price, used to fill market orders and resting limit orders
+func (p *PaperExecutor) UpdateMarketPrice(symbol string, price float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.lastPrices[symbol] = price
+
+	// 价格穿过委托价的挂单按委托价成交，资金已在下单时冻结
+	for _, order := range p.restingOrders(symbol) {
+		if !crosses(order.Side, order.Price, price) {
+			continue
+		}
+		amount := money.Sub(order.Amount, order.FilledAmount)
+		partial := p.roll(p.sim.PartialFillRate)
+		if partial {
+			amount *= p.fillRatio()
+		}
+
+		base, quote, _ := trading.SplitSymbol(order.Symbol)
+		p.settle(order, base, quote, amount, order.Price, p.sim.MakerFeeRate)
+		order.UpdatedAt = p.clock.Now()
+		if partial {
+			order.FilledAmount = money.Add(order.FilledAmount, amount)
+			order.FilledPrice = order.Price
+			order.Status = "PARTIALLY_FILLED"
+		} else {
+			fill(order, order.Price)
+		}
+	}
+}
+
+// restingOrders 返回交易对未完成的挂单，按订单号排序，使相同种子下的部分成交结果可以复现。调用方需持有锁
+func (p *PaperExecutor) restingOrders(symbol string) []*trading.Order {
+	var result []*trading.Order
+	for _, order := range p.orders {
+		if order.Symbol == symbol && trading.IsOpenStatus(order.Status) {
+			result = append(result, order)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].RawOrderID != result[j].RawOrderID {
+			return result[i].RawOrderID < result[j].RawOrderID
+		}
+		return result[i].OrderID < result[j].OrderID
+	})
+	return result
+}
+
+// crosses 判断市场价格是否达到限价单的成交条件
+func crosses(side string, limit, market float64) bool {
+	if side == "buy" {
+		return market <= limit
+	}
+	return market >= limit
+}
+
+// settle 将成交的 amount 计入得到的资产（买入为基础资产，卖出为计价资产），按 rate 从中扣除手续费，调用方需持有锁
+func (p *PaperExecutor) settle(order *trading.Order, base, quote string, amount, price, rate float64) {
+	asset, received := base, money.FromFloat(amount)
+	if order.Side == "sell" {
+		asset, received = quote, received.Mul(money.FromFloat(price))
+	}
+	fee := received.Mul(money.FromFloat(rate))
+	p.balances[asset] = p.balances[asset].Add(received.Sub(fee))
+	if fee.IsPositive() {
+		order.Fee = money.Float(money.FromFloat(order.Fee).Add(fee))
+		order.FeeAsset = asset
+	}
+}
+
+// fill 将订单标记为按 price 全部成交
+func fill(order *trading.Order, price float64) {
+	order.FilledAmount = order.Amount
+	order.FilledPrice = price
+	order.Status = "FILLED"
+}
+
+// PlaceOrder implements order placement: market orders and marketable limit orders fill immediately
+// at the last price, other limit orders rest with their funds reserved until the market crosses
+func (p *PaperExecutor) PlaceOrder(ctx context.Context, order *trading.Order) error {
+	// 模拟下单到交易所撮合之间的延迟，不持有锁，期间的行情更新照常生效
+	if delay := p.latency(); delay > 0 {
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if order.OrderType != "market" && order.OrderType != "limit" {
+		return fmt.Errorf("%w: unsupported order type: %s", trading.ErrOrderRejected, order.OrderType)
+	}
+	if p.roll(p.sim.RejectRate) {
+		return fmt.Errorf("%w: simulated rejection", trading.ErrOrderRejected)
+	}
+
+	base, quote, ok := trading.SplitSymbol(order.Symbol)
+	if !ok {
+		return fmt.Errorf("%w: unable to determine quote asset for symbol: %s", trading.ErrOrderRejected, order.Symbol)
+	}
+
+	lastPrice, hasPrice := p.lastPrices[order.Symbol]
+	resting := order.OrderType == "limit" && order.Price > 0 && !(hasPrice && crosses(order.Side, order.Price, lastPrice))
+
+	price := order.Price
+	if !resting {
+		if !hasPrice {
+			return fmt.Errorf("%w: no market price available for symbol: %s", trading.ErrOrderRejected, order.Symbol)
+		}
+		price = lastPrice
+	}
+
+	// 按计价资产金额下的市价单以成交价换算数量，和交易所的 quoteOrderQty 一致；限价单按委托价换算
+	amount := order.BaseAmount(price)
+	if order.UsesQuoteAmount() {
+		amount = trading.QuoteToBase(order.QuoteAmount, price)
+	} else if order.OrderType == "limit" && order.Price > 0 {
+		amount = order.BaseAmount(order.Price)
+	}
+	if amount <= 0 {
+		return fmt.Errorf("%w: invalid amount: %s", trading.ErrOrderRejected, precision.String(amount))
+	}
+	order.Amount = amount
+
+	// 立即成交的订单部分成交时，只有成交部分占用资金，其余部分过期
+	filled := order.Amount
+	if !resting && p.roll(p.sim.PartialFillRate) {
+		filled *= p.fillRatio()
+	}
+
+	cost := money.FromFloat(order.Amount).Mul(money.FromFloat(price))
+	switch order.Side {
+	case "buy":
+		if p.balances[quote].LessThan(cost) {
+			return &trading.InsufficientFundsError{Asset: quote, Available: money.Float(p.balances[quote]), Required: money.Float(cost)}
+		}
+		if resting {
+			p.balances[quote] = p.balances[quote].Sub(cost)
+		} else {
+			p.balances[quote] = p.balances[quote].Sub(money.FromFloat(filled).Mul(money.FromFloat(price)))
+			p.settle(order, base, quote, filled, price, p.sim.TakerFeeRate)
+		}
+	case "sell":
+		if p.balances[base].LessThan(money.FromFloat(order.Amount)) {
+			return &trading.InsufficientFundsError{Asset: base, Available: money.Float(p.balances[base]), Required: order.Amount}
+		}
+		if resting {
+			p.balances[base] = p.balances[base].Sub(money.FromFloat(order.Amount))
+		} else {
+			p.balances[base] = p.balances[base].Sub(money.FromFloat(filled))
+			p.settle(order, base, quote, filled, price, p.sim.TakerFeeRate)
+		}
+	default:
+		return fmt.Errorf("%w: invalid side: %s", trading.ErrOrderRejected, order.Side)
+	}
+
+	p.nextID++
+	switch {
+	case resting:
+		order.Status = "NEW"
+	case filled < order.Amount:
+		order.FilledAmount = filled
+		order.FilledPrice = price
+		order.Status = "EXPIRED"
+	default:
+		fill(order, price)
+	}
+	order.RawOrderID = p.nextID
+	order.OrderID = p.idPrefix + "-" + strconv.FormatInt(p.nextID, 10)
+	if order.ClientOrderID == "" {
+		order.ClientOrderID = trading.NewClientOrderID()
+	}
+	order.CreatedAt = p.clock.Now()
+	order.UpdatedAt = order.CreatedAt
+
+	stored := *order
+	p.orders[order.OrderID] = &stored
+	return nil
+}
+
+// OpenHedge implements trading.Hedger，按最新价格开合约空单
+func (p *PaperExecutor) OpenHedge(ctx context.Context, symbol string, amount float64) (*trading.Order, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	price, ok := p.lastPrices[symbol]
+	if !ok {
+		return nil, fmt.Errorf("%w: no market price available for symbol: %s", trading.ErrOrderRejected, symbol)
+	}
+	if amount <= 0 {
+		return nil, fmt.Errorf("%w: invalid amount: %s", trading.ErrOrderRejected, precision.String(amount))
+	}
+
+	hedge := p.hedges[symbol]
+	hedge.EntryPrice = (hedge.Amount*hedge.EntryPrice + amount*price) / (hedge.Amount + amount)
+	hedge.Amount += amount
+	p.hedges[symbol] = hedge
+	return p.hedgeOrder(symbol, "sell", amount, price), nil
+}
+
+// CloseHedge implements trading.Hedger，按最新价格平掉合约空单，盈亏计入计价资产
+func (p *PaperExecutor) CloseHedge(ctx context.Context, symbol string) (*trading.Order, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	hedge, ok := p.hedges[symbol]
+	if !ok {
+		return nil, nil
+	}
+	price, ok := p.lastPrices[symbol]
+	if !ok {
+		return nil, fmt.Errorf("%w: no market price available for symbol: %s", trading.ErrOrderRejected, symbol)
+	}
+	_, quote, ok := trading.SplitSymbol(symbol)
+	if !ok {
+		return nil, fmt.Errorf("%w: unable to determine quote asset for symbol: %s", trading.ErrOrderRejected, symbol)
+	}
+
+	pnl := money.FromFloat(hedge.Amount).Mul(money.FromFloat(hedge.EntryPrice).Sub(money.FromFloat(price)))
+	p.balances[quote] = p.balances[quote].Add(pnl)
+	delete(p.hedges, symbol)
+	return p.hedgeOrder(symbol, "buy", hedge.Amount, price), nil
+}
+
+// HedgeAmount implements trading.Hedger
+func (p *PaperExecutor) HedgeAmount(ctx context.Context, symbol string) (float64, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.hedges[symbol].Amount, nil
+}
+
+// hedgeOrder 返回已成交的对冲合约订单，调用方需持有锁
+func (p *PaperExecutor) hedgeOrder(symbol, side string, amount, price float64) *trading.Order {
+	p.nextID++
+	order := &trading.Order{
+		Symbol:    symbol,
+		Side:      side,
+		Amount:    amount,
+		Price:     price,
+		OrderType: "market",
+	}
+	fill(order, price)
+	order.RawOrderID = p.nextID
+	order.OrderID = p.idPrefix + "-hedge-" + strconv.FormatInt(p.nextID, 10)
+	order.ClientOrderID = trading.NewClientOrderID()
+	order.CreatedAt = p.clock.Now()
+	order.UpdatedAt = order.CreatedAt
+	return order
+}
+
+// BestQuote implements trading.QuoteProvider，模拟盘口没有价差，买一卖一价都为最新价格
+func (p *PaperExecutor) BestQuote(ctx context.Context, symbol string) (trading.Quote, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	price, ok := p.lastPrices[symbol]
+	if !ok {
+		return trading.Quote{}, fmt.Errorf("%w: no market price available for symbol: %s", trading.ErrExchangeUnavailable, symbol)
+	}
+	return trading.Quote{Bid: price, Ask: price}, nil
+}
+
+// CancelOrder implements order cancellation, releasing the funds reserved by the unfilled part of a resting limit order
+func (p *PaperExecutor) CancelOrder(ctx context.Context, symbol string, orderID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	order, ok := p.orders[orderID]
+	if !ok || order.Symbol != symbol {
+		return fmt.Errorf("%w: %s", trading.ErrOrderNotFound, orderID)
+	}
+
+	if !trading.IsOpenStatus(order.Status) {
+		return fmt.Errorf("order already %s: %s", strings.ToLower(order.Status), orderID)
+	}
+
+	// 部分成交的挂单只释放未成交部分冻结的资金
+	remaining := money.FromFloat(order.Amount).Sub(money.FromFloat(order.FilledAmount))
+	base, quote, _ := trading.SplitSymbol(order.Symbol)
+	if order.Side == "buy" {
+		p.balances[quote] = p.balances[quote].Add(remaining.Mul(money.FromFloat(order.Price)))
+	} else {
+		p.balances[base] = p.balances[base].Add(remaining)
+	}
+	order.Status = "CANCELED"
+	order.UpdatedAt = p.clock.Now()
+	return nil
+}
+
+// GetOrderStatus implements order status retrieval
+func (p *PaperExecutor) GetOrderStatus(ctx context.Context, symbol, orderID string) (*trading.Order, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	order, ok := p.orders[orderID]
+	if !ok || order.Symbol != symbol {
+		return nil, fmt.Errorf("%w: %s", trading.ErrOrderNotFound, orderID)
+	}
+
+	result := *order
+	return &result, nil
+}
+
+// GetBalance implements balance retrieval
+func (p *PaperExecutor) GetBalance(ctx context.Context, symbol string) (float64, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	balance, ok := p.balances[symbol]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", trading.ErrBalanceNotFound, symbol)
+	}
+	return money.Float(balance), nil
+}
+
+// ExportState implements trading.StatefulExecutor
+func (p *PaperExecutor) ExportState() trading.ExecutorState {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	state := trading.ExecutorState{Balances: make(map[string]float64, len(p.balances))}
+	for asset, amount := range p.balances {
+		state.Balances[asset] = money.Float(amount)
+	}
+	for _, order := range p.orders {
+		if trading.IsOpenStatus(order.Status) {
+			state.OpenOrders = append(state.OpenOrders, *order)
+		}
+	}
+	if len(p.hedges) > 0 {
+		state.Hedges = make(map[string]trading.HedgePosition, len(p.hedges))
+		for symbol, hedge := range p.hedges {
+			state.Hedges[symbol] = hedge
+		}
+	}
+	return state
+}
+
+// RestoreState implements trading.StatefulExecutor; 挂单保留原订单号，已冻结的资金不重复扣减
+func (p *PaperExecutor) RestoreState(state trading.ExecutorState) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.balances = make(map[string]decimal.Decimal, len(state.Balances))
+	for asset, amount := range state.Balances {
+		p.balances[asset] = money.FromFloat(amount)
+	}
+	p.orders = make(map[string]*trading.Order, len(state.OpenOrders))
+	for _, order := range state.OpenOrders {
+		stored := order
+		p.orders[order.OrderID] = &stored
+	}
+	p.hedges = make(map[string]trading.HedgePosition, len(state.Hedges))
+	for symbol, hedge := range state.Hedges {
+		p.hedges[symbol] = hedge
+	}
+}
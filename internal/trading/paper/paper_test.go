@@ -0,0 +1,427 @@
+package paper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/trading"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaperExecutor_PlaceOrder(t *testing.T) {
+	ctx := context.Background()
+	executor := NewPaperExecutor(map[string]float64{"USDT": 1000})
+	executor.UpdateMarketPrice("BTCUSDT", 50000)
+
+	t.Run("limit buy", func(t *testing.T) {
+		order := &trading.Order{
+			Symbol:    "BTCUSDT",
+			Side:      "buy",
+			Amount:    0.01,
+			Price:     50000,
+			OrderType: "limit",
+		}
+		require.NoError(t, executor.PlaceOrder(ctx, order))
+		assert.Equal(t, "FILLED", order.Status)
+		assert.NotEmpty(t, order.OrderID)
+
+		usdt, err := executor.GetBalance(ctx, "USDT")
+		require.NoError(t, err)
+		assert.InDelta(t, 500, usdt, 1e-9)
+
+		btc, err := executor.GetBalance(ctx, "BTC")
+		require.NoError(t, err)
+		assert.InDelta(t, 0.01, btc, 1e-9)
+	})
+
+	t.Run("market order without price", func(t *testing.T) {
+		order := &trading.Order{
+			Symbol:    "ETHUSDT",
+			Side:      "buy",
+			Amount:    1,
+			OrderType: "market",
+		}
+		assert.Error(t, executor.PlaceOrder(ctx, order))
+
+		executor.UpdateMarketPrice("ETHUSDT", 100)
+		require.NoError(t, executor.PlaceOrder(ctx, order))
+		assert.Equal(t, 100.0, order.FilledPrice)
+		assert.Zero(t, order.Price)
+	})
+
+	t.Run("insufficient balance", func(t *testing.T) {
+		order := &trading.Order{
+			Symbol:    "BTCUSDT",
+			Side:      "sell",
+			Amount:    1,
+			Price:     50000,
+			OrderType: "limit",
+		}
+		err := executor.PlaceOrder(ctx, order)
+		assert.ErrorIs(t, err, trading.ErrInsufficientFunds)
+		assert.ErrorIs(t, err, trading.ErrOrderRejected)
+	})
+
+	t.Run("market buy by quote amount", func(t *testing.T) {
+		order := &trading.Order{
+			Symbol:      "ETHUSDT",
+			Side:        "buy",
+			QuoteAmount: 50,
+			OrderType:   "market",
+		}
+		require.NoError(t, executor.PlaceOrder(ctx, order))
+		assert.InDelta(t, 0.5, order.Amount, 1e-9)
+
+		usdt, err := executor.GetBalance(ctx, "USDT")
+		require.NoError(t, err)
+		assert.InDelta(t, 350, usdt, 1e-9)
+	})
+
+	t.Run("limit buy by quote amount", func(t *testing.T) {
+		order := &trading.Order{
+			Symbol:      "BTCUSDT",
+			Side:        "buy",
+			QuoteAmount: 100,
+			Price:       40000,
+			OrderType:   "limit",
+		}
+		require.NoError(t, executor.PlaceOrder(ctx, order))
+		assert.InDelta(t, 0.0025, order.Amount, 1e-9)
+	})
+
+	t.Run("missing amount", func(t *testing.T) {
+		order := &trading.Order{
+			Symbol:    "BTCUSDT",
+			Side:      "buy",
+			Price:     40000,
+			OrderType: "limit",
+		}
+		assert.ErrorIs(t, executor.PlaceOrder(ctx, order), trading.ErrOrderRejected)
+	})
+
+	t.Run("unknown quote asset", func(t *testing.T) {
+		order := &trading.Order{
+			Symbol:    "FOOBAR",
+			Side:      "buy",
+			Amount:    1,
+			Price:     1,
+			OrderType: "limit",
+		}
+		assert.Error(t, executor.PlaceOrder(ctx, order))
+	})
+}
+
+func TestPaperExecutor_GetOrderStatus(t *testing.T) {
+	ctx := context.Background()
+	executor := NewPaperExecutor(map[string]float64{"USDT": 1000})
+	executor.UpdateMarketPrice("BTCUSDT", 50000)
+
+	order := &trading.Order{
+		Symbol:    "BTCUSDT",
+		Side:      "buy",
+		Amount:    0.01,
+		Price:     50000,
+		OrderType: "limit",
+	}
+	require.NoError(t, executor.PlaceOrder(ctx, order))
+
+	status, err := executor.GetOrderStatus(ctx, "BTCUSDT", order.OrderID)
+	require.NoError(t, err)
+	assert.Equal(t, "FILLED", status.Status)
+
+	assert.Error(t, executor.CancelOrder(ctx, "BTCUSDT", order.OrderID))
+
+	_, err = executor.GetOrderStatus(ctx, "BTCUSDT", "999")
+	assert.Error(t, err)
+
+	// 重启后新建的执行器不会复用之前的订单号
+	restarted := NewPaperExecutor(map[string]float64{"USDT": 1000})
+	restarted.UpdateMarketPrice("BTCUSDT", 50000)
+	next := &trading.Order{Symbol: "BTCUSDT", Side: "buy", Amount: 0.01, Price: 50000, OrderType: "limit"}
+	require.NoError(t, restarted.PlaceOrder(ctx, next))
+	assert.NotEqual(t, order.OrderID, next.OrderID)
+}
+
+func TestPaperExecutor_RestingLimitOrder(t *testing.T) {
+	ctx := context.Background()
+	executor := NewPaperExecutor(map[string]float64{"USDT": 1000, "BTC": 1})
+	executor.UpdateMarketPrice("BTCUSDT", 50000)
+
+	// 买单委托价低于市价，不成交，资金冻结
+	buy := &trading.Order{Symbol: "BTCUSDT", Side: "buy", Amount: 0.01, Price: 49000, OrderType: "limit"}
+	require.NoError(t, executor.PlaceOrder(ctx, buy))
+	assert.Equal(t, "NEW", buy.Status)
+	assert.Zero(t, buy.FilledAmount)
+
+	usdt, err := executor.GetBalance(ctx, "USDT")
+	require.NoError(t, err)
+	assert.InDelta(t, 510, usdt, 1e-9)
+
+	// 价格未穿过委托价，仍未成交
+	executor.UpdateMarketPrice("BTCUSDT", 49500)
+	status, err := executor.GetOrderStatus(ctx, "BTCUSDT", buy.OrderID)
+	require.NoError(t, err)
+	assert.Equal(t, "NEW", status.Status)
+
+	// 价格跌破委托价后按委托价成交
+	executor.UpdateMarketPrice("BTCUSDT", 48800)
+	status, err = executor.GetOrderStatus(ctx, "BTCUSDT", buy.OrderID)
+	require.NoError(t, err)
+	assert.Equal(t, "FILLED", status.Status)
+	assert.InDelta(t, 0.01, status.FilledAmount, 1e-9)
+	assert.InDelta(t, 49000, status.FilledPrice, 1e-9)
+
+	btc, err := executor.GetBalance(ctx, "BTC")
+	require.NoError(t, err)
+	assert.InDelta(t, 1.01, btc, 1e-9)
+
+	// 撤销未成交的卖单释放冻结的数量
+	sell := &trading.Order{Symbol: "BTCUSDT", Side: "sell", Amount: 0.5, Price: 60000, OrderType: "limit"}
+	require.NoError(t, executor.PlaceOrder(ctx, sell))
+	assert.Equal(t, "NEW", sell.Status)
+
+	btc, err = executor.GetBalance(ctx, "BTC")
+	require.NoError(t, err)
+	assert.InDelta(t, 0.51, btc, 1e-9)
+
+	require.NoError(t, executor.CancelOrder(ctx, "BTCUSDT", sell.OrderID))
+	btc, err = executor.GetBalance(ctx, "BTC")
+	require.NoError(t, err)
+	assert.InDelta(t, 1.01, btc, 1e-9)
+
+	executor.UpdateMarketPrice("BTCUSDT", 61000)
+	status, err = executor.GetOrderStatus(ctx, "BTCUSDT", sell.OrderID)
+	require.NoError(t, err)
+	assert.Equal(t, "CANCELED", status.Status)
+}
+
+func TestPaperExecutor_ExportRestoreState(t *testing.T) {
+	ctx := context.Background()
+	executor := NewPaperExecutor(map[string]float64{"USDT": 1000})
+	executor.UpdateMarketPrice("BTCUSDT", 50000)
+
+	buy := &trading.Order{Symbol: "BTCUSDT", Side: "buy", Amount: 0.01, Price: 49000, OrderType: "limit"}
+	require.NoError(t, executor.PlaceOrder(ctx, buy))
+
+	state := executor.ExportState()
+	assert.InDelta(t, 510, state.Balances["USDT"], 1e-9)
+	require.Len(t, state.OpenOrders, 1)
+
+	// 新实例恢复后挂单按原订单号继续撮合
+	restored := NewPaperExecutor(nil)
+	restored.RestoreState(state)
+	restored.UpdateMarketPrice("BTCUSDT", 48000)
+
+	status, err := restored.GetOrderStatus(ctx, "BTCUSDT", buy.OrderID)
+	require.NoError(t, err)
+	assert.Equal(t, "FILLED", status.Status)
+
+	btc, err := restored.GetBalance(ctx, "BTC")
+	require.NoError(t, err)
+	assert.InDelta(t, 0.01, btc, 1e-9)
+	usdt, err := restored.GetBalance(ctx, "USDT")
+	require.NoError(t, err)
+	assert.InDelta(t, 510, usdt, 1e-9)
+}
+
+func TestPaperExecutor_Simulation(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("rejection", func(t *testing.T) {
+		executor := NewPaperExecutor(map[string]float64{"USDT": 1000})
+		executor.SetSimulation(Simulation{RejectRate: 1, Seed: 1})
+		executor.UpdateMarketPrice("BTCUSDT", 50000)
+
+		order := &trading.Order{Symbol: "BTCUSDT", Side: "buy", Amount: 0.01, OrderType: "market"}
+		assert.ErrorIs(t, executor.PlaceOrder(ctx, order), trading.ErrOrderRejected)
+
+		usdt, err := executor.GetBalance(ctx, "USDT")
+		require.NoError(t, err)
+		assert.InDelta(t, 1000, usdt, 1e-9)
+	})
+
+	t.Run("partial fill of market order", func(t *testing.T) {
+		executor := NewPaperExecutor(map[string]float64{"USDT": 1000})
+		executor.SetSimulation(Simulation{PartialFillRate: 1, MinFillRatio: 0.5, Seed: 1})
+		executor.UpdateMarketPrice("BTCUSDT", 50000)
+
+		order := &trading.Order{Symbol: "BTCUSDT", Side: "buy", Amount: 0.01, OrderType: "market"}
+		require.NoError(t, executor.PlaceOrder(ctx, order))
+		assert.Equal(t, "EXPIRED", order.Status)
+		assert.GreaterOrEqual(t, order.FilledAmount, 0.005)
+		assert.Less(t, order.FilledAmount, 0.01)
+
+		// 只有成交部分扣减资金
+		usdt, err := executor.GetBalance(ctx, "USDT")
+		require.NoError(t, err)
+		assert.InDelta(t, 1000-order.FilledAmount*50000, usdt, 1e-9)
+		btc, err := executor.GetBalance(ctx, "BTC")
+		require.NoError(t, err)
+		assert.InDelta(t, order.FilledAmount, btc, 1e-9)
+	})
+
+	t.Run("partial fill of resting order", func(t *testing.T) {
+		executor := NewPaperExecutor(map[string]float64{"USDT": 1000})
+		executor.SetSimulation(Simulation{PartialFillRate: 1, MinFillRatio: 0.5, Seed: 1})
+		executor.UpdateMarketPrice("BTCUSDT", 50000)
+
+		order := &trading.Order{Symbol: "BTCUSDT", Side: "buy", Amount: 0.01, Price: 49000, OrderType: "limit"}
+		require.NoError(t, executor.PlaceOrder(ctx, order))
+		executor.UpdateMarketPrice("BTCUSDT", 48000)
+
+		status, err := executor.GetOrderStatus(ctx, "BTCUSDT", order.OrderID)
+		require.NoError(t, err)
+		assert.Equal(t, "PARTIALLY_FILLED", status.Status)
+		assert.Less(t, status.FilledAmount, 0.01)
+
+		// 撤单只释放未成交部分冻结的资金
+		require.NoError(t, executor.CancelOrder(ctx, "BTCUSDT", order.OrderID))
+		usdt, err := executor.GetBalance(ctx, "USDT")
+		require.NoError(t, err)
+		assert.InDelta(t, 1000-status.FilledAmount*49000, usdt, 1e-9)
+		btc, err := executor.GetBalance(ctx, "BTC")
+		require.NoError(t, err)
+		assert.InDelta(t, status.FilledAmount, btc, 1e-9)
+	})
+
+	t.Run("resting orders fill in order id sequence", func(t *testing.T) {
+		// 相同种子下多次运行的部分成交数量一致，不受 map 遍历顺序影响
+		run := func() []float64 {
+			executor := NewPaperExecutor(map[string]float64{"USDT": 10000})
+			executor.SetSimulation(Simulation{PartialFillRate: 1, MinFillRatio: 0.1, Seed: 7})
+			executor.UpdateMarketPrice("BTCUSDT", 50000)
+
+			var orders []*trading.Order
+			for i := 0; i < 8; i++ {
+				order := &trading.Order{Symbol: "BTCUSDT", Side: "buy", Amount: 0.01, Price: 49000 - float64(i), OrderType: "limit"}
+				require.NoError(t, executor.PlaceOrder(ctx, order))
+				orders = append(orders, order)
+			}
+			executor.UpdateMarketPrice("BTCUSDT", 48000)
+
+			filled := make([]float64, len(orders))
+			for i, order := range orders {
+				status, err := executor.GetOrderStatus(ctx, "BTCUSDT", order.OrderID)
+				require.NoError(t, err)
+				filled[i] = status.FilledAmount
+			}
+			return filled
+		}
+
+		expected := run()
+		for i := 0; i < 10; i++ {
+			assert.Equal(t, expected, run())
+		}
+	})
+
+	t.Run("latency", func(t *testing.T) {
+		executor := NewPaperExecutor(map[string]float64{"USDT": 1000})
+		executor.SetSimulation(Simulation{MinLatency: 20 * time.Millisecond, MaxLatency: 30 * time.Millisecond})
+		executor.UpdateMarketPrice("BTCUSDT", 50000)
+
+		start := time.Now()
+		order := &trading.Order{Symbol: "BTCUSDT", Side: "buy", Amount: 0.01, OrderType: "market"}
+		require.NoError(t, executor.PlaceOrder(ctx, order))
+		assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+		assert.ErrorIs(t, executor.PlaceOrder(canceled, &trading.Order{Symbol: "BTCUSDT", Side: "buy", Amount: 0.01, OrderType: "market"}), context.Canceled)
+	})
+}
+
+func TestPaperExecutor_Fees(t *testing.T) {
+	ctx := context.Background()
+	executor := NewPaperExecutor(map[string]float64{"USDT": 1000})
+	executor.SetSimulation(Simulation{MakerFeeRate: 0.0002, TakerFeeRate: 0.001})
+	executor.UpdateMarketPrice("BTCUSDT", 100)
+
+	// 立即成交按吃单费率从买入的基础资产中扣除
+	before := time.Now()
+	buy := &trading.Order{Symbol: "BTCUSDT", Side: "buy", Amount: 2, OrderType: "market", ClientOrderID: "my-order"}
+	require.NoError(t, executor.PlaceOrder(ctx, buy))
+	assert.Equal(t, "my-order", buy.ClientOrderID)
+	assert.InDelta(t, 0.002, buy.Fee, 1e-12)
+	assert.Equal(t, "BTC", buy.FeeAsset)
+	assert.False(t, buy.CreatedAt.Before(before))
+	assert.Equal(t, buy.CreatedAt, buy.UpdatedAt)
+	balance, err := executor.GetBalance(ctx, "BTC")
+	require.NoError(t, err)
+	assert.InDelta(t, 1.998, balance, 1e-12)
+
+	// 挂单成交按挂单费率从卖出得到的计价资产中扣除
+	sell := &trading.Order{Symbol: "BTCUSDT", Side: "sell", Amount: 1, Price: 110, OrderType: "limit"}
+	require.NoError(t, executor.PlaceOrder(ctx, sell))
+	assert.NotEmpty(t, sell.ClientOrderID)
+	assert.Zero(t, sell.Fee)
+	executor.UpdateMarketPrice("BTCUSDT", 111)
+
+	status, err := executor.GetOrderStatus(ctx, "BTCUSDT", sell.OrderID)
+	require.NoError(t, err)
+	assert.Equal(t, "FILLED", status.Status)
+	assert.InDelta(t, 0.022, status.Fee, 1e-12)
+	assert.Equal(t, "USDT", status.FeeAsset)
+	assert.False(t, status.UpdatedAt.Before(status.CreatedAt))
+	balance, err = executor.GetBalance(ctx, "USDT")
+	require.NoError(t, err)
+	assert.InDelta(t, 1000-200+110-0.022, balance, 1e-9)
+}
+
+func TestPaperExecutor_DecimalBalances(t *testing.T) {
+	ctx := context.Background()
+	executor := NewPaperExecutor(map[string]float64{"USDT": 1000})
+	executor.SetSimulation(Simulation{TakerFeeRate: 0.001})
+	executor.UpdateMarketPrice("ETHUSDT", 0.3)
+
+	// 多次小额成交后余额仍是精确的十进制结果
+	for range 100 {
+		require.NoError(t, executor.PlaceOrder(ctx, &trading.Order{Symbol: "ETHUSDT", Side: "buy", Amount: 0.1, OrderType: "market"}))
+	}
+	usdt, err := executor.GetBalance(ctx, "USDT")
+	require.NoError(t, err)
+	assert.Equal(t, 997.0, usdt)
+	eth, err := executor.GetBalance(ctx, "ETH")
+	require.NoError(t, err)
+	assert.Equal(t, 9.99, eth)
+}
+
+func TestPaperExecutor_Hedge(t *testing.T) {
+	ctx := context.Background()
+	executor := NewPaperExecutor(map[string]float64{"USDT": 1000})
+
+	_, err := executor.OpenHedge(ctx, "BTCUSDT", 1)
+	assert.ErrorIs(t, err, trading.ErrOrderRejected)
+
+	executor.UpdateMarketPrice("BTCUSDT", 100)
+	order, err := executor.OpenHedge(ctx, "BTCUSDT", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "sell", order.Side)
+	assert.Equal(t, "FILLED", order.Status)
+
+	executor.UpdateMarketPrice("BTCUSDT", 110)
+	_, err = executor.OpenHedge(ctx, "BTCUSDT", 1)
+	require.NoError(t, err)
+	amount, err := executor.HedgeAmount(ctx, "BTCUSDT")
+	require.NoError(t, err)
+	assert.InDelta(t, 2, amount, 1e-9)
+
+	// 对冲随状态快照恢复
+	restored := NewPaperExecutor(nil)
+	restored.RestoreState(executor.ExportState())
+	restored.UpdateMarketPrice("BTCUSDT", 120)
+
+	// 开仓均价 105，平仓价 120，空单亏损 30
+	order, err = restored.CloseHedge(ctx, "BTCUSDT")
+	require.NoError(t, err)
+	assert.Equal(t, "buy", order.Side)
+	usdt, err := restored.GetBalance(ctx, "USDT")
+	require.NoError(t, err)
+	assert.InDelta(t, 970, usdt, 1e-9)
+
+	order, err = restored.CloseHedge(ctx, "BTCUSDT")
+	require.NoError(t, err)
+	assert.Nil(t, order)
+}
@@ -0,0 +1,25 @@
+package trading
+
+import "context"
+
+// SystemStatus 交易所系统状态
+type SystemStatus struct {
+	Maintenance bool   `json:"maintenance"` // 系统维护中
+	Message     string `json:"message"`
+}
+
+// AssetStatus 资产的充值和提现状态
+type AssetStatus struct {
+	Asset           string `json:"asset"`
+	DepositEnabled  bool   `json:"deposit_enabled"`
+	WithdrawEnabled bool   `json:"withdraw_enabled"`
+}
+
+// WalletStatusProvider is implemented by executors that can query the exchange system status and the wallet status of assets
+type WalletStatusProvider interface {
+	// SystemStatus returns whether the exchange is under maintenance
+	SystemStatus(ctx context.Context) (SystemStatus, error)
+
+	// AssetStatuses returns the deposit and withdrawal status of all assets
+	AssetStatuses(ctx context.Context) ([]AssetStatus, error)
+}
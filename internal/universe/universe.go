@@ -0,0 +1,241 @@
+package universe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/exchangeinfo"
+)
+
+// StatusDelisted 交易对已从交易所下架；只在内部登记、尚未同步到交易所的交易对状态为空
+const StatusDelisted = "DELISTED"
+
+var (
+	// ErrInvalidSymbol 交易对名称不合法
+	ErrInvalidSymbol = errors.New("invalid symbol")
+
+	// ErrNotLoaded 品种池尚未从存储加载，此时写入会覆盖已保存的标签和上线日期
+	ErrNotLoaded = errors.New("symbol universe is not loaded")
+)
+
+// Symbol 品种池中交易对的元数据：交易所字段由同步维护，标签和上线日期由内部维护
+type Symbol struct {
+	Symbol     string    `json:"symbol"`
+	BaseAsset  string    `json:"base_asset"`
+	QuoteAsset string    `json:"quote_asset"`
+	Status     string    `json:"status"`    // 交易所状态，下架后为 DELISTED
+	TickSize   float64   `json:"tick_size"` // 价格最小变动单位
+	StepSize   float64   `json:"step_size"` // 数量最小变动单位
+	Tags       []string  `json:"tags"`      // 内部分类标签，如 meme、L1
+	ListedAt   time.Time `json:"listed_at"` // 上线日期，默认为首次同步到的时间
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// HasTag 判断交易对是否带有标签，不区分大小写
+func (s Symbol) HasTag(tag string) bool {
+	return slices.ContainsFunc(s.Tags, func(t string) bool { return strings.EqualFold(t, tag) })
+}
+
+// Listed 交易对是否在交易所上架（包括暂停交易）
+func (s Symbol) Listed() bool {
+	return s.Status != "" && s.Status != StatusDelisted
+}
+
+// Update 通过 API 修改的内部元数据，字段为 nil 时不修改
+type Update struct {
+	Tags     []string   `json:"tags"`
+	ListedAt *time.Time `json:"listed_at"`
+}
+
+// Filter 列表的筛选条件，字段为空时不筛选
+type Filter struct {
+	Tag        string
+	QuoteAsset string
+	Status     string
+}
+
+// Store 持久化品种池
+type Store interface {
+	// SaveSymbols inserts or replaces the symbols
+	SaveSymbols(ctx context.Context, symbols []Symbol) error
+
+	// ListSymbols returns all symbols of the universe
+	ListSymbols(ctx context.Context) ([]Symbol, error)
+}
+
+// Service 维护内部品种池：定期按交易所元数据同步上架、下架和精度，保留内部标签和上线日期；
+// 代币发现、按标签的交易对配置和报告按品种池的标签区分交易对
+type Service struct {
+	store Store
+
+	mu      sync.RWMutex
+	symbols map[string]Symbol
+	loaded  bool
+}
+
+// NewService creates a new Service instance
+func NewService(store Store) *Service {
+	return &Service{store: store, symbols: make(map[string]Symbol)}
+}
+
+// Load 从存储加载品种池
+func (s *Service) Load(ctx context.Context) error {
+	list, err := s.store.ListSymbols(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load symbol universe: %w", err)
+	}
+
+	symbols := make(map[string]Symbol, len(list))
+	for _, symbol := range list {
+		symbols[symbol.Symbol] = symbol
+	}
+	s.mu.Lock()
+	s.symbols = symbols
+	s.loaded = true
+	s.mu.Unlock()
+	return nil
+}
+
+// Sync 按交易所当前的交易对更新品种池：新交易对以 now 为上线日期加入，已有交易对更新状态和精度，
+// 交易所不再返回的交易对标记为下架；只保存有变化的交易对，返回新加入的交易对
+func (s *Service) Sync(ctx context.Context, listed []exchangeinfo.Symbol, now time.Time) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.loaded {
+		return nil, ErrNotLoaded
+	}
+
+	var changed []Symbol
+	var added []string
+	seen := make(map[string]bool, len(listed))
+	for _, info := range listed {
+		seen[info.Symbol] = true
+		current, ok := s.symbols[info.Symbol]
+		next := current
+		next.Symbol = info.Symbol
+		next.BaseAsset, next.QuoteAsset = info.BaseAsset, info.QuoteAsset
+		next.Status = info.Status
+		next.TickSize, next.StepSize = info.TickSize, info.StepSize
+		if next.ListedAt.IsZero() {
+			next.ListedAt = now
+		}
+		if !ok {
+			added = append(added, info.Symbol)
+		}
+		if !ok || !sameExchangeFields(current, next) {
+			next.UpdatedAt = now
+			changed = append(changed, next)
+		}
+	}
+	for name, current := range s.symbols {
+		if !seen[name] && current.Listed() {
+			current.Status = StatusDelisted
+			current.UpdatedAt = now
+			changed = append(changed, current)
+		}
+	}
+	if len(changed) == 0 {
+		return nil, nil
+	}
+
+	if err := s.store.SaveSymbols(ctx, changed); err != nil {
+		return nil, fmt.Errorf("failed to save symbol universe: %w", err)
+	}
+	for _, symbol := range changed {
+		s.symbols[symbol.Symbol] = symbol
+	}
+	sort.Strings(added)
+	return added, nil
+}
+
+// sameExchangeFields 判断由交易所维护的字段是否相同
+func sameExchangeFields(a, b Symbol) bool {
+	return a.BaseAsset == b.BaseAsset && a.QuoteAsset == b.QuoteAsset && a.Status == b.Status &&
+		a.TickSize == b.TickSize && a.StepSize == b.StepSize && a.ListedAt.Equal(b.ListedAt)
+}
+
+// Update 修改交易对的标签或上线日期，交易对不在品种池中时作为内部交易对加入
+func (s *Service) Update(ctx context.Context, symbol string, update Update, now time.Time) (Symbol, error) {
+	if symbol == "" || symbol != strings.ToUpper(symbol) {
+		return Symbol{}, fmt.Errorf("%w: %q must be an upper-case trading pair", ErrInvalidSymbol, symbol)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.loaded {
+		return Symbol{}, ErrNotLoaded
+	}
+
+	next := s.symbols[symbol]
+	next.Symbol = symbol
+	if update.Tags != nil {
+		next.Tags = normalizeTags(update.Tags)
+	}
+	if update.ListedAt != nil {
+		next.ListedAt = *update.ListedAt
+	}
+	next.UpdatedAt = now
+
+	if err := s.store.SaveSymbols(ctx, []Symbol{next}); err != nil {
+		return Symbol{}, fmt.Errorf("failed to save symbol %s: %w", symbol, err)
+	}
+	s.symbols[symbol] = next
+	return next, nil
+}
+
+// normalizeTags 去掉空白和重复（不区分大小写）的标签并排序
+func normalizeTags(tags []string) []string {
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || slices.ContainsFunc(result, func(t string) bool { return strings.EqualFold(t, tag) }) {
+			continue
+		}
+		result = append(result, tag)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Get 返回交易对的元数据
+func (s *Service) Get(symbol string) (Symbol, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	info, ok := s.symbols[symbol]
+	return info, ok
+}
+
+// List 返回符合条件的交易对，按交易对排序
+func (s *Service) List(filter Filter) []Symbol {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []Symbol
+	for _, info := range s.symbols {
+		if filter.Tag != "" && !info.HasTag(filter.Tag) {
+			continue
+		}
+		if filter.QuoteAsset != "" && info.QuoteAsset != filter.QuoteAsset {
+			continue
+		}
+		if filter.Status != "" && info.Status != filter.Status {
+			continue
+		}
+		result = append(result, info)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Symbol < result[j].Symbol })
+	return result
+}
+
+// Tags 返回交易对的标签，交易对不在品种池中时为空
+func (s *Service) Tags(symbol string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.Clone(s.symbols[symbol].Tags)
+}
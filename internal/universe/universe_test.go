@@ -0,0 +1,118 @@
+package universe
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/exchangeinfo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryStore struct {
+	symbols map[string]Symbol
+	saves   int
+}
+
+func (m *memoryStore) SaveSymbols(_ context.Context, symbols []Symbol) error {
+	m.saves++
+	for _, symbol := range symbols {
+		m.symbols[symbol.Symbol] = symbol
+	}
+	return nil
+}
+
+func (m *memoryStore) ListSymbols(context.Context) ([]Symbol, error) {
+	var list []Symbol
+	for _, symbol := range m.symbols {
+		list = append(list, symbol)
+	}
+	return list, nil
+}
+
+func TestService_Sync(t *testing.T) {
+	ctx := context.Background()
+	day1 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	store := &memoryStore{symbols: map[string]Symbol{
+		"PEPEUSDT": {Symbol: "PEPEUSDT", Status: exchangeinfo.StatusTrading, Tags: []string{"meme"}, ListedAt: day1.AddDate(-1, 0, 0)},
+	}}
+	service := NewService(store)
+	_, err := service.Sync(ctx, nil, day1)
+	require.ErrorIs(t, err, ErrNotLoaded)
+	require.NoError(t, service.Load(ctx))
+
+	listed := []exchangeinfo.Symbol{
+		{Symbol: "BTCUSDT", BaseAsset: "BTC", QuoteAsset: "USDT", Status: exchangeinfo.StatusTrading, TickSize: 0.01, StepSize: 0.00001},
+		{Symbol: "PEPEUSDT", BaseAsset: "PEPE", QuoteAsset: "USDT", Status: exchangeinfo.StatusTrading, TickSize: 0.00000001, StepSize: 1},
+	}
+	added, err := service.Sync(ctx, listed, day1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BTCUSDT"}, added)
+
+	// 同步保留内部标签和上线日期
+	pepe, ok := service.Get("PEPEUSDT")
+	require.True(t, ok)
+	assert.Equal(t, []string{"meme"}, pepe.Tags)
+	assert.Equal(t, day1.AddDate(-1, 0, 0), pepe.ListedAt)
+	assert.Equal(t, 1.0, pepe.StepSize)
+	btc, _ := service.Get("BTCUSDT")
+	assert.Equal(t, day1, btc.ListedAt)
+
+	// 没有变化时不保存
+	saves := store.saves
+	added, err = service.Sync(ctx, listed, day2)
+	require.NoError(t, err)
+	assert.Empty(t, added)
+	assert.Equal(t, saves, store.saves)
+
+	// 交易所不再返回的交易对标记为下架
+	_, err = service.Sync(ctx, listed[:1], day2)
+	require.NoError(t, err)
+	pepe, _ = service.Get("PEPEUSDT")
+	assert.Equal(t, StatusDelisted, pepe.Status)
+	assert.False(t, pepe.Listed())
+	assert.Equal(t, StatusDelisted, store.symbols["PEPEUSDT"].Status)
+}
+
+func TestService_Update(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := &memoryStore{symbols: map[string]Symbol{
+		"SOLUSDT": {Symbol: "SOLUSDT", QuoteAsset: "USDT", Status: exchangeinfo.StatusTrading},
+		"ETHBTC":  {Symbol: "ETHBTC", QuoteAsset: "BTC", Status: exchangeinfo.StatusTrading, Tags: []string{"L1"}},
+	}}
+	service := NewService(store)
+	require.NoError(t, service.Load(ctx))
+
+	updated, err := service.Update(ctx, "SOLUSDT", Update{Tags: []string{" L1 ", "l1", "", "smart-contract"}}, now)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"L1", "smart-contract"}, updated.Tags)
+	assert.Equal(t, exchangeinfo.StatusTrading, updated.Status)
+	assert.Equal(t, updated, store.symbols["SOLUSDT"])
+
+	// 未上架的交易对作为内部交易对加入
+	listedAt := now.AddDate(0, 1, 0)
+	updated, err = service.Update(ctx, "NEWUSDT", Update{Tags: []string{"meme"}, ListedAt: &listedAt}, now)
+	require.NoError(t, err)
+	assert.False(t, updated.Listed())
+	assert.Equal(t, listedAt, updated.ListedAt)
+
+	_, err = service.Update(ctx, "newusdt", Update{}, now)
+	assert.ErrorIs(t, err, ErrInvalidSymbol)
+
+	names := func(list []Symbol) []string {
+		var result []string
+		for _, symbol := range list {
+			result = append(result, symbol.Symbol)
+		}
+		return result
+	}
+	assert.Equal(t, []string{"ETHBTC", "SOLUSDT"}, names(service.List(Filter{Tag: "l1"})))
+	assert.Equal(t, []string{"SOLUSDT"}, names(service.List(Filter{Tag: "L1", QuoteAsset: "USDT"})))
+	assert.Equal(t, []string{"ETHBTC", "SOLUSDT"}, names(service.List(Filter{Status: exchangeinfo.StatusTrading})))
+	assert.Equal(t, []string{"meme"}, service.Tags("NEWUSDT"))
+	assert.Empty(t, service.Tags("BTCUSDT"))
+}
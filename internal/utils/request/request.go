@@ -1,7 +1,10 @@
 package request
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
 
 	"github.com/go-resty/resty/v2"
 )
@@ -9,3 +12,12 @@ import (
 var Request = resty.New().SetTransport(&http.Transport{
 	Proxy: http.ProxyFromEnvironment, // 通用适配环境变量
 }).SetRetryCount(3)
+
+// RedactURL 去掉请求错误中的 URL，用于路径或参数中带 token 的请求，避免错误写入日志时泄露密钥
+func RedactURL(err error) error {
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		return err
+	}
+	return fmt.Errorf("%s request: %w", urlErr.Op, urlErr.Err)
+}
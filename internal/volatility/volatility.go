@@ -0,0 +1,177 @@
+package volatility
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/data/candle"
+	"github.com/songzhibin97/quantaflux/internal/models"
+)
+
+// 一年的时长，用于年化波动率，加密货币全年交易
+const year = 365 * 24 * time.Hour
+
+// History 历史行情来源，data.DataStorage 实现了该接口
+type History interface {
+	GetHistoricalData(ctx context.Context, symbol string, start, end time.Time) ([]models.MarketData, error)
+}
+
+// Options 波动率计算参数
+type Options struct {
+	Interval time.Duration // K 线周期
+	Window   int           // 计算使用的收益率和真实波幅个数，默认 20
+}
+
+// Estimate 交易对最近 Window 根 K 线的波动率
+type Estimate struct {
+	Symbol     string    `json:"symbol"`
+	Volatility float64   `json:"volatility"` // 单根 K 线对数收益率的样本标准差
+	Annualized float64   `json:"annualized"` // 按全年交易年化的波动率
+	ATR        float64   `json:"atr"`        // 平均真实波幅，价格单位
+	Close      float64   `json:"close"`      // 最后一根 K 线的收盘价
+	Samples    int       `json:"samples"`    // 参与计算的收益率个数
+	UpdatedAt  time.Time `json:"updated_at"` // 最后一根 K 线的收盘时间
+}
+
+// RelativeATR 返回 ATR 占收盘价的比例
+func (e Estimate) RelativeATR() float64 {
+	if e.Close <= 0 {
+		return 0
+	}
+	return e.ATR / e.Close
+}
+
+// Service 按 K 线计算各交易对的滚动历史波动率和 ATR。启动时从历史行情预热，
+// 之后每根 K 线收盘时更新并缓存结果；K 线按行情自身的时间戳聚合，回测与实盘一致
+type Service struct {
+	history    History
+	options    Options
+	aggregator *candle.Aggregator
+
+	mu        sync.RWMutex
+	candles   map[string][]models.Candle // 交易对 -> 最近 Window+1 根已收盘的 K 线
+	estimates map[string]Estimate
+}
+
+// NewService creates a new Service instance
+func NewService(history History, options Options) *Service {
+	if options.Window <= 0 {
+		options.Window = 20
+	}
+	return &Service{
+		history:    history,
+		options:    options,
+		aggregator: candle.NewAggregator(options.Interval),
+		candles:    make(map[string][]models.Candle),
+		estimates:  make(map[string]Estimate),
+	}
+}
+
+// Load 用 now 之前 Window+2 个周期的历史行情预热，最后一根未收盘的 K 线继续由实时行情聚合；
+// 单个交易对加载失败不影响其他交易对
+func (s *Service) Load(ctx context.Context, symbols []string, now time.Time) error {
+	lookback := time.Duration(s.options.Window+2) * s.options.Interval
+	var errs []error
+	for _, symbol := range symbols {
+		history, err := s.history.GetHistoricalData(ctx, symbol, now.Add(-lookback), now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to load price history of %s: %w", symbol, err))
+			continue
+		}
+		for _, data := range history {
+			s.Observe(data)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Observe 加入一条行情，K 线收盘时重新计算该交易对的波动率并返回 true
+func (s *Service) Observe(data models.MarketData) (Estimate, bool) {
+	if data.Price <= 0 {
+		return Estimate{}, false
+	}
+	closed, ok := s.aggregator.Add(data)
+	if !ok {
+		return Estimate{}, false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	candles := append(s.candles[closed.Symbol], *closed)
+	if len(candles) > s.options.Window+1 {
+		candles = candles[len(candles)-s.options.Window-1:]
+	}
+	s.candles[closed.Symbol] = candles
+
+	estimate, ok := compute(candles, s.options.Interval)
+	if !ok {
+		return Estimate{}, false
+	}
+	s.estimates[closed.Symbol] = estimate
+	return estimate, true
+}
+
+// Estimate 返回交易对最近一次计算的波动率，K 线不足 3 根时返回 false
+func (s *Service) Estimate(symbol string) (Estimate, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	estimate, ok := s.estimates[symbol]
+	return estimate, ok
+}
+
+// Volatility implements risk.VolatilitySource
+func (s *Service) Volatility(symbol string) (float64, bool) {
+	estimate, ok := s.Estimate(symbol)
+	return estimate.Volatility, ok
+}
+
+// RelativeATR implements risk.VolatilitySource
+func (s *Service) RelativeATR(symbol string) (float64, bool) {
+	estimate, ok := s.Estimate(symbol)
+	return estimate.RelativeATR(), ok && estimate.ATR > 0
+}
+
+// compute 按时间有序的 K 线计算对数收益率的样本标准差和真实波幅的简单平均
+func compute(candles []models.Candle, interval time.Duration) (Estimate, bool) {
+	if len(candles) < 3 {
+		return Estimate{}, false
+	}
+
+	returns := make([]float64, 0, len(candles)-1)
+	var trueRanges float64
+	for i := 1; i < len(candles); i++ {
+		prev, cur := candles[i-1].Close, candles[i]
+		returns = append(returns, math.Log(cur.Close/prev))
+		trueRanges += max(cur.High-cur.Low, math.Abs(cur.High-prev), math.Abs(cur.Low-prev))
+	}
+
+	var mean float64
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+	var variance float64
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	vol := math.Sqrt(variance / float64(len(returns)-1))
+
+	last := candles[len(candles)-1]
+	estimate := Estimate{
+		Symbol:     last.Symbol,
+		Volatility: vol,
+		ATR:        trueRanges / float64(len(returns)),
+		Close:      last.Close,
+		Samples:    len(returns),
+		UpdatedAt:  last.CloseTime,
+	}
+	if interval > 0 {
+		estimate.Annualized = vol * math.Sqrt(float64(year)/float64(interval))
+	}
+	return estimate, true
+}
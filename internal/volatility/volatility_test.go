@@ -0,0 +1,103 @@
+package volatility
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/songzhibin97/quantaflux/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubHistory []models.MarketData
+
+func (h stubHistory) GetHistoricalData(_ context.Context, symbol string, start, end time.Time) ([]models.MarketData, error) {
+	var result []models.MarketData
+	for _, d := range h {
+		if d.Symbol == symbol && !d.Timestamp.Before(start) && !d.Timestamp.After(end) {
+			result = append(result, d)
+		}
+	}
+	return result, nil
+}
+
+func TestService_Observe(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tick := func(minute int, price float64) models.MarketData {
+		return models.MarketData{Symbol: "BTCUSDT", Price: price, Timestamp: base.Add(time.Duration(minute) * time.Minute)}
+	}
+
+	service := NewService(nil, Options{Interval: time.Minute, Window: 3})
+
+	// 第 2 根 K 线收盘时只有一个收益率，不计算
+	for i, price := range []float64{100, 110, 99} {
+		_, ok := service.Observe(tick(i, price))
+		assert.False(t, ok)
+	}
+	_, ok := service.Estimate("BTCUSDT")
+	assert.False(t, ok)
+
+	// 分钟内的高低点计入真实波幅
+	_, ok = service.Observe(tick(2, 97))
+	assert.False(t, ok)
+	_, ok = service.Observe(tick(2, 99))
+	assert.False(t, ok)
+
+	estimate, ok := service.Observe(tick(3, 108.9))
+	require.True(t, ok)
+	assert.Equal(t, 2, estimate.Samples)
+	// 真实波幅：|110-100|=10，max(99-97, |99-110|, |97-110|)=13
+	assert.InDelta(t, 11.5, estimate.ATR, 1e-9)
+	assert.InDelta(t, 11.5/99, estimate.RelativeATR(), 1e-9)
+	r1, r2 := math.Log(1.1), math.Log(0.9)
+	assert.InDelta(t, math.Abs(r1-r2)/math.Sqrt2, estimate.Volatility, 1e-9)
+	assert.InDelta(t, estimate.Volatility*math.Sqrt(365*24*60), estimate.Annualized, 1e-9)
+
+	// 超过窗口后丢弃最早的 K 线：收盘价 110、99、108.9、98.01，真实波幅 13、9.9、10.89
+	_, ok = service.Observe(tick(4, 98.01))
+	assert.True(t, ok)
+	estimate, ok = service.Observe(tick(5, 100))
+	require.True(t, ok)
+	assert.Equal(t, 3, estimate.Samples)
+	assert.InDelta(t, (13+9.9+10.89)/3, estimate.ATR, 1e-9)
+	assert.Equal(t, base.Add(5*time.Minute), estimate.UpdatedAt)
+
+	vol, ok := service.Volatility("BTCUSDT")
+	assert.True(t, ok)
+	assert.Equal(t, estimate.Volatility, vol)
+	_, ok = service.Volatility("ETHUSDT")
+	assert.False(t, ok)
+}
+
+func TestService_Load(t *testing.T) {
+	now := time.Date(2025, 1, 1, 1, 0, 0, 0, time.UTC)
+	var history stubHistory
+	for i := range 60 {
+		price := 100.0
+		if i%2 == 1 {
+			price = 102
+		}
+		history = append(history, models.MarketData{Symbol: "BTCUSDT", Price: price, Timestamp: now.Add(time.Duration(i-60) * time.Minute)})
+	}
+
+	service := NewService(history, Options{Interval: 5 * time.Minute, Window: 4})
+	require.NoError(t, service.Load(context.Background(), []string{"BTCUSDT", "ETHUSDT"}, now))
+
+	// 只加载最近 6 个周期，最后一根 K 线尚未收盘
+	estimate, ok := service.Estimate("BTCUSDT")
+	require.True(t, ok)
+	assert.Equal(t, 4, estimate.Samples)
+	assert.Equal(t, now.Add(-5*time.Minute), estimate.UpdatedAt)
+	assert.InDelta(t, 2, estimate.ATR, 1e-9)
+
+	_, ok = service.Estimate("ETHUSDT")
+	assert.False(t, ok)
+
+	// 实时行情继续聚合预热时未收盘的 K 线
+	estimate, ok = service.Observe(models.MarketData{Symbol: "BTCUSDT", Price: 101, Timestamp: now})
+	require.True(t, ok)
+	assert.Equal(t, now, estimate.UpdatedAt)
+}
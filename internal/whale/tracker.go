@@ -0,0 +1,144 @@
+package whale
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// 大额转账方向
+const (
+	FlowInflow   = "exchange_inflow"  // 从非交易所地址转入交易所，通常先于抛售
+	FlowOutflow  = "exchange_outflow" // 从交易所转出到非交易所地址
+	FlowTransfer = "transfer"         // 其他转账
+)
+
+// Transfer 一笔大额链上转账
+type Transfer struct {
+	Blockchain   string    `json:"blockchain"`
+	Asset        string    `json:"asset"` // 资产代码，大写，如 BTC
+	Hash         string    `json:"hash"`
+	From         string    `json:"from"`
+	FromExchange string    `json:"from_exchange,omitempty"` // 转出地址所属交易所，为空表示非交易所地址
+	To           string    `json:"to"`
+	ToExchange   string    `json:"to_exchange,omitempty"` // 转入地址所属交易所
+	Amount       float64   `json:"amount"`
+	AmountUSD    float64   `json:"amount_usd"`
+	Timestamp    time.Time `json:"timestamp"`
+}
+
+// Flow 返回转账相对交易所的方向
+func (t Transfer) Flow() string {
+	switch {
+	case t.ToExchange != "" && t.FromExchange == "":
+		return FlowInflow
+	case t.FromExchange != "" && t.ToExchange == "":
+		return FlowOutflow
+	default:
+		return FlowTransfer
+	}
+}
+
+// TransferSource 提供大额链上转账
+type TransferSource interface {
+	// Transfers returns transfers worth at least minValueUSD since the given time.
+	Transfers(ctx context.Context, since time.Time, minValueUSD float64) ([]Transfer, error)
+}
+
+// Tracker 定期拉取大额转账，按资产统计统计窗口内流入和流出交易所的金额
+type Tracker struct {
+	source   TransferSource
+	window   time.Duration
+	minValue float64
+
+	mu        sync.RWMutex
+	transfers []Transfer
+	seen      map[string]bool // 已记录的转账哈希，避免分页重叠时重复统计
+	last      time.Time       // 上次拉取到的最新转账时间
+}
+
+func NewTracker(source TransferSource, window time.Duration, minValueUSD float64) *Tracker {
+	return &Tracker{
+		source:   source,
+		window:   window,
+		minValue: minValueUSD,
+		seen:     make(map[string]bool),
+	}
+}
+
+// Poll 拉取上次之后的新转账并返回，同时清理统计窗口之外的转账
+func (t *Tracker) Poll(ctx context.Context, now time.Time) ([]Transfer, error) {
+	t.mu.RLock()
+	since := t.last
+	t.mu.RUnlock()
+	if since.IsZero() || since.Before(now.Add(-t.window)) {
+		since = now.Add(-t.window)
+	}
+
+	transfers, err := t.source.Transfers(ctx, since, t.minValue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get whale transfers: %w", err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var added []Transfer
+	for _, transfer := range transfers {
+		key := transfer.Blockchain + ":" + transfer.Hash
+		if t.seen[key] {
+			continue
+		}
+		t.seen[key] = true
+		t.transfers = append(t.transfers, transfer)
+		added = append(added, transfer)
+		if transfer.Timestamp.After(t.last) {
+			t.last = transfer.Timestamp
+		}
+	}
+
+	cutoff := now.Add(-t.window)
+	kept := t.transfers[:0]
+	for _, transfer := range t.transfers {
+		if transfer.Timestamp.Before(cutoff) {
+			delete(t.seen, transfer.Blockchain+":"+transfer.Hash)
+			continue
+		}
+		kept = append(kept, transfer)
+	}
+	t.transfers = kept
+
+	return added, nil
+}
+
+// Metrics 返回资产在统计窗口内的大额转账指标，作为情绪分析的输入
+func (t *Tracker) Metrics(asset string) map[string]float64 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var inflow, outflow float64
+	var count int
+	for _, transfer := range t.transfers {
+		if transfer.Asset != asset {
+			continue
+		}
+		count++
+		switch transfer.Flow() {
+		case FlowInflow:
+			inflow += transfer.AmountUSD
+		case FlowOutflow:
+			outflow += transfer.AmountUSD
+		}
+	}
+	if count == 0 {
+		return nil
+	}
+
+	return map[string]float64{
+		"whale_transfers":            float64(count),
+		"whale_exchange_inflow_usd":  inflow,
+		"whale_exchange_outflow_usd": outflow,
+		"whale_net_inflow_usd":       inflow - outflow,
+	}
+}
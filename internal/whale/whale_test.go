@@ -0,0 +1,85 @@
+package whale
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWhaleAlertSource_Transfers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/transactions", r.URL.Path)
+		assert.Equal(t, "test-key", r.URL.Query().Get("api_key"))
+		assert.Equal(t, "1000000", r.URL.Query().Get("min_value"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"result":"success","count":1,"transactions":[{
+			"blockchain":"ethereum","symbol":"pepe","hash":"0xabc",
+			"from":{"address":"0x1","owner_type":"unknown"},
+			"to":{"address":"0x2","owner":"binance","owner_type":"exchange"},
+			"timestamp":1735689600,"amount":1e12,"amount_usd":2500000}]}`))
+	}))
+	defer server.Close()
+
+	source := NewWhaleAlertSource("test-key")
+	source.baseURL = server.URL
+	source.httpClient = resty.NewWithClient(server.Client())
+
+	transfers, err := source.Transfers(context.Background(), time.Unix(1735689000, 0), 1_000_000)
+	require.NoError(t, err)
+	require.Len(t, transfers, 1)
+	assert.Equal(t, "PEPE", transfers[0].Asset)
+	assert.Equal(t, "binance", transfers[0].ToExchange)
+	assert.Equal(t, FlowInflow, transfers[0].Flow())
+	assert.Equal(t, 2500000.0, transfers[0].AmountUSD)
+}
+
+type stubSource struct {
+	transfers []Transfer
+	since     time.Time
+}
+
+func (s *stubSource) Transfers(ctx context.Context, since time.Time, minValueUSD float64) ([]Transfer, error) {
+	s.since = since
+	return s.transfers, nil
+}
+
+func TestTracker(t *testing.T) {
+	now := time.Now()
+	source := &stubSource{transfers: []Transfer{
+		{Blockchain: "ethereum", Hash: "a", Asset: "PEPE", ToExchange: "binance", AmountUSD: 3e6, Timestamp: now.Add(-2 * time.Hour)},
+		{Blockchain: "ethereum", Hash: "b", Asset: "PEPE", FromExchange: "okx", AmountUSD: 1e6, Timestamp: now.Add(-time.Hour)},
+		{Blockchain: "ethereum", Hash: "c", Asset: "PEPE", AmountUSD: 5e6, Timestamp: now.Add(-time.Minute)},
+	}}
+	tracker := NewTracker(source, 24*time.Hour, 1e6)
+
+	added, err := tracker.Poll(context.Background(), now)
+	require.NoError(t, err)
+	assert.Len(t, added, 3)
+	assert.Equal(t, now.Add(-24*time.Hour), source.since)
+
+	metrics := tracker.Metrics("PEPE")
+	assert.Equal(t, 3.0, metrics["whale_transfers"])
+	assert.Equal(t, 3e6, metrics["whale_exchange_inflow_usd"])
+	assert.Equal(t, 1e6, metrics["whale_exchange_outflow_usd"])
+	assert.Equal(t, 2e6, metrics["whale_net_inflow_usd"])
+	assert.Nil(t, tracker.Metrics("BTC"))
+
+	// 重复返回的转账不重复统计，下次从最新转账时间开始查询
+	added, err = tracker.Poll(context.Background(), now)
+	require.NoError(t, err)
+	assert.Empty(t, added)
+	assert.Equal(t, now.Add(-time.Minute), source.since)
+
+	// 超出统计窗口的转账被清理
+	source.transfers = nil
+	_, err = tracker.Poll(context.Background(), now.Add(23*time.Hour+30*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, tracker.Metrics("PEPE")["whale_transfers"])
+}
@@ -0,0 +1,99 @@
+package whale
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/songzhibin97/quantaflux/internal/data"
+	"github.com/songzhibin97/quantaflux/internal/utils/request"
+)
+
+// WhaleAlertSource 通过 Whale Alert API 获取大额链上转账，交易所归属由 Whale Alert 标注
+type WhaleAlertSource struct {
+	baseURL    string
+	apiKey     string
+	httpClient *resty.Client
+}
+
+func NewWhaleAlertSource(apiKey string) *WhaleAlertSource {
+	return &WhaleAlertSource{
+		baseURL:    "https://api.whale-alert.io",
+		apiKey:     apiKey,
+		httpClient: request.Request,
+	}
+}
+
+// Transfers implements TransferSource
+func (w *WhaleAlertSource) Transfers(ctx context.Context, since time.Time, minValueUSD float64) ([]Transfer, error) {
+	params := map[string]string{
+		"api_key":   w.apiKey,
+		"start":     strconv.FormatInt(since.Unix(), 10),
+		"min_value": strconv.FormatFloat(minValueUSD, 'f', 0, 64),
+	}
+
+	resp, err := w.httpClient.R().SetContext(ctx).SetQueryParams(params).Get(w.baseURL + "/v1/transactions")
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to execute request: %w", data.ErrSourceUnavailable, err)
+	}
+
+	var result struct {
+		Result       string `json:"result"`
+		Message      string `json:"message"`
+		Transactions []struct {
+			Blockchain string  `json:"blockchain"`
+			Symbol     string  `json:"symbol"`
+			Hash       string  `json:"hash"`
+			From       owner   `json:"from"`
+			To         owner   `json:"to"`
+			Timestamp  int64   `json:"timestamp"`
+			Amount     float64 `json:"amount"`
+			AmountUSD  float64 `json:"amount_usd"`
+		} `json:"transactions"`
+	}
+	if err := json.Unmarshal(resp.Body(), &result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if resp.StatusCode() != http.StatusOK || result.Result != "success" {
+		return nil, fmt.Errorf("%w: unexpected status code %d: %s", data.ErrSourceUnavailable, resp.StatusCode(), result.Message)
+	}
+
+	transfers := make([]Transfer, 0, len(result.Transactions))
+	for _, tx := range result.Transactions {
+		transfers = append(transfers, Transfer{
+			Blockchain:   tx.Blockchain,
+			Asset:        strings.ToUpper(tx.Symbol),
+			Hash:         tx.Hash,
+			From:         tx.From.Address,
+			FromExchange: tx.From.exchange(),
+			To:           tx.To.Address,
+			ToExchange:   tx.To.exchange(),
+			Amount:       tx.Amount,
+			AmountUSD:    tx.AmountUSD,
+			Timestamp:    time.Unix(tx.Timestamp, 0),
+		})
+	}
+	return transfers, nil
+}
+
+type owner struct {
+	Address   string `json:"address"`
+	Owner     string `json:"owner"`
+	OwnerType string `json:"owner_type"`
+}
+
+// exchange 地址属于交易所时返回交易所名称，否则返回空
+func (o owner) exchange() string {
+	if o.OwnerType != "exchange" {
+		return ""
+	}
+	if o.Owner == "" {
+		return "unknown"
+	}
+	return o.Owner
+}